@@ -0,0 +1,110 @@
+// Command govctl is the governance toolbox for policy authors.
+//
+// Usage:
+//
+//	govctl eval -expr 'telemetry.pipeline_latency_s9 > 0.25' -fixture snapshot.json
+//	govctl eval -expr 'context.Hardware.TEE.Technology == "SGX"' -var context -fixture system_context.json -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"src/cel_host"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches a subcommand and returns the process exit code: 0 on success, 1 if the command
+// failed, 2 on a usage error.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	switch args[0] {
+	case "eval":
+		return evalCmd(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	}
+	fmt.Fprintf(stderr, "govctl: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, `Usage: govctl <command> [flags]
+
+Commands:
+  eval    evaluate a CEL expression against a JSON fixture and print its result, cost, and trace
+
+Run "govctl <command> -h" for the command's flags.`)
+}
+
+// evalCmd runs an ExpressionTest.
+func evalCmd(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	expr := fs.String("expr", "", "CEL expression to evaluate")
+	fixture := fs.String("fixture", "", "JSON fixture file, e.g. a SystemContext or TelemetryData document (- for stdin)")
+	variable := fs.String("var", cel_host.TelemetryVariable, "name the fixture is bound to in the expression")
+	costLimit := fs.Uint64("cost-limit", cel_host.DefaultRuleCostLimit, "evaluation cost limit")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *expr == "" || *fixture == "" {
+		fmt.Fprintln(stderr, "govctl eval: -expr and -fixture are required")
+		fs.Usage()
+		return 2
+	}
+
+	var data []byte
+	var err error
+	if *fixture == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*fixture)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "govctl eval: %v\n", err)
+		return 1
+	}
+
+	test := cel_host.ExpressionTest{Expression: *expr, Fixture: data, Variable: *variable, CostLimit: *costLimit}
+	res, err := test.Run(context.Background())
+	if err != nil && res.Type == "" {
+		// Nothing was evaluated: the expression or fixture is invalid.
+		fmt.Fprintf(stderr, "govctl eval: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(res)
+	} else {
+		if res.Error != "" {
+			fmt.Fprintf(stdout, "error:  %s\n", res.Error)
+		} else {
+			fmt.Fprintf(stdout, "result: %s (%s)\n", res.Value, res.Type)
+		}
+		fmt.Fprintf(stdout, "cost:   %d\n", res.Cost)
+		fmt.Fprintln(stdout, "trace:")
+		for _, step := range res.Trace {
+			fmt.Fprintf(stdout, "  %4d  %-34s = %s\n", step.Offset, step.Snippet, step.Value)
+		}
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
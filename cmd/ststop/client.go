@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/api"
+	"services/telemetry"
+)
+
+// client reads the STS REST API and posts acknowledgements to the admin API.
+type client struct {
+	http     *http.Client
+	apiURL   string
+	adminURL string // Empty disables acknowledgement
+	token    string
+}
+
+// snapshot is everything one dashboard frame shows.
+type snapshot struct {
+	health     api.HealthResponse
+	history    []telemetry.TelemetryData
+	governance *api.GovernanceStatus // Nil if the daemon has no governance module
+	incident   *telemetry.Incident   // Nil if history holds no incident
+	errs       []error
+	at         time.Time
+}
+
+// fetch reads every endpoint the dashboard shows. Optional endpoints answering 404 or 503 are left
+// empty; other failures are collected in errs, so one broken endpoint does not blank the screen.
+func (c *client) fetch(ctx context.Context, history int) snapshot {
+	snap := snapshot{at: time.Now()}
+	if err := c.get(ctx, "/healthz", &snap.health); err != nil {
+		snap.errs = append(snap.errs, err)
+	}
+	if err := c.get(ctx, "/api/v1/telemetry?n="+strconv.Itoa(history), &snap.history); err != nil && !optional(err) {
+		snap.errs = append(snap.errs, err)
+	}
+	var gov api.GovernanceStatus
+	switch err := c.get(ctx, "/api/v1/governance/status", &gov); {
+	case err == nil:
+		snap.governance = &gov
+	case !optional(err):
+		snap.errs = append(snap.errs, err)
+	}
+	var incident telemetry.Incident
+	switch err := c.get(ctx, "/api/v1/incident", &incident); {
+	case err == nil:
+		snap.incident = &incident
+	case !optional(err):
+		snap.errs = append(snap.errs, err)
+	}
+	return snap
+}
+
+// statusError is a non-2xx API response.
+type statusError struct {
+	path    string
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.path, e.status, e.message)
+}
+
+// optional reports whether err means the endpoint's component is absent or has nothing to show.
+func optional(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && (se.status == http.StatusNotFound || se.status == http.StatusServiceUnavailable)
+}
+
+func (c *client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, path, v)
+}
+
+// acknowledge pauses escalation of the current violation through the admin API.
+func (c *client) acknowledge(ctx context.Context, reason string, ttl time.Duration) (telemetry.Acknowledgement, error) {
+	var ack telemetry.Acknowledgement
+	if c.adminURL == "" {
+		return ack, errors.New("acknowledgement needs -admin")
+	}
+	body, err := json.Marshal(map[string]string{"reason": reason, "ttl": ttl.String()})
+	if err != nil {
+		return ack, err
+	}
+	const path = "/admin/v1/acknowledge"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.adminURL+path, bytes.NewReader(body))
+	if err != nil {
+		return ack, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	err = c.do(req, path, &ack)
+	return ack, err
+}
+
+func (c *client) do(req *http.Request, path string, v interface{}) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e api.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return &statusError{path: strings.SplitN(path, "?", 2)[0], status: resp.StatusCode, message: e.Error}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
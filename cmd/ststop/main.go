@@ -0,0 +1,217 @@
+// Command ststop is a terminal dashboard for operators of an STS daemon, for SSH-only environments
+// without Grafana. It polls the daemon's REST API and shows live telemetry, the breach timeline,
+// the governance state version and recent escalations, and acknowledges a GATM violation through
+// the admin API at a key press.
+//
+// Usage:
+//
+//	ststop -api http://127.0.0.1:8080 -admin https://127.0.0.1:8443 -token "$STS_ADMIN_TOKEN"
+//	ststop -api http://127.0.0.1:8080 -once
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run starts the dashboard and returns the process exit code: 0 on quit, 1 if the dashboard could
+// not start (or, with -once, the daemon could not be read), 2 on a usage error.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ststop", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	apiURL := fs.String("api", "http://127.0.0.1:8080", "STS REST API base URL")
+	adminURL := fs.String("admin", "", "admin API base URL; acknowledgement is disabled without it")
+	// The token is not a flag default, which -h would print.
+	token := fs.String("token", "", "admin API bearer token (default $STS_ADMIN_TOKEN)")
+	interval := fs.Duration("interval", 2*time.Second, "refresh interval")
+	history := fs.Int("history", 200, "telemetry records fetched for the sparkline and timeline")
+	ackTTL := fs.Duration("ack-ttl", 30*time.Minute, "how long an acknowledgement pauses escalation")
+	once := fs.Bool("once", false, "print one frame and exit, e.g. for scripts")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *token == "" {
+		*token = os.Getenv("STS_ADMIN_TOKEN")
+	}
+	if *interval <= 0 || *history <= 0 || *ackTTL <= 0 {
+		fmt.Fprintln(stderr, "ststop: -interval, -history and -ack-ttl must be positive")
+		return 2
+	}
+
+	c := &client{
+		http:     &http.Client{Timeout: 5 * time.Second},
+		apiURL:   strings.TrimSuffix(*apiURL, "/"),
+		adminURL: strings.TrimSuffix(*adminURL, "/"),
+		token:    *token,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *once {
+		v := view{snap: c.fetch(ctx, *history), width: terminalWidth(), api: c.apiURL}
+		frame := strings.TrimPrefix(v.render(), clearScreen)
+		fmt.Fprint(stdout, strings.ReplaceAll(frame, "\x1b[K\r\n", "\n"))
+		if len(v.snap.errs) > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		fmt.Fprintf(stderr, "ststop: stdin is not a terminal (use -once): %v\n", err)
+		return 1
+	}
+	defer restore()
+	fmt.Fprint(stdout, "\x1b[?1049h\x1b[?25l") // Alternate screen, hidden cursor
+	defer fmt.Fprint(stdout, "\x1b[?25h\x1b[?1049l")
+
+	d := &dashboard{client: c, ackTTL: *ackTTL, view: view{api: c.apiURL}}
+	return d.loop(ctx, stdin, stdout, *interval, *history)
+}
+
+// dashboard is the interactive event loop.
+type dashboard struct {
+	client *client
+	ackTTL time.Duration
+	view   view
+}
+
+func (d *dashboard) loop(ctx context.Context, stdin io.Reader, stdout io.Writer, interval time.Duration, history int) int {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	snaps := make(chan snapshot)
+	refresh := make(chan struct{}, 1)
+	go func() {
+		for {
+			snap := d.client.fetch(ctx, history)
+			select {
+			case snaps <- snap:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-time.After(interval):
+			case <-refresh:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := stdin.Read(buf)
+			for _, k := range buf[:n] {
+				select {
+				case keys <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	acks := make(chan string)
+
+	draw := func() { fmt.Fprint(stdout, d.view.render()) }
+	requestRefresh := func() {
+		select {
+		case refresh <- struct{}{}:
+		default:
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return 0
+		case snap := <-snaps:
+			d.view.snap = snap
+			d.view.width = terminalWidth()
+			draw()
+		case message := <-acks:
+			d.view.message = message
+			draw()
+			requestRefresh()
+		case k, ok := <-keys:
+			if !ok {
+				return 0
+			}
+			if quit := d.key(ctx, k, acks, requestRefresh); quit {
+				return 0
+			}
+			draw()
+		}
+	}
+}
+
+// key handles one byte of keyboard input and reports whether to quit.
+func (d *dashboard) key(ctx context.Context, k byte, acks chan<- string, refresh func()) bool {
+	v := &d.view
+	const ctrlC, enter, newline, esc, backspace, del = 3, '\r', '\n', 27, 8, 127
+	if k == ctrlC {
+		return true
+	}
+	if !v.prompting {
+		switch k {
+		case 'q':
+			return true
+		case 'r':
+			refresh()
+		case 'a':
+			if d.client.adminURL == "" {
+				v.message = yellow + "acknowledgement needs -admin" + reset
+				break
+			}
+			v.prompting, v.input, v.message = true, "", ""
+		}
+		return false
+	}
+
+	switch k {
+	case enter, newline:
+		reason := strings.TrimSpace(v.input)
+		if reason == "" {
+			v.message = yellow + "a reason is required" + reset
+			return false
+		}
+		v.prompting, v.input, v.message = false, "", "acknowledging…"
+		go func() {
+			ack, err := d.client.acknowledge(ctx, reason, d.ackTTL)
+			message := green + "acknowledged by " + ack.By + " until " + ack.Until.Local().Format("15:04:05") + reset
+			if err != nil {
+				message = red + "acknowledge failed: " + err.Error() + reset
+			}
+			select {
+			case acks <- message:
+			case <-ctx.Done():
+			}
+		}()
+	case esc:
+		v.prompting, v.input = false, ""
+	case backspace, del:
+		_, size := utf8.DecodeLastRuneInString(v.input)
+		v.input = v.input[:len(v.input)-size]
+	default:
+		if k >= ' ' {
+			v.input += string(k)
+		}
+	}
+	return false
+}
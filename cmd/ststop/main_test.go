@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"internal/api"
+	"services/telemetry"
+)
+
+func TestRun_HelpHidesToken(t *testing.T) {
+	t.Setenv("STS_ADMIN_TOKEN", "s3cret")
+	var stderr bytes.Buffer
+	if code := run([]string{"-h"}, strings.NewReader(""), &bytes.Buffer{}, &stderr); code != 2 {
+		t.Errorf("run(-h) = %d, want 2", code)
+	}
+	if strings.Contains(stderr.String(), "s3cret") {
+		t.Errorf("usage prints the admin token:\n%s", stderr.String())
+	}
+}
+
+// newDaemon serves the REST endpoints ststop reads, with no governance module or incident.
+func newDaemon(t *testing.T, health api.HealthResponse) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(health)
+	})
+	mux.HandleFunc("/api/v1/telemetry", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]telemetry.TelemetryData{health.Telemetry})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRun_Once(t *testing.T) {
+	srv := newDaemon(t, api.HealthResponse{
+		Telemetry: telemetry.TelemetryData{Timestamp: time.Now(), Severity: telemetry.SeverityCritical, GATMBreachCount: 4, IntegrityHashChainStatus: "SYNCED"},
+		State:     telemetry.StateHealthy,
+	})
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-api", srv.URL, "-once"}, strings.NewReader(""), &stdout, &stderr); code != 0 {
+		t.Fatalf("run(-once) = %d, stderr %s\n%s", code, stderr.String(), stdout.String())
+	}
+	frame := stdout.String()
+	if strings.Contains(frame, clearScreen) || strings.Contains(frame, "\r\n") {
+		t.Error("-once frame contains terminal control sequences")
+	}
+	for _, want := range []string{"CRITICAL", "Breaches\x1b[0m 4", "not configured", "none in history"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("-once frame lacks %q:\n%s", want, frame)
+		}
+	}
+
+	// An unreachable daemon fails the script.
+	srv.Close()
+	if code := run([]string{"-api", srv.URL, "-once"}, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{}); code != 1 {
+		t.Errorf("run(-once) against a stopped daemon = %d, want 1", code)
+	}
+}
+
+func TestRender(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	v := view{
+		api:   "http://sts",
+		width: 80,
+		snap: snapshot{
+			at: at,
+			health: api.HealthResponse{Telemetry: telemetry.TelemetryData{
+				Severity: telemetry.SeverityDegraded, IsAcknowledged: true, ViolationCauses: []string{"latency", "load"},
+			}},
+			history: []telemetry.TelemetryData{
+				{Severity: telemetry.SeverityOK},
+				{Severity: telemetry.SeverityWarn},
+				{Severity: telemetry.SeverityCritical, IsGATMViolating: true, PipelineLatencyS9: time.Second},
+				{Severity: telemetry.SeverityCritical, IsSilenced: true},
+			},
+			governance: &api.GovernanceStatus{Generation: 7, LastUpdated: at.Add(-time.Minute), Error: "timeout"},
+		},
+		prompting: true,
+		input:     "paging db team",
+	}
+	frame := v.render()
+	for _, want := range []string{"acknowledged  causes: latency, load", "generation 7, updated 1m0s ago", "stale\x1b[0m (timeout)", "Acknowledge reason:\x1b[0m paging db team"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame lacks %q:\n%s", want, frame)
+		}
+	}
+	if got := timeline(v.snap.history); stripANSI(got) != "·wCs" {
+		t.Errorf("timeline = %q, want ·wCs", stripANSI(got))
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(frame, "\r\n"), "\r\n") {
+		if !strings.HasSuffix(line, "\x1b[K") {
+			t.Fatalf("line %q does not clear to the end, as raw mode needs", line)
+		}
+	}
+}
+
+// stripANSI removes the SGR sequences render emits.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			i += strings.IndexByte(s[i:], 'm')
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func TestDashboardKeys(t *testing.T) {
+	var got struct {
+		auth string
+		body map[string]string
+	}
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got.body)
+		json.NewEncoder(w).Encode(telemetry.Acknowledgement{By: "olive", Until: time.Now().Add(time.Hour)})
+	}))
+	defer admin.Close()
+
+	d := &dashboard{client: &client{http: admin.Client(), adminURL: admin.URL, token: "tok"}, ackTTL: 10 * time.Minute}
+	acks := make(chan string, 1)
+	refreshed := 0
+	press := func(keys string) bool {
+		for i := 0; i < len(keys); i++ {
+			if d.key(context.Background(), keys[i], acks, func() { refreshed++ }) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if press("r"); refreshed != 1 {
+		t.Errorf("r refreshed %d times", refreshed)
+	}
+	// Esc cancels the prompt; keys typed into it are not commands.
+	if press("aq\x1b"); d.view.prompting || d.view.input != "" {
+		t.Errorf("after Esc: prompting %v, input %q", d.view.prompting, d.view.input)
+	}
+	press("a\r")
+	if !d.view.prompting || !strings.Contains(d.view.message, "reason is required") {
+		t.Errorf("empty reason: prompting %v, message %q", d.view.prompting, d.view.message)
+	}
+	press("db slow!\x7f\r")
+	if d.view.prompting {
+		t.Error("still prompting after Enter")
+	}
+	if message := <-acks; !strings.Contains(message, "acknowledged by olive") {
+		t.Errorf("ack message = %q", message)
+	}
+	if got.auth != "Bearer tok" || got.body["reason"] != "db slow" || got.body["ttl"] != "10m0s" {
+		t.Errorf("acknowledge request = %q %v", got.auth, got.body)
+	}
+	if !press("q") || !press("\x03") {
+		t.Error("q and Ctrl-C do not quit")
+	}
+
+	d.client.adminURL = ""
+	if press("a"); d.view.prompting || !strings.Contains(d.view.message, "needs -admin") {
+		t.Errorf("without -admin: prompting %v, message %q", d.view.prompting, d.view.message)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"services/telemetry"
+)
+
+// ANSI escape sequences.
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reset       = "\x1b[0m"
+	bold        = "\x1b[1m"
+	dim         = "\x1b[2m"
+	red         = "\x1b[31m"
+	green       = "\x1b[32m"
+	yellow      = "\x1b[33m"
+	magenta     = "\x1b[35m"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// severityMarks are the timeline marks of non-OK severities.
+var severityMarks = map[telemetry.Severity]string{
+	telemetry.SeverityWarn:     "w",
+	telemetry.SeverityDegraded: "d",
+	telemetry.SeverityCritical: "C",
+}
+
+// view is the dashboard state rendered into one frame.
+type view struct {
+	snap      snapshot
+	width     int
+	api       string
+	prompting bool   // Reading an acknowledgement reason
+	input     string // Reason typed so far
+	message   string // Outcome of the last action
+}
+
+// render draws the frame. Lines end in CRLF, since the terminal is in raw mode.
+func (v view) render() string {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString("\x1b[K\r\n")
+	}
+	b.WriteString(clearScreen)
+	h := v.snap.health
+	td := h.Telemetry
+
+	line("%sststop%s  %s  %supdated %s%s", bold, reset, v.api, dim, v.snap.at.Format("15:04:05"), reset)
+	line("")
+	paused := ""
+	if h.Paused {
+		paused = yellow + "  PAUSED" + reset
+	}
+	line("%sState%s     %s   %sSeverity%s %s   %sBreaches%s %d   %sBackpressure%s %.0f%%%s",
+		bold, reset, stateColor(h.State)+string(h.State)+reset,
+		bold, reset, severityColor(td.Severity)+string(td.Severity)+reset,
+		bold, reset, td.GATMBreachCount, bold, reset, h.Backpressure*100, paused)
+	flags := []string{}
+	if td.IsSilenced {
+		flags = append(flags, "silenced")
+	}
+	if td.IsAcknowledged {
+		flags = append(flags, "acknowledged")
+	}
+	if len(td.ViolationCauses) > 0 {
+		flags = append(flags, "causes: "+strings.Join(td.ViolationCauses, ", "))
+	}
+	line("%sLatency%s   %-10s %sLoad%s %5.1f%%   %sHash chain%s %s   %s",
+		bold, reset, formatSeconds(td.PipelineLatencyS9.Seconds()), bold, reset, td.ResourceLoad_Pct*100,
+		bold, reset, td.IntegrityHashChainStatus, strings.Join(flags, "  "))
+	if hist := td.LatencyHistogram; hist != nil && hist.Count() > 0 {
+		line("%sLatency%s   p50 %s  p99 %s", bold, reset, formatSeconds(hist.Quantile(0.5)), formatSeconds(hist.Quantile(0.99)))
+	}
+	line("")
+
+	width := max(v.width-12, 10)
+	history := v.snap.history
+	if len(history) > width {
+		history = history[len(history)-width:]
+	}
+	line("%sLatency%s   %s", bold, reset, sparkline(history))
+	line("%sTimeline%s  %s", bold, reset, timeline(history))
+	if len(history) > 0 {
+		line("%s          %s … %s, one mark per snapshot: · ok  w warn  d degraded  C critical  s silenced  a acknowledged%s",
+			dim, history[0].Timestamp.Local().Format("15:04:05"), history[len(history)-1].Timestamp.Local().Format("15:04:05"), reset)
+	}
+	line("")
+
+	if g := v.snap.governance; g != nil {
+		fresh := green + "fresh" + reset
+		if !g.Fresh {
+			fresh = red + "stale" + reset
+			if g.Error != "" {
+				fresh += " (" + g.Error + ")"
+			}
+		}
+		line("%sGovernance%s generation %d, updated %s, %s; %d sampling rates, %d masking rules",
+			bold, reset, g.Generation, ago(g.LastUpdated, v.snap.at), fresh, len(g.SamplingRates), len(g.MaskingRules))
+	} else {
+		line("%sGovernance%s %snot configured%s", bold, reset, dim, reset)
+	}
+	line("")
+
+	line("%sRecent escalations%s", bold, reset)
+	if inc := v.snap.incident; inc != nil {
+		status := "ended " + ago(inc.EndedAt, v.snap.at)
+		if inc.Ongoing {
+			status = red + "ongoing" + reset
+		}
+		line("  incident since %s, %s, peak %s with %d breaches, first causes: %s",
+			inc.StartedAt.Local().Format("15:04:05"), status, inc.PeakSeverity, inc.PeakBreaches, strings.Join(inc.FirstCauses, ", "))
+		transitions := inc.Transitions
+		if len(transitions) > 6 {
+			transitions = transitions[len(transitions)-6:]
+		}
+		for _, t := range transitions {
+			line("  %s  %-12s %s  breaches %d  %s", t.At.Local().Format("15:04:05"), t.Event,
+				severityColor(t.Severity)+fmt.Sprintf("%-8s", t.Severity)+reset, t.BreachCount, strings.Join(t.Causes, ", "))
+		}
+		if n := len(inc.Escalations); n > 0 {
+			line("  %d escalation(s), last %s", n, ago(inc.Escalations[n-1], v.snap.at))
+		}
+	} else {
+		line("  %snone in history%s", dim, reset)
+	}
+	line("")
+
+	for _, err := range v.snap.errs {
+		line("%serror:%s %v", red, reset, err)
+	}
+	if v.message != "" {
+		line("%s", v.message)
+	}
+	if v.prompting {
+		line("%sAcknowledge reason:%s %s▏  %s(Enter to send, Esc to cancel)%s", bold, reset, v.input, dim, reset)
+	} else {
+		line("%s[a] acknowledge  [r] refresh  [q] quit%s", dim, reset)
+	}
+	return b.String()
+}
+
+func sparkline(history []telemetry.TelemetryData) string {
+	var peak float64
+	for _, td := range history {
+		peak = max(peak, td.PipelineLatencyS9.Seconds())
+	}
+	var b strings.Builder
+	for _, td := range history {
+		i := 0
+		if peak > 0 {
+			i = int(td.PipelineLatencyS9.Seconds() / peak * float64(len(sparks)-1))
+		}
+		color := ""
+		if td.IsGATMViolating {
+			color = red
+		}
+		b.WriteString(color + string(sparks[i]) + reset)
+	}
+	if peak > 0 {
+		b.WriteString(dim + " max " + formatSeconds(peak) + reset)
+	}
+	return b.String()
+}
+
+func timeline(history []telemetry.TelemetryData) string {
+	var b strings.Builder
+	for _, td := range history {
+		mark := severityMarks[td.Severity]
+		switch {
+		case td.IsSilenced:
+			mark = "s"
+		case td.IsAcknowledged:
+			mark = "a"
+		case mark == "":
+			mark = "·"
+		}
+		b.WriteString(severityColor(td.Severity) + mark + reset)
+	}
+	return b.String()
+}
+
+func severityColor(s telemetry.Severity) string {
+	switch s {
+	case telemetry.SeverityWarn:
+		return yellow
+	case telemetry.SeverityDegraded:
+		return magenta
+	case telemetry.SeverityCritical:
+		return red
+	}
+	return green
+}
+
+func stateColor(s telemetry.LifecycleState) string {
+	switch s {
+	case telemetry.StateHealthy:
+		return green
+	case telemetry.StateWarn, telemetry.StateRecovering:
+		return yellow
+	}
+	return red
+}
+
+func formatSeconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond).String()
+}
+
+func ago(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return now.Sub(t).Round(time.Second).String() + " ago"
+}
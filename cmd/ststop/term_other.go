@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+// makeRaw leaves the terminal as it is: keys take effect after Enter.
+func makeRaw() (restore func(), err error) {
+	return func() {}, nil
+}
+
+// terminalWidth returns 80 columns.
+func terminalWidth() int {
+	return 80
+}
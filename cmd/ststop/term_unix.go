@@ -0,0 +1,41 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// makeRaw switches the terminal on stdin to raw mode with stty, so single key presses are read
+// without echo, and returns a function restoring the previous mode.
+func makeRaw() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+// terminalWidth returns the number of columns of the terminal on stdin, or 80 if unknown.
+func terminalWidth() int {
+	out, err := stty("size")
+	if fields := strings.Fields(out); err == nil && len(fields) == 2 {
+		if cols, err := strconv.Atoi(fields[1]); err == nil && cols > 0 {
+			return cols
+		}
+	}
+	return 80
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,230 @@
+package governance
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"pkg/correlation"
+)
+
+const (
+	defaultTokenTTL = 5 * time.Minute
+	minHMACKeySize  = 32
+
+	algEdDSA = "EdDSA"
+	algHS256 = "HS256"
+)
+
+// AdmissionClaims is the payload of an admission token: a JWT attesting that PolicyID admitted the
+// system context whose hash is ContextHash, until ExpiresAt.
+type AdmissionClaims struct {
+	ID            string `json:"jti"`
+	Issuer        string `json:"iss,omitempty"`
+	IssuedAt      int64  `json:"iat"`
+	ExpiresAt     int64  `json:"exp"`
+	PolicyID      string `json:"policy_id"`
+	ContextHash   string `json:"context_hash"` // See ContextHash
+	BundleVersion string `json:"bundle_version,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// ContextHash returns the hex SHA-256 of the canonical JSON encoding of sc, which admission tokens
+// bind decisions to.
+func ContextHash(sc SystemContext) (string, error) {
+	encoded, err := json.Marshal(sc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode system context: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// TokenIssuer mints admission tokens, so a workload can present its admission to downstream
+// components later without them re-evaluating the policy. Tokens are JWTs signed with EdDSA
+// (Ed25519) or HS256.
+type TokenIssuer struct {
+	Issuer string
+	TTL    time.Duration // Token lifetime (default 5m)
+
+	alg  string
+	sign func(signingInput []byte) []byte
+	now  func() time.Time
+}
+
+// NewTokenIssuer creates an issuer signing with key: an ed25519.PrivateKey for EdDSA, which
+// downstream components verify with the public key, or a shared secret ([]byte, at least 32
+// bytes) for HS256.
+func NewTokenIssuer(key interface{}, issuer string, ttl time.Duration) (*TokenIssuer, error) {
+	if ttl <= 0 {
+		ttl = defaultTokenTTL
+	}
+	t := &TokenIssuer{Issuer: issuer, TTL: ttl, now: time.Now}
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		if len(k) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("admission token: invalid Ed25519 private key size %d", len(k))
+		}
+		t.alg = algEdDSA
+		t.sign = func(input []byte) []byte { return ed25519.Sign(k, input) }
+	case []byte:
+		if len(k) < minHMACKeySize {
+			return nil, fmt.Errorf("admission token: HMAC key must be at least %d bytes", minHMACKeySize)
+		}
+		t.alg = algHS256
+		t.sign = func(input []byte) []byte { return hmacSHA256(k, input) }
+	default:
+		return nil, fmt.Errorf("admission token: unsupported signing key type %T", key)
+	}
+	return t, nil
+}
+
+func hmacSHA256(key, input []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(input)
+	return mac.Sum(nil)
+}
+
+// Issue mints a token for an admitted decision. The correlation ID from ctx is included, so
+// the token can be traced to the request it was issued for.
+func (t *TokenIssuer) Issue(ctx context.Context, policyID, contextHash, bundleVersion string) (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("admission token: %w", err)
+	}
+	now := t.now()
+	claims := AdmissionClaims{
+		ID:            hex.EncodeToString(id[:]),
+		Issuer:        t.Issuer,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(t.TTL).Unix(),
+		PolicyID:      policyID,
+		ContextHash:   contextHash,
+		BundleVersion: bundleVersion,
+		CorrelationID: correlation.FromContext(ctx),
+	}
+	header, _ := json.Marshal(map[string]string{"alg": t.alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("admission token: %w", err)
+	}
+	input := b64(header) + "." + b64(payload)
+	return input + "." + b64(t.sign([]byte(input))), nil
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TokenVerifier checks admission tokens minted by a TokenIssuer.
+type TokenVerifier struct {
+	// Issuer, if set, must match the token's issuer.
+	Issuer string
+	// Leeway tolerates clock skew between issuer and verifier when checking expiry.
+	Leeway time.Duration
+
+	alg    string
+	verify func(signingInput, signature []byte) bool
+	now    func() time.Time
+}
+
+// NewTokenVerifier creates a verifier for key: the issuer's ed25519.PublicKey, or the HS256 shared
+// secret.
+func NewTokenVerifier(key interface{}, issuer string) (*TokenVerifier, error) {
+	v := &TokenVerifier{Issuer: issuer, now: time.Now}
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("admission token: invalid Ed25519 public key size %d", len(k))
+		}
+		v.alg = algEdDSA
+		v.verify = func(input, sig []byte) bool { return ed25519.Verify(k, input, sig) }
+	case []byte:
+		if len(k) < minHMACKeySize {
+			return nil, fmt.Errorf("admission token: HMAC key must be at least %d bytes", minHMACKeySize)
+		}
+		v.alg = algHS256
+		v.verify = func(input, sig []byte) bool { return hmac.Equal(hmacSHA256(k, input), sig) }
+	default:
+		return nil, fmt.Errorf("admission token: unsupported verification key type %T", key)
+	}
+	return v, nil
+}
+
+// Verify checks the token's signature, algorithm, issuer and expiry, and returns its claims.
+func (v *TokenVerifier) Verify(token string) (AdmissionClaims, error) {
+	var claims AdmissionClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, fmt.Errorf("%w: malformed token", ErrInvalidAdmissionToken)
+	}
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return claims, fmt.Errorf("%w: malformed header", ErrInvalidAdmissionToken)
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	// The algorithm is fixed by the key, never taken from the token.
+	if json.Unmarshal(header, &h) != nil || h.Alg != v.alg {
+		return claims, fmt.Errorf("%w: unexpected algorithm", ErrInvalidAdmissionToken)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !v.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return claims, fmt.Errorf("%w: bad signature", ErrInvalidAdmissionToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		return claims, fmt.Errorf("%w: malformed claims", ErrInvalidAdmissionToken)
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return claims, fmt.Errorf("%w: issuer '%s' not trusted", ErrInvalidAdmissionToken, claims.Issuer)
+	}
+	if !v.now().Before(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return claims, fmt.Errorf("%w: expired at %s", ErrAdmissionTokenExpired, time.Unix(claims.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	return claims, nil
+}
+
+// VerifyFor is Verify for a specific policy and system context: the token must have been issued
+// for policyID and for a context identical to sc.
+func (v *TokenVerifier) VerifyFor(token, policyID string, sc SystemContext) (AdmissionClaims, error) {
+	claims, err := v.Verify(token)
+	if err != nil {
+		return claims, err
+	}
+	hash, err := ContextHash(sc)
+	if err != nil {
+		return claims, err
+	}
+	if claims.PolicyID != policyID {
+		return claims, fmt.Errorf("%w: token was issued for policy '%s'", ErrAdmissionTokenMismatch, claims.PolicyID)
+	}
+	if claims.ContextHash != hash {
+		return claims, fmt.Errorf("%w: token was issued for another system context", ErrAdmissionTokenMismatch)
+	}
+	return claims, nil
+}
+
+// EvaluateRequestToken is EvaluateRequestContext that also mints an admission token with Tokens
+// when the request is admitted. token is empty if the request is denied or Tokens is not set.
+func (pae *PolicyAdmissionEngine) EvaluateRequestToken(ctx context.Context, policyID string, sc SystemContext) (admitted bool, token string, err error) {
+	admitted, err = pae.EvaluateRequestContext(ctx, policyID, sc)
+	if !admitted || pae.Tokens == nil {
+		return admitted, "", err
+	}
+	hash, err := ContextHash(sc)
+	if err != nil {
+		return admitted, "", err
+	}
+	token, err = pae.Tokens.Issue(ctx, policyID, hash, pae.BundleVersion)
+	return admitted, token, err
+}
@@ -0,0 +1,158 @@
+package governance
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var hmacKey = bytes.Repeat([]byte{0x42}, minHMACKeySize)
+
+// newTokenPair returns an issuer and verifier for alg, both reading the clock from now.
+func newTokenPair(t *testing.T, alg string, now *time.Time) (*TokenIssuer, *TokenVerifier) {
+	t.Helper()
+	var signKey, verifyKey interface{} = hmacKey, hmacKey
+	if alg == algEdDSA {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signKey, verifyKey = priv, pub
+	}
+	issuer, err := NewTokenIssuer(signKey, "pae", time.Minute)
+	if err != nil {
+		t.Fatalf("NewTokenIssuer() error = %v", err)
+	}
+	verifier, err := NewTokenVerifier(verifyKey, "pae")
+	if err != nil {
+		t.Fatalf("NewTokenVerifier() error = %v", err)
+	}
+	issuer.now = func() time.Time { return *now }
+	verifier.now = func() time.Time { return *now }
+	return issuer, verifier
+}
+
+func TestAdmissionToken_RoundTrip(t *testing.T) {
+	for _, alg := range []string{algEdDSA, algHS256} {
+		now := time.Unix(1_800_000_000, 0)
+		issuer, verifier := newTokenPair(t, alg, &now)
+		token, err := issuer.Issue(context.Background(), "gpu-tee", "abc123", "v7")
+		if err != nil {
+			t.Fatalf("%s: Issue() error = %v", alg, err)
+		}
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			t.Fatalf("%s: Verify() error = %v", alg, err)
+		}
+		if claims.PolicyID != "gpu-tee" || claims.ContextHash != "abc123" || claims.BundleVersion != "v7" ||
+			claims.Issuer != "pae" || claims.ExpiresAt != now.Add(time.Minute).Unix() || claims.ID == "" {
+			t.Errorf("%s: claims = %+v", alg, claims)
+		}
+	}
+}
+
+func TestAdmissionToken_RejectsAlgorithmMismatch(t *testing.T) {
+	now := time.Now()
+	hsIssuer, _ := newTokenPair(t, algHS256, &now)
+	_, edVerifier := newTokenPair(t, algEdDSA, &now)
+	token, err := hsIssuer.Issue(context.Background(), "p", "h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := edVerifier.Verify(token); !errors.Is(err, ErrInvalidAdmissionToken) || !strings.Contains(err.Error(), "algorithm") {
+		t.Errorf("Verify() of an HS256 token by an EdDSA verifier error = %v", err)
+	}
+
+	// An unsigned token claiming "none" is refused too.
+	parts := strings.Split(token, ".")
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "."
+	if _, err := edVerifier.Verify(none); !errors.Is(err, ErrInvalidAdmissionToken) {
+		t.Errorf("Verify() of an alg=none token error = %v", err)
+	}
+}
+
+func TestAdmissionToken_RejectsTamperedPayload(t *testing.T) {
+	now := time.Now()
+	issuer, verifier := newTokenPair(t, algEdDSA, &now)
+	token, err := issuer.Issue(context.Background(), "basic", "h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	parts[1] = base64.RawURLEncoding.EncodeToString(bytes.Replace(payload, []byte(`"basic"`), []byte(`"admin"`), 1))
+	if _, err := verifier.Verify(strings.Join(parts, ".")); !errors.Is(err, ErrInvalidAdmissionToken) || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Verify() of a tampered token error = %v, want a bad signature", err)
+	}
+}
+
+func TestAdmissionToken_ExpiryWithLeeway(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	issuer, verifier := newTokenPair(t, algHS256, &now)
+	token, err := issuer.Issue(context.Background(), "p", "h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute) // Exactly at expiry
+	if _, err := verifier.Verify(token); !errors.Is(err, ErrAdmissionTokenExpired) {
+		t.Errorf("Verify() at expiry error = %v, want ErrAdmissionTokenExpired", err)
+	}
+	verifier.Leeway = 10 * time.Second
+	if _, err := verifier.Verify(token); err != nil {
+		t.Errorf("Verify() within leeway error = %v", err)
+	}
+	now = now.Add(10 * time.Second)
+	if _, err := verifier.Verify(token); !errors.Is(err, ErrAdmissionTokenExpired) {
+		t.Errorf("Verify() past leeway error = %v, want ErrAdmissionTokenExpired", err)
+	}
+}
+
+func TestAdmissionToken_RejectsWrongIssuer(t *testing.T) {
+	now := time.Now()
+	issuer, verifier := newTokenPair(t, algHS256, &now)
+	issuer.Issuer = "rogue"
+	token, err := issuer.Issue(context.Background(), "p", "h", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(token); !errors.Is(err, ErrInvalidAdmissionToken) || !strings.Contains(err.Error(), "rogue") {
+		t.Errorf("Verify() of a token from another issuer error = %v", err)
+	}
+	verifier.Issuer = ""
+	if _, err := verifier.Verify(token); err != nil {
+		t.Errorf("Verify() without an issuer check error = %v", err)
+	}
+}
+
+func TestAdmissionToken_VerifyFor(t *testing.T) {
+	now := time.Now()
+	issuer, verifier := newTokenPair(t, algEdDSA, &now)
+	systemContext := func(tee bool) SystemContext {
+		return NewSystemContext().WithCPUArchitecture("amd64").WithMemoryKB(1 << 20).WithKernelVersion("6.8.0").WithTEE(tee).MustBuild()
+	}
+	sc := systemContext(true)
+	hash, err := ContextHash(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := issuer.Issue(context.Background(), "confidential", hash, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifier.VerifyFor(token, "confidential", sc); err != nil {
+		t.Errorf("VerifyFor() error = %v", err)
+	}
+	if _, err := verifier.VerifyFor(token, "other", sc); !errors.Is(err, ErrAdmissionTokenMismatch) {
+		t.Errorf("VerifyFor() with another policy error = %v, want ErrAdmissionTokenMismatch", err)
+	}
+	if _, err := verifier.VerifyFor(token, "confidential", systemContext(false)); !errors.Is(err, ErrAdmissionTokenMismatch) {
+		t.Errorf("VerifyFor() with another context error = %v, want ErrAdmissionTokenMismatch", err)
+	}
+}
@@ -0,0 +1,148 @@
+package governance
+
+import (
+	"fmt"
+	"strings"
+
+	"pkg/recovery"
+)
+
+// Group combinators, as reported in ErrConstraintUnsatisfied.Key for an unsatisfied group.
+const (
+	combinatorAllOf    = "all_of"
+	combinatorAnyOf    = "any_of"
+	combinatorNotAnyOf = "not_any_of"
+)
+
+// combinator returns the group kind and members of c, or "" for a keyed constraint. It fails if c
+// is neither or more than one, or sets a threshold outside any_of.
+func (c PolicyConstraint) combinator() (string, []PolicyConstraint, error) {
+	kind, members, set := "", []PolicyConstraint(nil), 0
+	for _, g := range []struct {
+		kind    string
+		members []PolicyConstraint
+	}{{combinatorAllOf, c.AllOf}, {combinatorAnyOf, c.AnyOf}, {combinatorNotAnyOf, c.NotAnyOf}} {
+		if len(g.members) > 0 {
+			kind, members, set = g.kind, g.members, set+1
+		}
+	}
+	switch {
+	case set > 1 || (set == 1 && c.Key != ""):
+		return "", nil, fmt.Errorf("constraint must have either a key or exactly one of all_of, any_of and not_any_of")
+	case set == 0 && c.Key == "":
+		return "", nil, fmt.Errorf("constraint has neither a key nor members")
+	case c.Threshold != 0 && kind != combinatorAnyOf:
+		return "", nil, fmt.Errorf("threshold is only valid on an any_of group")
+	}
+	return kind, members, nil
+}
+
+// label names c in errors: its key, or its combinator and members, e.g.
+// "any_of(Hardware.TEE_Support, all_of(Hardware.SR_IOV_Enabled, Hardware.MemoryBytes))".
+func (c PolicyConstraint) label() string {
+	kind, members, err := c.combinator()
+	if err != nil || kind == "" {
+		return c.Key
+	}
+	labels := make([]string, len(members))
+	for i, m := range members {
+		labels[i] = m.label()
+	}
+	return kind + "(" + strings.Join(labels, ", ") + ")"
+}
+
+// weight returns the constraint's weight in an any_of threshold.
+func (c PolicyConstraint) weight() float64 {
+	if c.Weight == 0 {
+		return 1
+	}
+	return c.Weight
+}
+
+// evaluateConstraint evaluates a keyed constraint with its registered evaluator, or a group by
+// combining its members. err reports a malformed constraint or a failed evaluation, never an
+// unsatisfied one.
+func (pae *PolicyAdmissionEngine) evaluateConstraint(policyID string, c PolicyConstraint, context SystemContext) (bool, error) {
+	kind, members, err := c.combinator()
+	if err != nil {
+		return false, fmt.Errorf("policy '%s' admission failed: invalid constraint '%s': %w", policyID, c.label(), err)
+	}
+	switch kind {
+	case combinatorAllOf:
+		for _, m := range members {
+			if ok, err := pae.evaluateConstraint(policyID, m, context); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case combinatorNotAnyOf:
+		for _, m := range members {
+			if ok, err := pae.evaluateConstraint(policyID, m, context); err != nil || ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case combinatorAnyOf:
+		var score float64
+		for _, m := range members {
+			if m.Weight < 0 {
+				return false, fmt.Errorf("policy '%s' admission failed: constraint '%s' has a negative weight", policyID, m.label())
+			}
+			ok, err := pae.evaluateConstraint(policyID, m, context)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				continue
+			}
+			if c.Threshold == 0 {
+				return true, nil
+			}
+			if score += m.weight(); score >= c.Threshold {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	evaluator, found := pae.ConstraintRegistry[c.Key]
+	if !found && strings.HasPrefix(c.Key, cpesConstraintPrefix) {
+		evaluator, found = evaluateCPES, true
+	}
+	if !found {
+		// If a mandatory constraint key is unrecognized, admission must fail to ensure integrity.
+		return false, fmt.Errorf("%w '%s' required by policy %s", ErrUnsupportedConstraint, c.Key, policyID)
+	}
+
+	var satisfied bool
+	err = recovery.Call("constraint evaluator "+c.Key, func() (err error) {
+		satisfied, err = evaluator(context, c)
+		return err
+	})
+	if err != nil {
+		// Evaluation failed due to malformed constraint definition or unexpected context format
+		return false, fmt.Errorf("policy '%s' admission failed during evaluation of constraint '%s': %w", policyID, c.Key, err)
+	}
+	return satisfied, nil
+}
+
+// UnsatisfiedConstraints evaluates every top-level constraint of policyID against sc, rather than
+// stopping at the first like EvaluateRequest, and returns the labels of those not met, e.g. for
+// compliance reports. Decisions are not counted in the admission metrics.
+func (pae *PolicyAdmissionEngine) UnsatisfiedConstraints(policyID string, sc SystemContext) ([]string, error) {
+	policy, ok := pae.Policies[policyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrPolicyNotFound, policyID)
+	}
+	var failing []string
+	for _, c := range policy.Constraints {
+		satisfied, err := pae.evaluateConstraint(policyID, c, sc)
+		if err != nil {
+			return failing, err
+		}
+		if !satisfied {
+			failing = append(failing, c.label())
+		}
+	}
+	return failing, nil
+}
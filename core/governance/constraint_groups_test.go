@@ -0,0 +1,132 @@
+package governance
+
+import (
+	"errors"
+	"testing"
+)
+
+// flagEngine returns an engine whose "Flag.<name>" constraints are satisfied when set[name] is true.
+func flagEngine(set map[string]bool, policies map[string]IsolationPolicy) *PolicyAdmissionEngine {
+	pae := newEngine("", policies)
+	for _, name := range []string{"A", "B", "C"} {
+		name := name
+		pae.RegisterConstraint("Flag."+name, func(SystemContext, PolicyConstraint) (bool, error) {
+			return set[name], nil
+		})
+	}
+	return pae
+}
+
+func flag(name string) PolicyConstraint {
+	return PolicyConstraint{Key: "Flag." + name}
+}
+
+func weighted(name string, w float64) PolicyConstraint {
+	c := flag(name)
+	c.Weight = w
+	return c
+}
+
+func TestEvaluateConstraint_Groups(t *testing.T) {
+	tests := []struct {
+		name string
+		c    PolicyConstraint
+		set  map[string]bool
+		want bool
+	}{
+		{"all_of satisfied", PolicyConstraint{AllOf: []PolicyConstraint{flag("A"), flag("B")}}, map[string]bool{"A": true, "B": true}, true},
+		{"all_of one missing", PolicyConstraint{AllOf: []PolicyConstraint{flag("A"), flag("B")}}, map[string]bool{"A": true}, false},
+		{"any_of one satisfied", PolicyConstraint{AnyOf: []PolicyConstraint{flag("A"), flag("B")}}, map[string]bool{"B": true}, true},
+		{"any_of none satisfied", PolicyConstraint{AnyOf: []PolicyConstraint{flag("A"), flag("B")}}, nil, false},
+		{"not_any_of none satisfied", PolicyConstraint{NotAnyOf: []PolicyConstraint{flag("A"), flag("B")}}, nil, true},
+		{"not_any_of one satisfied", PolicyConstraint{NotAnyOf: []PolicyConstraint{flag("A"), flag("B")}}, map[string]bool{"B": true}, false},
+		{
+			"nested",
+			PolicyConstraint{AnyOf: []PolicyConstraint{flag("A"), {AllOf: []PolicyConstraint{flag("B"), {NotAnyOf: []PolicyConstraint{flag("C")}}}}}},
+			map[string]bool{"B": true}, true,
+		},
+		{
+			"threshold met by weights",
+			PolicyConstraint{AnyOf: []PolicyConstraint{weighted("A", 2), weighted("B", 1), weighted("C", 1)}, Threshold: 3},
+			map[string]bool{"A": true, "C": true}, true,
+		},
+		{
+			"threshold not met",
+			PolicyConstraint{AnyOf: []PolicyConstraint{weighted("A", 2), weighted("B", 1), weighted("C", 1)}, Threshold: 3},
+			map[string]bool{"B": true, "C": true}, false,
+		},
+		{
+			"threshold with default weights",
+			PolicyConstraint{AnyOf: []PolicyConstraint{flag("A"), flag("B"), flag("C")}, Threshold: 2},
+			map[string]bool{"A": true, "C": true}, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pae := flagEngine(tt.set, nil)
+			got, err := pae.evaluateConstraint("p", tt.c, SystemContext{})
+			if err != nil || got != tt.want {
+				t.Fatalf("evaluateConstraint() = %t, %v; want %t", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateConstraint_InvalidGroups(t *testing.T) {
+	for name, c := range map[string]PolicyConstraint{
+		"empty":                  {},
+		"key and members":        {Key: "Flag.A", AllOf: []PolicyConstraint{flag("B")}},
+		"two combinators":        {AllOf: []PolicyConstraint{flag("A")}, AnyOf: []PolicyConstraint{flag("B")}},
+		"threshold on all_of":    {AllOf: []PolicyConstraint{flag("A")}, Threshold: 1},
+		"negative weight":        {AnyOf: []PolicyConstraint{weighted("A", -1)}, Threshold: 1},
+		"unsupported member key": {AllOf: []PolicyConstraint{{Key: "Flag.Unknown"}}},
+	} {
+		pae := flagEngine(map[string]bool{"A": true}, nil)
+		if _, err := pae.evaluateConstraint("p", c, SystemContext{}); err == nil {
+			t.Errorf("%s: evaluateConstraint() error = nil, want an error", name)
+		}
+	}
+}
+
+func TestEvaluateRequest_ReportsUnsatisfiedGroup(t *testing.T) {
+	group := PolicyConstraint{AnyOf: []PolicyConstraint{flag("A"), {AllOf: []PolicyConstraint{flag("B"), flag("C")}}}, Required: true}
+	pae := flagEngine(map[string]bool{"B": true}, map[string]IsolationPolicy{
+		"L3": {ID: "L3", Constraints: []PolicyConstraint{group}},
+	})
+
+	admitted, err := pae.EvaluateRequest("L3", SystemContext{})
+	var unsatisfied *ErrConstraintUnsatisfied
+	if admitted || !errors.As(err, &unsatisfied) {
+		t.Fatalf("EvaluateRequest() = %t, %v; want ErrConstraintUnsatisfied", admitted, err)
+	}
+	if want := "any_of(Flag.A, all_of(Flag.B, Flag.C))"; unsatisfied.Key != want {
+		t.Errorf("Key = %q, want %q", unsatisfied.Key, want)
+	}
+
+	failing, err := pae.UnsatisfiedConstraints("L3", SystemContext{})
+	if err != nil || len(failing) != 1 || failing[0] != unsatisfied.Key {
+		t.Errorf("UnsatisfiedConstraints() = %v, %v; want [%s]", failing, err, unsatisfied.Key)
+	}
+}
+
+func TestMemoryBytesConstraint(t *testing.T) {
+	pae := newEngine("", nil)
+	sc := SystemContext{Hardware: HardwareContext{MemoryBytes: 16 << 30}}
+	for _, tt := range []struct {
+		min, max string
+		want     bool
+	}{
+		{min: "8GiB", want: true},
+		{min: "16gib", max: "16GiB", want: true},
+		{min: "17179869185", want: false},
+		{max: "16GB", want: false},
+	} {
+		got, err := pae.evaluateConstraint("p", PolicyConstraint{Key: "Hardware.MemoryBytes", Min: tt.min, Max: tt.max}, sc)
+		if err != nil || got != tt.want {
+			t.Errorf("MemoryBytes(min %q, max %q) = %t, %v; want %t", tt.min, tt.max, got, err, tt.want)
+		}
+	}
+	if _, err := pae.evaluateConstraint("p", PolicyConstraint{Key: "Hardware.MemoryBytes", Min: "lots"}, sc); err == nil {
+		t.Error("MemoryBytes(min \"lots\") error = nil, want a parse error")
+	}
+}
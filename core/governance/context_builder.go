@@ -0,0 +1,176 @@
+package governance
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	knownTEETechnologies = map[string]bool{TEETechnologySGX: true, TEETechnologySEVSNP: true, TEETechnologyTDX: true, TEETechnologyTrustZone: true}
+	knownSELinuxModes    = map[string]bool{"": true, "enforcing": true, "permissive": true, "disabled": true}
+)
+
+// Validate checks that the context has the fields admission depends on and that enumerated fields
+// hold known values. All problems are reported together.
+func (sc SystemContext) Validate() error {
+	var problems []string
+	hw := sc.Hardware
+	if hw.CPUArchitecture == "" {
+		problems = append(problems, "hardware.cpu_architecture is required")
+	}
+	if hw.MemoryBytes == 0 {
+		problems = append(problems, "hardware.memory_bytes is required")
+	}
+	if sc.OS.KernelVersion == "" {
+		problems = append(problems, "os.kernel_version is required")
+	}
+	if hw.TEE.Technology != "" {
+		if !hw.TEE.Supported {
+			problems = append(problems, fmt.Sprintf("hardware.tee.technology is '%s' but tee.supported is false", hw.TEE.Technology))
+		}
+		if !knownTEETechnologies[hw.TEE.Technology] {
+			problems = append(problems, fmt.Sprintf("unknown TEE technology '%s'", hw.TEE.Technology))
+		}
+	}
+	if _, ok := lockdownRank[sc.OS.Lockdown]; sc.OS.Lockdown != "" && !ok {
+		problems = append(problems, fmt.Sprintf("unknown lockdown mode '%s'", sc.OS.Lockdown))
+	}
+	if !knownSELinuxModes[sc.OS.SELinux] {
+		problems = append(problems, fmt.Sprintf("unknown SELinux mode '%s'", sc.OS.SELinux))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid system context: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// SystemContextBuilder assembles a SystemContext fluently, e.g.,
+//
+//	sc, err := NewSystemContext().WithCPUArchitecture("amd64").WithMemoryKB(8192).WithTEE(true).Build()
+type SystemContextBuilder struct {
+	sc SystemContext
+}
+
+// NewSystemContext starts an empty builder.
+func NewSystemContext() *SystemContextBuilder {
+	return &SystemContextBuilder{}
+}
+
+// WithCPUArchitecture sets the CPU architecture, e.g., "amd64".
+func (b *SystemContextBuilder) WithCPUArchitecture(arch string) *SystemContextBuilder {
+	b.sc.Hardware.CPUArchitecture = arch
+	return b
+}
+
+// WithMemoryKB sets physical memory in KiB.
+func (b *SystemContextBuilder) WithMemoryKB(kb uint64) *SystemContextBuilder {
+	b.sc.Hardware.MemoryBytes = kb * 1024
+	return b
+}
+
+// WithMemoryBytes sets physical memory in bytes.
+func (b *SystemContextBuilder) WithMemoryBytes(n uint64) *SystemContextBuilder {
+	b.sc.Hardware.MemoryBytes = n
+	return b
+}
+
+// WithTEE sets whether a TEE is available, without naming the technology.
+func (b *SystemContextBuilder) WithTEE(supported bool) *SystemContextBuilder {
+	b.sc.Hardware.TEE.Supported = supported
+	return b
+}
+
+// WithTEETechnology declares an available TEE of the given technology (a TEETechnology constant).
+func (b *SystemContextBuilder) WithTEETechnology(technology, version string, attestation bool) *SystemContextBuilder {
+	b.sc.Hardware.TEE = TEEInfo{Supported: true, Technology: technology, Version: version, Attestation: attestation}
+	return b
+}
+
+// WithSRIOV sets whether SR-IOV is enabled.
+func (b *SystemContextBuilder) WithSRIOV(enabled bool) *SystemContextBuilder {
+	b.sc.Hardware.SR_IOV_Enabled = enabled
+	return b
+}
+
+// WithIOMMU sets DMA remapping support and the number of IOMMU groups.
+func (b *SystemContextBuilder) WithIOMMU(enabled bool, groups int) *SystemContextBuilder {
+	b.sc.Hardware.IOMMU = IOMMUInfo{Enabled: enabled, Groups: groups}
+	return b
+}
+
+// WithPCIDevice adds a PCIe function.
+func (b *SystemContextBuilder) WithPCIDevice(dev PCIDevice) *SystemContextBuilder {
+	b.sc.Hardware.PCIDevices = append(b.sc.Hardware.PCIDevices, dev)
+	return b
+}
+
+// WithAccelerator adds a GPU or accelerator.
+func (b *SystemContextBuilder) WithAccelerator(acc Accelerator) *SystemContextBuilder {
+	b.sc.Hardware.Accelerators = append(b.sc.Hardware.Accelerators, acc)
+	return b
+}
+
+// WithKernelVersion sets the kernel release string.
+func (b *SystemContextBuilder) WithKernelVersion(version string) *SystemContextBuilder {
+	b.sc.OS.KernelVersion = version
+	return b
+}
+
+// WithLockdown sets the kernel lockdown mode (a Lockdown constant).
+func (b *SystemContextBuilder) WithLockdown(mode string) *SystemContextBuilder {
+	b.sc.OS.Lockdown = mode
+	return b
+}
+
+// WithSecureBoot sets the Secure Boot state.
+func (b *SystemContextBuilder) WithSecureBoot(enabled bool) *SystemContextBuilder {
+	b.sc.OS.SecureBoot = enabled
+	return b
+}
+
+// WithSELinux sets the SELinux mode: "enforcing", "permissive", or "disabled".
+func (b *SystemContextBuilder) WithSELinux(mode string) *SystemContextBuilder {
+	b.sc.OS.SELinux = mode
+	return b
+}
+
+// WithAppArmor sets whether AppArmor is enabled.
+func (b *SystemContextBuilder) WithAppArmor(enabled bool) *SystemContextBuilder {
+	b.sc.OS.AppArmor = enabled
+	return b
+}
+
+// WithKernelParam adds a boot parameter; use an empty value for bare flags.
+func (b *SystemContextBuilder) WithKernelParam(key, value string) *SystemContextBuilder {
+	if b.sc.OS.KernelCmdline == nil {
+		b.sc.OS.KernelCmdline = make(map[string]string)
+	}
+	b.sc.OS.KernelCmdline[key] = value
+	return b
+}
+
+// WithCPES sets a CPES value at a dotted path, creating intermediate objects.
+func (b *SystemContextBuilder) WithCPES(path string, value interface{}) *SystemContextBuilder {
+	if b.sc.CPESConfiguration == nil {
+		b.sc.CPESConfiguration = CPESConfig{}
+	}
+	b.sc.CPESConfiguration.Set(path, value)
+	return b
+}
+
+// Build validates and returns the context.
+func (b *SystemContextBuilder) Build() (SystemContext, error) {
+	if err := b.sc.Validate(); err != nil {
+		return SystemContext{}, err
+	}
+	return b.sc, nil
+}
+
+// MustBuild is like Build but panics on an invalid context. Intended for tests.
+func (b *SystemContextBuilder) MustBuild() SystemContext {
+	sc, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return sc
+}
@@ -0,0 +1,109 @@
+package governance
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func validBuilder() *SystemContextBuilder {
+	return NewSystemContext().WithCPUArchitecture("amd64").WithMemoryKB(8192).WithKernelVersion("6.8.0")
+}
+
+func TestSystemContextBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SystemContextBuilder
+		want    []string // Substrings of the error; none if valid
+	}{
+		{name: "Minimal", builder: validBuilder()},
+		{
+			name:    "Fully Populated",
+			builder: validBuilder().WithTEETechnology(TEETechnologyTDX, "1.5", true).WithLockdown(LockdownConfidentiality).WithSELinux("enforcing"),
+		},
+		{
+			name:    "Empty Reports Every Required Field",
+			builder: NewSystemContext(),
+			want:    []string{"hardware.cpu_architecture is required", "hardware.memory_bytes is required", "os.kernel_version is required"},
+		},
+		{name: "TEE Without Technology", builder: validBuilder().WithTEE(true)},
+		{
+			// WithTEE(false) after WithTEETechnology leaves a technology on an unsupported TEE.
+			name:    "Technology On Unsupported TEE",
+			builder: validBuilder().WithTEETechnology(TEETechnologySGX, "2", false).WithTEE(false),
+			want:    []string{"hardware.tee.technology is 'SGX' but tee.supported is false"},
+		},
+		{name: "Unknown TEE Technology", builder: validBuilder().WithTEETechnology("Enclave9", "", false), want: []string{"unknown TEE technology 'Enclave9'"}},
+		{name: "Unknown Lockdown", builder: validBuilder().WithLockdown("paranoid"), want: []string{"unknown lockdown mode 'paranoid'"}},
+		{name: "Unknown SELinux Mode", builder: validBuilder().WithSELinux("Enforcing"), want: []string{"unknown SELinux mode 'Enforcing'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Build() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Build() succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Build() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSystemContextBuilder_Fields(t *testing.T) {
+	gpu := Accelerator{Vendor: "nvidia"}
+	dev := PCIDevice{Address: "0000:3b:00.0"}
+	sc := validBuilder().
+		WithMemoryBytes(16<<30).
+		WithTEETechnology(TEETechnologySEVSNP, "1.55", true).
+		WithSRIOV(true).
+		WithIOMMU(true, 12).
+		WithPCIDevice(dev).
+		WithAccelerator(gpu).
+		WithSecureBoot(true).
+		WithAppArmor(true).
+		WithKernelParam("iommu", "on").
+		WithKernelParam("nosmt", "").
+		WithCPES("network.egress.enabled", false).
+		WithCPES("network.mode", "strict").
+		MustBuild()
+
+	want := SystemContext{
+		Hardware: HardwareContext{
+			CPUArchitecture: "amd64",
+			MemoryBytes:     16 << 30, // The last memory setter wins
+			TEE:             TEEInfo{Supported: true, Technology: TEETechnologySEVSNP, Version: "1.55", Attestation: true},
+			SR_IOV_Enabled:  true,
+			IOMMU:           IOMMUInfo{Enabled: true, Groups: 12},
+			PCIDevices:      []PCIDevice{dev},
+			Accelerators:    []Accelerator{gpu},
+		},
+		OS: OSContext{
+			KernelVersion: "6.8.0",
+			SecureBoot:    true,
+			AppArmor:      true,
+			KernelCmdline: map[string]string{"iommu": "on", "nosmt": ""},
+		},
+		CPESConfiguration: CPESConfig{"network": map[string]interface{}{"egress": map[string]interface{}{"enabled": false}, "mode": "strict"}},
+	}
+	if !reflect.DeepEqual(sc, want) {
+		t.Errorf("MustBuild() = %+v, want %+v", sc, want)
+	}
+}
+
+func TestSystemContextBuilder_MustBuildPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild() of an invalid context did not panic")
+		}
+	}()
+	NewSystemContext().MustBuild()
+}
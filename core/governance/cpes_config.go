@@ -0,0 +1,282 @@
+package governance
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CPESSchemaVersionKey is the top-level CPES key carrying the schema version of the document.
+const CPESSchemaVersionKey = "schema_version"
+
+// CPES field types accepted by CPESFieldSpec.
+const (
+	CPESTypeString = "string"
+	CPESTypeInt    = "int"
+	CPESTypeFloat  = "float"
+	CPESTypeBool   = "bool"
+)
+
+// CPESConfig is the Configuration and Environment State document. Nested objects are addressed
+// with dotted paths, e.g., "network.egress.enabled".
+type CPESConfig map[string]interface{}
+
+// Get returns the raw value at a dotted path.
+func (c CPESConfig) Get(path string) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := asMap(cur)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// Set stores value at a dotted path, replacing any non-object value along the way.
+func (c CPESConfig) Set(path string, value interface{}) {
+	m := map[string]interface{}(c)
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := asMap(m[part])
+		if !ok {
+			child = make(map[string]interface{})
+			m[part] = child
+		}
+		m = child
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// GetString returns the string at path, or def if it is missing or not a string.
+func (c CPESConfig) GetString(path, def string) string {
+	if v, ok := c.Get(path); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return def
+}
+
+// GetBool returns the bool at path, or def if it is missing or not a bool.
+func (c CPESConfig) GetBool(path string, def bool) bool {
+	if v, ok := c.Get(path); ok {
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	}
+	return def
+}
+
+// GetInt returns the integer at path, or def if it is missing or not integral. JSON-decoded
+// numbers (float64) are accepted when they have no fractional part.
+func (c CPESConfig) GetInt(path string, def int64) int64 {
+	if v, ok := c.Get(path); ok {
+		if n, ok := asInt(v); ok {
+			return n
+		}
+	}
+	return def
+}
+
+// GetFloat returns the number at path, or def if it is missing or not numeric.
+func (c CPESConfig) GetFloat(path string, def float64) float64 {
+	if v, ok := c.Get(path); ok {
+		if f, ok := asFloat(v); ok {
+			return f
+		}
+	}
+	return def
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case CPESConfig:
+		return m, true
+	}
+	return nil, false
+}
+
+func asFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func asInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	}
+	f, ok := asFloat(v)
+	if !ok || f != math.Trunc(f) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// CPESFieldSpec declares the type of a CPES key and whether it must be present.
+type CPESFieldSpec struct {
+	Type     string `json:"type"` // One of the CPESType constants
+	Required bool   `json:"required"`
+}
+
+// CPESSchema describes a versioned CPES document layout.
+type CPESSchema struct {
+	Version string                   `json:"version"`
+	Fields  map[string]CPESFieldSpec `json:"fields"` // Keyed by dotted path
+}
+
+// Validate checks the document's schema version, required fields, and field types.
+// All problems are reported together, ordered by path.
+func (s CPESSchema) Validate(cfg CPESConfig) error {
+	var problems []string
+	if v := cfg.GetString(CPESSchemaVersionKey, ""); v != s.Version {
+		problems = append(problems, fmt.Sprintf("schema version '%s' does not match expected '%s'", v, s.Version))
+	}
+
+	paths := make([]string, 0, len(s.Fields))
+	for path := range s.Fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		spec := s.Fields[path]
+		v, ok := cfg.Get(path)
+		if !ok {
+			if spec.Required {
+				problems = append(problems, fmt.Sprintf("required key '%s' is missing", path))
+			}
+			continue
+		}
+		if !matchesCPESType(v, spec.Type) {
+			problems = append(problems, fmt.Sprintf("key '%s' must be of type %s, got %T", path, spec.Type, v))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("CPES configuration invalid: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func matchesCPESType(v interface{}, typ string) bool {
+	switch typ {
+	case CPESTypeString:
+		_, ok := v.(string)
+		return ok
+	case CPESTypeBool:
+		_, ok := v.(bool)
+		return ok
+	case CPESTypeInt:
+		_, ok := asInt(v)
+		return ok
+	case CPESTypeFloat:
+		_, ok := asFloat(v)
+		return ok
+	}
+	return false
+}
+
+// cpesConstraintPrefix routes constraint keys such as "CPES.network.egress.enabled" to evaluateCPES.
+const cpesConstraintPrefix = "CPES."
+
+// evaluateCPES checks a CPES key against a constraint. With Values, the key's value (formatted as
+// a string) must be one of them; with MinVersion, it must be a dotted version at least that high;
+// with Min or Max, it must be a number within those inclusive bounds; otherwise it is compared as a
+// bool against Required, a missing key counting as false.
+func evaluateCPES(context SystemContext, constraint PolicyConstraint) (bool, error) {
+	path := strings.TrimPrefix(constraint.Key, cpesConstraintPrefix)
+	v, present := context.CPESConfiguration.Get(path)
+
+	switch {
+	case constraint.Min != "" || constraint.Max != "":
+		n, ok := asFloat(v)
+		if !present || !ok {
+			return false, nil
+		}
+		if constraint.Min != "" {
+			lo, err := parseBound(constraint.Min)
+			if err != nil || n < lo {
+				return false, err
+			}
+		}
+		if constraint.Max != "" {
+			hi, err := parseBound(constraint.Max)
+			if err != nil || n > hi {
+				return false, err
+			}
+		}
+		return true, nil
+	case len(constraint.Values) > 0:
+		if !present {
+			return false, nil
+		}
+		s := fmt.Sprint(v)
+		if f, ok := asFloat(v); ok {
+			s = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		for _, allowed := range constraint.Values {
+			if s == allowed {
+				return true, nil
+			}
+		}
+		return false, nil
+	case constraint.MinVersion != "":
+		version, ok := v.(string)
+		if !present || !ok {
+			return false, nil
+		}
+		cmp, err := compareVersions(version, constraint.MinVersion)
+		if err != nil {
+			return false, err
+		}
+		return cmp >= 0, nil
+	default:
+		if present {
+			if _, ok := v.(bool); !ok {
+				return false, fmt.Errorf("CPES key '%s' is %T, not a bool", path, v)
+			}
+		}
+		return context.CPESConfiguration.GetBool(path, false) == constraint.Required, nil
+	}
+}
+
+// parseBound parses a Min/Max bound: a number, or a duration such as "200ms" expressed in seconds,
+// the unit durations are stored in CPES documents.
+func parseBound(s string) (float64, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("bound '%s' is neither a number nor a duration", s)
+	}
+	return d.Seconds(), nil
+}
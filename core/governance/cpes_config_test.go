@@ -0,0 +1,157 @@
+package governance
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testCPES(t *testing.T) CPESConfig {
+	t.Helper()
+	var cfg CPESConfig
+	doc := `{"schema_version": "1", "network": {"egress": {"enabled": true, "rate": 2.5, "peers": 3}, "mode": "strict"}, "agent": {"version": "1.4.2", "timeout": 0.2}}`
+	if err := json.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestCPESConfig_Accessors(t *testing.T) {
+	cfg := testCPES(t)
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "String", got: cfg.GetString("network.mode", "open"), want: "strict"},
+		{name: "String Missing", got: cfg.GetString("network.zone", "open"), want: "open"},
+		{name: "String Wrong Type", got: cfg.GetString("network.egress.enabled", "open"), want: "open"},
+		{name: "Bool", got: cfg.GetBool("network.egress.enabled", false), want: true},
+		{name: "Bool Wrong Type", got: cfg.GetBool("network.mode", true), want: true},
+		{name: "Int From JSON Number", got: cfg.GetInt("network.egress.peers", -1), want: int64(3)},
+		{name: "Int Fractional", got: cfg.GetInt("network.egress.rate", -1), want: int64(-1)},
+		{name: "Int Through Non-Object", got: cfg.GetInt("network.mode.peers", -1), want: int64(-1)},
+		{name: "Float", got: cfg.GetFloat("network.egress.rate", 0), want: 2.5},
+		{name: "Float From Integral", got: cfg.GetFloat("network.egress.peers", 0), want: 3.0},
+		{name: "Float Wrong Type", got: cfg.GetFloat("agent.version", 1), want: 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", tt.got, tt.got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestCPESConfig_Set(t *testing.T) {
+	cfg := testCPES(t)
+	cfg.Set("network.egress.peers", 5)
+	cfg.Set("network.mode.level", "high") // Replaces the string with an object
+	cfg.Set("storage.encrypted", true)    // Creates the intermediate object
+
+	if got := cfg.GetInt("network.egress.peers", 0); got != 5 {
+		t.Errorf("peers = %d, want 5", got)
+	}
+	if got := cfg.GetString("network.mode.level", ""); got != "high" {
+		t.Errorf("mode.level = %q, want high", got)
+	}
+	if !cfg.GetBool("storage.encrypted", false) {
+		t.Error("storage.encrypted = false, want true")
+	}
+	// Siblings along the path are kept.
+	if !cfg.GetBool("network.egress.enabled", false) {
+		t.Error("network.egress.enabled was lost")
+	}
+}
+
+func TestCPESSchema_Validate(t *testing.T) {
+	fields := map[string]CPESFieldSpec{
+		"network.egress.enabled": {Type: CPESTypeBool, Required: true},
+		"network.egress.peers":   {Type: CPESTypeInt, Required: true},
+		"network.egress.rate":    {Type: CPESTypeFloat},
+		"agent.version":          {Type: CPESTypeString},
+		"storage.encrypted":      {Type: CPESTypeBool},
+	}
+	tests := []struct {
+		name   string
+		schema CPESSchema
+		edit   func(CPESConfig)
+		want   []string // Substrings of the error, in order; none if valid
+	}{
+		{name: "Valid", schema: CPESSchema{Version: "1", Fields: fields}},
+		{name: "Version Mismatch", schema: CPESSchema{Version: "2", Fields: fields}, want: []string{"schema version '1' does not match expected '2'"}},
+		{
+			name: "All Problems Ordered By Path", schema: CPESSchema{Version: "1", Fields: fields},
+			edit: func(c CPESConfig) {
+				c.Set("agent.version", 1.5)
+				c.Set("network.egress.peers", 2.5)
+				delete(c["network"].(map[string]interface{})["egress"].(map[string]interface{}), "enabled")
+			},
+			want: []string{"key 'agent.version' must be of type string", "required key 'network.egress.enabled' is missing", "key 'network.egress.peers' must be of type int"},
+		},
+		{name: "Unknown Type", schema: CPESSchema{Version: "1", Fields: map[string]CPESFieldSpec{"network.mode": {Type: "enum"}}}, want: []string{"key 'network.mode' must be of type enum"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testCPES(t)
+			if tt.edit != nil {
+				tt.edit(cfg)
+			}
+			err := tt.schema.Validate(cfg)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() succeeded, want an error")
+			}
+			rest := err.Error()
+			for _, want := range tt.want {
+				i := strings.Index(rest, want)
+				if i < 0 {
+					t.Fatalf("Validate() error = %v, want %q in order", err, want)
+				}
+				rest = rest[i+len(want):]
+			}
+		})
+	}
+}
+
+func TestEvaluateCPES(t *testing.T) {
+	sc := SystemContext{CPESConfiguration: testCPES(t)}
+	tests := []struct {
+		name       string
+		constraint PolicyConstraint
+		want       bool
+		wantErr    bool
+	}{
+		{name: "Bool Required", constraint: PolicyConstraint{Key: "CPES.network.egress.enabled", Required: true}, want: true},
+		{name: "Bool Missing Counts As False", constraint: PolicyConstraint{Key: "CPES.storage.encrypted", Required: false}, want: true},
+		{name: "Bool Wrong Type", constraint: PolicyConstraint{Key: "CPES.network.mode", Required: true}, wantErr: true},
+		{name: "Values", constraint: PolicyConstraint{Key: "CPES.network.mode", Values: []string{"audit", "strict"}}, want: true},
+		{name: "Values Number", constraint: PolicyConstraint{Key: "CPES.network.egress.rate", Values: []string{"2.5"}}, want: true},
+		{name: "Values Missing", constraint: PolicyConstraint{Key: "CPES.network.zone", Values: []string{"eu"}}, want: false},
+		{name: "Min Version", constraint: PolicyConstraint{Key: "CPES.agent.version", MinVersion: "1.4"}, want: true},
+		{name: "Min Version Too Low", constraint: PolicyConstraint{Key: "CPES.agent.version", MinVersion: "1.10"}, want: false},
+		{name: "Within Bounds", constraint: PolicyConstraint{Key: "CPES.network.egress.peers", Min: "3", Max: "4"}, want: true},
+		{name: "Above Max", constraint: PolicyConstraint{Key: "CPES.network.egress.rate", Max: "2"}, want: false},
+		{name: "Duration Bound", constraint: PolicyConstraint{Key: "CPES.agent.timeout", Max: "200ms"}, want: true},
+		{name: "Duration Bound Exceeded", constraint: PolicyConstraint{Key: "CPES.agent.timeout", Max: "150ms"}, want: false},
+		{name: "Bound Not Numeric Value", constraint: PolicyConstraint{Key: "CPES.network.mode", Min: "1"}, want: false},
+		{name: "Invalid Bound", constraint: PolicyConstraint{Key: "CPES.network.egress.peers", Min: "lots"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := evaluateCPES(sc, tt.constraint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evaluateCPES() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evaluateCPES() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
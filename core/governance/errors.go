@@ -0,0 +1,34 @@
+package governance
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the admission engine; match them with errors.Is.
+var (
+	ErrPolicyNotFound        = errors.New("policy not found in manifest")
+	ErrUnsupportedConstraint = errors.New("unsupported constraint key")
+	ErrUnsupportedSchema     = errors.New("unsupported policy manifest schema version")
+	ErrBundleChecksum        = errors.New("policy bundle checksum mismatch")
+	ErrBundleUnlistedFile    = errors.New("policy bundle contains a file not listed in its index")
+	ErrInsufficientResources = errors.New("insufficient node resources")
+
+	ErrInvalidAdmissionToken  = errors.New("invalid admission token")
+	ErrAdmissionTokenExpired  = errors.New("admission token expired")
+	ErrAdmissionTokenMismatch = errors.New("admission token does not match the request")
+)
+
+// ErrConstraintUnsatisfied reports that the system context did not meet a policy constraint.
+// Match it with errors.As to find which constraint blocked admission.
+type ErrConstraintUnsatisfied struct {
+	PolicyID string
+	Key      string
+	Required bool
+	// CorrelationID identifies the request that was denied, when evaluated with EvaluateRequestContext.
+	CorrelationID string
+}
+
+func (e *ErrConstraintUnsatisfied) Error() string {
+	return fmt.Sprintf("policy '%s' admission failed: constraint '%s' (Required: %t) not met by system context", e.PolicyID, e.Key, e.Required)
+}
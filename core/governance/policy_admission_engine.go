@@ -1,10 +1,22 @@
 package governance
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"pkg/bytesize"
+	"pkg/correlation"
+	"pkg/expand"
+	"pkg/metrics"
+	"pkg/strictjson"
 )
 
 // ConstraintEvaluatorFunc defines the signature for a function that evaluates a specific constraint key.
@@ -19,6 +31,13 @@ type PolicyAdmissionEngine struct {
 	ManifestPath string
 	Policies     map[string]IsolationPolicy
 	ConstraintRegistry map[string]ConstraintEvaluatorFunc
+	// BundleVersion is the version from the bundle index when loaded with NewPolicyAdmissionEngineFromBundle.
+	BundleVersion string
+	// Tokens, if set, mints admission tokens for admitted requests; see EvaluateRequestToken.
+	Tokens *TokenIssuer
+
+	decisions    metrics.Counter   // policy, result: admitted|denied|error
+	evalDuration metrics.Histogram // seconds
 }
 
 // manifestWrapper assists in decoding the expected V2.0-POLI-STRUCT schema.
@@ -32,86 +51,355 @@ func (pae *PolicyAdmissionEngine) RegisterConstraint(key string, fn ConstraintEv
 	pae.ConstraintRegistry[key] = fn
 }
 
+// SetMetrics instruments admission decisions. A nil provider disables instrumentation.
+func (pae *PolicyAdmissionEngine) SetMetrics(p metrics.Provider) {
+	p = metrics.OrNop(p)
+	pae.decisions = p.Counter("admission_decisions_total", "Admission decisions by policy and result.", "policy", "result")
+	pae.evalDuration = p.Histogram("admission_evaluation_duration_seconds", "Time to evaluate a policy against a system context.", nil)
+}
+
+// ManifestOption customizes how a manifest is decoded.
+type ManifestOption func(*manifestOptions)
+
+type manifestOptions struct {
+	strict   bool
+	expander *expand.Expander
+}
+
+// WithStrictDecoding rejects manifests containing fields the schema does not define, reporting
+// the path of the first one (e.g. "policies[0].constraints[1].requierd"), instead of ignoring them.
+func WithStrictDecoding() ManifestOption {
+	return func(o *manifestOptions) { o.strict = true }
+}
+
+// WithExpansion expands ${VAR} references (environment variables, ${node.name}, ${node.region})
+// in the manifest with x before decoding it, so one manifest can serve several environments. A
+// reference without a value fails the load. Bundle checksums cover the unexpanded files.
+func WithExpansion(x *expand.Expander) ManifestOption {
+	return func(o *manifestOptions) { o.expander = x }
+}
+
 // NewPolicyAdmissionEngine loads the manifest and initializes the engine, including the constraint registry.
-func NewPolicyAdmissionEngine(path string) (*PolicyAdmissionEngine, error) {
+func NewPolicyAdmissionEngine(path string, opts ...ManifestOption) (*PolicyAdmissionEngine, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read isolation manifest at %s: %w", path, err)
 	}
+	return newPolicyAdmissionEngine(data, path, opts)
+}
 
-	var wrapper manifestWrapper
-	if err := json.Unmarshal(data, &wrapper); err != nil {
-		return nil, fmt.Errorf("failed to parse policy manifest JSON: %w", err)
+// NewPolicyAdmissionEngineFromReader loads the manifest from r, e.g. an in-memory manifest in tests.
+// ManifestPath is left empty.
+func NewPolicyAdmissionEngineFromReader(r io.Reader, opts ...ManifestOption) (*PolicyAdmissionEngine, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read isolation manifest: %w", err)
 	}
+	return newPolicyAdmissionEngine(data, "", opts)
+}
 
-	if wrapper.SchemaVersion != "V2.0-POLI-STRUCT" {
-		// Fixed previously unhandled 'tErrorf' reference.
-		return nil, fmt.Errorf("unsupported policy manifest schema version: %s", wrapper.SchemaVersion)
+// NewPolicyAdmissionEngineFromFS loads the manifest named name from fsys, e.g. an embed.FS built
+// with go:embed. ManifestPath is set to name.
+func NewPolicyAdmissionEngineFromFS(fsys fs.FS, name string, opts ...ManifestOption) (*PolicyAdmissionEngine, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read isolation manifest %s: %w", name, err)
+	}
+	return newPolicyAdmissionEngine(data, name, opts)
+}
+
+func newPolicyAdmissionEngine(data []byte, path string, opts []ManifestOption) (*PolicyAdmissionEngine, error) {
+	policies, err := parseManifest(data, applyManifestOptions(opts))
+	if err != nil {
+		return nil, err
 	}
 
 	policyMap := make(map[string]IsolationPolicy)
-	for _, policy := range wrapper.Policies {
+	for _, policy := range policies {
 		policyMap[policy.ID] = policy
 	}
+	return newEngine(path, policyMap), nil
+}
+
+func applyManifestOptions(opts []ManifestOption) manifestOptions {
+	var o manifestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// parseManifest decodes a V2.0-POLI-STRUCT document into its policies.
+func parseManifest(data []byte, o manifestOptions) ([]IsolationPolicy, error) {
+	decode := json.Unmarshal
+	if o.strict {
+		decode = strictjson.Unmarshal
+	}
+
+	if o.expander != nil {
+		expanded, err := o.expander.ExpandJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand policy manifest: %w", err)
+		}
+		data = expanded
+	}
+
+	var wrapper manifestWrapper
+	if err := decode(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse policy manifest JSON: %w", err)
+	}
 
+	if wrapper.SchemaVersion != "V2.0-POLI-STRUCT" {
+		// Fixed previously unhandled 'tErrorf' reference.
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSchema, wrapper.SchemaVersion)
+	}
+	return wrapper.Policies, nil
+}
+
+func newEngine(path string, policies map[string]IsolationPolicy) *PolicyAdmissionEngine {
 	engine := &PolicyAdmissionEngine{
 		ManifestPath: path,
-		Policies:     policyMap,
+		Policies:     policies,
 		ConstraintRegistry: make(map[string]ConstraintEvaluatorFunc),
 	}
+	engine.SetMetrics(nil)
 
 	// Initialize and register default evaluators	
 	engine.registerDefaultEvaluators()
 
-	return engine, nil
+	return engine
 }
 
 // registerDefaultEvaluators sets up the common constraint logic dynamically, decoupling evaluation from the core loop.
 // Constraint values (PolicyConstraint.Required) are treated as strings to allow flexible comparison logic.
 func (pae *PolicyAdmissionEngine) registerDefaultEvaluators() {
-	// Generic evaluator for mandatory boolean hardware flags
-	boolEvaluator := func(current bool, requiredStr string) (bool, error) {
-		required, err := strconv.ParseBool(requiredStr)
+	pae.RegisterConstraint("Hardware.TEE_Support", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.Hardware.TEE.Supported == constraint.Required, nil
+	})
+
+	pae.RegisterConstraint("Hardware.SR_IOV_Enabled", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.Hardware.SR_IOV_Enabled == constraint.Required, nil
+	})
+
+	pae.RegisterConstraint("Hardware.IOMMU_Enabled", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.Hardware.IOMMU.Enabled == constraint.Required, nil
+	})
+
+	pae.RegisterConstraint("Hardware.TEE.Technology", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		if len(constraint.Values) == 0 {
+			return false, fmt.Errorf("constraint requires a non-empty list of allowed technologies")
+		}
+		if !context.Hardware.TEE.Supported {
+			return false, nil
+		}
+		for _, tech := range constraint.Values {
+			if strings.EqualFold(tech, context.Hardware.TEE.Technology) {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+
+	pae.RegisterConstraint("Hardware.TEE.Version", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		if constraint.MinVersion == "" {
+			return false, fmt.Errorf("constraint requires min_version")
+		}
+		if !context.Hardware.TEE.Supported || context.Hardware.TEE.Version == "" {
+			return false, nil
+		}
+		cmp, err := compareVersions(context.Hardware.TEE.Version, constraint.MinVersion)
 		if err != nil {
-			return false, fmt.Errorf("constraint value '%s' is not a valid boolean: %w", requiredStr, err)
+			return false, err
 		}
-		return current == required, nil
-	}
+		return cmp >= 0, nil
+	})
 
-	pae.RegisterConstraint("Hardware.TEE_Support", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
-		return boolEvaluator(context.Hardware.TEE_Support, constraint.Required)
+	pae.RegisterConstraint("Hardware.TEE.Attestation", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.Hardware.TEE.Attestation == constraint.Required, nil
 	})
 
-	pae.RegisterConstraint("Hardware.SR_IOV_Enabled", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
-		return boolEvaluator(context.Hardware.SR_IOV_Enabled, constraint.Required)
+	pae.RegisterConstraint("Hardware.Accelerator.Present", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return (len(context.Hardware.Accelerators) > 0) == constraint.Required, nil
+	})
+
+	// The remaining accelerator constraints are satisfied if any single accelerator qualifies.
+	pae.RegisterConstraint("Hardware.Accelerator.Vendor", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return anyAccelerator(context, constraint, func(a Accelerator) string { return a.Vendor })
+	})
+
+	pae.RegisterConstraint("Hardware.Accelerator.Partitioning", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return anyAccelerator(context, constraint, func(a Accelerator) string { return a.Partitioning })
+	})
+
+	pae.RegisterConstraint("Hardware.Accelerator.ConfidentialCompute", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		supported := false
+		for _, a := range context.Hardware.Accelerators {
+			supported = supported || a.ConfidentialCompute
+		}
+		return supported == constraint.Required, nil
+	})
+
+	// OS.Lockdown is satisfied when the active mode is at least as strict as the least strict listed mode,
+	// so ["integrity"] admits both integrity and confidentiality.
+	pae.RegisterConstraint("OS.Lockdown", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		if len(constraint.Values) == 0 {
+			return false, fmt.Errorf("constraint requires a non-empty list of lockdown modes")
+		}
+		minimum := len(lockdownRank)
+		for _, mode := range constraint.Values {
+			rank, ok := lockdownRank[mode]
+			if !ok {
+				return false, fmt.Errorf("unknown lockdown mode '%s'", mode)
+			}
+			if rank < minimum {
+				minimum = rank
+			}
+		}
+		current, ok := lockdownRank[context.OS.Lockdown]
+		return ok && current >= minimum, nil
+	})
+
+	pae.RegisterConstraint("OS.SecureBoot", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.OS.SecureBoot == constraint.Required, nil
+	})
+
+	pae.RegisterConstraint("OS.SELinux.Enforcing", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return (context.OS.SELinux == "enforcing") == constraint.Required, nil
+	})
+
+	pae.RegisterConstraint("OS.AppArmor", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		return context.OS.AppArmor == constraint.Required, nil
+	})
+
+	// OS.KernelCmdline requires every listed parameter, written as "flag" or "key=value".
+	pae.RegisterConstraint("OS.KernelCmdline", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		for _, param := range constraint.Values {
+			key, value, hasValue := strings.Cut(param, "=")
+			current, present := context.OS.KernelCmdline[key]
+			if !present || (hasValue && current != value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+
+	// Hardware.MemoryBytes bounds physical memory with Min and/or Max, e.g. "8GiB" or "8589934592".
+	pae.RegisterConstraint("Hardware.MemoryBytes", func(context SystemContext, constraint PolicyConstraint) (bool, error) {
+		if constraint.Min == "" && constraint.Max == "" {
+			return false, fmt.Errorf("constraint requires min or max")
+		}
+		if constraint.Min != "" {
+			lo, err := bytesize.Parse(constraint.Min)
+			if err != nil || context.Hardware.MemoryBytes < lo {
+				return false, err
+			}
+		}
+		if constraint.Max != "" {
+			hi, err := bytesize.Parse(constraint.Max)
+			if err != nil || context.Hardware.MemoryBytes > hi {
+				return false, err
+			}
+		}
+		return true, nil
 	})
 
 	// Future constraints (e.g., minimum version, required resource level) would be registered here.
 }
 
+// lockdownRank orders kernel lockdown modes by strictness.
+var lockdownRank = map[string]int{LockdownNone: 0, LockdownIntegrity: 1, LockdownConfidentiality: 2}
+
+// anyAccelerator reports whether some accelerator's field (as selected by field) is in constraint.Values.
+func anyAccelerator(context SystemContext, constraint PolicyConstraint, field func(Accelerator) string) (bool, error) {
+	if len(constraint.Values) == 0 {
+		return false, fmt.Errorf("constraint requires a non-empty list of allowed values")
+	}
+	for _, a := range context.Hardware.Accelerators {
+		for _, allowed := range constraint.Values {
+			if strings.EqualFold(allowed, field(a)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// compareVersions compares dotted numeric versions such as "1.1" and "2", returning -1, 0, or 1.
+// Missing components are treated as zero.
+func compareVersions(a, b string) (int, error) {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		var err error
+		if i < len(as) {
+			if x, err = strconv.Atoi(as[i]); err != nil {
+				return 0, fmt.Errorf("version '%s' is not dotted numeric: %w", a, err)
+			}
+		}
+		if i < len(bs) {
+			if y, err = strconv.Atoi(bs[i]); err != nil {
+				return 0, fmt.Errorf("version '%s' is not dotted numeric: %w", b, err)
+			}
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// EvaluateRequestContext is EvaluateRequest for a traced request: errors carry the correlation ID
+// from ctx, so a denial can be matched to the API call and audit entries that caused it.
+func (pae *PolicyAdmissionEngine) EvaluateRequestContext(ctx context.Context, policyID string, sc SystemContext) (bool, error) {
+	admitted, err := pae.EvaluateRequest(policyID, sc)
+	id := correlation.FromContext(ctx)
+	if err == nil || id == "" {
+		return admitted, err
+	}
+	var unsatisfied *ErrConstraintUnsatisfied
+	if errors.As(err, &unsatisfied) {
+		unsatisfied.CorrelationID = id
+		return admitted, err
+	}
+	return admitted, fmt.Errorf("%w (correlation_id=%s)", err, id)
+}
+
 // EvaluateRequest checks if the requested policy_id can be supported on the target hardware context.
-func (pae *PolicyAdmissionEngine) EvaluateRequest(policyID string, context SystemContext) (bool, error) {
+func (pae *PolicyAdmissionEngine) EvaluateRequest(policyID string, context SystemContext) (admitted bool, err error) {
+	start := time.Now()
+	defer func() {
+		pae.evalDuration.Observe(time.Since(start).Seconds())
+		label := policyID
+		if errors.Is(err, ErrPolicyNotFound) {
+			label = "unknown" // Keep caller-supplied IDs out of label cardinality.
+		}
+		var unsatisfied *ErrConstraintUnsatisfied
+		switch {
+		case admitted:
+			pae.decisions.Inc(label, "admitted")
+		case errors.As(err, &unsatisfied):
+			pae.decisions.Inc(label, "denied")
+		default:
+			pae.decisions.Inc(label, "error")
+		}
+	}()
+
 	policy, ok := pae.Policies[policyID]
 	if !ok {
-		return false, fmt.Errorf("requested policy ID '%s' not found in manifest", policyID)
+		return false, fmt.Errorf("%w: '%s'", ErrPolicyNotFound, policyID)
 	}
 
 	// Evaluate constraints against the SystemContext using the dynamic registry
 	for _, constraint := range policy.Constraints {
-		evaluator, found := pae.ConstraintRegistry[constraint.Key]
-		if !found {
-			// If a mandatory constraint key is unrecognized, admission must fail to ensure integrity.
-			return false, fmt.Errorf("unhandled or unsupported constraint key '%s' required by policy %s", constraint.Key, policyID)
-		}
-
-		satisfied, err := evaluator(context, constraint)
+		satisfied, err := pae.evaluateConstraint(policyID, constraint, context)
 		if err != nil {
-			// Evaluation failed due to malformed constraint definition or unexpected context format
-			return false, fmt.Errorf("policy '%s' admission failed during evaluation of constraint '%s': %w", policyID, constraint.Key, err)
+			return false, err
 		}
-
 		if !satisfied {
-			return false, fmt.Errorf("policy '%s' admission failed: constraint '%s' (Required: %s) not met by system context", policyID, constraint.Key, constraint.Required)
+			return false, &ErrConstraintUnsatisfied{PolicyID: policyID, Key: constraint.label(), Required: constraint.Required}
 		}
 	}
 
@@ -0,0 +1,160 @@
+package governance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"pkg/strictjson"
+)
+
+// BundleIndexFile is the index at the root of a policy bundle.
+const BundleIndexFile = "bundle.json"
+
+// BundleIndex lists the manifests that make up a policy bundle and their SHA-256 checksums, so a
+// bundle is applied exactly as it was packaged or not at all.
+type BundleIndex struct {
+	Version string       `json:"version"`
+	Files   []BundleFile `json:"files"`
+}
+
+// BundleFile is one manifest in a bundle. Path is slash-separated and relative to the bundle root.
+type BundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"` // Hex-encoded
+}
+
+// NewPolicyAdmissionEngineFromBundleDir loads the policy bundle rooted at dir. ManifestPath is set to dir.
+func NewPolicyAdmissionEngineFromBundleDir(dir string, opts ...ManifestOption) (*PolicyAdmissionEngine, error) {
+	engine, err := NewPolicyAdmissionEngineFromBundle(os.DirFS(dir), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("policy bundle %s: %w", dir, err)
+	}
+	engine.ManifestPath = dir
+	return engine, nil
+}
+
+// NewPolicyAdmissionEngineFromBundle loads every manifest listed in the bundle index of fsys,
+// verifying each checksum first. A policy ID defined in more than one manifest is an error, as is a
+// .json file the index does not list.
+func NewPolicyAdmissionEngineFromBundle(fsys fs.FS, opts ...ManifestOption) (*PolicyAdmissionEngine, error) {
+	o := applyManifestOptions(opts)
+	index, _, err := readBundleIndex(fsys, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUnlisted(fsys, index); err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]IsolationPolicy)
+	definedIn := make(map[string]string)
+	for _, f := range index.Files {
+		data, err := readBundleFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := parseManifest(data, o)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Path, err)
+		}
+		for _, policy := range manifest {
+			if prev, dup := definedIn[policy.ID]; dup {
+				return nil, fmt.Errorf("policy '%s' defined in both %s and %s", policy.ID, prev, f.Path)
+			}
+			definedIn[policy.ID] = f.Path
+			policies[policy.ID] = policy
+		}
+	}
+
+	engine := newEngine("", policies)
+	engine.BundleVersion = index.Version
+	return engine, nil
+}
+
+// BundleDigest hashes the bundle index and every file it lists, so callers can detect any change
+// to the bundle without reloading it. Checksums are not verified here.
+func BundleDigest(fsys fs.FS) ([sha256.Size]byte, error) {
+	index, raw, err := readBundleIndex(fsys, manifestOptions{})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	h := sha256.New()
+	h.Write(raw)
+	for _, f := range index.Files {
+		data, err := fs.ReadFile(fsys, f.Path)
+		if err != nil {
+			return [sha256.Size]byte{}, fmt.Errorf("failed to read bundle file %s: %w", f.Path, err)
+		}
+		h.Write(data)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+func readBundleIndex(fsys fs.FS, o manifestOptions) (BundleIndex, []byte, error) {
+	var index BundleIndex
+	raw, err := fs.ReadFile(fsys, BundleIndexFile)
+	if err != nil {
+		return index, nil, fmt.Errorf("failed to read bundle index: %w", err)
+	}
+	decode := json.Unmarshal
+	if o.strict {
+		decode = strictjson.Unmarshal
+	}
+	if err := decode(raw, &index); err != nil {
+		return index, nil, fmt.Errorf("failed to parse bundle index: %w", err)
+	}
+	if index.Version == "" {
+		return index, nil, errors.New("bundle index: version is required")
+	}
+	if len(index.Files) == 0 {
+		return index, nil, errors.New("bundle index: no files listed")
+	}
+	for _, f := range index.Files {
+		if !fs.ValidPath(f.Path) || f.Path == BundleIndexFile {
+			return index, nil, fmt.Errorf("bundle index: invalid path '%s'", f.Path)
+		}
+	}
+	return index, raw, nil
+}
+
+func readBundleFile(fsys fs.FS, f BundleFile) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle file %s: %w", f.Path, err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, f.SHA256) {
+		return nil, fmt.Errorf("%w: %s (index %s, actual %s)", ErrBundleChecksum, f.Path, f.SHA256, got)
+	}
+	return data, nil
+}
+
+// checkUnlisted rejects manifests present in the bundle but missing from the index, which would
+// otherwise be ignored without notice.
+func checkUnlisted(fsys fs.FS, index BundleIndex) error {
+	listed := make(map[string]bool, len(index.Files))
+	for _, f := range index.Files {
+		listed[f.Path] = true
+	}
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || p == BundleIndexFile || path.Ext(p) != ".json" {
+			return nil
+		}
+		if !listed[p] {
+			return fmt.Errorf("%w: %s", ErrBundleUnlistedFile, p)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package governance
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceUsage is an amount of node capacity, either reserved by policies or available.
+type ResourceUsage struct {
+	MemoryKB     uint64 `json:"memory_kb"`
+	Accelerators int    `json:"accelerators"`
+}
+
+// PolicyVerdict is the outcome of one policy within a set.
+type PolicyVerdict struct {
+	PolicyID string               `json:"policy_id"`
+	Admitted bool                 `json:"admitted"`
+	Reason   string               `json:"reason,omitempty"` // Why the policy was refused
+	Reserved ResourceRequirements `json:"reserved"`
+}
+
+// SetVerdict is the outcome of evaluating policies as co-resident workloads on one node.
+type SetVerdict struct {
+	// Admitted is true if every policy is admitted and their combined reservations fit the node.
+	Admitted  bool            `json:"admitted"`
+	Policies  []PolicyVerdict `json:"policies"`  // In the order requested
+	Required  ResourceUsage   `json:"required"`  // Sum of the reservations of every policy
+	Available ResourceUsage   `json:"available"` // Node capacity from the system context
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// EvaluateSet checks whether the policies can be co-resident on the node described by sc: each
+// must be admitted by EvaluateRequest and fit the node on its own, and their combined resource
+// reservations must fit too. A policy listed twice is counted twice, as two workloads. The error
+// is that of the first refused policy, or wraps ErrInsufficientResources if only the aggregate
+// does not fit; it is nil if the set is admitted.
+func (pae *PolicyAdmissionEngine) EvaluateSet(policyIDs []string, sc SystemContext) (SetVerdict, error) {
+	verdict := SetVerdict{
+		Policies:  make([]PolicyVerdict, 0, len(policyIDs)),
+		Available: ResourceUsage{MemoryKB: sc.Hardware.MemoryBytes / 1024, Accelerators: len(sc.Hardware.Accelerators)},
+	}
+	var firstErr error
+	for _, id := range policyIDs {
+		pv := PolicyVerdict{PolicyID: id}
+		admitted, err := pae.EvaluateRequest(id, sc)
+		if policy, ok := pae.Policies[id]; ok {
+			pv.Reserved = policy.Resources
+			verdict.Required.MemoryKB += policy.Resources.MinMemoryKB
+			verdict.Required.Accelerators += policy.Resources.Accelerators
+			if admitted {
+				err = fitResources(id, policy.Resources, verdict.Available)
+				admitted = err == nil
+			}
+		}
+		pv.Admitted = admitted
+		if err != nil {
+			pv.Reason = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		verdict.Policies = append(verdict.Policies, pv)
+	}
+	if firstErr == nil {
+		firstErr = fitAggregate(verdict.Required, verdict.Available)
+	}
+	verdict.Admitted = firstErr == nil
+	if firstErr != nil {
+		verdict.Reason = firstErr.Error()
+	}
+	return verdict, firstErr
+}
+
+// fitResources checks a single policy's reservation against the node's capacity.
+func fitResources(policyID string, r ResourceRequirements, available ResourceUsage) error {
+	if r.MinMemoryKB > available.MemoryKB {
+		return fmt.Errorf("%w: policy '%s' requires %d KB of memory, node has %d KB", ErrInsufficientResources, policyID, r.MinMemoryKB, available.MemoryKB)
+	}
+	if r.Accelerators > available.Accelerators {
+		return fmt.Errorf("%w: policy '%s' requires %d accelerators, node has %d", ErrInsufficientResources, policyID, r.Accelerators, available.Accelerators)
+	}
+	return nil
+}
+
+// fitAggregate checks the combined reservation of a set against the node's capacity.
+func fitAggregate(required, available ResourceUsage) error {
+	var short []string
+	if required.MemoryKB > available.MemoryKB {
+		short = append(short, fmt.Sprintf("memory %d KB of %d KB", required.MemoryKB, available.MemoryKB))
+	}
+	if required.Accelerators > available.Accelerators {
+		short = append(short, fmt.Sprintf("accelerators %d of %d", required.Accelerators, available.Accelerators))
+	}
+	if len(short) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: policies together require %s", ErrInsufficientResources, strings.Join(short, ", "))
+}
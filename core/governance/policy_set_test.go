@@ -0,0 +1,85 @@
+package governance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateSet(t *testing.T) {
+	const gib = 1024 * 1024 // KB
+	engine := flagEngine(map[string]bool{"A": true}, map[string]IsolationPolicy{
+		"small":  {ID: "small", Resources: ResourceRequirements{MinMemoryKB: gib / 2, Accelerators: 1}},
+		"big":    {ID: "big", Resources: ResourceRequirements{MinMemoryKB: 3 * gib}},
+		"gpu2":   {ID: "gpu2", Resources: ResourceRequirements{Accelerators: 2}},
+		"huge":   {ID: "huge", Resources: ResourceRequirements{MinMemoryKB: 8 * gib}},
+		"denied": {ID: "denied", Constraints: []PolicyConstraint{flag("B")}},
+	})
+	sc := SystemContext{Hardware: HardwareContext{MemoryBytes: 4 * gib * 1024, Accelerators: make([]Accelerator, 2)}}
+
+	tests := []struct {
+		name     string
+		policies []string
+		wantErr  error  // Sentinel the error wraps, if any
+		reason   string // Substring of the set's reason; empty if the set is admitted
+		admitted []bool // Per-policy verdicts
+		required ResourceUsage
+	}{
+		{name: "Fits", policies: []string{"small", "big"}, admitted: []bool{true, true}, required: ResourceUsage{MemoryKB: 3*gib + gib/2, Accelerators: 1}},
+		{name: "Fills Node Exactly", policies: []string{"small", "small", "big"}, admitted: []bool{true, true, true}, required: ResourceUsage{MemoryKB: 4 * gib, Accelerators: 2}},
+		{
+			name: "Memory Only Fits Per Policy", policies: []string{"big", "big"},
+			wantErr: ErrInsufficientResources, reason: "policies together require memory",
+			admitted: []bool{true, true}, required: ResourceUsage{MemoryKB: 6 * gib},
+		},
+		{
+			name: "Accelerators Only Fit Per Policy", policies: []string{"gpu2", "small"},
+			wantErr: ErrInsufficientResources, reason: "policies together require accelerators 3 of 2",
+			admitted: []bool{true, true}, required: ResourceUsage{MemoryKB: gib / 2, Accelerators: 3},
+		},
+		{
+			name: "Policy Too Large For Node", policies: []string{"small", "huge"},
+			wantErr: ErrInsufficientResources, reason: "policy 'huge' requires",
+			admitted: []bool{true, false}, required: ResourceUsage{MemoryKB: 8*gib + gib/2, Accelerators: 1},
+		},
+		{
+			// The first refused policy is reported, not the aggregate that also does not fit.
+			name: "Constraint Denial First", policies: []string{"big", "denied", "big"},
+			reason:   "constraint 'Flag.B'",
+			admitted: []bool{true, false, true}, required: ResourceUsage{MemoryKB: 6 * gib},
+		},
+		{
+			name: "Unknown Policy", policies: []string{"small", "missing"},
+			wantErr: ErrPolicyNotFound, reason: "'missing'",
+			admitted: []bool{true, false}, required: ResourceUsage{MemoryKB: gib / 2, Accelerators: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := engine.EvaluateSet(tt.policies, sc)
+			wantAdmitted := tt.reason == ""
+			switch {
+			case wantAdmitted && err != nil:
+				t.Fatalf("EvaluateSet() error = %v, want admitted", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("EvaluateSet() error = %v, want %v", err, tt.wantErr)
+			case !wantAdmitted && err == nil:
+				t.Fatal("EvaluateSet() admitted the set, want refused")
+			}
+			if verdict.Admitted != wantAdmitted || !strings.Contains(verdict.Reason, tt.reason) {
+				t.Errorf("verdict = admitted %v reason %q, want %v containing %q", verdict.Admitted, verdict.Reason, wantAdmitted, tt.reason)
+			}
+			if len(verdict.Policies) != len(tt.policies) {
+				t.Fatalf("verdict has %d policies, want %d", len(verdict.Policies), len(tt.policies))
+			}
+			for i, pv := range verdict.Policies {
+				if pv.PolicyID != tt.policies[i] || pv.Admitted != tt.admitted[i] {
+					t.Errorf("policy %d = %+v, want %s admitted %v", i, pv, tt.policies[i], tt.admitted[i])
+				}
+			}
+			if verdict.Required != tt.required || verdict.Available != (ResourceUsage{MemoryKB: 4 * gib, Accelerators: 2}) {
+				t.Errorf("required %+v available %+v, want required %+v", verdict.Required, verdict.Available, tt.required)
+			}
+		})
+	}
+}
@@ -7,6 +7,16 @@ type PolicyConstraint struct {
     Key       string `json:"key"`        // e.g., "Hardware.TEE_Support"
     Required  bool   `json:"required"`   // e.g., true
     MinVersion string `json:"min_version,omitempty"` // For versioned constraints
+    Values    []string `json:"values,omitempty"`     // Allowed values for set-membership constraints, e.g., ["SGX", "TDX"]
+    Min       string   `json:"min,omitempty"`        // Inclusive numeric lower bound, e.g., "0.5" or "200ms"
+    Max       string   `json:"max,omitempty"`        // Inclusive numeric upper bound
+
+    // A constraint without a key is a group combining its members; exactly one of these is set.
+    AllOf     []PolicyConstraint `json:"all_of,omitempty"`     // Every member must be satisfied
+    AnyOf     []PolicyConstraint `json:"any_of,omitempty"`     // At least one member, or Threshold, must be satisfied
+    NotAnyOf  []PolicyConstraint `json:"not_any_of,omitempty"` // No member may be satisfied
+    Weight    float64            `json:"weight,omitempty"`     // Weight of the constraint in an any_of group's Threshold (default 1)
+    Threshold float64            `json:"threshold,omitempty"`  // With any_of: total weight of satisfied members required
 }
 
 // IsolationPolicy defines a specific security posture level (e.g., L5, L3).
@@ -14,25 +24,101 @@ type IsolationPolicy struct {
     ID          string             `json:"id"`
     Description string             `json:"description"`
     Constraints []PolicyConstraint `json:"constraints"`
+    Resources   ResourceRequirements `json:"resources,omitempty"` // Node capacity the policy's workload consumes
+}
+
+// ResourceRequirements is the node capacity a policy's workload reserves. Unlike constraints they
+// add up: policies admitted one at a time may not fit together (see EvaluateSet).
+type ResourceRequirements struct {
+    MinMemoryKB  uint64 `json:"min_memory_kb,omitempty"`
+    Accelerators int    `json:"accelerators,omitempty"` // Whole accelerators, e.g. GPUs
 }
 
 // --- System Context Definitions ---
 
 // HardwareContext captures physical device capabilities required for admission.
 type HardwareContext struct {
-    TEE_Support      bool   `json:"tee_support"`      // Trusted Execution Environment
+    TEE              TEEInfo `json:"tee"`             // Trusted Execution Environment
     SR_IOV_Enabled   bool   `json:"sr_iov_enabled"`   // Single Root I/O Virtualization
     CPUArchitecture  string `json:"cpu_architecture"` 
+    MemoryBytes      uint64 `json:"memory_bytes,omitempty"` // Total physical memory
+    IOMMU            IOMMUInfo   `json:"iommu"`
+    PCIDevices       []PCIDevice `json:"pci_devices,omitempty"`
+    Accelerators     []Accelerator `json:"accelerators,omitempty"`
+}
+
+// Accelerator partitioning modes.
+const (
+    PartitioningNone  = ""
+    PartitioningMIG   = "mig"    // NVIDIA Multi-Instance GPU
+    PartitioningSRIOV = "sr-iov" // Virtual functions exposed through SR-IOV
+)
+
+// Accelerator is a GPU or dedicated accelerator discovered on the PCIe bus.
+type Accelerator struct {
+    Address             string `json:"address"`
+    Kind                string `json:"kind"`   // "gpu" or "accelerator"
+    Vendor              string `json:"vendor"` // e.g., "nvidia", "amd", "intel"
+    DeviceID            string `json:"device_id"`
+    MemoryBytes         uint64 `json:"memory_bytes,omitempty"` // Zero when the driver does not expose it
+    Partitioning        string `json:"partitioning,omitempty"` // One of the Partitioning constants
+    ConfidentialCompute bool   `json:"confidential_compute"`
+}
+
+// TEE technologies recognized by the context collector and the Hardware.TEE.Technology constraint.
+const (
+    TEETechnologySGX       = "SGX"
+    TEETechnologySEVSNP    = "SEV-SNP"
+    TEETechnologyTDX       = "TDX"
+    TEETechnologyTrustZone = "TrustZone"
+)
+
+// TEEInfo describes the Trusted Execution Environment available on the platform.
+type TEEInfo struct {
+    Supported   bool   `json:"supported"`
+    Technology  string `json:"technology,omitempty"`  // One of the TEETechnology constants
+    Version     string `json:"version,omitempty"`     // Technology-specific version, e.g., "2" for SGX2
+    Attestation bool   `json:"attestation"`           // Whether remote attestation evidence can be produced
+}
+
+// IOMMUInfo describes DMA remapping support, which device isolation depends on.
+type IOMMUInfo struct {
+    Enabled bool `json:"enabled"`
+    Groups  int  `json:"groups"` // Number of IOMMU groups exposed by the kernel
 }
 
-// OSContext captures operating system environment details (stubbed for future expansion).
+// PCIDevice is a PCIe function discovered on the host.
+type PCIDevice struct {
+    Address       string `json:"address"`               // e.g., "0000:3b:00.0"
+    Class         string `json:"class"`                 // PCI class code, e.g., "0x020000"
+    ClassName     string `json:"class_name"`            // e.g., "network"
+    Vendor        string `json:"vendor"`                // e.g., "0x8086"
+    Device        string `json:"device"`
+    IOMMUGroup    string `json:"iommu_group,omitempty"`
+    SRIOVTotalVFs int    `json:"sriov_total_vfs,omitempty"` // Zero when the device has no SR-IOV capability
+    SRIOVNumVFs   int    `json:"sriov_num_vfs,omitempty"`   // Currently enabled virtual functions
+}
+
+// Kernel lockdown modes, from least to most restrictive.
+const (
+    LockdownNone            = "none"
+    LockdownIntegrity       = "integrity"
+    LockdownConfidentiality = "confidentiality"
+)
+
+// OSContext captures operating system environment details, including kernel hardening state.
 type OSContext struct {
-    KernelVersion string `json:"kernel_version"`
+    KernelVersion string            `json:"kernel_version"`
+    Lockdown      string            `json:"lockdown"`       // One of the Lockdown constants; "" if the LSM is absent
+    SecureBoot    bool              `json:"secure_boot"`
+    SELinux       string            `json:"selinux"`        // "enforcing", "permissive", or "disabled"
+    AppArmor      bool              `json:"apparmor"`       // AppArmor LSM enabled
+    KernelCmdline map[string]string `json:"kernel_cmdline,omitempty"` // Boot parameters; bare flags map to ""
 }
 
 // SystemContext aggregates the current operational state of the target platform, checked against policies.
 type SystemContext struct {
     Hardware HardwareContext `json:"hardware"`
     OS       OSContext       `json:"os"`
-    CPESConfiguration map[string]interface{} `json:"cpes_configuration"` // Configuration and Environment State
+    CPESConfiguration CPESConfig `json:"cpes_configuration"` // Configuration and Environment State
 }
\ No newline at end of file
@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"core/governance"
+	"internal/admission"
+	"internal/authz"
+	"pkg/correlation"
+)
+
+// Audited admission actions.
+const (
+	ActionAdmitWorkload   = "admit_workload"
+	ActionReleaseWorkload = "release_workload"
+	ActionIssueToken      = "issue_admission_token"
+)
+
+// HandleAdmission serves the workload tracking endpoints for warmer. Operators may POST to
+// /admin/v1/admission/workloads to admit a workload, which tracks it so it is flagged if context
+// drift later revokes its policy, and to /admin/v1/admission/release to stop tracking one once it
+// has terminated. If the warmer mints admission tokens, operators may also POST to
+// /admin/v1/admission/tokens to obtain one for an admitted policy; tokens are never minted for the
+// read-only API.
+func (s *Server) HandleAdmission(warmer *admission.Warmer) {
+	s.Handle("/admin/v1/admission/workloads", ActionAdmitWorkload, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		s.handleAdmitWorkload(w, r, principal, warmer)
+	})
+	s.Handle("/admin/v1/admission/release", ActionReleaseWorkload, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		s.handleReleaseWorkload(w, r, principal, warmer)
+	})
+	s.Handle("/admin/v1/admission/tokens", ActionIssueToken, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		s.handleIssueToken(w, r, principal, warmer)
+	})
+}
+
+func (s *Server) handleAdmitWorkload(w http.ResponseWriter, r *http.Request, principal string, warmer *admission.Warmer) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	d, err := warmer.Admit(r.Context(), req.WorkloadID, req.PolicyID)
+	switch {
+	case errors.Is(err, admission.ErrInvalidWorkload):
+		writeError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, governance.ErrPolicyNotFound):
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	// A denial or a full tracking set is audited as refused, with the workload in the detail.
+	detail := fmt.Sprintf("workload=%s policy=%s", req.WorkloadID, req.PolicyID)
+	switch {
+	case err != nil:
+		err = fmt.Errorf("%s: %w", detail, err)
+	case !d.Admitted:
+		err = fmt.Errorf("%s: %s", detail, d.Reason)
+	}
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionAdmitWorkload, Reason: req.Reason, Detail: detail, CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		// An unaudited workload is not tracked.
+		if d.Admitted && err == nil {
+			warmer.Release(req.WorkloadID)
+		}
+		return
+	}
+	if errors.Is(err, admission.ErrWorkloadLimit) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (s *Server) handleReleaseWorkload(w http.ResponseWriter, r *http.Request, principal string, warmer *admission.Warmer) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	if req.WorkloadID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("workload_id is required"))
+		return
+	}
+	var err error
+	if !warmer.Release(req.WorkloadID) {
+		err = fmt.Errorf("workload '%s' is not tracked", req.WorkloadID)
+	}
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionReleaseWorkload, Reason: req.Reason,
+		Detail: "workload=" + req.WorkloadID, CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"tracked": warmer.Tracked()})
+}
+
+func (s *Server) handleIssueToken(w http.ResponseWriter, r *http.Request, principal string, warmer *admission.Warmer) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	d, err := warmer.Issue(r.Context(), req.PolicyID)
+	if errors.Is(err, governance.ErrPolicyNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	failed := err != nil // Tokens are not configured, or minting failed
+	if !failed && !d.Admitted {
+		err = fmt.Errorf("policy %s denies admission: %s", req.PolicyID, d.Reason)
+	}
+	// The token itself is a bearer credential and is not written to the audit log.
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionIssueToken, Reason: req.Reason,
+		Detail: "policy=" + req.PolicyID, CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		return
+	}
+	if failed {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
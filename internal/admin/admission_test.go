@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"core/governance"
+	"internal/admission"
+)
+
+// newTestWarmer returns a refreshed warmer admitting policy "open" and denying policy "secure".
+func newTestWarmer(t *testing.T, cfg admission.WarmerConfig) *admission.Warmer {
+	t.Helper()
+	cfg.ManifestPath = filepath.Join(t.TempDir(), "manifest.json")
+	manifest := `{"schema_version": "V2.0-POLI-STRUCT", "policies": [
+		{"id": "open", "constraints": []},
+		{"id": "secure", "constraints": [{"key": "OS.SecureBoot", "required": true}]}
+	]}`
+	if err := os.WriteFile(cfg.ManifestPath, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Collect = func(context.Context) (governance.SystemContext, error) { return governance.SystemContext{}, nil }
+	w, err := admission.NewWarmer(cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestServer_AdmitWorkload(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	warmer := newTestWarmer(t, admission.WarmerConfig{MaxWorkloads: 1})
+	s.HandleAdmission(warmer)
+
+	tests := []struct {
+		name    string
+		token   string
+		body    string
+		code    int
+		outcome string // Outcome of the audit record; "-" if none is written
+		tracked int
+	}{
+		{name: "Viewer Denied", token: "viewer", body: `{"reason": "deploy", "workload_id": "ns/a", "policy_id": "open"}`, code: http.StatusForbidden, outcome: OutcomeDenied},
+		{name: "Reason Required", token: "operator", body: `{"workload_id": "ns/a", "policy_id": "open"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Invalid Workload", token: "operator", body: `{"reason": "deploy", "workload_id": "ns a", "policy_id": "open"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Unknown Policy", token: "operator", body: `{"reason": "deploy", "workload_id": "ns/a", "policy_id": "missing"}`, code: http.StatusNotFound, outcome: "-"},
+		{name: "Admitted", token: "operator", body: `{"reason": "deploy", "workload_id": "ns/a", "policy_id": "open"}`, code: http.StatusOK, tracked: 1},
+		{name: "Policy Denies", token: "operator", body: `{"reason": "deploy", "workload_id": "ns/b", "policy_id": "secure"}`, code: http.StatusOK, outcome: OutcomeRefused, tracked: 1},
+		{name: "Limit Reached", token: "operator", body: `{"reason": "deploy", "workload_id": "ns/b", "policy_id": "open"}`, code: http.StatusConflict, outcome: OutcomeRefused, tracked: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(s.audit.List())
+			rec := call(s, http.MethodPost, "/admin/v1/admission/workloads", tt.token, tt.body)
+			if rec.Code != tt.code {
+				t.Fatalf("admit = %d %s, want %d", rec.Code, rec.Body, tt.code)
+			}
+			records := s.audit.List()[before:]
+			switch {
+			case tt.outcome == "-" && len(records) != 0:
+				t.Errorf("audit = %+v, want nothing recorded", records)
+			case tt.outcome != "-" && (len(records) != 1 || records[0].Outcome != tt.outcome):
+				t.Errorf("audit = %+v, want one record with outcome %q", records, tt.outcome)
+			}
+			if got := warmer.Tracked(); got != tt.tracked {
+				t.Errorf("Tracked() = %d, want %d", got, tt.tracked)
+			}
+		})
+	}
+
+	records := s.audit.List()
+	if last := records[len(records)-1]; !strings.Contains(last.Detail, "workload=ns/b policy=open") {
+		t.Errorf("refused admission detail = %q, want the workload and policy", last.Detail)
+	}
+}
+
+func TestServer_ReleaseWorkload(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	warmer := newTestWarmer(t, admission.WarmerConfig{})
+	s.HandleAdmission(warmer)
+	if _, err := warmer.Admit(context.Background(), "ns/a", "open"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := call(s, http.MethodPost, "/admin/v1/admission/release", "operator", `{"reason": "terminated", "workload_id": "ns/a"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("release = %d %s", rec.Code, rec.Body)
+	}
+	var resp map[string]int
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp["tracked"] != 0 {
+		t.Errorf("release response = %v, %v, want 0 tracked", resp, err)
+	}
+	if rec := call(s, http.MethodPost, "/admin/v1/admission/release", "operator", `{"reason": "terminated", "workload_id": "ns/a"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("second release = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	records := s.audit.List()
+	if len(records) != 2 || records[0].Action != ActionReleaseWorkload || records[0].Detail != "workload=ns/a" || records[1].Outcome != OutcomeRefused {
+		t.Errorf("audit = %+v, want a release followed by a refused one", records)
+	}
+}
+
+func TestServer_AdmitWorkloadUnaudited(t *testing.T) {
+	s, _ := newTestServer(t, NewAuditLog(failingWriter{}))
+	warmer := newTestWarmer(t, admission.WarmerConfig{})
+	s.HandleAdmission(warmer)
+
+	rec := call(s, http.MethodPost, "/admin/v1/admission/workloads", "operator", `{"reason": "deploy", "workload_id": "ns/a", "policy_id": "open"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("admit with a failing audit log = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := warmer.Tracked(); got != 0 {
+		t.Errorf("Tracked() = %d, want the unaudited workload released", got)
+	}
+}
+
+func TestServer_IssueToken(t *testing.T) {
+	issuer, err := governance.NewTokenIssuer([]byte(strings.Repeat("k", 32)), "sts", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := newTestServer(t, nil)
+	warmer := newTestWarmer(t, admission.WarmerConfig{Tokens: issuer})
+	s.HandleAdmission(warmer)
+
+	// The read-only lookup never carries a token.
+	if d, _ := warmer.Decide(context.Background(), "open"); !d.Admitted || d.Token != "" {
+		t.Errorf("Decide() = %+v, want admitted without a token", d)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		body      string
+		code      int
+		wantToken bool
+		outcome   string // Outcome of the audit record; "-" if none is written
+	}{
+		{name: "Viewer Denied", token: "viewer", body: `{"reason": "deploy", "policy_id": "open"}`, code: http.StatusForbidden, outcome: OutcomeDenied},
+		{name: "Unknown Policy", token: "operator", body: `{"reason": "deploy", "policy_id": "missing"}`, code: http.StatusNotFound, outcome: "-"},
+		{name: "Admitted", token: "operator", body: `{"reason": "deploy", "policy_id": "open"}`, code: http.StatusOK, wantToken: true},
+		{name: "Denied Without Token", token: "operator", body: `{"reason": "deploy", "policy_id": "secure"}`, code: http.StatusOK, outcome: OutcomeRefused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(s.audit.List())
+			rec := call(s, http.MethodPost, "/admin/v1/admission/tokens", tt.token, tt.body)
+			if rec.Code != tt.code {
+				t.Fatalf("issue = %d %s, want %d", rec.Code, rec.Body, tt.code)
+			}
+			var d admission.Decision
+			json.NewDecoder(rec.Body).Decode(&d)
+			if (d.Token != "") != tt.wantToken {
+				t.Errorf("token = %q, want one %v", d.Token, tt.wantToken)
+			}
+			records := s.audit.List()[before:]
+			switch {
+			case tt.outcome == "-" && len(records) != 0:
+				t.Errorf("audit = %+v, want nothing recorded", records)
+			case tt.outcome != "-" && (len(records) != 1 || records[0].Outcome != tt.outcome):
+				t.Errorf("audit = %+v, want one record with outcome %q", records, tt.outcome)
+			case len(records) == 1 && d.Token != "" && strings.Contains(records[0].Detail, d.Token):
+				t.Error("the audit record contains the token")
+			}
+		})
+	}
+}
+
+func TestServer_IssueTokenNotConfigured(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	s.HandleAdmission(newTestWarmer(t, admission.WarmerConfig{}))
+	if rec := call(s, http.MethodPost, "/admin/v1/admission/tokens", "operator", `{"reason": "deploy", "policy_id": "open"}`); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("issue without an issuer = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,179 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"internal/authz"
+	"internal/events"
+)
+
+// Audited administrative actions.
+const (
+	ActionAcknowledge   = "acknowledge"
+	ActionResetBreaches = "reset_breaches"
+	ActionPolicyReload  = "policy_reload"
+	ActionThresholds    = "update_thresholds"
+	ActionLogLevel      = "set_log_level"
+	ActionDebugProfile  = "debug_profile"
+)
+
+// DefaultAuditCapacity is the number of records an AuditLog keeps in memory unless WithCapacity
+// says otherwise.
+const DefaultAuditCapacity = 1000
+
+// SystemPrincipal attributes records for actions the service performed on its own.
+const SystemPrincipal = "system"
+
+// GovernancePrincipal attributes records for changes pushed by the governance policy document.
+const GovernancePrincipal = "governance"
+
+// OutcomeDenied marks a record for an action that was refused by the authorization policy, or by
+// the admission policy for threshold changes.
+const OutcomeDenied = "denied"
+
+// OutcomeRefused marks a record for an authorized action the service could not perform, e.g. a
+// reset held back by a lifecycle guard or an acknowledgement with no active breach.
+const OutcomeRefused = "refused"
+
+// AuditRecord captures who performed an administrative action and why.
+type AuditRecord struct {
+	// Seq numbers the records of this process from 1, in the order they were recorded.
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`
+	Role      string    `json:"role,omitempty"`
+	Action    string    `json:"action"`
+	Outcome   string    `json:"outcome,omitempty"` // Empty when the action was performed
+	Reason    string    `json:"reason"`
+	Detail    string    `json:"detail,omitempty"`
+	// CorrelationID links the record to the API request and log lines that produced it.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// AuditLog is an append-only record of administrative actions.
+// The most recent records are kept in a bounded in-memory buffer and, if a writer is configured,
+// every record is appended to it as JSONL, so older ones remain available there once evicted.
+type AuditLog struct {
+	records []AuditRecord // ring buffer; once full, head is the oldest record
+	head    int
+	seq     uint64
+	out     io.Writer
+	mu      sync.Mutex
+}
+
+// AuditOption customizes an AuditLog.
+type AuditOption func(*AuditLog)
+
+// WithCapacity keeps the last n records in memory instead of DefaultAuditCapacity. Non-positive
+// values are ignored.
+func WithCapacity(n int) AuditOption {
+	return func(a *AuditLog) {
+		if n > 0 {
+			a.records = make([]AuditRecord, 0, n)
+		}
+	}
+}
+
+// NewAuditLog creates an audit log. out may be nil for in-memory only.
+func NewAuditLog(out io.Writer, opts ...AuditOption) *AuditLog {
+	a := &AuditLog{out: out, records: make([]AuditRecord, 0, DefaultAuditCapacity)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Record appends an audit record, evicting the oldest in-memory record once the buffer is full.
+// Persisting to the writer happens before the record is acknowledged, so an error means the record
+// is not durable.
+func (a *AuditLog) Record(rec AuditRecord) error {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	rec.Seq = a.seq + 1
+	if a.out != nil {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("audit: failed to encode record: %w", err)
+		}
+		if _, err := a.out.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("audit: failed to persist record: %w", err)
+		}
+	}
+	a.seq = rec.Seq
+	if len(a.records) < cap(a.records) {
+		a.records = append(a.records, rec)
+		return nil
+	}
+	a.records[a.head] = rec
+	a.head = (a.head + 1) % len(a.records)
+	return nil
+}
+
+// List returns a copy of the records held in memory, ordered from oldest to newest.
+func (a *AuditLog) List() []AuditRecord {
+	return a.Page(0, 0).Records
+}
+
+// AuditPage is a page of audit records, ordered from oldest to newest.
+type AuditPage struct {
+	Records []AuditRecord `json:"records"`
+	// Next is the Seq to pass as after to fetch the following page; zero on the last page.
+	Next uint64 `json:"next,omitempty"`
+}
+
+// Page returns up to limit in-memory records recorded after the one numbered after, oldest first.
+// A non-positive limit returns every such record. Records evicted from memory are skipped.
+func (a *AuditLog) Page(after uint64, limit int) AuditPage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := len(a.records)
+	oldest := a.seq - uint64(n) + 1
+	start := 0
+	if after >= oldest {
+		start = int(min(after-oldest+1, uint64(n)))
+	}
+	end := n
+	if limit > 0 && start+limit < n {
+		end = start + limit
+	}
+	page := AuditPage{Records: make([]AuditRecord, 0, end-start)}
+	for i := start; i < end; i++ {
+		page.Records = append(page.Records, a.records[(a.head+i)%n])
+	}
+	if end < n {
+		page.Next = page.Records[len(page.Records)-1].Seq
+	}
+	return page
+}
+
+// AuthzAudit adapts the log to authz.Policy.Audit, recording every privileged decision. It is
+// meant for surfaces that do not audit calls themselves, such as gRPC services; the admin Server
+// and control socket already do. Persistence failures cannot be reported and are dropped.
+func (a *AuditLog) AuthzAudit() authz.AuditFunc {
+	return func(ev authz.Event) {
+		outcome := "allowed"
+		if !ev.Allowed {
+			outcome = OutcomeDenied
+		}
+		a.Record(AuditRecord{Time: ev.Time, Principal: ev.Principal, Role: ev.Role.String(), Action: ev.Action, Outcome: outcome})
+	}
+}
+
+// RecordPolicyReloads audits every PolicyReloaded event on bus, including the bundle version,
+// until the returned function is called. Persistence failures cannot be reported and are dropped.
+func (a *AuditLog) RecordPolicyReloads(bus *events.Bus) (unsubscribe func()) {
+	return events.Subscribe(bus, events.PolicyReloaded, func(ev events.PolicyReloadedEvent) {
+		detail := fmt.Sprintf("source=%s policies=%d", ev.ManifestPath, ev.Policies)
+		if ev.BundleVersion != "" {
+			detail += " bundle_version=" + ev.BundleVersion
+		}
+		a.Record(AuditRecord{Time: ev.At, Principal: SystemPrincipal, Action: ActionPolicyReload, Detail: detail})
+	})
+}
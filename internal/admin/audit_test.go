@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func seqs(records []AuditRecord) []uint64 {
+	out := make([]uint64, len(records))
+	for i, rec := range records {
+		out[i] = rec.Seq
+	}
+	return out
+}
+
+func TestAuditLog_Capacity(t *testing.T) {
+	var out bytes.Buffer
+	audit := NewAuditLog(&out, WithCapacity(3))
+	for i := 0; i < 5; i++ {
+		if err := audit.Record(AuditRecord{Principal: "alice", Action: ActionResetBreaches}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the last three records are kept in memory; the writer has every one.
+	if got, want := seqs(audit.List()), []uint64{3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 5 {
+		t.Errorf("writer has %d records, want 5", lines)
+	}
+}
+
+func TestAuditLog_Page(t *testing.T) {
+	audit := NewAuditLog(nil, WithCapacity(4))
+	if page := audit.Page(0, 2); len(page.Records) != 0 || page.Next != 0 {
+		t.Errorf("Page() of an empty log = %+v, want no records", page)
+	}
+	for i := 0; i < 6; i++ {
+		audit.Record(AuditRecord{Principal: "alice", Action: ActionResetBreaches})
+	}
+
+	tests := []struct {
+		name     string
+		after    uint64
+		limit    int
+		want     []uint64
+		wantNext uint64
+	}{
+		{name: "First Page", limit: 2, want: []uint64{3, 4}, wantNext: 4},
+		{name: "Last Page", after: 4, limit: 2, want: []uint64{5, 6}},
+		{name: "Unlimited", want: []uint64{3, 4, 5, 6}},
+		{name: "Evicted Cursor", after: 1, limit: 3, want: []uint64{3, 4, 5}, wantNext: 5},
+		{name: "Caught Up", after: 6, limit: 2, want: []uint64{}},
+		{name: "Future Cursor", after: 10, limit: 2, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := audit.Page(tt.after, tt.limit)
+			if got := seqs(page.Records); !reflect.DeepEqual(got, tt.want) || page.Next != tt.wantNext {
+				t.Errorf("Page(%d, %d) = %v next %d, want %v next %d", tt.after, tt.limit, got, page.Next, tt.want, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/authz"
+	"pkg/correlation"
+)
+
+const (
+	defaultCPUProfile    = 30 * time.Second
+	defaultMaxCPUProfile = 2 * time.Minute
+)
+
+// DebugOptions selects the debug endpoints registered by HandleDebug.
+type DebugOptions struct {
+	Pprof        bool // CPU profiles and named runtime profiles (heap, goroutine, ...)
+	RuntimeStats bool // Goroutine, memory and GC counters
+	// MaxCPUProfile bounds the duration of a CPU profile (default 2m).
+	MaxCPUProfile time.Duration
+}
+
+// RuntimeStats is a snapshot of the Go runtime, like the one reported by gops.
+type RuntimeStats struct {
+	GoVersion    string        `json:"go_version"`
+	Goroutines   int           `json:"goroutines"`
+	GOMAXPROCS   int           `json:"gomaxprocs"`
+	NumCPU       int           `json:"num_cpu"`
+	HeapAlloc    uint64        `json:"heap_alloc_bytes"`
+	HeapInuse    uint64        `json:"heap_inuse_bytes"`
+	HeapObjects  uint64        `json:"heap_objects"`
+	Sys          uint64        `json:"sys_bytes"`
+	NumGC        uint32        `json:"num_gc"`
+	GCPauseTotal time.Duration `json:"gc_pause_total_ns"`
+	LastGC       time.Time     `json:"last_gc"`
+}
+
+// HandleDebug serves the debug endpoints enabled in opts, so a running daemon can be profiled
+// without redeploying it with extra tooling:
+//
+//   - /admin/v1/debug/pprof/profile?seconds=N writes a CPU profile (admins only, audited)
+//   - /admin/v1/debug/pprof/<name>, e.g. heap or goroutine, writes a named runtime profile;
+//     debug=1 or 2 selects text output (admins only, audited)
+//   - /admin/v1/debug/runtime reports RuntimeStats (operators)
+//
+// Profiles are written with runtime/pprof rather than net/http/pprof, which would also register
+// them on http.DefaultServeMux without authentication.
+func (s *Server) HandleDebug(opts DebugOptions) {
+	if opts.MaxCPUProfile <= 0 {
+		opts.MaxCPUProfile = defaultMaxCPUProfile
+	}
+	if opts.Pprof {
+		s.Handle("/admin/v1/debug/pprof/", ActionDebugProfile, authz.RoleAdmin, func(w http.ResponseWriter, r *http.Request, principal string) {
+			s.handleProfile(w, r, principal, opts.MaxCPUProfile)
+		})
+	}
+	if opts.RuntimeStats {
+		s.Handle("/admin/v1/debug/runtime", "read_runtime_stats", authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+			if r.Method != http.MethodGet {
+				writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+				return
+			}
+			writeJSON(w, http.StatusOK, readRuntimeStats())
+		})
+	}
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request, principal string, maxCPU time.Duration) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/admin/v1/debug/pprof/")
+	if name == "" {
+		names := []string{"profile"}
+		for _, p := range pprof.Profiles() {
+			names = append(names, p.Name())
+		}
+		writeJSON(w, http.StatusOK, map[string][]string{"profiles": names})
+		return
+	}
+
+	var (
+		detail string
+		write  func() error
+	)
+	if name == "profile" {
+		duration := defaultCPUProfile
+		if v := r.URL.Query().Get("seconds"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				writeError(w, http.StatusBadRequest, errors.New("seconds must be a positive integer"))
+				return
+			}
+			duration = time.Duration(n) * time.Second
+		}
+		if duration > maxCPU {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("seconds must not exceed %d", int(maxCPU.Seconds())))
+			return
+		}
+		detail = "profile=cpu duration=" + duration.String()
+		write = func() error {
+			if err := pprof.StartCPUProfile(w); err != nil {
+				return err // A profile is already running
+			}
+			select {
+			case <-time.After(duration):
+			case <-r.Context().Done():
+			}
+			pprof.StopCPUProfile()
+			return nil
+		}
+	} else {
+		profile := pprof.Lookup(name)
+		if profile == nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("unknown profile %q", name))
+			return
+		}
+		debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+		detail = "profile=" + name
+		write = func() error {
+			if name == "heap" && r.URL.Query().Get("gc") != "" {
+				runtime.GC()
+			}
+			return profile.WriteTo(w, debug)
+		}
+	}
+
+	// Profiling costs CPU and goroutine dumps may expose internals, so every profile is audited.
+	if err := s.audit.Record(AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionDebugProfile, Detail: detail, CorrelationID: correlation.FromContext(r.Context())}); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if debug := r.URL.Query().Get("debug"); debug != "" && debug != "0" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.pb.gz"`)
+	}
+	if err := write(); err != nil {
+		w.Header().Del("Content-Disposition")
+		writeError(w, http.StatusConflict, err)
+	}
+}
+
+func readRuntimeStats() RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	stats := RuntimeStats{
+		GoVersion:    runtime.Version(),
+		Goroutines:   runtime.NumGoroutine(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		NumCPU:       runtime.NumCPU(),
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		GCPauseTotal: time.Duration(m.PauseTotalNs),
+	}
+	if m.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(m.LastGC)).UTC()
+	}
+	return stats
+}
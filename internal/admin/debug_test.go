@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_DebugProfileRoles(t *testing.T) {
+	audit := NewAuditLog(nil)
+	s, _ := newTestServer(t, audit)
+	s.HandleDebug(DebugOptions{Pprof: true, RuntimeStats: true})
+
+	for _, token := range []string{"viewer", "operator"} {
+		if rec := call(s, http.MethodGet, "/admin/v1/debug/pprof/goroutine", token, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("profile by %s = %d, want %d", token, rec.Code, http.StatusForbidden)
+		}
+	}
+	records := audit.List()
+	if len(records) != 2 || records[0].Principal != "victor" || records[1].Principal != "olive" || records[1].Outcome != OutcomeDenied || records[1].Action != ActionDebugProfile {
+		t.Errorf("audit = %+v, want both refusals recorded as denied", records)
+	}
+
+	// Runtime stats are open to operators; only the refusal is audited.
+	rec := call(s, http.MethodGet, "/admin/v1/debug/runtime", "operator", "")
+	var stats RuntimeStats
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &stats) != nil || stats.Goroutines == 0 {
+		t.Errorf("runtime stats = %d %s", rec.Code, rec.Body)
+	}
+	if rec := call(s, http.MethodGet, "/admin/v1/debug/runtime", "viewer", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("runtime stats by a viewer = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if records := audit.List(); len(records) != 3 || records[2].Principal != "victor" || records[2].Outcome != OutcomeDenied {
+		t.Errorf("audit = %+v, want the viewer's refusal added", records)
+	}
+}
+
+func TestServer_DebugProfile(t *testing.T) {
+	audit := NewAuditLog(nil)
+	s, _ := newTestServer(t, audit)
+	s.HandleDebug(DebugOptions{Pprof: true, MaxCPUProfile: 10 * time.Second})
+
+	rec := call(s, http.MethodGet, "/admin/v1/debug/pprof/goroutine?debug=1", "admin", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "goroutine profile:") {
+		t.Fatalf("goroutine profile = %d %.100s", rec.Code, rec.Body)
+	}
+	records := audit.List()
+	if len(records) != 1 || records[0].Principal != "alice" || records[0].Action != ActionDebugProfile || records[0].Detail != "profile=goroutine" {
+		t.Errorf("audit = %+v, want the goroutine profile by alice", records)
+	}
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/admin/v1/debug/pprof/profile?seconds=11", http.StatusBadRequest},
+		{"/admin/v1/debug/pprof/profile?seconds=0", http.StatusBadRequest},
+		{"/admin/v1/debug/pprof/profile?seconds=ten", http.StatusBadRequest},
+		{"/admin/v1/debug/pprof/nonexistent", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := call(s, http.MethodGet, tt.path, "admin", ""); rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+	}
+	if n := len(audit.List()); n != 1 {
+		t.Errorf("audit has %d records after refused requests, want 1", n)
+	}
+
+	rec = call(s, http.MethodGet, "/admin/v1/debug/pprof/", "admin", "")
+	var index map[string][]string
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &index) != nil || len(index["profiles"]) < 2 || index["profiles"][0] != "profile" {
+		t.Errorf("profile index = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestServer_DebugProfileAuditFailure(t *testing.T) {
+	s, _ := newTestServer(t, NewAuditLog(failingWriter{}))
+	s.HandleDebug(DebugOptions{Pprof: true})
+
+	rec := call(s, http.MethodGet, "/admin/v1/debug/pprof/heap", "admin", "")
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("heap profile without an audit = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Errorf("heap profile written although it was not audited")
+	}
+}
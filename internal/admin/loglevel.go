@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"internal/authz"
+	"pkg/correlation"
+	"pkg/system"
+)
+
+// defaultLogLevelTTL bounds how long a log level change lasts when none is requested.
+const defaultLogLevelTTL = 15 * time.Minute
+
+// LogLevelsResponse reports the configured log levels and the runtime overrides in effect.
+type LogLevelsResponse struct {
+	Level           system.Level            `json:"level"`
+	ComponentLevels map[string]system.Level `json:"component_levels,omitempty"`
+	Overrides       []system.LevelOverride  `json:"overrides"`
+}
+
+// HandleLogLevels serves /admin/v1/log-level for levels. GET lists the levels; POST changes the
+// level of a component, or the global level, until the ttl (default 15m) elapses and it reverts
+// on its own. Operators may do both.
+func (s *Server) HandleLogLevels(levels *system.Levels) {
+	s.Handle("/admin/v1/log-level", ActionLogLevel, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		if r.Method == http.MethodGet {
+			writeJSON(w, http.StatusOK, newLogLevelsResponse(levels))
+			return
+		}
+		s.handleLogLevel(w, r, principal, levels)
+	})
+}
+
+func newLogLevelsResponse(levels *system.Levels) LogLevelsResponse {
+	global, components := levels.Configured()
+	return LogLevelsResponse{Level: global, ComponentLevels: components, Overrides: levels.Overrides()}
+}
+
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request, principal string, levels *system.Levels) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	component := strings.TrimSpace(req.Component)
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionLogLevel, Reason: req.Reason, CorrelationID: correlation.FromContext(r.Context())}
+	target := "component=" + component
+	if component == "" {
+		target = "global"
+	}
+
+	if req.Level == "" {
+		rec.Detail = target + " reset"
+		if err := s.audit.Record(rec); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		levels.Reset(component)
+		writeJSON(w, http.StatusOK, newLogLevelsResponse(levels))
+		return
+	}
+
+	level, err := system.ParseLevel(req.Level)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ttl := defaultLogLevelTTL
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("ttl must be a positive duration"))
+			return
+		}
+		ttl = parsed
+	}
+	rec.Detail = target + " level=" + level.String() + " ttl=" + ttl.String()
+	if err := s.audit.Record(rec); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	levels.Set(component, level, ttl)
+	writeJSON(w, http.StatusOK, newLogLevelsResponse(levels))
+}
@@ -0,0 +1,287 @@
+// Package admin provides the authenticated HTTP API operators use to intervene in a running STS:
+// acknowledging a GATM violation to pause escalation, resetting the breach counter, adjusting
+// GATM thresholds, raising log levels during an incident, silencing violations during maintenance,
+// tracking admitted workloads for context drift, and, if enabled, profiling the daemon.
+// Every action is written to an audit log recording who performed it and why, and every call refused
+// by the role-based authorization policy is recorded as denied.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/authz"
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+const (
+	// defaultAckTTL bounds how long an acknowledgement pauses escalation when none is requested.
+	defaultAckTTL = 30 * time.Minute
+	// defaultAuditPage and maxAuditPage bound the records returned by one audit log read.
+	defaultAuditPage = 100
+	maxAuditPage     = 1000
+)
+
+// ErrUnauthenticated is returned by an Authenticator when the request carries no valid credentials.
+var ErrUnauthenticated = errors.New("admin: unauthenticated")
+
+// Authenticator resolves the principal behind an HTTP request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (principal string, err error)
+}
+
+// StaticTokenAuthenticator authenticates bearer tokens against a fixed token → principal table.
+type StaticTokenAuthenticator struct {
+	Tokens map[string]string
+}
+
+// Authenticate implements Authenticator using constant-time token comparison.
+func (a *StaticTokenAuthenticator) Authenticate(r *http.Request) (string, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return "", ErrUnauthenticated
+	}
+	for candidate, principal := range a.Tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return principal, nil
+		}
+	}
+	return "", ErrUnauthenticated
+}
+
+// MTLSAuthenticator identifies callers by the common name of their verified client certificate.
+// The server's TLS configuration must require and verify client certificates.
+type MTLSAuthenticator struct{}
+
+// Authenticate implements Authenticator.
+func (MTLSAuthenticator) Authenticate(r *http.Request) (string, error) {
+	principal, err := authz.CertificatePrincipal(r.TLS)
+	if err != nil {
+		return "", ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+// actionRequest is the body accepted by the acknowledge and reset endpoints.
+type actionRequest struct {
+	Reason string `json:"reason"`
+	// TTL applies to acknowledgements, log level changes and silences, e.g. "45m".
+	TTL string `json:"ttl,omitempty"`
+	// Component and Level apply to log level changes only. An empty Component changes the global
+	// level; an empty Level reverts to the configured one.
+	Component string `json:"component,omitempty"`
+	Level     string `json:"level,omitempty"`
+	// The threshold fields apply to threshold updates only; omitted fields are left unchanged.
+	LatencyThreshold  string  `json:"latency_threshold,omitempty"` // e.g. "250ms"
+	LoadThreshold     float64 `json:"load_threshold,omitempty"`
+	MaxBreaches       int     `json:"max_breaches,omitempty"`
+	BreachDecayFactor float64 `json:"breach_decay_factor,omitempty"`
+	// WorkloadID and PolicyID apply to admission tracking only.
+	WorkloadID string `json:"workload_id,omitempty"`
+	PolicyID   string `json:"policy_id,omitempty"`
+	// The silence fields apply to maintenance silences only. A silence lasts from StartsAt, or now,
+	// until EndsAt or for TTL.
+	SilenceID string            `json:"silence_id,omitempty"`
+	Matchers  map[string]string `json:"matchers,omitempty"` // e.g. {"cause": "load"}
+	StartsAt  *time.Time        `json:"starts_at,omitempty"`
+	EndsAt    *time.Time        `json:"ends_at,omitempty"`
+}
+
+// Server exposes the admin API over HTTP.
+type Server struct {
+	sts     telemetry.STS
+	auth    Authenticator
+	policy  *authz.Policy
+	audit   *AuditLog
+	mux     *http.ServeMux
+	handler http.Handler
+}
+
+// NewServer creates the admin API handler. Viewers may read the audit log, operators may
+// acknowledge violations, and admins may reset the breach counter and change thresholds. A nil
+// policy grants every authenticated principal the viewer role only, so it fails closed. A nil audit
+// log keeps records in memory only.
+func NewServer(sts telemetry.STS, auth Authenticator, policy *authz.Policy, audit *AuditLog) *Server {
+	if policy == nil {
+		policy = &authz.Policy{Default: authz.RoleViewer}
+	}
+	if audit == nil {
+		audit = NewAuditLog(nil)
+	}
+	s := &Server{sts: sts, auth: auth, policy: policy, audit: audit, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/admin/v1/acknowledge", s.authorized(ActionAcknowledge, authz.RoleOperator, s.handleAcknowledge))
+	s.mux.HandleFunc("/admin/v1/reset", s.authorized(ActionResetBreaches, authz.RoleAdmin, s.handleReset))
+	s.mux.HandleFunc("/admin/v1/thresholds", s.authorized(ActionThresholds, authz.RoleAdmin, s.handleThresholds))
+	s.mux.HandleFunc("/admin/v1/audit", s.authorized("read_audit", authz.RoleViewer, s.handleAudit))
+	s.handler = correlation.Middleware(nil, s.mux)
+	return s
+}
+
+// ServeHTTP implements http.Handler. Every request is assigned a correlation ID, which is copied
+// into its audit records.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// PrincipalHandlerFunc handles a request from an authenticated, authorized principal.
+type PrincipalHandlerFunc func(w http.ResponseWriter, r *http.Request, principal string)
+
+// Handle registers an endpoint provided by another subsystem, e.g. SIH approvals, behind the same
+// authentication and role check as the built-in ones. The handler audits the calls it performs.
+func (s *Server) Handle(path, action string, required authz.Role, h PrincipalHandlerFunc) {
+	s.mux.HandleFunc(path, s.authorized(action, required, h))
+}
+
+// authorized authenticates the caller and checks it holds the required role. Refused privileged
+// calls are audited; the handler audits the calls it performs.
+func (s *Server) authorized(action string, required authz.Role, next PrincipalHandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := s.auth.Authenticate(r)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		if err := s.policy.Authorize(principal, action, required); err != nil {
+			if required >= authz.RoleOperator {
+				s.audit.Record(AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: action, Outcome: OutcomeDenied, CorrelationID: correlation.FromContext(r.Context())})
+			}
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+		next(w, r, principal)
+	}
+}
+
+func (s *Server) handleAcknowledge(w http.ResponseWriter, r *http.Request, principal string) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	ttl := defaultAckTTL
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("ttl must be a positive duration"))
+			return
+		}
+		ttl = parsed
+	}
+
+	ack, err := s.sts.Acknowledge(principal, req.Reason, ttl)
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionAcknowledge, Reason: req.Reason, Detail: "ttl=" + ttl.String(), CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, telemetry.ErrNoActiveBreach):
+		writeError(w, http.StatusConflict, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ack)
+}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request, principal string) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	before := s.sts.GetHealthStatus().GATMBreachCount
+	err := s.sts.ResetBreachCount()
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionResetBreaches, Reason: req.Reason, CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"previous_breach_count": before})
+}
+
+// recordOutcome audits an action once it has been attempted: as performed if err is nil, otherwise
+// as refused with err in the detail. Neither an acknowledgement nor a reset can be undone, so if
+// the record cannot be written the caller is told the action took effect unaudited. It reports
+// whether the caller should go on to write its response.
+func (s *Server) recordOutcome(w http.ResponseWriter, rec AuditRecord, err error) bool {
+	if err != nil {
+		rec.Outcome, rec.Detail = OutcomeRefused, err.Error()
+	}
+	auditErr := s.audit.Record(rec)
+	switch {
+	case auditErr == nil:
+		return true
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, errors.Join(err, auditErr))
+	default:
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("%s performed but not audited: %w", rec.Action, auditErr))
+	}
+	return false
+}
+
+// handleAudit serves a page of the audit log. The optional after query parameter is the Seq of the
+// last record already read, e.g. the previous page's next; limit caps the page at up to 1000
+// records and defaults to 100.
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request, principal string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	query := r.URL.Query()
+	var after uint64
+	if v := query.Get("after"); v != "" {
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("after must be a record sequence number"))
+			return
+		}
+		after = parsed
+	}
+	limit := defaultAuditPage
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed <= 0 || parsed > maxAuditPage {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("limit must be between 1 and %d", maxAuditPage))
+			return
+		}
+		limit = parsed
+	}
+	writeJSON(w, http.StatusOK, s.audit.Page(after, limit))
+}
+
+// decodeAction validates the method and body shared by mutating endpoints. A reason is mandatory.
+func decodeAction(w http.ResponseWriter, r *http.Request) (actionRequest, bool) {
+	var req actionRequest
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid JSON body"))
+		return req, false
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("reason is required"))
+		return req, false
+	}
+	return req, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
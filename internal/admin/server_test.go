@@ -0,0 +1,222 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"internal/authz"
+	"services/telemetry"
+)
+
+// breachingSource reports a latency breach on every collection.
+type breachingSource struct{}
+
+func (breachingSource) Collect(ctx context.Context) (telemetry.TelemetryData, error) {
+	return telemetry.TelemetryData{Timestamp: time.Now(), PipelineLatencyS9: time.Hour, IntegrityHashChainStatus: "SYNCED"}, nil
+}
+
+// newTestServer returns a server over an STS with one recorded breach, authenticating the tokens
+// "admin", "operator" and "viewer" as principals bound to those roles.
+func newTestServer(t *testing.T, audit *AuditLog) (*Server, telemetry.STS) {
+	t.Helper()
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{})
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	auth := &StaticTokenAuthenticator{Tokens: map[string]string{"admin": "alice", "operator": "olive", "viewer": "victor"}}
+	policy := &authz.Policy{Bindings: map[string]authz.Role{"alice": authz.RoleAdmin, "olive": authz.RoleOperator, "victor": authz.RoleViewer}}
+	return NewServer(sts, auth, policy, audit), sts
+}
+
+func call(s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Acknowledge(t *testing.T) {
+	s, sts := newTestServer(t, nil)
+
+	if rec := call(s, http.MethodPost, "/admin/v1/acknowledge", "operator", `{"ttl": "10m"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("acknowledge without a reason = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec := call(s, http.MethodPost, "/admin/v1/acknowledge", "operator", `{"reason": "investigating", "ttl": "10m"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("acknowledge = %d %s", rec.Code, rec.Body)
+	}
+	if !sts.GetHealthStatus().IsAcknowledged {
+		t.Error("violation not acknowledged")
+	}
+	records := s.audit.List()
+	if len(records) != 1 || records[0].Principal != "olive" || records[0].Action != ActionAcknowledge || records[0].Detail != "ttl=10m0s" {
+		t.Errorf("audit = %+v, want one acknowledgement by olive", records)
+	}
+}
+
+func TestServer_AcknowledgeWithoutBreach(t *testing.T) {
+	s, sts := newTestServer(t, nil)
+	if err := sts.ResetBreachCount(); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := call(s, http.MethodPost, "/admin/v1/acknowledge", "operator", `{"reason": "investigating"}`); rec.Code != http.StatusConflict {
+		t.Errorf("acknowledge without a breach = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	records := s.audit.List()
+	if len(records) != 1 || records[0].Outcome != OutcomeRefused || records[0].Detail != telemetry.ErrNoActiveBreach.Error() {
+		t.Errorf("audit = %+v, want the acknowledgement recorded as refused", records)
+	}
+}
+
+func TestServer_Reset(t *testing.T) {
+	s, sts := newTestServer(t, NewAuditLog(nil))
+
+	rec := call(s, http.MethodPost, "/admin/v1/reset", "admin", `{"reason": "false positive"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("reset = %d %s", rec.Code, rec.Body)
+	}
+	var body map[string]int
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body["previous_breach_count"] != 1 {
+		t.Errorf("reset response = %v, %v; want previous_breach_count 1", body, err)
+	}
+	if n := sts.GetHealthStatus().GATMBreachCount; n != 0 {
+		t.Errorf("GATMBreachCount = %d after reset", n)
+	}
+	if records := s.audit.List(); len(records) != 1 || records[0].Action != ActionResetBreaches || records[0].Reason != "false positive" {
+		t.Errorf("audit = %+v, want the reset", records)
+	}
+}
+
+func TestServer_ResetRefusedByGuard(t *testing.T) {
+	lc := telemetry.NewLifecycle()
+	lc.Guard(func(ctx context.Context, tr telemetry.Transition) error {
+		if tr.From == telemetry.StateSIHActive {
+			return errors.New("held for operator release")
+		}
+		return nil
+	})
+	if err := lc.Fire(context.Background(), telemetry.StateSIHActive, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{Lifecycle: lc}, breachingSource{})
+	s := NewServer(sts, &StaticTokenAuthenticator{Tokens: map[string]string{"admin": "alice"}}, &authz.Policy{Default: authz.RoleAdmin}, nil)
+
+	if rec := call(s, http.MethodPost, "/admin/v1/reset", "admin", `{"reason": "false positive"}`); rec.Code != http.StatusConflict {
+		t.Errorf("reset held by a guard = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	records := s.audit.List()
+	if len(records) != 1 || records[0].Outcome != OutcomeRefused || !strings.Contains(records[0].Detail, "held for operator release") {
+		t.Errorf("audit = %+v, want the reset recorded as refused", records)
+	}
+}
+
+func TestServer_Audit(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	call(s, http.MethodPost, "/admin/v1/reset", "admin", `{"reason": "false positive"}`)
+
+	if rec := call(s, http.MethodGet, "/admin/v1/audit", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated audit read = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	rec := call(s, http.MethodGet, "/admin/v1/audit", "viewer", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("audit = %d %s", rec.Code, rec.Body)
+	}
+	var page AuditPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || len(page.Records) != 1 || page.Records[0].Principal != "alice" || page.Next != 0 {
+		t.Errorf("audit = %+v, %v; want the reset by alice", page, err)
+	}
+}
+
+func TestServer_AuditPages(t *testing.T) {
+	audit := NewAuditLog(nil)
+	for i := 0; i < 5; i++ {
+		audit.Record(AuditRecord{Principal: "alice", Action: ActionResetBreaches})
+	}
+	s, _ := newTestServer(t, audit)
+
+	var seqs []uint64
+	for after := uint64(0); ; {
+		rec := call(s, http.MethodGet, fmt.Sprintf("/admin/v1/audit?limit=2&after=%d", after), "viewer", "")
+		var page AuditPage
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("audit after %d = %d %+v, %v", after, rec.Code, page, err)
+		}
+		for _, r := range page.Records {
+			seqs = append(seqs, r.Seq)
+		}
+		if page.Next == 0 {
+			break
+		}
+		after = page.Next
+	}
+	if want := []uint64{1, 2, 3, 4, 5}; !reflect.DeepEqual(seqs, want) {
+		t.Errorf("paged audit = %v, want %v", seqs, want)
+	}
+
+	for _, query := range []string{"limit=0", "limit=1001", "limit=x", "after=-1"} {
+		if rec := call(s, http.MethodGet, "/admin/v1/audit?"+query, "viewer", ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("audit?%s = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestServer_Denied(t *testing.T) {
+	s, sts := newTestServer(t, nil)
+
+	rec := call(s, http.MethodPost, "/admin/v1/reset", "operator", `{"reason": "clear it"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("reset by an operator = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if n := sts.GetHealthStatus().GATMBreachCount; n != 1 {
+		t.Errorf("GATMBreachCount = %d after a denied reset", n)
+	}
+	records := s.audit.List()
+	if len(records) != 1 || records[0].Outcome != OutcomeDenied || records[0].Principal != "olive" || records[0].Role != "operator" {
+		t.Errorf("audit = %+v, want the denied reset", records)
+	}
+}
+
+// failingWriter rejects every write, like a full audit volume.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestServer_ThresholdsRolledBackWhenUnaudited(t *testing.T) {
+	s, sts := newTestServer(t, NewAuditLog(failingWriter{}))
+	before := sts.Thresholds()
+
+	rec := call(s, http.MethodPost, "/admin/v1/thresholds", "admin", `{"reason": "noisy", "max_breaches": 9}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("thresholds with a failing audit log = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if got := sts.Thresholds(); got != before {
+		t.Errorf("thresholds = %+v after an unaudited change, want %+v", got, before)
+	}
+}
+
+func TestServer_NilPolicyFailsClosed(t *testing.T) {
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{})
+	sts.CollectNow(context.Background())
+	s := NewServer(sts, &StaticTokenAuthenticator{Tokens: map[string]string{"tok": "anyone"}}, nil, nil)
+
+	if rec := call(s, http.MethodPost, "/admin/v1/reset", "tok", `{"reason": "x"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("reset without a policy = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := call(s, http.MethodGet, "/admin/v1/audit", "tok", ""); rec.Code != http.StatusOK {
+		t.Errorf("audit read without a policy = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
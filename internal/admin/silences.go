@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"internal/authz"
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+// Audited silence actions.
+const (
+	ActionAddSilence    = "add_silence"
+	ActionExpireSilence = "expire_silence"
+)
+
+// HandleSilences serves the maintenance silences held by silences. GET /admin/v1/silences lists
+// them; POST adds one covering the given matchers from starts_at (default now) until ends_at, or
+// for ttl, with the reason as its comment. POST /admin/v1/silences/expire removes one by ID, e.g.
+// when maintenance ends early. Operators may do all three.
+func (s *Server) HandleSilences(silences *telemetry.SilenceRegistry) {
+	s.Handle("/admin/v1/silences", ActionAddSilence, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		if r.Method == http.MethodGet {
+			writeJSON(w, http.StatusOK, silences.List())
+			return
+		}
+		s.handleAddSilence(w, r, principal, silences)
+	})
+	s.Handle("/admin/v1/silences/expire", ActionExpireSilence, authz.RoleOperator, func(w http.ResponseWriter, r *http.Request, principal string) {
+		s.handleExpireSilence(w, r, principal, silences)
+	})
+}
+
+func (s *Server) handleAddSilence(w http.ResponseWriter, r *http.Request, principal string, silences *telemetry.SilenceRegistry) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	silence, err := newSilence(req, principal, time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	id, err := silences.Add(silence)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	silence.ID = id
+
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionAddSilence, Reason: req.Reason,
+		Detail: fmt.Sprintf("silence=%s matchers={%s} starts_at=%s ends_at=%s", id, formatMatchers(silence.Matchers),
+			silence.StartsAt.UTC().Format(time.RFC3339), silence.EndsAt.UTC().Format(time.RFC3339)),
+		CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, nil) {
+		// An unaudited silence must not suppress violations.
+		silences.Expire(id)
+		return
+	}
+	writeJSON(w, http.StatusOK, silence)
+}
+
+func (s *Server) handleExpireSilence(w http.ResponseWriter, r *http.Request, principal string, silences *telemetry.SilenceRegistry) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	if req.SilenceID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("silence_id is required"))
+		return
+	}
+	var err error
+	if !silences.Expire(req.SilenceID) {
+		err = fmt.Errorf("silence '%s' does not exist", req.SilenceID)
+	}
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Action: ActionExpireSilence, Reason: req.Reason,
+		Detail: "silence=" + req.SilenceID, CorrelationID: correlation.FromContext(r.Context())}
+	if !s.recordOutcome(w, rec, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, silences.List())
+}
+
+// newSilence builds the silence requested by req, validating its window.
+func newSilence(req actionRequest, principal string, now time.Time) (telemetry.Silence, error) {
+	silence := telemetry.Silence{Source: telemetry.SilenceSourceAPI, StartsAt: now, CreatedBy: principal, Comment: req.Reason}
+	for name, value := range req.Matchers {
+		if strings.TrimSpace(name) == "" {
+			return telemetry.Silence{}, errors.New("matcher names must not be empty")
+		}
+		silence.Matchers = append(silence.Matchers, telemetry.Matcher{Name: name, Value: value})
+	}
+	sort.Slice(silence.Matchers, func(i, j int) bool { return silence.Matchers[i].Name < silence.Matchers[j].Name })
+	if req.StartsAt != nil {
+		silence.StartsAt = *req.StartsAt
+	}
+
+	switch {
+	case req.EndsAt != nil && req.TTL != "":
+		return telemetry.Silence{}, errors.New("set ends_at or ttl, not both")
+	case req.EndsAt != nil:
+		silence.EndsAt = *req.EndsAt
+	case req.TTL != "":
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return telemetry.Silence{}, errors.New("ttl must be a positive duration")
+		}
+		silence.EndsAt = silence.StartsAt.Add(ttl)
+	default:
+		return telemetry.Silence{}, errors.New("ends_at or ttl is required")
+	}
+	if !silence.EndsAt.After(now) {
+		return telemetry.Silence{}, errors.New("silence must end in the future")
+	}
+	return silence, nil
+}
+
+// formatMatchers renders matchers as name=value pairs for audit records.
+func formatMatchers(matchers []telemetry.Matcher) string {
+	pairs := make([]string, len(matchers))
+	for i, m := range matchers {
+		pairs[i] = m.Name + "=" + m.Value
+	}
+	return strings.Join(pairs, ",")
+}
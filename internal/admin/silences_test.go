@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"services/telemetry"
+)
+
+func TestServer_AddSilence(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	silences := telemetry.NewSilenceRegistry()
+	s.HandleSilences(silences)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		token   string
+		body    string
+		code    int
+		outcome string // Outcome of the audit record; "-" if none is written
+	}{
+		{name: "Viewer Denied", token: "viewer", body: `{"reason": "upgrade", "ttl": "1h"}`, code: http.StatusForbidden, outcome: OutcomeDenied},
+		{name: "Reason Required", token: "operator", body: `{"ttl": "1h"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "End Required", token: "operator", body: `{"reason": "upgrade"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "End And TTL", token: "operator", body: `{"reason": "upgrade", "ttl": "1h", "ends_at": "2099-01-01T00:00:00Z"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Invalid TTL", token: "operator", body: `{"reason": "upgrade", "ttl": "-1h"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Already Ended", token: "operator", body: `{"reason": "upgrade", "ends_at": "` + past + `"}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Empty Matcher Name", token: "operator", body: `{"reason": "upgrade", "ttl": "1h", "matchers": {"": "load"}}`, code: http.StatusBadRequest, outcome: "-"},
+		{name: "Added", token: "operator", body: `{"reason": "upgrade", "ttl": "1h", "matchers": {"cause": "load", "zone": "a"}}`, code: http.StatusOK, outcome: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(s.audit.List())
+			rec := call(s, http.MethodPost, "/admin/v1/silences", tt.token, tt.body)
+			if rec.Code != tt.code {
+				t.Fatalf("add silence = %d %s, want %d", rec.Code, rec.Body, tt.code)
+			}
+			records := s.audit.List()[before:]
+			switch {
+			case tt.outcome == "-" && len(records) != 0:
+				t.Errorf("audit = %+v, want nothing recorded", records)
+			case tt.outcome != "-" && (len(records) != 1 || records[0].Outcome != tt.outcome):
+				t.Errorf("audit = %+v, want one record with outcome %q", records, tt.outcome)
+			}
+		})
+	}
+
+	list := silences.List()
+	if len(list) != 1 {
+		t.Fatalf("silences = %+v, want the added one", list)
+	}
+	got := list[0]
+	if got.Source != telemetry.SilenceSourceAPI || got.CreatedBy != "olive" || got.Comment != "upgrade" || got.EndsAt.Sub(got.StartsAt) != time.Hour {
+		t.Errorf("silence = %+v, want an hour-long API silence by olive", got)
+	}
+	if !silences.Silenced([]string{telemetry.CauseLoad}, map[string]string{"zone": "a"}, time.Now()) {
+		t.Error("Silenced() = false for a matching violation")
+	}
+	records := s.audit.List()
+	if detail := records[len(records)-1].Detail; !strings.Contains(detail, "silence="+got.ID) || !strings.Contains(detail, "matchers={cause=load,zone=a}") {
+		t.Errorf("audit detail = %q, want the silence ID and matchers", detail)
+	}
+
+	rec := call(s, http.MethodGet, "/admin/v1/silences", "operator", "")
+	var listed []telemetry.Silence
+	if err := json.NewDecoder(rec.Body).Decode(&listed); err != nil || len(listed) != 1 || listed[0].ID != got.ID {
+		t.Errorf("list silences = %d %+v, %v; want the added one", rec.Code, listed, err)
+	}
+}
+
+func TestServer_ExpireSilence(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	silences := telemetry.NewSilenceRegistry()
+	s.HandleSilences(silences)
+	id, err := silences.Add(telemetry.Silence{StartsAt: time.Now(), EndsAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := call(s, http.MethodPost, "/admin/v1/silences/expire", "operator", `{"reason": "done early"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expire without an ID = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	body := `{"reason": "done early", "silence_id": "` + id + `"}`
+	if rec := call(s, http.MethodPost, "/admin/v1/silences/expire", "operator", body); rec.Code != http.StatusOK {
+		t.Fatalf("expire = %d %s", rec.Code, rec.Body)
+	}
+	if list := silences.List(); len(list) != 0 {
+		t.Errorf("silences after expiry = %+v, want none", list)
+	}
+	if rec := call(s, http.MethodPost, "/admin/v1/silences/expire", "operator", body); rec.Code != http.StatusNotFound {
+		t.Errorf("second expire = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	records := s.audit.List()
+	if len(records) != 2 || records[0].Action != ActionExpireSilence || records[0].Detail != "silence="+id || records[1].Outcome != OutcomeRefused {
+		t.Errorf("audit = %+v, want an expiry followed by a refused one", records)
+	}
+}
+
+func TestServer_AddSilenceUnaudited(t *testing.T) {
+	s, _ := newTestServer(t, NewAuditLog(failingWriter{}))
+	silences := telemetry.NewSilenceRegistry()
+	s.HandleSilences(silences)
+
+	rec := call(s, http.MethodPost, "/admin/v1/silences", "operator", `{"reason": "upgrade", "ttl": "1h"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("add silence with a failing audit log = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if list := silences.List(); len(list) != 0 {
+		t.Errorf("silences = %+v, want the unaudited one removed", list)
+	}
+}
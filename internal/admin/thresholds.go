@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+// ThresholdsResponse reports the GATM thresholds in effect.
+type ThresholdsResponse struct {
+	LatencyThreshold  string  `json:"latency_threshold"`
+	LoadThreshold     float64 `json:"load_threshold"`
+	MaxBreaches       int     `json:"max_breaches"`
+	BreachDecayFactor float64 `json:"breach_decay_factor"`
+}
+
+func newThresholdsResponse(t telemetry.Thresholds) ThresholdsResponse {
+	return ThresholdsResponse{
+		LatencyThreshold:  t.LatencyThreshold.String(),
+		LoadThreshold:     t.LoadThreshold,
+		MaxBreaches:       t.MaxBreaches,
+		BreachDecayFactor: t.BreachDecayFactor,
+	}
+}
+
+// UpdateThresholds applies a runtime threshold change through sts and audits the outcome: a change
+// refused by validation or by the STS threshold admission policy is recorded as denied, with the
+// reason in the detail. rec supplies the principal, role, and reason; the rest is filled in. Use it
+// for every source of threshold changes, e.g. with GovernancePrincipal for governance pushes, so
+// they share one audit trail. A change that cannot be audited is rolled back.
+func UpdateThresholds(ctx context.Context, sts telemetry.STS, audit *AuditLog, rec AuditRecord, update telemetry.Thresholds) (telemetry.Thresholds, error) {
+	previous := sts.Thresholds()
+	applied, err := sts.UpdateThresholds(ctx, rec.Principal, update)
+	rec.Action = ActionThresholds
+	rec.CorrelationID = correlation.FromContext(ctx)
+	if err != nil {
+		rec.Outcome, rec.Detail = OutcomeDenied, err.Error()
+	} else {
+		rec.Detail = fmt.Sprintf("latency_threshold=%s load_threshold=%g max_breaches=%d breach_decay_factor=%g",
+			applied.LatencyThreshold, applied.LoadThreshold, applied.MaxBreaches, applied.BreachDecayFactor)
+	}
+	if auditErr := audit.Record(rec); auditErr != nil {
+		if err != nil {
+			return applied, errors.Join(err, auditErr)
+		}
+		if _, err := sts.UpdateThresholds(ctx, SystemPrincipal, previous); err != nil {
+			return applied, errors.Join(auditErr, fmt.Errorf("failed to roll back unaudited thresholds: %w", err))
+		}
+		return previous, auditErr
+	}
+	return applied, err
+}
+
+func (s *Server) handleThresholds(w http.ResponseWriter, r *http.Request, principal string) {
+	req, ok := decodeAction(w, r)
+	if !ok {
+		return
+	}
+	update := telemetry.Thresholds{LoadThreshold: req.LoadThreshold, MaxBreaches: req.MaxBreaches, BreachDecayFactor: req.BreachDecayFactor}
+	if req.LatencyThreshold != "" {
+		parsed, err := time.ParseDuration(req.LatencyThreshold)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("latency_threshold must be a positive duration"))
+			return
+		}
+		update.LatencyThreshold = parsed
+	}
+
+	rec := AuditRecord{Principal: principal, Role: s.policy.RoleOf(principal).String(), Reason: req.Reason}
+	applied, err := UpdateThresholds(r.Context(), s.sts, s.audit, rec, update)
+	switch {
+	case errors.Is(err, telemetry.ErrInvalidThresholds):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, telemetry.ErrThresholdsRejected):
+		writeError(w, http.StatusForbidden, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, newThresholdsResponse(applied))
+	}
+}
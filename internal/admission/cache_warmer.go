@@ -0,0 +1,392 @@
+// Package admission serves admission decisions for the local node from a precomputed capability matrix.
+package admission
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"core/governance"
+	"internal/events"
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+const defaultWarmInterval = 30 * time.Second
+
+// Logger defines the interface required for internal component logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// Decision is the precomputed outcome of one policy against the node's context.
+type Decision struct {
+	PolicyID string `json:"policy_id"`
+	Admitted bool   `json:"admitted"`
+	Reason   string `json:"reason,omitempty"` // Why admission was refused
+	// Constraint is the key of the constraint that refused admission, if one did.
+	Constraint  string    `json:"constraint,omitempty"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	// Input is the SystemContext the decision was computed from, as canonical JSON, when
+	// WarmerConfig.RecordInputs is set; ReplayDecision reproduces the decision from it.
+	Input json.RawMessage `json:"input,omitempty"`
+	// Token is a signed, time-limited admission token for an admitted decision, minted by Issue.
+	Token string `json:"token,omitempty"`
+}
+
+// CapabilityMatrix holds a decision for every policy in the manifest.
+type CapabilityMatrix struct {
+	Decisions  map[string]Decision `json:"decisions"`
+	ComputedAt time.Time           `json:"computed_at"`
+	// BundleVersion identifies the policy bundle the decisions were computed from, if one is used.
+	BundleVersion string `json:"bundle_version,omitempty"`
+}
+
+// WarmerConfig configures the cache warmer.
+type WarmerConfig struct {
+	// ManifestPath is a single manifest file. Exactly one of ManifestPath and BundleDir must be set.
+	ManifestPath string
+	// BundleDir is a policy bundle directory (see governance.BundleIndexFile). Checksums are verified
+	// on every reload; a bundle that fails verification is not applied.
+	BundleDir string
+	// Collect returns the node's current SystemContext, e.g., context_probe.Collector.Collect.
+	Collect func(ctx context.Context) (governance.SystemContext, error)
+	// Interval between change checks (default 30s).
+	Interval time.Duration
+	// OnReload, if set, configures each newly loaded engine (custom evaluators, metrics).
+	OnReload func(engine *governance.PolicyAdmissionEngine)
+	// Bus, if set, receives PolicyReloaded, AdmissionDenied and ContextDrift events.
+	Bus *events.Bus
+	// RecordInputs stores the complete SystemContext with every decision, so admission outcomes
+	// can be reproduced byte for byte in audits.
+	RecordInputs bool
+	// Tokens, if set, lets Issue mint admission tokens for admitted decisions, so workloads can
+	// present them to downstream components. Decide never mints them.
+	Tokens *governance.TokenIssuer
+	// MaxWorkloads caps the workloads Admit tracks for drift (default 10000). Admissions beyond it
+	// fail with ErrWorkloadLimit until workloads are released.
+	MaxWorkloads int
+	// TransientConstraints are constraint keys backed by live telemetry, e.g. CPES keys fed from
+	// STS health. Denials by these constraints, or by a suspension, can be queued with Enqueue.
+	TransientConstraints []string
+}
+
+// Warmer keeps the capability matrix current by re-evaluating every policy whenever the manifest
+// file or the collected SystemContext changes. When the context drifts, workloads admitted through
+// Admit whose policy no longer admits the node are flagged.
+type Warmer struct {
+	cfg WarmerConfig
+	log Logger
+
+	mu          sync.RWMutex
+	engine      *governance.PolicyAdmissionEngine
+	matrix      CapabilityMatrix
+	manifestSum [sha256.Size]byte
+	contextSum  [sha256.Size]byte
+	context     json.RawMessage // Encoded context the matrix was computed from
+	suspended   string          // Non-empty while admissions are suspended; the reason given
+
+	workloads map[string]string          // Workloads admitted through Admit, by ID; their policy ID
+	flagged   map[string]FlaggedWorkload // Admitted workloads that no longer qualify, by ID
+	drift     *ContextDrift              // Most recent context drift
+
+	queue []*queuedAdmission // Admissions waiting for a transient denial to clear
+	wake  chan struct{}      // Signals Run to refresh early, e.g. after STS recovered
+}
+
+// NewWarmer creates a warmer. Call Refresh or Run to populate the matrix.
+func NewWarmer(cfg WarmerConfig, logger Logger) (*Warmer, error) {
+	if (cfg.ManifestPath == "") == (cfg.BundleDir == "") || cfg.Collect == nil {
+		return nil, errors.New("cache warmer requires Collect and exactly one of ManifestPath and BundleDir")
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultWarmInterval
+	}
+	if cfg.MaxWorkloads == 0 {
+		cfg.MaxWorkloads = defaultMaxWorkloads
+	}
+	return &Warmer{cfg: cfg, log: logger, wake: make(chan struct{}, 1)}, nil
+}
+
+// Refresh reloads the manifest and re-collects the context, recomputing the matrix if either changed.
+func (w *Warmer) Refresh(ctx context.Context) error {
+	manifestSum, err := w.manifestDigest()
+	if err != nil {
+		return err
+	}
+	sc, err := w.cfg.Collect(ctx)
+	if err != nil {
+		return fmt.Errorf("system context collection failed: %w", err)
+	}
+	encoded, err := json.Marshal(sc)
+	if err != nil {
+		return fmt.Errorf("failed to encode system context: %w", err)
+	}
+	contextSum := sha256.Sum256(encoded)
+
+	w.mu.RLock()
+	engine := w.engine
+	manifestChanged := engine == nil || manifestSum != w.manifestSum
+	contextChanged := contextSum != w.contextSum
+	w.mu.RUnlock()
+	if !manifestChanged && !contextChanged {
+		return nil
+	}
+
+	if manifestChanged {
+		if engine, err = w.load(); err != nil {
+			// Keep serving the previous matrix rather than failing every request.
+			return err
+		}
+		if w.cfg.OnReload != nil {
+			w.cfg.OnReload(engine)
+		}
+		if w.cfg.Bus != nil {
+			events.Publish(w.cfg.Bus, events.PolicyReloaded, events.PolicyReloadedEvent{
+				ManifestPath: engine.ManifestPath, BundleVersion: engine.BundleVersion, Policies: len(engine.Policies), At: time.Now(),
+			})
+		}
+	}
+
+	var input json.RawMessage
+	if w.cfg.RecordInputs {
+		input = encoded
+	}
+	matrix := evaluateAll(engine, sc, input)
+	matrix.BundleVersion = engine.BundleVersion
+
+	w.mu.Lock()
+	prevMatrix, prevContext := w.matrix, w.context
+	w.engine, w.matrix = engine, matrix
+	w.manifestSum, w.contextSum, w.context = manifestSum, contextSum, encoded
+	var drift ContextDrift
+	var driftErr error
+	drifted := contextChanged && prevContext != nil
+	if drifted {
+		drift, driftErr = w.detectDrift(prevContext, encoded, prevMatrix, matrix)
+	}
+	w.mu.Unlock()
+
+	if drifted {
+		if driftErr != nil {
+			return driftErr
+		}
+		w.publishDrift(drift)
+	}
+
+	if w.log != nil {
+		w.log.Infof("Capability matrix recomputed (manifest changed: %t, context changed: %t, policies: %d)",
+			manifestChanged, contextChanged, len(matrix.Decisions))
+	}
+	w.retryQueued()
+	return nil
+}
+
+// manifestDigest hashes the manifest, or the bundle index and every file it lists.
+func (w *Warmer) manifestDigest() ([sha256.Size]byte, error) {
+	if w.cfg.BundleDir != "" {
+		sum, err := governance.BundleDigest(os.DirFS(w.cfg.BundleDir))
+		if err != nil {
+			return sum, fmt.Errorf("policy bundle %s: %w", w.cfg.BundleDir, err)
+		}
+		return sum, nil
+	}
+	manifest, err := os.ReadFile(w.cfg.ManifestPath)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("failed to read isolation manifest at %s: %w", w.cfg.ManifestPath, err)
+	}
+	return sha256.Sum256(manifest), nil
+}
+
+func (w *Warmer) load() (*governance.PolicyAdmissionEngine, error) {
+	if w.cfg.BundleDir != "" {
+		return governance.NewPolicyAdmissionEngineFromBundleDir(w.cfg.BundleDir)
+	}
+	return governance.NewPolicyAdmissionEngine(w.cfg.ManifestPath)
+}
+
+// evaluateAll decides every policy against sc, attaching input (nil unless recording) to each decision.
+func evaluateAll(engine *governance.PolicyAdmissionEngine, sc governance.SystemContext, input json.RawMessage) CapabilityMatrix {
+	now := time.Now()
+	ids := make([]string, 0, len(engine.Policies))
+	for id := range engine.Policies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	matrix := CapabilityMatrix{Decisions: make(map[string]Decision, len(ids)), ComputedAt: now}
+	for _, id := range ids {
+		admitted, err := engine.EvaluateRequest(id, sc)
+		d := Decision{PolicyID: id, Admitted: admitted, EvaluatedAt: now, Input: input}
+		if err != nil {
+			d.Reason, d.Constraint = err.Error(), unsatisfiedKey(err)
+		}
+		matrix.Decisions[id] = d
+	}
+	return matrix
+}
+
+// unsatisfiedKey returns the key of the constraint that refused admission, or "" if err is not a
+// constraint denial.
+func unsatisfiedKey(err error) string {
+	var unsatisfied *governance.ErrConstraintUnsatisfied
+	if errors.As(err, &unsatisfied) {
+		return unsatisfied.Key
+	}
+	return ""
+}
+
+// ReplayDecision re-evaluates a recorded decision against its recorded input and reports whether
+// engine reproduces the same outcome and reason.
+func ReplayDecision(engine *governance.PolicyAdmissionEngine, d Decision) (reproduced Decision, ok bool, err error) {
+	if len(d.Input) == 0 {
+		return Decision{}, false, errors.New("decision has no recorded input")
+	}
+	var sc governance.SystemContext
+	if err := json.Unmarshal(d.Input, &sc); err != nil {
+		return Decision{}, false, fmt.Errorf("invalid recorded input: %w", err)
+	}
+	admitted, evalErr := engine.EvaluateRequest(d.PolicyID, sc)
+	reproduced = Decision{PolicyID: d.PolicyID, Admitted: admitted, EvaluatedAt: time.Now(), Input: d.Input}
+	if evalErr != nil {
+		reproduced.Reason, reproduced.Constraint = evalErr.Error(), unsatisfiedKey(evalErr)
+	}
+	return reproduced, reproduced.Admitted == d.Admitted && reproduced.Reason == d.Reason, nil
+}
+
+// Run refreshes immediately and then every Interval until ctx is cancelled. With a Bus, it also
+// refreshes as soon as STS returns to healthy or a recovery playbook succeeds, so queued admissions
+// (see Enqueue) are retried without waiting for the next tick.
+func (w *Warmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	if w.cfg.Bus != nil {
+		defer events.Subscribe(w.cfg.Bus, events.LifecycleChanged, func(ev events.LifecycleChangedEvent) {
+			if ev.Transition.To == telemetry.StateHealthy {
+				w.Wake()
+			}
+		})()
+		defer events.Subscribe(w.cfg.Bus, events.RecoveryCompleted, func(ev events.RecoveryCompletedEvent) {
+			if ev.Succeeded {
+				w.Wake()
+			}
+		})()
+	}
+	for {
+		if err := w.Refresh(ctx); err != nil && w.log != nil {
+			w.log.Errorf("Capability matrix refresh failed, serving previous decisions: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// Wake makes Run refresh the matrix and retry queued admissions now.
+func (w *Warmer) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Suspend denies every admission with reason until Resume, e.g. while the node is quarantined.
+// The matrix keeps being refreshed so decisions are current once admissions resume.
+func (w *Warmer) Suspend(reason string) {
+	if reason == "" {
+		reason = "admissions suspended"
+	}
+	w.mu.Lock()
+	w.suspended = reason
+	w.mu.Unlock()
+	if w.log != nil {
+		w.log.Warnf("Admissions suspended: %s", reason)
+	}
+}
+
+// Resume lifts a Suspend and retries queued admissions.
+func (w *Warmer) Resume() {
+	w.mu.Lock()
+	w.suspended = ""
+	w.mu.Unlock()
+	w.retryQueued()
+}
+
+// Decide returns the precomputed decision for policyID. ok is false if the policy is unknown or
+// the matrix has not been computed yet. While suspended, known policies are denied with the
+// suspension reason. Denials are published with the correlation ID from ctx.
+func (w *Warmer) Decide(ctx context.Context, policyID string) (d Decision, ok bool) {
+	w.mu.RLock()
+	d, ok = w.current(policyID)
+	w.mu.RUnlock()
+	if ok && !d.Admitted {
+		w.publishDenial(ctx, d)
+	}
+	return d, ok
+}
+
+// Issue is Decide that also mints an admission token with WarmerConfig.Tokens when the decision
+// admits. Callers must authenticate the requester first: the token vouches for the admission to
+// downstream components. A denied decision is returned without a token and without an error.
+func (w *Warmer) Issue(ctx context.Context, policyID string) (Decision, error) {
+	if w.cfg.Tokens == nil {
+		return Decision{}, errors.New("admission tokens are not configured")
+	}
+	w.mu.RLock()
+	d, ok := w.current(policyID)
+	contextSum, bundleVersion := w.contextSum, w.matrix.BundleVersion
+	w.mu.RUnlock()
+	if !ok {
+		return Decision{}, fmt.Errorf("%w: '%s'", governance.ErrPolicyNotFound, policyID)
+	}
+	if !d.Admitted {
+		w.publishDenial(ctx, d)
+		return d, nil
+	}
+	// The context hash matches governance.ContextHash of the context the matrix was computed from.
+	token, err := w.cfg.Tokens.Issue(ctx, policyID, hex.EncodeToString(contextSum[:]), bundleVersion)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to mint admission token for policy %s: %w", policyID, err)
+	}
+	d.Token = token
+	return d, nil
+}
+
+// publishDenial publishes a denied decision with the correlation ID from ctx.
+func (w *Warmer) publishDenial(ctx context.Context, d Decision) {
+	if w.cfg.Bus != nil {
+		events.Publish(w.cfg.Bus, events.AdmissionDenied, events.AdmissionDeniedEvent{
+			PolicyID: d.PolicyID, Reason: d.Reason, At: time.Now(), CorrelationID: correlation.FromContext(ctx),
+		})
+	}
+}
+
+// Engine returns the engine the current matrix was computed with, or nil before the first Refresh.
+func (w *Warmer) Engine() *governance.PolicyAdmissionEngine {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.engine
+}
+
+// Matrix returns a copy of the current capability matrix.
+func (w *Warmer) Matrix() CapabilityMatrix {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := CapabilityMatrix{Decisions: make(map[string]Decision, len(w.matrix.Decisions)), ComputedAt: w.matrix.ComputedAt, BundleVersion: w.matrix.BundleVersion}
+	for id, d := range w.matrix.Decisions {
+		out.Decisions[id] = d
+	}
+	return out
+}
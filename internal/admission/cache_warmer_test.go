@@ -0,0 +1,197 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"core/governance"
+)
+
+const testManifest = `{"schema_version": "V2.0-POLI-STRUCT", "policies": [
+	{"id": "secure", "constraints": [{"key": "OS.SecureBoot", "required": true}]},
+	{"id": "selinux", "constraints": [{"key": "OS.SELinux.Enforcing", "required": true}]}
+]}`
+
+// testNode is a SystemContext the test can change between refreshes.
+type testNode struct {
+	mu sync.Mutex
+	sc governance.SystemContext
+}
+
+func (n *testNode) set(secureBoot bool, selinux string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sc.OS.SecureBoot, n.sc.OS.SELinux = secureBoot, selinux
+}
+
+func (n *testNode) collect(context.Context) (governance.SystemContext, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sc, nil
+}
+
+// newTestWarmer returns a refreshed warmer over testManifest that treats OS.SecureBoot denials as
+// transient, and the path of its manifest.
+func newTestWarmer(t *testing.T, node *testNode) (*Warmer, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(testManifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWarmer(WarmerConfig{ManifestPath: path, Collect: node.collect, TransientConstraints: []string{"OS.SecureBoot"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return w, path
+}
+
+func TestWarmer_Refresh(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "permissive")
+	w, path := newTestWarmer(t, node)
+
+	matrix := w.Matrix()
+	if d := matrix.Decisions["secure"]; !d.Admitted {
+		t.Errorf("secure = %+v, want admitted", d)
+	}
+	if d := matrix.Decisions["selinux"]; d.Admitted || d.Constraint != "OS.SELinux.Enforcing" {
+		t.Errorf("selinux = %+v, want denied by OS.SELinux.Enforcing", d)
+	}
+	if _, ok := w.Decide(context.Background(), "missing"); ok {
+		t.Error("Decide(missing) ok, want unknown")
+	}
+
+	// A manifest that fails to load keeps the previous matrix.
+	if err := os.WriteFile(path, []byte(`{"schema_version": "V1"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Refresh(context.Background()); err == nil {
+		t.Error("Refresh with an invalid manifest succeeded")
+	}
+	if d, ok := w.Decide(context.Background(), "secure"); !ok || !d.Admitted {
+		t.Errorf("secure after a failed reload = %+v, %v; want the previous decision", d, ok)
+	}
+}
+
+func TestWarmer_SuspendResume(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "enforcing")
+	w, _ := newTestWarmer(t, node)
+
+	w.Suspend("node quarantined")
+	if d, ok := w.Decide(context.Background(), "secure"); !ok || d.Admitted || d.Reason != "node quarantined" {
+		t.Errorf("Decide while suspended = %+v, want denied with the suspension reason", d)
+	}
+	if d := w.Matrix().Decisions["secure"]; !d.Admitted {
+		t.Errorf("matrix while suspended = %+v, want the unsuspended decision", d)
+	}
+	w.Resume()
+	if d, _ := w.Decide(context.Background(), "secure"); !d.Admitted {
+		t.Errorf("Decide after Resume = %+v, want admitted", d)
+	}
+}
+
+func TestWarmer_Drift(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "permissive")
+	w, _ := newTestWarmer(t, node)
+	if d, _ := w.Admit(context.Background(), "wl-1", "secure"); !d.Admitted {
+		t.Fatalf("Admit = %+v, want admitted", d)
+	}
+	if _, ok := w.LastDrift(); ok {
+		t.Error("LastDrift before any change reported a drift")
+	}
+
+	node.set(false, "enforcing")
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	drift, ok := w.LastDrift()
+	if !ok {
+		t.Fatal("LastDrift reported no drift after the context changed")
+	}
+	if want := []string{"os.secure_boot", "os.selinux"}; !reflect.DeepEqual(drift.Changed, want) {
+		t.Errorf("Changed = %v, want %v", drift.Changed, want)
+	}
+	if !reflect.DeepEqual(drift.Revoked, []string{"secure"}) || !reflect.DeepEqual(drift.Restored, []string{"selinux"}) {
+		t.Errorf("drift = %+v, want secure revoked and selinux restored", drift)
+	}
+	flagged := w.Flagged()
+	if len(flagged) != 1 || flagged[0].WorkloadID != "wl-1" || flagged[0].PolicyID != "secure" {
+		t.Errorf("Flagged = %+v, want wl-1", flagged)
+	}
+
+	// Restoring the context clears the flag; a released workload is no longer tracked.
+	node.set(true, "enforcing")
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if flagged := w.Flagged(); len(flagged) != 0 {
+		t.Errorf("Flagged after restore = %+v, want none", flagged)
+	}
+	w.Release("wl-1")
+	node.set(false, "enforcing")
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if flagged := w.Flagged(); len(flagged) != 0 {
+		t.Errorf("Flagged after Release = %+v, want none", flagged)
+	}
+}
+
+func TestWarmer_Admit(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "permissive")
+	w, _ := newTestWarmer(t, node)
+	w.cfg.MaxWorkloads = 2
+
+	tests := []struct {
+		name     string
+		workload string
+		policy   string
+		wantErr  error
+		admitted bool
+		tracked  int // Tracked workloads afterwards
+	}{
+		{name: "Admitted", workload: "ns/wl-1", policy: "secure", admitted: true, tracked: 1},
+		{name: "Denied Is Not Tracked", workload: "ns/wl-2", policy: "selinux", tracked: 1},
+		{name: "Readmission Does Not Count", workload: "ns/wl-1", policy: "secure", admitted: true, tracked: 1},
+		{name: "Fills Limit", workload: "ns/wl-3", policy: "secure", admitted: true, tracked: 2},
+		{name: "Beyond Limit", workload: "ns/wl-4", policy: "secure", wantErr: ErrWorkloadLimit, tracked: 2},
+		{name: "Empty ID", policy: "secure", wantErr: ErrInvalidWorkload, tracked: 2},
+		{name: "Invalid Character", workload: "wl 5", policy: "secure", wantErr: ErrInvalidWorkload, tracked: 2},
+		{name: "Too Long", workload: strings.Repeat("w", maxWorkloadIDLength+1), policy: "secure", wantErr: ErrInvalidWorkload, tracked: 2},
+		{name: "Unknown Policy", workload: "ns/wl-5", policy: "missing", wantErr: governance.ErrPolicyNotFound, tracked: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := w.Admit(context.Background(), tt.workload, tt.policy)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Admit() error = %v, want %v", err, tt.wantErr)
+			}
+			if d.Admitted != tt.admitted {
+				t.Errorf("Admit() = %+v, want admitted %v", d, tt.admitted)
+			}
+			if got := w.Tracked(); got != tt.tracked {
+				t.Errorf("Tracked() = %d, want %d", got, tt.tracked)
+			}
+		})
+	}
+
+	// Releasing frees room for another workload.
+	if !w.Release("ns/wl-3") || w.Release("ns/wl-3") {
+		t.Error("Release() should report the workload as tracked exactly once")
+	}
+	if _, err := w.Admit(context.Background(), "ns/wl-4", "secure"); err != nil {
+		t.Errorf("Admit() after Release error = %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"core/governance"
+	"internal/events"
+)
+
+const (
+	defaultMaxWorkloads = 10000
+	maxWorkloadIDLength = 253
+)
+
+var (
+	// ErrInvalidWorkload is returned by Admit and Enqueue for a malformed workload ID.
+	ErrInvalidWorkload = errors.New("invalid workload id")
+	// ErrWorkloadLimit is returned by Admit when WarmerConfig.MaxWorkloads workloads are tracked already.
+	ErrWorkloadLimit = errors.New("tracked workload limit reached")
+)
+
+// FlaggedWorkload is an admitted workload whose policy no longer admits the node's context.
+type FlaggedWorkload struct {
+	WorkloadID string    `json:"workload_id"`
+	PolicyID   string    `json:"policy_id"`
+	Reason     string    `json:"reason"`
+	FlaggedAt  time.Time `json:"flagged_at"`
+}
+
+// ContextDrift describes a change of the node's SystemContext between two refreshes.
+type ContextDrift struct {
+	Changed  []string          `json:"changed"`            // Changed fields, e.g. "os.secure_boot", "hardware.memory_bytes"
+	Revoked  []string          `json:"revoked,omitempty"`  // Policies admitted before the drift and denied after it
+	Restored []string          `json:"restored,omitempty"` // Policies denied before the drift and admitted after it
+	Flagged  []FlaggedWorkload `json:"flagged,omitempty"`  // Tracked workloads of the revoked policies
+	At       time.Time         `json:"at"`
+}
+
+// Admit is Decide for a workload: when the decision admits it, the workload is tracked, so it is
+// flagged if drift of the node's context later revokes the policy, until it is released. Admit
+// fails with ErrInvalidWorkload for a malformed ID, ErrPolicyNotFound for an unknown policy, and
+// ErrWorkloadLimit if the workload would be tracked beyond WarmerConfig.MaxWorkloads.
+func (w *Warmer) Admit(ctx context.Context, workloadID, policyID string) (Decision, error) {
+	if err := validateWorkloadID(workloadID); err != nil {
+		return Decision{}, err
+	}
+	d, ok := w.Decide(ctx, policyID)
+	if !ok {
+		return Decision{}, fmt.Errorf("%w: '%s'", governance.ErrPolicyNotFound, policyID)
+	}
+	if !d.Admitted {
+		return d, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, tracked := w.workloads[workloadID]; !tracked && len(w.workloads) >= w.cfg.MaxWorkloads {
+		return Decision{}, fmt.Errorf("%w: %d workloads", ErrWorkloadLimit, len(w.workloads))
+	}
+	if w.workloads == nil {
+		w.workloads = make(map[string]string)
+	}
+	w.workloads[workloadID] = policyID
+	delete(w.flagged, workloadID)
+	return d, nil
+}
+
+// Release stops tracking a workload, e.g. once it has terminated or been evicted. It reports
+// whether the workload was tracked.
+func (w *Warmer) Release(workloadID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, tracked := w.workloads[workloadID]
+	delete(w.workloads, workloadID)
+	delete(w.flagged, workloadID)
+	return tracked
+}
+
+// Tracked returns the number of workloads tracked for drift.
+func (w *Warmer) Tracked() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.workloads)
+}
+
+// validateWorkloadID accepts IDs of up to 253 letters, digits, and ".", "-", "_", ":", "/", such as
+// Kubernetes "namespace/name" references.
+func validateWorkloadID(id string) error {
+	if id == "" || len(id) > maxWorkloadIDLength {
+		return fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidWorkload, maxWorkloadIDLength)
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.' || c == '-' || c == '_' || c == ':' || c == '/':
+		default:
+			return fmt.Errorf("%w: '%s' contains %q", ErrInvalidWorkload, id, c)
+		}
+	}
+	return nil
+}
+
+// Flagged lists the tracked workloads that no longer qualify for their policy, by workload ID.
+func (w *Warmer) Flagged() []FlaggedWorkload {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := make([]FlaggedWorkload, 0, len(w.flagged))
+	for _, f := range w.flagged {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].WorkloadID < out[j].WorkloadID })
+	return out
+}
+
+// LastDrift returns the most recent context drift, if any was detected.
+func (w *Warmer) LastDrift() (ContextDrift, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.drift == nil {
+		return ContextDrift{}, false
+	}
+	return *w.drift, true
+}
+
+// detectDrift compares the previous and the recomputed matrix after the context changed, flags
+// tracked workloads whose policy was revoked, and clears the flag of those it admits again. It is
+// called with w.mu held.
+func (w *Warmer) detectDrift(prevContext, current json.RawMessage, prev, next CapabilityMatrix) (ContextDrift, error) {
+	changed, err := changedFields(prevContext, current)
+	if err != nil {
+		return ContextDrift{}, err
+	}
+	drift := ContextDrift{Changed: changed, At: next.ComputedAt}
+	for id, d := range next.Decisions {
+		before, ok := prev.Decisions[id]
+		switch {
+		case !ok:
+		case before.Admitted && !d.Admitted:
+			drift.Revoked = append(drift.Revoked, id)
+		case !before.Admitted && d.Admitted:
+			drift.Restored = append(drift.Restored, id)
+		}
+	}
+	sort.Strings(drift.Revoked)
+	sort.Strings(drift.Restored)
+
+	for workload, policyID := range w.workloads {
+		d, ok := next.Decisions[policyID]
+		if ok && d.Admitted {
+			delete(w.flagged, workload)
+			continue
+		}
+		reason := "policy no longer in the manifest"
+		if ok {
+			reason = d.Reason
+		}
+		f := FlaggedWorkload{WorkloadID: workload, PolicyID: policyID, Reason: reason, FlaggedAt: drift.At}
+		if w.flagged == nil {
+			w.flagged = make(map[string]FlaggedWorkload)
+		}
+		if existing, ok := w.flagged[workload]; ok {
+			f.FlaggedAt = existing.FlaggedAt
+		}
+		w.flagged[workload] = f
+		drift.Flagged = append(drift.Flagged, f)
+	}
+	sort.Slice(drift.Flagged, func(i, j int) bool { return drift.Flagged[i].WorkloadID < drift.Flagged[j].WorkloadID })
+	w.drift = &drift
+	return drift, nil
+}
+
+// publishDrift logs the drift and publishes it on the bus.
+func (w *Warmer) publishDrift(drift ContextDrift) {
+	if w.log != nil {
+		if len(drift.Flagged) > 0 {
+			w.log.Warnf("System context drifted (%v): policies %v revoked, %d workloads no longer qualify", drift.Changed, drift.Revoked, len(drift.Flagged))
+		} else {
+			w.log.Infof("System context drifted (%v): revoked policies %v, restored %v", drift.Changed, drift.Revoked, drift.Restored)
+		}
+	}
+	if w.cfg.Bus == nil {
+		return
+	}
+	flagged := make([]string, len(drift.Flagged))
+	for i, f := range drift.Flagged {
+		flagged[i] = f.WorkloadID
+	}
+	events.Publish(w.cfg.Bus, events.ContextDrift, events.ContextDriftEvent{
+		Changed: drift.Changed, Revoked: drift.Revoked, Restored: drift.Restored, Flagged: flagged, At: drift.At,
+	})
+}
+
+// changedFields lists the JSON paths whose values differ between two encoded contexts.
+func changedFields(before, after json.RawMessage) ([]string, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(before, &a); err != nil {
+		return nil, fmt.Errorf("invalid previous system context: %w", err)
+	}
+	if err := json.Unmarshal(after, &b); err != nil {
+		return nil, fmt.Errorf("invalid system context: %w", err)
+	}
+	var changed []string
+	diffValues("", a, b, &changed)
+	sort.Strings(changed)
+	return changed, nil
+}
+
+func diffValues(path string, a, b interface{}, changed *[]string) {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if fmt.Sprint(a) != fmt.Sprint(b) {
+			*changed = append(*changed, path)
+		}
+		return
+	}
+	for key, av := range am {
+		diffValues(joinPath(path, key), av, bm[key], changed)
+	}
+	for key, bv := range bm {
+		if _, ok := am[key]; !ok {
+			diffValues(joinPath(path, key), nil, bv, changed)
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
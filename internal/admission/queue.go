@@ -0,0 +1,128 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+
+	"core/governance"
+)
+
+// queuedAdmission is an admission waiting for a transient denial to clear.
+type queuedAdmission struct {
+	ctx        context.Context
+	workloadID string
+	policyID   string
+	result     chan Decision
+	stop       func() bool // Stops the context.AfterFunc that dequeues the request
+}
+
+// Enqueue decides policyID for workloadID like Admit, but when the denial is only transient — the
+// node is suspended, or the refusing constraint is one of WarmerConfig.TransientConstraints — the
+// request waits, and is re-evaluated whenever the matrix is refreshed or admissions resume. The
+// returned channel receives exactly one decision: the admission once the denial clears, or the
+// denial as soon as it is no longer transient. It is closed without a decision if ctx ends first.
+// Run retries queued requests early when STS reports recovery.
+func (w *Warmer) Enqueue(ctx context.Context, workloadID, policyID string) (<-chan Decision, error) {
+	if err := validateWorkloadID(workloadID); err != nil {
+		return nil, err
+	}
+	q := &queuedAdmission{ctx: ctx, workloadID: workloadID, policyID: policyID, result: make(chan Decision, 1)}
+	w.mu.Lock()
+	d, ok := w.current(policyID)
+	if !ok {
+		w.mu.Unlock()
+		return nil, fmt.Errorf("%w: '%s'", governance.ErrPolicyNotFound, policyID)
+	}
+	if !d.Admitted && w.transient(d) {
+		w.queue = append(w.queue, q)
+		q.stop = context.AfterFunc(ctx, func() { w.dequeue(q) })
+		w.mu.Unlock()
+		if w.log != nil {
+			w.log.Infof("Admission of %s for policy %s queued until the node is healthy: %s", workloadID, policyID, d.Reason)
+		}
+		return q.result, nil
+	}
+	w.mu.Unlock()
+	w.settle(q)
+	return q.result, nil
+}
+
+// current returns the decision for policyID with any suspension applied. It is called with w.mu held.
+func (w *Warmer) current(policyID string) (Decision, bool) {
+	d, ok := w.matrix.Decisions[policyID]
+	if ok && w.suspended != "" {
+		d.Admitted, d.Reason, d.Constraint = false, w.suspended, ""
+	}
+	return d, ok
+}
+
+// transient reports whether a denial is expected to clear once STS recovers. It is called with
+// w.mu held.
+func (w *Warmer) transient(d Decision) bool {
+	if w.suspended != "" {
+		return true
+	}
+	for _, key := range w.cfg.TransientConstraints {
+		if d.Constraint == key {
+			return true
+		}
+	}
+	return false
+}
+
+// retryQueued settles every queued admission whose denial has cleared or is no longer transient.
+func (w *Warmer) retryQueued() {
+	w.mu.Lock()
+	var ready []*queuedAdmission
+	waiting := w.queue[:0]
+	for _, q := range w.queue {
+		if d, ok := w.current(q.policyID); ok && !d.Admitted && w.transient(d) {
+			waiting = append(waiting, q)
+		} else {
+			ready = append(ready, q)
+		}
+	}
+	for i := len(waiting); i < len(w.queue); i++ {
+		w.queue[i] = nil
+	}
+	w.queue = waiting
+	w.mu.Unlock()
+
+	for _, q := range ready {
+		w.settle(q)
+	}
+}
+
+// dequeue removes a request whose context ended and closes its channel, unless it was settled.
+func (w *Warmer) dequeue(q *queuedAdmission) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, queued := range w.queue {
+		if queued == q {
+			w.queue = append(w.queue[:i], w.queue[i+1:]...)
+			close(q.result)
+			return
+		}
+	}
+}
+
+// settle decides a request and delivers the decision. A policy removed from the manifest while
+// the request was queued, or an admission that cannot be tracked, is delivered as a denial.
+func (w *Warmer) settle(q *queuedAdmission) {
+	if q.stop != nil {
+		q.stop()
+	}
+	d, err := w.Admit(q.ctx, q.workloadID, q.policyID)
+	if err != nil {
+		d = Decision{PolicyID: q.policyID, Reason: err.Error()}
+	}
+	q.result <- d
+	close(q.result)
+}
+
+// Queued returns the number of admissions waiting for a transient denial to clear.
+func (w *Warmer) Queued() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.queue)
+}
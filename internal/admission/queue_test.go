@@ -0,0 +1,146 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"core/governance"
+)
+
+// receive returns the decisions delivered on ch until it is closed, failing if it stays open.
+func receive(t *testing.T, ch <-chan Decision) []Decision {
+	t.Helper()
+	var got []Decision
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case d, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, d)
+		case <-timeout:
+			t.Fatalf("channel not closed after %d decisions", len(got))
+		}
+	}
+}
+
+func TestEnqueue_TransientDenialAdmittedAfterRefresh(t *testing.T) {
+	node := &testNode{}
+	node.set(false, "enforcing")
+	w, _ := newTestWarmer(t, node)
+
+	ch, err := w.Enqueue(context.Background(), "wl-1", "secure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := w.Queued(); n != 1 {
+		t.Fatalf("Queued = %d, want 1", n)
+	}
+	select {
+	case d := <-ch:
+		t.Fatalf("decision %+v delivered while the denial is transient", d)
+	default:
+	}
+
+	node.set(true, "enforcing")
+	if err := w.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, ch); len(got) != 1 || !got[0].Admitted {
+		t.Errorf("decisions = %+v, want one admission", got)
+	}
+	if n := w.Queued(); n != 0 {
+		t.Errorf("Queued after the retry = %d, want 0", n)
+	}
+}
+
+func TestEnqueue_SuspendedAdmittedAfterResume(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "enforcing")
+	w, _ := newTestWarmer(t, node)
+	w.Suspend("node quarantined")
+
+	ch, err := w.Enqueue(context.Background(), "wl-1", "selinux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := w.Queued(); n != 1 {
+		t.Fatalf("Queued = %d, want 1", n)
+	}
+	w.Resume()
+	if got := receive(t, ch); len(got) != 1 || !got[0].Admitted {
+		t.Errorf("decisions = %+v, want one admission", got)
+	}
+}
+
+func TestEnqueue_NonTransientDenial(t *testing.T) {
+	node := &testNode{}
+	node.set(false, "permissive")
+	w, _ := newTestWarmer(t, node)
+
+	ch, err := w.Enqueue(context.Background(), "wl-1", "selinux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, ch); len(got) != 1 || got[0].Admitted || got[0].Constraint != "OS.SELinux.Enforcing" {
+		t.Errorf("decisions = %+v, want the denial at once", got)
+	}
+	if n := w.Queued(); n != 0 {
+		t.Errorf("Queued = %d, want 0", n)
+	}
+
+	if _, err := w.Enqueue(context.Background(), "wl-1", "missing"); !errors.Is(err, governance.ErrPolicyNotFound) {
+		t.Errorf("Enqueue(missing) error = %v, want ErrPolicyNotFound", err)
+	}
+}
+
+func TestEnqueue_ContextCancelled(t *testing.T) {
+	node := &testNode{}
+	node.set(false, "enforcing")
+	w, _ := newTestWarmer(t, node)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := w.Enqueue(ctx, "wl-1", "secure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if got := receive(t, ch); len(got) != 0 {
+		t.Errorf("decisions = %+v, want the channel closed without one", got)
+	}
+	if n := w.Queued(); n != 0 {
+		t.Errorf("Queued after cancellation = %d, want 0", n)
+	}
+}
+
+func TestEnqueue_CancellationRacesRetry(t *testing.T) {
+	node := &testNode{}
+	node.set(true, "enforcing")
+	w, _ := newTestWarmer(t, node)
+
+	for i := 0; i < 200; i++ {
+		w.Suspend("node quarantined")
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := w.Enqueue(ctx, "wl-1", "secure")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); cancel() }()
+		go func() { defer wg.Done(); w.Resume() }()
+		wg.Wait()
+
+		// Either the retry delivers the admission or the cancellation closes the channel; never both.
+		if got := receive(t, ch); len(got) > 1 {
+			t.Fatalf("iteration %d: decisions = %+v, want at most one", i, got)
+		}
+		if n := w.Queued(); n != 0 {
+			t.Fatalf("iteration %d: Queued = %d, want 0", i, n)
+		}
+	}
+}
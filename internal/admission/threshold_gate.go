@@ -0,0 +1,51 @@
+package admission
+
+import (
+	"context"
+	"errors"
+
+	"core/governance"
+	"services/telemetry"
+)
+
+// DefaultThresholdPolicy is the policy runtime GATM threshold changes are evaluated against.
+const DefaultThresholdPolicy = "sts-thresholds"
+
+// ThresholdGate admits runtime GATM threshold changes only if the proposed thresholds satisfy an
+// admission policy, so a rogue or mistyped push is refused before it takes effect. The thresholds
+// are presented to the policy as a CPES document (see ThresholdDocument), e.g.
+//
+//	{"id": "sts-thresholds", "constraints": [{"key": "CPES.sts.latency_threshold", "min": "200ms"}]}
+//
+// A missing policy rejects every change.
+type ThresholdGate struct {
+	Engine *governance.PolicyAdmissionEngine
+	// PolicyID selects the policy to evaluate (default DefaultThresholdPolicy).
+	PolicyID string
+}
+
+// ThresholdDocument presents thresholds as a CPES document under "sts", with durations in seconds:
+// sts.latency_threshold, sts.load_threshold, sts.max_breaches, sts.breach_decay_factor.
+func ThresholdDocument(t telemetry.Thresholds) governance.CPESConfig {
+	doc := governance.CPESConfig{}
+	doc.Set("sts.latency_threshold", t.LatencyThreshold.Seconds())
+	doc.Set("sts.load_threshold", t.LoadThreshold)
+	doc.Set("sts.max_breaches", t.MaxBreaches)
+	doc.Set("sts.breach_decay_factor", t.BreachDecayFactor)
+	return doc
+}
+
+// AdmitThresholds implements telemetry.ThresholdAdmitter.
+func (g *ThresholdGate) AdmitThresholds(ctx context.Context, change telemetry.ThresholdChange) error {
+	if g.Engine == nil {
+		return errors.New("no threshold admission policy is loaded")
+	}
+	policyID := g.PolicyID
+	if policyID == "" {
+		policyID = DefaultThresholdPolicy
+	}
+	_, err := g.Engine.EvaluateRequestContext(ctx, policyID, governance.SystemContext{CPESConfiguration: ThresholdDocument(change.Proposed)})
+	return err
+}
+
+var _ telemetry.ThresholdAdmitter = (*ThresholdGate)(nil)
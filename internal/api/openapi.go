@@ -0,0 +1,193 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Document is the subset of the OpenAPI 3.0 object model the generator emits.
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the named schemas referenced from operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Operation describes one method on a path.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Response describes a response body.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps the schema of a response body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON Schema as used by OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              float64            `json:"minimum,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// APIVersion is reported in the generated document's info block.
+const APIVersion = "1.0.0"
+
+// Spec generates the OpenAPI document for the server's routes.
+func (s *Server) Spec() *Document {
+	g := &schemaGen{schemas: map[string]*Schema{}}
+	errSchema := g.schemaFor(reflect.TypeOf(ErrorResponse{}))
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "Sovereign Telemetry Service API", Version: APIVersion},
+		Paths:   map[string]map[string]Operation{},
+	}
+	for _, rt := range s.routes {
+		item, ok := doc.Paths[rt.path]
+		if !ok {
+			item = map[string]Operation{}
+			doc.Paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = Operation{
+			OperationID: operationID(rt.method, rt.path),
+			Summary:     rt.summary,
+			Parameters:  rt.params,
+			Responses: map[string]Response{
+				"200":     {Description: "OK", Content: jsonContent(g.schemaFor(rt.responseType()))},
+				"default": {Description: "Error", Content: jsonContent(errSchema)},
+			},
+		}
+	}
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// operationID derives a stable identifier, e.g., "GET /api/v1/admission/{policy}" → "getAdmissionPolicy".
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.Split(path, "/") {
+		part = strings.Trim(part, "{}")
+		if part == "" || part == "api" || part == "v1" {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(part, func(r rune) bool { return r == '_' || r == '-' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGen converts Go types to schemas, registering named structs as components.
+type schemaGen struct {
+	schemas map[string]*Schema
+}
+
+func (g *schemaGen) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == reflect.TypeOf(time.Duration(0)):
+		return &Schema{Type: "integer", Format: "int64"} // Nanoseconds, as encoding/json emits
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		return &Schema{} // Any value
+	}
+}
+
+// structRef registers t under its type name and returns a reference to it.
+func (g *schemaGen) structRef(t reflect.Type) *Schema {
+	name := t.Name()
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, done := g.schemas[name]; done {
+		return ref
+	}
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.schemas[name] = schema // Registered before recursing so self-references terminate
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		field, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if field == "-" {
+			continue
+		}
+		if field == "" {
+			field = f.Name
+		}
+		if f.Tag.Get("unit") == "s" {
+			schema.Properties[field] = &Schema{Type: "number", Format: "double"} // Durations encoded as float seconds
+		} else {
+			schema.Properties[field] = g.schemaFor(f.Type)
+		}
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, field)
+		}
+	}
+	return ref
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSpec_CoversRoutesAndSchemas(t *testing.T) {
+	srv := NewServer(Options{})
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json = %d", rec.Code)
+	}
+	var doc Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+
+	for _, rt := range srv.routes {
+		if _, ok := doc.Paths[rt.path]["get"]; !ok {
+			t.Errorf("spec is missing GET %s", rt.path)
+		}
+	}
+	if id := doc.Paths["/api/v1/admission/{policy}"]["get"].OperationID; id != "getAdmissionPolicy" {
+		t.Errorf("operationId = %q", id)
+	}
+
+	td, ok := doc.Components.Schemas["TelemetryData"]
+	if !ok {
+		t.Fatal("TelemetryData schema not registered")
+	}
+	if ts := td.Properties["timestamp"]; ts == nil || ts.Format != "date-time" {
+		t.Errorf("timestamp schema = %+v, want date-time string", ts)
+	}
+	for _, field := range td.Required {
+		if field == "labels" {
+			t.Error("omitempty field listed as required")
+		}
+	}
+}
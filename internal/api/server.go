@@ -0,0 +1,393 @@
+// Package api exposes the read-only REST surface used by remote consumers of STS: health, recent
+// telemetry, governance status, and precomputed admission decisions. The OpenAPI 3 document
+// describing it is generated from the same route table and served at /openapi.json.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"internal/admission"
+	"internal/inventory"
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+const defaultQueryLimit = 1000
+
+// HealthResponse is returned by the health endpoint.
+type HealthResponse struct {
+	Telemetry     telemetry.TelemetryData  `json:"telemetry"`
+	GATMViolation bool                     `json:"gatm_violation"`
+	Backpressure  float64                  `json:"backpressure"`
+	Paused        bool                     `json:"paused"` // Background collection is paused
+	State         telemetry.LifecycleState `json:"state"`  // Escalation state, e.g. RRP_PENDING
+}
+
+// GovernanceStatus summarizes the governance state pulled from the policy endpoint.
+type GovernanceStatus struct {
+	LastUpdated   time.Time          `json:"last_updated"`
+	Generation    uint64             `json:"generation"` // Incremented by every governance state update
+	Fresh         bool               `json:"fresh"`
+	SamplingRates map[string]float64 `json:"sampling_rates,omitempty"`
+	MaskingRules  []string           `json:"masking_rules,omitempty"`
+	Error         string             `json:"error,omitempty"` // Why the state is not fresh
+}
+
+// DriftResponse lists the admitted workloads that no longer qualify after the node's context drifted.
+type DriftResponse struct {
+	Flagged   []admission.FlaggedWorkload `json:"flagged"`
+	LastDrift *admission.ContextDrift     `json:"last_drift,omitempty"` // Absent until a drift is detected
+}
+
+// ErrorResponse is the body of every non-2xx response.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Options wires the server to the running components. Every field except STS is optional; the
+// endpoints of a missing component respond 503 Service Unavailable.
+type Options struct {
+	STS        telemetry.STS
+	Sink       telemetry.TelemetrySink // History for /api/v1/telemetry, e.g. a persistence.QueryFederator
+	Warmer     *admission.Warmer
+	Governance func() GovernanceStatus
+	Compliance *inventory.ComplianceReporter
+	// QueryLimit caps the number of records a telemetry query may return (default 1000).
+	QueryLimit int
+}
+
+// Server serves the REST API.
+type Server struct {
+	opts    Options
+	routes  []route
+	mux     *http.ServeMux
+	handler http.Handler
+}
+
+// NewServer creates the API handler.
+func NewServer(opts Options) *Server {
+	if opts.QueryLimit == 0 {
+		opts.QueryLimit = defaultQueryLimit
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	s.routes = []route{
+		{
+			method: http.MethodGet, path: "/healthz", summary: "Current telemetry snapshot and GATM status",
+			response: HealthResponse{}, handler: s.handleHealth,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/lifecycle", summary: "Escalation state and the transition that entered it",
+			response: telemetry.LifecycleStatus{}, handler: s.handleLifecycle,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/telemetry", summary: "Most recent persisted telemetry records, oldest first",
+			params:   []Parameter{{Name: "n", In: "query", Description: "Number of records", Schema: &Schema{Type: "integer", Minimum: 1}}},
+			response: []telemetry.TelemetryData{}, handler: s.handleTelemetry,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/telemetry/range", summary: "Persisted telemetry in a time range, paged in timestamp order",
+			params: []Parameter{
+				{Name: "from", In: "query", Description: "Inclusive start (RFC 3339)", Schema: &Schema{Type: "string", Format: "date-time"}},
+				{Name: "to", In: "query", Description: "Exclusive end (RFC 3339)", Schema: &Schema{Type: "string", Format: "date-time"}},
+				{Name: "cursor", In: "query", Description: "next_cursor of the previous page", Schema: &Schema{Type: "string"}},
+				{Name: "limit", In: "query", Description: "Records per page", Schema: &Schema{Type: "integer", Minimum: 1}},
+				{Name: "format", In: "query", Description: "json (default), one page; or ndjson, every page streamed one record per line", Schema: &Schema{Type: "string", Enum: []string{"json", "ndjson"}}},
+			},
+			response: telemetry.Page{}, handler: s.handleTelemetryRange,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/incident", summary: "Timeline of the current or most recent GATM incident",
+			response: telemetry.Incident{}, handler: s.handleIncident,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/governance/status", summary: "Governance state freshness and active policies",
+			response: GovernanceStatus{}, handler: s.handleGovernance,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/admission", summary: "Precomputed admission decisions for every policy",
+			response: admission.CapabilityMatrix{}, handler: s.handleMatrix,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/admission/{policy}", summary: "Precomputed admission decision for one policy",
+			params:   []Parameter{{Name: "policy", In: "path", Required: true, Schema: &Schema{Type: "string"}}},
+			response: admission.Decision{}, handler: s.handleDecision,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/compliance", summary: "Fleet compliance with the baseline policy",
+			params: []Parameter{
+				{Name: "format", In: "query", Description: "json (default) or csv", Schema: &Schema{Type: "string", Enum: []string{"json", "csv"}}},
+				{Name: "refresh", In: "query", Description: "Generate a new report instead of returning the latest", Schema: &Schema{Type: "boolean"}},
+			},
+			response: inventory.ComplianceReport{}, handler: s.handleCompliance,
+		},
+		{
+			method: http.MethodGet, path: "/api/v1/admission-drift", summary: "Admitted workloads that no longer qualify after context drift",
+			response: DriftResponse{}, handler: s.handleDrift,
+		},
+	}
+	for _, rt := range s.routes {
+		s.mux.HandleFunc(rt.pattern(), allowMethod(rt.method, rt.handler))
+	}
+	spec := s.Spec()
+	s.mux.HandleFunc("/openapi.json", allowMethod(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, spec)
+	}))
+	s.handler = correlation.Middleware(nil, s.mux)
+	return s
+}
+
+func allowMethod(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ServeHTTP implements http.Handler. Every request is assigned a correlation ID.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// route pairs a handler with the metadata used to generate its OpenAPI operation.
+type route struct {
+	method   string
+	path     string
+	summary  string
+	params   []Parameter
+	response interface{} // Zero value of the 200 response body
+	handler  http.HandlerFunc
+}
+
+// pattern converts a templated path to a ServeMux pattern; a trailing {param} becomes a subtree.
+func (r route) pattern() string {
+	if i := strings.Index(r.path, "{"); i >= 0 {
+		return r.path[:i]
+	}
+	return r.path
+}
+
+// pathParam returns the trailing path segment matched by a templated route.
+func pathParam(r *http.Request, prefix string) string {
+	return strings.TrimPrefix(r.URL.Path, prefix)
+}
+
+func (r route) responseType() reflect.Type {
+	return reflect.TypeOf(r.response)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, HealthResponse{
+		Telemetry:     s.opts.STS.GetHealthStatus(),
+		GATMViolation: s.opts.STS.CheckGATMViolation(),
+		Backpressure:  s.opts.STS.Backpressure(),
+		Paused:        s.opts.STS.Paused(),
+		State:         s.opts.STS.Lifecycle().State,
+	})
+}
+
+func (s *Server) handleLifecycle(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.opts.STS.Lifecycle())
+}
+
+func (s *Server) handleTelemetry(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Sink == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no telemetry sink is configured"))
+		return
+	}
+	n := s.opts.QueryLimit
+	if raw := r.URL.Query().Get("n"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("n must be a positive integer"))
+			return
+		}
+		n = min(parsed, s.opts.QueryLimit)
+	}
+	records, err := s.opts.Sink.QueryLastN(r.Context(), n)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, records)
+}
+
+func (s *Server) handleTelemetryRange(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Sink == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no telemetry sink is configured"))
+		return
+	}
+	query := r.URL.Query()
+	q := telemetry.RangeQuery{Cursor: query.Get("cursor"), Limit: s.opts.QueryLimit}
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		if raw := query.Get(bound.name); raw != "" {
+			t, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, errors.New(bound.name+" must be an RFC 3339 time"))
+				return
+			}
+			*bound.dst = t
+		}
+	}
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("limit must be a positive integer"))
+			return
+		}
+		q.Limit = min(parsed, s.opts.QueryLimit)
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "ndjson" {
+		writeError(w, http.StatusBadRequest, errors.New("format must be json or ndjson"))
+		return
+	}
+
+	page, err := telemetry.QueryRange(r.Context(), s.opts.Sink, q)
+	switch {
+	case errors.Is(err, telemetry.ErrInvalidCursor):
+		writeError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, telemetry.ErrRangeUnsupported):
+		writeError(w, http.StatusNotImplemented, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if format != "ndjson" {
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+
+	// Stream page after page, so the whole range is never held in memory. Once the first line is
+	// out the status is sent; a later failure ends the stream with an error line.
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for {
+		for _, td := range page.Records {
+			if err := enc.Encode(td); err != nil {
+				return
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if page.NextCursor == "" {
+			return
+		}
+		q.Cursor = page.NextCursor
+		if page, err = telemetry.QueryRange(r.Context(), s.opts.Sink, q); err != nil {
+			enc.Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+}
+
+func (s *Server) handleIncident(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Sink == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no telemetry sink is configured"))
+		return
+	}
+	incident, err := telemetry.ReconstructIncident(r.Context(), s.opts.Sink, s.opts.QueryLimit)
+	switch {
+	case errors.Is(err, telemetry.ErrNoIncident):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err)
+	default:
+		writeJSON(w, http.StatusOK, incident)
+	}
+}
+
+func (s *Server) handleGovernance(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Governance == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("governance status is not configured"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.opts.Governance())
+}
+
+func (s *Server) handleMatrix(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Warmer == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("admission cache is not configured"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.opts.Warmer.Matrix())
+}
+
+func (s *Server) handleDecision(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Warmer == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("admission cache is not configured"))
+		return
+	}
+	// Lookups only: workloads are tracked for drift through the authenticated admin API.
+	decision, ok := s.opts.Warmer.Decide(r.Context(), pathParam(r, "/api/v1/admission/"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("no decision for policy"))
+		return
+	}
+	writeJSON(w, http.StatusOK, decision)
+}
+
+func (s *Server) handleDrift(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Warmer == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("admission cache is not configured"))
+		return
+	}
+	resp := DriftResponse{Flagged: s.opts.Warmer.Flagged()}
+	if drift, ok := s.opts.Warmer.LastDrift(); ok {
+		resp.LastDrift = &drift
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCompliance(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Compliance == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("compliance reporting is not configured"))
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, http.StatusBadRequest, errors.New("format must be json or csv"))
+		return
+	}
+	report, ok := s.opts.Compliance.Latest()
+	if !ok || r.URL.Query().Get("refresh") == "true" {
+		var err error
+		if report, err = s.opts.Compliance.Generate(r.Context()); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="compliance.csv"`)
+		report.WriteCSV(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,154 @@
+package attestation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"internal/crot/tpm"
+)
+
+// serviceName is the fully qualified gRPC service name exposed by the verifier.
+const serviceName = "sts.attestation.v1.Verifier"
+
+// jsonCodec carries the verifier's messages as JSON over gRPC so the service
+// does not depend on generated protobuf bindings.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// ChallengeRequest asks the verifier for a fresh nonce.
+type ChallengeRequest struct {
+	NodeID string `json:"node_id"`
+}
+
+// ChallengeResponse carries the nonce the node must bind into its quote.
+type ChallengeResponse struct {
+	Nonce     []byte    `json:"nonce"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// verifierServer adapts Verifier to the gRPC handler signatures.
+type verifierServer interface {
+	challenge(ctx context.Context, req *ChallengeRequest) (*ChallengeResponse, error)
+	submitEvidence(ctx context.Context, ev *Evidence) (*Verdict, error)
+}
+
+func (v *Verifier) challenge(_ context.Context, req *ChallengeRequest) (*ChallengeResponse, error) {
+	nonce, expiresAt, err := v.Challenge(req.NodeID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &ChallengeResponse{Nonce: nonce, ExpiresAt: expiresAt}, nil
+}
+
+func (v *Verifier) submitEvidence(_ context.Context, ev *Evidence) (*Verdict, error) {
+	verdict, err := v.Verify(*ev)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &verdict, nil
+}
+
+// toStatus maps verifier errors to gRPC status codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, ErrUnknownNode):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrNoChallenge):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrTooManyChallenges):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, tpm.ErrQuoteInvalid):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+var verifierServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*verifierServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Challenge",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(ChallengeRequest)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, r interface{}) (interface{}, error) {
+					return srv.(verifierServer).challenge(ctx, r.(*ChallengeRequest))
+				}
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/Challenge"}, handler)
+			},
+		},
+		{
+			MethodName: "SubmitEvidence",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := new(Evidence)
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, r interface{}) (interface{}, error) {
+					return srv.(verifierServer).submitEvidence(ctx, r.(*Evidence))
+				}
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+				return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/SubmitEvidence"}, handler)
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// RegisterVerifierServer exposes v on the given gRPC server.
+func RegisterVerifierServer(s *grpc.Server, v *Verifier) {
+	s.RegisterService(&verifierServiceDesc, v)
+}
+
+// Client submits attestation evidence from a remote STS instance to the verifier.
+type Client struct {
+	conn   *grpc.ClientConn
+	nodeID string
+}
+
+// NewClient wraps an established connection to the verifier.
+func NewClient(conn *grpc.ClientConn, nodeID string) *Client {
+	return &Client{conn: conn, nodeID: nodeID}
+}
+
+// Attest runs a full challenge/quote/submit round using the local TPM provider.
+func (c *Client) Attest(ctx context.Context, provider *tpm.Provider) (*Verdict, error) {
+	ch := new(ChallengeResponse)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/Challenge", &ChallengeRequest{NodeID: c.nodeID}, ch, grpc.CallContentSubtype("json")); err != nil {
+		return nil, err
+	}
+
+	quote, err := provider.Quote(ch.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := new(Verdict)
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/SubmitEvidence", &Evidence{NodeID: c.nodeID, Quote: *quote}, verdict, grpc.CallContentSubtype("json")); err != nil {
+		return nil, err
+	}
+	return verdict, nil
+}
@@ -0,0 +1,250 @@
+// Package attestation implements the remote attestation verifier service mode.
+// Remote STS instances request a challenge nonce, quote their TPM against it, and submit
+// the evidence; the verifier checks it against stored golden values and feeds the verdict
+// into the fleet aggregator's integrity view.
+package attestation
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"internal/crot"
+	"internal/crot/tpm"
+	"internal/fleet"
+)
+
+const (
+	nonceSize       = 32
+	defaultNonceTTL = 30 * time.Second
+	// maxChallenges bounds the outstanding challenges per node, so challenges requested by other
+	// callers cannot pile up or displace the one a node is answering.
+	maxChallenges = 16
+)
+
+var (
+	// ErrUnknownNode is returned when no golden values are stored for a node.
+	ErrUnknownNode = errors.New("attestation: no golden values for node")
+	// ErrNoChallenge is returned when evidence does not answer an outstanding, unexpired challenge.
+	ErrNoChallenge = errors.New("attestation: no outstanding challenge for node")
+	// ErrTooManyChallenges is returned when a node already has the maximum number of unexpired
+	// challenges outstanding.
+	ErrTooManyChallenges = errors.New("attestation: too many outstanding challenges for node")
+)
+
+// GoldenValues are the trusted reference values for a single node.
+type GoldenValues struct {
+	AKPublic crypto.PublicKey
+	Policy   tpm.GoldenPolicy
+}
+
+// GoldenStore resolves golden values per node.
+type GoldenStore interface {
+	Golden(nodeID string) (GoldenValues, error)
+}
+
+// fileGoldenEntry is the on-disk representation of a node's golden values.
+type fileGoldenEntry struct {
+	AKPublicPEM string         `json:"ak_public_pem"`
+	PCRs        map[int]string `json:"pcrs"`
+}
+
+// FileGoldenStore is a GoldenStore loaded from a JSON document keyed by node ID.
+type FileGoldenStore struct {
+	nodes map[string]GoldenValues
+}
+
+// LoadFileGoldenStore reads golden values for every enrolled node from path.
+func LoadFileGoldenStore(path string) (*FileGoldenStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden store at %s: %w", path, err)
+	}
+	var doc struct {
+		Nodes map[string]fileGoldenEntry `json:"nodes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse golden store JSON: %w", err)
+	}
+
+	store := &FileGoldenStore{nodes: make(map[string]GoldenValues, len(doc.Nodes))}
+	for id, entry := range doc.Nodes {
+		block, _ := pem.Decode([]byte(entry.AKPublicPEM))
+		if block == nil {
+			return nil, fmt.Errorf("node %s: ak_public_pem is not valid PEM", id)
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: failed to parse AK public key: %w", id, err)
+		}
+		policy := tpm.GoldenPolicy{PCRs: entry.PCRs}
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("node %s: %w", id, err)
+		}
+		store.nodes[id] = GoldenValues{AKPublic: pub, Policy: policy}
+	}
+	return store, nil
+}
+
+// Golden implements GoldenStore.
+func (s *FileGoldenStore) Golden(nodeID string) (GoldenValues, error) {
+	golden, ok := s.nodes[nodeID]
+	if !ok {
+		return GoldenValues{}, fmt.Errorf("%w: %s", ErrUnknownNode, nodeID)
+	}
+	return golden, nil
+}
+
+// Evidence is submitted by a remote STS instance in response to a challenge.
+type Evidence struct {
+	NodeID string    `json:"node_id"`
+	Quote  tpm.Quote `json:"quote"` // must be bound to the nonce issued by Challenge
+}
+
+// Verdict is the verifier's decision for a single piece of evidence.
+type Verdict struct {
+	NodeID     string               `json:"node_id"`
+	Status     crot.IntegrityStatus `json:"status"`
+	VerifiedAt time.Time            `json:"verified_at"`
+	Reason     string               `json:"reason,omitempty"`
+}
+
+type challenge struct {
+	nonce     []byte
+	expiresAt time.Time
+}
+
+// Verifier checks remote attestation evidence and publishes verdicts to the fleet aggregator.
+type Verifier struct {
+	store      GoldenStore
+	aggregator *fleet.Aggregator
+	nonceTTL   time.Duration
+	now        func() time.Time
+
+	challenges map[string][]challenge // outstanding challenges per node, oldest first
+	mu         sync.Mutex
+}
+
+// NewVerifier creates a verifier. A zero nonceTTL defaults to 30 seconds.
+func NewVerifier(store GoldenStore, aggregator *fleet.Aggregator, nonceTTL time.Duration) *Verifier {
+	if nonceTTL == 0 {
+		nonceTTL = defaultNonceTTL
+	}
+	return &Verifier{
+		store:      store,
+		aggregator: aggregator,
+		nonceTTL:   nonceTTL,
+		now:        time.Now,
+		challenges: make(map[string][]challenge),
+	}
+}
+
+// Challenge issues a fresh nonce for nodeID. Earlier challenges stay outstanding until they are
+// answered or expire, so a challenge requested by another caller does not invalidate the one the
+// node is answering; at most 16 may be outstanding at once.
+func (v *Verifier) Challenge(nodeID string) ([]byte, time.Time, error) {
+	if _, err := v.store.Golden(nodeID); err != nil {
+		return nil, time.Time{}, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to generate challenge nonce: %w", err)
+	}
+	now := v.now()
+	expiresAt := now.Add(v.nonceTTL)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	pending := v.pruneLocked(nodeID, now)
+	if len(pending) >= maxChallenges {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrTooManyChallenges, nodeID)
+	}
+	v.challenges[nodeID] = append(pending, challenge{nonce: nonce, expiresAt: expiresAt})
+	return nonce, expiresAt, nil
+}
+
+// Verify checks the evidence against the outstanding challenge its quote answers and the node's
+// golden values. Only authentic evidence, answering an unexpired challenge, consumes that challenge
+// and is published to the fleet aggregator; evidence that cannot be authenticated is returned as an
+// error, leaving the challenge outstanding and the fleet view unchanged.
+func (v *Verifier) Verify(ev Evidence) (Verdict, error) {
+	// The nonce is not authenticated yet; it only selects the challenge to verify against.
+	nonce, err := ev.Quote.Nonce()
+	if err != nil {
+		return Verdict{}, fmt.Errorf("node %s: %w", ev.NodeID, err)
+	}
+	if !v.outstanding(ev.NodeID, nonce) {
+		return Verdict{}, fmt.Errorf("%w: %s", ErrNoChallenge, ev.NodeID)
+	}
+
+	golden, err := v.store.Golden(ev.NodeID)
+	if err != nil {
+		return Verdict{}, err
+	}
+	status, err := tpm.VerifyQuote(golden.AKPublic, &ev.Quote, nonce, golden.Policy)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("node %s: %w", ev.NodeID, err)
+	}
+
+	// Consume the challenge only if it was not answered in the meantime, so each nonce yields at
+	// most one verdict.
+	if !v.consume(ev.NodeID, nonce) {
+		return Verdict{}, fmt.Errorf("%w: %s", ErrNoChallenge, ev.NodeID)
+	}
+
+	verdict := Verdict{NodeID: ev.NodeID, Status: status, VerifiedAt: v.now()}
+	if status == crot.IntegrityDiverged {
+		verdict.Reason = "PCR values deviate from the golden policy"
+	}
+	if v.aggregator != nil {
+		v.aggregator.UpdateIntegrity(verdict.NodeID, verdict.Status, verdict.VerifiedAt)
+	}
+	return verdict, nil
+}
+
+// outstanding reports whether nonce answers an unexpired challenge issued to nodeID.
+func (v *Verifier) outstanding(nodeID string, nonce []byte) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return slices.ContainsFunc(v.pruneLocked(nodeID, v.now()), func(ch challenge) bool {
+		return bytes.Equal(ch.nonce, nonce)
+	})
+}
+
+// consume removes the challenge answered by nonce, reporting whether it was still outstanding.
+func (v *Verifier) consume(nodeID string, nonce []byte) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	pending := v.pruneLocked(nodeID, v.now())
+	i := slices.IndexFunc(pending, func(ch challenge) bool { return bytes.Equal(ch.nonce, nonce) })
+	if i < 0 {
+		return false
+	}
+	if pending = slices.Delete(pending, i, i+1); len(pending) == 0 {
+		delete(v.challenges, nodeID)
+	} else {
+		v.challenges[nodeID] = pending
+	}
+	return true
+}
+
+// pruneLocked drops nodeID's expired challenges and returns those still outstanding. v.mu must be
+// held.
+func (v *Verifier) pruneLocked(nodeID string, now time.Time) []challenge {
+	pending := slices.DeleteFunc(v.challenges[nodeID], func(ch challenge) bool { return now.After(ch.expiresAt) })
+	if len(pending) == 0 {
+		delete(v.challenges, nodeID)
+		return nil
+	}
+	v.challenges[nodeID] = pending
+	return pending
+}
@@ -0,0 +1,217 @@
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm-tools/simulator"
+
+	"internal/crot"
+	"internal/crot/tpm"
+	"internal/fleet"
+)
+
+type mapGoldenStore map[string]GoldenValues
+
+func (s mapGoldenStore) Golden(nodeID string) (GoldenValues, error) {
+	golden, ok := s[nodeID]
+	if !ok {
+		return GoldenValues{}, ErrUnknownNode
+	}
+	return golden, nil
+}
+
+// newTestVerifier returns a verifier enrolling "node-a" with a simulated TPM, and that TPM.
+func newTestVerifier(t *testing.T) (*Verifier, *fleet.Aggregator, *tpm.Provider) {
+	t.Helper()
+	sim, err := simulator.Get()
+	if err != nil {
+		t.Fatalf("simulator.Get() error = %v", err)
+	}
+	// A fresh simulator reports all-zero SHA-256 PCRs.
+	policy := tpm.GoldenPolicy{PCRs: map[int]string{0: hex.EncodeToString(make([]byte, 32))}}
+	p, err := tpm.NewProvider(sim, policy)
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	agg := fleet.NewAggregator(fleet.AggregatorConfig{})
+	store := mapGoldenStore{"node-a": {AKPublic: p.AKPublic(), Policy: policy}}
+	return NewVerifier(store, agg, time.Minute), agg, p
+}
+
+func evidence(t *testing.T, v *Verifier, p *tpm.Provider) Evidence {
+	t.Helper()
+	nonce, _, err := v.Challenge("node-a")
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+	quote, err := p.Quote(nonce)
+	if err != nil {
+		t.Fatalf("Quote() error = %v", err)
+	}
+	return Evidence{NodeID: "node-a", Quote: *quote}
+}
+
+func TestVerify_RejectsReplay(t *testing.T) {
+	v, agg, p := newTestVerifier(t)
+	ev := evidence(t, v, p)
+
+	verdict, err := v.Verify(ev)
+	if err != nil || verdict.Status != crot.IntegritySynced {
+		t.Fatalf("Verify() = %+v, %v; want %s", verdict, err, crot.IntegritySynced)
+	}
+	if got := agg.IntegrityView()["node-a"]; got != crot.IntegritySynced {
+		t.Errorf("fleet integrity = %s, want %s", got, crot.IntegritySynced)
+	}
+	if _, err := v.Verify(ev); !errors.Is(err, ErrNoChallenge) {
+		t.Errorf("replayed Verify() error = %v, want ErrNoChallenge", err)
+	}
+}
+
+func TestVerify_RejectsExpiredChallenge(t *testing.T) {
+	v, agg, p := newTestVerifier(t)
+	now := time.Now()
+	v.now = func() time.Time { return now }
+	ev := evidence(t, v, p)
+
+	now = now.Add(time.Minute + time.Second)
+	if _, err := v.Verify(ev); !errors.Is(err, ErrNoChallenge) {
+		t.Errorf("Verify() after expiry error = %v, want ErrNoChallenge", err)
+	}
+	if _, ok := agg.IntegrityView()["node-a"]; ok {
+		t.Error("expired evidence reached the fleet view")
+	}
+}
+
+func TestVerify_BadSignatureKeepsChallenge(t *testing.T) {
+	v, agg, p := newTestVerifier(t)
+	ev := evidence(t, v, p)
+
+	forged := ev
+	forged.Quote.Signature = append([]byte(nil), ev.Quote.Signature...)
+	forged.Quote.Signature[0] ^= 0xff
+	if _, err := v.Verify(forged); !errors.Is(err, tpm.ErrQuoteInvalid) {
+		t.Fatalf("Verify() with a forged signature error = %v, want ErrQuoteInvalid", err)
+	}
+	if _, ok := agg.IntegrityView()["node-a"]; ok {
+		t.Error("unauthenticated evidence reached the fleet view")
+	}
+
+	// The node's genuine answer is still accepted.
+	if verdict, err := v.Verify(ev); err != nil || verdict.Status != crot.IntegritySynced {
+		t.Errorf("Verify() after forged evidence = %+v, %v; want %s", verdict, err, crot.IntegritySynced)
+	}
+}
+
+func TestVerify_ConcurrentChallenges(t *testing.T) {
+	v, _, p := newTestVerifier(t)
+	first := evidence(t, v, p)
+	// Another caller's challenge does not invalidate the one the node is answering.
+	second := evidence(t, v, p)
+
+	for _, ev := range []Evidence{first, second} {
+		if verdict, err := v.Verify(ev); err != nil || verdict.Status != crot.IntegritySynced {
+			t.Errorf("Verify() = %+v, %v; want %s", verdict, err, crot.IntegritySynced)
+		}
+	}
+	if _, err := v.Verify(first); !errors.Is(err, ErrNoChallenge) {
+		t.Errorf("replayed Verify() error = %v, want ErrNoChallenge", err)
+	}
+}
+
+func TestChallenge_Limit(t *testing.T) {
+	v, _, p := newTestVerifier(t)
+	now := time.Now()
+	v.now = func() time.Time { return now }
+
+	ev := evidence(t, v, p)
+	for i := 1; i < maxChallenges; i++ {
+		if _, _, err := v.Challenge("node-a"); err != nil {
+			t.Fatalf("Challenge() %d error = %v", i, err)
+		}
+	}
+	if _, _, err := v.Challenge("node-a"); !errors.Is(err, ErrTooManyChallenges) {
+		t.Fatalf("Challenge() beyond the limit error = %v, want ErrTooManyChallenges", err)
+	}
+	// Outstanding challenges are kept rather than evicted, so the node's answer is still accepted.
+	if _, err := v.Verify(ev); err != nil {
+		t.Errorf("Verify() after the limit was reached error = %v", err)
+	}
+	if _, _, err := v.Challenge("node-a"); err != nil {
+		t.Errorf("Challenge() after one was answered error = %v", err)
+	}
+
+	// Expired challenges no longer count.
+	now = now.Add(time.Minute + time.Second)
+	if _, _, err := v.Challenge("node-a"); err != nil {
+		t.Errorf("Challenge() after expiry error = %v", err)
+	}
+}
+
+func TestLoadFileGoldenStore(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	akPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	zero := hex.EncodeToString(make([]byte, 32))
+
+	tests := []struct {
+		name    string
+		pcrs    map[int]string
+		wantErr string
+	}{
+		{name: "Valid", pcrs: map[int]string{0: zero, 7: zero}},
+		{name: "Empty", pcrs: map[int]string{}, wantErr: "at least one PCR"},
+		{name: "Missing", wantErr: "at least one PCR"},
+		{name: "Index Out Of Range", pcrs: map[int]string{24: zero}, wantErr: "out of range"},
+		{name: "Not Hex", pcrs: map[int]string{0: strings.Repeat("zz", 32)}, wantErr: "hex-encoded SHA-256"},
+		{name: "Wrong Length", pcrs: map[int]string{0: zero[:40]}, wantErr: "hex-encoded SHA-256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := map[string]interface{}{"nodes": map[string]interface{}{
+				"node-a": map[string]interface{}{"ak_public_pem": akPEM, "pcrs": tt.pcrs},
+			}}
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "golden.json")
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			store, err := LoadFileGoldenStore(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "node-a") {
+					t.Errorf("LoadFileGoldenStore() error = %v, want one naming node-a and containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if golden, err := store.Golden("node-a"); err != nil || len(golden.Policy.PCRs) != len(tt.pcrs) {
+				t.Errorf("Golden(node-a) = %+v, %v; want the stored policy", golden, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package authz assigns roles to authenticated principals and decides which administrative
+// operations they may perform. It is shared by the admin HTTP API, the control socket, and gRPC
+// services (see package authzgrpc).
+package authz
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Role is an ordered privilege level; each role includes every permission of the roles below it.
+type Role int
+
+const (
+	// RoleNone grants nothing. It is the role of unknown principals unless a default is configured.
+	RoleNone Role = iota
+	// RoleViewer may read status, history, and the audit log.
+	RoleViewer
+	// RoleOperator may additionally acknowledge violations and trigger collections.
+	RoleOperator
+	// RoleAdmin may additionally reset breach counters and reload policies.
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{RoleNone: "none", RoleViewer: "viewer", RoleOperator: "operator", RoleAdmin: "admin"}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// ParseRole parses a role name as used in configuration files.
+func ParseRole(s string) (Role, error) {
+	for role, name := range roleNames {
+		if strings.EqualFold(s, name) {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role '%s'", s)
+}
+
+// ErrForbidden is returned when a principal's role is insufficient for an operation.
+var ErrForbidden = errors.New("authz: forbidden")
+
+// Event describes one authorization decision, for audit logging.
+type Event struct {
+	Time      time.Time
+	Principal string
+	Role      Role
+	Action    string
+	Required  Role
+	Allowed   bool
+}
+
+// AuditFunc receives every authorization decision for privileged actions (operator and above).
+type AuditFunc func(ev Event)
+
+// Policy binds principals to roles.
+type Policy struct {
+	// Bindings maps a principal (token owner, certificate common name, or "uid:<n>") to its role.
+	Bindings map[string]Role
+	// Default is the role of authenticated principals without a binding. The zero value grants nothing.
+	Default Role
+	// Audit, if set, is called for every decision on a privileged action, allowed or denied.
+	Audit AuditFunc
+}
+
+// RoleOf returns the role bound to principal.
+func (p *Policy) RoleOf(principal string) Role {
+	if role, ok := p.Bindings[principal]; ok {
+		return role
+	}
+	return p.Default
+}
+
+// Authorize checks that principal holds at least the required role for action.
+func (p *Policy) Authorize(principal, action string, required Role) error {
+	role := p.RoleOf(principal)
+	allowed := role >= required
+	if p.Audit != nil && required >= RoleOperator {
+		p.Audit(Event{Time: time.Now(), Principal: principal, Role: role, Action: action, Required: required, Allowed: allowed})
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s requires role %s, principal '%s' has %s", ErrForbidden, action, required, principal, role)
+	}
+	return nil
+}
+
+// CertificatePrincipal returns the subject common name of the verified client certificate, for
+// mTLS-based identities. The TLS configuration must require and verify client certificates.
+func CertificatePrincipal(state *tls.ConnectionState) (string, error) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", errors.New("authz: no verified client certificate")
+	}
+	cn := state.VerifiedChains[0][0].Subject.CommonName
+	if cn == "" {
+		return "", errors.New("authz: client certificate has no common name")
+	}
+	return cn, nil
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal stored by WithPrincipal.
+func PrincipalFrom(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
@@ -0,0 +1,68 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoleOrdering(t *testing.T) {
+	roles := []Role{RoleNone, RoleViewer, RoleOperator, RoleAdmin}
+	for i, role := range roles {
+		parsed, err := ParseRole(role.String())
+		if err != nil || parsed != role {
+			t.Errorf("ParseRole(%q) = %v, %v", role.String(), parsed, err)
+		}
+		for _, lower := range roles[:i] {
+			if role <= lower {
+				t.Errorf("%s does not include %s", role, lower)
+			}
+		}
+	}
+	if role, err := ParseRole("ADMIN"); err != nil || role != RoleAdmin {
+		t.Errorf("ParseRole(ADMIN) = %v, %v; want case-insensitive match", role, err)
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("ParseRole(root) accepted an unknown role")
+	}
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	var events []Event
+	p := &Policy{
+		Bindings: map[string]Role{"alice": RoleAdmin, "olive": RoleOperator},
+		Default:  RoleViewer,
+		Audit:    func(ev Event) { events = append(events, ev) },
+	}
+
+	if err := p.Authorize("olive", "acknowledge", RoleOperator); err != nil {
+		t.Errorf("operator acknowledge: %v", err)
+	}
+	if err := p.Authorize("olive", "reset", RoleAdmin); !errors.Is(err, ErrForbidden) {
+		t.Errorf("operator reset error = %v, want ErrForbidden", err)
+	}
+	// Unbound principals get the default role; viewer actions are not audited.
+	if err := p.Authorize("mallory", "status", RoleViewer); err != nil {
+		t.Errorf("default viewer status: %v", err)
+	}
+	if err := p.Authorize("alice", "reset", RoleAdmin); err != nil {
+		t.Errorf("admin reset: %v", err)
+	}
+
+	want := []struct {
+		principal string
+		role      Role
+		allowed   bool
+	}{{"olive", RoleOperator, true}, {"olive", RoleOperator, false}, {"alice", RoleAdmin, true}}
+	if len(events) != len(want) {
+		t.Fatalf("audited %d decisions, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if ev := events[i]; ev.Principal != w.principal || ev.Role != w.role || ev.Allowed != w.allowed || ev.Time.IsZero() {
+			t.Errorf("event %d = %+v, want %s (%s) allowed=%v", i, ev, w.principal, w.role, w.allowed)
+		}
+	}
+
+	if role := (&Policy{}).RoleOf("anyone"); role != RoleNone {
+		t.Errorf("zero policy role = %s, want none", role)
+	}
+}
@@ -0,0 +1,45 @@
+package authzgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestIdentity(t *testing.T) {
+	tokens := map[string]string{"s3cret": "alice"}
+	withToken := func(value string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", value))
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "node-a"}}
+	withCert := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}, PeerCertificates: []*x509.Certificate{cert}}},
+	})
+	withUnverifiedCert := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"bearer token", withToken("Bearer s3cret"), "alice"},
+		{"wrong token", withToken("Bearer guess"), ""},
+		{"no credentials", context.Background(), ""},
+		{"client certificate", withCert, "node-a"},
+		{"unverified client certificate", withUnverifiedCert, ""},
+	}
+	for _, tt := range tests {
+		got, err := Identity(tt.ctx, tokens)
+		if got != tt.want || (err == nil) != (tt.want != "") {
+			t.Errorf("%s: Identity() = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
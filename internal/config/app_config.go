@@ -0,0 +1,583 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"internal/admin"
+	"pkg/certreload"
+	"pkg/crashdump"
+	"pkg/errreport"
+	"pkg/featureflag"
+	"pkg/outbound"
+	"pkg/ratelimit"
+	"pkg/system"
+)
+
+// AppConfig is the unified configuration for an STS process: the telemetry service together with
+// the network servers it exposes.
+type AppConfig struct {
+	Telemetry TelemetryConfig `json:"telemetry" yaml:"telemetry"`
+
+	Health    ServerConfig `json:"health" yaml:"health"`
+	QueryAPI  ServerConfig `json:"query_api" yaml:"query_api"`
+	Admission ServerConfig `json:"admission" yaml:"admission"` // Admission webhook
+	Admin     ServerConfig `json:"admin" yaml:"admin"`
+
+	// Debug enables profiling and runtime stats endpoints on the admin server.
+	Debug DebugConfig `json:"debug,omitempty" yaml:"debug,omitempty"`
+
+	// Outbound decorates policy fetches and metrics scrapes with identifying headers.
+	Outbound OutboundConfig `json:"outbound,omitempty" yaml:"outbound,omitempty"`
+
+	Log LogConfig `json:"log,omitempty" yaml:"log,omitempty"`
+
+	ErrorReporting ErrorReportingConfig `json:"error_reporting,omitempty" yaml:"error_reporting,omitempty"`
+
+	// FeatureFlags roll out new behaviors per node or by percentage of nodes, keyed by flag name
+	// (see package featureflag). The governance policy document may override them.
+	FeatureFlags map[string]featureflag.Flag `json:"feature_flags,omitempty" yaml:"feature_flags,omitempty"`
+
+	// Preflight configures the startup checks run by Preflight.
+	Preflight PreflightConfig `json:"preflight,omitempty" yaml:"preflight,omitempty"`
+
+	// CrashBundle, if set, writes a crash bundle on panic or SIGABRT.
+	CrashBundle *CrashBundleConfig `json:"crash_bundle,omitempty" yaml:"crash_bundle,omitempty"`
+
+	// IncidentExport, if set, uploads an incident bundle to object storage on SIH escalation.
+	IncidentExport *IncidentExportConfig `json:"incident_export,omitempty" yaml:"incident_export,omitempty"`
+}
+
+// CrashBundleConfig configures crash bundles (see package crashdump), e.g.
+// {"dir": "/var/lib/sts/crash", "recent_records": 100}.
+type CrashBundleConfig struct {
+	Dir        string `json:"dir" yaml:"dir"`
+	MaxSizeMB  int    `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"` // Per bundle, default 4
+	MaxBundles int    `json:"max_bundles,omitempty" yaml:"max_bundles,omitempty"` // Default 5
+	// RecentRecords is the number of recent sink records included (default 50); negative omits them.
+	RecentRecords int `json:"recent_records,omitempty" yaml:"recent_records,omitempty"`
+}
+
+// defaultCrashRecentRecords is the number of sink records a crash bundle includes by default.
+const defaultCrashRecentRecords = 50
+
+// Validate checks the bundle settings without creating the directory.
+func (c CrashBundleConfig) Validate() error {
+	switch {
+	case c.Dir == "":
+		return errors.New("dir is required")
+	case c.MaxSizeMB < 0:
+		return errors.New("max_size_mb must not be negative")
+	case c.MaxBundles < 0:
+		return errors.New("max_bundles must not be negative")
+	}
+	return nil
+}
+
+// Recent returns the number of recent sink records to include, for telemetry.CrashSections.
+func (c CrashBundleConfig) Recent() int {
+	if c.RecentRecords == 0 {
+		return defaultCrashRecentRecords
+	}
+	return c.RecentRecords
+}
+
+// Dumper returns the configured crash bundle writer. Bundles are redacted with
+// crashdump.DefaultRedactionRules and, if masking is not nil, the governance masking rules it
+// returns, e.g. the MaskingRules of the governance state's GetPolicies.
+func (c CrashBundleConfig) Dumper(masking func() []string) (*crashdump.Dumper, error) {
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("crash_bundle: %w", err)
+	}
+	redactor := &system.Redactor{}
+	if err := redactor.SetRules(crashdump.DefaultRedactionRules); err != nil {
+		return nil, fmt.Errorf("crash_bundle: %w", err)
+	}
+	if masking != nil {
+		redactor.SetMaskingRules(masking)
+	}
+	return &crashdump.Dumper{
+		Dir:        c.Dir,
+		MaxBytes:   int64(c.MaxSizeMB) << 20,
+		MaxBundles: c.MaxBundles,
+		Redactor:   redactor,
+	}, nil
+}
+
+// Flags returns the feature flags for node, e.g. the "node" instance label, with the configured
+// flags installed as featureflag.SourceConfig.
+func (c *AppConfig) Flags(node string) (*featureflag.Set, error) {
+	flags := featureflag.New(node)
+	if err := flags.ReplaceSource(featureflag.SourceConfig, c.FeatureFlags); err != nil {
+		return nil, fmt.Errorf("feature_flags: %w", err)
+	}
+	return flags, nil
+}
+
+// DebugConfig enables the admin server's debug endpoints (see admin.Server.HandleDebug). They are
+// off by default; profiles require the admin role and are audited.
+type DebugConfig struct {
+	Pprof        bool `json:"pprof,omitempty" yaml:"pprof,omitempty"`
+	RuntimeStats bool `json:"runtime_stats,omitempty" yaml:"runtime_stats,omitempty"`
+	// MaxCPUProfile bounds a requested CPU profile (default 2m).
+	MaxCPUProfile time.Duration `json:"max_cpu_profile,omitempty" yaml:"max_cpu_profile,omitempty"`
+}
+
+// Validate checks the debug settings.
+func (d DebugConfig) Validate() error {
+	if d.MaxCPUProfile < 0 {
+		return errors.New("max_cpu_profile must not be negative")
+	}
+	return nil
+}
+
+// Options converts the debug settings into the admin server's representation.
+func (d DebugConfig) Options() admin.DebugOptions {
+	return admin.DebugOptions{Pprof: d.Pprof, RuntimeStats: d.RuntimeStats, MaxCPUProfile: d.MaxCPUProfile}
+}
+
+// ErrorReportingConfig sends faults of the service itself (panics, repeated collection failures,
+// policy load errors) to a Sentry-compatible error tracker. Reporting is disabled without a DSN.
+type ErrorReportingConfig struct {
+	SentryDSN   string `json:"sentry_dsn,omitempty" yaml:"sentry_dsn,omitempty"`
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Release     string `json:"release,omitempty" yaml:"release,omitempty"`
+	// AggregateWindow suppresses repeats of an identical fault for this long (default 10m).
+	AggregateWindow time.Duration `json:"aggregate_window,omitempty" yaml:"aggregate_window,omitempty"`
+}
+
+// Validate checks the DSN without contacting the tracker.
+func (e ErrorReportingConfig) Validate() error {
+	if e.AggregateWindow < 0 {
+		return errors.New("aggregate_window must not be negative")
+	}
+	if e.SentryDSN == "" {
+		return nil
+	}
+	_, err := errreport.NewSentry(e.SentryDSN)
+	return err
+}
+
+// Reporter returns the aggregating reporter for the configured tracker, or nil if reporting is
+// disabled. onError receives delivery failures and may be nil.
+func (e ErrorReportingConfig) Reporter(onError func(err error)) (errreport.ErrorReporter, error) {
+	if e.SentryDSN == "" {
+		return nil, nil
+	}
+	sentry, err := errreport.NewSentry(e.SentryDSN)
+	if err != nil {
+		return nil, err
+	}
+	sentry.Environment, sentry.Release, sentry.OnError = e.Environment, e.Release, onError
+	return &errreport.Aggregator{Next: sentry, Window: e.AggregateWindow}, nil
+}
+
+// LogConfig configures process logging.
+type LogConfig struct {
+	// Format is "text" (default), "json", or "logfmt".
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Output is where components log by default (stdout and stderr if unset).
+	Output LogOutputConfig `json:"output,omitempty" yaml:"output,omitempty"`
+	// Components route individual components elsewhere, e.g. {"sih": {"type": "journald"}}.
+	Components map[string]LogOutputConfig `json:"components,omitempty" yaml:"components,omitempty"`
+	// Level is the minimum level logged: "debug", "info" (default), "warn", or "error".
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// ComponentLevels set the level of individual components, e.g. {"sih": "debug"}. Both can be
+	// overridden at runtime through the admin API or control socket.
+	ComponentLevels map[string]string `json:"component_levels,omitempty" yaml:"component_levels,omitempty"`
+	// Redact replaces the built-in rules masking credentials in log messages, e.g. to add tenant
+	// identifiers. The governance masking rules can be applied in addition through the router's
+	// Redactor.
+	Redact []LogRedactionRule `json:"redact,omitempty" yaml:"redact,omitempty"`
+}
+
+// LogRedactionRule masks the matches of a regular expression in log messages.
+type LogRedactionRule struct {
+	Name    string `json:"name" yaml:"name"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// Replacement may reference submatches, e.g. "${1}[REDACTED]"; the default masks the whole match.
+	Replacement string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+}
+
+// Log output types.
+const (
+	LogOutputStdout   = "stdout"
+	LogOutputFile     = "file"
+	LogOutputSyslog   = "syslog"
+	LogOutputJournald = "journald"
+)
+
+// LogOutputConfig selects a log sink. Outputs with the same settings share one sink.
+type LogOutputConfig struct {
+	// Type is "stdout" (default), "file", "syslog", or "journald".
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// File output: rotated once it exceeds MaxSizeMB or is older than RotateEvery.
+	Path        string        `json:"path,omitempty" yaml:"path,omitempty"`
+	MaxSizeMB   int           `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"`
+	RotateEvery time.Duration `json:"rotate_every,omitempty" yaml:"rotate_every,omitempty"`
+	MaxBackups  int           `json:"max_backups,omitempty" yaml:"max_backups,omitempty"`
+
+	// Syslog output: the local daemon unless Network and Address are set, e.g. "udp" and
+	// "logs.example.internal:514". Tag (also the journald identifier) defaults to the program name.
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+	Address string `json:"address,omitempty" yaml:"address,omitempty"`
+	Tag     string `json:"tag,omitempty" yaml:"tag,omitempty"`
+}
+
+// Validate checks the output settings without opening anything.
+func (o LogOutputConfig) Validate() error {
+	switch o.Type {
+	case "", LogOutputStdout, LogOutputJournald:
+	case LogOutputFile:
+		if o.Path == "" {
+			return errors.New("file output requires path")
+		}
+		if o.MaxSizeMB < 0 || o.RotateEvery < 0 || o.MaxBackups < 0 {
+			return errors.New("max_size_mb, rotate_every and max_backups must not be negative")
+		}
+	case LogOutputSyslog:
+		if (o.Network == "") != (o.Address == "") {
+			return errors.New("syslog output requires both network and address, or neither")
+		}
+	default:
+		return fmt.Errorf("unknown output type '%s'", o.Type)
+	}
+	return nil
+}
+
+// sink opens the configured sink.
+func (o LogOutputConfig) sink() (system.Sink, error) {
+	switch o.Type {
+	case LogOutputFile:
+		return &system.RotatingFile{
+			Path:       o.Path,
+			MaxSize:    int64(o.MaxSizeMB) << 20,
+			MaxAge:     o.RotateEvery,
+			MaxBackups: o.MaxBackups,
+		}, nil
+	case LogOutputSyslog:
+		return system.NewSyslogSink(o.Network, o.Address, o.Tag)
+	case LogOutputJournald:
+		return system.NewJournaldSink(o.Tag)
+	}
+	return system.StdSink{}, nil
+}
+
+// Validate checks the log settings.
+func (l LogConfig) Validate() error {
+	if _, err := system.ParseFormat(l.Format); err != nil {
+		return err
+	}
+	if err := l.Output.Validate(); err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+	for component, o := range l.Components {
+		if err := o.Validate(); err != nil {
+			return fmt.Errorf("component %s: %w", component, err)
+		}
+	}
+	if _, _, err := l.levels(); err != nil {
+		return err
+	}
+	for i, rule := range l.Redact {
+		if rule.Name == "" {
+			return fmt.Errorf("redact[%d]: name is required", i)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("redact rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// redactionRules returns the configured redaction rules, or nil for the built-in ones.
+func (l LogConfig) redactionRules() []system.RedactionRule {
+	if len(l.Redact) == 0 {
+		return nil
+	}
+	rules := make([]system.RedactionRule, len(l.Redact))
+	for i, rule := range l.Redact {
+		rules[i] = system.RedactionRule{Name: rule.Name, Pattern: rule.Pattern, Replacement: rule.Replacement}
+	}
+	return rules
+}
+
+// levels parses the configured global and per-component levels.
+func (l LogConfig) levels() (system.Level, map[string]system.Level, error) {
+	global, err := system.ParseLevel(l.Level)
+	if err != nil {
+		return 0, nil, err
+	}
+	components := make(map[string]system.Level, len(l.ComponentLevels))
+	for component, name := range l.ComponentLevels {
+		level, err := system.ParseLevel(name)
+		if err != nil {
+			return 0, nil, fmt.Errorf("component %s: %w", component, err)
+		}
+		components[component] = level
+	}
+	return global, components, nil
+}
+
+// Router opens the configured sinks and returns the router handing out component loggers, with
+// the configured levels and redaction rules applied. Close it on shutdown.
+func (l LogConfig) Router() (*system.Router, error) {
+	format, err := system.ParseFormat(l.Format)
+	if err != nil {
+		return nil, err
+	}
+	global, levels, err := l.levels()
+	if err != nil {
+		return nil, err
+	}
+	opened := make(map[LogOutputConfig]system.Sink)
+	open := func(o LogOutputConfig) (system.Sink, error) {
+		if sink, ok := opened[o]; ok {
+			return sink, nil
+		}
+		sink, err := o.sink()
+		if err != nil {
+			return nil, err
+		}
+		opened[o] = sink
+		return sink, nil
+	}
+	closeAll := func() {
+		for _, sink := range opened {
+			sink.Close()
+		}
+	}
+
+	fallback, err := open(l.Output)
+	if err != nil {
+		return nil, fmt.Errorf("log output: %w", err)
+	}
+	components := make(map[string]system.Sink, len(l.Components))
+	for component, o := range l.Components {
+		sink, err := open(o)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("log output for %s: %w", component, err)
+		}
+		components[component] = sink
+	}
+	router := system.NewRouter(format, fallback, components)
+	router.Levels().Configure(global, levels)
+	if err := router.Redactor().SetRules(l.redactionRules()); err != nil {
+		router.Close()
+		return nil, err
+	}
+	return router, nil
+}
+
+// Outbound header variables, referenced in OutboundConfig values as ${name}.
+const (
+	VarNodeID        = "node_id"        // OutboundConfig.NodeID
+	VarPolicyVersion = "policy_version" // The applied governance policy version
+)
+
+// OutboundConfig sets the User-Agent and extra headers of outbound HTTP requests. Values may
+// reference ${node_id}, ${policy_version} and ${correlation_id}, e.g.
+// {"user_agent": "sts/1.4 (${node_id})", "headers": {"X-Policy-Version": "${policy_version}"}}.
+type OutboundConfig struct {
+	NodeID    string            `json:"node_id,omitempty" yaml:"node_id,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// PropagateTrace forwards the correlation ID as X-Correlation-ID and a W3C traceparent.
+	PropagateTrace bool `json:"propagate_trace,omitempty" yaml:"propagate_trace,omitempty"`
+}
+
+// Transport wraps base (http.DefaultTransport if nil) so every request carries the configured
+// headers. policyVersion supplies ${policy_version} and may be nil.
+func (o OutboundConfig) Transport(base http.RoundTripper, policyVersion func() string) *outbound.Transport {
+	if policyVersion == nil {
+		policyVersion = func() string { return "" }
+	}
+	nodeID := o.NodeID
+	return &outbound.Transport{
+		Base:      base,
+		UserAgent: o.UserAgent,
+		Headers:   o.Headers,
+		Vars: map[string]func() string{
+			VarNodeID:        func() string { return nodeID },
+			VarPolicyVersion: policyVersion,
+		},
+		Trace: o.PropagateTrace,
+	}
+}
+
+// Validate checks that header values reference only known variables.
+func (o OutboundConfig) Validate() error {
+	for name := range o.Headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("outbound: invalid header name '%s'", name)
+		}
+	}
+	return o.Transport(nil, nil).Validate()
+}
+
+// validHeaderName reports whether name is a non-empty HTTP token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c <= ' ' || c >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ServerConfig configures one listener. An empty ListenAddr disables the server.
+type ServerConfig struct {
+	ListenAddr string    `json:"listen_addr,omitempty" yaml:"listen_addr,omitempty"`
+	TLS        TLSConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	// RateLimit applies per-client limits to the server's endpoints; a zero rate disables limiting.
+	RateLimit RateLimitConfig `json:"rate_limit,omitempty" yaml:"rate_limit,omitempty"`
+}
+
+// Rate limit keys.
+const (
+	RateLimitByIP       = "ip"
+	RateLimitByIdentity = "identity" // Authenticated principal; requires authentication in front of the limiter
+)
+
+// RateLimitConfig configures a per-client token bucket.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty" yaml:"requests_per_second,omitempty"`
+	Burst             int     `json:"burst,omitempty" yaml:"burst,omitempty"`
+	// KeyBy selects "ip" (default) or "identity".
+	KeyBy string `json:"key_by,omitempty" yaml:"key_by,omitempty"`
+}
+
+// Enabled reports whether rate limiting is configured.
+func (r RateLimitConfig) Enabled() bool {
+	return r.RequestsPerSecond > 0
+}
+
+// Limiter creates the limiter for a server with rate limiting enabled.
+func (r RateLimitConfig) Limiter() *ratelimit.Limiter {
+	return ratelimit.New(ratelimit.Config{Rate: r.RequestsPerSecond, Burst: r.Burst})
+}
+
+// Validate checks the rate limit settings.
+func (r RateLimitConfig) Validate() error {
+	if r.RequestsPerSecond < 0 || r.Burst < 0 {
+		return errors.New("requests_per_second and burst must not be negative")
+	}
+	switch r.KeyBy {
+	case "", RateLimitByIP, RateLimitByIdentity:
+		return nil
+	default:
+		return fmt.Errorf("unknown key_by '%s'", r.KeyBy)
+	}
+}
+
+// TLSConfig enables TLS when CertFile and KeyFile are set. Certificates are reloaded from disk
+// when they change.
+type TLSConfig struct {
+	CertFile string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	// ClientCAFile enables client certificate verification against the bundle.
+	ClientCAFile      string        `json:"client_ca_file,omitempty" yaml:"client_ca_file,omitempty"`
+	RequireClientCert bool          `json:"require_client_cert,omitempty" yaml:"require_client_cert,omitempty"`
+	ReloadInterval    time.Duration `json:"reload_interval,omitempty" yaml:"reload_interval,omitempty"`
+}
+
+// Enabled reports whether TLS is configured.
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// Reloader creates the certificate reloader for a TLS-enabled server. Callers run it with
+// Run(ctx) and pass its TLSConfig() to the HTTP or gRPC server.
+func (t TLSConfig) Reloader(logger certreload.Logger) (*certreload.Reloader, error) {
+	return certreload.New(certreload.Options{
+		CertFile:          t.CertFile,
+		KeyFile:           t.KeyFile,
+		ClientCAFile:      t.ClientCAFile,
+		RequireClientCert: t.RequireClientCert,
+		PollInterval:      t.ReloadInterval,
+	}, logger)
+}
+
+// Validate checks the TLS settings without touching the files.
+func (t TLSConfig) Validate() error {
+	if !t.Enabled() {
+		if t.ClientCAFile != "" || t.RequireClientCert {
+			return errors.New("client certificate verification requires cert_file and key_file")
+		}
+		return nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return errors.New("both cert_file and key_file must be set")
+	}
+	if t.RequireClientCert && t.ClientCAFile == "" {
+		return errors.New("require_client_cert needs client_ca_file")
+	}
+	if t.ReloadInterval < 0 {
+		return errors.New("reload_interval must not be negative")
+	}
+	return nil
+}
+
+// Validate checks the telemetry configuration and every server's TLS and rate limit settings.
+func (c *AppConfig) Validate() error {
+	if err := c.Telemetry.Validate(); err != nil {
+		return err
+	}
+	servers := []struct {
+		name string
+		cfg  ServerConfig
+	}{{"health", c.Health}, {"query_api", c.QueryAPI}, {"admission", c.Admission}, {"admin", c.Admin}}
+	for _, s := range servers {
+		if err := s.cfg.TLS.Validate(); err != nil {
+			return fmt.Errorf("%s: tls: %w", s.name, err)
+		}
+		if err := s.cfg.RateLimit.Validate(); err != nil {
+			return fmt.Errorf("%s: rate_limit: %w", s.name, err)
+		}
+	}
+	if err := c.Log.Validate(); err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	if err := c.ErrorReporting.Validate(); err != nil {
+		return fmt.Errorf("error_reporting: %w", err)
+	}
+	if err := c.Debug.Validate(); err != nil {
+		return fmt.Errorf("debug: %w", err)
+	}
+	for name, f := range c.FeatureFlags {
+		if _, ok := featureflag.Defaults[name]; !ok {
+			return fmt.Errorf("feature_flags: unknown flag '%s'", name)
+		}
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("feature_flags: %s: %w", name, err)
+		}
+	}
+	if err := c.Preflight.Validate(); err != nil {
+		return fmt.Errorf("preflight: %w", err)
+	}
+	if c.CrashBundle != nil {
+		if err := c.CrashBundle.Validate(); err != nil {
+			return fmt.Errorf("crash_bundle: %w", err)
+		}
+	}
+	if c.IncidentExport != nil {
+		if err := c.IncidentExport.Validate(); err != nil {
+			return fmt.Errorf("incident_export: %w", err)
+		}
+	}
+	return c.Outbound.Validate()
+}
+
+// DefaultAppConfig returns the default telemetry configuration with every server disabled.
+func DefaultAppConfig() *AppConfig {
+	return &AppConfig{Telemetry: *DefaultTelemetryConfig()}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"core/governance"
+	"pkg/strictjson"
+	"src/cel_host"
+)
+
+// Embedded holds the configuration compiled into the binary, the lowest layer of Layers. The files
+// under embedded/ are defaults; appliance builds that may not read external files replace them
+// with their own before running go build.
+//
+//go:embed embedded/*.json
+var Embedded embed.FS
+
+// Names of the embedded documents in Layers.Embedded.
+const (
+	EmbeddedPolicyManifest   = "embedded/policy_manifest.json"
+	EmbeddedCELRuntimeConfig = "embedded/cel_runtime_config.json"
+	EmbeddedTelemetryConfig  = "embedded/telemetry_config.json"
+)
+
+// Environment variables holding a complete document, the highest layer of Layers.
+const (
+	EnvPolicyManifest   = "STS_POLICY_MANIFEST"
+	EnvCELRuntimeConfig = "STS_CEL_RUNTIME_CONFIG"
+	EnvTelemetryConfig  = "STS_TELEMETRY_CONFIG"
+)
+
+// Origin is the layer a document was loaded from.
+type Origin string
+
+const (
+	OriginEmbedded Origin = "embedded"
+	OriginFile     Origin = "file"
+	OriginEnv      Origin = "env"
+)
+
+// Layers resolves the policy manifest, the CEL runtime configuration and the telemetry
+// configuration from three layers: the documents embedded in the binary, files, and environment
+// variables, each overriding the one before. The zero value uses Embedded and the environment only.
+type Layers struct {
+	// Embedded is the embedded layer (default Embedded).
+	Embedded fs.FS
+	// File layer. An empty path, or one that does not exist, is skipped, so the same binary runs
+	// with or without the files.
+	PolicyManifestPath   string
+	CELRuntimeConfigPath string
+	TelemetryConfigPath  string
+	// Getenv looks up the environment layer (default os.Getenv).
+	Getenv func(key string) string
+}
+
+// layer is one resolved document.
+type layer struct {
+	origin Origin
+	source string // File path, embedded name, or environment variable
+	data   []byte
+}
+
+// resolve returns every present layer of a document, lowest first.
+func (l Layers) resolve(embedded, path, env string) ([]layer, error) {
+	var layers []layer
+	fsys := l.Embedded
+	if fsys == nil {
+		fsys = Embedded
+	}
+	data, err := fs.ReadFile(fsys, embedded)
+	switch {
+	case err == nil:
+		layers = append(layers, layer{OriginEmbedded, embedded, data})
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read embedded %s: %w", embedded, err)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			layers = append(layers, layer{OriginFile, path, data})
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	getenv := l.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	if v := getenv(env); v != "" {
+		layers = append(layers, layer{OriginEnv, env, []byte(v)})
+	}
+	return layers, nil
+}
+
+// top returns the highest present layer of a document that is replaced as a whole.
+func (l Layers) top(embedded, path, env string) (layer, error) {
+	layers, err := l.resolve(embedded, path, env)
+	if err != nil {
+		return layer{}, err
+	}
+	if len(layers) == 0 {
+		return layer{}, fmt.Errorf("%s is neither embedded, in a file, nor set in $%s", embedded, env)
+	}
+	return layers[len(layers)-1], nil
+}
+
+// PolicyEngine loads the admission engine from the highest layer defining the policy manifest.
+// Manifests are not merged: a file or environment manifest replaces the embedded one entirely.
+func (l Layers) PolicyEngine(opts ...governance.ManifestOption) (*governance.PolicyAdmissionEngine, Origin, error) {
+	top, err := l.top(EmbeddedPolicyManifest, l.PolicyManifestPath, EnvPolicyManifest)
+	if err != nil {
+		return nil, "", err
+	}
+	engine, err := governance.NewPolicyAdmissionEngineFromReader(bytes.NewReader(top.data), opts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("policy manifest from %s %s: %w", top.origin, top.source, err)
+	}
+	engine.ManifestPath = top.source
+	return engine, top.origin, nil
+}
+
+// CELRuntimeConfig loads the CEL runtime configuration from the highest layer defining it. Like
+// manifests, it is replaced rather than merged.
+func (l Layers) CELRuntimeConfig() (cel_host.RuntimeConfiguration, Origin, error) {
+	var cfg cel_host.RuntimeConfiguration
+	top, err := l.top(EmbeddedCELRuntimeConfig, l.CELRuntimeConfigPath, EnvCELRuntimeConfig)
+	if err != nil {
+		return cfg, "", err
+	}
+	if err := json.Unmarshal(top.data, &cfg); err != nil {
+		return cfg, "", fmt.Errorf("invalid CEL runtime configuration from %s %s: %w", top.origin, top.source, err)
+	}
+	return cfg, top.origin, nil
+}
+
+// TelemetryConfig decodes every layer of the telemetry configuration in turn over the defaults, so
+// a layer only needs the fields it overrides, and validates the result. strict is as for
+// DecodeTelemetryConfig.
+func (l Layers) TelemetryConfig(strict bool) (*TelemetryConfig, error) {
+	layers, err := l.resolve(EmbeddedTelemetryConfig, l.TelemetryConfigPath, EnvTelemetryConfig)
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultTelemetryConfig()
+	for _, ly := range layers {
+		if strict {
+			err = strictjson.Unmarshal(ly.data, cfg)
+		} else {
+			err = json.Unmarshal(ly.data, cfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode telemetry configuration from %s %s: %w", ly.origin, ly.source, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid telemetry configuration: %w", err)
+	}
+	return cfg, nil
+}
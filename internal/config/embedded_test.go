@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLayers(t *testing.T) {
+	embedded := fstest.MapFS{
+		EmbeddedTelemetryConfig: {Data: []byte(`{"monitor_interval": 2000000000, "gatm": {"max_breaches": 7}}`)},
+		EmbeddedPolicyManifest:  {Data: []byte(`{"schema_version": "V2.0-POLI-STRUCT", "policies": [{"id": "embedded"}]}`)},
+	}
+	file := filepath.Join(t.TempDir(), "telemetry.json")
+	if err := os.WriteFile(file, []byte(`{"monitor_interval": 3000000000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		EnvTelemetryConfig: `{"gatm": {"max_breaches": 9}}`,
+		EnvPolicyManifest:  `{"schema_version": "V2.0-POLI-STRUCT", "policies": [{"id": "env"}]}`,
+	}
+	l := Layers{
+		Embedded:            embedded,
+		TelemetryConfigPath: file,
+		PolicyManifestPath:  filepath.Join(t.TempDir(), "missing.json"),
+		Getenv:              func(key string) string { return env[key] },
+	}
+
+	cfg, err := l.TelemetryConfig(true)
+	if err != nil {
+		t.Fatalf("TelemetryConfig() error = %v", err)
+	}
+	if cfg.MonitorInterval != 3*time.Second || cfg.GATM.MaxBreaches != 9 || cfg.GATM.ResourceLoadThreshold != 0.95 {
+		t.Errorf("TelemetryConfig() = %v / %d / %v, want file interval, env breaches, default load threshold",
+			cfg.MonitorInterval, cfg.GATM.MaxBreaches, cfg.GATM.ResourceLoadThreshold)
+	}
+
+	engine, origin, err := l.PolicyEngine()
+	if err != nil {
+		t.Fatalf("PolicyEngine() error = %v", err)
+	}
+	if _, ok := engine.Policies["env"]; !ok || origin != OriginEnv || len(engine.Policies) != 1 {
+		t.Errorf("PolicyEngine() from %s = %v, want only the environment manifest", origin, engine.Policies)
+	}
+
+	delete(env, EnvPolicyManifest)
+	if engine, origin, err = l.PolicyEngine(); err != nil || origin != OriginEmbedded {
+		t.Errorf("PolicyEngine() from %s, error = %v; want the embedded manifest", origin, err)
+	}
+	if _, _, err := l.CELRuntimeConfig(); err == nil {
+		t.Error("CELRuntimeConfig() succeeded with no layer defining it")
+	}
+}
+
+func TestEmbeddedDefaults(t *testing.T) {
+	l := Layers{Getenv: func(string) string { return "" }}
+	if _, err := l.TelemetryConfig(true); err != nil {
+		t.Errorf("embedded telemetry configuration: %v", err)
+	}
+	if _, _, err := l.PolicyEngine(); err != nil {
+		t.Errorf("embedded policy manifest: %v", err)
+	}
+	if _, _, err := l.CELRuntimeConfig(); err != nil {
+		t.Errorf("embedded CEL runtime configuration: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"pkg/crashdump"
+	"pkg/incidentbundle"
+	"pkg/objstore"
+	"pkg/system"
+)
+
+// Incident export defaults.
+const (
+	defaultIncidentPrefix  = "incidents/"
+	defaultIncidentHistory = 500
+	defaultIncidentLogs    = 1000
+)
+
+// IncidentExportConfig configures incident bundles (see package incidentbundle), uploaded to
+// S3-compatible object storage on SIH escalation, e.g.
+// {"endpoint": "http://minio:9000", "bucket": "sts-incidents", "signing_key_file": "/etc/sts/incident.pem"}.
+type IncidentExportConfig struct {
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	Region   string `json:"region,omitempty" yaml:"region,omitempty"` // Default us-east-1
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	Prefix   string `json:"prefix,omitempty" yaml:"prefix,omitempty"` // Default "incidents/"
+	// VirtualHosted addresses the bucket as a subdomain of the endpoint, as AWS prefers; MinIO
+	// expects the default path-style addressing.
+	VirtualHosted bool `json:"virtual_hosted,omitempty" yaml:"virtual_hosted,omitempty"`
+	// AccessKeyID and SecretAccessKey default to AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+	AccessKeyID     string `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty" yaml:"secret_access_key,omitempty"`
+
+	// SigningKeyFile holds the PEM-encoded PKCS #8 Ed25519 private key signing each bundle, e.g.
+	// from "openssl genpkey -algorithm ed25519".
+	SigningKeyFile string `json:"signing_key_file" yaml:"signing_key_file"`
+	// KeyID names the signing key in bundle manifests (default the first 16 hex digits of the
+	// SHA-256 digest of the public key).
+	KeyID string `json:"key_id,omitempty" yaml:"key_id,omitempty"`
+
+	HistoryRecords int `json:"history_records,omitempty" yaml:"history_records,omitempty"` // Default 500
+	LogLines       int `json:"log_lines,omitempty" yaml:"log_lines,omitempty"`             // Default 1000
+	MaxSizeMB      int `json:"max_size_mb,omitempty" yaml:"max_size_mb,omitempty"`         // Per bundle before compression, default 16
+}
+
+// Validate checks the export settings without reading the signing key or contacting the store.
+func (c IncidentExportConfig) Validate() error {
+	if err := c.store().Validate(); err != nil {
+		return err
+	}
+	switch {
+	case c.SigningKeyFile == "":
+		return errors.New("signing_key_file is required")
+	case c.HistoryRecords < 0 || c.LogLines < 0 || c.MaxSizeMB < 0:
+		return errors.New("history_records, log_lines and max_size_mb must not be negative")
+	}
+	return nil
+}
+
+// History returns the number of telemetry records to include, for telemetry.IncidentSections.
+func (c IncidentExportConfig) History() int {
+	if c.HistoryRecords == 0 {
+		return defaultIncidentHistory
+	}
+	return c.HistoryRecords
+}
+
+// Logs returns the number of recent log lines to include, for system.Router.KeepTail.
+func (c IncidentExportConfig) Logs() int {
+	if c.LogLines == 0 {
+		return defaultIncidentLogs
+	}
+	return c.LogLines
+}
+
+func (c IncidentExportConfig) store() *objstore.S3 {
+	return &objstore.S3{
+		Endpoint:        c.Endpoint,
+		Region:          c.Region,
+		Bucket:          c.Bucket,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		VirtualHosted:   c.VirtualHosted,
+	}
+}
+
+// Exporter returns the configured incident bundle exporter for node, holding the "logs" section
+// if logs is not nil. The caller registers the remaining sections: telemetry.IncidentSections,
+// the governance module's PoliciesSection and context_probe.BundleSection. Bundles are redacted
+// like crash bundles; see CrashBundleConfig.Dumper for masking.
+func (c IncidentExportConfig) Exporter(node string, masking func() []string, logs *system.Tail) (*incidentbundle.Exporter, error) {
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("incident_export: %w", err)
+	}
+	key, err := loadEd25519Key(c.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("incident_export: %w", err)
+	}
+	keyID := c.KeyID
+	if keyID == "" {
+		sum := sha256.Sum256(key.Public().(ed25519.PublicKey))
+		keyID = hex.EncodeToString(sum[:8])
+	}
+	redactor := &system.Redactor{}
+	if err := redactor.SetRules(crashdump.DefaultRedactionRules); err != nil {
+		return nil, fmt.Errorf("incident_export: %w", err)
+	}
+	if masking != nil {
+		redactor.SetMaskingRules(masking)
+	}
+	prefix := c.Prefix
+	if prefix == "" {
+		prefix = defaultIncidentPrefix
+	}
+	e := &incidentbundle.Exporter{
+		Uploader: c.store(),
+		Node:     node,
+		Prefix:   prefix,
+		KeyID:    keyID,
+		Key:      key,
+		MaxBytes: int64(c.MaxSizeMB) << 20,
+		Redactor: redactor,
+	}
+	if logs != nil {
+		e.Register(incidentbundle.LogSection(logs))
+	}
+	return e, nil
+}
+
+// loadEd25519Key reads a PEM-encoded PKCS #8 Ed25519 private key.
+func loadEd25519Key(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not PEM-encoded", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+
+	"services/telemetry"
+)
+
+// PreflightConfig configures the checks Preflight runs before the service starts, e.g.
+// {"fail_fast": true, "degrade": ["sink"]}.
+type PreflightConfig struct {
+	// FailFast stops at the first failed check; the rest are reported as skipped.
+	FailFast bool `json:"fail_fast,omitempty" yaml:"fail_fast,omitempty"`
+	// Degrade names the components allowed to start degraded when their check fails: "sink",
+	// "metrics_endpoint" or "time_sync". Configuration, manifests, rules and the source always fail.
+	Degrade []string `json:"degrade,omitempty" yaml:"degrade,omitempty"`
+	// Timeout bounds each check (default 10s).
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// Components that PreflightConfig.Degrade may name.
+var degradableComponents = []string{"sink", "metrics_endpoint", "time_sync"}
+
+// Validate checks the degradable components.
+func (p PreflightConfig) Validate() error {
+	if p.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	for _, c := range p.Degrade {
+		if !slices.Contains(degradableComponents, c) {
+			return fmt.Errorf("component '%s' cannot degrade; use one of %v", c, degradableComponents)
+		}
+	}
+	return nil
+}
+
+// Preflight verifies, before Run begins, that the configuration is valid, the policy manifest
+// and CEL runtime configuration from layers parse, the GATM rules compile, the metrics endpoint
+// and time reference are reachable, the sink is available and the source can collect. Checks of
+// dependencies not supplied in deps are left out; the metrics endpoint is only checked with a
+// source, since the simulated source does not use it.
+//
+// The report lists every check. The error wraps telemetry.ErrPreflightFailed if a check failed
+// whose component may not degrade.
+func Preflight(ctx context.Context, c *AppConfig, layers Layers, deps STSDependencies) (telemetry.PreflightReport, error) {
+	checks := []telemetry.PreflightCheck{
+		{Component: "config", Name: "validate", Check: func(context.Context) error { return c.Validate() }},
+		{Component: "manifest", Name: "policy", Check: func(context.Context) error {
+			_, _, err := layers.PolicyEngine()
+			return err
+		}},
+		{Component: "manifest", Name: "cel_runtime", Check: func(context.Context) error {
+			_, _, err := layers.CELRuntimeConfig()
+			return err
+		}},
+		{Component: "gatm_rules", Name: "compile", Check: func(context.Context) error {
+			_, err := c.Telemetry.GATM.CompileRules()
+			return err
+		}},
+	}
+	if deps.Source != nil && c.Telemetry.MetricsEndpoint != "" {
+		checks = append(checks, telemetry.PreflightCheck{Component: "metrics_endpoint", Name: c.Telemetry.MetricsEndpoint, Check: func(ctx context.Context) error {
+			endpoints, err := c.Telemetry.MetricsEndpoints()
+			if err != nil {
+				return err
+			}
+			return endpoints.Do(ctx, func(endpoint string) error { return dialEndpoint(ctx, endpoint) })
+		}})
+	}
+	if ts := c.Telemetry.TimeSync; ts != nil {
+		checks = append(checks, telemetry.PreflightCheck{Component: "time_sync", Name: ts.Source, Check: func(ctx context.Context) error {
+			probe, err := ts.Probe()
+			if err != nil {
+				return err
+			}
+			_, err = probe.ClockOffset(ctx)
+			return err
+		}})
+	}
+	if deps.Sink != nil {
+		checks = append(checks, telemetry.SinkCheck(deps.Sink))
+	}
+	if deps.Source != nil {
+		checks = append(checks, telemetry.SourceCheck(deps.Source))
+	}
+
+	for i := range checks {
+		checks[i].Degrade = slices.Contains(c.Preflight.Degrade, checks[i].Component)
+	}
+	return telemetry.Preflight(ctx, checks, telemetry.PreflightOptions{FailFast: c.Preflight.FailFast, Timeout: c.Preflight.Timeout})
+}
+
+// dialEndpoint opens and closes a TCP connection to the host of an endpoint URL.
+func dialEndpoint(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
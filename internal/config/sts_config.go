@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+
+	"pkg/errreport"
+	"pkg/featureflag"
+	"pkg/metrics"
+	"services/telemetry"
+)
+
+// STSDependencies supplies the runtime collaborators of the telemetry service that are not part
+// of the configuration file. Every field is optional.
+type STSDependencies struct {
+	// Source collects raw metrics. Nil selects the simulated source.
+	Source     telemetry.TelemetrySource
+	Sink       telemetry.TelemetrySink
+	StateStore telemetry.BreachStateStore
+	Escalation *telemetry.EscalationRouter
+	// EscalationTargets are the named handlers referenced by EscalationRoutes (e.g. "sih", "pager").
+	// Routes are registered on Escalation, or on a new router if it is nil; build the configuration
+	// once per router so routes are not registered twice.
+	EscalationTargets map[string]telemetry.EscalationHandler
+	Metrics           metrics.Provider
+	// Silences receives the configured maintenance windows; a new registry is created if nil.
+	// Pass the registry shared with the governance module so both sources coexist.
+	Silences *telemetry.SilenceRegistry
+	// Labels are static instance labels (e.g., node, zone) matched by silences.
+	Labels map[string]string
+	// Lifecycle is the escalation state machine to drive, with any guards and hooks already
+	// registered. A new one is created if nil.
+	Lifecycle *telemetry.Lifecycle
+	// ThresholdAdmission vets runtime threshold changes, e.g. an *admission.ThresholdGate.
+	ThresholdAdmission telemetry.ThresholdAdmitter
+	// ErrorReporter receives source panics and repeated collection failures, e.g. from
+	// ErrorReportingConfig.Reporter.
+	ErrorReporter errreport.ErrorReporter
+	// Flags gates behaviors being rolled out, e.g. from AppConfig.Flags, shared with the
+	// governance module so the policy document can steer the rollout.
+	Flags *featureflag.Set
+}
+
+// STSConfiguration validates the configuration and converts it into the telemetry service's
+// representation, so the two types cannot drift.
+func (c *TelemetryConfig) STSConfiguration(deps STSDependencies) (telemetry.STSConfiguration, error) {
+	if err := c.Validate(); err != nil {
+		return telemetry.STSConfiguration{}, err
+	}
+
+	programs, err := c.GATM.CompileRules()
+	if err != nil {
+		return telemetry.STSConfiguration{}, err
+	}
+	rules := make([]telemetry.GATMRule, 0, len(programs))
+	for _, p := range programs {
+		rules = append(rules, p)
+	}
+
+	silences := deps.Silences
+	if silences == nil {
+		silences = telemetry.NewSilenceRegistry()
+	}
+	if err := silences.ReplaceSource(telemetry.SilenceSourceConfig, c.SilenceWindows()); err != nil {
+		return telemetry.STSConfiguration{}, fmt.Errorf("telemetry: %w", err)
+	}
+
+	escalation, err := c.escalationRouter(deps)
+	if err != nil {
+		return telemetry.STSConfiguration{}, err
+	}
+
+	sts := telemetry.STSConfiguration{
+		DefaultInterval:  c.MonitorInterval,
+		StartupSplay:     c.StartupSplay.Std(),
+		Jitter:           c.Jitter,
+		LatencyThreshold: c.GATM.S9LatencyThreshold,
+		LoadThreshold:    c.GATM.ResourceLoadThreshold,
+		MaxBreaches:      c.GATM.MaxBreaches,
+		MaxClockSkew:     c.GATM.MaxClockSkew.Std(),
+		Rules:            rules,
+		Silences:         silences,
+		Labels:           deps.Labels,
+		Sink:             deps.Sink,
+		SinkWriteTimeout: c.SinkWriteTimeout,
+		EscalationMode:   c.GATM.EscalationMode,
+		SlidingWindow:    c.GATM.SlidingWindow.Window(),
+		Escalation:       escalation,
+		StateStore:       deps.StateStore,
+		Metrics:          deps.Metrics,
+		Lifecycle:        deps.Lifecycle,
+
+		ThresholdAdmission: deps.ThresholdAdmission,
+		ErrorReporter:      deps.ErrorReporter,
+		Flags:              deps.Flags,
+	}
+	if c.TimeSync != nil {
+		probe, err := c.TimeSync.Probe()
+		if err != nil {
+			return telemetry.STSConfiguration{}, fmt.Errorf("telemetry: %w", err)
+		}
+		sts.Processors = append(sts.Processors, telemetry.ClockProcessor(probe))
+	}
+	if c.GATM.EscalationMode == telemetry.EscalationModeBurnRate {
+		detector, err := telemetry.NewBurnRateDetector(c.GATM.BurnRate.Detector(), deps.Sink)
+		if err != nil {
+			return telemetry.STSConfiguration{}, fmt.Errorf("gatm: %w", err)
+		}
+		sts.BurnRate = detector
+	}
+	return sts, nil
+}
+
+// NewSovereignTelemetryServiceFromConfig builds the telemetry service from configuration. A
+// configured fault injection script wraps the source.
+func NewSovereignTelemetryServiceFromConfig(c *TelemetryConfig, deps STSDependencies) (telemetry.STS, error) {
+	stsCfg, err := c.STSConfiguration(deps)
+	if err != nil {
+		return nil, err
+	}
+	src := deps.Source
+	if len(c.FaultInjection) > 0 {
+		if src == nil {
+			if src, err = telemetry.NewSimulatedTelemetrySource(telemetry.SimulationConfig{}); err != nil {
+				return nil, err
+			}
+		}
+		if src, err = telemetry.NewFaultInjector(src, c.FaultInjection); err != nil {
+			return nil, fmt.Errorf("telemetry: %w", err)
+		}
+	}
+	return telemetry.NewSovereignTelemetryService(stsCfg, src), nil
+}
+
+// escalationRouter registers the configured cause routes, resolving each target by name.
+func (c *TelemetryConfig) escalationRouter(deps STSDependencies) (*telemetry.EscalationRouter, error) {
+	if len(c.EscalationRoutes) == 0 {
+		return deps.Escalation, nil
+	}
+	// Resolve every target before registering any, so a bad name leaves a shared router untouched.
+	resolved := make([][]telemetry.EscalationHandler, len(c.EscalationRoutes))
+	for i, route := range c.EscalationRoutes {
+		for _, name := range route.Targets {
+			h, ok := deps.EscalationTargets[name]
+			if !ok {
+				return nil, fmt.Errorf("telemetry: escalation route for cause '%s' references unknown target '%s'", route.Cause, name)
+			}
+			resolved[i] = append(resolved[i], h)
+		}
+	}
+
+	router := deps.Escalation
+	if router == nil {
+		router = telemetry.NewEscalationRouter()
+	}
+	for i, route := range c.EscalationRoutes {
+		router.HandleCause(route.Cause, resolved[i]...)
+	}
+	return router, nil
+}
@@ -1,8 +1,19 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"time"
+
+	"internal/rrp"
+	"pkg/discovery"
+	"pkg/duration"
+	"pkg/strictjson"
+	"services/telemetry"
+	"src/cel_host"
 )
 
 // GATMConfig defines the parameters necessary for the Generalized Anomaly Threshold Model (GATM).
@@ -16,16 +27,219 @@ type GATMConfig struct {
 	
 	// MaxBreaches is the threshold for persistent breaches before RRP/SIH escalation.
 	MaxBreaches           int           `json:"max_breaches" yaml:"max_breaches"`
+
+	// MaxClockSkew breaches GATM (cause "clock_skew") when the probed clock offset exceeds it, e.g.
+	// "500ms". It requires TimeSync; zero disables the check.
+	MaxClockSkew duration.Duration `json:"max_clock_skew,omitempty" yaml:"max_clock_skew,omitempty"`
+
+	// Rules are additional CEL expressions over TelemetryData evaluated beyond the built-in thresholds.
+	Rules []GATMRuleConfig `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// DeterministicRules rejects rules calling nondeterministic host functions, so rule outcomes can
+	// be reproduced from their recorded inputs in audits.
+	DeterministicRules bool `json:"deterministic_rules,omitempty" yaml:"deterministic_rules,omitempty"`
+
+	// EscalationMode selects "consecutive" (default), "burn_rate", or "sliding_window" escalation.
+	EscalationMode string              `json:"escalation_mode,omitempty" yaml:"escalation_mode,omitempty"`
+	BurnRate       BurnRateConfig      `json:"burn_rate,omitempty" yaml:"burn_rate,omitempty"`
+	SlidingWindow  SlidingWindowConfig `json:"sliding_window,omitempty" yaml:"sliding_window,omitempty"`
+}
+
+// SlidingWindowConfig escalates when at least Ratio of the last Samples collections violated GATM.
+type SlidingWindowConfig struct {
+	Samples int     `json:"samples" yaml:"samples"`
+	Ratio   float64 `json:"ratio" yaml:"ratio"`
+}
+
+// Window converts the sliding-window settings into the telemetry service's representation.
+func (w SlidingWindowConfig) Window() telemetry.SlidingWindowConfig {
+	return telemetry.SlidingWindowConfig{Samples: w.Samples, Ratio: w.Ratio}
+}
+
+// BurnRateConfig defines the SLO objective and window pairs for burn-rate escalation.
+type BurnRateConfig struct {
+	Objective float64                `json:"objective" yaml:"objective"` // e.g., 0.99
+	Windows   []BurnRateWindowConfig `json:"windows" yaml:"windows"`     // Defaults to 1h/5m@14.4 and 6h/30m@6
+}
+
+// BurnRateWindowConfig pairs a long and short window with the burn-rate factor both must exceed.
+type BurnRateWindowConfig struct {
+	Long   time.Duration `json:"long" yaml:"long"`
+	Short  time.Duration `json:"short" yaml:"short"`
+	Factor float64       `json:"factor" yaml:"factor"`
+}
+
+// Detector converts the burn-rate settings into the telemetry service's representation.
+func (b BurnRateConfig) Detector() telemetry.BurnRateConfig {
+	windows := telemetry.DefaultBurnRateWindows()
+	if len(b.Windows) > 0 {
+		windows = make([]telemetry.BurnRateWindow, 0, len(b.Windows))
+		for _, w := range b.Windows {
+			windows = append(windows, telemetry.BurnRateWindow{Long: w.Long, Short: w.Short, Factor: w.Factor})
+		}
+	}
+	return telemetry.BurnRateConfig{Objective: b.Objective, Windows: windows}
+}
+
+// GATMRuleConfig defines a single CEL-based GATM rule. The expression must evaluate to true on breach,
+// e.g. `telemetry.pipeline_latency_s9 > 0.5 && telemetry.resource_load_pct > 0.7`.
+type GATMRuleConfig struct {
+	Name       string `json:"name" yaml:"name"`
+	Expression string `json:"expression" yaml:"expression"`
+	// CostLimit bounds the per-evaluation CEL cost. Zero applies cel_host.DefaultRuleCostLimit.
+	CostLimit uint64 `json:"cost_limit,omitempty" yaml:"cost_limit,omitempty"`
+}
+
+// CompileRules compiles every configured CEL rule, failing on the first invalid expression.
+func (g *GATMConfig) CompileRules() ([]*cel_host.RuleProgram, error) {
+	programs := make([]*cel_host.RuleProgram, 0, len(g.Rules))
+	seen := make(map[string]bool, len(g.Rules))
+	for _, rule := range g.Rules {
+		if rule.Name == "" {
+			return nil, errors.New("gatm: rule name must not be empty")
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("gatm: duplicate rule name '%s'", rule.Name)
+		}
+		seen[rule.Name] = true
+
+		program, err := cel_host.CompileRuleWithOptions(rule.Name, rule.Expression, cel_host.RuleOptions{
+			CostLimit:     rule.CostLimit,
+			Deterministic: g.DeterministicRules,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("gatm: %w", err)
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
 }
 
 // TelemetryConfig defines the generalized configuration necessary for STS operation.
 type TelemetryConfig struct {
 	Enabled         bool          `json:"enabled" yaml:"enabled"`
 	MonitorInterval time.Duration `json:"monitor_interval" yaml:"monitor_interval"`
+	// StartupSplay randomly delays the first collection by up to this long, e.g. "30s".
+	StartupSplay duration.Duration `json:"startup_splay,omitempty" yaml:"startup_splay,omitempty"`
+	// Jitter perturbs each monitor interval by up to this fraction (0.0 - 1.0), e.g. 0.1 for ±10%.
+	Jitter float64 `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	// SinkWriteTimeout bounds each telemetry sink write (default half the monitor interval); a
+	// negative value disables the bound.
+	SinkWriteTimeout time.Duration `json:"sink_write_timeout,omitempty" yaml:"sink_write_timeout,omitempty"`
 
 	GATM GATMConfig `json:"gatm" yaml:"gatm"` // Configuration for the Generalized Anomaly Threshold Model
 
-	MetricsEndpoint string `json:"metrics_endpoint" yaml:"metrics_endpoint"` // Source for raw metrics collection
+	// MetricsEndpoint is the source for raw metrics collection. It may be discovered instead of fixed,
+	// e.g. "srv+http://_monitord._tcp.example.internal/metrics" or "consul+http://monitord/metrics".
+	MetricsEndpoint string `json:"metrics_endpoint" yaml:"metrics_endpoint"`
+
+	// TimeSync probes the clock offset every collection, recording it in each snapshot.
+	TimeSync *TimeSyncConfig `json:"time_sync,omitempty" yaml:"time_sync,omitempty"`
+
+	// Silences are planned maintenance windows during which matching violations are not counted.
+	Silences []SilenceConfig `json:"silences,omitempty" yaml:"silences,omitempty"`
+
+	// FaultInjection scripts faults into collected telemetry for chaos drills and deterministic tests.
+	// It must be empty in production.
+	FaultInjection []telemetry.Fault `json:"fault_injection,omitempty" yaml:"fault_injection,omitempty"`
+
+	// EscalationRoutes send violations to responders by cause, in addition to severity-based handlers.
+	EscalationRoutes []EscalationRouteConfig `json:"escalation_routes,omitempty" yaml:"escalation_routes,omitempty"`
+
+	// RecoveryPlaybook defines the RRP recovery steps; build it with rrp.PlaybookConfig.Build.
+	RecoveryPlaybook *rrp.PlaybookConfig `json:"recovery_playbook,omitempty" yaml:"recovery_playbook,omitempty"`
+}
+
+// Time-sync probe sources.
+const (
+	TimeSyncChrony = "chrony"
+	TimeSyncNTP    = "ntp"
+)
+
+// TimeSyncConfig selects the clock probe: the local chronyd, e.g. {"source": "chrony"}, or an NTP
+// server queried directly, e.g. {"source": "ntp", "server": "time.example.internal"}.
+type TimeSyncConfig struct {
+	Source  string        `json:"source" yaml:"source"`
+	Server  string        `json:"server,omitempty" yaml:"server,omitempty"`
+	Command string        `json:"command,omitempty" yaml:"command,omitempty"` // chronyc path, default from PATH
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"` // NTP query timeout, default 2s
+}
+
+// Probe builds the configured clock probe.
+func (t TimeSyncConfig) Probe() (telemetry.ClockProbe, error) {
+	switch t.Source {
+	case TimeSyncChrony:
+		return telemetry.ChronyProbe{Command: t.Command}, nil
+	case TimeSyncNTP:
+		if t.Server == "" {
+			return nil, errors.New("time sync: ntp source requires a server")
+		}
+		return telemetry.NTPProbe{Server: t.Server, Timeout: t.Timeout}, nil
+	}
+	return nil, fmt.Errorf("time sync: unknown source '%s'", t.Source)
+}
+
+// EscalationRouteConfig routes violations with a matching cause to named escalation targets, e.g.
+// {"cause": "integrity", "targets": ["sih", "pager"]} or {"cause": "load", "targets": ["autoscaler"]}.
+// Cause is a violation cause ("latency", "load", "integrity", "rule:<name>") or a prefix ending in
+// "*" such as "rule:*". Targets are resolved through STSDependencies.EscalationTargets.
+type EscalationRouteConfig struct {
+	Cause   string   `json:"cause" yaml:"cause"`
+	Targets []string `json:"targets" yaml:"targets"`
+}
+
+// Validate checks that the route names a recognizable cause and at least one target.
+func (r EscalationRouteConfig) Validate() error {
+	switch {
+	case r.Cause == "":
+		return errors.New("escalation route cause must not be empty")
+	case len(r.Targets) == 0:
+		return fmt.Errorf("escalation route for cause '%s' has no targets", r.Cause)
+	}
+	switch r.Cause {
+	case telemetry.CauseLatency, telemetry.CauseLoad, telemetry.CauseIntegrity, telemetry.CauseClockSkew, telemetry.CauseClockJump:
+		return nil
+	}
+	if strings.HasSuffix(r.Cause, "*") || (strings.HasPrefix(r.Cause, telemetry.CauseRulePrefix) && len(r.Cause) > len(telemetry.CauseRulePrefix)) {
+		return nil
+	}
+	return fmt.Errorf("escalation route has unknown cause '%s'", r.Cause)
+}
+
+// SilenceConfig defines a maintenance window. Matchers select violations by label, including
+// the "cause" label (e.g., {"cause": "load"}); an empty matcher set silences every violation.
+type SilenceConfig struct {
+	Matchers map[string]string `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	StartsAt time.Time         `json:"starts_at" yaml:"starts_at"`
+	EndsAt   time.Time         `json:"ends_at" yaml:"ends_at"`
+	Comment  string            `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// MetricsEndpoints returns a failover caller over the instances MetricsEndpoint resolves to.
+func (c *TelemetryConfig) MetricsEndpoints() (*discovery.Failover, error) {
+	r, err := discovery.ParseURL(c.MetricsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+	return discovery.NewFailover(r, 0), nil
+}
+
+// SilenceWindows converts the configured silences for registration with telemetry.SilenceRegistry.
+func (c *TelemetryConfig) SilenceWindows() []telemetry.Silence {
+	silences := make([]telemetry.Silence, 0, len(c.Silences))
+	for _, sc := range c.Silences {
+		matchers := make([]telemetry.Matcher, 0, len(sc.Matchers))
+		for name, value := range sc.Matchers {
+			matchers = append(matchers, telemetry.Matcher{Name: name, Value: value})
+		}
+		silences = append(silences, telemetry.Silence{
+			Source:   telemetry.SilenceSourceConfig,
+			Matchers: matchers,
+			StartsAt: sc.StartsAt,
+			EndsAt:   sc.EndsAt,
+			Comment:  sc.Comment,
+		})
+	}
+	return silences
 }
 
 // Validate ensures that the telemetry configuration is sound before use.
@@ -33,6 +247,19 @@ func (c *TelemetryConfig) Validate() error {
 	if c.MonitorInterval <= 0 {
 		return errors.New("telemetry: monitor interval must be positive")
 	}
+	if c.StartupSplay < 0 {
+		return errors.New("telemetry: startup splay must not be negative")
+	}
+	if c.Jitter < 0 || c.Jitter > 1 {
+		return errors.New("telemetry: jitter must be between 0.0 and 1.0")
+	}
+	if c.SinkWriteTimeout > c.MonitorInterval {
+		return errors.New("telemetry: sink write timeout must not exceed the monitor interval")
+	}
+
+	if _, err := discovery.ParseURL(c.MetricsEndpoint); err != nil {
+		return fmt.Errorf("telemetry: %w", err)
+	}
 
 	if c.GATM.ResourceLoadThreshold <= 0.0 || c.GATM.ResourceLoadThreshold > 1.0 {
 		return errors.New("gatm: resource load threshold must be between (0.0, 1.0]")
@@ -43,6 +270,53 @@ func (c *TelemetryConfig) Validate() error {
 	if c.GATM.MaxBreaches <= 0 {
 		return errors.New("gatm: maximum breaches must be positive")
 	}
+	if c.GATM.MaxClockSkew < 0 {
+		return errors.New("gatm: maximum clock skew must not be negative")
+	}
+	if c.GATM.MaxClockSkew > 0 && c.TimeSync == nil {
+		return errors.New("gatm: maximum clock skew requires time_sync")
+	}
+	if c.TimeSync != nil {
+		if _, err := c.TimeSync.Probe(); err != nil {
+			return fmt.Errorf("telemetry: %w", err)
+		}
+	}
+	if _, err := c.GATM.CompileRules(); err != nil {
+		return err
+	}
+	switch c.GATM.EscalationMode {
+	case "", telemetry.EscalationModeConsecutive:
+	case telemetry.EscalationModeBurnRate:
+		if err := c.GATM.BurnRate.Detector().Validate(); err != nil {
+			return fmt.Errorf("gatm: %w", err)
+		}
+	case telemetry.EscalationModeSlidingWindow:
+		if err := c.GATM.SlidingWindow.Window().Validate(); err != nil {
+			return fmt.Errorf("gatm: %w", err)
+		}
+	default:
+		return fmt.Errorf("gatm: unknown escalation mode '%s'", c.GATM.EscalationMode)
+	}
+	for _, f := range c.FaultInjection {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("telemetry: %w", err)
+		}
+	}
+	for _, route := range c.EscalationRoutes {
+		if err := route.Validate(); err != nil {
+			return fmt.Errorf("telemetry: %w", err)
+		}
+	}
+	if c.RecoveryPlaybook != nil {
+		if err := c.RecoveryPlaybook.Validate(); err != nil {
+			return err
+		}
+	}
+	for i, sc := range c.Silences {
+		if !sc.EndsAt.After(sc.StartsAt) {
+			return fmt.Errorf("telemetry: silence %d must end after it starts", i)
+		}
+	}
 
 	return nil
 }
@@ -61,6 +335,26 @@ func DefaultTelemetryConfig() *TelemetryConfig {
 	}
 }
 
+// DecodeTelemetryConfig reads a JSON document from r over the defaults and validates the result.
+// In strict mode a field the schema does not define, such as a misspelled "s9_lattency_threshold",
+// is an error naming the field's path rather than being ignored.
+func DecodeTelemetryConfig(r io.Reader, strict bool) (*TelemetryConfig, error) {
+	cfg := DefaultTelemetryConfig()
+	var err error
+	if strict {
+		err = strictjson.Decode(r, cfg)
+	} else {
+		err = json.NewDecoder(r).Decode(cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode telemetry configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid telemetry configuration: %w", err)
+	}
+	return cfg, nil
+}
+
 // LoadTelemetryConfig loads the configuration for the STS.
 // This function now uses defaults and performs mandatory validation.
 func LoadTelemetryConfig() (*TelemetryConfig, error) {
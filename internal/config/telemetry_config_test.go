@@ -1,8 +1,13 @@
 package config
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"services/telemetry"
 )
 
 func TestTelemetryConfig_Validate(t *testing.T) {
@@ -50,6 +55,33 @@ func TestTelemetryConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid CEL Rule",
+			config: &TelemetryConfig{
+				MonitorInterval: 5 * time.Second,
+				GATM: GATMConfig{S9LatencyThreshold: 1 * time.Second, ResourceLoadThreshold: 0.5, MaxBreaches: 1,
+					Rules: []GATMRuleConfig{{Name: "slow-and-busy", Expression: "telemetry.pipeline_latency_s9 > 0.5 && telemetry.resource_load_pct > 0.7"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid CEL Rule (Syntax)",
+			config: &TelemetryConfig{
+				MonitorInterval: 5 * time.Second,
+				GATM: GATMConfig{S9LatencyThreshold: 1 * time.Second, ResourceLoadThreshold: 0.5, MaxBreaches: 1,
+					Rules: []GATMRuleConfig{{Name: "broken", Expression: "telemetry.pipeline_latency_s9 >"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid CEL Rule (Non-Bool)",
+			config: &TelemetryConfig{
+				MonitorInterval: 5 * time.Second,
+				GATM: GATMConfig{S9LatencyThreshold: 1 * time.Second, ResourceLoadThreshold: 0.5, MaxBreaches: 1,
+					Rules: []GATMRuleConfig{{Name: "numeric", Expression: "1 + 2"}}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -67,4 +99,126 @@ func TestDefaultTelemetryConfig(t *testing.T) {
 	if err := cfg.Validate(); err != nil {
 		t.Errorf("DefaultTelemetryConfig should always be valid, but got error: %v", err)
 	}
-}
\ No newline at end of file
+}
+func TestTelemetryConfig_STSConfiguration(t *testing.T) {
+	cfg := DefaultTelemetryConfig()
+	cfg.GATM.EscalationMode = "sliding_window"
+	cfg.GATM.SlidingWindow = SlidingWindowConfig{Samples: 10, Ratio: 0.5}
+
+	sts, err := cfg.STSConfiguration(STSDependencies{})
+	if err != nil {
+		t.Fatalf("STSConfiguration() error = %v", err)
+	}
+	if sts.LatencyThreshold != 800*time.Millisecond {
+		t.Errorf("LatencyThreshold = %v, want 800ms", sts.LatencyThreshold)
+	}
+	if sts.DefaultInterval != cfg.MonitorInterval || sts.LoadThreshold != 0.95 || sts.MaxBreaches != 5 {
+		t.Errorf("thresholds not carried over: %+v", sts)
+	}
+	if sts.SlidingWindow.Samples != 10 || sts.Silences == nil {
+		t.Errorf("escalation settings not carried over: %+v", sts)
+	}
+
+	cfg.GATM.EscalationMode = "burn_rate"
+	if _, err := cfg.STSConfiguration(STSDependencies{}); err == nil {
+		t.Error("burn-rate mode without a sink should fail")
+	}
+}
+
+func TestDecodeTelemetryConfig(t *testing.T) {
+	input := `{"monitor_interval": 2000000000, "gatm": {"s9_lattency_threshold": 500000000}}`
+
+	cfg, err := DecodeTelemetryConfig(strings.NewReader(input), false)
+	if err != nil {
+		t.Fatalf("lenient decode failed: %v", err)
+	}
+	if cfg.MonitorInterval != 2*time.Second || cfg.GATM.S9LatencyThreshold != 800*time.Millisecond {
+		t.Errorf("lenient decode = %v / %v, want overrides over defaults", cfg.MonitorInterval, cfg.GATM.S9LatencyThreshold)
+	}
+
+	_, err = DecodeTelemetryConfig(strings.NewReader(input), true)
+	if err == nil || !strings.Contains(err.Error(), `"gatm.s9_lattency_threshold"`) {
+		t.Errorf("strict decode error = %v, want unknown field gatm.s9_lattency_threshold", err)
+	}
+}
+
+func TestDecodeTelemetryConfig_Durations(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "String", input: `{"startup_splay": "30s"}`, want: 30 * time.Second},
+		{name: "Nanoseconds", input: `{"startup_splay": 30000000000}`, want: 30 * time.Second},
+		{name: "Invalid", input: `{"startup_splay": "thirty seconds"}`, wantErr: true},
+		{name: "Negative", input: `{"startup_splay": "-1s"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := DecodeTelemetryConfig(strings.NewReader(tt.input), true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeTelemetryConfig(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err == nil && cfg.StartupSplay.Std() != tt.want {
+				t.Errorf("StartupSplay = %v, want %v", cfg.StartupSplay, tt.want)
+			}
+		})
+	}
+
+	input := `{"time_sync": {"source": "chrony"}, "gatm": {"max_clock_skew": "500ms"}}`
+	cfg, err := DecodeTelemetryConfig(strings.NewReader(input), true)
+	if err != nil {
+		t.Fatalf("DecodeTelemetryConfig(%s) error = %v", input, err)
+	}
+	sts, err := cfg.STSConfiguration(STSDependencies{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GATM.MaxClockSkew.Std() != 500*time.Millisecond || sts.MaxClockSkew != 500*time.Millisecond {
+		t.Errorf("MaxClockSkew = %v, carried over as %v; want 500ms", cfg.GATM.MaxClockSkew, sts.MaxClockSkew)
+	}
+	if _, err := DecodeTelemetryConfig(strings.NewReader(`{"gatm": {"max_clock_skew": "half a second"}}`), true); err == nil {
+		t.Error("DecodeTelemetryConfig() with an invalid max_clock_skew succeeded")
+	}
+}
+
+func TestTelemetryConfig_EscalationRoutes(t *testing.T) {
+	var reached []string
+	target := func(name string) telemetry.EscalationHandler {
+		return telemetry.EscalationHandlerFunc(func(ctx context.Context, td telemetry.TelemetryData) error {
+			reached = append(reached, name)
+			return nil
+		})
+	}
+	deps := STSDependencies{EscalationTargets: map[string]telemetry.EscalationHandler{
+		"sih": target("sih"), "pager": target("pager"), "autoscaler": target("autoscaler"),
+	}}
+
+	cfg := DefaultTelemetryConfig()
+	cfg.EscalationRoutes = []EscalationRouteConfig{
+		{Cause: "integrity", Targets: []string{"sih", "pager"}},
+		{Cause: "load", Targets: []string{"autoscaler"}},
+		{Cause: "rule:*", Targets: []string{"pager"}},
+	}
+	sts, err := cfg.STSConfiguration(deps)
+	if err != nil {
+		t.Fatalf("STSConfiguration() error = %v", err)
+	}
+	td := telemetry.TelemetryData{Severity: telemetry.SeverityCritical, ViolationCauses: []string{"load", "rule:disk", "rule:queue"}}
+	if err := sts.Escalation.Dispatch(context.Background(), td); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if want := []string{"autoscaler", "pager"}; !reflect.DeepEqual(reached, want) {
+		t.Errorf("reached %v, want %v", reached, want)
+	}
+
+	cfg.EscalationRoutes = []EscalationRouteConfig{{Cause: "integrity", Targets: []string{"sms"}}}
+	if _, err := cfg.STSConfiguration(deps); err == nil {
+		t.Error("unknown escalation target should fail")
+	}
+	cfg.EscalationRoutes = []EscalationRouteConfig{{Cause: "lattency", Targets: []string{"pager"}}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("unknown escalation cause should fail validation")
+	}
+}
@@ -0,0 +1,82 @@
+package context_probe
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"core/governance"
+)
+
+// acceleratorVendors maps PCI vendor IDs of GPU and accelerator makers to names.
+var acceleratorVendors = map[string]string{
+	"0x10de": "nvidia",
+	"0x1002": "amd",
+	"0x8086": "intel",
+	"0x1da3": "habana",
+}
+
+// confidentialComputeDevices lists known parts with GPU confidential-computing support, by vendor
+// and device ID (NVIDIA Hopper family).
+var confidentialComputeDevices = map[string]map[string]bool{
+	"0x10de": {"0x2321": true, "0x2322": true, "0x2324": true, "0x2330": true, "0x2331": true, "0x2339": true, "0x2342": true},
+}
+
+// accelerators selects display and processing-accelerator functions from the PCI device list.
+// Virtual functions are skipped by requiring a known vendor and a physical device directory.
+func (c *Collector) accelerators(devices []governance.PCIDevice) []governance.Accelerator {
+	var out []governance.Accelerator
+	for _, dev := range devices {
+		var kind string
+		switch dev.ClassName {
+		case "display":
+			kind = "gpu"
+		case "accelerator", "processor":
+			kind = "accelerator"
+		default:
+			continue
+		}
+		vendor, ok := acceleratorVendors[dev.Vendor]
+		if !ok {
+			continue
+		}
+		dir := filepath.Join(c.SysfsRoot, "bus/pci/devices", dev.Address)
+		if exists(filepath.Join(dir, "physfn")) {
+			continue
+		}
+
+		acc := governance.Accelerator{
+			Address:             dev.Address,
+			Kind:                kind,
+			Vendor:              vendor,
+			DeviceID:            dev.Device,
+			MemoryBytes:         c.acceleratorMemory(dir),
+			ConfidentialCompute: confidentialComputeDevices[dev.Vendor][dev.Device],
+		}
+		switch {
+		case vendor == "nvidia" && c.migCapable():
+			acc.Partitioning = governance.PartitioningMIG
+		case dev.SRIOVTotalVFs > 0:
+			acc.Partitioning = governance.PartitioningSRIOV
+		}
+		out = append(out, acc)
+	}
+	return out
+}
+
+// acceleratorMemory reads device memory where the driver exposes it (amdgpu, xe/i915 via the
+// lmem attribute). Other drivers report zero.
+func (c *Collector) acceleratorMemory(dir string) uint64 {
+	for _, attr := range []string{"mem_info_vram_total", "lmem_total_bytes"} {
+		if s, err := readTrimmed(filepath.Join(dir, attr)); err == nil {
+			if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// migCapable reports whether the NVIDIA driver exposes MIG capabilities.
+func (c *Collector) migCapable() bool {
+	return exists(filepath.Join(c.ProcRoot, "driver/nvidia/capabilities/mig"))
+}
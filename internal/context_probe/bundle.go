@@ -0,0 +1,92 @@
+package context_probe
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"core/governance"
+	"pkg/crashdump"
+)
+
+// CollectorVersion identifies the probe logic that produced a context, so auditors can tell which
+// detection rules were in effect.
+const CollectorVersion = "1.0.0"
+
+// ErrBundleSignature is returned when a context bundle fails signature verification.
+var ErrBundleSignature = errors.New("context bundle signature invalid")
+
+// ContextBundle is a signed, timestamped SystemContext that can be shipped to a central admission
+// service for offline evaluation and retained for audit. Context holds the exact bytes that were
+// signed, so the bundle survives re-encoding unchanged.
+type ContextBundle struct {
+	NodeID           string          `json:"node_id"`
+	CollectedAt      time.Time       `json:"collected_at"`
+	CollectorVersion string          `json:"collector_version"`
+	Context          json.RawMessage `json:"context"`
+	KeyID            string          `json:"key_id"`
+	Signature        []byte          `json:"signature"`
+}
+
+// NewContextBundle serializes and signs sc on behalf of nodeID.
+func NewContextBundle(nodeID string, sc governance.SystemContext, collectedAt time.Time, keyID string, key ed25519.PrivateKey) (*ContextBundle, error) {
+	raw, err := json.Marshal(sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode system context: %w", err)
+	}
+	b := &ContextBundle{
+		NodeID:           nodeID,
+		CollectedAt:      collectedAt.UTC(),
+		CollectorVersion: CollectorVersion,
+		Context:          raw,
+		KeyID:            keyID,
+	}
+	payload, err := b.signingPayload()
+	if err != nil {
+		return nil, err
+	}
+	b.Signature = ed25519.Sign(key, payload)
+	return b, nil
+}
+
+// signingPayload covers every field except the signature itself.
+func (b *ContextBundle) signingPayload() ([]byte, error) {
+	unsigned := *b
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode context bundle: %w", err)
+	}
+	return payload, nil
+}
+
+// Verify checks the signature against pub and returns the decoded SystemContext.
+func (b *ContextBundle) Verify(pub ed25519.PublicKey) (governance.SystemContext, error) {
+	var sc governance.SystemContext
+	payload, err := b.signingPayload()
+	if err != nil {
+		return sc, err
+	}
+	if !ed25519.Verify(pub, payload, b.Signature) {
+		return sc, fmt.Errorf("%w: node %s, key %s", ErrBundleSignature, b.NodeID, b.KeyID)
+	}
+	if err := json.Unmarshal(b.Context, &sc); err != nil {
+		return sc, fmt.Errorf("failed to decode system context: %w", err)
+	}
+	return sc, nil
+}
+
+// BundleSection returns the incident bundle section "context": a context bundle collected by c
+// when the incident is exported, signed on behalf of nodeID.
+func BundleSection(c *Collector, nodeID, keyID string, key ed25519.PrivateKey) crashdump.Section {
+	return crashdump.Section{Name: "context", Collect: func(ctx context.Context) (interface{}, error) {
+		sc, err := c.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewContextBundle(nodeID, sc, time.Now(), keyID, key)
+	}}
+}
@@ -0,0 +1,41 @@
+package context_probe
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"core/governance"
+)
+
+func TestContextBundleRoundTrip(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := governance.SystemContext{Hardware: governance.HardwareContext{CPUArchitecture: "amd64", MemoryBytes: 1 << 30}}
+	b, err := NewContextBundle("node-a", sc, time.Now(), "k1", key)
+	if err != nil {
+		t.Fatalf("NewContextBundle() error = %v", err)
+	}
+
+	encoded, _ := json.Marshal(b)
+	var decoded ContextBundle
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.Verify(pub)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Hardware.MemoryBytes != sc.Hardware.MemoryBytes {
+		t.Errorf("Verify() context = %+v, want %+v", got, sc)
+	}
+
+	decoded.NodeID = "node-b"
+	if _, err := decoded.Verify(pub); !errors.Is(err, ErrBundleSignature) {
+		t.Errorf("Verify() after tampering error = %v, want ErrBundleSignature", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package context_probe discovers the governance.SystemContext of the local host from sysfs and
+// procfs, replacing manually supplied capability flags.
+package context_probe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"core/governance"
+)
+
+// Collector probes the host. Roots are configurable so tests can point at a fake tree.
+type Collector struct {
+	SysfsRoot string
+	ProcRoot  string
+	DevRoot   string
+}
+
+// NewCollector creates a collector for the live host.
+func NewCollector() *Collector {
+	return &Collector{SysfsRoot: "/sys", ProcRoot: "/proc", DevRoot: "/dev"}
+}
+
+// Collect assembles the SystemContext. Probes for optional hardware degrade to "absent" rather
+// than failing; only unreadable core facts (memory, kernel version) return an error.
+func (c *Collector) Collect(ctx context.Context) (governance.SystemContext, error) {
+	var sc governance.SystemContext
+	sc.Hardware.CPUArchitecture = runtime.GOARCH
+
+	mem, err := c.memoryBytes()
+	if err != nil {
+		return sc, fmt.Errorf("failed to probe memory: %w", err)
+	}
+	sc.Hardware.MemoryBytes = mem
+
+	release, err := readTrimmed(filepath.Join(c.ProcRoot, "sys/kernel/osrelease"))
+	if err != nil {
+		return sc, fmt.Errorf("failed to probe kernel version: %w", err)
+	}
+	sc.OS.KernelVersion = release
+	c.hardening(&sc.OS)
+
+	if ctx.Err() != nil {
+		return sc, ctx.Err()
+	}
+
+	sc.Hardware.TEE = c.tee()
+	sc.Hardware.PCIDevices = c.pciDevices()
+	sc.Hardware.Accelerators = c.accelerators(sc.Hardware.PCIDevices)
+	sc.Hardware.IOMMU = c.iommu()
+	for _, dev := range sc.Hardware.PCIDevices {
+		if dev.SRIOVTotalVFs > 0 {
+			sc.Hardware.SR_IOV_Enabled = true
+			break
+		}
+	}
+	return sc, nil
+}
+
+// memoryBytes reads MemTotal from /proc/meminfo.
+func (c *Collector) memoryBytes() (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(c.ProcRoot, "meminfo"))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("malformed MemTotal '%s': %w", fields[1], err)
+			}
+			return kb * 1024, nil
+		}
+	}
+	return 0, fmt.Errorf("MemTotal not found in meminfo")
+}
+
+func readTrimmed(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// readInt returns the integer content of path, or 0 if it is missing or malformed.
+func readInt(path string) int {
+	s, err := readTrimmed(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
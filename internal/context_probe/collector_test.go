@@ -0,0 +1,144 @@
+package context_probe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"core/governance"
+)
+
+// writeTree creates files (relative path -> content) under root; values starting with "->" become symlinks.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		var err error
+		if len(content) > 2 && content[:2] == "->" {
+			err = os.Symlink(content[2:], path)
+		} else {
+			err = os.WriteFile(path, []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCollectPCIAndIOMMU(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/meminfo":                                         "MemTotal:       16384 kB\nMemFree: 1 kB\n",
+		"proc/sys/kernel/osrelease":                            "6.1.0\n",
+		"sys/kernel/iommu_groups/7/type":                       "DMA\n",
+		"sys/bus/pci/devices/0000:3b:00.0/class":               "0x020000\n",
+		"sys/bus/pci/devices/0000:3b:00.0/vendor":              "0x8086\n",
+		"sys/bus/pci/devices/0000:3b:00.0/sriov_totalvfs":      "64\n",
+		"sys/bus/pci/devices/0000:3b:00.0/sriov_numvfs":        "8\n",
+		"sys/bus/pci/devices/0000:3b:00.0/iommu_group":         "->../../../../kernel/iommu_groups/7",
+		"sys/bus/pci/devices/0000:00:02.0/class":               "0x030000\n",
+		"sys/bus/pci/devices/0000:00:02.0/vendor":              "0x1002\n",
+		"sys/bus/pci/devices/0000:00:02.0/mem_info_vram_total": "17163091968\n",
+		"sys/bus/pci/devices/0000:c1:00.0/class":               "0x030200\n",
+		"sys/bus/pci/devices/0000:c1:00.0/vendor":              "0x10de\n",
+		"sys/bus/pci/devices/0000:c1:00.0/device":              "0x2330\n",
+		"proc/driver/nvidia/capabilities/mig/config":           "DeviceFileMinor: 1\n",
+	})
+
+	c := &Collector{SysfsRoot: filepath.Join(root, "sys"), ProcRoot: filepath.Join(root, "proc"), DevRoot: filepath.Join(root, "dev")}
+	sc, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	hw := sc.Hardware
+	if hw.MemoryBytes != 16384*1024 || sc.OS.KernelVersion != "6.1.0" {
+		t.Errorf("MemoryBytes = %d, KernelVersion = %q", hw.MemoryBytes, sc.OS.KernelVersion)
+	}
+	if !hw.SR_IOV_Enabled || !hw.IOMMU.Enabled || hw.IOMMU.Groups != 1 {
+		t.Errorf("SR_IOV_Enabled = %v, IOMMU = %+v", hw.SR_IOV_Enabled, hw.IOMMU)
+	}
+	if len(hw.PCIDevices) != 3 {
+		t.Fatalf("PCIDevices = %+v, want 3 devices", hw.PCIDevices)
+	}
+	nic := hw.PCIDevices[1]
+	if nic.ClassName != "network" || nic.SRIOVTotalVFs != 64 || nic.SRIOVNumVFs != 8 || nic.IOMMUGroup != "7" {
+		t.Errorf("NIC = %+v", nic)
+	}
+	if hw.PCIDevices[0].ClassName != "display" {
+		t.Errorf("GPU class = %q, want display", hw.PCIDevices[0].ClassName)
+	}
+
+	want := []governance.Accelerator{
+		{Address: "0000:00:02.0", Kind: "gpu", Vendor: "amd", MemoryBytes: 17163091968},
+		{Address: "0000:c1:00.0", Kind: "gpu", Vendor: "nvidia", DeviceID: "0x2330", Partitioning: governance.PartitioningMIG, ConfidentialCompute: true},
+	}
+	if len(hw.Accelerators) != len(want) {
+		t.Fatalf("Accelerators = %+v, want %+v", hw.Accelerators, want)
+	}
+	for i := range want {
+		if hw.Accelerators[i] != want[i] {
+			t.Errorf("Accelerators[%d] = %+v, want %+v", i, hw.Accelerators[i], want[i])
+		}
+	}
+}
+
+func TestCollectTEE(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  governance.TEEInfo
+	}{
+		{"None", nil, governance.TEEInfo{}},
+		{"SGX2 With Provisioning", map[string]string{
+			"proc/cpuinfo":      "processor\t: 0\nflags\t\t: fpu sgx sgx_lc\n",
+			"dev/sgx_enclave":   "",
+			"dev/sgx_provision": "",
+		}, governance.TEEInfo{Supported: true, Technology: governance.TEETechnologySGX, Version: "2", Attestation: true}},
+		{"SEV-SNP Guest", map[string]string{"dev/sev-guest": ""},
+			governance.TEEInfo{Supported: true, Technology: governance.TEETechnologySEVSNP, Attestation: true}},
+		{"TDX Host", map[string]string{"sys/module/kvm_intel/parameters/tdx": "Y\n"},
+			governance.TEEInfo{Supported: true, Technology: governance.TEETechnologyTDX, Version: "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.files)
+			c := &Collector{SysfsRoot: filepath.Join(root, "sys"), ProcRoot: filepath.Join(root, "proc"), DevRoot: filepath.Join(root, "dev")}
+			if got := c.tee(); got != tt.want {
+				t.Errorf("tee() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectHardening(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/meminfo":                           "MemTotal: 1024 kB\n",
+		"proc/sys/kernel/osrelease":              "6.8.0\n",
+		"proc/cmdline":                           "BOOT_IMAGE=/vmlinuz ro lockdown=integrity nosmt iommu=force\n",
+		"sys/kernel/security/lockdown":           "none [integrity] confidentiality\n",
+		"sys/fs/selinux/enforce":                 "1",
+		"sys/module/apparmor/parameters/enabled": "N\n",
+		"sys/" + secureBootVar:                   "\x06\x00\x00\x00\x01",
+	})
+
+	c := &Collector{SysfsRoot: filepath.Join(root, "sys"), ProcRoot: filepath.Join(root, "proc"), DevRoot: filepath.Join(root, "dev")}
+	sc, err := c.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	osc := sc.OS
+	if osc.Lockdown != governance.LockdownIntegrity || !osc.SecureBoot || osc.SELinux != "enforcing" || osc.AppArmor {
+		t.Errorf("OS = %+v", osc)
+	}
+	if v, ok := osc.KernelCmdline["nosmt"]; !ok || v != "" || osc.KernelCmdline["iommu"] != "force" {
+		t.Errorf("KernelCmdline = %v", osc.KernelCmdline)
+	}
+}
@@ -0,0 +1,66 @@
+package context_probe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"core/governance"
+)
+
+// secureBootVar is the EFI global variable holding the Secure Boot state.
+const secureBootVar = "firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-e0398c3a3f8c"
+
+// hardening fills the kernel lockdown, Secure Boot, LSM, and boot parameter fields of osc.
+func (c *Collector) hardening(osc *governance.OSContext) {
+	osc.Lockdown = c.lockdown()
+	osc.SecureBoot = c.secureBoot()
+	osc.SELinux = c.selinux()
+	if v, err := readTrimmed(filepath.Join(c.SysfsRoot, "module/apparmor/parameters/enabled")); err == nil {
+		osc.AppArmor = v == "Y"
+	}
+	if cmdline, err := readTrimmed(filepath.Join(c.ProcRoot, "cmdline")); err == nil {
+		osc.KernelCmdline = parseCmdline(cmdline)
+	}
+}
+
+// lockdown returns the bracketed mode from e.g. "none [integrity] confidentiality".
+func (c *Collector) lockdown() string {
+	v, err := readTrimmed(filepath.Join(c.SysfsRoot, "kernel/security/lockdown"))
+	if err != nil {
+		return ""
+	}
+	for _, mode := range strings.Fields(v) {
+		if strings.HasPrefix(mode, "[") && strings.HasSuffix(mode, "]") {
+			return strings.Trim(mode, "[]")
+		}
+	}
+	return ""
+}
+
+// secureBoot reads the EFI variable: four attribute bytes followed by a one-byte state.
+func (c *Collector) secureBoot() bool {
+	data, err := os.ReadFile(filepath.Join(c.SysfsRoot, secureBootVar))
+	return err == nil && len(data) >= 5 && data[4] == 1
+}
+
+func (c *Collector) selinux() string {
+	switch v, err := readTrimmed(filepath.Join(c.SysfsRoot, "fs/selinux/enforce")); {
+	case err != nil:
+		return "disabled"
+	case v == "1":
+		return "enforcing"
+	default:
+		return "permissive"
+	}
+}
+
+// parseCmdline splits boot parameters into key/value pairs. Later occurrences win, as in the kernel.
+func parseCmdline(cmdline string) map[string]string {
+	params := make(map[string]string)
+	for _, field := range strings.Fields(cmdline) {
+		key, value, _ := strings.Cut(field, "=")
+		params[key] = strings.Trim(value, `"`)
+	}
+	return params
+}
@@ -0,0 +1,80 @@
+package context_probe
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"core/governance"
+)
+
+// pciClassNames maps PCI base class codes to readable names.
+var pciClassNames = map[uint64]string{
+	0x01: "storage",
+	0x02: "network",
+	0x03: "display",
+	0x04: "multimedia",
+	0x06: "bridge",
+	0x08: "system",
+	0x0b: "processor",
+	0x0c: "serial_bus",
+	0x10: "encryption",
+	0x12: "accelerator",
+}
+
+// pciDevices enumerates /sys/bus/pci/devices. A missing PCI bus (e.g., in a container) yields nil.
+func (c *Collector) pciDevices() []governance.PCIDevice {
+	root := filepath.Join(c.SysfsRoot, "bus/pci/devices")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	devices := make([]governance.PCIDevice, 0, len(entries))
+	for _, e := range entries {
+		dir := filepath.Join(root, e.Name())
+		dev := governance.PCIDevice{Address: e.Name()}
+		dev.Class, _ = readTrimmed(filepath.Join(dir, "class"))
+		dev.Vendor, _ = readTrimmed(filepath.Join(dir, "vendor"))
+		dev.Device, _ = readTrimmed(filepath.Join(dir, "device"))
+		dev.ClassName = pciClassName(dev.Class)
+		dev.SRIOVTotalVFs = readInt(filepath.Join(dir, "sriov_totalvfs"))
+		dev.SRIOVNumVFs = readInt(filepath.Join(dir, "sriov_numvfs"))
+		if target, err := os.Readlink(filepath.Join(dir, "iommu_group")); err == nil {
+			dev.IOMMUGroup = filepath.Base(target)
+		}
+		devices = append(devices, dev)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Address < devices[j].Address })
+	return devices
+}
+
+// pciClassName decodes the base class from a 24-bit class code such as "0x020000".
+func pciClassName(class string) string {
+	code, err := strconv.ParseUint(strings.TrimPrefix(class, "0x"), 16, 32)
+	if err != nil {
+		return "unknown"
+	}
+	if name, ok := pciClassNames[code>>16]; ok {
+		return name
+	}
+	return "other"
+}
+
+// iommu reports DMA remapping support from the kernel's IOMMU group and device listings.
+func (c *Collector) iommu() governance.IOMMUInfo {
+	var info governance.IOMMUInfo
+	if groups, err := os.ReadDir(filepath.Join(c.SysfsRoot, "kernel/iommu_groups")); err == nil {
+		info.Groups = len(groups)
+	}
+	if units, err := os.ReadDir(filepath.Join(c.SysfsRoot, "class/iommu")); err == nil && len(units) > 0 {
+		info.Enabled = true
+	}
+	// Some platforms expose groups without populating /sys/class/iommu.
+	if info.Groups > 0 {
+		info.Enabled = true
+	}
+	return info
+}
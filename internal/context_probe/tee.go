@@ -0,0 +1,60 @@
+package context_probe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"core/governance"
+)
+
+// tee detects the platform's TEE from device nodes, kernel module parameters, and CPU flags.
+// Guest-side device nodes are checked first since they also indicate attestation capability.
+func (c *Collector) tee() governance.TEEInfo {
+	flags := c.cpuFlags()
+	dev := func(name string) bool { return exists(filepath.Join(c.DevRoot, name)) }
+	param := func(module, name string) bool {
+		v, err := readTrimmed(filepath.Join(c.SysfsRoot, "module", module, "parameters", name))
+		return err == nil && (v == "Y" || v == "1")
+	}
+
+	switch {
+	case dev("tdx_guest") || flags["tdx_guest"]:
+		return governance.TEEInfo{Supported: true, Technology: governance.TEETechnologyTDX, Version: "1", Attestation: dev("tdx_guest")}
+	case dev("sev-guest") || flags["sev_snp"] || param("kvm_amd", "sev_snp"):
+		return governance.TEEInfo{Supported: true, Technology: governance.TEETechnologySEVSNP, Attestation: dev("sev-guest")}
+	case param("kvm_intel", "tdx"):
+		return governance.TEEInfo{Supported: true, Technology: governance.TEETechnologyTDX, Version: "1"}
+	case dev("sgx_enclave") || flags["sgx"]:
+		info := governance.TEEInfo{Supported: true, Technology: governance.TEETechnologySGX, Version: "1"}
+		// Flexible launch control is required for SGX2-era enclaves and DCAP attestation.
+		if flags["sgx_lc"] {
+			info.Version = "2"
+		}
+		info.Attestation = dev("sgx_provision")
+		return info
+	case dev("tee0") || exists(filepath.Join(c.SysfsRoot, "class/tee/tee0")):
+		return governance.TEEInfo{Supported: true, Technology: governance.TEETechnologyTrustZone}
+	}
+	return governance.TEEInfo{}
+}
+
+// cpuFlags returns the flag set of the first processor in /proc/cpuinfo.
+func (c *Collector) cpuFlags() map[string]bool {
+	flags := make(map[string]bool)
+	data, err := os.ReadFile(filepath.Join(c.ProcRoot, "cpuinfo"))
+	if err != nil {
+		return flags
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		for _, f := range strings.Fields(value) {
+			flags[f] = true
+		}
+		break
+	}
+	return flags
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package control
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID is unsupported off Linux; AllowedUIDs then rejects every peer and
+// socket file permissions must be used instead.
+func peerUID(conn *net.UnixConn) (int, error) {
+	return -1, errors.New("peer credentials are not supported on this platform")
+}
@@ -0,0 +1,364 @@
+// Package control exposes a local Unix domain socket for host agents that must not open TCP ports.
+// Requests and responses are newline-delimited JSON. Access is restricted by the socket's file
+// permissions and, where supported, by the connecting peer's UID, which also selects the peer's
+// role ("uid:<n>") under an optional authorization policy.
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"internal/admin"
+	"internal/authz"
+	"pkg/correlation"
+	"pkg/system"
+	"services/telemetry"
+)
+
+// Commands accepted on the control socket.
+const (
+	CommandStatus        = "status"
+	CommandCollect       = "collect"
+	CommandResetBreaches = "reset_breaches"
+	CommandReload        = "reload"
+	CommandHistory       = "history"
+	CommandPause         = "pause"
+	CommandResume        = "resume"
+	CommandLogLevel      = "log_level"
+)
+
+// commandRoles is the minimum role for each command when an authorization policy is configured.
+var commandRoles = map[string]authz.Role{
+	CommandStatus:        authz.RoleViewer,
+	CommandHistory:       authz.RoleViewer,
+	CommandCollect:       authz.RoleOperator,
+	CommandPause:         authz.RoleOperator,
+	CommandResume:        authz.RoleOperator,
+	CommandLogLevel:      authz.RoleOperator,
+	CommandResetBreaches: authz.RoleAdmin,
+	CommandReload:        authz.RoleAdmin,
+}
+
+const (
+	defaultSocketMode   os.FileMode = 0o600
+	defaultHistoryLimit             = 100
+	defaultLogLevelTTL              = 15 * time.Minute
+)
+
+// Logger defines the interface required for control socket logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// ReloadFunc reloads the running configuration.
+type ReloadFunc func(ctx context.Context) error
+
+// Config holds the control socket settings.
+type Config struct {
+	SocketPath string
+	// Mode is applied to the socket file. Zero defaults to 0600 (owner only).
+	Mode os.FileMode
+	// AllowedUIDs, if non-empty, additionally restricts peers by UID (Linux SO_PEERCRED).
+	AllowedUIDs []int
+	// HistoryLimit caps the number of records returned by the history command. Zero defaults to 100.
+	HistoryLimit int
+	// Policy, if set, assigns roles to peers by principal "uid:<n>" and restricts commands accordingly.
+	Policy *authz.Policy
+	// Audit, if set, records every privileged command (operator and above), performed, refused or
+	// denied.
+	Audit *admin.AuditLog
+	// LogLevels, if set, can be listed and overridden with the log_level command.
+	LogLevels *system.Levels
+}
+
+// Request is a single control command.
+type Request struct {
+	Command string `json:"command"`
+	// N is the number of records requested by the history command.
+	N int `json:"n,omitempty"`
+	// Component, Level and TTL apply to the log_level command. Without a level it lists the
+	// levels; with one it overrides the level of Component (all components if empty) for TTL,
+	// e.g. "30m" (default 15m). Level "reset" reverts to the configured level at once.
+	Component string `json:"component,omitempty"`
+	Level     string `json:"level,omitempty"`
+	TTL       string `json:"ttl,omitempty"`
+	// CorrelationID, if set, is adopted for the command; otherwise a new one is assigned.
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// Response is returned for every request.
+type Response struct {
+	OK            bool        `json:"ok"`
+	Error         string      `json:"error,omitempty"`
+	Data          interface{} `json:"data,omitempty"`
+	CorrelationID string      `json:"correlation_id,omitempty"`
+}
+
+// Server serves control commands for a running STS instance.
+type Server struct {
+	cfg    Config
+	sts    telemetry.STS
+	sink   telemetry.TelemetrySink
+	reload ReloadFunc
+	log    Logger
+	wg     sync.WaitGroup
+}
+
+// NewServer creates a control server. sink and reload are optional; the corresponding commands
+// report an error when they are not configured.
+func NewServer(cfg Config, sts telemetry.STS, sink telemetry.TelemetrySink, reload ReloadFunc, logger Logger) *Server {
+	if cfg.Mode == 0 {
+		cfg.Mode = defaultSocketMode
+	}
+	if cfg.HistoryLimit == 0 {
+		cfg.HistoryLimit = defaultHistoryLimit
+	}
+	return &Server{cfg: cfg, sts: sts, sink: sink, reload: reload, log: logger}
+}
+
+// Serve listens on the socket until ctx is cancelled. A stale socket file from a previous run is removed.
+func (s *Server) Serve(ctx context.Context) error {
+	if s.cfg.SocketPath == "" {
+		return errors.New("control: socket path must be set")
+	}
+	if err := os.Remove(s.cfg.SocketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("control: failed to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", s.cfg.SocketPath)
+	if err != nil {
+		return fmt.Errorf("control: failed to listen on %s: %w", s.cfg.SocketPath, err)
+	}
+	if err := os.Chmod(s.cfg.SocketPath, s.cfg.Mode); err != nil {
+		listener.Close()
+		return fmt.Errorf("control: failed to restrict socket permissions: %w", err)
+	}
+	s.log.Infof("Control socket listening on %s (mode %v)", s.cfg.SocketPath, s.cfg.Mode)
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				s.wg.Wait()
+				return ctx.Err()
+			}
+			s.log.Warnf("Control socket accept failed: %v", err)
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleConn(ctx, conn.(*net.UnixConn))
+		}()
+	}
+}
+
+func (s *Server) handleConn(ctx context.Context, conn *net.UnixConn) {
+	defer conn.Close()
+
+	// Unblock the reader on shutdown so Serve can drain in-flight connections.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	uid, uidErr := peerUID(conn)
+	if len(s.cfg.AllowedUIDs) > 0 {
+		if uidErr != nil || !s.uidAllowed(uid) {
+			s.log.Warnf("Control socket rejected peer (uid %d): %v", uid, uidErr)
+			json.NewEncoder(conn).Encode(Response{Error: "permission denied"})
+			return
+		}
+	}
+	principal := "uid:unknown"
+	if uidErr == nil {
+		principal = "uid:" + strconv.Itoa(uid)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		resp := Response{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			reqCtx := ctx
+			if req.CorrelationID != "" {
+				reqCtx = correlation.WithID(reqCtx, req.CorrelationID)
+			}
+			reqCtx = correlation.Ensure(reqCtx, nil)
+			resp = s.authorizeAndDispatch(reqCtx, principal, req)
+			resp.CorrelationID = correlation.FromContext(reqCtx)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// authorizeAndDispatch enforces the policy, if any, and audits privileged commands.
+func (s *Server) authorizeAndDispatch(ctx context.Context, principal string, req Request) Response {
+	required, known := commandRoles[req.Command]
+	if !known {
+		return s.dispatch(ctx, req)
+	}
+	role := authz.RoleAdmin
+	if s.cfg.Policy != nil {
+		role = s.cfg.Policy.RoleOf(principal)
+		if err := s.cfg.Policy.Authorize(principal, req.Command, required); err != nil {
+			log := correlation.WithLogger(ctx, s.log)
+			log.Warnf("Control socket denied '%s' to %s: %v", req.Command, principal, err)
+			if auditErr := s.auditCommand(ctx, principal, role, req.Command, required, admin.OutcomeDenied, err.Error()); auditErr != nil {
+				log.Errorf("Control socket failed to audit denied '%s' by %s: %v", req.Command, principal, auditErr)
+			}
+			return Response{Error: err.Error()}
+		}
+	}
+	// Like the admin API, audit the result: a command that failed is recorded as refused, and one
+	// that took effect without a durable record is reported as an error.
+	resp := s.dispatch(ctx, req)
+	outcome, detail := "", ""
+	if resp.Error != "" {
+		outcome, detail = admin.OutcomeRefused, resp.Error
+	}
+	if err := s.auditCommand(ctx, principal, role, req.Command, required, outcome, detail); err != nil {
+		if resp.Error != "" {
+			return Response{Error: resp.Error + "; " + err.Error()}
+		}
+		return Response{Error: fmt.Sprintf("%s performed but not audited: %v", req.Command, err)}
+	}
+	return resp
+}
+
+func (s *Server) auditCommand(ctx context.Context, principal string, role authz.Role, command string, required authz.Role, outcome, detail string) error {
+	if s.cfg.Audit == nil || required < authz.RoleOperator {
+		return nil
+	}
+	return s.cfg.Audit.Record(admin.AuditRecord{Principal: principal, Role: role.String(), Action: command, Reason: "control socket", Outcome: outcome, Detail: detail,
+		CorrelationID: correlation.FromContext(ctx),
+	})
+}
+
+func (s *Server) dispatch(ctx context.Context, req Request) Response {
+	switch req.Command {
+	case CommandStatus:
+		return Response{OK: true, Data: map[string]interface{}{
+			"telemetry":      s.sts.GetHealthStatus(),
+			"gatm_violation": s.sts.CheckGATMViolation(),
+			"paused":         s.sts.Paused(),
+		}}
+	case CommandCollect:
+		if err := s.sts.CollectNow(ctx); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true, Data: s.sts.GetHealthStatus()}
+	case CommandPause:
+		s.sts.Pause()
+		correlation.WithLogger(ctx, s.log).Infof("Background collection paused via control socket")
+		return Response{OK: true}
+	case CommandResume:
+		s.sts.Resume()
+		correlation.WithLogger(ctx, s.log).Infof("Background collection resumed via control socket")
+		return Response{OK: true}
+	case CommandResetBreaches:
+		if err := s.sts.ResetBreachCount(); err != nil {
+			return Response{Error: err.Error()}
+		}
+		correlation.WithLogger(ctx, s.log).Infof("GATM breach count reset via control socket")
+		return Response{OK: true}
+	case CommandReload:
+		if s.reload == nil {
+			return Response{Error: "reload is not configured"}
+		}
+		if err := s.reload(ctx); err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true}
+	case CommandHistory:
+		if s.sink == nil {
+			return Response{Error: "no telemetry sink is configured"}
+		}
+		n := req.N
+		if n <= 0 || n > s.cfg.HistoryLimit {
+			n = s.cfg.HistoryLimit
+		}
+		history, err := s.sink.QueryLastN(ctx, n)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		return Response{OK: true, Data: history}
+	case CommandLogLevel:
+		return s.logLevel(ctx, req)
+	default:
+		return Response{Error: fmt.Sprintf("unknown command '%s'", req.Command)}
+	}
+}
+
+func (s *Server) logLevel(ctx context.Context, req Request) Response {
+	levels := s.cfg.LogLevels
+	if levels == nil {
+		return Response{Error: "log levels are not configured"}
+	}
+	switch req.Level {
+	case "":
+	case "reset":
+		levels.Reset(req.Component)
+		correlation.WithLogger(ctx, s.log).Infof("Log level of %s reset via control socket", logTarget(req.Component))
+	default:
+		level, err := system.ParseLevel(req.Level)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+		ttl := defaultLogLevelTTL
+		if req.TTL != "" {
+			if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+				return Response{Error: "ttl must be a positive duration"}
+			}
+		}
+		levels.Set(req.Component, level, ttl)
+		correlation.WithLogger(ctx, s.log).Infof("Log level of %s set to %s for %s via control socket", logTarget(req.Component), level, ttl)
+	}
+	global, components := levels.Configured()
+	return Response{OK: true, Data: map[string]interface{}{
+		"level":            global,
+		"component_levels": components,
+		"overrides":        levels.Overrides(),
+	}}
+}
+
+func logTarget(component string) string {
+	if component == "" {
+		return "all components"
+	}
+	return component
+}
+
+func (s *Server) uidAllowed(uid int) bool {
+	for _, allowed := range s.cfg.AllowedUIDs {
+		if uid == allowed {
+			return true
+		}
+	}
+	return false
+}
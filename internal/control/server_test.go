@@ -0,0 +1,212 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"internal/admin"
+	"internal/authz"
+	"pkg/system"
+	"services/telemetry"
+)
+
+// nopLogger discards the control socket's log lines.
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+
+// breachingSource reports a latency breach on every collection.
+type breachingSource struct{}
+
+func (breachingSource) Collect(ctx context.Context) (telemetry.TelemetryData, error) {
+	return telemetry.TelemetryData{Timestamp: time.Now(), PipelineLatencyS9: time.Hour, IntegrityHashChainStatus: "SYNCED"}, nil
+}
+
+// startServer serves sts on a socket in a temporary directory until the test ends and returns
+// the socket path.
+func startServer(t *testing.T, cfg Config, sts telemetry.STS) string {
+	t.Helper()
+	cfg.SocketPath = filepath.Join(t.TempDir(), "control.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(cfg, sts, nil, nil, nopLogger{}).Serve(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		conn, err := net.Dial("unix", cfg.SocketPath)
+		if err == nil {
+			conn.Close()
+			return cfg.SocketPath
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("control socket did not start: %v", err)
+		}
+	}
+}
+
+// send issues one request on a new connection and returns the response.
+func send(t *testing.T, path string, req Request) Response {
+	t.Helper()
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		t.Fatal(err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestServer_ResetRefusedByGuard(t *testing.T) {
+	lc := telemetry.NewLifecycle()
+	lc.Guard(func(ctx context.Context, tr telemetry.Transition) error {
+		if tr.From == telemetry.StateSIHActive {
+			return errors.New("held for operator release")
+		}
+		return nil
+	})
+	if err := lc.Fire(context.Background(), telemetry.StateSIHActive, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{Lifecycle: lc}, breachingSource{})
+	audit := admin.NewAuditLog(nil)
+	path := startServer(t, Config{Audit: audit}, sts)
+
+	resp := send(t, path, Request{Command: CommandResetBreaches})
+	if resp.OK || !strings.Contains(resp.Error, "held for operator release") {
+		t.Errorf("reset_breaches = %+v, want the guard's refusal", resp)
+	}
+	if lc.State() != telemetry.StateSIHActive {
+		t.Errorf("lifecycle = %s after a refused reset, want SIH_ACTIVE", lc.State())
+	}
+	records := audit.List()
+	if len(records) != 1 || records[0].Action != CommandResetBreaches || records[0].Outcome != admin.OutcomeRefused || !strings.Contains(records[0].Detail, "held for operator release") {
+		t.Errorf("audit = %+v, want the reset recorded as refused", records)
+	}
+}
+
+func TestServer_SocketMode(t *testing.T) {
+	path := startServer(t, Config{}, telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{}))
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("socket mode = %v, want 0600", mode)
+	}
+}
+
+func TestServer_RoleDenied(t *testing.T) {
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{})
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	principal := "uid:" + strconv.Itoa(os.Getuid())
+	audit := admin.NewAuditLog(nil)
+	path := startServer(t, Config{Audit: audit, Policy: &authz.Policy{Bindings: map[string]authz.Role{principal: authz.RoleViewer}}}, sts)
+
+	if resp := send(t, path, Request{Command: CommandStatus}); !resp.OK {
+		t.Errorf("status by a viewer = %+v, want ok", resp)
+	}
+	resp := send(t, path, Request{Command: CommandResetBreaches})
+	if resp.OK || !strings.Contains(resp.Error, "requires role admin") {
+		t.Errorf("reset_breaches by a viewer = %+v, want a role error", resp)
+	}
+	if n := sts.GetHealthStatus().GATMBreachCount; n != 1 {
+		t.Errorf("GATMBreachCount = %d after a denied reset, want 1", n)
+	}
+	// Viewer commands are not audited; the denied reset is.
+	records := audit.List()
+	if len(records) != 1 || records[0].Principal != principal || records[0].Role != "viewer" || records[0].Outcome != admin.OutcomeDenied || records[0].CorrelationID != resp.CorrelationID {
+		t.Errorf("audit = %+v, want the denied reset by %s", records, principal)
+	}
+}
+
+func TestServer_UnknownCommand(t *testing.T) {
+	audit := admin.NewAuditLog(nil)
+	path := startServer(t, Config{Audit: audit}, telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{}))
+
+	if resp := send(t, path, Request{Command: "format_disk"}); resp.OK || resp.Error != "unknown command 'format_disk'" {
+		t.Errorf("unknown command = %+v, want an error", resp)
+	}
+	if records := audit.List(); len(records) != 0 {
+		t.Errorf("audit = %+v, want nothing recorded", records)
+	}
+}
+
+func TestServer_PauseResume(t *testing.T) {
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{})
+	audit := admin.NewAuditLog(nil)
+	path := startServer(t, Config{Audit: audit}, sts)
+
+	if resp := send(t, path, Request{Command: CommandPause}); !resp.OK || !sts.Paused() {
+		t.Fatalf("pause = %+v, paused %v; want paused", resp, sts.Paused())
+	}
+	status := send(t, path, Request{Command: CommandStatus})
+	if data, _ := status.Data.(map[string]interface{}); data["paused"] != true {
+		t.Errorf("status = %+v, want paused reported", status)
+	}
+	if resp := send(t, path, Request{Command: CommandResume}); !resp.OK || sts.Paused() {
+		t.Errorf("resume = %+v, paused %v; want resumed", resp, sts.Paused())
+	}
+	records := audit.List()
+	if len(records) != 2 || records[0].Action != CommandPause || records[1].Action != CommandResume || records[1].Role != "admin" {
+		t.Errorf("audit = %+v, want pause then resume", records)
+	}
+}
+
+func TestServer_LogLevel(t *testing.T) {
+	levels := system.NewLevels(system.LevelWarn, nil)
+	path := startServer(t, Config{LogLevels: levels}, telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{}))
+
+	if resp := send(t, path, Request{Command: CommandLogLevel, Component: "sts", Level: "debug", TTL: "10m"}); !resp.OK || levels.Level("sts") != system.LevelDebug {
+		t.Fatalf("log_level debug = %+v, level %v; want DEBUG", resp, levels.Level("sts"))
+	}
+	if resp := send(t, path, Request{Command: CommandLogLevel, Level: "debug", TTL: "-1m"}); resp.OK {
+		t.Errorf("log_level with a negative ttl = %+v, want an error", resp)
+	}
+	if resp := send(t, path, Request{Command: CommandLogLevel, Component: "sts", Level: "reset"}); !resp.OK || levels.Level("sts") != system.LevelWarn {
+		t.Errorf("log_level reset = %+v, level %v; want the configured WARN", resp, levels.Level("sts"))
+	}
+	if overrides := levels.Overrides(); len(overrides) != 0 {
+		t.Errorf("overrides after reset = %+v, want none", overrides)
+	}
+}
+
+func TestServer_ResetBreaches(t *testing.T) {
+	sts := telemetry.NewSovereignTelemetryService(telemetry.STSConfiguration{}, breachingSource{})
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	audit := admin.NewAuditLog(nil)
+	path := startServer(t, Config{Audit: audit}, sts)
+
+	resp := send(t, path, Request{Command: CommandResetBreaches, CorrelationID: "req-7"})
+	if !resp.OK || resp.CorrelationID != "req-7" {
+		t.Fatalf("reset_breaches = %+v, want ok under the caller's correlation ID", resp)
+	}
+	if n := sts.GetHealthStatus().GATMBreachCount; n != 0 {
+		t.Errorf("GATMBreachCount = %d after reset, want 0", n)
+	}
+	records := audit.List()
+	if len(records) != 1 || records[0].Action != CommandResetBreaches || records[0].Outcome != "" || records[0].CorrelationID != "req-7" {
+		t.Errorf("audit = %+v, want the performed reset", records)
+	}
+}
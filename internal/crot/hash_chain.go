@@ -0,0 +1,124 @@
+// Package crot implements the software Chain Root of Trust (CRoT) used to derive
+// IntegrityHashChainStatus when no hardware CRoT anchor is available.
+// It maintains an incremental hash chain over watched configuration/state files and
+// supports Merkle-proof verification for partial integrity checks.
+package crot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+	"time"
+)
+
+// HashFunc constructs the hash primitive used for chain links and Merkle nodes.
+// It is pluggable so deployments can align with the digest used by their hardware anchor.
+type HashFunc func() hash.Hash
+
+// DefaultHashFunc is SHA-256, matching the CRoT anchor digest.
+var DefaultHashFunc HashFunc = sha256.New
+
+// ErrChainBroken is returned when a link no longer references its predecessor's digest.
+var ErrChainBroken = errors.New("crot: hash chain broken")
+
+// Link is a single entry of the hash chain.
+// Digest = H(PrevDigest || Label || PayloadDigest), binding each entry to its full history.
+type Link struct {
+	Sequence      uint64    `json:"sequence"`
+	Label         string    `json:"label"`          // e.g., the file path that changed
+	PayloadDigest []byte    `json:"payload_digest"` // H(payload)
+	PrevDigest    []byte    `json:"prev_digest"`
+	Digest        []byte    `json:"digest"`
+	RecordedAt    time.Time `json:"recorded_at"`
+}
+
+// HashChain is an append-only, thread-safe hash chain.
+type HashChain struct {
+	newHash HashFunc
+	links   []Link
+	mu      sync.RWMutex
+}
+
+// NewHashChain creates an empty chain. A nil HashFunc defaults to SHA-256.
+func NewHashChain(fn HashFunc) *HashChain {
+	if fn == nil {
+		fn = DefaultHashFunc
+	}
+	return &HashChain{newHash: fn}
+}
+
+// Append hashes the payload and extends the chain with a new link, returning it.
+func (c *HashChain) Append(label string, payload []byte) Link {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var prev []byte
+	if n := len(c.links); n > 0 {
+		prev = c.links[n-1].Digest
+	}
+
+	link := Link{
+		Sequence:      uint64(len(c.links)),
+		Label:         label,
+		PayloadDigest: c.sum(payload),
+		PrevDigest:    prev,
+		RecordedAt:    time.Now(),
+	}
+	link.Digest = c.linkDigest(link)
+	c.links = append(c.links, link)
+	return link
+}
+
+// Head returns the digest of the most recent link, or nil for an empty chain.
+func (c *HashChain) Head() []byte {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.links) == 0 {
+		return nil
+	}
+	return c.links[len(c.links)-1].Digest
+}
+
+// Links returns a copy of the chain's entries, ordered from oldest to newest.
+func (c *HashChain) Links() []Link {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]Link, len(c.links))
+	copy(out, c.links)
+	return out
+}
+
+// Verify walks the chain and recomputes every link digest.
+func (c *HashChain) Verify() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var prev []byte
+	for _, link := range c.links {
+		if !bytes.Equal(link.PrevDigest, prev) {
+			return fmt.Errorf("%w: link %d does not reference its predecessor", ErrChainBroken, link.Sequence)
+		}
+		if !bytes.Equal(link.Digest, c.linkDigest(link)) {
+			return fmt.Errorf("%w: link %d digest mismatch", ErrChainBroken, link.Sequence)
+		}
+		prev = link.Digest
+	}
+	return nil
+}
+
+func (c *HashChain) sum(data []byte) []byte {
+	h := c.newHash()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (c *HashChain) linkDigest(link Link) []byte {
+	h := c.newHash()
+	h.Write(link.PrevDigest)
+	h.Write([]byte(link.Label))
+	h.Write(link.PayloadDigest)
+	return h.Sum(nil)
+}
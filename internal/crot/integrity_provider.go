@@ -0,0 +1,172 @@
+package crot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// IntegrityStatus mirrors the values reported in TelemetryData.IntegrityHashChainStatus.
+type IntegrityStatus string
+
+const (
+	IntegritySynced      IntegrityStatus = "SYNCED"
+	IntegrityDiverged    IntegrityStatus = "DIVERGED"
+	IntegrityUnreachable IntegrityStatus = "UNREACHABLE"
+)
+
+// IntegrityProvider derives the current CRoT integrity status.
+// Implementations may be backed by hardware anchors or by the local FileChainVerifier.
+type IntegrityProvider interface {
+	Status(ctx context.Context) (IntegrityStatus, error)
+}
+
+// FileChainVerifier is the software CRoT: it anchors a Merkle root over a set of watched
+// configuration/state files and records every observed change in an incremental hash chain.
+type FileChainVerifier struct {
+	newHash HashFunc
+	paths   []string // sorted, fixing leaf order
+
+	chain   *HashChain
+	anchor  *MerkleTree
+	digests map[string][]byte // last observed content digest per path
+	mu      sync.Mutex
+}
+
+// NewFileChainVerifier reads the watched files and anchors their current state as the trusted baseline.
+// A nil HashFunc defaults to SHA-256.
+func NewFileChainVerifier(fn HashFunc, paths []string) (*FileChainVerifier, error) {
+	if fn == nil {
+		fn = DefaultHashFunc
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("crot: file chain verifier requires at least one watched path")
+	}
+
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	v := &FileChainVerifier{
+		newHash: fn,
+		paths:   sorted,
+		chain:   NewHashChain(fn),
+		digests: make(map[string][]byte, len(sorted)),
+	}
+	if err := v.Reanchor(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reanchor accepts the current on-disk state as the new trusted baseline.
+// It must only be called after an authorized configuration change.
+func (v *FileChainVerifier) Reanchor() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	leaves, err := v.refreshLocked()
+	if err != nil {
+		return err
+	}
+	tree, err := NewMerkleTree(v.newHash, leaves)
+	if err != nil {
+		return err
+	}
+	v.anchor = tree
+	v.chain.Append("anchor", tree.Root())
+	return nil
+}
+
+// Status recomputes the watched files' digests and compares the resulting root against the anchor.
+func (v *FileChainVerifier) Status(ctx context.Context) (IntegrityStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return IntegrityUnreachable, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	leaves, err := v.refreshLocked()
+	if err != nil {
+		return IntegrityUnreachable, err
+	}
+	if err := v.chain.Verify(); err != nil {
+		return IntegrityDiverged, nil
+	}
+
+	current, err := NewMerkleTree(v.newHash, leaves)
+	if err != nil {
+		return IntegrityUnreachable, err
+	}
+	if !bytes.Equal(current.Root(), v.anchor.Root()) {
+		return IntegrityDiverged, nil
+	}
+	return IntegritySynced, nil
+}
+
+// VerifyFile performs a partial integrity check of a single watched file using a Merkle proof
+// against the anchored root, without rehashing the remaining files.
+func (v *FileChainVerifier) VerifyFile(path string) (IntegrityStatus, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	index := sort.SearchStrings(v.paths, path)
+	if index == len(v.paths) || v.paths[index] != path {
+		return IntegrityUnreachable, fmt.Errorf("crot: path %s is not watched", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return IntegrityUnreachable, fmt.Errorf("crot: failed to read %s: %w", path, err)
+	}
+	proof, err := v.anchor.Proof(index)
+	if err != nil {
+		return IntegrityUnreachable, err
+	}
+	if err := VerifyProof(v.newHash, v.anchor.Root(), v.leaf(path, v.chain.sum(content)), proof); err != nil {
+		return IntegrityDiverged, nil
+	}
+	return IntegritySynced, nil
+}
+
+// Root returns the anchored Merkle root.
+func (v *FileChainVerifier) Root() []byte {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.anchor.Root()
+}
+
+// Chain exposes the underlying hash chain for audit export.
+func (v *FileChainVerifier) Chain() *HashChain {
+	return v.chain
+}
+
+// refreshLocked rehashes every watched file, appending a chain link for each changed digest,
+// and returns the ordered Merkle leaves. Callers must hold v.mu.
+func (v *FileChainVerifier) refreshLocked() ([][]byte, error) {
+	leaves := make([][]byte, len(v.paths))
+	for i, path := range v.paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("crot: failed to read %s: %w", path, err)
+		}
+		digest := v.chain.sum(content)
+		if prev, ok := v.digests[path]; !ok || !bytes.Equal(prev, digest) {
+			v.chain.Append(path, content)
+			v.digests[path] = digest
+		}
+		leaves[i] = v.leaf(path, digest)
+	}
+	return leaves, nil
+}
+
+// leaf binds a file's path to its content digest so renamed files cannot satisfy a proof.
+func (v *FileChainVerifier) leaf(path string, digest []byte) []byte {
+	return append([]byte(path+"\x00"), digest...)
+}
+
+// Ensure FileChainVerifier implements the IntegrityProvider interface.
+var _ IntegrityProvider = (*FileChainVerifier)(nil)
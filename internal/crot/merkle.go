@@ -0,0 +1,122 @@
+package crot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Domain separation prefixes prevent second-preimage attacks between leaves and interior nodes.
+var (
+	leafPrefix = []byte{0x00}
+	nodePrefix = []byte{0x01}
+)
+
+// ErrProofInvalid is returned when a Merkle proof does not reconstruct the expected root.
+var ErrProofInvalid = errors.New("crot: merkle proof invalid")
+
+// ProofStep is one sibling hash on the path from a leaf to the root.
+type ProofStep struct {
+	Sibling []byte `json:"sibling"`
+	Left    bool   `json:"left"` // true if the sibling sits to the left of the running hash
+}
+
+// MerkleProof proves that a single leaf is included under a given root.
+type MerkleProof struct {
+	Index int         `json:"index"`
+	Steps []ProofStep `json:"steps"`
+}
+
+// MerkleTree is an immutable binary Merkle tree over a fixed, ordered set of leaves.
+// Odd nodes are promoted unchanged to the next level.
+type MerkleTree struct {
+	newHash HashFunc
+	levels  [][][]byte // levels[0] holds leaf hashes, the last level holds the root
+}
+
+// NewMerkleTree hashes the given leaves and builds the tree. A nil HashFunc defaults to SHA-256.
+func NewMerkleTree(fn HashFunc, leaves [][]byte) (*MerkleTree, error) {
+	if fn == nil {
+		fn = DefaultHashFunc
+	}
+	if len(leaves) == 0 {
+		return nil, errors.New("crot: merkle tree requires at least one leaf")
+	}
+
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = hashLeaf(fn, leaf)
+	}
+
+	tree := &MerkleTree{newHash: fn, levels: [][][]byte{level}}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashNode(fn, level[i], level[i+1]))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree, nil
+}
+
+// Root returns the Merkle root digest.
+func (t *MerkleTree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof builds an inclusion proof for the leaf at index.
+func (t *MerkleTree) Proof(index int) (MerkleProof, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return MerkleProof{}, fmt.Errorf("crot: leaf index %d out of range [0, %d)", index, len(t.levels[0]))
+	}
+
+	proof := MerkleProof{Index: index}
+	pos := index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := pos ^ 1
+		if sibling < len(level) {
+			proof.Steps = append(proof.Steps, ProofStep{Sibling: level[sibling], Left: sibling < pos})
+		}
+		pos /= 2
+	}
+	return proof, nil
+}
+
+// VerifyProof checks that leaf is included under root according to proof.
+func VerifyProof(fn HashFunc, root, leaf []byte, proof MerkleProof) error {
+	if fn == nil {
+		fn = DefaultHashFunc
+	}
+	running := hashLeaf(fn, leaf)
+	for _, step := range proof.Steps {
+		if step.Left {
+			running = hashNode(fn, step.Sibling, running)
+		} else {
+			running = hashNode(fn, running, step.Sibling)
+		}
+	}
+	if !bytes.Equal(running, root) {
+		return fmt.Errorf("%w: leaf %d", ErrProofInvalid, proof.Index)
+	}
+	return nil
+}
+
+func hashLeaf(fn HashFunc, data []byte) []byte {
+	h := fn()
+	h.Write(leafPrefix)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func hashNode(fn HashFunc, left, right []byte) []byte {
+	h := fn()
+	h.Write(nodePrefix)
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
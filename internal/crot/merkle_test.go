@@ -0,0 +1,71 @@
+package crot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMerkleProof_Verify(t *testing.T) {
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+	tree, err := NewMerkleTree(nil, leaves)
+	if err != nil {
+		t.Fatalf("NewMerkleTree() error = %v", err)
+	}
+
+	for i, leaf := range leaves {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatalf("Proof(%d) error = %v", i, err)
+		}
+		if err := VerifyProof(nil, tree.Root(), leaf, proof); err != nil {
+			t.Errorf("VerifyProof(%d) error = %v", i, err)
+		}
+		if err := VerifyProof(nil, tree.Root(), []byte("tampered"), proof); err == nil {
+			t.Errorf("VerifyProof(%d) accepted a tampered leaf", i)
+		}
+	}
+}
+
+func TestFileChainVerifier_Status(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.json")
+	for _, p := range []string{a, b} {
+		if err := os.WriteFile(p, []byte(`{"v":1}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := NewFileChainVerifier(nil, []string{a, b})
+	if err != nil {
+		t.Fatalf("NewFileChainVerifier() error = %v", err)
+	}
+	if status, err := v.Status(context.Background()); err != nil || status != IntegritySynced {
+		t.Fatalf("Status() = %v, %v; want %v", status, err, IntegritySynced)
+	}
+
+	if err := os.WriteFile(b, []byte(`{"v":2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := v.Status(context.Background()); status != IntegrityDiverged {
+		t.Errorf("Status() after modification = %v, want %v", status, IntegrityDiverged)
+	}
+	if status, _ := v.VerifyFile(a); status != IntegritySynced {
+		t.Errorf("VerifyFile(a) = %v, want %v", status, IntegritySynced)
+	}
+	if status, _ := v.VerifyFile(b); status != IntegrityDiverged {
+		t.Errorf("VerifyFile(b) = %v, want %v", status, IntegrityDiverged)
+	}
+
+	if err := v.Reanchor(); err != nil {
+		t.Fatalf("Reanchor() error = %v", err)
+	}
+	if status, _ := v.Status(context.Background()); status != IntegritySynced {
+		t.Errorf("Status() after Reanchor = %v, want %v", status, IntegritySynced)
+	}
+	if err := v.Chain().Verify(); err != nil {
+		t.Errorf("Chain().Verify() error = %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Package tpm implements a TPM 2.0 backed crot.IntegrityProvider.
+// It reads PCRs, produces quotes signed by an Attestation Key (AK), and verifies them
+// against a golden PCR policy to derive IntegrityHashChainStatus.
+package tpm
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"internal/crot"
+)
+
+const (
+	// nonceSize is the size of the freshness nonce embedded into each quote.
+	nonceSize = 32
+	// numPCRs is the number of PCRs in a TPM 2.0 PC Client bank.
+	numPCRs = 24
+)
+
+// ErrQuoteInvalid is returned when a quote's signature, nonce, or PCR digest cannot be verified.
+var ErrQuoteInvalid = errors.New("tpm: quote verification failed")
+
+// GoldenPolicy lists the expected SHA-256 PCR values, hex-encoded, keyed by PCR index.
+type GoldenPolicy struct {
+	PCRs map[int]string `json:"pcrs"`
+}
+
+// LoadGoldenPolicy reads a golden PCR policy from a JSON file.
+func LoadGoldenPolicy(path string) (GoldenPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return GoldenPolicy{}, fmt.Errorf("failed to read golden PCR policy at %s: %w", path, err)
+	}
+	var policy GoldenPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return GoldenPolicy{}, fmt.Errorf("failed to parse golden PCR policy JSON: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return GoldenPolicy{}, err
+	}
+	return policy, nil
+}
+
+// Validate checks that the policy lists at least one PCR, every index is a PCR of the SHA-256 bank
+// (0-23), and every value is a hex-encoded SHA-256 digest.
+func (g GoldenPolicy) Validate() error {
+	if len(g.PCRs) == 0 {
+		return errors.New("golden PCR policy must list at least one PCR")
+	}
+	for i, value := range g.PCRs {
+		if i < 0 || i >= numPCRs {
+			return fmt.Errorf("golden PCR index %d is out of range 0-%d", i, numPCRs-1)
+		}
+		if digest, err := hex.DecodeString(value); err != nil || len(digest) != sha256.Size {
+			return fmt.Errorf("golden PCR %d must be a hex-encoded SHA-256 digest", i)
+		}
+	}
+	return nil
+}
+
+// Selection returns the sorted SHA-256 PCR selection covered by the policy.
+func (g GoldenPolicy) Selection() tpm2.PCRSelection {
+	indices := make([]int, 0, len(g.PCRs))
+	for i := range g.PCRs {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: indices}
+}
+
+// Quote is the attestation evidence produced by the TPM.
+type Quote struct {
+	Attestation []byte         `json:"attestation"` // TPMS_ATTEST structure
+	Signature   []byte         `json:"signature"`   // RSASSA-PKCS1v1_5 over SHA-256(Attestation)
+	PCRs        map[int][]byte `json:"pcrs"`
+}
+
+// Nonce returns the nonce recorded in the quote's attestation. It is not authenticated until
+// VerifyQuote succeeds; use it only to find the challenge the quote claims to answer.
+func (q *Quote) Nonce() ([]byte, error) {
+	attest, err := tpm2.DecodeAttestationData(q.Attestation)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQuoteInvalid, err)
+	}
+	return attest.ExtraData, nil
+}
+
+// Provider is a TPM-backed crot.IntegrityProvider.
+type Provider struct {
+	rw     io.ReadWriteCloser
+	ak     *client.Key
+	golden GoldenPolicy
+	mu     sync.Mutex // the TPM command channel is not safe for concurrent use
+}
+
+// OpenProvider opens the TPM device (e.g., /dev/tpmrm0) and creates an AK.
+func OpenProvider(devicePath string, golden GoldenPolicy) (*Provider, error) {
+	rw, err := tpm2.OpenTPM(devicePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TPM device %s: %w", devicePath, err)
+	}
+	p, err := NewProvider(rw, golden)
+	if err != nil {
+		rw.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewProvider creates an AK on an already opened TPM channel.
+// Tests inject a go-tpm-tools simulator here.
+func NewProvider(rw io.ReadWriteCloser, golden GoldenPolicy) (*Provider, error) {
+	if err := golden.Validate(); err != nil {
+		return nil, err
+	}
+	ak, err := client.AttestationKeyRSA(rw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attestation key: %w", err)
+	}
+	return &Provider{rw: rw, ak: ak, golden: golden}, nil
+}
+
+// AKPublic returns the AK public key used to verify quotes produced by this provider.
+func (p *Provider) AKPublic() crypto.PublicKey {
+	return p.ak.PublicKey()
+}
+
+// Quote produces a quote over the golden policy's PCR selection bound to nonce.
+func (p *Provider) Quote(nonce []byte) (*Quote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sel := p.golden.Selection()
+	attest, sig, err := tpm2.Quote(p.rw, p.ak.Handle(), "", "", nonce, sel, tpm2.AlgNull)
+	if err != nil {
+		return nil, fmt.Errorf("tpm quote failed: %w", err)
+	}
+	if sig.RSA == nil {
+		return nil, errors.New("tpm quote returned a non-RSA signature")
+	}
+	pcrs, err := tpm2.ReadPCRs(p.rw, sel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PCRs: %w", err)
+	}
+	return &Quote{Attestation: attest, Signature: sig.RSA.Signature, PCRs: pcrs}, nil
+}
+
+// Status quotes the TPM with a fresh nonce and verifies the result against the golden policy.
+func (p *Provider) Status(ctx context.Context) (crot.IntegrityStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return crot.IntegrityUnreachable, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return crot.IntegrityUnreachable, fmt.Errorf("failed to generate quote nonce: %w", err)
+	}
+	quote, err := p.Quote(nonce)
+	if err != nil {
+		return crot.IntegrityUnreachable, err
+	}
+	return VerifyQuote(p.AKPublic(), quote, nonce, p.golden)
+}
+
+// Close releases the AK handle and the TPM channel.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ak.Close()
+	return p.rw.Close()
+}
+
+// VerifyQuote checks the quote's signature, nonce, and PCR digest, then compares PCR values
+// against the golden policy. Evidence that is authentic but deviates from the policy maps to
+// IntegrityDiverged; evidence that cannot be authenticated is returned as an error.
+func VerifyQuote(akPub crypto.PublicKey, quote *Quote, nonce []byte, golden GoldenPolicy) (crot.IntegrityStatus, error) {
+	rsaPub, ok := akPub.(*rsa.PublicKey)
+	if !ok {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: unsupported AK public key type %T", ErrQuoteInvalid, akPub)
+	}
+	attestDigest := sha256.Sum256(quote.Attestation)
+	if err := rsa.VerifyPKCS1v15(rsaPub, crypto.SHA256, attestDigest[:], quote.Signature); err != nil {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: %v", ErrQuoteInvalid, err)
+	}
+
+	attest, err := tpm2.DecodeAttestationData(quote.Attestation)
+	if err != nil {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: %v", ErrQuoteInvalid, err)
+	}
+	if !bytes.Equal(attest.ExtraData, nonce) {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: nonce mismatch", ErrQuoteInvalid)
+	}
+	if attest.AttestedQuoteInfo == nil {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: attestation is not a quote", ErrQuoteInvalid)
+	}
+
+	// The quote must cover exactly the policy's bank and indices; the digest below only vouches for
+	// the PCRs the TPM was asked to quote.
+	sel := golden.Selection()
+	quoted := attest.AttestedQuoteInfo.PCRSelection
+	quotedPCRs := append([]int(nil), quoted.PCRs...)
+	sort.Ints(quotedPCRs)
+	if quoted.Hash != sel.Hash || !slices.Equal(quotedPCRs, sel.PCRs) {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: quote covers PCRs %v in bank %v, policy expects %v in bank %v",
+			ErrQuoteInvalid, quotedPCRs, quoted.Hash, sel.PCRs, sel.Hash)
+	}
+
+	// The quoted digest is SHA-256 over the selected PCR values in ascending index order.
+	h := sha256.New()
+	for _, i := range sel.PCRs {
+		value, ok := quote.PCRs[i]
+		if !ok {
+			return crot.IntegrityUnreachable, fmt.Errorf("%w: PCR %d missing from evidence", ErrQuoteInvalid, i)
+		}
+		h.Write(value)
+	}
+	if !bytes.Equal(h.Sum(nil), attest.AttestedQuoteInfo.PCRDigest) {
+		return crot.IntegrityUnreachable, fmt.Errorf("%w: PCR values do not match quoted digest", ErrQuoteInvalid)
+	}
+
+	for _, i := range sel.PCRs {
+		expected, err := hex.DecodeString(golden.PCRs[i])
+		if err != nil {
+			return crot.IntegrityUnreachable, fmt.Errorf("golden PCR %d is not valid hex: %w", i, err)
+		}
+		if !bytes.Equal(quote.PCRs[i], expected) {
+			return crot.IntegrityDiverged, nil
+		}
+	}
+	return crot.IntegritySynced, nil
+}
+
+// Ensure Provider implements the crot.IntegrityProvider interface.
+var _ crot.IntegrityProvider = (*Provider)(nil)
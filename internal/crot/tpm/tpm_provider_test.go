@@ -0,0 +1,104 @@
+package tpm
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/google/go-tpm-tools/simulator"
+	"github.com/google/go-tpm/legacy/tpm2"
+
+	"internal/crot"
+)
+
+func TestProvider_StatusWithSimulator(t *testing.T) {
+	sim, err := simulator.Get()
+	if err != nil {
+		t.Fatalf("simulator.Get() error = %v", err)
+	}
+
+	// A fresh simulator reports all-zero SHA-256 PCRs.
+	zero := hex.EncodeToString(make([]byte, 32))
+	p, err := NewProvider(sim, GoldenPolicy{PCRs: map[int]string{0: zero, 7: zero}})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	defer p.Close()
+
+	if status, err := p.Status(context.Background()); err != nil || status != crot.IntegritySynced {
+		t.Fatalf("Status() = %v, %v; want %v", status, err, crot.IntegritySynced)
+	}
+
+	if err := tpm2.PCRExtend(sim, 7, tpm2.AlgSHA256, make([]byte, 32), ""); err != nil {
+		t.Fatalf("PCRExtend() error = %v", err)
+	}
+	if status, err := p.Status(context.Background()); err != nil || status != crot.IntegrityDiverged {
+		t.Errorf("Status() after extend = %v, %v; want %v", status, err, crot.IntegrityDiverged)
+	}
+}
+
+// signQuote returns a quote over sel signed with key. The quoted digest covers pcrs at the
+// indices of the golden policy, whatever sel says, as a TPM quoting sel would not produce.
+func signQuote(t *testing.T, key *rsa.PrivateKey, nonce []byte, sel tpm2.PCRSelection, pcrs map[int][]byte, golden GoldenPolicy) *Quote {
+	t.Helper()
+	h := sha256.New()
+	for _, i := range golden.Selection().PCRs {
+		h.Write(pcrs[i])
+	}
+	attest, err := tpm2.AttestationData{
+		Magic:             0xff544347,
+		Type:              tpm2.TagAttestQuote,
+		QualifiedSigner:   tpm2.Name{Digest: &tpm2.HashValue{Alg: tpm2.AlgSHA256, Value: make([]byte, 32)}},
+		ExtraData:         nonce,
+		AttestedQuoteInfo: &tpm2.QuoteInfo{PCRSelection: sel, PCRDigest: h.Sum(nil)},
+	}.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(attest)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Quote{Attestation: attest, Signature: sig, PCRs: pcrs}
+}
+
+func TestVerifyQuote_Selection(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := make([]byte, 32)
+	golden := GoldenPolicy{PCRs: map[int]string{0: hex.EncodeToString(zero), 7: hex.EncodeToString(zero)}}
+	pcrs := map[int][]byte{0: zero, 7: zero}
+	nonce := []byte("nonce")
+
+	tests := []struct {
+		name string
+		sel  tpm2.PCRSelection
+		want error
+	}{
+		{name: "Matches Policy", sel: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0, 7}}},
+		{name: "Other Bank", sel: tpm2.PCRSelection{Hash: tpm2.AlgSHA1, PCRs: []int{0, 7}}, want: ErrQuoteInvalid},
+		{name: "Fewer PCRs", sel: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0}}, want: ErrQuoteInvalid},
+		{name: "Other PCRs", sel: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0, 8}}, want: ErrQuoteInvalid},
+		{name: "More PCRs", sel: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: []int{0, 7, 8}}, want: ErrQuoteInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := signQuote(t, key, nonce, tt.sel, pcrs, golden)
+			status, err := VerifyQuote(&key.PublicKey, quote, nonce, golden)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("VerifyQuote() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && status != crot.IntegritySynced {
+				t.Errorf("VerifyQuote() = %v, want %v", status, crot.IntegritySynced)
+			}
+		})
+	}
+}
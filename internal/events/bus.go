@@ -0,0 +1,234 @@
+// Package events is an in-process publish/subscribe bus with typed topics. Producers publish
+// without knowing who listens, so escalation, alerting, and audit subsystems are wired by
+// subscription rather than direct calls.
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+const defaultQueueSize = 64
+
+// Topic names a stream of events of type T.
+type Topic[T any] struct {
+	Name string
+}
+
+// GATMViolationEvent is published when a snapshot escalates into a new severity tier.
+type GATMViolationEvent struct {
+	Snapshot      telemetry.TelemetryData
+	CorrelationID string // Collection cycle that escalated
+}
+
+// PolicyReloadedEvent is published after the isolation manifest is reloaded.
+type PolicyReloadedEvent struct {
+	ManifestPath  string
+	BundleVersion string // Empty unless the policies came from a bundle
+	Policies      int
+	At            time.Time
+}
+
+// GovernanceUpdatedEvent is published after the governance policy document is applied.
+type GovernanceUpdatedEvent struct {
+	SamplingRates int
+	MaskingRules  int
+	Silences      int
+	Generation    uint64
+	At            time.Time
+}
+
+// GovernanceStaleEvent is published when the governance policies have not refreshed within their
+// maximum age, so stale sampling and masking rules are surfaced rather than silently enforced.
+type GovernanceStaleEvent struct {
+	Generation  uint64
+	LastUpdated time.Time // Zero if the policies were never loaded
+	Age         time.Duration
+	MaxAge      time.Duration
+	At          time.Time
+}
+
+// AdmissionDeniedEvent is published when a workload request is refused.
+type AdmissionDeniedEvent struct {
+	PolicyID      string
+	NodeID        string
+	Reason        string
+	At            time.Time
+	CorrelationID string
+}
+
+// NodeMissingEvent is published when a fleet node stops reporting for longer than the aggregator's TTL.
+type NodeMissingEvent struct {
+	NodeID   string
+	LastSeen time.Time
+	At       time.Time
+}
+
+// RecoveryCompletedEvent is published after an RRP recovery playbook run finishes.
+type RecoveryCompletedEvent struct {
+	Playbook      string
+	Succeeded     bool
+	FailedStep    string // Empty on success
+	RolledBack    int    // Completed steps undone after the failure
+	Duration      time.Duration
+	At            time.Time
+	CorrelationID string // Escalation that triggered the run
+}
+
+// LifecycleChangedEvent is published when the STS lifecycle moves to a new escalation state.
+type LifecycleChangedEvent struct {
+	Transition telemetry.Transition
+}
+
+// ContextDriftEvent is published when the node's SystemContext changes between two admission
+// matrix refreshes, e.g. secure boot was disabled or memory was removed.
+type ContextDriftEvent struct {
+	Changed  []string // Changed context fields, e.g. "os.secure_boot"
+	Revoked  []string // Policies that admitted the previous context and deny the current one
+	Restored []string // Policies that denied the previous context and admit the current one
+	Flagged  []string // Admitted workloads that no longer qualify
+	At       time.Time
+}
+
+// Built-in topics.
+var (
+	GATMViolation     = Topic[GATMViolationEvent]{Name: "gatm_violation"}
+	PolicyReloaded    = Topic[PolicyReloadedEvent]{Name: "policy_reloaded"}
+	GovernanceUpdated = Topic[GovernanceUpdatedEvent]{Name: "governance_updated"}
+	GovernanceStale   = Topic[GovernanceStaleEvent]{Name: "governance_stale"}
+	AdmissionDenied   = Topic[AdmissionDeniedEvent]{Name: "admission_denied"}
+	NodeMissing       = Topic[NodeMissingEvent]{Name: "node_missing"}
+	RecoveryCompleted = Topic[RecoveryCompletedEvent]{Name: "recovery_completed"}
+	LifecycleChanged  = Topic[LifecycleChangedEvent]{Name: "lifecycle_changed"}
+	ContextDrift      = Topic[ContextDriftEvent]{Name: "context_drift"}
+)
+
+// subscription delivers events from a bounded queue on its own goroutine, so a slow subscriber
+// delays only itself.
+type subscription struct {
+	queue chan interface{}
+	done  chan struct{}
+}
+
+// Bus routes published events to subscribers of the same topic.
+type Bus struct {
+	queueSize int
+	mu        sync.RWMutex
+	subs      map[string]map[*subscription]struct{}
+	closed    bool
+	dropped   atomic.Uint64
+	wg        sync.WaitGroup
+}
+
+// NewBus creates a bus whose subscribers each buffer up to queueSize events (default 64).
+func NewBus(queueSize int) *Bus {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	return &Bus{queueSize: queueSize, subs: make(map[string]map[*subscription]struct{})}
+}
+
+// Publish delivers event to every subscriber of topic without blocking. Subscribers whose queue is
+// full miss the event; see Dropped.
+func Publish[T any](b *Bus, topic Topic[T], event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs[topic.Name] {
+		select {
+		case sub.queue <- event:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe calls handler for each event published to topic, in publish order, until the returned
+// function is called or the bus is closed.
+func Subscribe[T any](b *Bus, topic Topic[T], handler func(T)) (unsubscribe func()) {
+	sub := &subscription{queue: make(chan interface{}, b.queueSize), done: make(chan struct{})}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return func() {}
+	}
+	if b.subs[topic.Name] == nil {
+		b.subs[topic.Name] = make(map[*subscription]struct{})
+	}
+	b.subs[topic.Name][sub] = struct{}{}
+	b.wg.Add(1)
+	b.mu.Unlock()
+
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case <-sub.done:
+				return
+			case ev, ok := <-sub.queue:
+				if !ok {
+					return
+				}
+				handler(ev.(T))
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			if _, ok := b.subs[topic.Name][sub]; ok {
+				delete(b.subs[topic.Name], sub)
+				close(sub.done)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Dropped reports how many deliveries were skipped because a subscriber's queue was full.
+func (b *Bus) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Close stops accepting subscriptions, lets every subscriber drain its queued events, and waits
+// for them to finish.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, subs := range b.subs {
+		for sub := range subs {
+			close(sub.queue)
+		}
+	}
+	b.subs = make(map[string]map[*subscription]struct{})
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// EscalationHandler returns a telemetry.EscalationHandler that publishes GATMViolation events,
+// for registration on the STS escalation router.
+func EscalationHandler(b *Bus) telemetry.EscalationHandler {
+	return telemetry.EscalationHandlerFunc(func(ctx context.Context, td telemetry.TelemetryData) error {
+		Publish(b, GATMViolation, GATMViolationEvent{Snapshot: td, CorrelationID: correlation.FromContext(ctx)})
+		return nil
+	})
+}
+
+// TransitionHook returns a telemetry.TransitionHook that publishes LifecycleChanged events, for
+// registration with Lifecycle.OnTransition.
+func TransitionHook(b *Bus) telemetry.TransitionHook {
+	return func(ctx context.Context, t telemetry.Transition) {
+		Publish(b, LifecycleChanged, LifecycleChangedEvent{Transition: t})
+	}
+}
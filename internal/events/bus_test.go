@@ -0,0 +1,45 @@
+package events
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	b := NewBus(4)
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	Subscribe(b, AdmissionDenied, func(ev AdmissionDeniedEvent) {
+		mu.Lock()
+		got = append(got, ev.PolicyID)
+		mu.Unlock()
+	})
+	// A subscriber on another topic must not see these events.
+	Subscribe(b, PolicyReloaded, func(PolicyReloadedEvent) { t.Error("unexpected PolicyReloaded event") })
+
+	Publish(b, AdmissionDenied, AdmissionDeniedEvent{PolicyID: "L4"})
+	Publish(b, AdmissionDenied, AdmissionDeniedEvent{PolicyID: "L5"})
+	b.Close()
+
+	if len(got) != 2 || got[0] != "L4" || got[1] != "L5" {
+		t.Errorf("received %v, want [L4 L5]", got)
+	}
+}
+
+func TestBoundedQueueDrops(t *testing.T) {
+	b := NewBus(1)
+	block := make(chan struct{})
+	Subscribe(b, GovernanceUpdated, func(GovernanceUpdatedEvent) { <-block })
+
+	for i := 0; i < 5; i++ {
+		Publish(b, GovernanceUpdated, GovernanceUpdatedEvent{})
+	}
+	// At most one event is in the handler and one queued; the rest are dropped.
+	if d := b.Dropped(); d < 3 {
+		t.Errorf("Dropped() = %d, want at least 3", d)
+	}
+	close(block)
+	b.Close()
+}
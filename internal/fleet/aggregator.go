@@ -0,0 +1,201 @@
+// Package fleet aggregates telemetry and integrity state reported by remote STS instances.
+package fleet
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"internal/crot"
+	"internal/events"
+	"services/telemetry"
+)
+
+const (
+	defaultNodeTTL     = 2 * time.Minute
+	defaultForgetAfter = 24 * time.Hour
+)
+
+// AggregatorConfig configures stale-node handling. The zero value applies defaults.
+type AggregatorConfig struct {
+	// NodeTTL is how long a node may go without reporting before it is marked missing (default 2m).
+	// Negative disables expiry.
+	NodeTTL time.Duration
+	// ForgetAfter is how long after its last report a missing node is dropped entirely (default 24h).
+	ForgetAfter time.Duration
+	// Bus, if set, receives a NodeMissing event for each node that expires.
+	Bus *events.Bus
+}
+
+// NodeState is the aggregator's view of a single STS instance.
+type NodeState struct {
+	NodeID              string                  `json:"node_id"`
+	Telemetry           telemetry.TelemetryData `json:"telemetry"`
+	Integrity           crot.IntegrityStatus    `json:"integrity"`
+	IntegrityVerifiedAt time.Time               `json:"integrity_verified_at"`
+	LastSeen            time.Time               `json:"last_seen"`
+	Missing             bool                    `json:"missing"` // Stopped reporting for longer than NodeTTL
+}
+
+// Posture summarizes GATM state across the fleet. A missing node is a signal in its own right:
+// its last snapshot no longer describes it, so it is counted as missing rather than by that snapshot.
+type Posture struct {
+	Nodes        int      `json:"nodes"`
+	Violating    int      `json:"violating"` // Reporting nodes whose latest snapshot violates GATM
+	Missing      int      `json:"missing"`
+	MissingNodes []string `json:"missing_nodes,omitempty"`
+}
+
+// Healthy reports whether every node is reporting and none violates GATM.
+func (p Posture) Healthy() bool {
+	return p.Violating == 0 && p.Missing == 0
+}
+
+// Aggregator is the thread-safe, in-memory fleet view.
+type Aggregator struct {
+	cfg   AggregatorConfig
+	now   func() time.Time
+	nodes map[string]*NodeState
+	mu    sync.RWMutex
+}
+
+// NewAggregator creates an empty fleet aggregator.
+func NewAggregator(cfg AggregatorConfig) *Aggregator {
+	if cfg.NodeTTL == 0 {
+		cfg.NodeTTL = defaultNodeTTL
+	}
+	if cfg.ForgetAfter == 0 {
+		cfg.ForgetAfter = defaultForgetAfter
+	}
+	return &Aggregator{cfg: cfg, now: time.Now, nodes: make(map[string]*NodeState)}
+}
+
+// RecordTelemetry stores the latest telemetry snapshot reported by a node.
+func (a *Aggregator) RecordTelemetry(nodeID string, data telemetry.TelemetryData) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	node := a.nodeLocked(nodeID)
+	node.Telemetry = data
+	node.LastSeen = a.now()
+	node.Missing = false
+}
+
+// UpdateIntegrity records the verified integrity status of a node.
+// Verified results take precedence over the node's self-reported IntegrityHashChainStatus.
+func (a *Aggregator) UpdateIntegrity(nodeID string, status crot.IntegrityStatus, verifiedAt time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	node := a.nodeLocked(nodeID)
+	node.Integrity = status
+	node.IntegrityVerifiedAt = verifiedAt
+	node.LastSeen = a.now()
+	node.Missing = false
+}
+
+// IntegrityView returns the verified integrity status per node.
+// Nodes that have never been verified, or have gone missing, are reported as UNREACHABLE.
+func (a *Aggregator) IntegrityView() map[string]crot.IntegrityStatus {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	view := make(map[string]crot.IntegrityStatus, len(a.nodes))
+	for id, node := range a.nodes {
+		if node.Integrity == "" || node.Missing {
+			view[id] = crot.IntegrityUnreachable
+			continue
+		}
+		view[id] = node.Integrity
+	}
+	return view
+}
+
+// Snapshot returns a copy of every node's state, ordered by node ID.
+func (a *Aggregator) Snapshot() []NodeState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	out := make([]NodeState, 0, len(a.nodes))
+	for _, node := range a.nodes {
+		out = append(out, *node)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out
+}
+
+// Posture summarizes the fleet's current GATM state.
+func (a *Aggregator) Posture() Posture {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	p := Posture{Nodes: len(a.nodes)}
+	for id, node := range a.nodes {
+		switch {
+		case node.Missing:
+			p.Missing++
+			p.MissingNodes = append(p.MissingNodes, id)
+		case node.Telemetry.IsGATMViolating:
+			p.Violating++
+		}
+	}
+	sort.Strings(p.MissingNodes)
+	return p
+}
+
+// Expire marks nodes that have not reported within NodeTTL as missing, publishing a NodeMissing
+// event for each, and drops nodes silent for longer than ForgetAfter. It returns the newly
+// missing node IDs.
+func (a *Aggregator) Expire() []string {
+	if a.cfg.NodeTTL < 0 {
+		return nil
+	}
+	now := a.now()
+	var expired []events.NodeMissingEvent
+
+	a.mu.Lock()
+	for id, node := range a.nodes {
+		silent := now.Sub(node.LastSeen)
+		switch {
+		case silent > a.cfg.ForgetAfter:
+			delete(a.nodes, id)
+		case silent > a.cfg.NodeTTL && !node.Missing:
+			node.Missing = true
+			expired = append(expired, events.NodeMissingEvent{NodeID: id, LastSeen: node.LastSeen, At: now})
+		}
+	}
+	a.mu.Unlock()
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].NodeID < expired[j].NodeID })
+	ids := make([]string, 0, len(expired))
+	for _, ev := range expired {
+		if a.cfg.Bus != nil {
+			events.Publish(a.cfg.Bus, events.NodeMissing, ev)
+		}
+		ids = append(ids, ev.NodeID)
+	}
+	return ids
+}
+
+// Run calls Expire every half NodeTTL until ctx is cancelled. It returns immediately if expiry is disabled.
+func (a *Aggregator) Run(ctx context.Context) {
+	if a.cfg.NodeTTL < 0 {
+		return
+	}
+	ticker := time.NewTicker(a.cfg.NodeTTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Expire()
+		}
+	}
+}
+
+// nodeLocked returns the state for nodeID, creating it if necessary. Callers must hold a.mu.
+func (a *Aggregator) nodeLocked(nodeID string) *NodeState {
+	node, ok := a.nodes[nodeID]
+	if !ok {
+		node = &NodeState{NodeID: nodeID}
+		a.nodes[nodeID] = node
+	}
+	return node
+}
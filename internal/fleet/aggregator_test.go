@@ -0,0 +1,64 @@
+package fleet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"internal/crot"
+	"internal/events"
+	"services/telemetry"
+)
+
+func TestAggregatorExpiresSilentNodes(t *testing.T) {
+	bus := events.NewBus(8)
+	defer bus.Close()
+	missing := make(chan events.NodeMissingEvent, 4)
+	events.Subscribe(bus, events.NodeMissing, func(ev events.NodeMissingEvent) { missing <- ev })
+
+	now := time.Unix(1000, 0)
+	a := NewAggregator(AggregatorConfig{NodeTTL: time.Minute, ForgetAfter: time.Hour, Bus: bus})
+	a.now = func() time.Time { return now }
+
+	a.RecordTelemetry("node-a", telemetry.TelemetryData{})
+	a.RecordTelemetry("node-b", telemetry.TelemetryData{IsGATMViolating: true})
+	a.UpdateIntegrity("node-a", crot.IntegritySynced, now)
+
+	now = now.Add(45 * time.Second)
+	a.RecordTelemetry("node-b", telemetry.TelemetryData{IsGATMViolating: true})
+	now = now.Add(30 * time.Second)
+
+	if got := a.Expire(); !reflect.DeepEqual(got, []string{"node-a"}) {
+		t.Fatalf("Expire() = %v, want [node-a]", got)
+	}
+	if got := a.Expire(); len(got) != 0 {
+		t.Errorf("second Expire() = %v, want no newly missing nodes", got)
+	}
+	select {
+	case ev := <-missing:
+		if ev.NodeID != "node-a" || !ev.LastSeen.Equal(time.Unix(1000, 0)) {
+			t.Errorf("NodeMissing event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no NodeMissing event published")
+	}
+
+	want := Posture{Nodes: 2, Violating: 1, Missing: 1, MissingNodes: []string{"node-a"}}
+	if got := a.Posture(); !reflect.DeepEqual(got, want) || got.Healthy() {
+		t.Errorf("Posture() = %+v, want %+v (unhealthy)", got, want)
+	}
+	if got := a.IntegrityView()["node-a"]; got != crot.IntegrityUnreachable {
+		t.Errorf("missing node integrity = %s, want %s", got, crot.IntegrityUnreachable)
+	}
+
+	// A report brings the node back; prolonged silence removes it entirely.
+	a.RecordTelemetry("node-a", telemetry.TelemetryData{})
+	if p := a.Posture(); p.Missing != 0 {
+		t.Errorf("Posture().Missing = %d after node reported again, want 0", p.Missing)
+	}
+	now = now.Add(2 * time.Hour)
+	a.Expire()
+	if got := a.Snapshot(); len(got) != 0 {
+		t.Errorf("Snapshot() = %d nodes after ForgetAfter, want 0", len(got))
+	}
+}
@@ -0,0 +1,151 @@
+package inventory
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"core/governance"
+)
+
+const defaultComplianceInterval = 15 * time.Minute
+
+// NodeCompliance is one node's result against the baseline policy.
+type NodeCompliance struct {
+	NodeID       string    `json:"node_id"`
+	Compliant    bool      `json:"compliant"`
+	Failing      []string  `json:"failing,omitempty"` // Constraints the node does not meet
+	Error        string    `json:"error,omitempty"`   // Why the node could not be evaluated
+	DiscoveredAt time.Time `json:"discovered_at"`     // When the evaluated context was collected
+}
+
+// ComplianceReport is the fleet's compliance with a baseline policy at one point in time.
+type ComplianceReport struct {
+	Policy        string           `json:"policy"`
+	BundleVersion string           `json:"bundle_version,omitempty"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Compliant     int              `json:"compliant"`
+	NonCompliant  int              `json:"non_compliant"`
+	Nodes         []NodeCompliance `json:"nodes"` // Ordered by node ID
+}
+
+// WriteCSV writes the report as CSV, one row per node. Failing constraints are joined with ";".
+func (r ComplianceReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"node_id", "policy", "compliant", "failing", "error", "discovered_at", "generated_at"})
+	for _, n := range r.Nodes {
+		cw.Write([]string{
+			n.NodeID, r.Policy, strconv.FormatBool(n.Compliant), strings.Join(n.Failing, ";"), n.Error,
+			n.DiscoveredAt.UTC().Format(time.RFC3339), r.GeneratedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ComplianceConfig configures a ComplianceReporter.
+type ComplianceConfig struct {
+	Registry Registry
+	// Engine returns the engine to evaluate with, e.g. admission.Warmer.Engine, so reports follow
+	// manifest reloads.
+	Engine func() *governance.PolicyAdmissionEngine
+	// BaselinePolicy is the policy every node is expected to meet.
+	BaselinePolicy string
+	// Interval between reports (default 15m).
+	Interval time.Duration
+	// OnError, if set, receives failures of periodic reports; the previous report is kept.
+	OnError func(err error)
+}
+
+// ComplianceReporter periodically evaluates every registered node against a baseline policy.
+type ComplianceReporter struct {
+	cfg ComplianceConfig
+
+	mu     sync.RWMutex
+	latest *ComplianceReport
+}
+
+// NewComplianceReporter creates a reporter. Call Generate or Run to produce reports.
+func NewComplianceReporter(cfg ComplianceConfig) (*ComplianceReporter, error) {
+	if cfg.Registry == nil || cfg.Engine == nil || cfg.BaselinePolicy == "" {
+		return nil, errors.New("compliance reporter requires Registry, Engine and BaselinePolicy")
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultComplianceInterval
+	}
+	return &ComplianceReporter{cfg: cfg}, nil
+}
+
+// Generate evaluates every registered node now and keeps the result as the latest report. A node
+// whose context cannot be evaluated is reported non-compliant with the error.
+func (c *ComplianceReporter) Generate(ctx context.Context) (ComplianceReport, error) {
+	engine := c.cfg.Engine()
+	if engine == nil {
+		return ComplianceReport{}, errors.New("no policy engine is loaded")
+	}
+	if _, ok := engine.Policies[c.cfg.BaselinePolicy]; !ok {
+		return ComplianceReport{}, fmt.Errorf("baseline %w: '%s'", governance.ErrPolicyNotFound, c.cfg.BaselinePolicy)
+	}
+	nodes, err := c.cfg.Registry.Query(ctx, Selector{})
+	if err != nil {
+		return ComplianceReport{}, fmt.Errorf("inventory query failed: %w", err)
+	}
+
+	report := ComplianceReport{
+		Policy:        c.cfg.BaselinePolicy,
+		BundleVersion: engine.BundleVersion,
+		GeneratedAt:   time.Now(),
+		Nodes:         make([]NodeCompliance, 0, len(nodes)),
+	}
+	for _, rec := range nodes {
+		n := NodeCompliance{NodeID: rec.NodeID, DiscoveredAt: rec.DiscoveredAt}
+		n.Failing, err = engine.UnsatisfiedConstraints(c.cfg.BaselinePolicy, rec.Context)
+		if err != nil {
+			n.Error = err.Error()
+		}
+		n.Compliant = err == nil && len(n.Failing) == 0
+		if n.Compliant {
+			report.Compliant++
+		} else {
+			report.NonCompliant++
+		}
+		report.Nodes = append(report.Nodes, n)
+	}
+
+	c.mu.Lock()
+	c.latest = &report
+	c.mu.Unlock()
+	return report, nil
+}
+
+// Latest returns the most recent report, if one was generated.
+func (c *ComplianceReporter) Latest() (ComplianceReport, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.latest == nil {
+		return ComplianceReport{}, false
+	}
+	return *c.latest, true
+}
+
+// Run generates a report immediately and then every Interval until ctx is cancelled.
+func (c *ComplianceReporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Generate(ctx); err != nil && c.cfg.OnError != nil {
+			c.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
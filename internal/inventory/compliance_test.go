@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"core/governance"
+)
+
+func newTestReporter(t *testing.T, policy string) *ComplianceReporter {
+	t.Helper()
+	reg, engine := newTestRegistry(t), newTestEngine(t)
+	c, err := NewComplianceReporter(ComplianceConfig{Registry: reg, Engine: func() *governance.PolicyAdmissionEngine { return engine }, BaselinePolicy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestComplianceReporter_Generate(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		policy       string
+		nonCompliant int
+		nodes        []NodeCompliance // Error holds a substring of the node's error
+	}{
+		{
+			name: "Baseline", policy: "baseline", nonCompliant: 2,
+			nodes: []NodeCompliance{
+				{NodeID: "a", Compliant: true, DiscoveredAt: at},
+				{NodeID: "b", Failing: []string{"OS.SELinux.Enforcing"}, DiscoveredAt: at},
+				{NodeID: "c", Failing: []string{"OS.SecureBoot"}, DiscoveredAt: at},
+			},
+		},
+		{
+			name: "Evaluation Error", policy: "tee", nonCompliant: 2,
+			nodes: []NodeCompliance{
+				{NodeID: "a", Compliant: true, DiscoveredAt: at},
+				{NodeID: "b", Failing: []string{"Hardware.TEE.Version"}, DiscoveredAt: at},
+				{NodeID: "c", Error: "version 'beta' is not dotted numeric", DiscoveredAt: at},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestReporter(t, tt.policy)
+			if _, ok := c.Latest(); ok {
+				t.Fatal("Latest() returned a report before Generate")
+			}
+			report, err := c.Generate(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Policy != tt.policy || report.Compliant != len(tt.nodes)-tt.nonCompliant || report.NonCompliant != tt.nonCompliant {
+				t.Errorf("report = %s with %d compliant and %d non-compliant, want %s with %d and %d",
+					report.Policy, report.Compliant, report.NonCompliant, tt.policy, len(tt.nodes)-tt.nonCompliant, tt.nonCompliant)
+			}
+			if latest, ok := c.Latest(); !ok || !reflect.DeepEqual(latest, report) {
+				t.Errorf("Latest() = %+v, %v, want the generated report", latest, ok)
+			}
+
+			if len(report.Nodes) != len(tt.nodes) {
+				t.Fatalf("report has %d nodes, want %d", len(report.Nodes), len(tt.nodes))
+			}
+			for i, got := range report.Nodes {
+				want := tt.nodes[i]
+				if !strings.Contains(got.Error, want.Error) || (got.Error == "") != (want.Error == "") {
+					t.Errorf("node %s error = %q, want %q", got.NodeID, got.Error, want.Error)
+				}
+				got.Error, want.Error = "", ""
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("node %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestComplianceReporter_Errors(t *testing.T) {
+	if _, err := NewComplianceReporter(ComplianceConfig{Registry: NewMemoryRegistry(), BaselinePolicy: "baseline"}); err == nil {
+		t.Error("NewComplianceReporter() without an engine succeeded")
+	}
+
+	c := newTestReporter(t, "missing")
+	if _, err := c.Generate(context.Background()); !errors.Is(err, governance.ErrPolicyNotFound) {
+		t.Errorf("Generate() with an unknown baseline error = %v, want ErrPolicyNotFound", err)
+	}
+	if _, ok := c.Latest(); ok {
+		t.Error("Latest() returned a report after a failed Generate")
+	}
+
+	c, err := NewComplianceReporter(ComplianceConfig{Registry: NewMemoryRegistry(), Engine: func() *governance.PolicyAdmissionEngine { return nil }, BaselinePolicy: "baseline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Generate(context.Background()); err == nil {
+		t.Error("Generate() without a loaded engine succeeded")
+	}
+}
+
+func TestComplianceReport_WriteCSV(t *testing.T) {
+	// Times are written in UTC whatever their location.
+	discovered := time.Date(2026, 3, 1, 13, 0, 0, 0, time.FixedZone("CET", 3600))
+	report := ComplianceReport{
+		Policy:      "baseline",
+		GeneratedAt: time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC),
+		Nodes: []NodeCompliance{
+			{NodeID: "a", Compliant: true, DiscoveredAt: discovered},
+			{NodeID: "b", Failing: []string{"OS.SecureBoot", "OS.SELinux.Enforcing"}, DiscoveredAt: discovered},
+			{NodeID: "c", Error: "version 'beta', not numeric", DiscoveredAt: discovered},
+		},
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `node_id,policy,compliant,failing,error,discovered_at,generated_at
+a,baseline,true,,,2026-03-01T12:00:00Z,2026-03-02T08:30:00Z
+b,baseline,false,OS.SecureBoot;OS.SELinux.Enforcing,,2026-03-01T12:00:00Z,2026-03-02T08:30:00Z
+c,baseline,false,,"version 'beta', not numeric",2026-03-01T12:00:00Z,2026-03-02T08:30:00Z
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
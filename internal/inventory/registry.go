@@ -0,0 +1,130 @@
+// Package inventory tracks the discovered SystemContext of each node so admission decisions and
+// fleet scheduling can target nodes by capability.
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"core/governance"
+)
+
+// NodeRecord is the most recent capability discovery result for a node.
+type NodeRecord struct {
+	NodeID       string                   `json:"node_id"`
+	Context      governance.SystemContext `json:"context"`
+	DiscoveredAt time.Time                `json:"discovered_at"`
+}
+
+// Selector filters nodes by capability. Zero-valued fields match any node.
+type Selector struct {
+	TEE             bool   `json:"tee,omitempty"`
+	SRIOV           bool   `json:"sr_iov,omitempty"`
+	MinMemoryBytes  uint64 `json:"min_memory_bytes,omitempty"`
+	CPUArchitecture string `json:"cpu_architecture,omitempty"`
+}
+
+// Matches reports whether the system context satisfies every set field of the selector.
+func (s Selector) Matches(sc governance.SystemContext) bool {
+	hw := sc.Hardware
+	switch {
+	case s.TEE && !hw.TEE.Supported:
+		return false
+	case s.SRIOV && !hw.SR_IOV_Enabled:
+		return false
+	case hw.MemoryBytes < s.MinMemoryBytes:
+		return false
+	case s.CPUArchitecture != "" && hw.CPUArchitecture != s.CPUArchitecture:
+		return false
+	}
+	return true
+}
+
+// Registry stores node capability records. MemoryRegistry serves a single process; fleet-wide
+// deployments back the same interface with shared storage.
+type Registry interface {
+	Register(ctx context.Context, rec NodeRecord) error
+	Lookup(ctx context.Context, nodeID string) (NodeRecord, bool, error)
+	Query(ctx context.Context, sel Selector) ([]NodeRecord, error)
+	Remove(ctx context.Context, nodeID string) error
+}
+
+// MemoryRegistry is the in-process Registry implementation.
+type MemoryRegistry struct {
+	nodes map[string]NodeRecord
+	mu    sync.RWMutex
+}
+
+// NewMemoryRegistry creates an empty registry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{nodes: make(map[string]NodeRecord)}
+}
+
+// Register stores or replaces the record for rec.NodeID. A zero DiscoveredAt is set to now.
+func (r *MemoryRegistry) Register(ctx context.Context, rec NodeRecord) error {
+	if rec.NodeID == "" {
+		return fmt.Errorf("inventory: node ID is required")
+	}
+	if rec.DiscoveredAt.IsZero() {
+		rec.DiscoveredAt = time.Now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nodes[rec.NodeID] = rec
+	return nil
+}
+
+// Lookup returns the record for nodeID, if registered.
+func (r *MemoryRegistry) Lookup(ctx context.Context, nodeID string) (NodeRecord, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rec, ok := r.nodes[nodeID]
+	return rec, ok, nil
+}
+
+// Query returns the records matching sel, ordered by node ID.
+func (r *MemoryRegistry) Query(ctx context.Context, sel Selector) ([]NodeRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var out []NodeRecord
+	for _, rec := range r.nodes {
+		if sel.Matches(rec.Context) {
+			out = append(out, rec)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out, nil
+}
+
+// Remove deletes the record for nodeID. Removing an unknown node is not an error.
+func (r *MemoryRegistry) Remove(ctx context.Context, nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.nodes, nodeID)
+	return nil
+}
+
+// AdmissibleNodes evaluates policyID against every registered node and returns those admitted,
+// ordered by node ID. Per-node rejections are expected and not reported; an unknown policy is.
+func AdmissibleNodes(ctx context.Context, reg Registry, engine *governance.PolicyAdmissionEngine, policyID string) ([]NodeRecord, error) {
+	if _, ok := engine.Policies[policyID]; !ok {
+		return nil, fmt.Errorf("%w: '%s'", governance.ErrPolicyNotFound, policyID)
+	}
+	nodes, err := reg.Query(ctx, Selector{})
+	if err != nil {
+		return nil, fmt.Errorf("inventory query failed: %w", err)
+	}
+	var admitted []NodeRecord
+	for _, rec := range nodes {
+		if ok, _ := engine.EvaluateRequest(policyID, rec.Context); ok {
+			admitted = append(admitted, rec)
+		}
+	}
+	return admitted, nil
+}
+
+// Ensure MemoryRegistry implements the Registry interface.
+var _ Registry = (*MemoryRegistry)(nil)
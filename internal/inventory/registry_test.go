@@ -0,0 +1,141 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"core/governance"
+)
+
+const testManifest = `{"schema_version": "V2.0-POLI-STRUCT", "policies": [
+	{"id": "baseline", "constraints": [{"key": "OS.SecureBoot", "required": true}, {"key": "OS.SELinux.Enforcing", "required": true}]},
+	{"id": "tee", "constraints": [{"key": "Hardware.TEE.Version", "min_version": "2"}]}
+]}`
+
+func newTestEngine(t *testing.T) *governance.PolicyAdmissionEngine {
+	t.Helper()
+	engine, err := governance.NewPolicyAdmissionEngineFromReader(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine
+}
+
+// newTestRegistry registers:
+//   - "a": secure boot and SELinux enforcing, SGX 2, SR-IOV, 64 GiB amd64
+//   - "b": secure boot only, no TEE, 16 GiB arm64
+//   - "c": SELinux enforcing only, a TEE version that cannot be compared, 32 GiB amd64
+func newTestRegistry(t *testing.T) *MemoryRegistry {
+	t.Helper()
+	const gib = 1 << 30
+	reg := NewMemoryRegistry()
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, rec := range []NodeRecord{
+		{NodeID: "c", DiscoveredAt: at, Context: governance.SystemContext{
+			Hardware: governance.HardwareContext{TEE: governance.TEEInfo{Supported: true, Version: "beta"}, MemoryBytes: 32 * gib, CPUArchitecture: "amd64"},
+			OS:       governance.OSContext{SELinux: "enforcing"},
+		}},
+		{NodeID: "a", DiscoveredAt: at, Context: governance.SystemContext{
+			Hardware: governance.HardwareContext{TEE: governance.TEEInfo{Supported: true, Technology: "SGX", Version: "2"}, SR_IOV_Enabled: true, MemoryBytes: 64 * gib, CPUArchitecture: "amd64"},
+			OS:       governance.OSContext{SecureBoot: true, SELinux: "enforcing"},
+		}},
+		{NodeID: "b", DiscoveredAt: at, Context: governance.SystemContext{
+			Hardware: governance.HardwareContext{MemoryBytes: 16 * gib, CPUArchitecture: "arm64"},
+			OS:       governance.OSContext{SecureBoot: true, SELinux: "permissive"},
+		}},
+	} {
+		if err := reg.Register(context.Background(), rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return reg
+}
+
+func nodeIDs(recs []NodeRecord) string {
+	ids := make([]string, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.NodeID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestMemoryRegistry_Query(t *testing.T) {
+	reg := newTestRegistry(t)
+	tests := []struct {
+		name string
+		sel  Selector
+		want string
+	}{
+		{name: "Any", want: "a,b,c"},
+		{name: "TEE", sel: Selector{TEE: true}, want: "a,c"},
+		{name: "SR-IOV", sel: Selector{SRIOV: true}, want: "a"},
+		{name: "Minimum Memory Inclusive", sel: Selector{MinMemoryBytes: 32 << 30}, want: "a,c"},
+		{name: "Architecture", sel: Selector{CPUArchitecture: "arm64"}, want: "b"},
+		{name: "Combined", sel: Selector{TEE: true, CPUArchitecture: "amd64", MinMemoryBytes: 48 << 30}, want: "a"},
+		{name: "None", sel: Selector{SRIOV: true, CPUArchitecture: "arm64"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reg.Query(context.Background(), tt.sel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := nodeIDs(got); ids != tt.want {
+				t.Errorf("Query(%+v) = %q, want %q", tt.sel, ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryRegistry_RegisterLookupRemove(t *testing.T) {
+	ctx := context.Background()
+	reg := NewMemoryRegistry()
+	if err := reg.Register(ctx, NodeRecord{}); err == nil {
+		t.Error("Register() without a node ID succeeded")
+	}
+
+	before := time.Now()
+	if err := reg.Register(ctx, NodeRecord{NodeID: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	rec, ok, err := reg.Lookup(ctx, "a")
+	if err != nil || !ok || rec.DiscoveredAt.Before(before) {
+		t.Fatalf("Lookup(a) = %+v, %v, %v; want the record stamped on registration", rec, ok, err)
+	}
+
+	// Registering again replaces the record.
+	if err := reg.Register(ctx, NodeRecord{NodeID: "a", Context: governance.SystemContext{Hardware: governance.HardwareContext{CPUArchitecture: "arm64"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if rec, _, _ := reg.Lookup(ctx, "a"); rec.Context.Hardware.CPUArchitecture != "arm64" {
+		t.Errorf("Lookup(a) after re-registration = %+v, want the new context", rec)
+	}
+
+	if err := reg.Remove(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Remove(ctx, "a"); err != nil {
+		t.Errorf("Remove() of an unknown node error = %v", err)
+	}
+	if _, ok, _ := reg.Lookup(ctx, "a"); ok {
+		t.Error("Lookup(a) found a removed node")
+	}
+}
+
+func TestAdmissibleNodes(t *testing.T) {
+	reg, engine := newTestRegistry(t), newTestEngine(t)
+	got, err := AdmissibleNodes(context.Background(), reg, engine, "tee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Node c's TEE version cannot be evaluated; like any other rejection it is left out.
+	if ids := nodeIDs(got); ids != "a" {
+		t.Errorf("AdmissibleNodes(tee) = %q, want %q", ids, "a")
+	}
+	if _, err := AdmissibleNodes(context.Background(), reg, engine, "missing"); !errors.Is(err, governance.ErrPolicyNotFound) {
+		t.Errorf("AdmissibleNodes(missing) error = %v, want ErrPolicyNotFound", err)
+	}
+}
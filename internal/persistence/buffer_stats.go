@@ -0,0 +1,150 @@
+package persistence
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"services/telemetry"
+)
+
+// FieldStats summarizes one numeric TelemetryData field over a window.
+type FieldStats struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	P95  float64 `json:"p95"`
+}
+
+// WindowStats aggregates the records stored in a CircularBufferSink over a time window.
+type WindowStats struct {
+	Count int       `json:"count"`
+	From  time.Time `json:"from"` // Timestamp of the oldest record included
+	To    time.Time `json:"to"`   // Timestamp of the newest record included
+
+	PipelineLatency_S9 FieldStats `json:"pipeline_latency_s9"`
+	ResourceLoad_Pct   FieldStats `json:"resource_load_pct"`
+	GATMBreachCount    FieldStats `json:"gatm_breach_count"`
+
+	// LatencyHistogram merges the latency distributions of the records reporting one, on the
+	// buckets of the oldest; nil if none did. LatencyP99 is estimated from it. Malformed
+	// histograms are left out and counted in InvalidHistograms.
+	LatencyHistogram  *telemetry.Histogram `json:"latency_histogram,omitempty"`
+	LatencyP99        float64              `json:"latency_p99,omitempty"`
+	InvalidHistograms int                  `json:"invalid_histograms,omitempty"`
+
+	ViolationRatio    float64            `json:"violation_ratio"`    // Fraction of records with IsGATMViolating
+	SilencedRatio     float64            `json:"silenced_ratio"`     // Fraction of records with IsSilenced
+	AcknowledgedRatio float64            `json:"acknowledged_ratio"` // Fraction of records with IsAcknowledged
+	CauseRatios       map[string]float64 `json:"cause_ratios"`       // Fraction of records reporting each violation cause
+}
+
+// Stats aggregates the records within window of the newest stored record. A zero window covers
+// everything stored. Records are read in place under the buffer's read lock; only the numeric
+// columns needed for percentiles are copied.
+func (s *CircularBufferSink) Stats(window time.Duration) WindowStats {
+	var (
+		stats                             WindowStats
+		latency, load, breaches           []float64
+		violating, silenced, acknowledged int
+		causes                            = make(map[string]int)
+		histogram                         *telemetry.Histogram
+	)
+
+	s.buffer.Peek(s.buffer.Cap(), func(first, second []telemetry.TelemetryData) {
+		total := len(first) + len(second)
+		at := func(i int) *telemetry.TelemetryData {
+			if i < len(first) {
+				return &first[i]
+			}
+			return &second[i-len(first)]
+		}
+		if total == 0 {
+			return
+		}
+
+		newest := at(total - 1).Timestamp
+		// Walk backwards from the newest record until the window is exhausted.
+		start := total
+		for start > 0 {
+			if window > 0 && newest.Sub(at(start-1).Timestamp) > window {
+				break
+			}
+			start--
+		}
+
+		n := total - start
+		latency = make([]float64, 0, n)
+		load = make([]float64, 0, n)
+		breaches = make([]float64, 0, n)
+		for i := start; i < total; i++ {
+			td := at(i)
+			latency = append(latency, td.PipelineLatencyS9.Seconds())
+			load = append(load, td.ResourceLoad_Pct)
+			breaches = append(breaches, float64(td.GATMBreachCount))
+			if td.IsGATMViolating {
+				violating++
+			}
+			if td.IsSilenced {
+				silenced++
+			}
+			if td.IsAcknowledged {
+				acknowledged++
+			}
+			for _, c := range td.ViolationCauses {
+				causes[c]++
+			}
+			switch {
+			case td.LatencyHistogram == nil:
+			case td.LatencyHistogram.Validate() != nil:
+				stats.InvalidHistograms++
+			case histogram == nil:
+				histogram = td.LatencyHistogram.Clone()
+			default:
+				if err := histogram.Merge(td.LatencyHistogram); err != nil {
+					stats.InvalidHistograms++
+				}
+			}
+		}
+		if n > 0 {
+			stats.From, stats.To = at(start).Timestamp, newest
+		}
+	})
+
+	stats.Count = len(latency)
+	stats.CauseRatios = make(map[string]float64, len(causes))
+	if stats.Count == 0 {
+		return stats
+	}
+	total := float64(stats.Count)
+	stats.PipelineLatency_S9 = summarize(latency)
+	stats.ResourceLoad_Pct = summarize(load)
+	stats.GATMBreachCount = summarize(breaches)
+	stats.ViolationRatio = float64(violating) / total
+	stats.SilencedRatio = float64(silenced) / total
+	stats.AcknowledgedRatio = float64(acknowledged) / total
+	for c, count := range causes {
+		stats.CauseRatios[c] = float64(count) / total
+	}
+	if histogram != nil {
+		stats.LatencyHistogram = histogram
+		stats.LatencyP99 = histogram.Quantile(0.99)
+	}
+	return stats
+}
+
+// summarize computes min/max/mean and the nearest-rank 95th percentile. values is sorted in place.
+func summarize(values []float64) FieldStats {
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+	return FieldStats{
+		Min:  values[0],
+		Max:  values[len(values)-1],
+		Mean: sum / float64(len(values)),
+		P95:  values[rank],
+	}
+}
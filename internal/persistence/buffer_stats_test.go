@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"services/telemetry"
+)
+
+func TestCircularBufferSink_Stats(t *testing.T) {
+	s := NewCircularBufferSink(16)
+	// A buffer of 16 keeps records 9..24; the oldest eight are overwritten.
+	for i := 1; i <= 24; i++ {
+		td := record(i)
+		td.PipelineLatencyS9 = time.Duration(i) * time.Second
+		td.IsGATMViolating = i%4 == 0
+		td.IsSilenced = i%8 == 0
+		if td.IsGATMViolating {
+			td.ViolationCauses = []string{"latency"}
+		}
+		if err := s.Record(context.Background(), td); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		count     int
+		from      int64
+		p95       float64
+		violating float64
+		silenced  float64
+	}{
+		// Records 9..24; nearest-rank p95 of 16 values is the 16th.
+		{name: "Everything", window: 0, count: 16, from: 9, p95: 24, violating: 4.0 / 16, silenced: 2.0 / 16},
+		// Records 5s or less older than the newest: 19..24; p95 rank ceil(5.7) = 6.
+		{name: "Window", window: 5 * time.Second, count: 6, from: 19, p95: 24, violating: 2.0 / 6, silenced: 1.0 / 6},
+		{name: "Newest Only", window: time.Nanosecond, count: 1, from: 24, p95: 24, violating: 1, silenced: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := s.Stats(tt.window)
+			if stats.Count != tt.count || stats.From.Unix() != tt.from || stats.To.Unix() != 24 {
+				t.Fatalf("Stats = count %d from %v to %v, want %d from %ds to 24s", stats.Count, stats.From, stats.To, tt.count, tt.from)
+			}
+			if got := stats.PipelineLatency_S9; got.Min != float64(tt.from) || got.Max != 24 || got.P95 != tt.p95 {
+				t.Errorf("latency = %+v, want min %d max 24 p95 %v", got, tt.from, tt.p95)
+			}
+			if stats.ViolationRatio != tt.violating || stats.CauseRatios["latency"] != tt.violating || stats.SilencedRatio != tt.silenced {
+				t.Errorf("ratios = violating %v causes %v silenced %v, want %v and %v", stats.ViolationRatio, stats.CauseRatios, stats.SilencedRatio, tt.violating, tt.silenced)
+			}
+		})
+	}
+
+	if stats := NewCircularBufferSink(4).Stats(0); stats.Count != 0 || stats.CauseRatios == nil {
+		t.Errorf("Stats of an empty buffer = %+v, want zero count and an empty cause map", stats)
+	}
+}
+
+func TestSummarize_NearestRankP95(t *testing.T) {
+	values := make([]float64, 0, 20)
+	for i := 20; i >= 1; i-- {
+		values = append(values, float64(i))
+	}
+	got := summarize(values)
+	if want := (FieldStats{Min: 1, Max: 20, Mean: 10.5, P95: 19}); got != want {
+		t.Errorf("summarize(1..20) = %+v, want %+v", got, want)
+	}
+}
+
+func TestCircularBufferSink_StatsSkipsInvalidHistograms(t *testing.T) {
+	s := NewCircularBufferSink(8)
+	histograms := []*telemetry.Histogram{
+		{Bounds: []float64{1, 2}, Counts: []uint64{1}}, // One count for three buckets
+		{Bounds: []float64{1, 2}, Counts: []uint64{1, 0, 0}, Sum: 0.5},
+		{Bounds: []float64{1, 2}, Counts: []uint64{0, 2, 0}, Sum: 3},
+		{Bounds: []float64{2, 1}, Counts: []uint64{0, 0, 5}}, // Bounds not ascending
+	}
+	for i, h := range histograms {
+		td := record(i + 1)
+		td.LatencyHistogram = h
+		if err := s.Record(context.Background(), td); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := s.Stats(0)
+	if stats.InvalidHistograms != 2 {
+		t.Errorf("InvalidHistograms = %d, want 2", stats.InvalidHistograms)
+	}
+	if h := stats.LatencyHistogram; h == nil || h.Count() != 3 || h.Sum != 3.5 {
+		t.Errorf("LatencyHistogram = %+v, want the two valid histograms merged", h)
+	}
+}
@@ -3,67 +3,67 @@ package persistence
 import (
 	"context"
 	"sync"
+
+	"pkg/ringbuf"
 	"services/telemetry"
 )
 
 // CircularBufferSink implements the telemetry.TelemetrySink interface using a fixed-size in-memory buffer.
 // This is used for quick trend analysis and providing historical context (QueryLastN) without an external TSDB dependency.
 type CircularBufferSink struct {
-	capacity int
-	buffer   []telemetry.TelemetryData
-	mu       sync.RWMutex
-	head     int // Pointer to the next available slot
-	count    int // Current number of elements stored
+	buffer *ringbuf.Buffer[telemetry.TelemetryData]
+
+	// Memory budget sizing; only used when budget > 0.
+	budget  int64
+	sizeMu  sync.Mutex
+	avgSize float64 // Moving average of measured entry sizes in bytes
+	records int     // Records since the last capacity adjustment
 }
 
 // NewCircularBufferSink creates a new in-memory sink with a defined maximum capacity.
 func NewCircularBufferSink(capacity int) *CircularBufferSink {
 	return &CircularBufferSink{
-		capacity: capacity,
-		buffer:   make([]telemetry.TelemetryData, capacity),
+		buffer: ringbuf.New[telemetry.TelemetryData](capacity, ringbuf.Overwrite),
+	}
+}
+
+// NewMemoryBoundedBufferSink creates an in-memory sink whose capacity is derived from a memory
+// budget in bytes (see bytesize.Parse). Entry sizes are measured as records arrive and the capacity
+// is adjusted so the retained history stays within budget as TelemetryData grows.
+func NewMemoryBoundedBufferSink(budget int64) *CircularBufferSink {
+	base := float64(baseEntrySize)
+	return &CircularBufferSink{
+		buffer:  ringbuf.New[telemetry.TelemetryData](capacityFor(budget, base), ringbuf.Overwrite),
+		budget:  budget,
+		avgSize: base,
 	}
 }
 
 // Record accepts a snapshot of telemetry data and persists it in the buffer.
 // If the buffer is full, it overwrites the oldest record.
 func (s *CircularBufferSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	
-	s.buffer[s.head] = data
-	s.head = (s.head + 1) % s.capacity
-	if s.count < s.capacity {
-		s.count++
+	if s.budget > 0 {
+		s.observe(data)
 	}
-	return nil
+	return s.buffer.Push(data)
+}
+
+// Capacity reports the current maximum number of records retained.
+func (s *CircularBufferSink) Capacity() int {
+	return s.buffer.Cap()
 }
 
 // QueryLastN fetches the last N records, ordered from oldest to newest.
 func (s *CircularBufferSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	
 	if n <= 0 {
 		return nil, nil
 	}
-	
-	effectiveN := s.count
-	if n < effectiveN {
-		effectiveN = n
-	}
-	
-	result := make([]telemetry.TelemetryData, effectiveN)
-	
-	// Calculate the start index (start reading right after the oldest element)
-	start := (s.head - effectiveN + s.capacity) % s.capacity
-	
-	// Read data sequentially, handling wrap-around
-	for i := 0; i < effectiveN; i++ {
-		index := (start + i) % s.capacity
-		result[i] = s.buffer[index]
-	}
-	
-	return result, nil
+	return s.buffer.Last(n), nil
+}
+
+// QueryRange pages through the buffered records in timestamp order.
+func (s *CircularBufferSink) QueryRange(ctx context.Context, q telemetry.RangeQuery) (telemetry.Page, error) {
+	return telemetry.PageRecords(s.buffer.Last(s.buffer.Len()), q)
 }
 
 // Close is defined to satisfy the potential use case for external sinks but does nothing for in-memory.
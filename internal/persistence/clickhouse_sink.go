@@ -0,0 +1,358 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"services/telemetry"
+)
+
+const (
+	defaultClickHouseTable         = "sts_telemetry"
+	defaultClickHouseBatchSize     = 1000
+	defaultClickHouseFlushInterval = 5 * time.Second
+	defaultClickHouseMaxBuffered   = 100000
+	defaultClickHouseMaxRetries    = 5
+	clickHouseInitialBackoff       = 500 * time.Millisecond
+)
+
+// ErrSinkBufferFull is returned when a buffered sink cannot accept more records.
+var ErrSinkBufferFull = errors.New("sink buffer full")
+
+// ClickHouseSinkConfig configures buffering, retries, and retention for the ClickHouse sink.
+type ClickHouseSinkConfig struct {
+	Table         string
+	BatchSize     int           // Records per bulk insert
+	FlushInterval time.Duration // Maximum time a record waits in the buffer
+	MaxBuffered   int           // Records held (including retries) before Record rejects new data
+	MaxRetries    int           // Attempts per flush cycle before the batch is deferred to the next cycle
+	TTL           time.Duration // Optional table retention; zero keeps data indefinitely
+}
+
+// ClickHouseSink implements telemetry.TelemetrySink with buffered bulk inserts for
+// long-retention, high-cardinality fleet analytics.
+type ClickHouseSink struct {
+	conn driver.Conn
+	cfg  ClickHouseSinkConfig
+
+	buffer []telemetry.TelemetryData
+	mu     sync.Mutex
+	flush  chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+
+	lastErr error // Most recent asynchronous insert failure, guarded by mu
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewClickHouseSink creates the table if it does not exist and starts the background flusher.
+func NewClickHouseSink(ctx context.Context, conn driver.Conn, cfg ClickHouseSinkConfig) (*ClickHouseSink, error) {
+	if cfg.Table == "" {
+		cfg.Table = defaultClickHouseTable
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultClickHouseBatchSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultClickHouseFlushInterval
+	}
+	if cfg.MaxBuffered == 0 {
+		cfg.MaxBuffered = defaultClickHouseMaxBuffered
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultClickHouseMaxRetries
+	}
+
+	s := &ClickHouseSink{
+		conn:  conn,
+		cfg:   cfg,
+		flush: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if err := s.ensureSchema(ctx); err != nil {
+		return nil, err
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *ClickHouseSink) ensureSchema(ctx context.Context) error {
+	ttl := ""
+	if s.cfg.TTL > 0 {
+		ttl = fmt.Sprintf(" TTL toDateTime(timestamp) + INTERVAL %d SECOND", int64(s.cfg.TTL.Seconds()))
+	}
+	ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		timestamp           DateTime64(9, 'UTC'),
+		pipeline_latency_s9 Float64,
+		resource_load_pct   Float64,
+		hash_chain_status   LowCardinality(String),
+		gatm_breach_count   Int64,
+		is_gatm_violating   Bool,
+		violation_causes    Array(LowCardinality(String)),
+		is_silenced         Bool,
+		is_acknowledged     Bool,
+		severity            LowCardinality(String),
+		labels              Map(String, String),
+		derived             Map(String, Float64)
+	) ENGINE = MergeTree ORDER BY timestamp%s`, s.cfg.Table, ttl)
+	if err := s.conn.Exec(ctx, ddl); err != nil {
+		return fmt.Errorf("failed to create ClickHouse table %s: %w", s.cfg.Table, err)
+	}
+	// Tables created before derived metrics were recorded lack the column.
+	alter := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS derived Map(String, Float64)`, s.cfg.Table)
+	if err := s.conn.Exec(ctx, alter); err != nil {
+		return fmt.Errorf("failed to migrate ClickHouse table %s: %w", s.cfg.Table, err)
+	}
+	return nil
+}
+
+// Record buffers the snapshot; it is inserted asynchronously in the next batch.
+func (s *ClickHouseSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	s.mu.Lock()
+	if len(s.buffer) >= s.cfg.MaxBuffered {
+		s.mu.Unlock()
+		return fmt.Errorf("clickhouse sink: %w (%d records)", ErrSinkBufferFull, s.cfg.MaxBuffered)
+	}
+	s.buffer = append(s.buffer, data)
+	full := len(s.buffer) >= s.cfg.BatchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// run flushes on interval or when a batch fills, until Close.
+func (s *ClickHouseSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.flush:
+		}
+		s.flushAll(context.Background())
+	}
+}
+
+// flushAll drains the buffer batch by batch, retrying each with exponential backoff.
+// A batch that exhausts its retries stays at the front of the buffer for the next cycle.
+func (s *ClickHouseSink) flushAll(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		n := len(s.buffer)
+		if n > s.cfg.BatchSize {
+			n = s.cfg.BatchSize
+		}
+		batch := append([]telemetry.TelemetryData(nil), s.buffer[:n]...)
+		s.mu.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err := s.insertWithRetry(ctx, batch); err != nil {
+			s.mu.Lock()
+			s.lastErr = err
+			s.mu.Unlock()
+			return err
+		}
+
+		s.mu.Lock()
+		s.buffer = s.buffer[len(batch):]
+		s.lastErr = nil
+		s.mu.Unlock()
+	}
+}
+
+func (s *ClickHouseSink) insertWithRetry(ctx context.Context, batch []telemetry.TelemetryData) error {
+	backoff := clickHouseInitialBackoff
+	var err error
+	for attempt := 1; attempt <= s.cfg.MaxRetries; attempt++ {
+		if err = s.insert(ctx, batch); err == nil {
+			return nil
+		}
+		if attempt == s.cfg.MaxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.stop:
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("clickhouse bulk insert failed after %d attempts: %w", s.cfg.MaxRetries, err)
+}
+
+func (s *ClickHouseSink) insert(ctx context.Context, batch []telemetry.TelemetryData) error {
+	b, err := s.conn.PrepareBatch(ctx, "INSERT INTO "+s.cfg.Table)
+	if err != nil {
+		return err
+	}
+	for _, td := range batch {
+		causes := td.ViolationCauses
+		if causes == nil {
+			causes = []string{}
+		}
+		labels := td.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		derived := td.Derived
+		if derived == nil {
+			derived = map[string]float64{}
+		}
+		if err := b.Append(
+			td.Timestamp,
+			td.PipelineLatencyS9.Seconds(),
+			td.ResourceLoad_Pct,
+			td.IntegrityHashChainStatus,
+			int64(td.GATMBreachCount),
+			td.IsGATMViolating,
+			causes,
+			td.IsSilenced,
+			td.IsAcknowledged,
+			string(td.Severity),
+			labels,
+			derived,
+		); err != nil {
+			b.Abort()
+			return err
+		}
+	}
+	return b.Send()
+}
+
+// QueryLastN fetches the last N persisted records, ordered from oldest to newest.
+// Records still buffered are not included.
+func (s *ClickHouseSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	rows, err := s.conn.Query(ctx, fmt.Sprintf(`SELECT %s FROM %s ORDER BY timestamp DESC, %s DESC LIMIT %d`,
+		clickHouseColumns, s.cfg.Table, clickHouseTiebreak, n))
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse query failed: %w", err)
+	}
+	defer rows.Close()
+	result, err := scanClickHouseRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reverse into oldest-to-newest order.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+// clickHouseColumns are the selected columns, in the order scanClickHouseRows reads them.
+const clickHouseColumns = `timestamp, pipeline_latency_s9, resource_load_pct, hash_chain_status,
+		gatm_breach_count, is_gatm_violating, violation_causes, is_silenced, is_acknowledged, severity, labels, derived`
+
+// clickHouseTiebreak orders records sharing a timestamp, e.g. from different nodes, so OFFSET
+// paging neither repeats nor skips them. The table has no row ID; only identical records tie.
+const clickHouseTiebreak = `cityHash64(` + clickHouseColumns + `)`
+
+func scanClickHouseRows(rows driver.Rows) ([]telemetry.TelemetryData, error) {
+	var result []telemetry.TelemetryData
+	for rows.Next() {
+		var (
+			td       telemetry.TelemetryData
+			latency  float64
+			breaches int64
+			severity string
+		)
+		if err := rows.Scan(&td.Timestamp, &latency, &td.ResourceLoad_Pct,
+			&td.IntegrityHashChainStatus, &breaches, &td.IsGATMViolating, &td.ViolationCauses,
+			&td.IsSilenced, &td.IsAcknowledged, &severity, &td.Labels, &td.Derived); err != nil {
+			return nil, fmt.Errorf("clickhouse scan failed: %w", err)
+		}
+		td.PipelineLatencyS9 = telemetry.DurationFromSeconds(latency)
+		td.GATMBreachCount = int(breaches)
+		td.Severity = telemetry.Severity(severity)
+		result = append(result, td)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("clickhouse query failed: %w", err)
+	}
+	return result, nil
+}
+
+// QueryRange pages through the persisted records in timestamp order. Records still buffered are
+// not included.
+func (s *ClickHouseSink) QueryRange(ctx context.Context, q telemetry.RangeQuery) (telemetry.Page, error) {
+	lower, skip, err := q.Bounds()
+	if err != nil {
+		return telemetry.Page{}, err
+	}
+	limit := q.PageLimit()
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE timestamp >= ?`, clickHouseColumns, s.cfg.Table)
+	args := []interface{}{lower}
+	if !q.To.IsZero() {
+		query += ` AND timestamp < ?`
+		args = append(args, q.To)
+	}
+	// One extra record tells whether another page follows.
+	query += fmt.Sprintf(` ORDER BY timestamp, %s LIMIT %d OFFSET %d`, clickHouseTiebreak, limit+1, skip)
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return telemetry.Page{}, fmt.Errorf("clickhouse query failed: %w", err)
+	}
+	defer rows.Close()
+	records, err := scanClickHouseRows(rows)
+	if err != nil {
+		return telemetry.Page{}, err
+	}
+	if len(records) <= limit {
+		return telemetry.Page{Records: records}, nil
+	}
+	records = records[:limit]
+	return telemetry.Page{Records: records, NextCursor: telemetry.NextCursor(lower, skip, records).String()}, nil
+}
+
+// LastError reports the most recent asynchronous insert failure, or nil once a flush succeeds.
+func (s *ClickHouseSink) LastError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// Ping checks that the ClickHouse server is reachable.
+func (s *ClickHouseSink) Ping(ctx context.Context) error {
+	return s.conn.Ping(ctx)
+}
+
+// Close stops the background flusher and performs a final flush bounded by ctx. Later calls
+// return the result of the first.
+func (s *ClickHouseSink) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		if s.closeErr = s.flushAll(ctx); s.closeErr == nil {
+			s.closeErr = s.conn.Close()
+		}
+	})
+	return s.closeErr
+}
+
+// Ensure ClickHouseSink implements the TelemetrySink interface.
+var _ telemetry.TelemetrySink = (*ClickHouseSink)(nil)
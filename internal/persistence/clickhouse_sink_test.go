@@ -0,0 +1,139 @@
+package persistence
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"services/telemetry"
+)
+
+// fakeClickHouse records inserted batches and serves them back to queries, in insertion order.
+type fakeClickHouse struct {
+	driver.Conn
+	mu       sync.Mutex
+	inserted []telemetry.TelemetryData
+	queries  []string
+	closed   int
+}
+
+func (c *fakeClickHouse) Exec(ctx context.Context, query string, args ...any) error {
+	return nil
+}
+
+func (c *fakeClickHouse) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	return &fakeBatch{conn: c}, nil
+}
+
+func (c *fakeClickHouse) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries = append(c.queries, query)
+	return &fakeRows{records: append([]telemetry.TelemetryData(nil), c.inserted...)}, nil
+}
+
+func (c *fakeClickHouse) Close() error {
+	c.mu.Lock()
+	c.closed++
+	c.mu.Unlock()
+	return nil
+}
+
+type fakeBatch struct {
+	driver.Batch
+	conn *fakeClickHouse
+	rows []telemetry.TelemetryData
+}
+
+func (b *fakeBatch) Append(v ...any) error {
+	b.rows = append(b.rows, telemetry.TelemetryData{Timestamp: v[0].(time.Time), GATMBreachCount: int(v[4].(int64))})
+	return nil
+}
+
+func (b *fakeBatch) Abort() error { return nil }
+
+func (b *fakeBatch) Send() error {
+	b.conn.mu.Lock()
+	b.conn.inserted = append(b.conn.inserted, b.rows...)
+	b.conn.mu.Unlock()
+	return nil
+}
+
+type fakeRows struct {
+	driver.Rows
+	records []telemetry.TelemetryData
+	next    int
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.records)
+}
+
+// Scan fills the columns of clickHouseColumns the fake keeps: the timestamp and breach count.
+func (r *fakeRows) Scan(dest ...any) error {
+	td := r.records[r.next-1]
+	*dest[0].(*time.Time) = td.Timestamp
+	*dest[4].(*int64) = int64(td.GATMBreachCount)
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return nil }
+func (r *fakeRows) Close() error { return nil }
+
+func newTestClickHouseSink(t *testing.T, conn *fakeClickHouse) *ClickHouseSink {
+	t.Helper()
+	s, err := NewClickHouseSink(context.Background(), conn, ClickHouseSinkConfig{FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewClickHouseSink() error = %v", err)
+	}
+	return s
+}
+
+func TestClickHouseSink_CloseIsIdempotent(t *testing.T) {
+	conn := &fakeClickHouse{}
+	s := newTestClickHouseSink(t, conn)
+	for seq := 1; seq <= 3; seq++ {
+		if err := s.Record(context.Background(), record(seq)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.Close(context.Background()); err != nil {
+			t.Errorf("Close() #%d error = %v", i+1, err)
+		}
+	}
+	if len(conn.inserted) != 3 || conn.closed != 1 {
+		t.Errorf("Close() flushed %d records and closed the connection %d times, want 3 and once", len(conn.inserted), conn.closed)
+	}
+}
+
+func TestClickHouseSink_QueryRangeBreaksTimestampTies(t *testing.T) {
+	conn := &fakeClickHouse{}
+	s := newTestClickHouseSink(t, conn)
+	defer s.Close(context.Background())
+	at := time.Unix(100, 0).UTC()
+	conn.inserted = []telemetry.TelemetryData{{Timestamp: at, GATMBreachCount: 1}, {Timestamp: at, GATMBreachCount: 2}}
+
+	page, err := s.QueryRange(context.Background(), telemetry.RangeQuery{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Records) != 1 || page.NextCursor == "" {
+		t.Errorf("page = %+v, want one record and a cursor", page)
+	}
+	if _, err := s.QueryLastN(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	// Records sharing a timestamp must come back in the same order on every page.
+	for _, query := range conn.queries {
+		if !strings.Contains(query, "ORDER BY timestamp, "+clickHouseTiebreak) &&
+			!strings.Contains(query, "ORDER BY timestamp DESC, "+clickHouseTiebreak+" DESC") {
+			t.Errorf("query orders by timestamp alone: %s", query)
+		}
+	}
+}
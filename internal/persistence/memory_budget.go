@@ -0,0 +1,63 @@
+package persistence
+
+import (
+	"unsafe"
+
+	"services/telemetry"
+)
+
+const (
+	// baseEntrySize is the fixed in-buffer footprint of a record, before any heap-allocated fields.
+	baseEntrySize = int(unsafe.Sizeof(telemetry.TelemetryData{}))
+	// mapEntryOverhead approximates the per-entry bucket overhead of a Go map.
+	mapEntryOverhead = 16
+	// resizeInterval is how many records are measured between capacity adjustments.
+	resizeInterval = 64
+	// resizeTolerance avoids reallocating the buffer for small drifts in the average entry size.
+	resizeTolerance = 0.1
+	// sizeSmoothing weights each new measurement in the moving average.
+	sizeSmoothing = 0.05
+)
+
+// entrySize estimates the retained memory of a record, including its strings, causes, labels and
+// derived metrics.
+// Strings shared with other records are counted in full, so the estimate errs on the high side.
+func entrySize(td telemetry.TelemetryData) int {
+	const stringHeader = int(unsafe.Sizeof(""))
+	size := baseEntrySize + len(td.IntegrityHashChainStatus) + len(td.Severity)
+	for _, c := range td.ViolationCauses {
+		size += stringHeader + len(c)
+	}
+	for k, v := range td.Labels {
+		size += 2*stringHeader + len(k) + len(v) + mapEntryOverhead
+	}
+	for k := range td.Derived {
+		size += stringHeader + len(k) + 8 + mapEntryOverhead
+	}
+	return size
+}
+
+func capacityFor(budget int64, avgSize float64) int {
+	return max(1, int(float64(budget)/avgSize))
+}
+
+// observe folds the record's size into the moving average and resizes the buffer to keep
+// capacity * average size within the budget. Shrinking happens as soon as the estimate exceeds the
+// budget; growing waits for resizeInterval records so transient dips do not churn allocations.
+func (s *CircularBufferSink) observe(td telemetry.TelemetryData) {
+	size := float64(entrySize(td))
+
+	s.sizeMu.Lock()
+	defer s.sizeMu.Unlock()
+
+	s.avgSize += sizeSmoothing * (size - s.avgSize)
+	s.records++
+
+	target := capacityFor(s.budget, s.avgSize)
+	current := s.buffer.Cap()
+	drift := float64(target-current) / float64(current)
+	if drift < -resizeTolerance || (drift > resizeTolerance && s.records >= resizeInterval) {
+		s.buffer.Resize(target)
+		s.records = 0
+	}
+}
@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"services/telemetry"
+)
+
+// MigratingSink moves telemetry history from one sink generation to another without a gap in
+// query results. Until Backfill completes, records are written to both sinks and queries are served
+// by the old one; afterwards the new sink handles everything on its own.
+type MigratingSink struct {
+	from, to telemetry.TelemetrySink
+
+	mu        sync.RWMutex
+	cutover   time.Time // Timestamp of the first record dual-written; older history needs backfill
+	dual      int       // Records dual-written so far
+	completed bool
+}
+
+// NewMigratingSink starts a migration from one sink to another.
+func NewMigratingSink(from, to telemetry.TelemetrySink) *MigratingSink {
+	return &MigratingSink{from: from, to: to}
+}
+
+// Record writes to the new sink and, until cutover, to the old one as well so its query view stays current.
+func (s *MigratingSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	s.mu.Lock()
+	if s.cutover.IsZero() {
+		s.cutover = data.Timestamp
+	}
+	completed := s.completed
+	if !completed {
+		s.dual++
+	}
+	s.mu.Unlock()
+
+	if err := s.to.Record(ctx, data); err != nil {
+		return fmt.Errorf("migrating sink: new backend write failed: %w", err)
+	}
+	if completed {
+		return nil
+	}
+	if err := s.from.Record(ctx, data); err != nil {
+		return fmt.Errorf("migrating sink: old backend write failed: %w", err)
+	}
+	return nil
+}
+
+// Backfill copies up to the last n records from the old sink that predate the first dual-written
+// record into the new sink, then switches queries over. Backfilled records arrive after newer ones,
+// so the new sink must order query results by timestamp rather than insertion. Backfill can be
+// retried after a failure; records copied by a failed attempt may be written again.
+func (s *MigratingSink) Backfill(ctx context.Context, n int) error {
+	s.mu.RLock()
+	cutover, dual, completed := s.cutover, s.dual, s.completed
+	s.mu.RUnlock()
+	if completed {
+		return nil
+	}
+
+	// The old sink's newest records are the dual-written ones; read past them to find n older ones.
+	history, err := s.from.QueryLastN(ctx, n+dual)
+	if err != nil {
+		return fmt.Errorf("migrating sink: backfill read failed: %w", err)
+	}
+	for i, td := range history {
+		if !cutover.IsZero() && !td.Timestamp.Before(cutover) {
+			// Already dual-written.
+			history = history[:i]
+			break
+		}
+	}
+	if len(history) > n {
+		history = history[len(history)-n:]
+	}
+	copied := 0
+	for _, td := range history {
+		if err := s.to.Record(ctx, td); err != nil {
+			return fmt.Errorf("migrating sink: backfill write failed after %d records: %w", copied, err)
+		}
+		copied++
+	}
+
+	s.mu.Lock()
+	s.completed = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Completed reports whether backfill has finished and the new sink serves queries.
+func (s *MigratingSink) Completed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.completed
+}
+
+// QueryLastN reads from the old sink until backfill completes, then from the new sink.
+func (s *MigratingSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	if s.Completed() {
+		return s.to.QueryLastN(ctx, n)
+	}
+	return s.from.QueryLastN(ctx, n)
+}
+
+// Close closes both sinks.
+func (s *MigratingSink) Close(ctx context.Context) error {
+	newErr := s.to.Close(ctx)
+	if err := s.from.Close(ctx); err != nil {
+		return err
+	}
+	return newErr
+}
+
+// Ensure MigratingSink implements the TelemetrySink interface.
+var _ telemetry.TelemetrySink = (*MigratingSink)(nil)
@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// newTestMigratingSink migrates from a buffer already holding records 1 to history.
+func newTestMigratingSink(t *testing.T, history int, remote *flakyRemote) *MigratingSink {
+	t.Helper()
+	old := NewCircularBufferSink(100)
+	for i := 1; i <= history; i++ {
+		if err := old.Record(context.Background(), record(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewMigratingSink(old, remote)
+}
+
+func TestMigratingSink_BackfillStraddlesCutover(t *testing.T) {
+	ctx := context.Background()
+	remote := &flakyRemote{budget: -1}
+	s := newTestMigratingSink(t, 5, remote)
+	for i := 6; i <= 7; i++ {
+		if err := s.Record(ctx, record(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The two dual-written records do not count against n, and are not copied again.
+	if err := s.Backfill(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got := remote.breaches(); !equalSeq(got, 6, 7, 3, 4, 5) {
+		t.Errorf("new sink records = %v, want [6 7 3 4 5]", got)
+	}
+	if !s.Completed() {
+		t.Error("Completed() = false after Backfill")
+	}
+
+	// After cutover, records go to the new sink only.
+	if err := s.Record(ctx, record(8)); err != nil {
+		t.Fatal(err)
+	}
+	old, _ := s.from.QueryLastN(ctx, 1)
+	if len(old) != 1 || old[0].GATMBreachCount != 7 {
+		t.Errorf("old sink newest record = %v, want 7", old)
+	}
+}
+
+func TestMigratingSink_RetriesFailedBackfill(t *testing.T) {
+	ctx := context.Background()
+	remote := &flakyRemote{budget: -1}
+	s := newTestMigratingSink(t, 4, remote)
+	if err := s.Record(ctx, record(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	remote.setBudget(2)
+	if err := s.Backfill(ctx, 10); !errors.Is(err, errRemoteDown) {
+		t.Fatalf("Backfill() error = %v, want errRemoteDown", err)
+	}
+	if s.Completed() {
+		t.Fatal("Completed() = true after a failed backfill")
+	}
+	// Queries are still served by the old sink, which has the whole history.
+	got, err := s.QueryLastN(ctx, 10)
+	if err != nil || len(got) != 5 {
+		t.Fatalf("QueryLastN() = %d records, %v; want 5 from the old sink", len(got), err)
+	}
+
+	remote.setBudget(-1)
+	if err := s.Backfill(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+	// Records copied by the failed attempt are written again.
+	if got := remote.breaches(); !equalSeq(got, 5, 1, 2, 1, 2, 3, 4) {
+		t.Errorf("new sink records = %v, want [5 1 2 1 2 3 4]", got)
+	}
+	if !s.Completed() {
+		t.Error("Completed() = false after a successful retry")
+	}
+}
@@ -0,0 +1,249 @@
+package persistence
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sync"
+
+	"services/telemetry"
+)
+
+const (
+	mmapMagic           = "STSRING1"
+	mmapHeaderSize      = 64
+	mmapSlotHeaderSize  = 8 // Payload length and CRC-32
+	defaultMmapSlotSize = 1024
+)
+
+// Header layout: magic, slot size, capacity, head (next slot to write) and count.
+const (
+	mmapOffSlotSize = 8
+	mmapOffCapacity = 12
+	mmapOffHead     = 16
+	mmapOffCount    = 20
+)
+
+var (
+	// ErrRecordTooLarge is returned by MmapBufferSink.Record for a record that does not fit in a slot.
+	ErrRecordTooLarge = errors.New("record exceeds slot size")
+	// ErrBufferLayout is returned when an existing buffer file has another capacity or slot size.
+	ErrBufferLayout = errors.New("buffer file layout mismatch")
+)
+
+// MmapBufferSinkConfig configures a memory-mapped ring buffer.
+type MmapBufferSinkConfig struct {
+	Path     string // Buffer file; created if missing
+	Capacity int    // Records retained
+	SlotSize int    // Maximum encoded record size in bytes, including an 8 byte slot header (default 1024)
+}
+
+// MmapBufferSink is a variant of CircularBufferSink kept in a fixed-size memory-mapped file, so
+// the short-term history survives process restarts at near in-memory speed. Records are stored as
+// JSON in fixed-size slots after a header holding the head and count. Each slot carries a
+// checksum, so a record torn by a crash mid-write is skipped rather than returned.
+//
+// Writes reach the page cache only; they survive a crash of the process but not of the host
+// unless Sync is called.
+type MmapBufferSink struct {
+	mu       sync.RWMutex
+	file     *os.File
+	data     []byte // Mapped file; nil once closed
+	slotSize int
+	capacity int
+	head     int
+	count    int
+}
+
+// NewMmapBufferSink opens the buffer file at cfg.Path, creating it if missing. An existing file
+// keeps its records; it must have been created with the same Capacity and SlotSize.
+func NewMmapBufferSink(cfg MmapBufferSinkConfig) (*MmapBufferSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("mmap buffer sink: Path is required")
+	}
+	if cfg.Capacity <= 0 {
+		return nil, errors.New("mmap buffer sink: Capacity must be positive")
+	}
+	if cfg.SlotSize == 0 {
+		cfg.SlotSize = defaultMmapSlotSize
+	}
+	if cfg.SlotSize <= mmapSlotHeaderSize {
+		return nil, fmt.Errorf("mmap buffer sink: SlotSize must exceed %d bytes", mmapSlotHeaderSize)
+	}
+	size := int64(mmapHeaderSize) + int64(cfg.Capacity)*int64(cfg.SlotSize)
+
+	file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("mmap buffer sink: failed to open %s: %w", cfg.Path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("mmap buffer sink: %w", err)
+	}
+	fresh := info.Size() == 0
+	if fresh {
+		if err := file.Truncate(size); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("mmap buffer sink: failed to size %s: %w", cfg.Path, err)
+		}
+	} else if info.Size() != size {
+		file.Close()
+		return nil, fmt.Errorf("mmap buffer sink: %w: %s is %d bytes, want %d", ErrBufferLayout, cfg.Path, info.Size(), size)
+	}
+	data, err := mapFile(file, int(size))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("mmap buffer sink: failed to map %s: %w", cfg.Path, err)
+	}
+
+	s := &MmapBufferSink{file: file, data: data, slotSize: cfg.SlotSize, capacity: cfg.Capacity}
+	if fresh {
+		copy(data, mmapMagic)
+		binary.LittleEndian.PutUint32(data[mmapOffSlotSize:], uint32(cfg.SlotSize))
+		binary.LittleEndian.PutUint32(data[mmapOffCapacity:], uint32(cfg.Capacity))
+		return s, nil
+	}
+	if err := s.readHeader(); err != nil {
+		s.close()
+		return nil, fmt.Errorf("mmap buffer sink: %s: %w", cfg.Path, err)
+	}
+	return s, nil
+}
+
+func (s *MmapBufferSink) readHeader() error {
+	if string(s.data[:len(mmapMagic)]) != mmapMagic {
+		return errors.New("not a buffer file")
+	}
+	slotSize := int(binary.LittleEndian.Uint32(s.data[mmapOffSlotSize:]))
+	capacity := int(binary.LittleEndian.Uint32(s.data[mmapOffCapacity:]))
+	if slotSize != s.slotSize || capacity != s.capacity {
+		return fmt.Errorf("%w: file has capacity %d and slot size %d", ErrBufferLayout, capacity, slotSize)
+	}
+	s.head = int(binary.LittleEndian.Uint32(s.data[mmapOffHead:]))
+	s.count = int(binary.LittleEndian.Uint32(s.data[mmapOffCount:]))
+	if s.head >= s.capacity || s.count > s.capacity {
+		return errors.New("corrupt header")
+	}
+	return nil
+}
+
+func (s *MmapBufferSink) slot(i int) []byte {
+	off := mmapHeaderSize + i*s.slotSize
+	return s.data[off : off+s.slotSize]
+}
+
+// Record writes the snapshot over the oldest record once the buffer is full.
+func (s *MmapBufferSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if len(payload) > s.slotSize-mmapSlotHeaderSize {
+		return fmt.Errorf("mmap buffer sink: %w: %d bytes, slot holds %d", ErrRecordTooLarge, len(payload), s.slotSize-mmapSlotHeaderSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return errors.New("mmap buffer sink: closed")
+	}
+	// The slot is complete before the header advances to it; a crash in between leaves a slot
+	// whose checksum no longer matches, which reads skip.
+	slot := s.slot(s.head)
+	binary.LittleEndian.PutUint32(slot[0:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(slot[4:], crc32.ChecksumIEEE(payload))
+	copy(slot[mmapSlotHeaderSize:], payload)
+
+	s.head = (s.head + 1) % s.capacity
+	if s.count < s.capacity {
+		s.count++
+	}
+	binary.LittleEndian.PutUint32(s.data[mmapOffHead:], uint32(s.head))
+	binary.LittleEndian.PutUint32(s.data[mmapOffCount:], uint32(s.count))
+	return nil
+}
+
+// QueryLastN fetches the last N records, ordered from oldest to newest. Torn records are skipped.
+func (s *MmapBufferSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return nil, errors.New("mmap buffer sink: closed")
+	}
+	if n > s.count {
+		n = s.count
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	records := make([]telemetry.TelemetryData, 0, n)
+	for i := n; i > 0; i-- {
+		slot := s.slot((s.head - i + s.capacity) % s.capacity)
+		length := int(binary.LittleEndian.Uint32(slot[0:]))
+		if length > s.slotSize-mmapSlotHeaderSize {
+			continue
+		}
+		payload := slot[mmapSlotHeaderSize : mmapSlotHeaderSize+length]
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(slot[4:]) {
+			continue
+		}
+		var td telemetry.TelemetryData
+		if err := json.Unmarshal(payload, &td); err != nil {
+			continue
+		}
+		records = append(records, td)
+	}
+	return records, nil
+}
+
+// QueryRange pages through the buffered records in timestamp order.
+func (s *MmapBufferSink) QueryRange(ctx context.Context, q telemetry.RangeQuery) (telemetry.Page, error) {
+	records, err := s.QueryLastN(ctx, s.capacity)
+	if err != nil {
+		return telemetry.Page{}, err
+	}
+	return telemetry.PageRecords(records, q)
+}
+
+// Capacity reports the maximum number of records retained.
+func (s *MmapBufferSink) Capacity() int {
+	return s.capacity
+}
+
+// Sync flushes the mapped file to disk, so its records also survive a host crash.
+func (s *MmapBufferSink) Sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data == nil {
+		return errors.New("mmap buffer sink: closed")
+	}
+	return s.file.Sync()
+}
+
+// Close flushes and unmaps the buffer file.
+func (s *MmapBufferSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		return nil
+	}
+	syncErr := s.file.Sync()
+	if err := s.close(); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+func (s *MmapBufferSink) close() error {
+	err := unmapFile(s.data)
+	s.data = nil
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
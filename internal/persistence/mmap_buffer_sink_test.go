@@ -0,0 +1,142 @@
+//go:build unix
+
+package persistence
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"services/telemetry"
+)
+
+func newTestMmapSink(t *testing.T, path string, capacity int) *MmapBufferSink {
+	t.Helper()
+	s, err := NewMmapBufferSink(MmapBufferSinkConfig{Path: path, Capacity: capacity, SlotSize: 256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func breachSeq(records []telemetry.TelemetryData) []int {
+	var seq []int
+	for _, td := range records {
+		seq = append(seq, td.GATMBreachCount)
+	}
+	return seq
+}
+
+func TestMmapBufferSink_ReopenKeepsRecords(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ring")
+	s := newTestMmapSink(t, path, 4)
+	for i := 1; i <= 3; i++ {
+		if err := s.Record(ctx, record(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s = newTestMmapSink(t, path, 4)
+	defer s.Close(ctx)
+	got, err := s.QueryLastN(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 1, 2, 3) {
+		t.Fatalf("records after reopen = %v, want [1 2 3]", seq)
+	}
+	if err := s.Record(ctx, record(4)); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.QueryLastN(ctx, 2)
+	if seq := breachSeq(got); !equalSeq(seq, 3, 4) {
+		t.Fatalf("QueryLastN(2) = %v, want [3 4]", seq)
+	}
+}
+
+func TestMmapBufferSink_RejectsLayoutMismatch(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ring")
+	s := newTestMmapSink(t, path, 4)
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cfg := range []MmapBufferSinkConfig{
+		{Path: path, Capacity: 8, SlotSize: 256},
+		{Path: path, Capacity: 8, SlotSize: 128}, // Same file size, other layout
+	} {
+		if _, err := NewMmapBufferSink(cfg); !errors.Is(err, ErrBufferLayout) {
+			t.Errorf("NewMmapBufferSink(capacity %d, slot size %d) error = %v, want ErrBufferLayout", cfg.Capacity, cfg.SlotSize, err)
+		}
+	}
+}
+
+func TestMmapBufferSink_WrapsAround(t *testing.T) {
+	ctx := context.Background()
+	s := newTestMmapSink(t, filepath.Join(t.TempDir(), "ring"), 3)
+	defer s.Close(ctx)
+	for i := 1; i <= 7; i++ {
+		if err := s.Record(ctx, record(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := s.QueryLastN(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 5, 6, 7) {
+		t.Fatalf("records = %v, want [5 6 7]", seq)
+	}
+
+	page, err := s.QueryRange(ctx, telemetry.RangeQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(page.Records); !equalSeq(seq, 5, 6, 7) {
+		t.Fatalf("QueryRange records = %v, want [5 6 7]", seq)
+	}
+}
+
+func TestMmapBufferSink_SkipsTornSlots(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ring")
+	s := newTestMmapSink(t, path, 4)
+	for i := 1; i <= 3; i++ {
+		if err := s.Record(ctx, record(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Corrupt the payload of the middle record, as a crash mid-write would.
+	s.slot(1)[mmapSlotHeaderSize] ^= 0xff
+	if err := s.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s = newTestMmapSink(t, path, 4)
+	defer s.Close(ctx)
+	got, err := s.QueryLastN(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 1, 3) {
+		t.Fatalf("records = %v, want [1 3]", seq)
+	}
+}
+
+func TestMmapBufferSink_RecordTooLarge(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewMmapBufferSink(MmapBufferSinkConfig{Path: filepath.Join(t.TempDir(), "ring"), Capacity: 2, SlotSize: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close(ctx)
+	if err := s.Record(ctx, record(1)); !errors.Is(err, ErrRecordTooLarge) {
+		t.Fatalf("Record error = %v, want ErrRecordTooLarge", err)
+	}
+}
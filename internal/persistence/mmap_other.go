@@ -0,0 +1,17 @@
+//go:build !unix
+
+package persistence
+
+import (
+	"errors"
+	"os"
+)
+
+// mapFile is not supported on this platform.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return nil, errors.New("memory-mapped files are not supported on this platform")
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package persistence
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps size bytes of f for shared reading and writing.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
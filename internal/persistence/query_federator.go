@@ -0,0 +1,146 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"services/telemetry"
+)
+
+// QueryFederator serves history queries from a fast in-memory sink and reads through to a
+// persistent sink only when the buffer holds fewer records than requested. Results from both are
+// merged by timestamp and deduplicated, so callers such as the REST query API see one history.
+//
+// Records are written to both sinks, buffer first. Use the federator in place of the two sinks
+// rather than alongside them, or every record is stored twice.
+type QueryFederator struct {
+	buffer     telemetry.TelemetrySink
+	persistent telemetry.TelemetrySink
+}
+
+// NewQueryFederator federates queries across buffer (e.g. a CircularBufferSink) and persistent.
+func NewQueryFederator(buffer, persistent telemetry.TelemetrySink) *QueryFederator {
+	return &QueryFederator{buffer: buffer, persistent: persistent}
+}
+
+// Record writes the snapshot to the buffer and then to the persistent sink.
+func (f *QueryFederator) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	if err := f.buffer.Record(ctx, data); err != nil {
+		return fmt.Errorf("federated sink: buffer write failed: %w", err)
+	}
+	if err := f.persistent.Record(ctx, data); err != nil {
+		return fmt.Errorf("federated sink: persistent write failed: %w", err)
+	}
+	return nil
+}
+
+// QueryLastN fetches the last N records, ordered from oldest to newest. Where both sinks hold a
+// record with the same timestamp, the buffered copy wins.
+func (f *QueryFederator) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	recent, err := f.buffer.QueryLastN(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("federated query: buffer read failed: %w", err)
+	}
+	if len(recent) >= n {
+		return recent, nil
+	}
+
+	// The persistent sink may lag behind the buffer (e.g. ClickHouse batches inserts), so its last
+	// N records can both overlap the buffer and miss its newest entries; merge rather than splice.
+	older, err := f.persistent.QueryLastN(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("federated query: persistent read failed: %w", err)
+	}
+	return mergeHistory(older, recent, n), nil
+}
+
+// QueryRange pages through the persistent sink's history. Once it is exhausted, the page is
+// completed with buffered records newer than the persistent ones, which it may not hold yet.
+func (f *QueryFederator) QueryRange(ctx context.Context, q telemetry.RangeQuery) (telemetry.Page, error) {
+	page, err := telemetry.QueryRange(ctx, f.persistent, q)
+	if err != nil {
+		return telemetry.Page{}, fmt.Errorf("federated query: persistent read failed: %w", err)
+	}
+	if page.NextCursor != "" {
+		return page, nil
+	}
+	lower, skip, err := q.Bounds()
+	if err != nil {
+		return telemetry.Page{}, err
+	}
+	if len(page.Records) > 0 {
+		// Buffered records up to the persistent sink's newest are already covered.
+		c := telemetry.NextCursor(lower, skip, page.Records)
+		lower, skip = c.After, c.Skip
+	}
+	remaining := q.PageLimit() - len(page.Records)
+	// A cursor cannot encode the zero time of a query without From, so lower is passed as From.
+	bq := telemetry.RangeQuery{From: lower, To: q.To, Limit: max(remaining, 1)}
+	if skip > 0 {
+		bq.Cursor = telemetry.Cursor{After: lower, Skip: skip}.String()
+	}
+	recent, err := telemetry.QueryRange(ctx, f.buffer, bq)
+	if err != nil {
+		return telemetry.Page{}, fmt.Errorf("federated query: buffer read failed: %w", err)
+	}
+	switch {
+	case len(recent.Records) == 0:
+	case remaining == 0:
+		// The page is full; the buffered record only tells that another page follows.
+		page.NextCursor = telemetry.Cursor{After: lower, Skip: skip}.String()
+	default:
+		page.Records = append(page.Records, recent.Records...)
+		if recent.NextCursor != "" {
+			page.NextCursor = telemetry.NextCursor(lower, skip, recent.Records).String()
+		}
+	}
+	return page, nil
+}
+
+// Ping checks both sinks with telemetry.PingSink.
+func (f *QueryFederator) Ping(ctx context.Context) error {
+	if err := telemetry.PingSink(ctx, f.buffer); err != nil {
+		return fmt.Errorf("federated sink: buffer: %w", err)
+	}
+	if err := telemetry.PingSink(ctx, f.persistent); err != nil {
+		return fmt.Errorf("federated sink: persistent: %w", err)
+	}
+	return nil
+}
+
+// mergeHistory combines two oldest-to-newest histories, keeping the preferred copy of records with
+// equal timestamps, and returns the newest n.
+func mergeHistory(other, preferred []telemetry.TelemetryData, n int) []telemetry.TelemetryData {
+	seen := make(map[int64]bool, len(preferred))
+	merged := make([]telemetry.TelemetryData, 0, len(other)+len(preferred))
+	for _, td := range preferred {
+		seen[td.Timestamp.UnixNano()] = true
+		merged = append(merged, td)
+	}
+	for _, td := range other {
+		if !seen[td.Timestamp.UnixNano()] {
+			merged = append(merged, td)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Timestamp.Before(merged[j].Timestamp) })
+	if len(merged) > n {
+		merged = merged[len(merged)-n:]
+	}
+	return merged
+}
+
+// Close closes both sinks.
+func (f *QueryFederator) Close(ctx context.Context) error {
+	bufferErr := f.buffer.Close(ctx)
+	if err := f.persistent.Close(ctx); err != nil {
+		return err
+	}
+	return bufferErr
+}
+
+// Ensure QueryFederator implements the TelemetrySink interface.
+var _ telemetry.TelemetrySink = (*QueryFederator)(nil)
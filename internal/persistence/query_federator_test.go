@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"services/telemetry"
+)
+
+// newLaggingFederator returns a federator whose persistent sink holds records 1 to persisted and
+// whose buffer, of capacity 4, holds the newest of records 1 to buffered. Buffered copies are
+// labeled so tests can tell which sink served a record.
+func newLaggingFederator(t *testing.T, persisted, buffered int) *QueryFederator {
+	t.Helper()
+	ctx := context.Background()
+	buffer, persistent := NewCircularBufferSink(4), NewCircularBufferSink(100)
+	for i := 1; i <= buffered; i++ {
+		td := record(i)
+		td.Labels = map[string]string{"sink": "buffer"}
+		if err := buffer.Record(ctx, td); err != nil {
+			t.Fatal(err)
+		}
+		if i <= persisted {
+			if err := persistent.Record(ctx, record(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return NewQueryFederator(buffer, persistent)
+}
+
+func TestQueryFederator_QueryLastN(t *testing.T) {
+	// The persistent sink lags: it holds 1..5 while the buffer holds 5..8.
+	f := newLaggingFederator(t, 5, 8)
+	ctx := context.Background()
+
+	got, err := f.QueryLastN(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 6, 7, 8) {
+		t.Errorf("QueryLastN(3) = %v, want [6 7 8] from the buffer", seq)
+	}
+
+	got, err = f.QueryLastN(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 1, 2, 3, 4, 5, 6, 7, 8) {
+		t.Fatalf("QueryLastN(10) = %v, want [1..8] without duplicates", seq)
+	}
+	if got[4].Labels["sink"] != "buffer" || got[3].Labels["sink"] != "" {
+		t.Errorf("record 5 labels %v, record 4 labels %v; want the buffered copy of the overlap", got[4].Labels, got[3].Labels)
+	}
+
+	got, err = f.QueryLastN(ctx, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := breachSeq(got); !equalSeq(seq, 3, 4, 5, 6, 7, 8) {
+		t.Errorf("QueryLastN(6) = %v, want [3..8]", seq)
+	}
+}
+
+func TestQueryFederator_QueryRange(t *testing.T) {
+	tests := []struct {
+		name                string
+		persisted, buffered int
+		limit               int
+		want                []int
+	}{
+		{name: "Handoff Within A Page", persisted: 5, buffered: 8, limit: 3, want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "Handoff On A Page Boundary", persisted: 6, buffered: 8, limit: 3, want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "Single Page", persisted: 5, buffered: 8, limit: 100, want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "Nothing Persisted", persisted: 0, buffered: 3, limit: 2, want: []int{1, 2, 3}},
+		{name: "Persistent Caught Up", persisted: 8, buffered: 8, limit: 3, want: []int{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLaggingFederator(t, tt.persisted, tt.buffered)
+			var got []int
+			q := telemetry.RangeQuery{Limit: tt.limit}
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatal("paging does not terminate")
+				}
+				page, err := f.QueryRange(context.Background(), q)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(page.Records) > tt.limit {
+					t.Fatalf("page of %d records exceeds limit %d", len(page.Records), tt.limit)
+				}
+				got = append(got, breachSeq(page.Records)...)
+				if page.NextCursor == "" {
+					break
+				}
+				q.Cursor = page.NextCursor
+			}
+			if !equalSeq(got, tt.want...) {
+				t.Errorf("paged records %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"services/telemetry"
+)
+
+const (
+	defaultRedisKeyPrefix = "sts"
+	defaultRedisMaxLen    = 10000
+	// redisUpdateAttempts bounds the optimistic transactions of UpdateBreachState.
+	redisUpdateAttempts = 10
+)
+
+// RedisSinkConfig configures the Redis-backed sink and breach state store.
+type RedisSinkConfig struct {
+	// KeyPrefix namespaces the keys; replicas that should share history must use the same prefix.
+	KeyPrefix string
+	// MaxLen caps the number of records retained in the history sorted set.
+	MaxLen int64
+}
+
+// RedisSink implements telemetry.TelemetrySink on a Redis sorted set scored by timestamp, and
+// telemetry.BreachStateStore on a plain key updated in WATCH/MULTI transactions, so multiple STS replicas and external consumers
+// share the same recent-history view and breach state.
+type RedisSink struct {
+	client     redis.UniversalClient
+	historyKey string
+	stateKey   string
+	maxLen     int64
+}
+
+// NewRedisSink wraps an existing Redis client. Zero-valued configuration applies defaults.
+func NewRedisSink(client redis.UniversalClient, cfg RedisSinkConfig) *RedisSink {
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = defaultRedisKeyPrefix
+	}
+	if cfg.MaxLen == 0 {
+		cfg.MaxLen = defaultRedisMaxLen
+	}
+	return &RedisSink{
+		client:     client,
+		historyKey: cfg.KeyPrefix + ":telemetry:history",
+		stateKey:   cfg.KeyPrefix + ":telemetry:breach_state",
+		maxLen:     cfg.MaxLen,
+	}
+}
+
+// Record adds the snapshot to the history set and trims it to MaxLen in a single transaction.
+func (s *RedisSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry record: %w", err)
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, s.historyKey, redis.Z{Score: float64(data.Timestamp.UnixNano()), Member: payload})
+		pipe.ZRemRangeByRank(ctx, s.historyKey, 0, -s.maxLen-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis sink write failed: %w", err)
+	}
+	return nil
+}
+
+// QueryLastN fetches the last N records, ordered from oldest to newest.
+func (s *RedisSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	members, err := s.client.ZRange(ctx, s.historyKey, int64(-n), -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis sink query failed: %w", err)
+	}
+	result := make([]telemetry.TelemetryData, 0, len(members))
+	for _, m := range members {
+		var td telemetry.TelemetryData
+		if err := json.Unmarshal([]byte(m), &td); err != nil {
+			return nil, fmt.Errorf("failed to decode telemetry record: %w", err)
+		}
+		result = append(result, td)
+	}
+	return result, nil
+}
+
+// LoadBreachState reads the shared breach state. A missing key yields the zero state.
+func (s *RedisSink) LoadBreachState(ctx context.Context) (telemetry.BreachState, error) {
+	state, err := decodeBreachState(s.client.Get(ctx, s.stateKey))
+	if err != nil {
+		return telemetry.BreachState{}, fmt.Errorf("redis breach state read failed: %w", err)
+	}
+	return state, nil
+}
+
+// UpdateBreachState implements telemetry.BreachStateStore with an optimistic transaction: the
+// key is watched while update runs, and the write is retried if another replica changed it.
+func (s *RedisSink) UpdateBreachState(ctx context.Context, update func(telemetry.BreachState) telemetry.BreachState) (telemetry.BreachState, error) {
+	var state telemetry.BreachState
+	txf := func(tx *redis.Tx) error {
+		current, err := decodeBreachState(tx.Get(ctx, s.stateKey))
+		if err != nil {
+			return err
+		}
+		state = update(current)
+		payload, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to encode breach state: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, s.stateKey, payload, 0)
+			return nil
+		})
+		return err
+	}
+	for attempt := 0; attempt < redisUpdateAttempts; attempt++ {
+		err := s.client.Watch(ctx, txf, s.stateKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return telemetry.BreachState{}, fmt.Errorf("redis breach state update failed: %w", err)
+		}
+		return state, nil
+	}
+	return telemetry.BreachState{}, fmt.Errorf("redis breach state update failed: %d attempts conflicted with other writers", redisUpdateAttempts)
+}
+
+// decodeBreachState decodes the result of a GET of the state key.
+func decodeBreachState(cmd *redis.StringCmd) (telemetry.BreachState, error) {
+	payload, err := cmd.Bytes()
+	if errors.Is(err, redis.Nil) {
+		return telemetry.BreachState{}, nil
+	}
+	if err != nil {
+		return telemetry.BreachState{}, err
+	}
+	var state telemetry.BreachState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return telemetry.BreachState{}, fmt.Errorf("failed to decode breach state: %w", err)
+	}
+	return state, nil
+}
+
+// Ping checks that the Redis server is reachable.
+func (s *RedisSink) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// Close does not close the injected client, which may be shared.
+func (s *RedisSink) Close(ctx context.Context) error {
+	return nil
+}
+
+// Ensure RedisSink implements both the sink and breach state store interfaces.
+var (
+	_ telemetry.TelemetrySink    = (*RedisSink)(nil)
+	_ telemetry.BreachStateStore = (*RedisSink)(nil)
+)
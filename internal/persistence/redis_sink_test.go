@@ -0,0 +1,90 @@
+package persistence
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"services/telemetry"
+)
+
+func newTestRedisSink(t *testing.T) *RedisSink {
+	t.Helper()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisSink(client, RedisSinkConfig{KeyPrefix: "test"})
+}
+
+func TestRedisSink_UpdateBreachStateIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	sink := newTestRedisSink(t)
+	if state, err := sink.LoadBreachState(ctx); err != nil || state.Count != 0 {
+		t.Fatalf("LoadBreachState() on a missing key = %+v, %v; want the zero state", state, err)
+	}
+
+	const writers, increments = 8, 25
+	var succeeded atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				// Heavily contended updates may exhaust their attempts; every successful one must count.
+				if _, err := sink.UpdateBreachState(ctx, func(s telemetry.BreachState) telemetry.BreachState {
+					s.Count++
+					return s
+				}); err == nil {
+					succeeded.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	state, err := sink.LoadBreachState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := succeeded.Load(); n == 0 || int64(state.Count) != n {
+		t.Errorf("Count = %d after %d successful concurrent increments", state.Count, n)
+	}
+}
+
+// violatingSource reports a latency breach every second, starting at the Unix epoch.
+type violatingSource struct{ i int64 }
+
+func (s *violatingSource) Collect(ctx context.Context) (telemetry.TelemetryData, error) {
+	s.i++
+	return telemetry.TelemetryData{Timestamp: time.Unix(s.i, 0), PipelineLatencyS9: time.Hour, IntegrityHashChainStatus: "SYNCED"}, nil
+}
+
+func TestRedisSink_ReplicasShareOneCountPerCycle(t *testing.T) {
+	sink := newTestRedisSink(t)
+	cfg := telemetry.STSConfiguration{DefaultInterval: time.Second, StateStore: sink}
+	replicas := []telemetry.STS{
+		telemetry.NewSovereignTelemetryService(cfg, &violatingSource{}),
+		telemetry.NewSovereignTelemetryService(cfg, &violatingSource{}),
+		telemetry.NewSovereignTelemetryService(cfg, &violatingSource{}),
+	}
+	for cycle := 0; cycle < 4; cycle++ {
+		for _, sts := range replicas {
+			if err := sts.CollectNow(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	state, err := sink.LoadBreachState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Count != 4 || !state.Cycle.Equal(time.Unix(4, 0)) {
+		t.Errorf("shared state = %+v, want count 4 advanced for cycle 4", state)
+	}
+}
@@ -0,0 +1,347 @@
+package persistence
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"services/telemetry"
+)
+
+const (
+	walFileName             = "telemetry.wal"
+	defaultWALRetryInterval = 5 * time.Second
+)
+
+// WALSinkConfig configures the on-disk write-ahead log in front of a remote sink.
+type WALSinkConfig struct {
+	Dir           string        // Directory holding the log; created if missing
+	RetryInterval time.Duration // How often replay is attempted while records are pending
+}
+
+// WALSink wraps a remote telemetry.TelemetrySink. Records the remote rejects are appended to a
+// fsync'd JSON-lines log and replayed in order once the remote recovers, so a network partition
+// delays telemetry instead of losing it. While the log is non-empty, new records are queued behind
+// it to preserve ordering.
+type WALSink struct {
+	remote   telemetry.TelemetrySink
+	path     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	file    *os.File
+	pending int   // Records in the log not yet delivered
+	lastErr error // Most recent remote or replay failure, guarded by mu
+
+	replayMu sync.Mutex // Serializes replays
+	kick     chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewWALSink opens (or creates) the log in cfg.Dir and starts the background replayer. Records
+// left over from a previous run are replayed first.
+func NewWALSink(remote telemetry.TelemetrySink, cfg WALSinkConfig) (*WALSink, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("wal sink: Dir is required")
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = defaultWALRetryInterval
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("wal sink: failed to create %s: %w", cfg.Dir, err)
+	}
+
+	s := &WALSink{
+		remote:   remote,
+		path:     filepath.Join(cfg.Dir, walFileName),
+		interval: cfg.RetryInterval,
+		kick:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	pending, err := s.recover()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("wal sink: failed to open %s: %w", s.path, err)
+	}
+	s.file = file
+	s.pending = pending
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.run(ctx)
+	if pending > 0 {
+		s.trigger()
+	}
+	return s, nil
+}
+
+// recover counts complete records in an existing log and truncates a torn final write left by a crash.
+func (s *WALSink) recover() (int, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("wal sink: failed to read %s: %w", s.path, err)
+	}
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	if complete < len(data) {
+		if err := os.Truncate(s.path, int64(complete)); err != nil {
+			return 0, fmt.Errorf("wal sink: failed to truncate torn record: %w", err)
+		}
+	}
+	return bytes.Count(data[:complete], []byte{'\n'}), nil
+}
+
+// Record forwards the snapshot to the remote sink, falling back to the log if the remote fails.
+// An error is returned only if the record could not be made durable locally.
+func (s *WALSink) Record(ctx context.Context, data telemetry.TelemetryData) error {
+	s.mu.Lock()
+	backlog := s.pending > 0
+	s.mu.Unlock()
+
+	if !backlog {
+		err := s.remote.Record(ctx, data)
+		if err == nil {
+			return nil
+		}
+		s.setErr(err)
+	}
+	if err := s.append(data); err != nil {
+		return fmt.Errorf("wal sink: failed to persist record: %w", err)
+	}
+	if !backlog {
+		s.trigger()
+	}
+	return nil
+}
+
+func (s *WALSink) append(data telemetry.TelemetryData) error {
+	line, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.pending++
+	return nil
+}
+
+func (s *WALSink) trigger() {
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+// run attempts replay on every interval, and immediately after the log goes from empty to non-empty.
+func (s *WALSink) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+		if err := s.replay(ctx); err != nil {
+			s.setErr(err)
+		}
+	}
+}
+
+// replay delivers logged records in order until the remote fails, then compacts the delivered
+// prefix out of the log. Records appended during replay are preserved for the next pass.
+func (s *WALSink) replay(ctx context.Context) error {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+
+	s.mu.Lock()
+	if s.pending == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	info, err := s.file.Stat()
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("wal sink: %w", err)
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("wal sink: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		delivered int64
+		sendErr   error
+	)
+	r := bufio.NewReader(io.LimitReader(f, info.Size()))
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// io.EOF, or a partial line still being written; it is picked up next pass.
+			break
+		}
+		var td telemetry.TelemetryData
+		if err := json.Unmarshal(line, &td); err != nil {
+			// A corrupt entry can never be delivered; drop it rather than block the log.
+			s.setErr(fmt.Errorf("wal sink: dropped corrupt record: %w", err))
+			delivered += int64(len(line))
+			continue
+		}
+		if sendErr = s.remote.Record(ctx, td); sendErr != nil {
+			break
+		}
+		delivered += int64(len(line))
+	}
+
+	if delivered > 0 {
+		if err := s.compact(delivered); err != nil {
+			return err
+		}
+	}
+	if sendErr != nil {
+		return fmt.Errorf("wal sink: replay paused: %w", sendErr)
+	}
+	s.mu.Lock()
+	s.lastErr = nil
+	s.mu.Unlock()
+	return nil
+}
+
+// compact rewrites the log without its first n bytes and reopens it for appending.
+func (s *WALSink) compact(n int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("wal sink: compaction read failed: %w", err)
+	}
+	rest := data[n:]
+
+	tmp := s.path + ".tmp"
+	if err := writeFileSync(tmp, rest); err != nil {
+		return fmt.Errorf("wal sink: compaction write failed: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("wal sink: compaction rename failed: %w", err)
+	}
+	// Without this, a crash could bring back the old log and replay delivered records again.
+	if err := syncDir(filepath.Dir(s.path)); err != nil {
+		return fmt.Errorf("wal sink: compaction sync failed: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("wal sink: failed to reopen %s: %w", s.path, err)
+	}
+	s.file.Close()
+	s.file = file
+	s.pending = bytes.Count(rest, []byte{'\n'})
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir fsyncs a directory, making a rename within it durable. Windows cannot sync a directory
+// handle; NTFS journals the rename itself.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *WALSink) setErr(err error) {
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+}
+
+// Pending reports the number of records waiting in the log for the remote to recover.
+func (s *WALSink) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// LastError reports the most recent remote failure, or nil once a replay fully drains the log.
+func (s *WALSink) LastError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// QueryLastN delegates to the remote sink. Records still in the log are not included.
+func (s *WALSink) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	return s.remote.QueryLastN(ctx, n)
+}
+
+// Close stops the replayer, makes a final replay attempt bounded by ctx, and closes the remote.
+// Undelivered records stay on disk and are replayed by the next NewWALSink on the same Dir.
+func (s *WALSink) Close(ctx context.Context) error {
+	s.cancel()
+	<-s.done
+	replayErr := s.replay(ctx)
+
+	s.mu.Lock()
+	pending := s.pending
+	s.file.Close()
+	s.mu.Unlock()
+
+	if err := s.remote.Close(ctx); err != nil {
+		return err
+	}
+	if replayErr != nil {
+		return fmt.Errorf("wal sink: %d records left pending: %w", pending, replayErr)
+	}
+	return nil
+}
+
+// Ensure WALSink implements the TelemetrySink interface.
+var _ telemetry.TelemetrySink = (*WALSink)(nil)
@@ -0,0 +1,224 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"services/telemetry"
+)
+
+var errRemoteDown = errors.New("remote down")
+
+// flakyRemote accepts records while it has budget; a negative budget accepts everything.
+type flakyRemote struct {
+	mu      sync.Mutex
+	budget  int
+	records []telemetry.TelemetryData
+	closed  bool
+}
+
+func (r *flakyRemote) setBudget(n int) {
+	r.mu.Lock()
+	r.budget = n
+	r.mu.Unlock()
+}
+
+func (r *flakyRemote) Record(ctx context.Context, td telemetry.TelemetryData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.budget == 0 {
+		return errRemoteDown
+	}
+	if r.budget > 0 {
+		r.budget--
+	}
+	r.records = append(r.records, td)
+	return nil
+}
+
+func (r *flakyRemote) QueryLastN(ctx context.Context, n int) ([]telemetry.TelemetryData, error) {
+	return nil, nil
+}
+
+func (r *flakyRemote) Close(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	return nil
+}
+
+// breaches returns the breach counts of the delivered records, which the tests use as sequence numbers.
+func (r *flakyRemote) breaches() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var seq []int
+	for _, td := range r.records {
+		seq = append(seq, td.GATMBreachCount)
+	}
+	return seq
+}
+
+func record(seq int) telemetry.TelemetryData {
+	return telemetry.TelemetryData{Timestamp: time.Unix(int64(seq), 0).UTC(), GATMBreachCount: seq}
+}
+
+func waitPending(t *testing.T, s *WALSink, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Pending() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Pending() = %d, want %d", s.Pending(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func equalSeq(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func newTestWALSink(t *testing.T, remote *flakyRemote, dir string) *WALSink {
+	t.Helper()
+	s, err := NewWALSink(remote, WALSinkConfig{Dir: dir, RetryInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewWALSink() error = %v", err)
+	}
+	return s
+}
+
+func TestWALSink_ReplaysAfterTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	var log []byte
+	for seq := 1; seq <= 2; seq++ {
+		line, _ := json.Marshal(record(seq))
+		log = append(append(log, line...), '\n')
+	}
+	log = append(log, `{"timestamp":"2026-`...) // Crashed mid-write
+	if err := os.WriteFile(filepath.Join(dir, walFileName), log, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := &flakyRemote{budget: -1}
+	s := newTestWALSink(t, remote, dir)
+	defer s.Close(context.Background())
+	waitPending(t, s, 0)
+	if got := remote.breaches(); !equalSeq(got, 1, 2) {
+		t.Errorf("replayed %v, want the two complete records", got)
+	}
+	if err := s.LastError(); err != nil {
+		t.Errorf("LastError() = %v, want the torn record discarded silently", err)
+	}
+}
+
+func TestWALSink_QueuesBehindBacklog(t *testing.T) {
+	remote := &flakyRemote{budget: 0}
+	s := newTestWALSink(t, remote, t.TempDir())
+	defer s.Close(context.Background())
+
+	// Hold replay off so the backlog outlives the remote's recovery.
+	s.replayMu.Lock()
+	s.Record(context.Background(), record(1))
+	remote.setBudget(-1)
+	s.Record(context.Background(), record(2))
+	s.Record(context.Background(), record(3))
+	if got := remote.breaches(); len(got) != 0 || s.Pending() != 3 {
+		t.Errorf("delivered %v with %d pending, want every record queued behind the first", got, s.Pending())
+	}
+	s.replayMu.Unlock()
+
+	s.trigger()
+	waitPending(t, s, 0)
+	if got := remote.breaches(); !equalSeq(got, 1, 2, 3) {
+		t.Errorf("delivered %v, want 1 2 3", got)
+	}
+	if err := s.LastError(); err != nil {
+		t.Errorf("LastError() = %v after the log drained", err)
+	}
+}
+
+func TestWALSink_CompactsDeliveredPrefix(t *testing.T) {
+	dir := t.TempDir()
+	remote := &flakyRemote{budget: 0}
+	s := newTestWALSink(t, remote, dir)
+	defer s.Close(context.Background())
+	for seq := 1; seq <= 3; seq++ {
+		if err := s.Record(context.Background(), record(seq)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remote.setBudget(1)
+	if err := s.replay(context.Background()); !errors.Is(err, errRemoteDown) {
+		t.Fatalf("replay() error = %v, want the remote failure", err)
+	}
+	if s.Pending() != 2 {
+		t.Errorf("Pending() = %d after delivering one of three", s.Pending())
+	}
+	// The reopened log still takes appends after the compacted records.
+	if err := s.Record(context.Background(), record(4)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged []int
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var td telemetry.TelemetryData
+		if err := json.Unmarshal([]byte(line), &td); err != nil {
+			t.Fatal(err)
+		}
+		logged = append(logged, td.GATMBreachCount)
+	}
+	if !equalSeq(logged, 2, 3, 4) {
+		t.Errorf("log holds %v, want 2 3 4", logged)
+	}
+	if _, err := os.Stat(filepath.Join(dir, walFileName+".tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("compaction left its temporary file: %v", err)
+	}
+}
+
+func TestWALSink_Close(t *testing.T) {
+	dir := t.TempDir()
+	remote := &flakyRemote{budget: 0}
+	s := newTestWALSink(t, remote, dir)
+	s.Record(context.Background(), record(1))
+	s.Record(context.Background(), record(2))
+
+	// The remote is still down: the records stay on disk for the next run.
+	if err := s.Close(context.Background()); err == nil || !strings.Contains(err.Error(), "2 records left pending") {
+		t.Errorf("Close() error = %v, want the pending records reported", err)
+	}
+	if !remote.closed {
+		t.Error("Close() did not close the remote")
+	}
+
+	next := &flakyRemote{budget: 0}
+	s = newTestWALSink(t, next, dir)
+	if s.Pending() != 2 {
+		t.Fatalf("Pending() = %d after reopening", s.Pending())
+	}
+	// A recovered remote receives the backlog in Close's final replay.
+	next.setBudget(-1)
+	if err := s.Close(context.Background()); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if got := next.breaches(); !equalSeq(got, 1, 2) {
+		t.Errorf("final replay delivered %v, want 1 2", got)
+	}
+}
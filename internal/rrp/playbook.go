@@ -0,0 +1,159 @@
+// Package rrp runs Rapid Recovery Protocol (RRP) playbooks: ordered recovery steps executed when
+// telemetry escalates to the RRP level, with per-step timeouts and rollback of completed steps.
+package rrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"internal/crot"
+)
+
+const defaultStepTimeout = 30 * time.Second
+
+// Built-in step actions.
+const (
+	ActionRestartUnit = "restart_unit" // systemctl restart <target>
+	ActionClearCache  = "clear_cache"  // Dependencies.Caches[<target>]
+	ActionVerifyCRoT  = "verify_crot"  // Dependencies.Integrity must report SYNCED
+	ActionCommand     = "command"      // Run Command directly, without a shell
+)
+
+// Step is one recovery step. Rollback, if set, undoes the step when a later step fails.
+type Step struct {
+	Name     string
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+	Rollback func(ctx context.Context) error
+}
+
+// Playbook is an ordered list of recovery steps.
+type Playbook struct {
+	Name  string
+	Steps []Step
+}
+
+// PlaybookConfig defines a playbook in configuration.
+type PlaybookConfig struct {
+	Name  string       `json:"name" yaml:"name"`
+	Steps []StepConfig `json:"steps" yaml:"steps"`
+}
+
+// StepConfig defines one step. Target names the unit for restart_unit and the cache for
+// clear_cache; Command is the argv for the command action. Rollback, if set, is an argv run to
+// undo the step when a later step fails.
+type StepConfig struct {
+	Name     string        `json:"name" yaml:"name"`
+	Action   string        `json:"action" yaml:"action"`
+	Target   string        `json:"target,omitempty" yaml:"target,omitempty"`
+	Command  []string      `json:"command,omitempty" yaml:"command,omitempty"`
+	Timeout  time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"` // Default 30s
+	Rollback []string      `json:"rollback,omitempty" yaml:"rollback,omitempty"`
+}
+
+// Dependencies supplies the collaborators built-in actions act on.
+type Dependencies struct {
+	// Integrity is re-checked by verify_crot steps.
+	Integrity crot.IntegrityProvider
+	// Caches are cleared by name by clear_cache steps.
+	Caches map[string]func(ctx context.Context) error
+}
+
+// Validate checks the playbook definition without resolving dependencies.
+func (c PlaybookConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("rrp: playbook name must not be empty")
+	}
+	if len(c.Steps) == 0 {
+		return fmt.Errorf("rrp: playbook '%s' has no steps", c.Name)
+	}
+	seen := make(map[string]bool, len(c.Steps))
+	for i, s := range c.Steps {
+		if s.Name == "" {
+			return fmt.Errorf("rrp: step %d of playbook '%s' has no name", i, c.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("rrp: duplicate step name '%s'", s.Name)
+		}
+		seen[s.Name] = true
+		if s.Timeout < 0 {
+			return fmt.Errorf("rrp: step '%s' timeout must not be negative", s.Name)
+		}
+		switch s.Action {
+		case ActionRestartUnit, ActionClearCache:
+			if s.Target == "" {
+				return fmt.Errorf("rrp: step '%s' (%s) requires a target", s.Name, s.Action)
+			}
+		case ActionCommand:
+			if len(s.Command) == 0 {
+				return fmt.Errorf("rrp: step '%s' requires a command", s.Name)
+			}
+		case ActionVerifyCRoT:
+		default:
+			return fmt.Errorf("rrp: step '%s' has unknown action '%s'", s.Name, s.Action)
+		}
+	}
+	return nil
+}
+
+// Build validates the configuration and binds each step to its implementation.
+func (c PlaybookConfig) Build(deps Dependencies) (*Playbook, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	pb := &Playbook{Name: c.Name, Steps: make([]Step, 0, len(c.Steps))}
+	for _, sc := range c.Steps {
+		step := Step{Name: sc.Name, Timeout: sc.Timeout}
+		switch sc.Action {
+		case ActionRestartUnit:
+			step.Run = commandFunc([]string{"systemctl", "restart", sc.Target})
+		case ActionClearCache:
+			clear, ok := deps.Caches[sc.Target]
+			if !ok {
+				return nil, fmt.Errorf("rrp: step '%s' references unknown cache '%s'", sc.Name, sc.Target)
+			}
+			step.Run = clear
+		case ActionVerifyCRoT:
+			if deps.Integrity == nil {
+				return nil, fmt.Errorf("rrp: step '%s' requires an integrity provider", sc.Name)
+			}
+			step.Run = verifyCRoT(deps.Integrity)
+		case ActionCommand:
+			step.Run = commandFunc(sc.Command)
+		}
+		if len(sc.Rollback) > 0 {
+			step.Rollback = commandFunc(sc.Rollback)
+		}
+		pb.Steps = append(pb.Steps, step)
+	}
+	return pb, nil
+}
+
+func verifyCRoT(p crot.IntegrityProvider) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		status, err := p.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("CRoT integrity check failed: %w", err)
+		}
+		if status != crot.IntegritySynced {
+			return fmt.Errorf("CRoT integrity is %s", status)
+		}
+		return nil
+	}
+}
+
+func commandFunc(argv []string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		out, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput()
+		if err != nil {
+			if len(out) > 1024 {
+				out = out[len(out)-1024:]
+			}
+			return fmt.Errorf("%s: %w: %s", argv[0], err, out)
+		}
+		return nil
+	}
+}
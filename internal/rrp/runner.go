@@ -0,0 +1,223 @@
+package rrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"internal/events"
+	"pkg/correlation"
+	"pkg/metrics"
+	"services/telemetry"
+)
+
+// Step outcomes reported in StepResult.Status.
+const (
+	StepSucceeded      = "succeeded"
+	StepFailed         = "failed"
+	StepTimedOut       = "timed_out"
+	StepSkipped        = "skipped"     // Not reached because an earlier step failed
+	StepRolledBack     = "rolled_back" // Succeeded, then undone after a later failure
+	StepRollbackFailed = "rollback_failed"
+)
+
+// Sentinel errors returned by Runner; match them with errors.Is.
+var (
+	ErrPlaybookRunning = errors.New("recovery playbook already running")
+	ErrPlaybookFailed  = errors.New("recovery playbook failed")
+)
+
+// Logger defines the interface required for internal component logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// StepResult reports the outcome of one step.
+type StepResult struct {
+	Name     string        `json:"name"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Result reports a playbook run.
+type Result struct {
+	Playbook  string        `json:"playbook"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Succeeded bool          `json:"succeeded"`
+	Steps     []StepResult  `json:"steps"`
+}
+
+// RunnerConfig configures the playbook runner.
+type RunnerConfig struct {
+	Playbook *Playbook
+	// Metrics, if set, receives run and step outcomes.
+	Metrics metrics.Provider
+	// Bus, if set, receives a RecoveryCompleted event after every run.
+	Bus *events.Bus
+}
+
+// Runner executes a playbook, one run at a time.
+type Runner struct {
+	cfg     RunnerConfig
+	log     Logger
+	running atomic.Bool
+
+	mu   sync.Mutex
+	last *Result
+
+	runs         metrics.Counter   // playbook, result: succeeded|failed
+	steps        metrics.Counter   // playbook, step, status
+	stepDuration metrics.Histogram // playbook, step
+}
+
+// NewRunner creates a runner for cfg.Playbook.
+func NewRunner(cfg RunnerConfig, logger Logger) *Runner {
+	p := metrics.OrNop(cfg.Metrics)
+	return &Runner{
+		cfg:          cfg,
+		log:          logger,
+		runs:         p.Counter("rrp_playbook_runs_total", "Recovery playbook runs by playbook and result.", "playbook", "result"),
+		steps:        p.Counter("rrp_playbook_steps_total", "Recovery playbook steps by playbook, step, and status.", "playbook", "step", "status"),
+		stepDuration: p.Histogram("rrp_playbook_step_duration_seconds", "Recovery playbook step duration.", nil, "playbook", "step"),
+	}
+}
+
+// Run executes the steps in order, each bounded by its timeout. When a step fails, the remaining
+// steps are skipped and the completed steps' rollback hooks run in reverse order. Rollbacks are
+// not cut short by cancellation of ctx, only by their step's timeout.
+func (r *Runner) Run(ctx context.Context) (Result, error) {
+	if !r.running.CompareAndSwap(false, true) {
+		return Result{}, ErrPlaybookRunning
+	}
+	defer r.running.Store(false)
+
+	pb := r.cfg.Playbook
+	res := Result{Playbook: pb.Name, StartedAt: time.Now(), Succeeded: true, Steps: make([]StepResult, len(pb.Steps))}
+	failed := -1
+	for i, step := range pb.Steps {
+		res.Steps[i].Name = step.Name
+		if failed >= 0 {
+			res.Steps[i].Status = StepSkipped
+			continue
+		}
+		stepCtx, cancel := context.WithTimeout(ctx, stepTimeout(step))
+		start := time.Now()
+		err := step.Run(stepCtx)
+		res.Steps[i].Duration = time.Since(start)
+		switch {
+		case err == nil:
+			res.Steps[i].Status = StepSucceeded
+		case errors.Is(stepCtx.Err(), context.DeadlineExceeded):
+			res.Steps[i].Status, res.Steps[i].Error = StepTimedOut, err.Error()
+		default:
+			res.Steps[i].Status, res.Steps[i].Error = StepFailed, err.Error()
+		}
+		cancel()
+		if err != nil {
+			failed = i
+			res.Succeeded = false
+		}
+	}
+
+	rolledBack := 0
+	if failed >= 0 {
+		rollbackCtx := context.WithoutCancel(ctx)
+		for i := failed - 1; i >= 0; i-- {
+			step := pb.Steps[i]
+			if step.Rollback == nil {
+				continue
+			}
+			stepCtx, cancel := context.WithTimeout(rollbackCtx, stepTimeout(step))
+			err := step.Rollback(stepCtx)
+			cancel()
+			if err != nil {
+				res.Steps[i].Status, res.Steps[i].Error = StepRollbackFailed, err.Error()
+				continue
+			}
+			res.Steps[i].Status = StepRolledBack
+			rolledBack++
+		}
+	}
+	res.Duration = time.Since(res.StartedAt)
+	r.report(ctx, res, failed, rolledBack)
+
+	if failed >= 0 {
+		return res, fmt.Errorf("%w: %s at step '%s': %s", ErrPlaybookFailed, pb.Name, res.Steps[failed].Name, res.Steps[failed].Error)
+	}
+	return res, nil
+}
+
+func stepTimeout(s Step) time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+	return defaultStepTimeout
+}
+
+// report records the result for LastResult, metrics, the event bus, and the log.
+func (r *Runner) report(ctx context.Context, res Result, failed, rolledBack int) {
+	r.mu.Lock()
+	r.last = &res
+	r.mu.Unlock()
+
+	outcome := StepSucceeded
+	if !res.Succeeded {
+		outcome = StepFailed
+	}
+	r.runs.Inc(res.Playbook, outcome)
+	for _, s := range res.Steps {
+		r.steps.Inc(res.Playbook, s.Name, s.Status)
+		if s.Status != StepSkipped {
+			r.stepDuration.Observe(s.Duration.Seconds(), res.Playbook, s.Name)
+		}
+	}
+
+	ev := events.RecoveryCompletedEvent{
+		Playbook: res.Playbook, Succeeded: res.Succeeded, RolledBack: rolledBack,
+		Duration: res.Duration, At: time.Now(), CorrelationID: correlation.FromContext(ctx),
+	}
+	if failed >= 0 {
+		ev.FailedStep = res.Steps[failed].Name
+	}
+	if r.cfg.Bus != nil {
+		events.Publish(r.cfg.Bus, events.RecoveryCompleted, ev)
+	}
+
+	if r.log == nil {
+		return
+	}
+	if res.Succeeded {
+		r.log.Infof("Recovery playbook %s succeeded in %v", res.Playbook, res.Duration)
+	} else {
+		r.log.Errorf("Recovery playbook %s failed at step %s (%d steps rolled back)", res.Playbook, ev.FailedStep, rolledBack)
+	}
+}
+
+// LastResult returns the most recent run's result, or false if the playbook has not run.
+func (r *Runner) LastResult() (Result, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		return Result{}, false
+	}
+	return *r.last, true
+}
+
+// Handler adapts the runner to telemetry.EscalationHandler, for registration on the severity tier
+// or violation causes that mean RRP. A run already in progress is not restarted.
+func (r *Runner) Handler() telemetry.EscalationHandler {
+	return telemetry.EscalationHandlerFunc(func(ctx context.Context, td telemetry.TelemetryData) error {
+		_, err := r.Run(ctx)
+		if errors.Is(err, ErrPlaybookRunning) {
+			return nil
+		}
+		return err
+	})
+}
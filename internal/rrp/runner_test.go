@@ -0,0 +1,96 @@
+package rrp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunnerRollsBackOnFailure(t *testing.T) {
+	var calls []string
+	step := func(name string, err error) Step {
+		return Step{
+			Name:     name,
+			Timeout:  50 * time.Millisecond,
+			Run:      func(ctx context.Context) error { calls = append(calls, "run:"+name); return err },
+			Rollback: func(ctx context.Context) error { calls = append(calls, "rollback:"+name); return nil },
+		}
+	}
+	hang := Step{Name: "hang", Timeout: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	tests := []struct {
+		name       string
+		steps      []Step
+		wantErr    bool
+		wantCalls  []string
+		wantStatus []string
+	}{
+		{
+			name:       "Success",
+			steps:      []Step{step("restart", nil), step("verify", nil)},
+			wantCalls:  []string{"run:restart", "run:verify"},
+			wantStatus: []string{StepSucceeded, StepSucceeded},
+		},
+		{
+			name:       "Failure Rolls Back Completed Steps",
+			steps:      []Step{step("restart", nil), step("clear", nil), step("verify", errors.New("DIVERGED")), step("notify", nil)},
+			wantErr:    true,
+			wantCalls:  []string{"run:restart", "run:clear", "run:verify", "rollback:clear", "rollback:restart"},
+			wantStatus: []string{StepRolledBack, StepRolledBack, StepFailed, StepSkipped},
+		},
+		{
+			name:       "Timeout",
+			steps:      []Step{step("restart", nil), hang},
+			wantErr:    true,
+			wantCalls:  []string{"run:restart", "rollback:restart"},
+			wantStatus: []string{StepRolledBack, StepTimedOut},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			r := NewRunner(RunnerConfig{Playbook: &Playbook{Name: "recover", Steps: tt.steps}}, nil)
+			res, err := r.Run(context.Background())
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrPlaybookFailed)) {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+			var status []string
+			for _, s := range res.Steps {
+				status = append(status, s.Status)
+			}
+			if !reflect.DeepEqual(status, tt.wantStatus) || res.Succeeded == tt.wantErr {
+				t.Errorf("statuses = %v (succeeded %t), want %v", status, res.Succeeded, tt.wantStatus)
+			}
+			if last, ok := r.LastResult(); !ok || last.Playbook != "recover" {
+				t.Errorf("LastResult() = %+v, %t", last, ok)
+			}
+		})
+	}
+}
+
+func TestPlaybookConfigBuild(t *testing.T) {
+	cfg := PlaybookConfig{Name: "recover", Steps: []StepConfig{
+		{Name: "restart", Action: ActionRestartUnit, Target: "sts-collector.service"},
+		{Name: "clear", Action: ActionClearCache, Target: "admission"},
+	}}
+	if _, err := cfg.Build(Dependencies{}); err == nil {
+		t.Error("Build() should fail for an unknown cache")
+	}
+	pb, err := cfg.Build(Dependencies{Caches: map[string]func(context.Context) error{"admission": func(context.Context) error { return nil }}})
+	if err != nil || len(pb.Steps) != 2 {
+		t.Fatalf("Build() = %+v, %v", pb, err)
+	}
+
+	cfg.Steps = append(cfg.Steps, StepConfig{Name: "reboot", Action: "reboot"})
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown action")
+	}
+}
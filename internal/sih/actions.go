@@ -0,0 +1,182 @@
+package sih
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// In-cluster service account credentials, as mounted by Kubernetes.
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	maxCommandOutput  = 4096
+)
+
+// KubeClient is the minimal Kubernetes API client the quarantine actions need.
+type KubeClient struct {
+	BaseURL string // e.g. "https://10.0.0.1:443"
+	Token   string
+	HTTP    *http.Client
+}
+
+// InClusterKubeClient builds a client from the pod's service account, the way in-cluster
+// Kubernetes clients do.
+func InClusterKubeClient() (*KubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes cluster: KUBERNETES_SERVICE_HOST/PORT unset")
+	}
+	token, err := os.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("service account CA contains no certificates")
+	}
+	return &KubeClient{
+		BaseURL: "https://" + net.JoinHostPort(host, port),
+		Token:   strings.TrimSpace(string(token)),
+		HTTP: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+		},
+	}, nil
+}
+
+// patch sends a strategic merge patch to path.
+func (c *KubeClient) patch(ctx context.Context, path string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, strings.TrimRight(c.BaseURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/strategic-merge-patch+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kubernetes API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kubernetes API returned %d for PATCH %s: %s", resp.StatusCode, path, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// CordonNode marks a Kubernetes node unschedulable so no new pods are placed on it.
+type CordonNode struct {
+	Client *KubeClient
+	Node   string
+}
+
+func (a *CordonNode) Name() string     { return "cordon_node" }
+func (a *CordonNode) Describe() string { return "cordon node " + a.Node }
+
+func (a *CordonNode) Execute(ctx context.Context) error {
+	return a.Client.patch(ctx, "/api/v1/nodes/"+url.PathEscape(a.Node),
+		map[string]interface{}{"spec": map[string]interface{}{"unschedulable": true}})
+}
+
+// ReadinessGate sets a pod readiness-gate condition to False, taking the pod out of Service
+// endpoints. The pod spec must list ConditionType under readinessGates.
+type ReadinessGate struct {
+	Client        *KubeClient
+	Namespace     string
+	Pod           string
+	ConditionType string // e.g. "sts.example.com/integrity"
+}
+
+func (a *ReadinessGate) Name() string { return "readiness_gate" }
+func (a *ReadinessGate) Describe() string {
+	return fmt.Sprintf("set readiness gate %s=False on pod %s/%s", a.ConditionType, a.Namespace, a.Pod)
+}
+
+func (a *ReadinessGate) Execute(ctx context.Context) error {
+	condition := map[string]interface{}{
+		"type":               a.ConditionType,
+		"status":             "False",
+		"reason":             "SIHQuarantine",
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	}
+	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/status", url.PathEscape(a.Namespace), url.PathEscape(a.Pod))
+	return a.Client.patch(ctx, path, map[string]interface{}{"status": map[string]interface{}{"conditions": []interface{}{condition}}})
+}
+
+// Suspender stops admissions, e.g. *admission.Warmer.
+type Suspender interface {
+	Suspend(reason string)
+}
+
+// StopAdmissions denies every admission request on this node until resumed by an operator.
+type StopAdmissions struct {
+	Target Suspender
+}
+
+func (a *StopAdmissions) Name() string     { return "stop_admissions" }
+func (a *StopAdmissions) Describe() string { return "stop accepting admissions" }
+
+func (a *StopAdmissions) Execute(ctx context.Context) error {
+	a.Target.Suspend("node quarantined by SIH")
+	return nil
+}
+
+// RunCommand runs a configured command, e.g. a site-specific isolation script. The command is
+// executed directly, not through a shell.
+type RunCommand struct {
+	Path string
+	Args []string
+	Env  []string // Added to the inherited environment
+}
+
+func (a *RunCommand) Name() string { return "run_command" }
+func (a *RunCommand) Describe() string {
+	return "run " + strings.Join(append([]string{a.Path}, a.Args...), " ")
+}
+
+func (a *RunCommand) Execute(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, a.Path, a.Args...)
+	cmd.Env = append(os.Environ(), a.Env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if len(out) > maxCommandOutput {
+			out = out[len(out)-maxCommandOutput:]
+		}
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Ensure the built-in actions implement Action.
+var (
+	_ Action = (*CordonNode)(nil)
+	_ Action = (*ReadinessGate)(nil)
+	_ Action = (*StopAdmissions)(nil)
+	_ Action = (*RunCommand)(nil)
+)
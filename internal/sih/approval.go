@@ -0,0 +1,408 @@
+package sih
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"internal/admin"
+	"internal/authz"
+	"pkg/correlation"
+)
+
+const (
+	defaultApprovalQuorum = 2
+	defaultApprovalTTL    = 15 * time.Minute
+)
+
+// Approval request states.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalExpired  = "expired"
+)
+
+// Audited approval actions.
+const (
+	ActionApprovalRequested = "sih_approval_requested"
+	ActionApprove           = "sih_approve"
+)
+
+// Sentinel errors for the approval workflow; match them with errors.Is.
+var (
+	ErrApprovalPending  = errors.New("sih actions awaiting approval")
+	ErrApprovalNotFound = errors.New("no pending sih approval request with that ID")
+	ErrDuplicateApprove = errors.New("principal has already approved this request")
+	ErrInvalidToken     = errors.New("invalid sih approval token")
+)
+
+// ApprovalConfig configures two-person approval of quarantine actions.
+type ApprovalConfig struct {
+	// Quorum is the number of distinct principals that must approve (default 2).
+	Quorum int
+	// TTL is how long a request waits for approval before it expires (default 15m).
+	TTL time.Duration
+	// TrustedKeys verify pre-signed approval tokens, by key ID.
+	TrustedKeys map[string]ed25519.PublicKey
+}
+
+// ApprovalRequest is a quarantine waiting for, or granted, approval.
+type ApprovalRequest struct {
+	ID            string    `json:"id"`
+	Reason        string    `json:"reason"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	RequestedAt   time.Time `json:"requested_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Approvers     []string  `json:"approvers"`
+	Status        string    `json:"status"`
+}
+
+// TokenClaims are the contents of a pre-signed approval token: the approvers who signed off in
+// advance, e.g. through a change board, and until when. An empty RequestID approves any request
+// opened before ExpiresAt.
+type TokenClaims struct {
+	KeyID     string    `json:"key_id"`
+	Approvers []string  `json:"approvers"`
+	RequestID string    `json:"request_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ApprovalGate holds quarantine actions until enough operators approve them, for environments
+// that forbid fully automated isolation. Only one request is open at a time; escalations while it
+// is pending join it.
+type ApprovalGate struct {
+	cfg   ApprovalConfig
+	audit *admin.AuditLog
+	now   func() time.Time
+
+	mu       sync.Mutex
+	current  *ApprovalRequest
+	run      func(ctx context.Context) error // Actions to execute on approval
+	standing []TokenClaims                   // Installed tokens approving future requests
+}
+
+// NewApprovalGate creates a gate. audit, if set, records every request, approval, and expiry.
+func NewApprovalGate(cfg ApprovalConfig, audit *admin.AuditLog) *ApprovalGate {
+	if cfg.Quorum <= 0 {
+		cfg.Quorum = defaultApprovalQuorum
+	}
+	if cfg.TTL == 0 {
+		cfg.TTL = defaultApprovalTTL
+	}
+	return &ApprovalGate{cfg: cfg, audit: audit, now: time.Now}
+}
+
+// request opens an approval request for run, or joins the pending one. If an installed token
+// covers the request, run executes immediately.
+func (g *ApprovalGate) request(ctx context.Context, reason string, run func(ctx context.Context) error) error {
+	g.mu.Lock()
+	expired := g.expireLocked()
+	if g.current != nil && g.current.Status == ApprovalPending {
+		g.mu.Unlock()
+		return errors.Join(ErrApprovalPending, g.recordExpiry(expired))
+	}
+	now := g.now()
+	req := &ApprovalRequest{
+		ID:            newRequestID(),
+		Reason:        reason,
+		CorrelationID: correlation.FromContext(ctx),
+		RequestedAt:   now,
+		ExpiresAt:     now.Add(g.cfg.TTL),
+		Status:        ApprovalPending,
+	}
+	g.current, g.run = req, run
+	standing := g.standingLocked(req.ID)
+	g.mu.Unlock()
+
+	if err := g.recordExpiry(expired); err != nil {
+		return err
+	}
+	if err := g.record(admin.AuditRecord{Principal: admin.SystemPrincipal, Action: ActionApprovalRequested, Reason: reason, Detail: "request=" + req.ID, CorrelationID: req.CorrelationID}); err != nil {
+		return err
+	}
+	if standing != nil {
+		_, err := g.grant(ctx, req.ID, admin.SystemPrincipal, standing.Approvers, tokenDetail(*standing))
+		return err
+	}
+	return ErrApprovalPending
+}
+
+// Approve records principal's approval of request id. Once Quorum distinct principals have
+// approved, the held actions run; executed reports whether that happened on this call.
+func (g *ApprovalGate) Approve(ctx context.Context, id, principal string) (executed bool, err error) {
+	return g.grant(ctx, id, principal, []string{principal}, "")
+}
+
+// ApproveWithToken approves request id with a pre-signed token, which satisfies the quorum on its
+// own. principal is the caller submitting the token; the approval is audited under its name, with
+// the token's approvers in the detail.
+func (g *ApprovalGate) ApproveWithToken(ctx context.Context, id, principal, token string) (executed bool, err error) {
+	claims, err := g.verify(token)
+	if err != nil {
+		return false, err
+	}
+	if claims.RequestID != "" && claims.RequestID != id {
+		return false, fmt.Errorf("%w: issued for request %s", ErrInvalidToken, claims.RequestID)
+	}
+	return g.grant(ctx, id, principal, claims.Approvers, tokenDetail(claims))
+}
+
+// tokenDetail describes a token approval for the audit log.
+func tokenDetail(claims TokenClaims) string {
+	return "token=" + claims.KeyID + " approvers=" + strings.Join(claims.Approvers, ",")
+}
+
+// InstallToken verifies a pre-signed token and keeps it, so requests opened before it expires are
+// approved without waiting for operators.
+func (g *ApprovalGate) InstallToken(token string) error {
+	claims, err := g.verify(token)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	g.standing = append(g.standing, claims)
+	g.mu.Unlock()
+	return nil
+}
+
+// grant adds approvers to request id on behalf of principal, who is audited as the approving
+// caller, and runs the held actions once the quorum is reached.
+func (g *ApprovalGate) grant(ctx context.Context, id, principal string, approvers []string, detail string) (bool, error) {
+	g.mu.Lock()
+	expired := g.expireLocked()
+	req := g.current
+	if req == nil || req.ID != id || req.Status != ApprovalPending {
+		g.mu.Unlock()
+		return false, errors.Join(ErrApprovalNotFound, g.recordExpiry(expired))
+	}
+	added := false
+	for _, p := range approvers {
+		if !contains(req.Approvers, p) {
+			req.Approvers = append(req.Approvers, p)
+			added = true
+		}
+	}
+	if !added {
+		g.mu.Unlock()
+		return false, ErrDuplicateApprove
+	}
+	approved := len(req.Approvers) >= g.cfg.Quorum
+	run := g.run
+	if approved {
+		req.Status, g.run = ApprovalApproved, nil
+	}
+	g.mu.Unlock()
+
+	if err := g.recordExpiry(expired); err != nil {
+		return false, err
+	}
+	rec := admin.AuditRecord{Principal: principal, Action: ActionApprove, Reason: req.Reason, Detail: strings.TrimSpace("request=" + id + " " + detail), CorrelationID: correlation.FromContext(ctx)}
+	if err := g.record(rec); err != nil {
+		return false, err
+	}
+	if !approved {
+		return false, nil
+	}
+	// The actions outlive the approving call; they are bounded by the executor's per-action timeout.
+	return true, run(context.WithoutCancel(ctx))
+}
+
+// Pending returns the open request, if any. A request past its deadline is not returned; it is
+// expired, and audited, by the next request or approval.
+func (g *ApprovalGate) Pending() (ApprovalRequest, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.current == nil || g.current.Status != ApprovalPending || g.now().After(g.current.ExpiresAt) {
+		return ApprovalRequest{}, false
+	}
+	req := *g.current
+	req.Approvers = append([]string(nil), req.Approvers...)
+	return req, true
+}
+
+// expireLocked expires the open request and installed tokens past their deadlines. It returns the
+// audit record of an expired request, which the caller writes with recordExpiry once it has
+// released g.mu. Callers must hold g.mu.
+func (g *ApprovalGate) expireLocked() *admin.AuditRecord {
+	now := g.now()
+	var expired *admin.AuditRecord
+	if req := g.current; req != nil && req.Status == ApprovalPending && now.After(req.ExpiresAt) {
+		req.Status, g.run = ApprovalExpired, nil
+		expired = &admin.AuditRecord{Principal: admin.SystemPrincipal, Action: ActionApprovalRequested, Outcome: ApprovalExpired, Reason: req.Reason, Detail: "request=" + req.ID, CorrelationID: req.CorrelationID}
+	}
+	live := g.standing[:0]
+	for _, c := range g.standing {
+		if now.Before(c.ExpiresAt) {
+			live = append(live, c)
+		}
+	}
+	g.standing = live
+	return expired
+}
+
+// recordExpiry audits a request expired by expireLocked, if any.
+func (g *ApprovalGate) recordExpiry(rec *admin.AuditRecord) error {
+	if rec == nil {
+		return nil
+	}
+	return g.record(*rec)
+}
+
+// standingLocked returns an installed token covering request id. Callers must hold g.mu.
+func (g *ApprovalGate) standingLocked(id string) *TokenClaims {
+	for i, c := range g.standing {
+		if c.RequestID == "" || c.RequestID == id {
+			return &g.standing[i]
+		}
+	}
+	return nil
+}
+
+func (g *ApprovalGate) record(rec admin.AuditRecord) error {
+	if g.audit == nil {
+		return nil
+	}
+	return g.audit.Record(rec)
+}
+
+// verify checks a token's signature against the trusted keys, its expiry, and that its approvers
+// meet the quorum.
+func (g *ApprovalGate) verify(token string) (TokenClaims, error) {
+	var claims TokenClaims
+	payloadPart, sigPart, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+	payload, err1 := base64.RawURLEncoding.DecodeString(payloadPart)
+	sig, err2 := base64.RawURLEncoding.DecodeString(sigPart)
+	if err := errors.Join(err1, err2); err != nil {
+		return claims, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	key, ok := g.cfg.TrustedKeys[claims.KeyID]
+	if !ok || !ed25519.Verify(key, payload, sig) {
+		return claims, fmt.Errorf("%w: signature not valid for key '%s'", ErrInvalidToken, claims.KeyID)
+	}
+	if !g.now().Before(claims.ExpiresAt) {
+		return claims, fmt.Errorf("%w: expired at %s", ErrInvalidToken, claims.ExpiresAt.Format(time.RFC3339))
+	}
+	distinct := make(map[string]bool, len(claims.Approvers))
+	for _, a := range claims.Approvers {
+		distinct[a] = true
+	}
+	if len(distinct) < g.cfg.Quorum {
+		return claims, fmt.Errorf("%w: %d approvers, quorum is %d", ErrInvalidToken, len(distinct), g.cfg.Quorum)
+	}
+	return claims, nil
+}
+
+// SignApprovalToken issues a pre-signed approval token for claims with key.
+func SignApprovalToken(key ed25519.PrivateKey, claims TokenClaims) (string, error) {
+	sort.Strings(claims.Approvers)
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	sig := ed25519.Sign(key, payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// approveRequest is the body accepted by the approve endpoint. Token, if set, approves on behalf
+// of the approvers it names instead of the caller; the caller is still audited as submitting it.
+type approveRequest struct {
+	ID    string `json:"id"`
+	Token string `json:"token,omitempty"`
+}
+
+// Register adds the approval endpoints to the admin API: viewers may list the pending request and
+// operators may approve it.
+func (g *ApprovalGate) Register(s *admin.Server) {
+	s.Handle("/admin/v1/sih/approvals", "read_sih_approvals", authz.RoleViewer, g.handlePending)
+	s.Handle("/admin/v1/sih/approve", ActionApprove, authz.RoleOperator, g.handleApprove)
+}
+
+func (g *ApprovalGate) handlePending(w http.ResponseWriter, r *http.Request, principal string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	pending := []ApprovalRequest{}
+	if req, ok := g.Pending(); ok {
+		pending = append(pending, req)
+	}
+	writeJSON(w, http.StatusOK, pending)
+}
+
+func (g *ApprovalGate) handleApprove(w http.ResponseWriter, r *http.Request, principal string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	var req approveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("a JSON body with the request id is required"))
+		return
+	}
+
+	var (
+		executed bool
+		err      error
+	)
+	if req.Token != "" {
+		executed, err = g.ApproveWithToken(r.Context(), req.ID, principal, req.Token)
+	} else {
+		executed, err = g.Approve(r.Context(), req.ID, principal)
+	}
+	switch {
+	case errors.Is(err, ErrApprovalNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrDuplicateApprove):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrInvalidToken):
+		writeError(w, http.StatusForbidden, err)
+	case err != nil && !executed:
+		writeError(w, http.StatusInternalServerError, err)
+	case err != nil:
+		// Approved, but some actions failed; they are audited individually.
+		writeJSON(w, http.StatusOK, map[string]interface{}{"executed": true, "error": err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"executed": executed})
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,165 @@
+package sih
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"internal/admin"
+	"services/telemetry"
+)
+
+func TestApprovalGate(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := func(approvers []string, expires time.Time) string {
+		tok, err := SignApprovalToken(priv, TokenClaims{KeyID: "board", Approvers: approvers, ExpiresAt: expires})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tok
+	}
+	td := telemetry.TelemetryData{ViolationCauses: []string{"integrity"}}
+
+	tests := []struct {
+		name    string
+		approve func(ctx context.Context, g *ApprovalGate, id string) error
+		advance time.Duration // Clock advance before approving
+		wantRan int
+		wantErr error
+	}{
+		{name: "Quorum Of Distinct Principals", approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			if _, err := g.Approve(ctx, id, "alice"); err != nil {
+				return err
+			}
+			_, err := g.Approve(ctx, id, "bob")
+			return err
+		}, wantRan: 1},
+		{name: "Same Principal Twice", approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			g.Approve(ctx, id, "alice")
+			_, err := g.Approve(ctx, id, "alice")
+			return err
+		}, wantErr: ErrDuplicateApprove},
+		{name: "Expired Request", advance: 20 * time.Minute, approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			_, err := g.Approve(ctx, id, "alice")
+			return err
+		}, wantErr: ErrApprovalNotFound},
+		{name: "Signed Token", approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			_, err := g.ApproveWithToken(ctx, id, "carol", token([]string{"alice", "bob"}, now.Add(time.Hour)))
+			return err
+		}, wantRan: 1},
+		{name: "Token Below Quorum", approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			_, err := g.ApproveWithToken(ctx, id, "carol", token([]string{"alice", "alice"}, now.Add(time.Hour)))
+			return err
+		}, wantErr: ErrInvalidToken},
+		{name: "Expired Token", approve: func(ctx context.Context, g *ApprovalGate, id string) error {
+			_, err := g.ApproveWithToken(ctx, id, "carol", token([]string{"alice", "bob"}, now.Add(-time.Minute)))
+			return err
+		}, wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := now
+			gate := NewApprovalGate(ApprovalConfig{TrustedKeys: map[string]ed25519.PublicKey{"board": pub}}, nil)
+			gate.now = func() time.Time { return clock }
+			action := &fakeAction{name: "cordon"}
+			exec := NewExecutor(ExecutorConfig{Actions: []Action{action}, Approval: gate}, nil)
+			ctx := context.Background()
+
+			if err := exec.Quarantine(ctx, td); !errors.Is(err, ErrApprovalPending) {
+				t.Fatalf("Quarantine() error = %v, want ErrApprovalPending", err)
+			}
+			if err := exec.Handler().Escalate(ctx, td); err != nil {
+				t.Fatalf("repeated escalation error = %v, want nil", err)
+			}
+			req, ok := gate.Pending()
+			if !ok || action.ran != 0 {
+				t.Fatalf("Pending() = %v, ran %d; want a held request", ok, action.ran)
+			}
+
+			clock = clock.Add(tt.advance)
+			err := tt.approve(ctx, gate, req.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("approve error = %v, want %v", err, tt.wantErr)
+			}
+			if action.ran != tt.wantRan {
+				t.Errorf("action ran %d times, want %d", action.ran, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestApprovalGate_StandingToken(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	gate := NewApprovalGate(ApprovalConfig{TrustedKeys: map[string]ed25519.PublicKey{"board": pub}}, nil)
+	tok, _ := SignApprovalToken(priv, TokenClaims{KeyID: "board", Approvers: []string{"alice", "bob"}, ExpiresAt: time.Now().Add(time.Hour)})
+	if err := gate.InstallToken(tok); err != nil {
+		t.Fatalf("InstallToken() error = %v", err)
+	}
+	action := &fakeAction{name: "cordon"}
+	exec := NewExecutor(ExecutorConfig{Actions: []Action{action}, Approval: gate}, nil)
+	if err := exec.Quarantine(context.Background(), telemetry.TelemetryData{}); err != nil {
+		t.Fatalf("Quarantine() error = %v, want immediate execution", err)
+	}
+	if action.ran != 1 {
+		t.Errorf("action ran %d times, want 1", action.ran)
+	}
+}
+
+func TestApprovalGate_Audit(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	clock := time.Now()
+	audit := admin.NewAuditLog(nil)
+	gate := NewApprovalGate(ApprovalConfig{TrustedKeys: map[string]ed25519.PublicKey{"board": pub}}, audit)
+	gate.now = func() time.Time { return clock }
+	ctx := context.Background()
+	noop := func(context.Context) error { return nil }
+
+	// An expired request is audited when the next request replaces it.
+	gate.request(ctx, "first", noop)
+	clock = clock.Add(time.Hour)
+	if err := gate.request(ctx, "second", noop); !errors.Is(err, ErrApprovalPending) {
+		t.Fatalf("request() error = %v, want ErrApprovalPending", err)
+	}
+	req, _ := gate.Pending()
+	tok, _ := SignApprovalToken(priv, TokenClaims{KeyID: "board", Approvers: []string{"alice", "bob"}, ExpiresAt: clock.Add(time.Hour)})
+	if executed, err := gate.ApproveWithToken(ctx, req.ID, "carol", tok); err != nil || !executed {
+		t.Fatalf("ApproveWithToken() = %v, %v; want executed", executed, err)
+	}
+
+	records := audit.List()
+	if len(records) != 4 {
+		t.Fatalf("audit = %+v, want two requests, an expiry and an approval", records)
+	}
+	if expiry := records[1]; expiry.Outcome != ApprovalExpired || expiry.Reason != "first" {
+		t.Errorf("records[1] = %+v, want the first request expired", expiry)
+	}
+	if approval := records[3]; approval.Action != ActionApprove || approval.Principal != "carol" || approval.Detail != "request="+req.ID+" token=board approvers=alice,bob" {
+		t.Errorf("records[3] = %+v, want the approval by carol with the token's approvers", approval)
+	}
+}
+
+// failingWriter rejects every write, like a full audit volume.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("no space left on device")
+}
+
+func TestApprovalGate_ExpiryAuditFailure(t *testing.T) {
+	clock := time.Now()
+	gate := NewApprovalGate(ApprovalConfig{}, nil)
+	gate.now = func() time.Time { return clock }
+	gate.request(context.Background(), "first", func(context.Context) error { return nil })
+	req, _ := gate.Pending()
+
+	gate.audit = admin.NewAuditLog(failingWriter{})
+	clock = clock.Add(time.Hour)
+	_, err := gate.Approve(context.Background(), req.ID, "alice")
+	if !errors.Is(err, ErrApprovalNotFound) || !strings.Contains(err.Error(), "no space left") {
+		t.Errorf("Approve() of an expired request error = %v, want ErrApprovalNotFound and the audit failure", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package sih executes Sovereign Integrity Hold (SIH) quarantine actions when telemetry escalates
+// to the SIH level: isolating the node from new work while integrity is investigated.
+package sih
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"internal/admin"
+	"pkg/correlation"
+	"services/telemetry"
+)
+
+const defaultActionTimeout = 30 * time.Second
+
+// Audit outcomes for quarantine actions. A successful action has an empty outcome, as for
+// other audited actions.
+const (
+	OutcomeDryRun = "dry_run"
+	OutcomeFailed = "failed"
+)
+
+// AuditActionPrefix prefixes the audit action of every quarantine step, e.g. "sih:cordon_node".
+const AuditActionPrefix = "sih:"
+
+// Logger defines the interface required for internal component logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// Action is one quarantine step.
+type Action interface {
+	// Name identifies the action in audit records and logs, e.g. "cordon_node".
+	Name() string
+	// Describe states what Execute would do, e.g. "cordon node worker-3"; it is audited in dry-run mode.
+	Describe() string
+	Execute(ctx context.Context) error
+}
+
+// ExecutorConfig configures the quarantine executor.
+type ExecutorConfig struct {
+	Actions []Action
+	// DryRun audits and logs each action without executing it.
+	DryRun bool
+	// Timeout bounds each action (default 30s).
+	Timeout time.Duration
+	// Audit, if set, receives a record for every action taken or skipped.
+	Audit *admin.AuditLog
+	// Approval, if set, holds the actions until operators approve them. Dry runs are not held.
+	Approval *ApprovalGate
+}
+
+// Executor runs the configured quarantine actions in order.
+type Executor struct {
+	cfg ExecutorConfig
+	log Logger
+}
+
+// NewExecutor creates an executor. Zero-valued configuration applies defaults.
+func NewExecutor(cfg ExecutorConfig, logger Logger) *Executor {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultActionTimeout
+	}
+	return &Executor{cfg: cfg, log: logger}
+}
+
+// Quarantine runs every action for the escalated snapshot td. A failed action does not stop the
+// remaining ones, since each independently reduces exposure; all failures are returned joined.
+// An audit record that cannot be persisted counts as a failure of its action.
+//
+// With an approval gate configured, Quarantine opens an approval request instead and returns
+// ErrApprovalPending; the actions run when the request is approved.
+func (e *Executor) Quarantine(ctx context.Context, td telemetry.TelemetryData) error {
+	reason := "SIH escalation: " + strings.Join(td.ViolationCauses, ",")
+	if e.cfg.Approval != nil && !e.cfg.DryRun {
+		err := e.cfg.Approval.request(ctx, reason, func(ctx context.Context) error { return e.runAll(ctx, reason) })
+		if errors.Is(err, ErrApprovalPending) && e.log != nil {
+			e.log.Warnf("SIH actions held for approval: %s", reason)
+		}
+		return err
+	}
+	return e.runAll(ctx, reason)
+}
+
+func (e *Executor) runAll(ctx context.Context, reason string) error {
+	var errs []error
+	for _, action := range e.cfg.Actions {
+		if err := e.run(ctx, action, reason); err != nil {
+			errs = append(errs, fmt.Errorf("sih action %s: %w", action.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Executor) run(ctx context.Context, action Action, reason string) error {
+	rec := admin.AuditRecord{
+		Principal:     admin.SystemPrincipal,
+		Action:        AuditActionPrefix + action.Name(),
+		Reason:        reason,
+		Detail:        action.Describe(),
+		CorrelationID: correlation.FromContext(ctx),
+	}
+
+	if e.cfg.DryRun {
+		rec.Outcome = OutcomeDryRun
+		if e.log != nil {
+			e.log.Infof("SIH dry run: would %s", action.Describe())
+		}
+		return e.audit(rec)
+	}
+
+	actionCtx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	err := action.Execute(actionCtx)
+	cancel()
+	if err != nil {
+		rec.Outcome = OutcomeFailed
+		rec.Detail += ": " + err.Error()
+		if e.log != nil {
+			e.log.Errorf("SIH action %s failed: %v", action.Name(), err)
+		}
+	} else if e.log != nil {
+		e.log.Warnf("SIH action executed: %s", action.Describe())
+	}
+	return errors.Join(err, e.audit(rec))
+}
+
+func (e *Executor) audit(rec admin.AuditRecord) error {
+	if e.cfg.Audit == nil {
+		return nil
+	}
+	return e.cfg.Audit.Record(rec)
+}
+
+// Handler adapts the executor to telemetry.EscalationHandler, for registration on the severity
+// tier or violation causes that mean SIH, e.g. router.Handle(telemetry.SeverityCritical, exec.Handler()).
+// Actions held for approval are not reported as an escalation failure.
+func (e *Executor) Handler() telemetry.EscalationHandler {
+	return telemetry.EscalationHandlerFunc(func(ctx context.Context, td telemetry.TelemetryData) error {
+		err := e.Quarantine(ctx, td)
+		if errors.Is(err, ErrApprovalPending) {
+			return nil
+		}
+		return err
+	})
+}
@@ -0,0 +1,84 @@
+package sih
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"internal/admin"
+	"services/telemetry"
+)
+
+type fakeAction struct {
+	name string
+	err  error
+	ran  int
+}
+
+func (a *fakeAction) Name() string                      { return a.name }
+func (a *fakeAction) Describe() string                  { return "do " + a.name }
+func (a *fakeAction) Execute(ctx context.Context) error { a.ran++; return a.err }
+
+func TestExecutorQuarantine(t *testing.T) {
+	td := telemetry.TelemetryData{ViolationCauses: []string{"integrity"}}
+	failing := &fakeAction{name: "failing", err: errors.New("boom")}
+	ok := &fakeAction{name: "ok"}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		wantErr      bool
+		wantRuns     int
+		wantOutcomes []string
+	}{
+		{name: "Executes All Actions", wantErr: true, wantRuns: 1, wantOutcomes: []string{OutcomeFailed, ""}},
+		{name: "Dry Run", dryRun: true, wantRuns: 0, wantOutcomes: []string{OutcomeDryRun, OutcomeDryRun}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing.ran, ok.ran = 0, 0
+			audit := admin.NewAuditLog(nil)
+			exec := NewExecutor(ExecutorConfig{Actions: []Action{failing, ok}, DryRun: tt.dryRun, Audit: audit}, nil)
+
+			err := exec.Handler().Escalate(context.Background(), td)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Quarantine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if failing.ran != tt.wantRuns || ok.ran != tt.wantRuns {
+				t.Errorf("actions ran %d/%d times, want %d", failing.ran, ok.ran, tt.wantRuns)
+			}
+			records := audit.List()
+			if len(records) != len(tt.wantOutcomes) {
+				t.Fatalf("got %d audit records, want %d", len(records), len(tt.wantOutcomes))
+			}
+			for i, rec := range records {
+				if rec.Outcome != tt.wantOutcomes[i] || rec.Principal != admin.SystemPrincipal || rec.Reason != "SIH escalation: integrity" {
+					t.Errorf("record %d = %+v, want outcome %q", i, rec, tt.wantOutcomes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCordonNode(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody map[string]map[string]bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.Method+" "+r.URL.Path, r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &gotBody)
+	}))
+	defer srv.Close()
+
+	action := &CordonNode{Client: &KubeClient{BaseURL: srv.URL, Token: "t0ken"}, Node: "worker-3"}
+	if err := action.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotPath != "PATCH /api/v1/nodes/worker-3" || gotAuth != "Bearer t0ken" || !gotBody["spec"]["unschedulable"] {
+		t.Errorf("request = %s auth=%q body=%v", gotPath, gotAuth, gotBody)
+	}
+}
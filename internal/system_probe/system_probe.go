@@ -6,31 +6,43 @@ import (
 	"fmt"
 	"time"
 
+	"internal/crot"
 	"telemetry_service/telemetry" // Assuming relative path for import based on structure
 )
 
 // SystemProbe provides real metric collection by interfacing with OS/Kube API and CRoT hooks.
 type SystemProbe struct {
 	// client connections, e.g., to Kernel Metrics endpoint or CRoT device handles
+
+	// integrity derives IntegrityHashChainStatus locally when no hardware CRoT is available.
+	integrity crot.IntegrityProvider
 }
 
 // NewSystemProbe creates a new instance of the system metric collector.
-func NewSystemProbe() *SystemProbe {
+// If integrity is nil, the hash chain status is reported as SYNCED until a hardware CRoT hook is wired.
+func NewSystemProbe(integrity crot.IntegrityProvider) *SystemProbe {
 	// Initialize real connections and probes here
-	return &SystemProbe{}
+	return &SystemProbe{integrity: integrity}
 }
 
 // Collect gathers real-time metrics for the Sovereign Telemetry Service.
 // This implementation must replace simulation for operational deployment.
 func (p *SystemProbe) Collect(ctx context.Context) (telemetry.TelemetryData, error) {
 	// 1. Fetch Pipeline Latency (e.g., check timestamp of last successful transaction log write)
-	latency := 0.5 // TODO: Replace with actual measurement
+	latency := 500 * time.Millisecond // TODO: Replace with actual measurement
 	
 	// 2. Fetch Resource Load (e.g., read /sys/fs/cgroup/cpu/cpu.stat or use runtime metrics)
 	load := 0.65 // TODO: Replace with actual measurement
 
 	// 3. Check CRoT Integrity Status (Crucial step)
 	integrityStatus := "SYNCED" // TODO: Implement call to hardware/firmware CRoT endpoint
+	if p.integrity != nil {
+		status, err := p.integrity.Status(ctx)
+		if err != nil {
+			return telemetry.TelemetryData{}, fmt.Errorf("CRoT integrity check failed: %w", err)
+		}
+		integrityStatus = string(status)
+	}
 
 	if ctx.Err() != nil {
 		return telemetry.TelemetryData{}, ctx.Err()
@@ -42,7 +54,7 @@ func (p *SystemProbe) Collect(ctx context.Context) (telemetry.TelemetryData, err
 	
 	return telemetry.TelemetryData{
 		Timestamp: time.Now(),
-		PipelineLatency_S9: latency,
+		PipelineLatencyS9: latency,
 		ResourceLoad_Pct: load,
 		IntegrityHashChainStatus: integrityStatus,
 		// GATMBreachCount and IsGATMViolating will be populated by the main STS service.
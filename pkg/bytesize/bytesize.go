@@ -0,0 +1,43 @@
+// Package bytesize parses human-readable byte counts such as "8GiB", "512 MB" or "1048576", as
+// used by memory budgets and governance constraints.
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// units maps the accepted suffixes, in upper case, to their multiplier. Binary (KiB) and decimal
+// (KB) units are distinct; longer suffixes come first so "KIB" is not read as "B".
+var units = []struct {
+	suffix string
+	factor float64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+	{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// Parse parses a byte count with an optional, case-insensitive unit suffix: B, KB, MB, GB, TB or
+// KiB, MiB, GiB, TiB. A bare number is a count of bytes; fractions such as "1.5GiB" are allowed
+// and rounded down to a whole byte.
+func Parse(s string) (uint64, error) {
+	num, factor := strings.TrimSpace(s), float64(1)
+	upper := strings.ToUpper(num)
+	for _, u := range units {
+		if strings.HasSuffix(upper, u.suffix) {
+			num, factor = strings.TrimSpace(num[:len(num)-len(u.suffix)]), u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("size '%s' is not a byte count such as \"8GiB\"", s)
+	}
+	if n*factor >= math.MaxUint64 {
+		return 0, fmt.Errorf("size '%s' is out of range", s)
+	}
+	return uint64(n * factor), nil
+}
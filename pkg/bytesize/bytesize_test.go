@@ -0,0 +1,35 @@
+package bytesize
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{in: "1048576", want: 1 << 20},
+		{in: "0", want: 0},
+		{in: "512B", want: 512},
+		{in: "16MB", want: 16e6},
+		{in: "16mb", want: 16e6},
+		{in: "512KiB", want: 512 << 10},
+		{in: "512kib", want: 512 << 10},
+		{in: " 8 GiB ", want: 8 << 30},
+		{in: "1.5GiB", want: 3 << 29},
+		{in: "2TB", want: 2e12},
+		{in: "1TiB", want: 1 << 40},
+		{in: "", wantErr: true},
+		{in: "GiB", wantErr: true},
+		{in: "-1MB", wantErr: true},
+		{in: "8 gigabytes", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "1e30TB", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Parse(%q) = %d, %v; want %d, error %t", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
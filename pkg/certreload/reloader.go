@@ -0,0 +1,159 @@
+// Package certreload serves TLS certificates that are reloaded from disk when the files change, so
+// rotated certificates take effect without restarting HTTP or gRPC servers.
+package certreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultPollInterval = 30 * time.Second
+
+// Logger defines the interface required for reload logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// Options configures the certificate files and client verification.
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile, if set, enables client certificate verification against the bundle.
+	ClientCAFile string
+	// RequireClientCert rejects clients without a verified certificate; otherwise one is verified if presented.
+	RequireClientCert bool
+	// PollInterval between file change checks (default 30s).
+	PollInterval time.Duration
+}
+
+// Reloader holds the current certificate and client CA pool.
+type Reloader struct {
+	opts Options
+	log  Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes [3]time.Time
+}
+
+// New loads the configured files. It fails if the initial certificate cannot be loaded.
+func New(opts Options, logger Logger) (*Reloader, error) {
+	if opts.CertFile == "" || opts.KeyFile == "" {
+		return nil, errors.New("certreload: CertFile and KeyFile are required")
+	}
+	if opts.RequireClientCert && opts.ClientCAFile == "" {
+		return nil, errors.New("certreload: RequireClientCert needs a ClientCAFile")
+	}
+	if opts.PollInterval == 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	r := &Reloader{opts: opts, log: logger}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the files if any modification time changed. A failed reload keeps the
+// previous certificate in service, so a half-written rotation never breaks the listener.
+func (r *Reloader) Reload() (changed bool, err error) {
+	var mods [3]time.Time
+	for i, path := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("certreload: %w", err)
+		}
+		mods[i] = info.ModTime()
+	}
+
+	r.mu.RLock()
+	unchanged := r.cert != nil && mods == r.modTimes
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("certreload: failed to load key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("certreload: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("certreload: no certificates found in %s", r.opts.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCA, r.modTimes = &cert, pool, mods
+	r.mu.Unlock()
+	return true, nil
+}
+
+// Run polls for changes until ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := r.Reload()
+		if r.log == nil {
+			continue
+		}
+		switch {
+		case err != nil:
+			r.log.Errorf("TLS certificate reload failed, keeping previous certificate: %v", err)
+		case changed:
+			r.log.Infof("TLS certificate reloaded from %s", r.opts.CertFile)
+		}
+	}
+}
+
+// Certificate returns the certificate currently in service.
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// TLSConfig returns a server configuration that picks up the current certificate and client CA
+// pool on every handshake. Use it with http.Server.TLSConfig or grpc credentials.NewTLS.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			cfg := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{*r.cert}}
+			if r.clientCA != nil {
+				cfg.ClientCAs = r.clientCA
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				if r.opts.RequireClientCert {
+					cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
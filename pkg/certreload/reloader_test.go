@@ -0,0 +1,85 @@
+package certreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSelfSigned(t *testing.T, dir, cn string, mod time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReloader_PicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Minute)
+	writeSelfSigned(t, dir, "first", start)
+
+	r, err := New(Options{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if changed, err := r.Reload(); changed || err != nil {
+		t.Fatalf("Reload without changes = %v, %v; want false, nil", changed, err)
+	}
+
+	writeSelfSigned(t, dir, "second", start.Add(time.Second))
+	if changed, err := r.Reload(); !changed || err != nil {
+		t.Fatalf("Reload after rotation = %v, %v; want true, nil", changed, err)
+	}
+	leaf, err := x509.ParseCertificate(r.Certificate().Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Subject.CommonName != "second" {
+		t.Errorf("serving %q, want rotated certificate", leaf.Subject.CommonName)
+	}
+
+	// A broken rotation keeps the previous certificate in service.
+	os.WriteFile(filepath.Join(dir, "tls.key"), []byte("garbage"), 0o600)
+	if _, err := r.Reload(); err == nil {
+		t.Fatal("expected error for corrupt key")
+	}
+	if r.Certificate() == nil {
+		t.Fatal("certificate dropped after failed reload")
+	}
+}
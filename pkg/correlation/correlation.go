@@ -0,0 +1,107 @@
+// Package correlation carries a request-scoped correlation ID through context.Context, so a single
+// admission denial or GATM escalation can be traced across API handlers, evaluators, sink writes,
+// audit records, and log lines.
+package correlation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header is the HTTP header (and, lower-cased, the gRPC metadata key) carrying the ID.
+const Header = "X-Correlation-ID"
+
+// Generator produces new IDs. Replace it to integrate with an external tracing scheme.
+type Generator func() string
+
+// RandomID is the default generator: 16 random bytes, hex-encoded.
+func RandomID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type idKey struct{}
+
+// WithID returns a context carrying id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns the ID carried by ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// Ensure returns ctx unchanged if it already carries an ID, or a derived context with a new one
+// from gen (RandomID if nil).
+func Ensure(ctx context.Context, gen Generator) context.Context {
+	if FromContext(ctx) != "" {
+		return ctx
+	}
+	if gen == nil {
+		gen = RandomID
+	}
+	return WithID(ctx, gen())
+}
+
+// Middleware adopts the caller's X-Correlation-ID, or assigns a new one, and echoes it in the
+// response so clients can quote it when reporting a problem.
+func Middleware(gen Generator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if id := r.Header.Get(Header); id != "" && len(id) <= 128 {
+			ctx = WithID(ctx, id)
+		}
+		ctx = Ensure(ctx, gen)
+		w.Header().Set(Header, FromContext(ctx))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Logger defines the interface required for correlated logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// FieldLogger is implemented by structured loggers that record the correlation ID as a field of
+// their own rather than in the message.
+type FieldLogger interface {
+	Logger
+	WithCorrelationID(id string) Logger
+}
+
+// WithLogger returns a logger that prefixes every line with the ID carried by ctx, or for a
+// FieldLogger, records it as a field. Without an ID, base is returned unchanged.
+func WithLogger(ctx context.Context, base Logger) Logger {
+	id := FromContext(ctx)
+	if id == "" || base == nil {
+		return base
+	}
+	if fl, ok := base.(FieldLogger); ok {
+		return fl.WithCorrelationID(id)
+	}
+	return &prefixLogger{base: base, prefix: "[correlation_id=" + id + "] "}
+}
+
+type prefixLogger struct {
+	base   Logger
+	prefix string
+}
+
+func (l *prefixLogger) Infof(format string, args ...interface{}) {
+	l.base.Infof(l.prefix+format, args...)
+}
+
+func (l *prefixLogger) Errorf(format string, args ...interface{}) {
+	l.base.Errorf(l.prefix+format, args...)
+}
+
+func (l *prefixLogger) Warnf(format string, args ...interface{}) {
+	l.base.Warnf(l.prefix+format, args...)
+}
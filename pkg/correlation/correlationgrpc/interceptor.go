@@ -0,0 +1,40 @@
+// Package correlationgrpc propagates correlation IDs over gRPC metadata. It is separate from
+// package correlation so that users of the HTTP and context helpers do not depend on gRPC.
+package correlationgrpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"pkg/correlation"
+)
+
+// UnaryServerInterceptor adopts the caller's correlation ID from metadata, or assigns a new one,
+// and returns it in the response header.
+func UnaryServerInterceptor(gen correlation.Generator) grpc.UnaryServerInterceptor {
+	key := strings.ToLower(correlation.Header)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(key); len(ids) > 0 && ids[0] != "" && len(ids[0]) <= 128 {
+				ctx = correlation.WithID(ctx, ids[0])
+			}
+		}
+		ctx = correlation.Ensure(ctx, gen)
+		grpc.SetHeader(ctx, metadata.Pairs(key, correlation.FromContext(ctx)))
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor forwards the ID carried by the outgoing call's context.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	key := strings.ToLower(correlation.Header)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id := correlation.FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, key, id)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,259 @@
+// Package crashdump writes a crash bundle when the process dies of a panic or SIGABRT: the stack
+// traces plus whatever state the registered sections collect (e.g. the latest telemetry snapshot,
+// breach state, governance state version and recent sink records), for postmortem analysis.
+//
+// A bundle is a directory holding manifest.json, stacks.txt and one JSON file per section. It is
+// assembled under a temporary name and renamed into place once complete, so a bundle that exists
+// is whole even if the process is killed while writing it. Bundles are redacted and size-limited,
+// and only the most recent MaxBundles are kept.
+package crashdump
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pkg/recovery"
+	"pkg/system"
+)
+
+// Defaults applied to zero-valued Dumper fields.
+const (
+	DefaultMaxBytes       = 4 << 20
+	DefaultMaxBundles     = 5
+	DefaultSectionTimeout = 2 * time.Second
+)
+
+// bundlePrefix starts the name of every bundle directory, so retention never touches other files.
+const bundlePrefix = "crash-"
+
+// DefaultRedactionRules mask personal data in addition to the credentials masked in logs
+// (system.DefaultRedactionRules): email addresses and IPv4 addresses.
+var DefaultRedactionRules = append(append([]system.RedactionRule(nil), system.DefaultRedactionRules...),
+	system.RedactionRule{Name: "email", Pattern: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`},
+	system.RedactionRule{Name: "ipv4", Pattern: `\b(?:\d{1,3}\.){3}\d{1,3}\b`},
+)
+
+// Section collects one part of the bundle. Collect must return a JSON-encodable value; it runs
+// while the process is failing, so it should only read in-memory state.
+type Section struct {
+	Name    string // File name stem, e.g. "telemetry"
+	Collect func(ctx context.Context) (interface{}, error)
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Reason    string            `json:"reason"`
+	Time      time.Time         `json:"time"`
+	PID       int               `json:"pid"`
+	GoVersion string            `json:"go_version"`
+	Sections  []string          `json:"sections"`            // Sections written in full
+	Truncated []string          `json:"truncated,omitempty"` // Sections cut at the size limit
+	Failed    map[string]string `json:"failed,omitempty"`    // Section errors, timeouts and omissions
+}
+
+// Dumper writes crash bundles to Dir. Sections are added with Register. The zero value, given a
+// Dir, writes bundles holding the stack traces only.
+type Dumper struct {
+	Dir string
+	// MaxBytes bounds each bundle (default 4 MiB). Stack traces are written first; a section that
+	// does not fit in the remaining budget is truncated, and later ones are omitted.
+	MaxBytes int64
+	// MaxBundles bounds the bundles kept in Dir (default 5); older ones are removed.
+	MaxBundles int
+	// SectionTimeout bounds each section (default 2s). A crashing process may hold the locks a
+	// section needs, so a section that does not return in time is abandoned.
+	SectionTimeout time.Duration
+	// Redactor masks personal data and credentials in every file of the bundle. Nil applies
+	// DefaultRedactionRules.
+	Redactor *system.Redactor
+
+	mu       sync.Mutex
+	sections []Section
+	once     sync.Once
+	fallback *system.Redactor
+}
+
+// Register adds a section to future bundles.
+func (d *Dumper) Register(s Section) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sections = append(d.sections, s)
+}
+
+// Recover writes a bundle if the calling goroutine is panicking, then resumes the panic. Defer it
+// at the top of long-running goroutines:
+//
+//	defer dumper.Recover()
+func (d *Dumper) Recover() {
+	if r := recover(); r != nil {
+		d.Write(fmt.Sprintf("panic: %v", r), debug.Stack())
+		panic(r)
+	}
+}
+
+// Write writes a bundle for reason with the given stack traces and returns its directory.
+func (d *Dumper) Write(reason string, stacks []byte) (string, error) {
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s%s-%d", bundlePrefix, now.Format("20060102T150405.000000000Z"), os.Getpid())
+	if err := os.MkdirAll(d.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("crashdump: %w", err)
+	}
+	tmp, err := os.MkdirTemp(d.Dir, "."+name+"-")
+	if err != nil {
+		return "", fmt.Errorf("crashdump: %w", err)
+	}
+
+	manifest := Manifest{Reason: d.redact(reason), Time: now, PID: os.Getpid(), GoVersion: runtime.Version()}
+	budget := d.MaxBytes
+	if budget <= 0 {
+		budget = DefaultMaxBytes
+	}
+	write := func(file string, data []byte) (bool, error) {
+		truncated := int64(len(data)) > budget
+		if truncated {
+			data = data[:budget]
+		}
+		budget -= int64(len(data))
+		return truncated, os.WriteFile(filepath.Join(tmp, file), data, 0o600)
+	}
+
+	if _, err := write("stacks.txt", []byte(d.redact(string(stacks)))); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("crashdump: %w", err)
+	}
+	d.mu.Lock()
+	sections := append([]Section(nil), d.sections...)
+	d.mu.Unlock()
+	for _, s := range sections {
+		if budget <= 0 {
+			manifest.fail(s.Name, "omitted: bundle size limit reached")
+			continue
+		}
+		data, err := d.collect(s)
+		if err != nil {
+			manifest.fail(s.Name, err.Error())
+			continue
+		}
+		truncated, err := write(s.Name+".json", []byte(d.redact(string(data))))
+		switch {
+		case err != nil:
+			manifest.fail(s.Name, err.Error())
+		case truncated:
+			manifest.Truncated = append(manifest.Truncated, s.Name)
+		default:
+			manifest.Sections = append(manifest.Sections, s.Name)
+		}
+	}
+
+	// The manifest is written whatever the remaining budget, so the bundle stays readable.
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := os.WriteFile(filepath.Join(tmp, "manifest.json"), data, 0o600); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("crashdump: %w", err)
+	}
+	path := filepath.Join(d.Dir, name)
+	if err := os.Rename(tmp, path); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("crashdump: %w", err)
+	}
+	d.prune()
+	return path, nil
+}
+
+func (m *Manifest) fail(section, reason string) {
+	if m.Failed == nil {
+		m.Failed = map[string]string{}
+	}
+	m.Failed[section] = reason
+}
+
+// collect runs a section within SectionTimeout and encodes its value.
+func (d *Dumper) collect(s Section) ([]byte, error) {
+	timeout := d.SectionTimeout
+	if timeout <= 0 {
+		timeout = DefaultSectionTimeout
+	}
+	return Collect(s, timeout)
+}
+
+// Collect runs s and returns its value encoded as indented JSON. A section that panics fails, and
+// one that does not return within timeout is abandoned.
+func Collect(s Section, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var data []byte
+		err := recovery.Call("crash section "+s.Name, func() error {
+			v, err := s.Collect(ctx)
+			if err != nil {
+				return err
+			}
+			data, err = json.MarshalIndent(v, "", "  ")
+			return err
+		})
+		done <- result{data, err}
+	}()
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out after %v", timeout)
+	}
+}
+
+func (d *Dumper) redact(s string) string {
+	if d.Redactor != nil {
+		return d.Redactor.Redact(s)
+	}
+	d.once.Do(func() {
+		d.fallback = &system.Redactor{}
+		d.fallback.SetRules(DefaultRedactionRules)
+	})
+	return d.fallback.Redact(s)
+}
+
+// prune removes the oldest bundles beyond MaxBundles, and temporary directories left by writes
+// that were interrupted.
+func (d *Dumper) prune() {
+	keep := d.MaxBundles
+	if keep <= 0 {
+		keep = DefaultMaxBundles
+	}
+	entries, err := os.ReadDir(d.Dir)
+	if err != nil {
+		return
+	}
+	var bundles []string
+	for _, e := range entries {
+		switch name := e.Name(); {
+		case !e.IsDir():
+		case strings.HasPrefix(name, bundlePrefix):
+			bundles = append(bundles, name)
+		case strings.HasPrefix(name, "."+bundlePrefix):
+			if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > time.Hour {
+				os.RemoveAll(filepath.Join(d.Dir, name))
+			}
+		}
+	}
+	// Names embed the UTC time, so they sort chronologically.
+	sort.Strings(bundles)
+	for len(bundles) > keep {
+		os.RemoveAll(filepath.Join(d.Dir, bundles[0]))
+		bundles = bundles[1:]
+	}
+}
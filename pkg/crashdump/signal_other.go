@@ -0,0 +1,8 @@
+//go:build !unix
+
+package crashdump
+
+// HandleAbort is a no-op on platforms without SIGABRT.
+func (d *Dumper) HandleAbort() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,45 @@
+//go:build unix
+
+package crashdump
+
+import (
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+)
+
+// HandleAbort writes a bundle with every goroutine's stack when the process receives SIGABRT,
+// then re-raises the signal so the runtime still terminates the process with its own dump. The
+// returned function stops handling the signal.
+func (d *Dumper) HandleAbort() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGABRT)
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		d.Write("signal: "+syscall.SIGABRT.String(), allStacks())
+		signal.Reset(syscall.SIGABRT)
+		syscall.Kill(os.Getpid(), syscall.SIGABRT)
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// allStacks returns the stack traces of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= 64<<20 {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,257 @@
+// Package discovery resolves service endpoints through DNS SRV records or the Consul catalog and
+// fails over among the discovered instances, so nodes are not pinned to one policy or metrics server.
+//
+// Endpoints are written as URLs whose scheme selects the mechanism:
+//
+//	https://policy.example.internal/v1/policy          a fixed endpoint
+//	srv+https://_policy._tcp.example.internal/v1/policy DNS SRV lookup of _policy._tcp.example.internal
+//	consul+http://monitord/metrics                     passing instances of Consul service "monitord"
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultConsulAddr = "http://127.0.0.1:8500"
+	defaultTTL        = 30 * time.Second
+)
+
+// ErrNoEndpoints is returned when resolution yields no instances.
+var ErrNoEndpoints = errors.New("discovery: no endpoints resolved")
+
+// Resolver lists the current endpoints of a service as URLs, most preferred first.
+type Resolver interface {
+	Resolve(ctx context.Context) ([]string, error)
+}
+
+// Static is a fixed list of endpoints.
+type Static []string
+
+// Resolve implements Resolver.
+func (s Static) Resolve(ctx context.Context) ([]string, error) {
+	if len(s) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return append([]string(nil), s...), nil
+}
+
+// SRV resolves endpoints from a DNS SRV record. Targets are ordered by priority, and randomly by
+// weight within a priority, as RFC 2782 prescribes.
+type SRV struct {
+	Name   string // Full record name, e.g. "_policy._tcp.example.internal"
+	Scheme string // URL scheme of the endpoints, e.g. "https"
+	Path   string // Appended to every endpoint, e.g. "/v1/policy"
+	// Resolver performs the lookup (default net.DefaultResolver).
+	Resolver *net.Resolver
+}
+
+// Resolve implements Resolver.
+func (s *SRV) Resolve(ctx context.Context) ([]string, error) {
+	r := s.Resolver
+	if r == nil {
+		r = net.DefaultResolver
+	}
+	_, records, err := r.LookupSRV(ctx, "", "", s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: SRV lookup of %s failed: %w", s.Name, err)
+	}
+	endpoints := make([]string, 0, len(records))
+	for _, rec := range records {
+		host := strings.TrimSuffix(rec.Target, ".")
+		endpoints = append(endpoints, s.Scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(rec.Port)))+s.Path)
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return endpoints, nil
+}
+
+// Consul resolves endpoints from the instances of a service passing their Consul health checks.
+type Consul struct {
+	Address string // Consul agent URL (default $CONSUL_HTTP_ADDR, then http://127.0.0.1:8500)
+	Service string
+	Tag     string // Optional tag filter
+	Scheme  string // URL scheme of the endpoints
+	Path    string
+	HTTP    *http.Client
+}
+
+type consulEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+// Resolve implements Resolver.
+func (c *Consul) Resolve(ctx context.Context) ([]string, error) {
+	addr := c.Address
+	if addr == "" {
+		addr = consulAddr()
+	}
+	query := url.Values{"passing": {"true"}}
+	if c.Tag != "" {
+		query.Set("tag", c.Tag)
+	}
+	reqURL := strings.TrimRight(addr, "/") + "/v1/health/service/" + url.PathEscape(c.Service) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv("CONSUL_HTTP_TOKEN"); token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: consul query for %s failed: %w", c.Service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery: consul returned %d for service %s", resp.StatusCode, c.Service)
+	}
+	var entries []consulEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("discovery: invalid consul response: %w", err)
+	}
+	endpoints := make([]string, 0, len(entries))
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+		endpoints = append(endpoints, c.Scheme+"://"+net.JoinHostPort(host, strconv.Itoa(e.Service.Port))+c.Path)
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return endpoints, nil
+}
+
+func consulAddr() string {
+	addr := os.Getenv("CONSUL_HTTP_ADDR")
+	if addr == "" {
+		return defaultConsulAddr
+	}
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	return addr
+}
+
+// ParseURL returns the resolver for an endpoint URL; see the package documentation for the
+// supported schemes. Plain URLs resolve to themselves.
+func ParseURL(raw string) (Resolver, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: invalid endpoint URL %q: %w", raw, err)
+	}
+	mechanism, scheme, ok := strings.Cut(u.Scheme, "+")
+	if !ok {
+		return Static{raw}, nil
+	}
+	path := u.EscapedPath()
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	switch mechanism {
+	case "srv":
+		return &SRV{Name: u.Host, Scheme: scheme, Path: path}, nil
+	case "consul":
+		return &Consul{Service: u.Host, Scheme: scheme, Path: path}, nil
+	}
+	return nil, fmt.Errorf("discovery: unsupported discovery mechanism %q in %q", mechanism, raw)
+}
+
+// Failover calls endpoints of a resolved service, sticking with one that works and moving to the
+// next instance when it fails. Endpoints are re-resolved after TTL, or as soon as every known
+// instance has failed.
+type Failover struct {
+	resolver Resolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	endpoints  []string
+	resolvedAt time.Time
+	current    int // Index of the endpoint tried first
+}
+
+// NewFailover creates a failover caller. A zero ttl re-resolves every 30s.
+func NewFailover(r Resolver, ttl time.Duration) *Failover {
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	return &Failover{resolver: r, ttl: ttl, now: time.Now}
+}
+
+// Do calls fn with each endpoint in turn, starting from the last one that worked, until one
+// succeeds or ctx is done. If all fail, the endpoints are re-resolved once and tried again; the
+// errors of every attempt are returned joined.
+func (f *Failover) Do(ctx context.Context, fn func(endpoint string) error) error {
+	var errs []error
+	for round := 0; round < 2; round++ {
+		endpoints, start, err := f.snapshot(ctx, round > 0)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		for i := range endpoints {
+			idx := (start + i) % len(endpoints)
+			err := fn(endpoints[idx])
+			if err == nil {
+				f.mu.Lock()
+				if idx < len(f.endpoints) && f.endpoints[idx] == endpoints[idx] {
+					f.current = idx
+				}
+				f.mu.Unlock()
+				return nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", endpoints[idx], err))
+			if ctx.Err() != nil {
+				return errors.Join(errs...)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// snapshot returns the endpoints to try and where to start, resolving if they are stale or refresh is set.
+func (f *Failover) snapshot(ctx context.Context, refresh bool) ([]string, int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if refresh || len(f.endpoints) == 0 || f.now().Sub(f.resolvedAt) >= f.ttl {
+		endpoints, err := f.resolver.Resolve(ctx)
+		switch {
+		case err == nil:
+			// Stay on the working endpoint if it is still listed, whatever its new position.
+			next := 0
+			if len(f.endpoints) > 0 && !refresh {
+				next = max(slices.Index(endpoints, f.endpoints[f.current]), 0)
+			}
+			f.endpoints, f.resolvedAt, f.current = endpoints, f.now(), next
+		case len(f.endpoints) == 0:
+			return nil, 0, err
+		}
+		// On a failed re-resolution, keep using the last known endpoints.
+	}
+	return f.endpoints, f.current, nil
+}
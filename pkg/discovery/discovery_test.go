@@ -0,0 +1,86 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Resolver
+		wantErr bool
+	}{
+		{raw: "https://policy.example/v1", want: Static{"https://policy.example/v1"}},
+		{raw: "srv+https://_policy._tcp.example/v1?x=1", want: &SRV{Name: "_policy._tcp.example", Scheme: "https", Path: "/v1?x=1"}},
+		{raw: "consul+http://monitord/metrics", want: &Consul{Service: "monitord", Scheme: "http", Path: "/metrics"}},
+		{raw: "zk+http://x/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseURL(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseURL() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsulResolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health/service/monitord" || r.URL.Query().Get("passing") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"Node": map[string]string{"Address": "10.0.0.1"}, "Service": map[string]interface{}{"Address": "", "Port": 9090}},
+			{"Node": map[string]string{"Address": "10.0.0.2"}, "Service": map[string]interface{}{"Address": "10.1.0.2", "Port": 9091}},
+		})
+	}))
+	defer srv.Close()
+
+	got, err := (&Consul{Address: srv.URL, Service: "monitord", Scheme: "http", Path: "/metrics"}).Resolve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"http://10.0.0.1:9090/metrics", "http://10.1.0.2:9091/metrics"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() = %v, want %v", got, want)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	f := NewFailover(Static{"a", "b", "c"}, 0)
+	down := map[string]bool{"a": true}
+	var calls []string
+	call := func(endpoint string) error {
+		calls = append(calls, endpoint)
+		if down[endpoint] {
+			return errors.New("unavailable")
+		}
+		return nil
+	}
+
+	if err := f.Do(context.Background(), call); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Do(context.Background(), call); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "b"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v (fail over, then stick)", calls, want)
+	}
+
+	down = map[string]bool{"a": true, "b": true, "c": true}
+	if err := f.Do(context.Background(), call); err == nil {
+		t.Error("Do() with every endpoint down returned nil")
+	}
+}
@@ -0,0 +1,53 @@
+// Package duration provides a time.Duration that configuration documents may write either as a
+// Go duration string such as "30s" or "500ms", or as an integer count of nanoseconds like the other
+// duration fields of those documents.
+package duration
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that decodes from a duration string or integer nanoseconds, and
+// encodes as a duration string.
+type Duration time.Duration
+
+// Std returns d as a time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String implements fmt.Stringer.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText encodes d as a duration string, e.g. "1m30s".
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a duration string such as "30s".
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("duration '%s' is not a duration such as \"30s\"", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string or an integer count of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err == nil {
+		*d = Duration(nanos)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration %s is neither a duration string such as \"30s\" nor integer nanoseconds", data)
+	}
+	return d.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,49 @@
+package duration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: `"30s"`, want: 30 * time.Second},
+		{in: `"500ms"`, want: 500 * time.Millisecond},
+		{in: `"1h30m"`, want: 90 * time.Minute},
+		{in: `"-2s"`, want: -2 * time.Second},
+		{in: `2000000000`, want: 2 * time.Second},
+		{in: `0`, want: 0},
+		{in: `"30"`, wantErr: true},
+		{in: `"soon"`, wantErr: true},
+		{in: `1.5`, wantErr: true},
+		{in: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var got Duration
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Std() != tt.want {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		D Duration `json:"d"`
+	}{Duration(90 * time.Second)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"d":"1m30s"}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
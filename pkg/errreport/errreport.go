@@ -0,0 +1,144 @@
+// Package errreport surfaces systemic faults of the observability stack itself: panics in
+// pluggable components, collection cycles that keep failing, and governance policies that cannot
+// be loaded. Such faults are easy to miss in the very logs and metrics they break, so they are
+// also sent to an error tracker such as Sentry.
+package errreport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"pkg/correlation"
+	"pkg/recovery"
+)
+
+// Event kinds.
+const (
+	KindPanic      = "panic"
+	KindCollection = "collection"
+	KindPolicy     = "policy"
+)
+
+// Event is one reported fault.
+type Event struct {
+	Kind      string
+	Component string // e.g. "telemetry source *kube.Source", "governance"
+	Err       error
+	Stack     []byte            // Set for panics
+	Tags      map[string]string // Extra searchable context, e.g. consecutive_failures
+	Time      time.Time
+	// CorrelationID is the ID of the cycle or request that failed, if any.
+	CorrelationID string
+	// Occurrences counts the identical events this one stands for, when aggregated (see Aggregator).
+	Occurrences int
+}
+
+// ErrorReporter receives fault events. Report must not block for long and must not panic; a
+// reporter that cannot deliver an event drops it, since the caller is already handling a failure.
+type ErrorReporter interface {
+	Report(ctx context.Context, ev Event)
+}
+
+// New builds an event for err, filling the stack from a *recovery.PanicError, the correlation ID
+// from ctx, and the time.
+func New(ctx context.Context, kind, component string, err error) Event {
+	ev := Event{Kind: kind, Component: component, Err: err, Time: time.Now(), CorrelationID: correlation.FromContext(ctx), Occurrences: 1}
+	var pe *recovery.PanicError
+	if errors.As(err, &pe) {
+		ev.Kind, ev.Stack = KindPanic, pe.Stack
+	}
+	return ev
+}
+
+// Report sends an event for err to r, if r is set.
+func Report(ctx context.Context, r ErrorReporter, kind, component string, err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.Report(ctx, New(ctx, kind, component, err))
+}
+
+// ReportPanic reports a panic of the calling goroutine to r and then re-panics, so the process
+// still crashes but the crash is not lost with it. Defer it at the top of long-running goroutines:
+//
+//	defer errreport.ReportPanic(ctx, reporter, "policy poller")
+func ReportPanic(ctx context.Context, r ErrorReporter, component string) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if r != nil {
+		ev := New(ctx, KindPanic, component, &recovery.PanicError{Component: component, Value: v, Stack: debug.Stack()})
+		r.Report(ctx, ev)
+	}
+	panic(v)
+}
+
+// Func adapts a function to an ErrorReporter.
+type Func func(ctx context.Context, ev Event)
+
+// Report implements ErrorReporter.
+func (f Func) Report(ctx context.Context, ev Event) { f(ctx, ev) }
+
+const defaultAggregateWindow = 10 * time.Minute
+
+type aggregate struct {
+	first      time.Time
+	suppressed int
+}
+
+// Aggregator forwards the first of identical events (same kind, component and error message)
+// within Window (default 10m) to Next and suppresses the rest, so a fault repeating every cycle
+// does not flood the tracker. The next event forwarded after the window carries the suppressed
+// count in Occurrences.
+type Aggregator struct {
+	Next   ErrorReporter
+	Window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]*aggregate
+	now  func() time.Time
+}
+
+// Report implements ErrorReporter.
+func (a *Aggregator) Report(ctx context.Context, ev Event) {
+	now := time.Now()
+	if a.now != nil {
+		now = a.now()
+	}
+	window := a.Window
+	if window <= 0 {
+		window = defaultAggregateWindow
+	}
+	key := fmt.Sprintf("%s\x00%s\x00%v", ev.Kind, ev.Component, ev.Err)
+
+	a.mu.Lock()
+	if a.seen == nil {
+		a.seen = make(map[string]*aggregate)
+	}
+	if agg, ok := a.seen[key]; ok && now.Sub(agg.first) < window {
+		agg.suppressed++
+		a.mu.Unlock()
+		return
+	}
+	occurrences := 1
+	if agg, ok := a.seen[key]; ok {
+		occurrences += agg.suppressed
+	}
+	for k, agg := range a.seen {
+		if now.Sub(agg.first) >= window {
+			delete(a.seen, k)
+		}
+	}
+	a.seen[key] = &aggregate{first: now}
+	a.mu.Unlock()
+
+	if ev.Occurrences < occurrences {
+		ev.Occurrences = occurrences
+	}
+	a.Next.Report(ctx, ev)
+}
@@ -0,0 +1,81 @@
+package errreport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pkg/recovery"
+)
+
+func TestAggregator(t *testing.T) {
+	var got []Event
+	now := time.Unix(0, 0)
+	a := &Aggregator{
+		Next:   Func(func(_ context.Context, ev Event) { got = append(got, ev) }),
+		Window: time.Minute,
+		now:    func() time.Time { return now },
+	}
+	ctx := context.Background()
+	failing := errors.New("connection refused")
+
+	for i := 0; i < 3; i++ {
+		Report(ctx, a, KindCollection, "source", failing)
+	}
+	Report(ctx, a, KindPolicy, "governance", failing)
+	now = now.Add(time.Minute)
+	Report(ctx, a, KindCollection, "source", failing)
+
+	if len(got) != 3 {
+		t.Fatalf("forwarded %d events, want 3", len(got))
+	}
+	if got[0].Occurrences != 1 || got[1].Kind != KindPolicy || got[2].Occurrences != 3 {
+		t.Errorf("occurrences = %d, %d, %d; want 1, 1, 3", got[0].Occurrences, got[1].Occurrences, got[2].Occurrences)
+	}
+}
+
+func TestNewPanicEvent(t *testing.T) {
+	err := recovery.Call("rule", func() error { panic("boom") })
+	ev := New(context.Background(), KindCollection, "rule", err)
+	if ev.Kind != KindPanic || len(ev.Stack) == 0 {
+		t.Errorf("New() kind = %q, stack %d bytes; want panic with a stack", ev.Kind, len(ev.Stack))
+	}
+}
+
+func TestSentry(t *testing.T) {
+	var auth string
+	var payload sentryEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sentry/api/42/store/" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		auth = r.Header.Get("X-Sentry-Auth")
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer srv.Close()
+
+	for _, dsn := range []string{"", "https://o1.ingest.sentry.io/42", "https://key@o1.ingest.sentry.io/"} {
+		if _, err := NewSentry(dsn); err == nil {
+			t.Errorf("NewSentry(%q) succeeded, want an error", dsn)
+		}
+	}
+	s, err := NewSentry(strings.Replace(srv.URL, "://", "://public@", 1) + "/sentry/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Environment = "test"
+	s.OnError = func(err error) { t.Error(err) }
+	s.Report(context.Background(), Event{Kind: KindPanic, Component: "poller", Err: errors.New("boom"), Stack: []byte("goroutine 1"), Occurrences: 2})
+
+	if !strings.Contains(auth, "sentry_key=public") {
+		t.Errorf("X-Sentry-Auth = %q", auth)
+	}
+	if payload.Level != "fatal" || payload.Message != "boom" || payload.Tags["component"] != "poller" || payload.Extra["stack"] != "goroutine 1" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
@@ -0,0 +1,163 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sentryTimeout = 5 * time.Second
+	sentryClient  = "sts-errreport/1.0"
+)
+
+// Sentry sends events to a Sentry (or Sentry-compatible, e.g. GlitchTip) project through its store
+// API. Delivery failures are passed to OnError, if set, and otherwise dropped.
+type Sentry struct {
+	Environment string // e.g. "production"
+	Release     string
+	ServerName  string // Defaults to the host name
+	HTTP        *http.Client
+	OnError     func(err error)
+
+	endpoint string
+	key      string
+}
+
+// NewSentry creates a reporter for a project DSN, e.g.
+// "https://<public key>@o1.ingest.sentry.io/<project id>".
+func NewSentry(dsn string) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("errreport: invalid Sentry DSN: %w", err)
+	}
+	key := u.User.Username()
+	path := strings.TrimSuffix(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project := path[slash+1:]
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || key == "" || project == "" {
+		return nil, fmt.Errorf("errreport: invalid Sentry DSN, want <scheme>://<key>@<host>/<project>")
+	}
+	endpoint := u.Scheme + "://" + u.Host + path[:slash] + "/api/" + project + "/store/"
+	host, _ := os.Hostname()
+	return &Sentry{ServerName: host, endpoint: endpoint, key: key}, nil
+}
+
+// sentryEvent is the subset of the Sentry event payload filled by Sentry.
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Platform    string            `json:"platform"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Message     string            `json:"message"`
+	Fingerprint []string          `json:"fingerprint"`
+	Tags        map[string]string `json:"tags"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Exception   struct {
+		Values []sentryException `json:"values"`
+	} `json:"exception"`
+}
+
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Report implements ErrorReporter. It posts the event synchronously, bounded by ctx and a 5s
+// timeout, so a panic reported just before the process exits is delivered.
+func (s *Sentry) Report(ctx context.Context, ev Event) {
+	var id [16]byte
+	rand.Read(id[:])
+	msg := ""
+	if ev.Err != nil {
+		msg = ev.Err.Error()
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	level := "error"
+	if ev.Kind == KindPanic {
+		level = "fatal"
+	}
+
+	payload := sentryEvent{
+		EventID:     hex.EncodeToString(id[:]),
+		Timestamp:   ev.Time.UTC().Format(time.RFC3339Nano),
+		Level:       level,
+		Logger:      ev.Component,
+		Platform:    "go",
+		ServerName:  s.ServerName,
+		Environment: s.Environment,
+		Release:     s.Release,
+		Message:     msg,
+		Fingerprint: []string{ev.Kind, ev.Component},
+		Tags:        map[string]string{"kind": ev.Kind, "component": ev.Component},
+	}
+	for k, v := range ev.Tags {
+		payload.Tags[k] = v
+	}
+	if ev.CorrelationID != "" {
+		payload.Tags["correlation_id"] = ev.CorrelationID
+	}
+	if len(ev.Stack) > 0 || ev.Occurrences > 1 {
+		payload.Extra = make(map[string]any)
+		if len(ev.Stack) > 0 {
+			payload.Extra["stack"] = string(ev.Stack)
+		}
+		if ev.Occurrences > 1 {
+			payload.Extra["occurrences"] = ev.Occurrences
+		}
+	}
+	errType := ev.Kind
+	if ev.Err != nil {
+		errType = reflect.TypeOf(ev.Err).String()
+	}
+	payload.Exception.Values = []sentryException{{Type: errType, Value: msg}}
+
+	if err := s.send(ctx, payload); err != nil && s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+func (s *Sentry) send(ctx context.Context, payload sentryEvent) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sentryTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client="+sentryClient+
+		", sentry_timestamp="+strconv.FormatInt(time.Now().Unix(), 10)+", sentry_key="+s.key)
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("errreport: sending event to Sentry failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("errreport: Sentry returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package expand substitutes variables in manifests and governance documents at load time, so one
+// document can serve several environments. References take the form:
+//
+//	${NAME}            environment variable NAME (or Expander.Vars["NAME"])
+//	${NAME:-fallback}  NAME, or fallback if it is unset or empty
+//	${node.name}       the node's name (Expander.NodeName, default the host name)
+//	${node.region}     the node's region (Expander.Region)
+//	$$                 a literal "$"
+//
+// Expansion is strict: a reference without a value and without a fallback fails the whole
+// document, naming every missing variable, rather than silently expanding to "".
+package expand
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Built-in template variables.
+const (
+	VarNodeName   = "node.name"
+	VarNodeRegion = "node.region"
+)
+
+// ErrUnsetVariable matches a MissingVariablesError with errors.Is.
+var ErrUnsetVariable = errors.New("unset variable")
+
+// MissingVariablesError lists the variables a document references without a value.
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnsetVariable, strings.Join(e.Names, ", "))
+}
+
+func (e *MissingVariablesError) Is(target error) bool { return target == ErrUnsetVariable }
+
+// Expander expands variable references. The zero value expands environment variables and
+// ${node.name}; ${node.region} is unset unless Region is.
+type Expander struct {
+	NodeName string // Default os.Hostname
+	Region   string
+	// Vars are looked up before the environment, e.g. values from a deployment descriptor.
+	Vars map[string]string
+	// LookupEnv looks up environment variables (default os.LookupEnv).
+	LookupEnv func(key string) (string, bool)
+}
+
+// Expand expands every reference in s.
+func (e *Expander) Expand(s string) (string, error) {
+	return e.expand(s, func(v string) string { return v })
+}
+
+// ExpandJSON expands every reference in a JSON document. Values are JSON-escaped, so a value
+// containing quotes or backslashes cannot break out of the string it is substituted into; a
+// reference outside a string, e.g. "min_memory_kb": ${MEMORY_KB}, is substituted as is.
+func (e *Expander) ExpandJSON(data []byte) ([]byte, error) {
+	out, err := e.expand(string(data), func(v string) string {
+		quoted, _ := json.Marshal(v)
+		return string(quoted[1 : len(quoted)-1])
+	})
+	return []byte(out), err
+}
+
+func (e *Expander) expand(s string, escape func(string) string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	missing := map[string]bool{}
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			s = s[i+2:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			s = s[i+1:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable reference %q", s[i:])
+		}
+		ref := s[i+2 : i+end]
+		s = s[i+end+1:]
+
+		name, fallback, hasFallback := strings.Cut(ref, ":-")
+		if !validName(name) {
+			return "", fmt.Errorf("invalid variable reference ${%s}", ref)
+		}
+		value, ok := e.lookup(name)
+		if !ok || value == "" {
+			if !hasFallback {
+				missing[name] = true
+				continue
+			}
+			value = fallback
+		}
+		b.WriteString(escape(value))
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", &MissingVariablesError{Names: names}
+	}
+	return b.String(), nil
+}
+
+func (e *Expander) lookup(name string) (string, bool) {
+	switch name {
+	case VarNodeName:
+		if e.NodeName != "" {
+			return e.NodeName, true
+		}
+		host, err := os.Hostname()
+		return host, err == nil
+	case VarNodeRegion:
+		return e.Region, e.Region != ""
+	}
+	if v, ok := e.Vars[name]; ok {
+		return v, true
+	}
+	lookupEnv := e.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	return lookupEnv(name)
+}
+
+// validName accepts environment variable names and the dotted built-in names.
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case (r >= '0' && r <= '9') || r == '.':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package expand
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{"STAGE": "prod", "EMPTY": ""}
+	e := &Expander{
+		NodeName:  "node-7",
+		Region:    "eu-west-1",
+		Vars:      map[string]string{"STAGE": "staging"},
+		LookupEnv: func(key string) (string, bool) { v, ok := env[key]; return v, ok },
+	}
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "plain text", want: "plain text"},
+		{in: "${node.name}.${node.region}", want: "node-7.eu-west-1"},
+		{in: "stage=${STAGE}", want: "stage=staging"},
+		{in: "${EMPTY:-fallback} ${UNSET:-}", want: "fallback "},
+		{in: "cost $$5, $ref, ends with $", want: "cost $5, $ref, ends with $"},
+		{in: "${1BAD}", wantErr: true},
+		{in: "${UNTERMINATED", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := e.Expand(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q, error %t", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	_, err := e.Expand("${B} ${A} ${B} ${EMPTY}")
+	var missing *MissingVariablesError
+	if !errors.Is(err, ErrUnsetVariable) || !errors.As(err, &missing) || !reflect.DeepEqual(missing.Names, []string{"A", "B", "EMPTY"}) {
+		t.Errorf("Expand() error = %v, want every missing variable listed", err)
+	}
+}
+
+func TestExpandJSON(t *testing.T) {
+	e := &Expander{Vars: map[string]string{"NAME": `a"b\c`, "KB": "1024"}}
+	got, err := e.ExpandJSON([]byte(`{"id": "${NAME}", "min_memory_kb": ${KB}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id": "a\"b\\c", "min_memory_kb": 1024}`; string(got) != want {
+		t.Errorf("ExpandJSON() = %s, want %s", got, want)
+	}
+}
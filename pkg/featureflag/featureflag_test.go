@@ -0,0 +1,64 @@
+package featureflag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetEnabled(t *testing.T) {
+	s := New("node-a")
+	if err := s.ReplaceSource(SourceConfig, map[string]Flag{
+		"canary":   {Nodes: []string{"node-a"}},
+		"excluded": {Enabled: true, ExcludeNodes: []string{"node-a"}},
+		CELRules:   {},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ReplaceSource(SourceGovernance, map[string]Flag{"canary": {}}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "canary", want: false}, // The governance document overrides the configuration
+		{name: "excluded", want: false},
+		{name: CELRules, want: false},
+		{name: PushGovernance, want: true}, // Default
+		{name: "unknown", want: false},
+	}
+	for _, tt := range tests {
+		if got := s.Enabled(tt.name); got != tt.want {
+			t.Errorf("Enabled(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	var nilSet *Set
+	if !nilSet.Enabled(CELRules) {
+		t.Error("nil Set should report the default")
+	}
+	if err := s.ReplaceSource(SourceConfig, map[string]Flag{"bad": {Percentage: 101}}); err == nil {
+		t.Error("ReplaceSource() accepted a percentage above 100")
+	}
+}
+
+func TestFlagPercentage(t *testing.T) {
+	const nodes = 1000
+	on := func(f Flag) map[string]bool {
+		out := make(map[string]bool)
+		for i := 0; i < nodes; i++ {
+			if node := fmt.Sprintf("node-%d", i); f.On("rollout", node) {
+				out[node] = true
+			}
+		}
+		return out
+	}
+	ten, fifty := on(Flag{Percentage: 10}), on(Flag{Percentage: 50})
+	if len(ten) < 50 || len(ten) > 150 || len(fifty) < 400 || len(fifty) > 600 {
+		t.Errorf("10%% and 50%% rollouts enabled %d and %d of %d nodes", len(ten), len(fifty), nodes)
+	}
+	for node := range ten {
+		if !fifty[node] {
+			t.Errorf("raising the percentage disabled %s", node)
+		}
+	}
+}
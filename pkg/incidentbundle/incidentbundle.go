@@ -0,0 +1,336 @@
+// Package incidentbundle exports an incident bundle when telemetry escalates to the SIH level: a
+// single signed archive holding the breach timeline, recent telemetry history, the governance
+// policies in force, the node's context bundle and recent log lines, uploaded to S3-compatible
+// object storage for central incident review.
+//
+// A bundle is a gzip-compressed tar archive holding manifest.json, one JSON file per section and
+// manifest.sig, the Ed25519 signature of manifest.json. The manifest records the SHA-256 digest of
+// every section file, so the signature covers the whole archive; Open verifies both. Bundles are
+// redacted like crash bundles, since they leave the node.
+package incidentbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pkg/crashdump"
+	"pkg/system"
+	"services/telemetry"
+)
+
+// Defaults applied to zero-valued Exporter fields.
+const (
+	DefaultMaxBytes       = 16 << 20
+	DefaultSectionTimeout = 5 * time.Second
+	DefaultUploadTimeout  = time.Minute
+)
+
+// Archive member names.
+const (
+	ManifestFile  = "manifest.json"
+	SignatureFile = "manifest.sig"
+)
+
+// ContentType is the media type of an uploaded bundle.
+const ContentType = "application/gzip"
+
+var (
+	// ErrSignature is returned by Open when a bundle fails signature or digest verification.
+	ErrSignature = errors.New("incident bundle signature invalid")
+	// ErrExportRunning is passed to OnError when Handler skips an escalation because the previous
+	// export has not finished.
+	ErrExportRunning = errors.New("incidentbundle: previous export still running")
+)
+
+// Uploader stores a bundle, e.g. *objstore.S3.
+type Uploader interface {
+	Put(ctx context.Context, key string, body []byte, contentType string, metadata map[string]string) error
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Node        string             `json:"node"`
+	Time        time.Time          `json:"time"`
+	Reason      string             `json:"reason"`
+	Severity    telemetry.Severity `json:"severity"`
+	Causes      []string           `json:"causes,omitempty"`
+	BreachCount int                `json:"breach_count"`
+	KeyID       string             `json:"key_id"`
+	Files       map[string]string  `json:"files"`            // Section file name to hex SHA-256 digest
+	Failed      map[string]string  `json:"failed,omitempty"` // Section errors, timeouts and omissions
+}
+
+// Exporter builds incident bundles and uploads them. Sections are added with Register, e.g.
+// telemetry.IncidentSections, the governance module's PoliciesSection and context_probe.BundleSection.
+type Exporter struct {
+	Uploader Uploader
+	Node     string // Recorded in the manifest and the object key
+	Prefix   string // Object key prefix, e.g. "incidents/"
+	KeyID    string
+	Key      ed25519.PrivateKey
+	// MaxBytes bounds the sections of a bundle before compression (default 16 MiB). A section that
+	// does not fit in the remaining budget is omitted.
+	MaxBytes int64
+	// SectionTimeout bounds each section (default 5s).
+	SectionTimeout time.Duration
+	// UploadTimeout bounds the upload (default 1m).
+	UploadTimeout time.Duration
+	// Redactor masks personal data and credentials in every section. Nil applies
+	// crashdump.DefaultRedactionRules.
+	Redactor *system.Redactor
+	// OnError, if set, receives the failures of exports started by Handler.
+	OnError func(err error)
+
+	running  atomic.Bool    // An export started by Handler is in flight
+	wg       sync.WaitGroup // Exports started by Handler
+	mu       sync.Mutex
+	sections []crashdump.Section
+	once     sync.Once
+	fallback *system.Redactor
+}
+
+// Register adds a section to future bundles.
+func (e *Exporter) Register(s crashdump.Section) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sections = append(e.sections, s)
+}
+
+// LogSection returns the section "logs": the recent log lines kept by tail, e.g. the tail of
+// system.Router.KeepTail.
+func LogSection(tail *system.Tail) crashdump.Section {
+	return crashdump.Section{Name: "logs", Collect: func(ctx context.Context) (interface{}, error) {
+		return tail.Lines(), nil
+	}}
+}
+
+// Validate checks that the exporter can upload and sign bundles.
+func (e *Exporter) Validate() error {
+	switch {
+	case e.Uploader == nil:
+		return errors.New("incidentbundle: uploader is required")
+	case len(e.Key) != ed25519.PrivateKeySize:
+		return errors.New("incidentbundle: an Ed25519 signing key is required")
+	}
+	return nil
+}
+
+// Export builds a bundle for the escalated snapshot td, uploads it and returns its object key.
+func (e *Exporter) Export(ctx context.Context, td telemetry.TelemetryData) (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+	now := time.Now().UTC()
+	manifest := Manifest{
+		Node:        e.Node,
+		Time:        now,
+		Reason:      "SIH escalation: " + strings.Join(td.ViolationCauses, ","),
+		Severity:    td.Severity,
+		Causes:      td.ViolationCauses,
+		BreachCount: td.GATMBreachCount,
+		KeyID:       e.KeyID,
+		Files:       map[string]string{},
+	}
+	archive, err := e.build(&manifest)
+	if err != nil {
+		return "", fmt.Errorf("incidentbundle: %w", err)
+	}
+
+	key := fmt.Sprintf("%s%s/%s-%s.tar.gz", e.Prefix, e.Node, now.Format("20060102T150405.000Z"), strings.ToLower(string(td.Severity)))
+	timeout := e.UploadTimeout
+	if timeout <= 0 {
+		timeout = DefaultUploadTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	metadata := map[string]string{"node": e.Node, "key-id": e.KeyID, "severity": string(td.Severity)}
+	if err := e.Uploader.Put(ctx, key, archive, ContentType, metadata); err != nil {
+		return "", fmt.Errorf("incidentbundle: %w", err)
+	}
+	return key, nil
+}
+
+// Handler adapts the exporter to telemetry.EscalationHandler, for registration on the severity
+// tier or violation causes that mean SIH, e.g.
+// router.Handle(telemetry.SeverityCritical, exporter.Handler()). The export runs in the
+// background, so a slow or unreachable store cannot hold up the collection cycle or the handlers
+// after it; its failure goes to OnError. An escalation arriving while the previous export is still
+// running is skipped.
+func (e *Exporter) Handler() telemetry.EscalationHandler {
+	return telemetry.EscalationHandlerFunc(func(ctx context.Context, td telemetry.TelemetryData) error {
+		if !e.running.CompareAndSwap(false, true) {
+			e.fail(ErrExportRunning)
+			return nil
+		}
+		e.wg.Add(1)
+		// The export outlives the cycle, but keeps its correlation ID.
+		ctx = context.WithoutCancel(ctx)
+		go func() {
+			defer e.wg.Done()
+			defer e.running.Store(false)
+			if _, err := e.Export(ctx, td); err != nil {
+				e.fail(err)
+			}
+		}()
+		return nil
+	})
+}
+
+// Wait blocks until the exports started by Handler have finished, e.g. before shutdown.
+func (e *Exporter) Wait() {
+	e.wg.Wait()
+}
+
+func (e *Exporter) fail(err error) {
+	if e.OnError != nil {
+		e.OnError(err)
+	}
+}
+
+// build collects the sections into a signed archive, completing manifest.
+func (e *Exporter) build(manifest *Manifest) ([]byte, error) {
+	budget := e.MaxBytes
+	if budget <= 0 {
+		budget = DefaultMaxBytes
+	}
+	timeout := e.SectionTimeout
+	if timeout <= 0 {
+		timeout = DefaultSectionTimeout
+	}
+	fail := func(section, reason string) {
+		if manifest.Failed == nil {
+			manifest.Failed = map[string]string{}
+		}
+		manifest.Failed[section] = reason
+	}
+
+	e.mu.Lock()
+	sections := append([]crashdump.Section(nil), e.sections...)
+	e.mu.Unlock()
+	type file struct {
+		name string
+		data []byte
+	}
+	var files []file
+	for _, s := range sections {
+		data, err := crashdump.Collect(s, timeout)
+		if err != nil {
+			fail(s.Name, err.Error())
+			continue
+		}
+		data = []byte(e.redact(string(data)))
+		if int64(len(data)) > budget {
+			fail(s.Name, fmt.Sprintf("omitted: %d bytes exceed the remaining bundle size limit", len(data)))
+			continue
+		}
+		budget -= int64(len(data))
+		name := s.Name + ".json"
+		sum := sha256.Sum256(data)
+		manifest.Files[name] = hex.EncodeToString(sum[:])
+		files = append(files, file{name, data})
+	}
+
+	manifest.Reason = e.redact(manifest.Reason)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append([]file{{ManifestFile, data}, {SignatureFile, ed25519.Sign(e.Key, data)}}, files...)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.name, Mode: 0o600, Size: int64(len(f.data)), ModTime: manifest.Time}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *Exporter) redact(s string) string {
+	if e.Redactor != nil {
+		return e.Redactor.Redact(s)
+	}
+	e.once.Do(func() {
+		e.fallback = &system.Redactor{}
+		e.fallback.SetRules(crashdump.DefaultRedactionRules)
+	})
+	return e.fallback.Redact(s)
+}
+
+// Bundle is a verified incident bundle.
+type Bundle struct {
+	Manifest Manifest
+	Files    map[string][]byte // Section files by name, e.g. "incident.json"
+}
+
+// Open reads an archive written by an Exporter, verifying the manifest signature against pub and
+// every section file against its digest.
+func Open(r io.Reader, pub ed25519.PublicKey) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("incidentbundle: %w", err)
+	}
+	defer gz.Close()
+	members := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("incidentbundle: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("incidentbundle: %w", err)
+		}
+		members[hdr.Name] = data
+	}
+
+	raw, sig := members[ManifestFile], members[SignatureFile]
+	if raw == nil || sig == nil {
+		return nil, fmt.Errorf("%w: %s or %s missing", ErrSignature, ManifestFile, SignatureFile)
+	}
+	if !ed25519.Verify(pub, raw, sig) {
+		return nil, fmt.Errorf("%w: manifest", ErrSignature)
+	}
+	b := &Bundle{Files: map[string][]byte{}}
+	if err := json.Unmarshal(raw, &b.Manifest); err != nil {
+		return nil, fmt.Errorf("incidentbundle: invalid manifest: %w", err)
+	}
+	for name, digest := range b.Manifest.Files {
+		data, ok := members[name]
+		sum := sha256.Sum256(data)
+		if !ok || hex.EncodeToString(sum[:]) != digest {
+			return nil, fmt.Errorf("%w: %s does not match the manifest", ErrSignature, name)
+		}
+		b.Files[name] = data
+	}
+	return b, nil
+}
@@ -0,0 +1,100 @@
+package incidentbundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pkg/crashdump"
+	"services/telemetry"
+)
+
+type memoryUploader struct {
+	key  string
+	body []byte
+	meta map[string]string
+}
+
+func (u *memoryUploader) Put(ctx context.Context, key string, body []byte, contentType string, metadata map[string]string) error {
+	u.key, u.body, u.meta = key, body, metadata
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	up := &memoryUploader{}
+	e := &Exporter{Uploader: up, Node: "node-a", Prefix: "incidents/", KeyID: "k1", Key: key, MaxBytes: 200, SectionTimeout: 20 * time.Millisecond}
+	e.Register(crashdump.Section{Name: "incident", Collect: func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"operator": "jane.doe@example.com"}, nil
+	}})
+	e.Register(crashdump.Section{Name: "broken", Collect: func(ctx context.Context) (interface{}, error) { return nil, errors.New("sink closed") }})
+	e.Register(crashdump.Section{Name: "history", Collect: func(ctx context.Context) (interface{}, error) { return strings.Repeat("x", 500), nil }})
+
+	td := telemetry.TelemetryData{Severity: telemetry.SeverityCritical, ViolationCauses: []string{"latency"}, GATMBreachCount: 3}
+	objectKey, err := e.Export(context.Background(), td)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if objectKey != up.key || !strings.HasPrefix(objectKey, "incidents/node-a/") || !strings.HasSuffix(objectKey, "-critical.tar.gz") {
+		t.Errorf("Export() key = %s, uploaded as %s", objectKey, up.key)
+	}
+
+	b, err := Open(bytes.NewReader(up.body), pub)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if b.Manifest.Node != "node-a" || b.Manifest.BreachCount != 3 || len(b.Files) != 1 {
+		t.Errorf("manifest = %+v, files %d", b.Manifest, len(b.Files))
+	}
+	if b.Manifest.Failed["broken"] == "" || b.Manifest.Failed["history"] == "" {
+		t.Errorf("manifest does not record the failed and omitted sections: %v", b.Manifest.Failed)
+	}
+	if strings.Contains(string(b.Files["incident.json"]), "jane.doe") {
+		t.Errorf("bundle leaks personal data: %s", b.Files["incident.json"])
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, err := Open(bytes.NewReader(up.body), otherPub); !errors.Is(err, ErrSignature) {
+		t.Errorf("Open() with another key error = %v, want ErrSignature", err)
+	}
+}
+
+// blockingUploader fails every upload once release is closed.
+type blockingUploader struct{ release chan struct{} }
+
+func (u *blockingUploader) Put(ctx context.Context, key string, body []byte, contentType string, metadata map[string]string) error {
+	<-u.release
+	return errors.New("store unavailable")
+}
+
+func TestHandler_ExportsInBackground(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	up := &blockingUploader{release: make(chan struct{})}
+	errs := make(chan error, 2)
+	e := &Exporter{Uploader: up, Node: "node-a", KeyID: "k1", Key: key, OnError: func(err error) { errs <- err }}
+	h := e.Handler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := h.Escalate(ctx, telemetry.TelemetryData{Timestamp: time.Now()}); err != nil {
+		t.Fatalf("Escalate() error = %v, want the upload failure reported to OnError", err)
+	}
+	cancel() // The collection cycle ending does not abort the export.
+
+	// The store is still blocked, so the next escalation is skipped rather than queued.
+	if err := h.Escalate(context.Background(), telemetry.TelemetryData{Timestamp: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; !errors.Is(err, ErrExportRunning) {
+		t.Errorf("OnError() = %v, want ErrExportRunning", err)
+	}
+
+	close(up.release)
+	e.Wait()
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "store unavailable") {
+		t.Errorf("OnError() = %v, want the upload failure", err)
+	}
+}
@@ -0,0 +1,44 @@
+// Package jitter desynchronizes periodic work across a fleet, so thousands of nodes started
+// together do not poll the same endpoint in lockstep.
+package jitter
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Splay returns a random delay in [0, window), for spreading out the first run of periodic work.
+// It returns 0 when window is not positive.
+func Splay(window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	return rand.N(window)
+}
+
+// Apply perturbs d by a random amount of up to ±fraction of d, e.g. 0.1 yields a value in
+// [0.9d, 1.1d]. fraction is clamped to [0, 1]; a zero fraction returns d unchanged.
+func Apply(d time.Duration, fraction float64) time.Duration {
+	fraction = min(max(fraction, 0), 1)
+	spread := time.Duration(float64(d) * fraction)
+	if spread <= 0 {
+		return d
+	}
+	return d - spread + rand.N(2*spread+1)
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+package jitter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		lo, hi   time.Duration
+	}{
+		{name: "No Jitter", fraction: 0, lo: time.Second, hi: time.Second},
+		{name: "Ten Percent", fraction: 0.1, lo: 900 * time.Millisecond, hi: 1100 * time.Millisecond},
+		{name: "Clamped", fraction: 5, lo: 0, hi: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				if got := Apply(time.Second, tt.fraction); got < tt.lo || got > tt.hi {
+					t.Fatalf("Apply(1s, %v) = %v, want within [%v, %v]", tt.fraction, got, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestSplayAndSleep(t *testing.T) {
+	if got := Splay(0); got != 0 {
+		t.Errorf("Splay(0) = %v, want 0", got)
+	}
+	for i := 0; i < 1000; i++ {
+		if got := Splay(time.Second); got < 0 || got >= time.Second {
+			t.Fatalf("Splay(1s) = %v, want within [0, 1s)", got)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Sleep(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Sleep() on cancelled context = %v, want context.Canceled", err)
+	}
+}
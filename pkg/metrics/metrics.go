@@ -0,0 +1,69 @@
+// Package metrics is a small instrumentation facade so STS, the admission engine, the governance
+// module, and sinks are not tied to a single metrics backend. Label values are passed positionally
+// in the order the label names were declared.
+package metrics
+
+// Counter is a monotonically increasing value.
+type Counter interface {
+	Inc(labelValues ...string)
+	Add(delta float64, labelValues ...string)
+}
+
+// Gauge is a value that can go up and down.
+type Gauge interface {
+	Set(value float64, labelValues ...string)
+}
+
+// Histogram samples observations into buckets.
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// BucketHistogram accepts observations already aggregated into buckets, e.g. a latency
+// distribution reported by a telemetry source, so the exported histogram keeps its tail.
+type BucketHistogram interface {
+	// ObserveBuckets adds counts[i] observations at or below bounds[i] (and above bounds[i-1]), the
+	// last of len(bounds)+1 counts being above every bound, with the given sum.
+	ObserveBuckets(bounds []float64, counts []uint64, sum float64, labelValues ...string)
+}
+
+// Provider creates instruments. Implementations must return the same underlying instrument when
+// called twice with the same name, so components can be constructed more than once.
+type Provider interface {
+	Counter(name, help string, labelNames ...string) Counter
+	Gauge(name, help string, labelNames ...string) Gauge
+	// Histogram uses backend default buckets when buckets is nil.
+	Histogram(name, help string, buckets []float64, labelNames ...string) Histogram
+	// BucketHistogram exports with buckets, or backend default buckets when nil; observations in
+	// other buckets are counted in the first exported bucket covering them.
+	BucketHistogram(name, help string, buckets []float64, labelNames ...string) BucketHistogram
+}
+
+// Nop is a Provider whose instruments discard every observation. It is the default everywhere a
+// Provider is optional.
+var Nop Provider = nopProvider{}
+
+type nopProvider struct{}
+
+func (nopProvider) Counter(string, string, ...string) Counter                { return nopInstrument{} }
+func (nopProvider) Gauge(string, string, ...string) Gauge                    { return nopInstrument{} }
+func (nopProvider) Histogram(string, string, []float64, ...string) Histogram { return nopInstrument{} }
+func (nopProvider) BucketHistogram(string, string, []float64, ...string) BucketHistogram {
+	return nopInstrument{}
+}
+
+type nopInstrument struct{}
+
+func (nopInstrument) Inc(...string)                                          {}
+func (nopInstrument) Add(float64, ...string)                                 {}
+func (nopInstrument) Set(float64, ...string)                                 {}
+func (nopInstrument) Observe(float64, ...string)                             {}
+func (nopInstrument) ObserveBuckets([]float64, []uint64, float64, ...string) {}
+
+// OrNop returns p, or Nop if p is nil.
+func OrNop(p Provider) Provider {
+	if p == nil {
+		return Nop
+	}
+	return p
+}
@@ -0,0 +1,164 @@
+// Package prom implements metrics.Provider on the Prometheus client library.
+package prom
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"pkg/metrics"
+)
+
+// Provider registers instruments with a Prometheus registerer under an optional namespace.
+type Provider struct {
+	reg       prometheus.Registerer
+	namespace string
+
+	mu         sync.Mutex
+	collectors map[string]prometheus.Collector
+}
+
+// NewProvider creates a provider. A nil registerer uses prometheus.DefaultRegisterer.
+func NewProvider(reg prometheus.Registerer, namespace string) *Provider {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	return &Provider{reg: reg, namespace: namespace, collectors: make(map[string]prometheus.Collector)}
+}
+
+// register returns the collector already registered under name, or registers c.
+func (p *Provider) register(name string, c prometheus.Collector) prometheus.Collector {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.collectors[name]; ok {
+		return existing
+	}
+	if err := p.reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			panic(err) // Invalid metric definitions are programming errors.
+		}
+		c = are.ExistingCollector
+	}
+	p.collectors[name] = c
+	return c
+}
+
+// Counter implements metrics.Provider.
+func (p *Provider) Counter(name, help string, labelNames ...string) metrics.Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: p.namespace, Name: name, Help: help}, labelNames)
+	return counter{p.register(name, vec).(*prometheus.CounterVec)}
+}
+
+// Gauge implements metrics.Provider.
+func (p *Provider) Gauge(name, help string, labelNames ...string) metrics.Gauge {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: p.namespace, Name: name, Help: help}, labelNames)
+	return gauge{p.register(name, vec).(*prometheus.GaugeVec)}
+}
+
+// Histogram implements metrics.Provider.
+func (p *Provider) Histogram(name, help string, buckets []float64, labelNames ...string) metrics.Histogram {
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: p.namespace, Name: name, Help: help, Buckets: buckets}, labelNames)
+	return histogram{p.register(name, vec).(*prometheus.HistogramVec)}
+}
+
+// BucketHistogram implements metrics.Provider. Observations accumulate for the life of the process
+// and are exported as a constant histogram on every scrape.
+func (p *Provider) BucketHistogram(name, help string, buckets []float64, labelNames ...string) metrics.BucketHistogram {
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	c := &bucketCollector{
+		desc:   prometheus.NewDesc(prometheus.BuildFQName(p.namespace, "", name), help, labelNames, nil),
+		bounds: slices.Clone(buckets),
+		series: make(map[string]*bucketSeries),
+	}
+	return p.register(name, c).(*bucketCollector)
+}
+
+type counter struct{ vec *prometheus.CounterVec }
+
+func (c counter) Inc(labelValues ...string) { c.vec.WithLabelValues(labelValues...).Inc() }
+func (c counter) Add(delta float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+type gauge struct{ vec *prometheus.GaugeVec }
+
+func (g gauge) Set(value float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+type histogram struct{ vec *prometheus.HistogramVec }
+
+func (h histogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+// bucketCollector exports pre-bucketed observations, re-bucketed onto its own bounds.
+type bucketCollector struct {
+	desc   *prometheus.Desc
+	bounds []float64
+
+	mu     sync.Mutex
+	series map[string]*bucketSeries // By joined label values
+}
+
+type bucketSeries struct {
+	labelValues []string
+	counts      []uint64 // Per bucket, not cumulative; the last is above every bound
+	count       uint64
+	sum         float64
+}
+
+func (c *bucketCollector) ObserveBuckets(bounds []float64, counts []uint64, sum float64, labelValues ...string) {
+	if len(counts) != len(bounds)+1 {
+		panic("prom: bucket histogram needs len(bounds)+1 counts") // Like a label count mismatch, a programming error.
+	}
+	key := strings.Join(labelValues, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &bucketSeries{labelValues: slices.Clone(labelValues), counts: make([]uint64, len(c.bounds)+1)}
+		c.series[key] = s
+	}
+	for i, n := range counts {
+		upper := math.Inf(1)
+		if i < len(bounds) {
+			upper = bounds[i]
+		}
+		s.counts[sort.SearchFloat64s(c.bounds, upper)] += n
+		s.count += n
+	}
+	s.sum += sum
+}
+
+// Describe implements prometheus.Collector.
+func (c *bucketCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+// Collect implements prometheus.Collector.
+func (c *bucketCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.series {
+		buckets := make(map[float64]uint64, len(c.bounds))
+		var cumulative uint64
+		for i, bound := range c.bounds {
+			cumulative += s.counts[i]
+			buckets[bound] = cumulative
+		}
+		ch <- prometheus.MustNewConstHistogram(c.desc, s.count, s.sum, buckets, s.labelValues...)
+	}
+}
+
+// Ensure Provider implements the metrics.Provider interface.
+var _ metrics.Provider = (*Provider)(nil)
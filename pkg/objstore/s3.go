@@ -0,0 +1,207 @@
+// Package objstore uploads objects to S3-compatible object storage, such as AWS S3, MinIO or Ceph
+// RGW. Requests are signed with AWS Signature Version 4, so no SDK is needed.
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is used when S3.Region is empty. MinIO accepts it whatever its configured region.
+const DefaultRegion = "us-east-1"
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+)
+
+// S3 is a client for one bucket. The zero value of every optional field applies a default.
+type S3 struct {
+	Endpoint string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://minio:9000"
+	Region   string // Default DefaultRegion
+	Bucket   string
+	// Credentials. Empty credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+	// AWS_SESSION_TOKEN.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// VirtualHosted addresses the bucket as a subdomain of the endpoint. Path-style addressing is
+	// the default, since MinIO and most self-hosted stores expect it.
+	VirtualHosted bool
+	HTTP          *http.Client // Default http.DefaultClient
+
+	now func() time.Time
+}
+
+// Validate checks the endpoint and bucket without contacting the store.
+func (s *S3) Validate() error {
+	u, err := url.Parse(s.Endpoint)
+	switch {
+	case err != nil:
+		return fmt.Errorf("objstore: invalid endpoint: %w", err)
+	case u.Scheme != "http" && u.Scheme != "https", u.Host == "":
+		return fmt.Errorf("objstore: endpoint must be an http(s) URL, got '%s'", s.Endpoint)
+	case s.Bucket == "":
+		return errors.New("objstore: bucket is required")
+	}
+	return nil
+}
+
+// Put uploads body as the object key. Metadata is stored as x-amz-meta-* headers.
+func (s *S3) Put(ctx context.Context, key string, body []byte, contentType string, metadata map[string]string) error {
+	target, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range metadata {
+		req.Header.Set("X-Amz-Meta-"+name, value)
+	}
+	sum := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(sum[:]))
+
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("objstore: PUT %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("objstore: PUT %s returned %d: %s", key, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (s *S3) objectURL(key string) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	u, _ := url.Parse(s.Endpoint)
+	key = strings.TrimPrefix(key, "/")
+	if s.VirtualHosted {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path = "/" + key
+	} else {
+		u.Path = "/" + s.Bucket + "/" + key
+	}
+	u.RawPath = escapePath(u.Path)
+	return u.String(), nil
+}
+
+// sign adds the Signature Version 4 headers to req, signing the host and every header already set.
+func (s *S3) sign(req *http.Request, payloadHash string) {
+	accessKey, secretKey, token := s.AccessKeyID, s.SecretAccessKey, s.SessionToken
+	if accessKey == "" && secretKey == "" {
+		accessKey, secretKey, token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+	}
+	region := s.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := t.Format("20060102") + "/" + region + "/s3/aws4_request"
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := signingAlgorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{t.Format("20060102"), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath percent-encodes an object path for Signature Version 4, keeping the "/" separators.
+func escapePath(path string) string { return uriEncode(path, false) }
+
+// uriEncode percent-encodes every byte of s except unreserved characters and, unless encodeSlash
+// is set, "/".
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', strings.IndexByte("-._~", c) >= 0:
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func canonicalQuery(values url.Values) string {
+	var params []string
+	for name, vs := range values {
+		for _, v := range vs {
+			params = append(params, uriEncode(name, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
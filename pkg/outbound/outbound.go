@@ -0,0 +1,131 @@
+// Package outbound attaches identifying headers to outbound HTTP requests, such as policy fetches
+// and metrics scrapes, so gateways in front of those services can attribute and route them.
+//
+// Header values may reference variables as ${name}, e.g. "X-Node: ${node_id}". Variables are
+// supplied through Transport.Vars; the built-in ${correlation_id} expands to the correlation ID
+// carried by the request context.
+package outbound
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+
+	"pkg/correlation"
+)
+
+// TraceparentHeader is the W3C Trace Context header set when Trace is enabled.
+const TraceparentHeader = "traceparent"
+
+// VarCorrelationID is the built-in variable holding the request's correlation ID.
+const VarCorrelationID = "correlation_id"
+
+// Transport is an http.RoundTripper that decorates each request before passing it to Base.
+// Headers already present on a request are left untouched.
+type Transport struct {
+	Base http.RoundTripper // Default http.DefaultTransport
+
+	UserAgent string            // Templated User-Agent, e.g. "sts/1.4 (node ${node_id})"
+	Headers   map[string]string // Templated static headers
+	// Vars resolve ${name} references; they are evaluated on every request, so values such as the
+	// applied policy version stay current.
+	Vars map[string]func() string
+
+	// Trace propagates the context's correlation ID as X-Correlation-ID and, when it is a 32-digit
+	// hex ID such as correlation.RandomID produces, as a W3C traceparent.
+	Trace bool
+}
+
+// Validate reports header values that reference an unknown variable.
+func (t *Transport) Validate() error {
+	check := func(name, value string) error {
+		var unknown string
+		os.Expand(value, func(v string) string {
+			if _, ok := t.Vars[v]; !ok && v != VarCorrelationID && unknown == "" {
+				unknown = v
+			}
+			return ""
+		})
+		if unknown != "" {
+			return fmt.Errorf("outbound: header %s references unknown variable ${%s}", name, unknown)
+		}
+		return nil
+	}
+	if err := check("User-Agent", t.UserAgent); err != nil {
+		return err
+	}
+	for name, value := range t.Headers {
+		if err := check(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := correlation.FromContext(req.Context())
+	expand := func(value string) string {
+		return os.Expand(value, func(v string) string {
+			if v == VarCorrelationID {
+				return id
+			}
+			if fn := t.Vars[v]; fn != nil {
+				return fn()
+			}
+			return ""
+		})
+	}
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(req.Context())
+	if t.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", expand(t.UserAgent))
+	}
+	for name, value := range t.Headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, expand(value))
+		}
+	}
+	if t.Trace && id != "" {
+		if req.Header.Get(correlation.Header) == "" {
+			req.Header.Set(correlation.Header, id)
+		}
+		if req.Header.Get(TraceparentHeader) == "" && isTraceID(id) {
+			req.Header.Set(TraceparentHeader, "00-"+id+"-"+spanID()+"-01")
+		}
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// isTraceID reports whether id is a valid W3C trace ID: 32 lower-case hex digits, not all zero.
+func isTraceID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	zero := true
+	for _, c := range id {
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			zero = false
+		default:
+			return false
+		}
+	}
+	return !zero
+}
+
+// spanID returns a random 16-digit hex parent ID for this hop.
+func spanID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
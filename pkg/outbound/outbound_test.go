@@ -0,0 +1,74 @@
+package outbound
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pkg/correlation"
+)
+
+func TestTransport(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	version := "v1"
+	tr := &Transport{
+		UserAgent: "sts/1.0 (${node_id})",
+		Headers:   map[string]string{"X-Policy-Version": "${policy_version}", "X-Route": "eu-1"},
+		Vars: map[string]func() string{
+			"node_id":        func() string { return "node-7" },
+			"policy_version": func() string { return version },
+		},
+		Trace: true,
+	}
+	if err := tr.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: tr}
+
+	id := "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := correlation.WithID(context.Background(), id)
+	version = "v2"
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.Header.Set("X-Route", "caller")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	want := map[string]string{
+		"User-Agent":       "sts/1.0 (node-7)",
+		"X-Policy-Version": "v2",
+		"X-Route":          "caller",
+		correlation.Header: id,
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("header %s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+	if tp := got.Get(TraceparentHeader); !strings.HasPrefix(tp, "00-"+id+"-") || len(tp) != 55 {
+		t.Errorf("traceparent = %q, want 00-%s-<span>-01", tp, id)
+	}
+	if req.Header.Get("User-Agent") != "" {
+		t.Error("RoundTrip modified the caller's request")
+	}
+}
+
+func TestValidateUnknownVariable(t *testing.T) {
+	tr := &Transport{Headers: map[string]string{"X-Node": "${node}"}}
+	if err := tr.Validate(); err == nil {
+		t.Error("Validate() = nil, want error for unknown variable")
+	}
+	tr = &Transport{UserAgent: "sts (${correlation_id})"}
+	if err := tr.Validate(); err != nil {
+		t.Errorf("Validate() with built-in variable = %v", err)
+	}
+}
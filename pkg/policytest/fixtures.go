@@ -0,0 +1,341 @@
+// Package policytest generates synthetic SystemContexts at the boundary of each constraint in an
+// isolation policy, so manifest unit tests can confirm constraints admit and reject what was intended.
+package policytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"core/governance"
+	"pkg/bytesize"
+)
+
+// unlistedValue is used wherever a fixture needs a value outside a constraint's allowed set.
+const unlistedValue = "policytest-unlisted"
+
+// Fixture is a SystemContext paired with the admission outcome it should produce.
+type Fixture struct {
+	Name       string
+	Constraint governance.PolicyConstraint // Constraint the fixture targets; zero for the all-pass fixture
+	Context    governance.SystemContext
+	WantAdmit  bool
+}
+
+// Mutator adjusts a context so that the given constraint is barely satisfied (pass) or barely violated (fail).
+type Mutator func(sc *governance.SystemContext, c governance.PolicyConstraint)
+
+type mutators struct {
+	pass, fail Mutator
+}
+
+// Generator builds fixtures from the registered per-key mutators.
+type Generator struct {
+	keys map[string]mutators
+}
+
+// NewGenerator creates a generator covering the admission engine's default constraint keys and CPES keys.
+func NewGenerator() *Generator {
+	g := &Generator{keys: make(map[string]mutators)}
+	g.registerDefaults()
+	return g
+}
+
+// Register adds or replaces the mutators for a custom constraint key.
+func (g *Generator) Register(key string, pass, fail Mutator) {
+	g.keys[key] = mutators{pass: pass, fail: fail}
+}
+
+// Fixtures returns one fixture that satisfies every constraint of policy, followed by one per
+// constraint that starts from it and violates only that constraint. Constraints that cannot be
+// violated (e.g., a minimum lockdown mode of "none") get no failing fixture.
+func (g *Generator) Fixtures(policy governance.IsolationPolicy) ([]Fixture, error) {
+	var base governance.SystemContext
+	for _, c := range policy.Constraints {
+		m, err := g.lookup(c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("policy '%s': %w", policy.ID, err)
+		}
+		m.pass(&base, c)
+	}
+
+	fixtures := []Fixture{{Name: policy.ID + "/all-pass", Context: base, WantAdmit: true}}
+	for _, c := range policy.Constraints {
+		m, _ := g.lookup(c.Key)
+		if m.fail == nil {
+			continue
+		}
+		sc, err := clone(base)
+		if err != nil {
+			return nil, err
+		}
+		m.fail(&sc, c)
+		fixtures = append(fixtures, Fixture{Name: policy.ID + "/fail/" + c.Key, Constraint: c, Context: sc})
+	}
+	return fixtures, nil
+}
+
+// Verify runs every fixture for policyID through engine and reports each outcome that differs from
+// the fixture's expectation.
+func (g *Generator) Verify(engine *governance.PolicyAdmissionEngine, policyID string) error {
+	policy, ok := engine.Policies[policyID]
+	if !ok {
+		return fmt.Errorf("%w: '%s'", governance.ErrPolicyNotFound, policyID)
+	}
+	fixtures, err := g.Fixtures(policy)
+	if err != nil {
+		return err
+	}
+	var mismatches []string
+	for _, f := range fixtures {
+		admitted, evalErr := engine.EvaluateRequest(policyID, f.Context)
+		if admitted != f.WantAdmit {
+			mismatches = append(mismatches, fmt.Sprintf("%s: admitted = %v, want %v (%v)", f.Name, admitted, f.WantAdmit, evalErr))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("policy '%s' boundary check failed:\n  %s", policyID, strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+func (g *Generator) lookup(key string) (mutators, error) {
+	if key == "" {
+		return mutators{}, fmt.Errorf("constraint groups (all_of, any_of, not_any_of) are not supported")
+	}
+	if m, ok := g.keys[key]; ok {
+		return m, nil
+	}
+	if strings.HasPrefix(key, "CPES.") {
+		return cpesMutators(strings.TrimPrefix(key, "CPES.")), nil
+	}
+	return mutators{}, fmt.Errorf("no fixture mutators registered for constraint key '%s'", key)
+}
+
+// clone deep-copies a context through its JSON form.
+func clone(sc governance.SystemContext) (governance.SystemContext, error) {
+	var out governance.SystemContext
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return out, fmt.Errorf("failed to clone fixture context: %w", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("failed to clone fixture context: %w", err)
+	}
+	return out, nil
+}
+
+// boolKey registers a constraint that compares a boolean field against Required.
+func (g *Generator) boolKey(key string, set func(sc *governance.SystemContext, v bool)) {
+	g.Register(key,
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) { set(sc, c.Required) },
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) { set(sc, !c.Required) })
+}
+
+func (g *Generator) registerDefaults() {
+	g.boolKey("Hardware.TEE_Support", func(sc *governance.SystemContext, v bool) { sc.Hardware.TEE.Supported = v })
+	g.boolKey("Hardware.SR_IOV_Enabled", func(sc *governance.SystemContext, v bool) { sc.Hardware.SR_IOV_Enabled = v })
+	g.boolKey("Hardware.IOMMU_Enabled", func(sc *governance.SystemContext, v bool) { sc.Hardware.IOMMU.Enabled = v })
+	g.boolKey("Hardware.TEE.Attestation", func(sc *governance.SystemContext, v bool) { sc.Hardware.TEE.Attestation = v })
+	g.boolKey("OS.SecureBoot", func(sc *governance.SystemContext, v bool) { sc.OS.SecureBoot = v })
+	g.boolKey("OS.AppArmor", func(sc *governance.SystemContext, v bool) { sc.OS.AppArmor = v })
+	g.boolKey("OS.SELinux.Enforcing", func(sc *governance.SystemContext, v bool) {
+		sc.OS.SELinux = "permissive"
+		if v {
+			sc.OS.SELinux = "enforcing"
+		}
+	})
+	g.boolKey("Hardware.Accelerator.Present", func(sc *governance.SystemContext, v bool) {
+		if !v {
+			sc.Hardware.Accelerators = nil
+			return
+		}
+		accelerator(sc)
+	})
+	g.boolKey("Hardware.Accelerator.ConfidentialCompute", func(sc *governance.SystemContext, v bool) {
+		if v {
+			accelerator(sc)
+		}
+		setAll(sc, func(a *governance.Accelerator) { a.ConfidentialCompute = v })
+	})
+
+	g.Register("Hardware.TEE.Technology",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			sc.Hardware.TEE.Supported = true
+			sc.Hardware.TEE.Technology = first(c.Values)
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			sc.Hardware.TEE.Technology = unlistedValue
+		})
+
+	g.Register("Hardware.TEE.Version",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			sc.Hardware.TEE.Supported = true
+			sc.Hardware.TEE.Version = c.MinVersion
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			sc.Hardware.TEE.Version = previousVersion(c.MinVersion)
+		})
+
+	g.Register("Hardware.Accelerator.Vendor",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			accelerator(sc).Vendor = first(c.Values)
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			setAll(sc, func(a *governance.Accelerator) { a.Vendor = unlistedValue })
+		})
+
+	g.Register("Hardware.Accelerator.Partitioning",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			accelerator(sc).Partitioning = first(c.Values)
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			setAll(sc, func(a *governance.Accelerator) { a.Partitioning = unlistedValue })
+		})
+
+	g.Register("Hardware.MemoryBytes",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			if n, ok := memoryBound(c.Min); ok {
+				sc.Hardware.MemoryBytes = n
+			} else if n, ok := memoryBound(c.Max); ok {
+				sc.Hardware.MemoryBytes = n
+			}
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			if n, ok := memoryBound(c.Min); ok && n > 0 {
+				sc.Hardware.MemoryBytes = n - 1
+			} else if n, ok := memoryBound(c.Max); ok {
+				sc.Hardware.MemoryBytes = n + 1
+			}
+		})
+
+	g.Register("OS.Lockdown", lockdownPass, lockdownFail)
+
+	g.Register("OS.KernelCmdline",
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			if sc.OS.KernelCmdline == nil {
+				sc.OS.KernelCmdline = make(map[string]string)
+			}
+			for _, param := range c.Values {
+				key, value, _ := strings.Cut(param, "=")
+				sc.OS.KernelCmdline[key] = value
+			}
+		},
+		func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			// Drop only the last required parameter.
+			if len(c.Values) > 0 {
+				key, _, _ := strings.Cut(c.Values[len(c.Values)-1], "=")
+				delete(sc.OS.KernelCmdline, key)
+			}
+		})
+}
+
+// lockdownModes lists kernel lockdown modes from least to most restrictive.
+var lockdownModes = []string{governance.LockdownNone, governance.LockdownIntegrity, governance.LockdownConfidentiality}
+
+// lockdownMinimum returns the index of the least strict mode listed by the constraint.
+func lockdownMinimum(c governance.PolicyConstraint) int {
+	minimum := len(lockdownModes) - 1
+	for _, v := range c.Values {
+		for i, mode := range lockdownModes {
+			if mode == v && i < minimum {
+				minimum = i
+			}
+		}
+	}
+	return minimum
+}
+
+func lockdownPass(sc *governance.SystemContext, c governance.PolicyConstraint) {
+	sc.OS.Lockdown = lockdownModes[lockdownMinimum(c)]
+}
+
+func lockdownFail(sc *governance.SystemContext, c governance.PolicyConstraint) {
+	if i := lockdownMinimum(c); i > 0 {
+		sc.OS.Lockdown = lockdownModes[i-1]
+		return
+	}
+	// Every mode satisfies a minimum of "none"; an unrecognized mode is the only way to fail.
+	sc.OS.Lockdown = ""
+}
+
+// cpesMutators covers CPES keys using the same value/version/bool precedence as the engine.
+func cpesMutators(path string) mutators {
+	set := func(sc *governance.SystemContext, v interface{}) {
+		if sc.CPESConfiguration == nil {
+			sc.CPESConfiguration = governance.CPESConfig{}
+		}
+		sc.CPESConfiguration.Set(path, v)
+	}
+	return mutators{
+		pass: func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			switch {
+			case len(c.Values) > 0:
+				set(sc, first(c.Values))
+			case c.MinVersion != "":
+				set(sc, c.MinVersion)
+			default:
+				set(sc, c.Required)
+			}
+		},
+		fail: func(sc *governance.SystemContext, c governance.PolicyConstraint) {
+			switch {
+			case len(c.Values) > 0:
+				set(sc, unlistedValue)
+			case c.MinVersion != "":
+				set(sc, previousVersion(c.MinVersion))
+			default:
+				set(sc, !c.Required)
+			}
+		},
+	}
+}
+
+// memoryBound parses a Hardware.MemoryBytes bound; ok is false if it is unset or invalid.
+func memoryBound(bound string) (n uint64, ok bool) {
+	if bound == "" {
+		return 0, false
+	}
+	n, err := bytesize.Parse(bound)
+	return n, err == nil
+}
+
+// accelerator returns the first accelerator, adding one if the context has none.
+func accelerator(sc *governance.SystemContext) *governance.Accelerator {
+	if len(sc.Hardware.Accelerators) == 0 {
+		sc.Hardware.Accelerators = []governance.Accelerator{{Address: "0000:00:00.0", Kind: "gpu"}}
+	}
+	return &sc.Hardware.Accelerators[0]
+}
+
+func setAll(sc *governance.SystemContext, fn func(a *governance.Accelerator)) {
+	for i := range sc.Hardware.Accelerators {
+		fn(&sc.Hardware.Accelerators[i])
+	}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// previousVersion returns the closest dotted version below v, e.g., "2.0" -> "1.999" and "1.1" -> "1.0".
+// A version of all zeros has nothing below it and yields "", which version constraints reject.
+func previousVersion(v string) string {
+	parts := strings.Split(v, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n == 0 {
+			continue
+		}
+		parts[i] = strconv.Itoa(n - 1)
+		for j := i + 1; j < len(parts); j++ {
+			parts[j] = "999"
+		}
+		return strings.Join(parts, ".")
+	}
+	return ""
+}
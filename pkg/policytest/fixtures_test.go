@@ -0,0 +1,56 @@
+package policytest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"core/governance"
+)
+
+const manifest = `{
+  "schema_version": "V2.0-POLI-STRUCT",
+  "policies": [{
+    "id": "L5",
+    "constraints": [
+      {"key": "Hardware.TEE.Technology", "values": ["SGX", "TDX"]},
+      {"key": "Hardware.TEE.Version", "min_version": "2.0"},
+      {"key": "OS.Lockdown", "values": ["integrity"]},
+      {"key": "OS.KernelCmdline", "values": ["nosmt", "iommu=force"]},
+      {"key": "Hardware.MemoryBytes", "min": "8GiB"},
+      {"key": "CPES.network.egress_enabled", "required": false}
+    ]
+  }]
+}`
+
+func TestGeneratorVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := governance.NewPolicyAdmissionEngine(path)
+	if err != nil {
+		t.Fatalf("NewPolicyAdmissionEngine() error = %v", err)
+	}
+
+	g := NewGenerator()
+	fixtures, err := g.Fixtures(engine.Policies["L5"])
+	if err != nil {
+		t.Fatalf("Fixtures() error = %v", err)
+	}
+	if len(fixtures) != 7 {
+		t.Errorf("Fixtures() returned %d fixtures, want 7", len(fixtures))
+	}
+	if err := g.Verify(engine, "L5"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPreviousVersion(t *testing.T) {
+	tests := map[string]string{"2.0": "1.999", "1.1": "1.0", "3": "2", "0.0": ""}
+	for in, want := range tests {
+		if got := previousVersion(in); got != want {
+			t.Errorf("previousVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package ratelimit provides per-client token bucket rate limiting for HTTP and gRPC endpoints,
+// so a single misbehaving client (e.g., a dashboard polling in a tight loop) cannot degrade the node.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxClients = 10000
+	defaultIdleTTL    = 10 * time.Minute
+)
+
+// Config sets the sustained rate and burst allowed for each client.
+type Config struct {
+	Rate  float64 // Tokens added per second
+	Burst int     // Bucket capacity; zero defaults to ceil(Rate)
+	// MaxClients bounds the number of tracked buckets (default 10000). When full, idle buckets are
+	// evicted first; if none are idle, unknown clients are rejected.
+	MaxClients int
+	// IdleTTL is how long an untouched bucket is kept (default 10m).
+	IdleTTL time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter tracks one token bucket per client key.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// New creates a limiter.
+func New(cfg Config) *Limiter {
+	if cfg.Burst == 0 {
+		cfg.Burst = int(cfg.Rate)
+		if float64(cfg.Burst) < cfg.Rate {
+			cfg.Burst++
+		}
+	}
+	if cfg.MaxClients == 0 {
+		cfg.MaxClients = defaultMaxClients
+	}
+	if cfg.IdleTTL == 0 {
+		cfg.IdleTTL = defaultIdleTTL
+	}
+	return &Limiter{cfg: cfg, now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Allow consumes a token for key. When it returns false, retryAfter estimates when the next token is available.
+func (l *Limiter) Allow(key string) (ok bool, retryAfter time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, found := l.buckets[key]
+	if !found {
+		if len(l.buckets) >= l.cfg.MaxClients && !l.evictIdle(now) {
+			return false, time.Second
+		}
+		b = &bucket{tokens: float64(l.cfg.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(l.cfg.Burst), b.tokens+now.Sub(b.last).Seconds()*l.cfg.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.cfg.Rate <= 0 {
+		return false, time.Second
+	}
+	return false, time.Duration((1 - b.tokens) / l.cfg.Rate * float64(time.Second))
+}
+
+// evictIdle drops buckets untouched for IdleTTL and reports whether any room was made.
+func (l *Limiter) evictIdle(now time.Time) bool {
+	before := len(l.buckets)
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.cfg.IdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	return len(l.buckets) < before
+}
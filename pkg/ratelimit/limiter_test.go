@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_PerClientBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New(Config{Rate: 1, Burst: 2, MaxClients: 2, IdleTTL: time.Minute})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d within burst rejected", i)
+		}
+	}
+	ok, retry := l.Allow("a")
+	if ok || retry != time.Second {
+		t.Fatalf("over-limit Allow = %v, %v; want false, 1s", ok, retry)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Fatal("independent client was limited")
+	}
+
+	// Bucket table is full and nothing is idle: new clients are rejected.
+	if ok, _ := l.Allow("c"); ok {
+		t.Fatal("client admitted beyond MaxClients")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := l.Allow("c"); !ok {
+		t.Fatal("idle buckets were not evicted")
+	}
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatal("evicted client was not readmitted")
+	}
+}
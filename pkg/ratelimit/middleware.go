@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// HTTPKeyFunc extracts the client key from a request.
+type HTTPKeyFunc func(r *http.Request) string
+
+// ClientIP keys requests by the remote IP address, ignoring the port.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Middleware rejects requests over the limit with 429 Too Many Requests and a Retry-After header.
+// A nil key function keys by ClientIP; use an identity-based key behind authentication.
+func Middleware(l *Limiter, key HTTPKeyFunc, next http.Handler) http.Handler {
+	if key == nil {
+		key = ClientIP
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := l.Allow(key(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GRPCKeyFunc extracts the client key from a call's context.
+type GRPCKeyFunc func(ctx context.Context) string
+
+// PeerIP keys calls by the peer's IP address.
+func PeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// UnaryServerInterceptor rejects calls over the limit with codes.ResourceExhausted.
+// A nil key function keys by PeerIP.
+func UnaryServerInterceptor(l *Limiter, key GRPCKeyFunc) grpc.UnaryServerInterceptor {
+	if key == nil {
+		key = PeerIP
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if ok, retryAfter := l.Allow(key(ctx)); !ok {
+			return nil, status.Error(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry after %v", retryAfter))
+		}
+		return handler(ctx, req)
+	}
+}
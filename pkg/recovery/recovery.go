@@ -0,0 +1,44 @@
+// Package recovery converts panics in pluggable components (constraint evaluators, CEL functions,
+// telemetry sources) into errors, so one faulty plugin cannot crash the admission engine or STS loop.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrPanic matches any *PanicError with errors.Is.
+var ErrPanic = errors.New("component panicked")
+
+// PanicError records a recovered panic and the component that raised it.
+type PanicError struct {
+	Component string      // e.g., "constraint evaluator Hardware.TEE.Version"
+	Value     interface{} // Value passed to panic
+	Stack     []byte      // Stack trace captured at recovery
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s panicked: %v", e.Component, e.Value)
+}
+
+// Is reports ErrPanic as a match.
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+// Unwrap exposes the panic value when it is itself an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Call runs fn, returning its error or a *PanicError naming component if fn panics.
+func Call(component string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Component: component, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,36 @@
+package recovery
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name      string
+		fn        func() error
+		wantPanic bool
+		wantIs    error
+	}{
+		{"Returns Error", func() error { return io.EOF }, false, io.EOF},
+		{"Panic Value", func() error { panic("boom") }, true, ErrPanic},
+		{"Panic Error", func() error { panic(io.ErrUnexpectedEOF) }, true, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Call("test component", tt.fn)
+			var pe *PanicError
+			if errors.As(err, &pe) != tt.wantPanic {
+				t.Fatalf("Call() error = %v, wantPanic %v", err, tt.wantPanic)
+			}
+			if !errors.Is(err, tt.wantIs) {
+				t.Errorf("Call() error = %v, want errors.Is %v", err, tt.wantIs)
+			}
+			if tt.wantPanic && pe.Component != "test component" {
+				t.Errorf("Component = %q", pe.Component)
+			}
+		})
+	}
+}
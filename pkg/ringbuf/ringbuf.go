@@ -0,0 +1,161 @@
+// Package ringbuf provides a fixed-capacity, concurrency-safe ring buffer.
+package ringbuf
+
+import (
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ErrFull is returned by Push on a full buffer with the Reject policy.
+var ErrFull = errors.New("ringbuf: buffer full")
+
+// Policy selects what Push does when the buffer is full.
+type Policy int
+
+const (
+	// Overwrite replaces the oldest element.
+	Overwrite Policy = iota
+	// Reject leaves the buffer unchanged and returns ErrFull.
+	Reject
+)
+
+// Buffer is a ring buffer of at most Cap elements, ordered from oldest to newest.
+type Buffer[T any] struct {
+	mu     sync.RWMutex
+	items  []T
+	head   int // Index of the next slot to write
+	count  int // Number of elements stored
+	policy Policy
+}
+
+// New creates a buffer with the given capacity. A capacity below 1 is treated as 1.
+func New[T any](capacity int, policy Policy) *Buffer[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Buffer[T]{items: make([]T, capacity), policy: policy}
+}
+
+// Push appends v as the newest element.
+func (b *Buffer[T]) Push(v T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.count == len(b.items) && b.policy == Reject {
+		return ErrFull
+	}
+	b.items[b.head] = v
+	b.head = (b.head + 1) % len(b.items)
+	if b.count < len(b.items) {
+		b.count++
+	}
+	return nil
+}
+
+// Len returns the number of stored elements.
+func (b *Buffer[T]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.count
+}
+
+// Cap returns the maximum number of elements.
+func (b *Buffer[T]) Cap() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.items)
+}
+
+// Newest returns the most recently pushed element, if any.
+func (b *Buffer[T]) Newest() (T, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var zero T
+	if b.count == 0 {
+		return zero, false
+	}
+	return b.items[(b.head-1+len(b.items))%len(b.items)], true
+}
+
+// Last copies the newest n elements (fewer if not available), ordered from oldest to newest.
+func (b *Buffer[T]) Last(n int) []T {
+	var result []T
+	b.Peek(n, func(first, second []T) {
+		result = make([]T, 0, len(first)+len(second))
+		result = append(result, first...)
+		result = append(result, second...)
+	})
+	return result
+}
+
+// Peek calls fn with the newest n elements without copying them. The window is split into two
+// slices of the underlying storage at the wrap-around point; first precedes second in time. The
+// slices are only valid inside fn, must not be modified, and fn must not call back into the buffer.
+func (b *Buffer[T]) Peek(n int, fn func(first, second []T)) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if n > b.count {
+		n = b.count
+	}
+	if n <= 0 {
+		fn(nil, nil)
+		return
+	}
+	start := (b.head - n + len(b.items)) % len(b.items)
+	if start+n <= len(b.items) {
+		fn(b.items[start:start+n], nil)
+		return
+	}
+	fn(b.items[start:], b.items[:start+n-len(b.items)])
+}
+
+// All iterates over the stored elements from oldest to newest. The buffer is read-locked for the
+// duration of the loop, so the loop body must not call methods that modify it.
+func (b *Buffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+
+		start := (b.head - b.count + len(b.items)) % len(b.items)
+		for i := 0; i < b.count; i++ {
+			if !yield(b.items[(start+i)%len(b.items)]) {
+				return
+			}
+		}
+	}
+}
+
+// Resize changes the capacity, keeping the newest elements that still fit. A capacity below 1 is
+// treated as 1.
+func (b *Buffer[T]) Resize(capacity int) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if capacity == len(b.items) {
+		return
+	}
+	n := min(b.count, capacity)
+	items := make([]T, capacity)
+	start := (b.head - n + len(b.items)) % len(b.items)
+	for i := 0; i < n; i++ {
+		items[i] = b.items[(start+i)%len(b.items)]
+	}
+	b.items = items
+	b.head = n % capacity
+	b.count = n
+}
+
+// Reset removes all elements.
+func (b *Buffer[T]) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	clear(b.items)
+	b.head, b.count = 0, 0
+}
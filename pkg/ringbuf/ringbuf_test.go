@@ -0,0 +1,73 @@
+package ringbuf
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBufferPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		pushes  []int
+		wantErr bool
+		want    []int
+	}{
+		{"Partial", Overwrite, []int{1, 2}, false, []int{1, 2}},
+		{"Overwrite Oldest", Overwrite, []int{1, 2, 3, 4, 5}, false, []int{3, 4, 5}},
+		{"Reject When Full", Reject, []int{1, 2, 3, 4}, true, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New[int](3, tt.policy)
+			var err error
+			for _, v := range tt.pushes {
+				if pushErr := b.Push(v); pushErr != nil {
+					err = pushErr
+				}
+			}
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrFull)) {
+				t.Fatalf("Push() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := b.Last(10); !slices.Equal(got, tt.want) {
+				t.Errorf("Last() = %v, want %v", got, tt.want)
+			}
+			if got := slices.Collect(b.All()); !slices.Equal(got, tt.want) {
+				t.Errorf("All() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeekWrapAround(t *testing.T) {
+	b := New[int](4, Overwrite)
+	for v := 1; v <= 6; v++ {
+		b.Push(v)
+	}
+	b.Peek(3, func(first, second []int) {
+		if got := append(slices.Clone(first), second...); !slices.Equal(got, []int{4, 5, 6}) {
+			t.Errorf("Peek() = %v + %v, want [4 5 6]", first, second)
+		}
+	})
+	if v, ok := b.Newest(); !ok || v != 6 {
+		t.Errorf("Newest() = %d, %v, want 6, true", v, ok)
+	}
+}
+
+func TestResizeKeepsNewest(t *testing.T) {
+	b := New[int](4, Overwrite)
+	for v := 1; v <= 6; v++ {
+		b.Push(v)
+	}
+	b.Resize(2)
+	if got := b.Last(10); !slices.Equal(got, []int{5, 6}) {
+		t.Fatalf("after shrink Last() = %v, want [5 6]", got)
+	}
+	b.Resize(3)
+	b.Push(7)
+	if got := b.Last(10); !slices.Equal(got, []int{5, 6, 7}) {
+		t.Errorf("after grow Last() = %v, want [5 6 7]", got)
+	}
+}
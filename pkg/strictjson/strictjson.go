@@ -0,0 +1,181 @@
+// Package strictjson decodes JSON documents while rejecting object keys that do not map to a
+// struct field, reporting the full path of the offending key (e.g. "gatm.lattency_threshold").
+package strictjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownField is wrapped by every UnknownFieldError; match it with errors.Is.
+var ErrUnknownField = errors.New("unknown field")
+
+// UnknownFieldError reports a key with no matching struct field.
+type UnknownFieldError struct {
+	Path string // Dotted path to the key; array elements appear as "[i]"
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("%v %q", ErrUnknownField, e.Path)
+}
+
+func (e *UnknownFieldError) Unwrap() error { return ErrUnknownField }
+
+// Unmarshal is json.Unmarshal with unknown-field rejection.
+func Unmarshal(data []byte, v any) error {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+	if path, ok := findUnknown(doc, reflect.TypeOf(v), ""); ok {
+		return &UnknownFieldError{Path: path}
+	}
+	// The walk above cannot see every type json accepts; DisallowUnknownFields catches the rest.
+	dec = json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// Decode reads r to EOF and decodes it as a single document with Unmarshal.
+func Decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(data, v)
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// findUnknown walks the generic document alongside t and returns the path of the first key that
+// t cannot hold. Types with custom decoders are trusted to handle their own input.
+func findUnknown(doc any, t reflect.Type, path string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	if t.Kind() != reflect.Pointer && (reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)) {
+		return "", false
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return findUnknown(doc, t.Elem(), path)
+	case reflect.Struct:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		fields := structFields(t)
+		for _, key := range sortedKeys(obj) {
+			ft, ok := lookupField(fields, key)
+			if !ok {
+				return join(path, key), true
+			}
+			if p, bad := findUnknown(obj[key], ft, join(path, key)); bad {
+				return p, true
+			}
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		for _, key := range sortedKeys(obj) {
+			if p, bad := findUnknown(obj[key], t.Elem(), join(path, key)); bad {
+				return p, true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := doc.([]any)
+		if !ok {
+			return "", false
+		}
+		for i, elem := range arr {
+			if p, bad := findUnknown(elem, t.Elem(), path+"["+strconv.Itoa(i)+"]"); bad {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// structFields maps JSON names to field types, including fields promoted from embedded structs.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range structFields(ft) {
+					if _, shadowed := fields[k]; !shadowed {
+						fields[k] = v
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupField matches key the way encoding/json does: exactly, then case-insensitively.
+func lookupField(fields map[string]reflect.Type, key string) (reflect.Type, bool) {
+	if ft, ok := fields[key]; ok {
+		return ft, true
+	}
+	for name, ft := range fields {
+		if strings.EqualFold(name, key) {
+			return ft, true
+		}
+	}
+	return nil, false
+}
+
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // Deterministic, so the same document always reports the same path
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,63 @@
+package strictjson
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type rule struct {
+	Name string `json:"name"`
+}
+
+type inner struct {
+	Threshold time.Duration `json:"threshold"`
+	Rules     []rule        `json:"rules"`
+}
+
+type doc struct {
+	Inner  inner             `json:"inner"`
+	Named  map[string]rule   `json:"named"`
+	Labels map[string]string `json:"labels"`
+	At     time.Time         `json:"at"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "Valid", input: `{"inner":{"threshold":5,"rules":[{"name":"a"}]},"labels":{"any":"x"},"at":"2024-01-01T00:00:00Z"}`},
+		{name: "Case Insensitive", input: `{"Inner":{"Threshold":5}}`},
+		{name: "Top Level", input: `{"inner":{},"extra":1}`, wantPath: "extra"},
+		{name: "Nested", input: `{"inner":{"lattency_threshold":5}}`, wantPath: "inner.lattency_threshold"},
+		{name: "Slice Element", input: `{"inner":{"rules":[{"name":"a"},{"nmae":"b"}]}}`, wantPath: "inner.rules[1].nmae"},
+		{name: "Map Value", input: `{"named":{"x":{"id":"b"}}}`, wantPath: "named.x.id"},
+		{name: "Syntax Error", input: `{"inner":`, wantErr: true},
+		{name: "Trailing Data", input: `{} {}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d doc
+			err := Unmarshal([]byte(tt.input), &d)
+			var unknown *UnknownFieldError
+			switch {
+			case tt.wantPath != "":
+				if !errors.As(err, &unknown) || unknown.Path != tt.wantPath {
+					t.Fatalf("Unmarshal() error = %v, want unknown field %q", err, tt.wantPath)
+				}
+				if !errors.Is(err, ErrUnknownField) {
+					t.Errorf("error does not wrap ErrUnknownField: %v", err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &unknown) {
+					t.Fatalf("Unmarshal() error = %v, want a decode error", err)
+				}
+			case err != nil:
+				t.Fatalf("Unmarshal() unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -1,28 +1,150 @@
 package system
 
 import (
-	"log"
-	"os"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"pkg/correlation"
 )
 
+// Format selects how DefaultLogger renders log lines.
+type Format string
+
+// Supported log formats. JSON and logfmt lines carry the fields ts, level, component, msg and,
+// when known, correlation_id, so they can be ingested by Loki or ELK without custom parsing.
+const (
+	FormatText   Format = "text"   // [component] LEVEL 2006/01/02 15:04:05 message
+	FormatJSON   Format = "json"   // {"ts":"...","level":"info","component":"...","msg":"..."}
+	FormatLogfmt Format = "logfmt" // ts=... level=info component=... msg="..."
+)
+
+// ParseFormat validates a configured format name; an empty name selects FormatText.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatLogfmt:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown log format '%s' (want text, json, or logfmt)", name)
+}
+
 // DefaultLogger implements the governance.Logger interface using standard Go log features.
 type DefaultLogger struct {
-	prefix string
+	prefix        string
+	component     string
+	format        Format
+	correlationID string
+	sink          Sink
+	levels        *Levels // Nil logs LevelInfo and above
+	redactor      *Redactor
 }
 
+// defaultRedactor masks credentials in the lines of loggers not handed out by a Router.
+var defaultRedactor = &Redactor{}
+
 func NewDefaultLogger(component string) *DefaultLogger {
-	return &DefaultLogger{prefix: fmt.Sprintf("[%s] ", component)}
+	return NewLogger(component, FormatText)
+}
+
+// NewLogger creates a logger for component rendering lines in format to stdout and stderr.
+func NewLogger(component string, format Format) *DefaultLogger {
+	return NewSinkLogger(component, format, StdSink{})
+}
+
+// NewSinkLogger creates a logger for component rendering lines in format to sink. Messages are
+// redacted with DefaultRedactionRules.
+func NewSinkLogger(component string, format Format, sink Sink) *DefaultLogger {
+	return &DefaultLogger{
+		prefix:    fmt.Sprintf("[%s] ", component),
+		component: component,
+		format:    format,
+		sink:      sink,
+		redactor:  defaultRedactor,
+	}
+}
+
+// WithCorrelationID implements correlation.FieldLogger. In text format the ID prefixes the message.
+func (l *DefaultLogger) WithCorrelationID(id string) correlation.Logger {
+	c := *l
+	c.correlationID = id
+	return &c
+}
+
+// Debugf logs a line only while the component's level is LevelDebug, e.g. after raising it
+// through the admin API.
+func (l *DefaultLogger) Debugf(format string, args ...interface{}) {
+	l.output(LevelDebug, log.Ldate|log.Ltime|log.Lshortfile, format, args...)
 }
 
 func (l *DefaultLogger) Infof(format string, args ...interface{}) {
-	log.New(os.Stdout, l.prefix + "INFO ", log.Ldate|log.Ltime).Printf(format, args...)
+	l.output(LevelInfo, log.Ldate|log.Ltime, format, args...)
 }
 
 func (l *DefaultLogger) Errorf(format string, args ...interface{}) {
-	log.New(os.Stderr, l.prefix + "ERROR ", log.Ldate|log.Ltime|log.Lshortfile).Printf(format, args...)
+	l.output(LevelError, log.Ldate|log.Ltime|log.Lshortfile, format, args...)
 }
 
 func (l *DefaultLogger) Warnf(format string, args ...interface{}) {
-    log.New(os.Stdout, l.prefix + "WARN ", log.Ldate|log.Ltime).Printf(format, args...)
-}
\ No newline at end of file
+	l.output(LevelWarn, log.Ldate|log.Ltime, format, args...)
+}
+
+// output renders one line. Text lines keep the standard log layout; calldepth 3 attributes
+// Lshortfile to the caller of Errorf.
+func (l *DefaultLogger) output(lvl Level, flags int, format string, args ...interface{}) {
+	if !l.enabled(lvl) {
+		return
+	}
+	msg := l.redactor.Redact(fmt.Sprintf(format, args...))
+	if l.format != FormatJSON && l.format != FormatLogfmt {
+		if l.correlationID != "" {
+			msg = "[correlation_id=" + l.correlationID + "] " + msg
+		}
+		var buf bytes.Buffer
+		log.New(&buf, l.prefix+lvl.String()+" ", flags).Output(3, msg)
+		l.sink.WriteLog(lvl, bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+		return
+	}
+
+	ts := time.Now().UTC().Format(time.RFC3339Nano)
+	level := strings.ToLower(lvl.String())
+	var line []byte
+	if l.format == FormatJSON {
+		entry := struct {
+			TS            string `json:"ts"`
+			Level         string `json:"level"`
+			Component     string `json:"component"`
+			CorrelationID string `json:"correlation_id,omitempty"`
+			Msg           string `json:"msg"`
+		}{ts, level, l.component, l.correlationID, msg}
+		line, _ = json.Marshal(entry)
+	} else {
+		var b strings.Builder
+		b.WriteString("ts=" + ts + " level=" + level + " component=" + logfmtValue(l.component))
+		if l.correlationID != "" {
+			b.WriteString(" correlation_id=" + logfmtValue(l.correlationID))
+		}
+		b.WriteString(" msg=" + logfmtValue(msg))
+		line = []byte(b.String())
+	}
+	l.sink.WriteLog(lvl, line)
+}
+
+func (l *DefaultLogger) enabled(lvl Level) bool {
+	if l.levels == nil {
+		return lvl >= LevelInfo
+	}
+	return l.levels.Enabled(l.component, lvl)
+}
+
+// logfmtValue quotes v when it is empty or contains spaces, quotes, '=' or control characters.
+func logfmtValue(v string) string {
+	if v == "" || strings.ContainsAny(v, " =\"\\") || strings.IndexFunc(v, func(r rune) bool { return r < ' ' }) >= 0 {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
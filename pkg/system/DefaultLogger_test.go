@@ -0,0 +1,101 @@
+package system
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultLogger_Formats(t *testing.T) {
+	sink := &recordingSink{}
+	NewSinkLogger("sts", FormatJSON, sink).WithCorrelationID("req-1").(*DefaultLogger).Warnf("disk at %d%%", 91)
+	var entry map[string]string
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(sink.lines[0], "WARN ")), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "warn" || entry["component"] != "sts" || entry["correlation_id"] != "req-1" || entry["msg"] != "disk at 91%" || entry["ts"] == "" {
+		t.Errorf("JSON entry = %v", entry)
+	}
+
+	sink.lines = nil
+	NewSinkLogger("sts", FormatLogfmt, sink).Infof("password=hunter2 sent")
+	if got := sink.lines[0]; !strings.Contains(got, ` level=info component=sts msg="password=[REDACTED] sent"`) {
+		t.Errorf("logfmt line = %q, want a redacted, quoted msg", got)
+	}
+
+	sink.lines = nil
+	NewSinkLogger("sts", FormatText, sink).Errorf("failed")
+	if got := sink.lines[0]; !strings.HasPrefix(got, "ERROR [sts] ERROR ") || !strings.Contains(got, "DefaultLogger_test.go") || !strings.HasSuffix(got, "failed") {
+		t.Errorf("text line = %q, want the standard log layout with the caller's file", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "JSON": FormatJSON, "logfmt": FormatLogfmt} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(\"xml\") error = nil")
+	}
+}
+
+// recordingSink keeps the lines written to it and counts Close calls.
+type recordingSink struct {
+	lines  []string
+	closed int
+}
+
+func (s *recordingSink) WriteLog(level Level, line []byte) error {
+	s.lines = append(s.lines, level.String()+" "+string(line))
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestLevels_OverridesExpire(t *testing.T) {
+	now := time.Unix(1000, 0)
+	v := NewLevels(LevelWarn, map[string]Level{"admission": LevelInfo})
+	v.now = func() time.Time { return now }
+
+	if v.Level("sts") != LevelWarn || v.Level("admission") != LevelInfo {
+		t.Fatalf("configured levels = %v, %v; want WARN, INFO", v.Level("sts"), v.Level("admission"))
+	}
+	v.Set("", LevelError, 0)
+	v.Set("sts", LevelDebug, time.Minute)
+	if v.Level("sts") != LevelDebug || v.Level("admission") != LevelError {
+		t.Errorf("overridden levels = %v, %v; want DEBUG, ERROR", v.Level("sts"), v.Level("admission"))
+	}
+	if got := v.Overrides(); len(got) != 2 || got[0].Component != "" || got[1].Component != "sts" {
+		t.Errorf("Overrides() = %+v, want global then sts", got)
+	}
+
+	now = now.Add(time.Minute)
+	if v.Level("sts") != LevelError {
+		t.Errorf("Level(sts) after expiry = %v, want the global override", v.Level("sts"))
+	}
+	if got := v.Overrides(); len(got) != 1 {
+		t.Errorf("Overrides() after expiry = %+v, want only the global override", got)
+	}
+	v.Reset("")
+	if v.Level("sts") != LevelWarn {
+		t.Errorf("Level(sts) after Reset = %v, want the configured WARN", v.Level("sts"))
+	}
+}
+
+func TestRouter_SharedLevels(t *testing.T) {
+	sink := &recordingSink{}
+	r := NewRouter(FormatText, sink, nil)
+	logger := r.Logger("sts")
+	logger.Debugf("hidden")
+	r.Levels().Set("sts", LevelDebug, 0)
+	logger.Debugf("shown")
+	if len(sink.lines) != 1 || !strings.HasSuffix(sink.lines[0], "shown") {
+		t.Errorf("lines = %v, want only the line logged after raising the level", sink.lines)
+	}
+}
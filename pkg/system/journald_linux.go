@@ -0,0 +1,73 @@
+//go:build linux
+
+package system
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strconv"
+)
+
+// journalSocket is where journald accepts native protocol datagrams.
+const journalSocket = "/run/systemd/journal/socket"
+
+// JournaldSink sends log lines to journald over its native protocol, mapping ERROR, WARN and INFO
+// to syslog priorities 3, 4 and 6.
+type JournaldSink struct {
+	conn       *net.UnixConn
+	identifier string
+}
+
+// NewJournaldSink connects to the local journal. identifier sets SYSLOG_IDENTIFIER.
+func NewJournaldSink(identifier string) (*JournaldSink, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldSink{conn: conn, identifier: identifier}, nil
+}
+
+// journalPriority maps a level to a syslog priority.
+func journalPriority(level Level) int {
+	switch level {
+	case LevelError:
+		return 3
+	case LevelWarn:
+		return 4
+	case LevelDebug:
+		return 7
+	}
+	return 6
+}
+
+// WriteLog implements Sink.
+func (j *JournaldSink) WriteLog(level Level, line []byte) error {
+	var b bytes.Buffer
+	b.WriteString("PRIORITY=" + strconv.Itoa(journalPriority(level)) + "\n")
+	if j.identifier != "" {
+		journalField(&b, "SYSLOG_IDENTIFIER", []byte(j.identifier))
+	}
+	journalField(&b, "MESSAGE", line)
+	_, err := j.conn.Write(b.Bytes())
+	return err
+}
+
+// journalField appends a field, using the length-prefixed form for values containing newlines.
+func journalField(b *bytes.Buffer, name string, value []byte) {
+	if bytes.IndexByte(value, '\n') < 0 {
+		b.WriteString(name + "=")
+		b.Write(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteString(name + "\n")
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.Write(value)
+	b.WriteByte('\n')
+}
+
+// Close implements Sink.
+func (j *JournaldSink) Close() error {
+	return j.conn.Close()
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package system
+
+import "errors"
+
+// JournaldSink is unsupported off Linux.
+type JournaldSink struct{}
+
+// NewJournaldSink always fails off Linux.
+func NewJournaldSink(identifier string) (*JournaldSink, error) {
+	return nil, errors.New("journald is only supported on Linux")
+}
+
+// WriteLog implements Sink.
+func (j *JournaldSink) WriteLog(level Level, line []byte) error {
+	return errors.New("journald is only supported on Linux")
+}
+
+// Close implements Sink.
+func (j *JournaldSink) Close() error { return nil }
@@ -0,0 +1,158 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ParseLevel parses a level name, e.g. "debug" or "WARN"; an empty name selects LevelInfo.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level '%s' (want debug, info, warn, or error)", name)
+}
+
+// MarshalText renders the level in lower case, e.g. "debug".
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(l.String())), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// LevelOverride is a level set at runtime, replacing the configured one until it expires.
+type LevelOverride struct {
+	Component string    `json:"component,omitempty"` // Empty for the global level
+	Level     Level     `json:"level"`
+	Expires   time.Time `json:"expires,omitempty"` // Zero until reset
+}
+
+// Levels holds the minimum level logged by each component. Runtime overrides, global or per
+// component, take precedence over the configured levels and revert on their own once they expire,
+// so debug logging enabled during an incident does not outlive it. The zero value logs LevelInfo
+// and above for every component.
+type Levels struct {
+	mu         sync.RWMutex
+	global     Level
+	components map[string]Level
+	overrides  map[string]LevelOverride // Keyed by component, "" for global
+	now        func() time.Time
+}
+
+// NewLevels creates levels logging global and above, except for components with a level of their
+// own.
+func NewLevels(global Level, components map[string]Level) *Levels {
+	v := &Levels{}
+	v.Configure(global, components)
+	return v
+}
+
+// Configure replaces the configured levels. Runtime overrides stay in effect.
+func (v *Levels) Configure(global Level, components map[string]Level) {
+	c := make(map[string]Level, len(components))
+	for component, l := range components {
+		c[component] = l
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.global, v.components = global, c
+}
+
+func (v *Levels) clock() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
+
+// Level returns the minimum level component logs: its own override, then the global override,
+// then its configured level, then the configured global level.
+func (v *Levels) Level(component string) Level {
+	now := v.clock()
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for _, key := range []string{component, ""} {
+		if o, ok := v.overrides[key]; ok && (o.Expires.IsZero() || now.Before(o.Expires)) {
+			return o.Level
+		}
+	}
+	if l, ok := v.components[component]; ok {
+		return l
+	}
+	return v.global
+}
+
+// Enabled reports whether component logs lines of level l.
+func (v *Levels) Enabled(component string, l Level) bool {
+	return l >= v.Level(component)
+}
+
+// Set overrides the level of component, or of every component without an override of its own if
+// component is empty, for d. A zero d keeps the override until Reset.
+func (v *Levels) Set(component string, l Level, d time.Duration) LevelOverride {
+	o := LevelOverride{Component: component, Level: l}
+	if d > 0 {
+		o.Expires = v.clock().Add(d)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.overrides == nil {
+		v.overrides = make(map[string]LevelOverride)
+	}
+	v.overrides[component] = o
+	return o
+}
+
+// Reset removes the override of component, or the global override if component is empty,
+// reverting to the configured level.
+func (v *Levels) Reset(component string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.overrides, component)
+}
+
+// Overrides lists the overrides in effect, global first and then by component, dropping expired ones.
+func (v *Levels) Overrides() []LevelOverride {
+	now := v.clock()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	out := make([]LevelOverride, 0, len(v.overrides))
+	for key, o := range v.overrides {
+		if !o.Expires.IsZero() && !now.Before(o.Expires) {
+			delete(v.overrides, key)
+			continue
+		}
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Component < out[j].Component })
+	return out
+}
+
+// Configured returns the configured global level and per-component levels.
+func (v *Levels) Configured() (Level, map[string]Level) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	c := make(map[string]Level, len(v.components))
+	for component, l := range v.components {
+		c[component] = l
+	}
+	return v.global, c
+}
@@ -0,0 +1,172 @@
+package system
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"pkg/metrics"
+)
+
+// redactedValue replaces the part of a log message matched by a redaction rule.
+const redactedValue = "[REDACTED]"
+
+// RedactionRule masks the matches of Pattern in log messages with Replacement, which may reference
+// submatches as in regexp.Expand, e.g. "${1}[REDACTED]" to keep a key and mask its value. An empty
+// Replacement masks the whole match.
+type RedactionRule struct {
+	Name        string // Label of the rule's hit metric
+	Pattern     string
+	Replacement string
+}
+
+// DefaultRedactionRules mask credentials that commonly end up in error messages: passwords in
+// endpoint URLs, bearer tokens, and token, secret, password or API key parameters.
+var DefaultRedactionRules = []RedactionRule{
+	{Name: "url_credentials", Pattern: `(://[^/\s:@]+:)[^/\s@]+@`, Replacement: "${1}" + redactedValue + "@"},
+	{Name: "bearer_token", Pattern: `(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`, Replacement: "${1}" + redactedValue},
+	{Name: "secret_parameter", Pattern: `(?i)((?:access_token|api_key|apikey|password|passwd|secret|token)["']?\s*[=:]\s*["']?)[^\s&"',;]+`, Replacement: "${1}" + redactedValue},
+}
+
+type compiledRule struct {
+	name        string
+	re          *regexp.Regexp
+	replacement string
+}
+
+// apply masks every match in s and returns the number of matches.
+func (c compiledRule) apply(s string) (string, int) {
+	matches := c.re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, 0
+	}
+	var b []byte
+	last := 0
+	for _, m := range matches {
+		b = append(b, s[last:m[0]]...)
+		b = c.re.ExpandString(b, c.replacement, s, m)
+		last = m[1]
+	}
+	return string(append(b, s[last:]...)), len(matches)
+}
+
+// Redactor masks sensitive values in log messages before they reach any sink. It applies a fixed
+// rule set, DefaultRedactionRules unless configured otherwise, plus the masking rules currently
+// enforced by governance, so tenant identifiers masked in telemetry are masked in logs too. The
+// zero value applies DefaultRedactionRules.
+type Redactor struct {
+	mu         sync.Mutex
+	configured bool
+	fixed      []compiledRule
+	masking    func() []string
+	maskingKey string
+	compiled   []compiledRule // Compiled masking rules for maskingKey
+
+	hits    metrics.Counter // rule
+	invalid metrics.Gauge
+}
+
+// SetMetrics counts the values masked by each rule. Governance masking rules are labelled with
+// their expression. A nil provider disables instrumentation.
+func (r *Redactor) SetMetrics(provider metrics.Provider) {
+	provider = metrics.OrNop(provider)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits = provider.Counter("log_redactions_total", "Values masked in log messages by redaction rule.", "rule")
+	r.invalid = provider.Gauge("log_redaction_invalid_rules", "Governance masking rules that are not valid regular expressions and are not applied to logs.")
+}
+
+// SetRules replaces the fixed rule set. Nil restores DefaultRedactionRules; an empty slice applies
+// none.
+func (r *Redactor) SetRules(rules []RedactionRule) error {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixed, r.configured = compiled, true
+	return nil
+}
+
+func compileRules(rules []RedactionRule) ([]compiledRule, error) {
+	if rules == nil {
+		rules = DefaultRedactionRules
+	}
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction rule '%s': %w", rule.Name, err)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = redactedValue
+		}
+		compiled = append(compiled, compiledRule{name: rule.Name, re: re, replacement: replacement})
+	}
+	return compiled, nil
+}
+
+// SetMaskingRules makes the redactor also apply the regular expressions returned by rules, e.g.
+// the MaskingRules returned by the governance state's GetPolicies. rules is consulted on every message, so policy updates
+// apply at once; invalid expressions are skipped and reported by the log_redaction_invalid_rules
+// gauge, since a logger cannot fail.
+func (r *Redactor) SetMaskingRules(rules func() []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.masking, r.maskingKey, r.compiled = rules, "", nil
+}
+
+// rules returns the fixed and masking rules to apply, recompiling the masking rules when they
+// changed, and the hit counter. The masking rules source is called without holding the lock, as it
+// may take locks of its own around logging calls.
+func (r *Redactor) rules() (fixed, masking []compiledRule, hits metrics.Counter) {
+	r.mu.Lock()
+	if !r.configured {
+		r.fixed, _ = compileRules(nil)
+		r.configured = true
+	}
+	source := r.masking
+	fixed, hits = r.fixed, r.hits
+	r.mu.Unlock()
+	if source == nil {
+		return fixed, nil, hits
+	}
+	current := source()
+	key := fmt.Sprintf("%q", current)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key != r.maskingKey || r.compiled == nil {
+		compiled := make([]compiledRule, 0, len(current))
+		invalid := 0
+		for _, rule := range current {
+			re, err := regexp.Compile(rule)
+			if err != nil {
+				invalid++
+				continue
+			}
+			compiled = append(compiled, compiledRule{name: rule, re: re, replacement: redactedValue})
+		}
+		r.maskingKey, r.compiled = key, compiled
+		if r.invalid != nil {
+			r.invalid.Set(float64(invalid))
+		}
+	}
+	return fixed, r.compiled, hits
+}
+
+// Redact returns msg with every value matched by a rule masked.
+func (r *Redactor) Redact(msg string) string {
+	fixed, masking, hits := r.rules()
+	for _, set := range [][]compiledRule{fixed, masking} {
+		for _, rule := range set {
+			var n int
+			if msg, n = rule.apply(msg); n > 0 && hits != nil {
+				hits.Add(float64(n), rule.name)
+			}
+		}
+	}
+	return msg
+}
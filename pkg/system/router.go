@@ -0,0 +1,80 @@
+package system
+
+import (
+	"errors"
+	"sync"
+)
+
+// Router hands out loggers whose lines go to a per-component sink, or to the default sink for
+// components without one. Its loggers share one set of Levels, which can be changed at runtime,
+// and one Redactor.
+type Router struct {
+	format     Format
+	fallback   Sink
+	components map[string]Sink
+	levels     *Levels
+	redactor   *Redactor
+	tail       *Tail
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewRouter routes every component's lines to fallback (StdSink if nil) unless components maps
+// it to a sink of its own. Sinks may be shared between components.
+func NewRouter(format Format, fallback Sink, components map[string]Sink) *Router {
+	if fallback == nil {
+		fallback = StdSink{}
+	}
+	return &Router{format: format, fallback: fallback, components: components, levels: &Levels{}, redactor: &Redactor{}}
+}
+
+// Levels returns the levels applied by the router's loggers.
+func (r *Router) Levels() *Levels {
+	return r.levels
+}
+
+// Redactor returns the redactor masking the messages of the router's loggers.
+func (r *Router) Redactor() *Redactor {
+	return r.redactor
+}
+
+// KeepTail makes the loggers handed out from now on record their lines in a tail of the last lines
+// lines, across all components, and returns it.
+func (r *Router) KeepTail(lines int) *Tail {
+	r.tail = NewTail(lines)
+	return r.tail
+}
+
+// Logger returns a logger for component writing to its routed sink.
+func (r *Router) Logger(component string) *DefaultLogger {
+	sink, ok := r.components[component]
+	if !ok {
+		sink = r.fallback
+	}
+	if r.tail != nil {
+		sink = r.tail.Wrap(sink)
+	}
+	l := NewSinkLogger(component, r.format, sink)
+	l.levels, l.redactor = r.levels, r.redactor
+	return l
+}
+
+// Close closes every sink once.
+func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	closed := map[Sink]bool{r.fallback: true}
+	errs := []error{r.fallback.Close()}
+	for _, sink := range r.components {
+		if !closed[sink] {
+			closed[sink] = true
+			errs = append(errs, sink.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,156 @@
+package system
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log line.
+type Level int
+
+// Log levels, in increasing severity.
+const (
+	LevelDebug Level = iota - 1
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level as printed in text lines, e.g. "INFO".
+func (l Level) String() string {
+	switch l {
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelDebug:
+		return "DEBUG"
+	}
+	return "INFO"
+}
+
+// Sink receives rendered log lines, without the trailing newline. Sinks are safe for concurrent use.
+type Sink interface {
+	WriteLog(level Level, line []byte) error
+	Close() error
+}
+
+// StdSink writes INFO and WARN lines to stdout and ERROR lines to stderr. It is the default sink.
+type StdSink struct{}
+
+// WriteLog implements Sink.
+func (StdSink) WriteLog(level Level, line []byte) error {
+	w := os.Stdout
+	if level >= LevelError {
+		w = os.Stderr
+	}
+	return writeLine(w, line)
+}
+
+// Close implements Sink; the standard streams stay open.
+func (StdSink) Close() error { return nil }
+
+// lineMu serializes writes to shared streams so concurrent lines never interleave.
+var lineMu sync.Mutex
+
+func writeLine(w io.Writer, line []byte) error {
+	lineMu.Lock()
+	defer lineMu.Unlock()
+	_, err := w.Write(append(line, '\n'))
+	return err
+}
+
+// RotatingFile is a Sink appending to a file that is rotated once it exceeds MaxSize bytes or is
+// older than MaxAge, whichever comes first. Rotated files are renamed to <path>.<timestamp> and
+// only the newest MaxBackups are kept.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64         // Zero disables size-based rotation
+	MaxAge     time.Duration // Zero disables time-based rotation
+	MaxBackups int           // Zero keeps every rotated file
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// WriteLog implements Sink, opening the file on first use.
+func (f *RotatingFile) WriteLog(level Level, line []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+	n := int64(len(line) + 1)
+	if (f.MaxSize > 0 && f.size > 0 && f.size+n > f.MaxSize) || (f.MaxAge > 0 && time.Since(f.openedAt) >= f.MaxAge) {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	written, err := f.file.Write(append(line, '\n'))
+	f.size += int64(written)
+	return err
+}
+
+// open appends to the file, creating it and its directory as needed. Callers hold f.mu.
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return fmt.Errorf("log file %s: %w", f.Path, err)
+	}
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("log file %s: %w", f.Path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("log file %s: %w", f.Path, err)
+	}
+	f.file, f.size, f.openedAt = file, info.Size(), info.ModTime()
+	if f.size == 0 {
+		f.openedAt = time.Now()
+	}
+	return nil
+}
+
+// rotate renames the current file aside, prunes old backups, and opens a new file. Callers hold f.mu.
+func (f *RotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+	backup := f.Path + "." + time.Now().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(f.Path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("log file %s: rotation failed: %w", f.Path, err)
+	}
+	if f.MaxBackups > 0 {
+		backups, _ := filepath.Glob(f.Path + ".*")
+		sort.Strings(backups) // Timestamps sort chronologically
+		for len(backups) > f.MaxBackups {
+			if strings.HasPrefix(backups[0], f.Path+".") {
+				os.Remove(backups[0])
+			}
+			backups = backups[1:]
+		}
+	}
+	return f.open()
+}
+
+// Close implements Sink.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,122 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(data))
+}
+
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "sts.log")
+	f := &RotatingFile{Path: path, MaxSize: 10}
+	defer f.Close()
+	for _, line := range []string{"one", "two", "three", "four"} {
+		if err := f.WriteLog(LevelInfo, []byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "one\ntwo\n" fills 8 of 10 bytes, so "three" starts a new file, as does "four".
+	if got := readLines(t, path); len(got) != 1 || got[0] != "four" {
+		t.Errorf("current file = %v, want [four]", got)
+	}
+	rotated := backups(t, path)
+	if len(rotated) != 2 {
+		t.Fatalf("backups = %v, want 2", rotated)
+	}
+	if got := readLines(t, rotated[0]); strings.Join(got, " ") != "one two" {
+		t.Errorf("oldest backup = %v, want [one two]", got)
+	}
+}
+
+func TestRotatingFile_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sts.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	// The age of an existing file is taken from its modification time.
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	f := &RotatingFile{Path: path, MaxAge: time.Hour}
+	defer f.Close()
+	for _, line := range []string{"new", "newer"} {
+		if err := f.WriteLog(LevelInfo, []byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readLines(t, path); strings.Join(got, " ") != "new newer" {
+		t.Errorf("current file = %v, want [new newer]", got)
+	}
+	if rotated := backups(t, path); len(rotated) != 1 || strings.Join(readLines(t, rotated[0]), " ") != "old" {
+		t.Errorf("backups = %v, want one holding the old line", rotated)
+	}
+}
+
+func TestRotatingFile_PrunesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sts.log")
+	f := &RotatingFile{Path: path, MaxSize: 1, MaxBackups: 2}
+	defer f.Close()
+	for i := 0; i < 5; i++ {
+		if err := f.WriteLog(LevelInfo, []byte{byte('a' + i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rotated := backups(t, path)
+	if len(rotated) != 2 {
+		t.Fatalf("backups = %v, want the newest 2", rotated)
+	}
+	for i, want := range []string{"c", "d"} {
+		if got := readLines(t, rotated[i]); len(got) != 1 || got[0] != want {
+			t.Errorf("backup %d = %v, want [%s]", i, got, want)
+		}
+	}
+}
+
+func TestRouter_RoutesByComponent(t *testing.T) {
+	fallback, audit := &recordingSink{}, &recordingSink{}
+	r := NewRouter(FormatLogfmt, fallback, map[string]Sink{"audit": audit, "audit-replica": audit})
+	tail := r.KeepTail(10)
+	r.Logger("audit").Infof("granted")
+	r.Logger("sts").Warnf("stale")
+
+	if len(audit.lines) != 1 || !strings.Contains(audit.lines[0], "msg=granted") {
+		t.Errorf("audit sink = %v, want the audit line", audit.lines)
+	}
+	if len(fallback.lines) != 1 || !strings.Contains(fallback.lines[0], "component=sts") {
+		t.Errorf("fallback sink = %v, want the sts line", fallback.lines)
+	}
+	if got := tail.Lines(); len(got) != 2 {
+		t.Errorf("tail = %v, want both lines", got)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	if audit.closed != 1 || fallback.closed != 1 {
+		t.Errorf("Close calls = audit %d, fallback %d; want each sink closed once", audit.closed, fallback.closed)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package system
+
+import "errors"
+
+// SyslogSink is unsupported on this platform.
+type SyslogSink struct{}
+
+// NewSyslogSink always fails on this platform.
+func NewSyslogSink(network, addr, tag string) (*SyslogSink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+// WriteLog implements Sink.
+func (s *SyslogSink) WriteLog(level Level, line []byte) error {
+	return errors.New("syslog is not supported on this platform")
+}
+
+// Close implements Sink.
+func (s *SyslogSink) Close() error { return nil }
@@ -0,0 +1,41 @@
+//go:build !windows && !plan9
+
+package system
+
+import (
+	"log/syslog"
+)
+
+// SyslogSink sends log lines to syslog, mapping ERROR, WARN and INFO to the err, warning and info
+// priorities.
+type SyslogSink struct {
+	w *syslog.Writer
+}
+
+// NewSyslogSink connects to the syslog daemon at addr over network ("udp", "tcp", or "unixgram"),
+// or to the local daemon if both are empty. tag identifies the process (default the program name).
+func NewSyslogSink(network, addr, tag string) (*SyslogSink, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_DAEMON|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// WriteLog implements Sink.
+func (s *SyslogSink) WriteLog(level Level, line []byte) error {
+	switch level {
+	case LevelError:
+		return s.w.Err(string(line))
+	case LevelWarn:
+		return s.w.Warning(string(line))
+	case LevelDebug:
+		return s.w.Debug(string(line))
+	}
+	return s.w.Info(string(line))
+}
+
+// Close implements Sink.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
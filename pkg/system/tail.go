@@ -0,0 +1,38 @@
+package system
+
+import "pkg/ringbuf"
+
+// Tail keeps the most recent log lines in memory, e.g. for incident bundles. Lines are stored as
+// rendered, after redaction. It is safe for concurrent use.
+type Tail struct {
+	lines *ringbuf.Buffer[string]
+}
+
+// NewTail creates a tail keeping the last lines lines.
+func NewTail(lines int) *Tail {
+	return &Tail{lines: ringbuf.New[string](lines, ringbuf.Overwrite)}
+}
+
+// Lines returns the kept lines, oldest first.
+func (t *Tail) Lines() []string {
+	return t.lines.Last(t.lines.Cap())
+}
+
+// Wrap returns a sink recording every line in the tail before passing it to next.
+func (t *Tail) Wrap(next Sink) Sink {
+	return &tailSink{next: next, tail: t}
+}
+
+type tailSink struct {
+	next Sink
+	tail *Tail
+}
+
+// WriteLog implements Sink.
+func (s *tailSink) WriteLog(level Level, line []byte) error {
+	s.tail.lines.Push(string(line))
+	return s.next.WriteLog(level, line)
+}
+
+// Close implements Sink, closing the wrapped sink.
+func (s *tailSink) Close() error { return s.next.Close() }
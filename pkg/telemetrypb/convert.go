@@ -0,0 +1,77 @@
+// Package telemetrypb holds the protobuf wire format for STS telemetry and governance state,
+// together with converters to and from the native Go types.
+package telemetrypb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative telemetry.proto
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"services/telemetry"
+)
+
+var severityToProto = map[telemetry.Severity]Severity{
+	telemetry.SeverityOK:       Severity_SEVERITY_OK,
+	telemetry.SeverityWarn:     Severity_SEVERITY_WARN,
+	telemetry.SeverityDegraded: Severity_SEVERITY_DEGRADED,
+	telemetry.SeverityCritical: Severity_SEVERITY_CRITICAL,
+}
+
+var severityFromProto = map[Severity]telemetry.Severity{
+	Severity_SEVERITY_OK:       telemetry.SeverityOK,
+	Severity_SEVERITY_WARN:     telemetry.SeverityWarn,
+	Severity_SEVERITY_DEGRADED: telemetry.SeverityDegraded,
+	Severity_SEVERITY_CRITICAL: telemetry.SeverityCritical,
+}
+
+// FromTelemetry converts a native snapshot to its wire representation.
+func FromTelemetry(td telemetry.TelemetryData) *TelemetryData {
+	return &TelemetryData{
+		Timestamp:         timestamppb.New(td.Timestamp),
+		PipelineLatencyS9: td.PipelineLatencyS9.Seconds(),
+		ResourceLoadPct:   td.ResourceLoad_Pct,
+		HashChainStatus:   td.IntegrityHashChainStatus,
+		GatmBreachCount:   int64(td.GATMBreachCount),
+		IsGatmViolating:   td.IsGATMViolating,
+		ViolationCauses:   td.ViolationCauses,
+		IsSilenced:        td.IsSilenced,
+		IsAcknowledged:    td.IsAcknowledged,
+		Severity:          severityToProto[td.Severity],
+		Labels:            td.Labels,
+	}
+}
+
+// ToTelemetry converts a wire snapshot to the native type. Unknown severities map to "".
+func ToTelemetry(pb *TelemetryData) telemetry.TelemetryData {
+	return telemetry.TelemetryData{
+		Timestamp:                pb.GetTimestamp().AsTime(),
+		PipelineLatencyS9:        telemetry.DurationFromSeconds(pb.GetPipelineLatencyS9()),
+		ResourceLoad_Pct:         pb.GetResourceLoadPct(),
+		IntegrityHashChainStatus: pb.GetHashChainStatus(),
+		GATMBreachCount:          int(pb.GetGatmBreachCount()),
+		IsGATMViolating:          pb.GetIsGatmViolating(),
+		ViolationCauses:          pb.GetViolationCauses(),
+		IsSilenced:               pb.GetIsSilenced(),
+		IsAcknowledged:           pb.GetIsAcknowledged(),
+		Severity:                 severityFromProto[pb.GetSeverity()],
+		Labels:                   pb.GetLabels(),
+	}
+}
+
+// FromTelemetryBatch converts a slice of native snapshots into a batch for nodeID.
+func FromTelemetryBatch(nodeID string, records []telemetry.TelemetryData) *TelemetryBatch {
+	batch := &TelemetryBatch{NodeId: nodeID, Records: make([]*TelemetryData, 0, len(records))}
+	for _, td := range records {
+		batch.Records = append(batch.Records, FromTelemetry(td))
+	}
+	return batch
+}
+
+// ToTelemetryBatch converts a wire batch to native snapshots, ordered as received.
+func ToTelemetryBatch(batch *TelemetryBatch) []telemetry.TelemetryData {
+	records := make([]telemetry.TelemetryData, 0, len(batch.GetRecords()))
+	for _, pb := range batch.GetRecords() {
+		records = append(records, ToTelemetry(pb))
+	}
+	return records
+}
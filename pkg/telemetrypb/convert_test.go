@@ -0,0 +1,37 @@
+package telemetrypb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"services/telemetry"
+)
+
+func TestTelemetryRoundTrip(t *testing.T) {
+	td := telemetry.TelemetryData{
+		Timestamp:                time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		PipelineLatencyS9:        1500 * time.Millisecond,
+		ResourceLoad_Pct:         0.75,
+		IntegrityHashChainStatus: "SYNCED",
+		GATMBreachCount:          2,
+		IsGATMViolating:          true,
+		ViolationCauses:          []string{"latency"},
+		Severity:                 telemetry.SeverityCritical,
+		Labels:                   map[string]string{"region": "eu-west-1"},
+	}
+	wire, err := proto.Marshal(FromTelemetryBatch("node-a", []telemetry.TelemetryData{td}))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var batch TelemetryBatch
+	if err := proto.Unmarshal(wire, &batch); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	got := ToTelemetryBatch(&batch)
+	if batch.GetNodeId() != "node-a" || len(got) != 1 || !reflect.DeepEqual(got[0], td) {
+		t.Errorf("round trip = %s %+v, want %+v", batch.GetNodeId(), got, td)
+	}
+}
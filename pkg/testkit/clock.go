@@ -0,0 +1,86 @@
+package testkit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"services/telemetry"
+)
+
+// FakeClock is a manually advanced clock.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a clock starting at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// ClockedSource wraps a TelemetrySource and stamps each snapshot with the fake clock's time, so
+// time-based STS behavior (silences, acknowledgement expiry, burn-rate windows) follows the clock.
+type ClockedSource struct {
+	Source telemetry.TelemetrySource
+	Clock  *FakeClock
+}
+
+// Collect implements telemetry.TelemetrySource.
+func (s *ClockedSource) Collect(ctx context.Context) (telemetry.TelemetryData, error) {
+	td, err := s.Source.Collect(ctx)
+	if err != nil {
+		return td, err
+	}
+	td.Timestamp = s.Clock.Now()
+	return td, nil
+}
+
+// Driver steps an STS through collection cycles on a fake clock instead of its Run ticker.
+type Driver struct {
+	STS   telemetry.STS
+	Clock *FakeClock
+}
+
+// NewDriver builds an STS over src whose snapshots are stamped by clock.
+func NewDriver(cfg telemetry.STSConfiguration, src telemetry.TelemetrySource, clock *FakeClock) *Driver {
+	return &Driver{
+		STS:   telemetry.NewSovereignTelemetryService(cfg, &ClockedSource{Source: src, Clock: clock}),
+		Clock: clock,
+	}
+}
+
+// Step advances the clock by d and runs one collection cycle, returning the resulting snapshot.
+func (d *Driver) Step(ctx context.Context, dt time.Duration) (telemetry.TelemetryData, error) {
+	d.Clock.Advance(dt)
+	err := d.STS.CollectNow(ctx)
+	return d.STS.GetHealthStatus(), err
+}
+
+// StepN runs n cycles spaced dt apart and returns the final snapshot, stopping at the first error.
+func (d *Driver) StepN(ctx context.Context, n int, dt time.Duration) (telemetry.TelemetryData, error) {
+	var (
+		td  telemetry.TelemetryData
+		err error
+	)
+	for i := 0; i < n; i++ {
+		if td, err = d.Step(ctx, dt); err != nil {
+			return td, err
+		}
+	}
+	return td, nil
+}
@@ -0,0 +1,69 @@
+// Package testkit provides fakes for integration-testing STS and governance wiring without real
+// network dependencies: an in-memory logger, fake policy and metric HTTP servers, and a fake clock
+// for driving STS collection deterministically.
+package testkit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Log levels recorded by MemoryLogger.
+const (
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+)
+
+// Entry is a single captured log line.
+type Entry struct {
+	Level   string
+	Message string
+}
+
+// MemoryLogger captures log entries in memory. It satisfies the Logger interfaces used across the
+// repo (Infof, Warnf, Errorf).
+type MemoryLogger struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryLogger creates an empty logger.
+func NewMemoryLogger() *MemoryLogger {
+	return &MemoryLogger{}
+}
+
+func (l *MemoryLogger) log(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, Entry{Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *MemoryLogger) Infof(format string, args ...interface{})  { l.log(LevelInfo, format, args...) }
+func (l *MemoryLogger) Warnf(format string, args ...interface{})  { l.log(LevelWarn, format, args...) }
+func (l *MemoryLogger) Errorf(format string, args ...interface{}) { l.log(LevelError, format, args...) }
+
+// Entries returns a copy of every captured entry, oldest first.
+func (l *MemoryLogger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Contains reports whether an entry at level contains substr. An empty level matches any level.
+func (l *MemoryLogger) Contains(level, substr string) bool {
+	for _, e := range l.Entries() {
+		if (level == "" || e.Level == level) && strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards captured entries.
+func (l *MemoryLogger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
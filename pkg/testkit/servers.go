@@ -0,0 +1,103 @@
+package testkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// PolicyServer is a fake governance policy endpoint serving a JSON document.
+type PolicyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	body     []byte
+	status   int
+	requests atomic.Int64
+}
+
+// NewPolicyServer starts a server returning doc (JSON-encoded) with 200 OK. Call Close when done.
+func NewPolicyServer(doc interface{}) *PolicyServer {
+	s := &PolicyServer{status: http.StatusOK}
+	s.SetDocument(doc)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		status, body := s.status, s.body
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	return s
+}
+
+// SetDocument replaces the served document. A []byte or string is served verbatim, which allows
+// serving deliberately malformed JSON.
+func (s *PolicyServer) SetDocument(doc interface{}) {
+	var body []byte
+	switch v := doc.(type) {
+	case []byte:
+		body = v
+	case string:
+		body = []byte(v)
+	default:
+		var err error
+		if body, err = json.Marshal(v); err != nil {
+			panic(fmt.Sprintf("testkit: cannot encode policy document: %v", err))
+		}
+	}
+	s.mu.Lock()
+	s.body = body
+	s.mu.Unlock()
+}
+
+// SetStatus makes subsequent responses use the given HTTP status code.
+func (s *PolicyServer) SetStatus(code int) {
+	s.mu.Lock()
+	s.status = code
+	s.mu.Unlock()
+}
+
+// Requests returns how many requests have been served.
+func (s *PolicyServer) Requests() int {
+	return int(s.requests.Load())
+}
+
+// MetricsServer is a fake metrics endpoint serving gauges in the Prometheus text format.
+type MetricsServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewMetricsServer starts an empty metrics endpoint. Call Close when done.
+func NewMetricsServer() *MetricsServer {
+	s := &MetricsServer{values: make(map[string]float64)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.values))
+		for name := range s.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		for _, name := range names {
+			fmt.Fprintf(w, "%s %g\n", name, s.values[name])
+		}
+		s.mu.Unlock()
+	}))
+	return s
+}
+
+// Set publishes a metric value, e.g., Set(`node_load1`, 0.5) or Set(`up{job="sts"}`, 1).
+func (s *MetricsServer) Set(name string, value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[name] = value
+}
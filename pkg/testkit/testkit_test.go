@@ -0,0 +1,56 @@
+package testkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"services/telemetry"
+)
+
+type constantSource struct{ td telemetry.TelemetryData }
+
+func (s constantSource) Collect(context.Context) (telemetry.TelemetryData, error) { return s.td, nil }
+
+func TestDriverEscalatesOnFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	src := constantSource{telemetry.TelemetryData{PipelineLatencyS9: 5 * time.Second, IntegrityHashChainStatus: "SYNCED"}}
+	d := NewDriver(telemetry.STSConfiguration{MaxBreaches: 3}, src, clock)
+
+	td, err := d.StepN(context.Background(), 3, time.Minute)
+	if err != nil {
+		t.Fatalf("StepN() error = %v", err)
+	}
+	if !td.Timestamp.Equal(start.Add(3 * time.Minute)) {
+		t.Errorf("Timestamp = %v, want %v", td.Timestamp, start.Add(3*time.Minute))
+	}
+	if !d.STS.CheckGATMViolation() {
+		t.Errorf("CheckGATMViolation() = false after %d breaches", td.GATMBreachCount)
+	}
+}
+
+func TestPolicyServer(t *testing.T) {
+	s := NewPolicyServer(map[string]interface{}{"masking_rules": []string{"secret"}})
+	defer s.Close()
+
+	resp, err := http.Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "secret") || s.Requests() != 1 {
+		t.Errorf("body = %s, requests = %d", body, s.Requests())
+	}
+
+	s.SetStatus(http.StatusServiceUnavailable)
+	resp, _ = http.Get(s.URL)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want 503", resp.StatusCode)
+	}
+}
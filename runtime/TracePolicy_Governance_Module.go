@@ -3,11 +3,30 @@ package governance
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"internal/events"
+	"pkg/discovery"
+	"pkg/errreport"
+	"pkg/expand"
+	"pkg/featureflag"
+	"pkg/jitter"
+	"pkg/metrics"
+	"pkg/strictjson"
+	"services/telemetry"
+)
+
+// Sentinel errors for policy retrieval and freshness; match them with errors.Is.
+var (
+	ErrPolicyFetchFailed     = errors.New("policy fetch failed")
+	ErrInvalidPolicyDocument = errors.New("invalid JSON policy structure")
+	ErrStaleGovernanceState  = errors.New("governance state is stale")
 )
 
 // Logger defines the interface required for internal component logging.
@@ -21,7 +40,11 @@ type Logger interface {
 type GovernanceState struct {
 	SamplingRates map[string]float64 `json:"sampling_rates"` // Key: Span/Service Name, Value: Sample probability (0.0 - 1.0)
 	MaskingRules  []string           `json:"masking_rules"`  // Regular expressions or rule names for data redaction
+	Silences      []SilenceWindow    `json:"silences"`       // Planned maintenance windows suppressing GATM escalation
+	Thresholds    *ThresholdOverrides `json:"thresholds,omitempty"` // GATM threshold changes, subject to STS admission
+	FeatureFlags  map[string]featureflag.Flag `json:"feature_flags,omitempty"` // Rollout of gated behaviors, overriding the configured flags
 	LastUpdated   time.Time
+	Generation    uint64       `json:"-"` // Incremented by every successful update; 0 until the first
 	mu            sync.RWMutex // Protects read/write access to policy data
 }
 
@@ -32,6 +55,44 @@ func (gs *GovernanceState) GetPolicies() (map[string]float64, []string) {
 	return gs.SamplingRates, gs.MaskingRules
 }
 
+// CheckFreshness returns ErrStaleGovernanceState if the state was never loaded or is older than maxAge.
+func (gs *GovernanceState) CheckFreshness(maxAge time.Duration, now time.Time) error {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	if gs.LastUpdated.IsZero() {
+		return fmt.Errorf("%w: never updated", ErrStaleGovernanceState)
+	}
+	if age := now.Sub(gs.LastUpdated); age > maxAge {
+		return fmt.Errorf("%w: last updated %v ago (max %v)", ErrStaleGovernanceState, age.Round(time.Second), maxAge)
+	}
+	return nil
+}
+
+// SilenceWindow is a maintenance window declared in the governance policy document.
+// Matchers select GATM violations by label, including the "cause" label.
+type SilenceWindow struct {
+	Matchers map[string]string `json:"matchers"`
+	StartsAt time.Time         `json:"starts_at"`
+	EndsAt   time.Time         `json:"ends_at"`
+	Comment  string            `json:"comment"`
+}
+
+// ThresholdOverrides are GATM thresholds pushed by the governance policy document. Omitted fields
+// leave the running thresholds unchanged.
+type ThresholdOverrides struct {
+	LatencyThreshold  string  `json:"latency_threshold,omitempty"` // e.g. "250ms"
+	LoadThreshold     float64 `json:"load_threshold,omitempty"`
+	MaxBreaches       int     `json:"max_breaches,omitempty"`
+	BreachDecayFactor float64 `json:"breach_decay_factor,omitempty"`
+}
+
+// GetSilences retrieves the maintenance windows from the current governance state.
+func (gs *GovernanceState) GetSilences() []SilenceWindow {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	return gs.Silences
+}
+
 // PolicySource defines the contract for fetching remote policy data (Dependency Injection).
 type HTTPClient interface {
 	Get(ctx context.Context, url string) ([]byte, error)
@@ -73,6 +134,92 @@ type TracePolicyGovernanceModule struct {
 	State     *GovernanceState
 	Client    HTTPClient
 	Log       Logger
+
+	// Endpoints, if set, locates the policy servers (e.g. through DNS SRV or Consul) and fails over
+	// among them; ConfigURL is then only used in logs. NewTracePolicyGovernanceModule sets it for
+	// "srv+" and "consul+" URLs.
+	Endpoints *discovery.Failover
+
+	// Silences, if set, is the STS silence registry: every update replaces its
+	// telemetry.SilenceSourcePolicy silences with the document's maintenance windows.
+	Silences *telemetry.SilenceRegistry
+
+	// OnSilencesUpdated, if set, receives the policy document's silences after every successful update.
+	OnSilencesUpdated func(silences []SilenceWindow)
+
+	// OnThresholdsUpdated, if set, receives the document's thresholds whenever they change, e.g. to
+	// apply them through the STS threshold admission policy (see admin.UpdateThresholds). A returned
+	// error means the change was rejected; the rest of the document is still applied.
+	OnThresholdsUpdated func(thresholds ThresholdOverrides) error
+
+	// Flags, if set, receives the document's feature flags as featureflag.SourceGovernance on every
+	// update. While its featureflag.PushGovernance flag is off, pushed thresholds are held back:
+	// OnThresholdsUpdated is not called and State.Thresholds keeps the last ones applied.
+	Flags *featureflag.Set
+
+	// OnChange, if set, receives the diff of every update that changed sampling rates or masking rules.
+	OnChange func(diff GovernanceDiff)
+	// MaxChangeHistory bounds the diffs retained for Changes (default 100).
+	MaxChangeHistory int
+
+	// StartupSplay randomly delays the first fetch of StartPolicyPolling by up to this long.
+	StartupSplay time.Duration
+	// Jitter perturbs each polling interval by up to this fraction (0.0 - 1.0), e.g. 0.1 for ±10%.
+	Jitter float64
+
+	// MaxPolicyAge raises a staleness alarm when policies have not refreshed successfully for this
+	// long, so sampling and masking rules do not silently go stale. Zero disables the alarm.
+	MaxPolicyAge time.Duration
+	// OnStale, if set, is called when the state becomes stale, e.g. to raise a GATM-style warning.
+	OnStale func(f Freshness)
+
+	// Bus, if set, receives a GovernanceUpdated event after every applied update and a
+	// GovernanceStale event whenever the staleness alarm is raised.
+	Bus *events.Bus
+
+	// OverridesPath names a local file, e.g. /etc/sts/governance-overrides.json, whose values take
+	// precedence over the fetched policy document; see GovernanceOverrides. It is re-read on every
+	// update, and ApplyOverrides applies edits without waiting for the next fetch.
+	OverridesPath string
+
+	// ErrorReporter, if set, receives policy load failures (failed fetches, invalid documents and
+	// override files) and panics of the polling goroutine.
+	ErrorReporter errreport.ErrorReporter
+
+	// Strict rejects policy documents containing unknown fields (e.g. a misspelled "masking_rules")
+	// instead of silently dropping them.
+	Strict bool
+
+	// Expander, if set, expands ${VAR} references in fetched documents and the overrides file before
+	// decoding them (see package expand). A document referencing an unset variable is rejected like
+	// invalid JSON.
+	Expander *expand.Expander
+
+	fetches     metrics.Counter // result: ok|fetch_error|invalid
+	lastSuccess metrics.Gauge   // Unix time of the last successful update
+	generation  metrics.Gauge
+	staleGauge  metrics.Gauge // 1 while the staleness alarm is raised
+
+	stale atomic.Bool
+
+	applyMu sync.Mutex
+	fetched *policyDocument // Last fetched document, before overrides
+
+	overridesMu sync.Mutex
+	overrides   *GovernanceOverrides // Last valid override file
+	overridden  []OverriddenValue
+
+	historyMu sync.Mutex
+	history   []GovernanceDiff // Applied changes, oldest first
+}
+
+// SetMetrics instruments policy fetches. A nil provider disables instrumentation.
+func (p *TracePolicyGovernanceModule) SetMetrics(provider metrics.Provider) {
+	provider = metrics.OrNop(provider)
+	p.fetches = provider.Counter("governance_policy_fetches_total", "Governance policy fetch attempts by result.", "result")
+	p.lastSuccess = provider.Gauge("governance_policy_last_update_timestamp_seconds", "Unix time of the last successful governance policy update.")
+	p.generation = provider.Gauge("governance_policy_generation", "Generation of the applied governance policies.")
+	p.staleGauge = provider.Gauge("governance_policy_stale", "1 if the governance policies are older than the maximum age, else 0.")
 }
 
 // NewTracePolicyGovernanceModule initializes and returns a configured module instance.
@@ -86,7 +233,7 @@ func NewTracePolicyGovernanceModule(url string, client HTTPClient, logger Logger
         // Fallback for scenarios where a default logger isn't injected, though discouraged
         // In a real system, this would panic or use a NoOp logger.
     }
-    return &TracePolicyGovernanceModule{
+    module := &TracePolicyGovernanceModule{
         ConfigURL: url,
         State:     &GovernanceState{
             SamplingRates: make(map[string]float64),
@@ -95,65 +242,193 @@ func NewTracePolicyGovernanceModule(url string, client HTTPClient, logger Logger
         Client: client,
         Log:    logger,
     }
+    if r, err := discovery.ParseURL(url); err == nil {
+        if _, static := r.(discovery.Static); !static {
+            module.Endpoints = discovery.NewFailover(r, 0)
+        }
+    }
+    module.SetMetrics(nil)
+    return module
+}
+
+// fetch retrieves the policy document from ConfigURL, or from the first discovered endpoint that answers.
+func (p *TracePolicyGovernanceModule) fetch(ctx context.Context) ([]byte, error) {
+	if p.Endpoints == nil {
+		return p.Client.Get(ctx, p.ConfigURL)
+	}
+	var data []byte
+	err := p.Endpoints.Do(ctx, func(endpoint string) error {
+		var err error
+		data, err = p.Client.Get(ctx, endpoint)
+		return err
+	})
+	return data, err
 }
 
 // FetchAndUpdate attempts to retrieve the latest policies and update the state atomically.
 // It includes validation checks for JSON structure integrity.
 func (p *TracePolicyGovernanceModule) FetchAndUpdate(ctx context.Context) error {
-	policyData, err := p.Client.Get(ctx, p.ConfigURL)
+	policyData, err := p.fetch(ctx)
 	if err != nil {
 		p.Log.Errorf("Error fetching policies from %s: %v", p.ConfigURL, err)
-		return fmt.Errorf("policy fetch error: %w", err)
+		p.fetches.Inc("fetch_error")
+		err = fmt.Errorf("%w: %w", ErrPolicyFetchFailed, err)
+		errreport.Report(ctx, p.ErrorReporter, errreport.KindPolicy, "governance", err)
+		return err
 	}
 
 	var newPolicies GovernanceState // Use the main state struct for unmarshaling integrity check
 
 	// Unmarshal and basic structural validation
-	if err := json.Unmarshal(policyData, &newPolicies); err != nil {
+	decode := json.Unmarshal
+	if p.Strict {
+		decode = strictjson.Unmarshal
+	}
+	if p.Expander != nil {
+		policyData, err = p.Expander.ExpandJSON(policyData)
+	}
+	if err == nil {
+		err = decode(policyData, &newPolicies)
+	}
+	if err != nil {
 		p.Log.Warnf("Fetched invalid JSON structure. Retaining previous policies. Error: %v", err)
-		return fmt.Errorf("invalid JSON policy structure: %w", err)
+		p.fetches.Inc("invalid")
+		err = fmt.Errorf("%w: %w", ErrInvalidPolicyDocument, err)
+		errreport.Report(ctx, p.ErrorReporter, errreport.KindPolicy, "governance", err)
+		return err
 	}
     
+	doc := policyDocument{
+		SamplingRates: newPolicies.SamplingRates,
+		MaskingRules:  newPolicies.MaskingRules,
+		Silences:      newPolicies.Silences,
+		Thresholds:    newPolicies.Thresholds,
+		FeatureFlags:  newPolicies.FeatureFlags,
+	}
+	p.apply(&doc)
+	p.fetches.Inc("ok")
+	return nil
+}
+
+// apply merges the local overrides over a freshly fetched document, or over the last fetched one
+// if fetched is nil, and installs the result as the governance state.
+func (p *TracePolicyGovernanceModule) apply(fetched *policyDocument) {
+	p.applyMu.Lock()
+	defer p.applyMu.Unlock()
+	if fetched != nil {
+		p.fetched = fetched
+	}
+	var doc policyDocument
+	if p.fetched != nil {
+		doc = *p.fetched
+	}
+	merged := p.mergeOverrides(doc)
+	if p.Flags != nil {
+		if err := p.Flags.ReplaceSource(featureflag.SourceGovernance, merged.FeatureFlags); err != nil {
+			p.Log.Warnf("Ignoring governance feature flags; previous flags remain in effect: %v", err)
+		}
+	}
+	// Pushed thresholds are held back while the flag is off, and applied once it turns on.
+	push := p.Flags.Enabled(featureflag.PushGovernance)
+
 	// Update state atomically
 	p.State.mu.Lock()
-	p.State.SamplingRates = newPolicies.SamplingRates
-	p.State.MaskingRules = newPolicies.MaskingRules
-	p.State.LastUpdated = time.Now()
+	thresholdsChanged := push && merged.Thresholds != nil &&
+		(p.State.Thresholds == nil || *p.State.Thresholds != *merged.Thresholds)
+	now := time.Now()
+	diff := DiffGovernance(p.State.SamplingRates, merged.SamplingRates, p.State.MaskingRules, merged.MaskingRules, now)
+	p.State.SamplingRates = merged.SamplingRates
+	p.State.MaskingRules = merged.MaskingRules
+	p.State.Silences = merged.Silences
+	if push {
+		p.State.Thresholds = merged.Thresholds
+	}
+	p.State.FeatureFlags = merged.FeatureFlags
+	if fetched != nil {
+		p.State.LastUpdated = now
+	}
+	p.State.Generation++
+	generation := p.State.Generation
 	p.State.mu.Unlock()
+	if fetched != nil {
+		p.lastSuccess.Set(float64(now.Unix()))
+	}
+	p.generation.Set(float64(generation))
+
+	if !diff.Empty() {
+		p.recordChange(diff)
+		p.Log.Infof("Governance policy changed: %s (sampling added %v, removed %v, changed %v; masking added %v, removed %v)",
+			diff, diff.SamplingAdded, diff.SamplingRemoved, diff.SamplingChanged, diff.MaskingAdded, diff.MaskingRemoved)
+		if p.OnChange != nil {
+			p.OnChange(diff)
+		}
+	}
+
+	p.applySilences(merged.Silences)
+	if p.OnSilencesUpdated != nil {
+		p.OnSilencesUpdated(merged.Silences)
+	}
+	if thresholdsChanged && p.OnThresholdsUpdated != nil {
+		if err := p.OnThresholdsUpdated(*merged.Thresholds); err != nil {
+			p.Log.Warnf("Governance threshold change rejected; previous thresholds remain in effect: %v", err)
+		}
+	}
+	if p.Bus != nil {
+		events.Publish(p.Bus, events.GovernanceUpdated, events.GovernanceUpdatedEvent{
+			SamplingRates: len(merged.SamplingRates), MaskingRules: len(merged.MaskingRules), Silences: len(merged.Silences),
+			Generation: generation, At: now,
+		})
+	}
     
     p.Log.Infof("Governance policies updated successfully. Rules: %d, Sampling rates: %d", 
-        len(p.State.MaskingRules), len(p.State.SamplingRates))
-	return nil
+        len(merged.MaskingRules), len(merged.SamplingRates))
 }
 
 // StartPolicyPolling begins the background task to update policies gracefully.
-// It executes the initial fetch immediately and then ticks at the specified interval.
+// It executes the initial fetch immediately and then ticks at the specified interval, perturbed by
+// Jitter. With a StartupSplay, the initial fetch is instead made in the background after a random
+// delay, so a fleet started together does not stampede the policy server.
 func (p *TracePolicyGovernanceModule) StartPolicyPolling(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-
     p.Log.Infof("Starting policy governance polling (interval: %v) from %s", interval, p.ConfigURL)
 
-    // Initial fetch to ensure readiness
-    if err := p.FetchAndUpdate(ctx); err != nil {
-        p.Log.Errorf("Initial policy fetch failed: %v", err)
-        // Continue polling loop, assuming eventual consistency will be achieved.
+    splay := jitter.Splay(p.StartupSplay)
+    if splay == 0 {
+        // Initial fetch to ensure readiness
+        if err := p.FetchAndUpdate(ctx); err != nil {
+            p.Log.Errorf("Initial policy fetch failed: %v", err)
+            // Continue polling loop, assuming eventual consistency will be achieved.
+        }
+        p.CheckStaleness(time.Now())
     }
-    
+
 	go func() {
-		defer ticker.Stop()
+		defer errreport.ReportPanic(ctx, p.ErrorReporter, "governance policy poller")
+		if splay > 0 {
+			if jitter.Sleep(ctx, splay) != nil {
+				return
+			}
+			if err := p.FetchAndUpdate(ctx); err != nil {
+				p.Log.Errorf("Initial policy fetch failed: %v", err)
+			}
+			p.CheckStaleness(time.Now())
+		}
+		timer := time.NewTimer(jitter.Apply(interval, p.Jitter))
+		defer timer.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				p.Log.Infof("Governance policy polling stopped gracefully.")
 				return
-			case <-ticker.C:
+			case <-timer.C:
 				// Use a short, bounded context for the fetch operation, ensuring the loop doesn't block permanently.
                 pollCtx, cancel := context.WithTimeout(ctx, interval / 2)
 				if err := p.FetchAndUpdate(pollCtx); err != nil {
                     // Specific errors are logged inside FetchAndUpdate.
 				}
                 cancel() 
+				p.CheckStaleness(time.Now())
 			}
+			timer.Reset(jitter.Apply(interval, p.Jitter))
 		}
 	}()
 }
\ No newline at end of file
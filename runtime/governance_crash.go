@@ -0,0 +1,44 @@
+package governance
+
+import (
+	"context"
+	"time"
+
+	"pkg/crashdump"
+	"pkg/featureflag"
+)
+
+// CrashSection returns the crash bundle section "governance": the generation and freshness of the
+// enforced governance state, identifying the policies in force when the process failed.
+func (p *TracePolicyGovernanceModule) CrashSection() crashdump.Section {
+	return crashdump.Section{Name: "governance", Collect: func(ctx context.Context) (interface{}, error) {
+		return p.State.Freshness(p.MaxPolicyAge, time.Now()), nil
+	}}
+}
+
+// PolicySnapshot is the enforced governance state at one point in time, with the values taken
+// from the local override file listed separately.
+type PolicySnapshot struct {
+	Freshness     Freshness                   `json:"freshness"`
+	SamplingRates map[string]float64          `json:"sampling_rates"`
+	MaskingRules  []string                    `json:"masking_rules"`
+	Silences      []SilenceWindow             `json:"silences,omitempty"`
+	Thresholds    *ThresholdOverrides         `json:"thresholds,omitempty"`
+	FeatureFlags  map[string]featureflag.Flag `json:"feature_flags,omitempty"`
+	Overrides     []OverriddenValue           `json:"overrides,omitempty"`
+}
+
+// PoliciesSection returns the incident bundle section "policies": the policies in force when the
+// incident was exported.
+func (p *TracePolicyGovernanceModule) PoliciesSection() crashdump.Section {
+	return crashdump.Section{Name: "policies", Collect: func(ctx context.Context) (interface{}, error) {
+		gs := p.State
+		snap := PolicySnapshot{Freshness: gs.Freshness(p.MaxPolicyAge, time.Now()), Overrides: p.Overrides()}
+		// Updates replace these values rather than modifying them, so they can be shared.
+		gs.mu.RLock()
+		snap.SamplingRates, snap.MaskingRules, snap.Silences = gs.SamplingRates, gs.MaskingRules, gs.Silences
+		snap.Thresholds, snap.FeatureFlags = gs.Thresholds, gs.FeatureFlags
+		gs.mu.RUnlock()
+		return snap, nil
+	}}
+}
@@ -0,0 +1,119 @@
+package governance
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// defaultChangeHistory is the number of governance changes retained when MaxChangeHistory is zero.
+const defaultChangeHistory = 100
+
+// SamplingChange is a sampling rate whose value changed between two documents.
+type SamplingChange struct {
+	Key string  `json:"key"`
+	Old float64 `json:"old"`
+	New float64 `json:"new"`
+}
+
+// GovernanceDiff describes what one applied policy document changed. Lists are sorted.
+type GovernanceDiff struct {
+	At              time.Time          `json:"at"`
+	SamplingAdded   map[string]float64 `json:"sampling_added,omitempty"`
+	SamplingRemoved map[string]float64 `json:"sampling_removed,omitempty"`
+	SamplingChanged []SamplingChange   `json:"sampling_changed,omitempty"`
+	MaskingAdded    []string           `json:"masking_added,omitempty"`
+	MaskingRemoved  []string           `json:"masking_removed,omitempty"`
+}
+
+// Empty reports whether the document changed nothing the diff tracks.
+func (d GovernanceDiff) Empty() bool {
+	return len(d.SamplingAdded) == 0 && len(d.SamplingRemoved) == 0 && len(d.SamplingChanged) == 0 &&
+		len(d.MaskingAdded) == 0 && len(d.MaskingRemoved) == 0
+}
+
+// String summarizes the diff for logs, e.g. "sampling +1 -0 ~2, masking +0 -1".
+func (d GovernanceDiff) String() string {
+	return fmt.Sprintf("sampling +%d -%d ~%d, masking +%d -%d",
+		len(d.SamplingAdded), len(d.SamplingRemoved), len(d.SamplingChanged), len(d.MaskingAdded), len(d.MaskingRemoved))
+}
+
+// DiffGovernance compares the sampling rates and masking rules of two policy documents.
+func DiffGovernance(oldRates, newRates map[string]float64, oldRules, newRules []string, at time.Time) GovernanceDiff {
+	d := GovernanceDiff{At: at}
+	for key, rate := range newRates {
+		old, ok := oldRates[key]
+		switch {
+		case !ok:
+			if d.SamplingAdded == nil {
+				d.SamplingAdded = make(map[string]float64)
+			}
+			d.SamplingAdded[key] = rate
+		case old != rate:
+			d.SamplingChanged = append(d.SamplingChanged, SamplingChange{Key: key, Old: old, New: rate})
+		}
+	}
+	for key, rate := range oldRates {
+		if _, ok := newRates[key]; !ok {
+			if d.SamplingRemoved == nil {
+				d.SamplingRemoved = make(map[string]float64)
+			}
+			d.SamplingRemoved[key] = rate
+		}
+	}
+	sort.Slice(d.SamplingChanged, func(i, j int) bool { return d.SamplingChanged[i].Key < d.SamplingChanged[j].Key })
+
+	d.MaskingAdded = missingFrom(newRules, oldRules)
+	d.MaskingRemoved = missingFrom(oldRules, newRules)
+	return d
+}
+
+// missingFrom returns the sorted, de-duplicated elements of a that are not in b.
+func missingFrom(a, b []string) []string {
+	present := make(map[string]bool, len(b))
+	for _, s := range b {
+		present[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !present[s] {
+			out = append(out, s)
+			present[s] = true
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// recordChange appends a non-empty diff to the bounded change history.
+func (p *TracePolicyGovernanceModule) recordChange(d GovernanceDiff) {
+	limit := p.MaxChangeHistory
+	if limit == 0 {
+		limit = defaultChangeHistory
+	}
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+	p.history = append(p.history, d)
+	if over := len(p.history) - limit; over > 0 {
+		p.history = append([]GovernanceDiff(nil), p.history[over:]...)
+	}
+}
+
+// Changes returns the retained governance changes, oldest first.
+func (p *TracePolicyGovernanceModule) Changes() []GovernanceDiff {
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+	return append([]GovernanceDiff(nil), p.history...)
+}
+
+// ChangesBetween returns the retained changes applied within [from, to], oldest first, e.g. to
+// answer what changed around the start of an incident.
+func (p *TracePolicyGovernanceModule) ChangesBetween(from, to time.Time) []GovernanceDiff {
+	var out []GovernanceDiff
+	for _, d := range p.Changes() {
+		if !d.At.Before(from) && !d.At.After(to) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
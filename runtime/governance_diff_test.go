@@ -0,0 +1,105 @@
+package governance
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffGovernance(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		oldRates, newRates map[string]float64
+		oldRules, newRules []string
+		want               GovernanceDiff
+	}{
+		{name: "Unchanged", oldRates: map[string]float64{"a": 0.5}, newRates: map[string]float64{"a": 0.5}, oldRules: []string{"x"}, newRules: []string{"x"}, want: GovernanceDiff{At: at}},
+		{name: "First Document", newRates: map[string]float64{"a": 0.5}, newRules: []string{"x"}, want: GovernanceDiff{At: at, SamplingAdded: map[string]float64{"a": 0.5}, MaskingAdded: []string{"x"}}},
+		{
+			name:     "Sampling",
+			oldRates: map[string]float64{"a": 0.5, "b": 0.1, "c": 1, "d": 0.2},
+			newRates: map[string]float64{"a": 0.5, "c": 0.3, "d": 0.4, "e": 0.9},
+			want: GovernanceDiff{
+				At:              at,
+				SamplingAdded:   map[string]float64{"e": 0.9},
+				SamplingRemoved: map[string]float64{"b": 0.1},
+				SamplingChanged: []SamplingChange{{Key: "c", Old: 1, New: 0.3}, {Key: "d", Old: 0.2, New: 0.4}},
+			},
+		},
+		{
+			name:     "Masking Reordered And Duplicated",
+			oldRules: []string{"y", "x", "z"},
+			newRules: []string{"z", "w", "x", "w"},
+			want:     GovernanceDiff{At: at, MaskingAdded: []string{"w"}, MaskingRemoved: []string{"y"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffGovernance(tt.oldRates, tt.newRates, tt.oldRules, tt.newRules, at)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffGovernance() = %+v, want %+v", got, tt.want)
+			}
+			if got.Empty() != (tt.name == "Unchanged") {
+				t.Errorf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestGovernanceChanges(t *testing.T) {
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", &staticClient{}, nopLogger{})
+	p.MaxChangeHistory = 3
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		p.recordChange(GovernanceDiff{At: start.Add(time.Duration(i) * time.Minute), MaskingAdded: []string{string(rune('a' + i))}})
+	}
+
+	changes := p.Changes()
+	if len(changes) != 3 || changes[0].MaskingAdded[0] != "c" || changes[2].MaskingAdded[0] != "e" {
+		t.Fatalf("Changes() = %+v, want the newest three, oldest first", changes)
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []string
+	}{
+		{name: "Inclusive Bounds", from: start.Add(2 * time.Minute), to: start.Add(3 * time.Minute), want: []string{"c", "d"}},
+		{name: "Evicted Changes", from: start, to: start.Add(time.Minute), want: nil},
+		{name: "Everything", from: start, to: start.Add(time.Hour), want: []string{"c", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range p.ChangesBetween(tt.from, tt.to) {
+				got = append(got, d.MaskingAdded...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChangesBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGovernanceChanges_RecordedOnUpdate(t *testing.T) {
+	client := &staticClient{doc: `{"sampling_rates": {"checkout": 0.5}}`}
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", client, nopLogger{})
+	var notified []GovernanceDiff
+	p.OnChange = func(d GovernanceDiff) { notified = append(notified, d) }
+
+	for _, doc := range []string{client.doc, client.doc, `{"sampling_rates": {"checkout": 0.25}, "masking_rules": ["\\d{16}"]}`} {
+		client.doc = doc
+		if err := p.FetchAndUpdate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The repeated document changed nothing and is not recorded.
+	changes := p.Changes()
+	if len(changes) != 2 || !reflect.DeepEqual(notified, changes) {
+		t.Fatalf("Changes() = %+v, OnChange got %+v; want the two changing updates", changes, notified)
+	}
+	if got := changes[1].SamplingChanged; len(got) != 1 || got[0] != (SamplingChange{Key: "checkout", Old: 0.5, New: 0.25}) {
+		t.Errorf("second change = %+v, want checkout 0.5 -> 0.25", changes[1])
+	}
+}
@@ -0,0 +1,35 @@
+package governance
+
+import (
+	"context"
+	"testing"
+
+	"internal/events"
+)
+
+func TestGovernanceUpdatedEvent(t *testing.T) {
+	bus := events.NewBus(0)
+	var published []events.GovernanceUpdatedEvent
+	events.Subscribe(bus, events.GovernanceUpdated, func(ev events.GovernanceUpdatedEvent) { published = append(published, ev) })
+
+	client := &staticClient{doc: `{"sampling_rates": {"checkout": 0.5, "search": 0.1}, "masking_rules": ["\\d{16}"],
+		"silences": [{"matchers": {"cause": "latency"}, "starts_at": "2026-03-01T01:00:00Z", "ends_at": "2026-03-01T03:00:00Z"}]}`}
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", client, nopLogger{})
+	p.Bus = bus
+	if err := p.FetchAndUpdate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// An invalid document is not applied and publishes nothing.
+	client.doc = `{"sampling_rates": `
+	if err := p.FetchAndUpdate(context.Background()); err == nil {
+		t.Fatal("FetchAndUpdate() with an invalid document succeeded")
+	}
+
+	bus.Close()
+	if len(published) != 1 {
+		t.Fatalf("GovernanceUpdated events = %+v, want one", published)
+	}
+	if ev := published[0]; ev.SamplingRates != 2 || ev.MaskingRules != 1 || ev.Silences != 1 || ev.Generation != 1 || ev.At.IsZero() {
+		t.Errorf("GovernanceUpdated = %+v, want 2 sampling rates, 1 masking rule, 1 silence at generation 1", ev)
+	}
+}
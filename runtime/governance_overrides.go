@@ -0,0 +1,200 @@
+package governance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"pkg/errreport"
+	"pkg/featureflag"
+	"pkg/strictjson"
+)
+
+// policyDocument is the enforceable content of a governance policy document.
+type policyDocument struct {
+	SamplingRates map[string]float64
+	MaskingRules  []string
+	Silences      []SilenceWindow
+	Thresholds    *ThresholdOverrides
+	FeatureFlags  map[string]featureflag.Flag
+}
+
+// GovernanceOverrides is the local override file, for emergency interventions on a single node.
+// Its values take precedence over the fetched document:
+//   - sampling_rates replace the fetched rate of the same key, or add one;
+//   - masking_rules and silences are enforced in addition to the fetched ones;
+//   - each non-zero thresholds field replaces the fetched value.
+//
+// Overrides can only add masking rules, never lift them. Since omitted thresholds leave the running
+// ones unchanged, removing a threshold override keeps its value until the document sets another.
+type GovernanceOverrides struct {
+	SamplingRates map[string]float64  `json:"sampling_rates,omitempty"`
+	MaskingRules  []string            `json:"masking_rules,omitempty"`
+	Silences      []SilenceWindow     `json:"silences,omitempty"`
+	Thresholds    *ThresholdOverrides `json:"thresholds,omitempty"`
+}
+
+// OverriddenValue is one value of the governance state that comes from the override file.
+type OverriddenValue struct {
+	Field    string      `json:"field"`             // e.g. "sampling_rates", "masking_rules", "thresholds.max_breaches"
+	Key      string      `json:"key,omitempty"`     // Sampling rate key
+	Fetched  interface{} `json:"fetched,omitempty"` // Value in the fetched document, if any
+	Override interface{} `json:"override"`
+}
+
+// loadOverrides reads OverridesPath. A missing file means no overrides; an unreadable or invalid
+// one is reported and the last valid overrides stay in effect.
+func (p *TracePolicyGovernanceModule) loadOverrides() *GovernanceOverrides {
+	if p.OverridesPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(p.OverridesPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		p.overrides = nil
+		return nil
+	}
+	if err == nil && p.Expander != nil {
+		data, err = p.Expander.ExpandJSON(data)
+	}
+	if err == nil {
+		decode := json.Unmarshal
+		if p.Strict {
+			decode = strictjson.Unmarshal
+		}
+		var o GovernanceOverrides
+		if err = decode(data, &o); err == nil {
+			p.overrides = &o
+			return &o
+		}
+	}
+	p.Log.Errorf("Ignoring changes to governance overrides %s; previous overrides remain in effect: %v", p.OverridesPath, err)
+	errreport.Report(context.Background(), p.ErrorReporter, errreport.KindPolicy, "governance overrides", err)
+	return p.overrides
+}
+
+// mergeOverrides returns doc with the local overrides applied and records which values they set.
+// doc itself is not modified.
+func (p *TracePolicyGovernanceModule) mergeOverrides(doc policyDocument) policyDocument {
+	p.overridesMu.Lock()
+	defer p.overridesMu.Unlock()
+	o := p.loadOverrides()
+	if o == nil {
+		p.overridden = nil
+		return doc
+	}
+
+	var overridden []OverriddenValue
+	rates := make(map[string]float64, len(doc.SamplingRates)+len(o.SamplingRates))
+	for key, rate := range doc.SamplingRates {
+		rates[key] = rate
+	}
+	for key, rate := range o.SamplingRates {
+		v := OverriddenValue{Field: "sampling_rates", Key: key, Override: rate}
+		if fetched, ok := doc.SamplingRates[key]; ok {
+			v.Fetched = fetched
+		}
+		rates[key] = rate
+		overridden = append(overridden, v)
+	}
+	slices.SortFunc(overridden, func(a, b OverriddenValue) int { return strings.Compare(a.Key, b.Key) })
+	doc.SamplingRates = rates
+
+	rules := slices.Clone(doc.MaskingRules)
+	for _, rule := range o.MaskingRules {
+		if !slices.Contains(rules, rule) {
+			rules = append(rules, rule)
+			overridden = append(overridden, OverriddenValue{Field: "masking_rules", Override: rule})
+		}
+	}
+	doc.MaskingRules = rules
+
+	if len(o.Silences) > 0 {
+		doc.Silences = append(slices.Clone(doc.Silences), o.Silences...)
+		for _, s := range o.Silences {
+			overridden = append(overridden, OverriddenValue{Field: "silences", Override: s})
+		}
+	}
+
+	if o.Thresholds != nil {
+		var fetched ThresholdOverrides
+		if doc.Thresholds != nil {
+			fetched = *doc.Thresholds
+		}
+		merged, t := fetched, *o.Thresholds
+		threshold := func(field string, fetched, override interface{}, fetchedSet bool) {
+			v := OverriddenValue{Field: "thresholds." + field, Override: override}
+			if fetchedSet {
+				v.Fetched = fetched
+			}
+			overridden = append(overridden, v)
+		}
+		if t.LatencyThreshold != "" {
+			threshold("latency_threshold", fetched.LatencyThreshold, t.LatencyThreshold, fetched.LatencyThreshold != "")
+			merged.LatencyThreshold = t.LatencyThreshold
+		}
+		if t.LoadThreshold != 0 {
+			threshold("load_threshold", fetched.LoadThreshold, t.LoadThreshold, fetched.LoadThreshold != 0)
+			merged.LoadThreshold = t.LoadThreshold
+		}
+		if t.MaxBreaches != 0 {
+			threshold("max_breaches", fetched.MaxBreaches, t.MaxBreaches, fetched.MaxBreaches != 0)
+			merged.MaxBreaches = t.MaxBreaches
+		}
+		if t.BreachDecayFactor != 0 {
+			threshold("breach_decay_factor", fetched.BreachDecayFactor, t.BreachDecayFactor, fetched.BreachDecayFactor != 0)
+			merged.BreachDecayFactor = t.BreachDecayFactor
+		}
+		doc.Thresholds = &merged
+	}
+
+	if len(overridden) > 0 {
+		p.Log.Warnf("Applying %d local governance overrides from %s", len(overridden), p.OverridesPath)
+	}
+	p.overridden = overridden
+	return doc
+}
+
+// Overrides lists the values of the current governance state that come from the override file
+// rather than the fetched document.
+func (p *TracePolicyGovernanceModule) Overrides() []OverriddenValue {
+	p.overridesMu.Lock()
+	defer p.overridesMu.Unlock()
+	return slices.Clone(p.overridden)
+}
+
+// ApplyOverrides re-reads the override file and applies it over the last fetched document without
+// waiting for the next fetch, e.g. after an operator edits the file. Before the first successful
+// fetch the overrides are applied over an empty document.
+func (p *TracePolicyGovernanceModule) ApplyOverrides() error {
+	if p.OverridesPath == "" {
+		return errors.New("no governance overrides file is configured")
+	}
+	p.apply(nil)
+	return nil
+}
+
+// OverridesHandler serves the overridden values as JSON, for mounting on the admin API.
+func (p *TracePolicyGovernanceModule) OverridesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		overrides := p.Overrides()
+		if overrides == nil {
+			overrides = []OverriddenValue{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Path      string            `json:"path"`
+			Overrides []OverriddenValue `json:"overrides"`
+		}{p.OverridesPath, overrides})
+	}
+}
@@ -0,0 +1,130 @@
+package governance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeOverrides replaces the override file at path; an empty content removes it.
+func writeOverrides(t *testing.T, path, content string) {
+	t.Helper()
+	if content == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeOverrides(t *testing.T) {
+	fetched := policyDocument{
+		SamplingRates: map[string]float64{"checkout": 0.5, "search": 0.1},
+		MaskingRules:  []string{`\d{16}`},
+		Thresholds:    &ThresholdOverrides{LatencyThreshold: "250ms", MaxBreaches: 5},
+	}
+	tests := []struct {
+		name       string
+		overrides  string
+		want       policyDocument
+		overridden []OverriddenValue
+	}{
+		{name: "No File", want: fetched},
+		{name: "Empty File", overrides: `{}`, want: fetched},
+		{
+			name:      "Sampling Replaced And Added",
+			overrides: `{"sampling_rates": {"search": 1, "admin": 0}}`,
+			want:      policyDocument{SamplingRates: map[string]float64{"checkout": 0.5, "search": 1, "admin": 0}, MaskingRules: fetched.MaskingRules, Thresholds: fetched.Thresholds},
+			overridden: []OverriddenValue{
+				{Field: "sampling_rates", Key: "admin", Override: 0.0},
+				{Field: "sampling_rates", Key: "search", Fetched: 0.1, Override: 1.0},
+			},
+		},
+		{
+			name:       "Masking Only Added",
+			overrides:  `{"masking_rules": ["\\d{16}", "secret=\\S+"]}`,
+			want:       policyDocument{SamplingRates: fetched.SamplingRates, MaskingRules: []string{`\d{16}`, `secret=\S+`}, Thresholds: fetched.Thresholds},
+			overridden: []OverriddenValue{{Field: "masking_rules", Override: `secret=\S+`}},
+		},
+		{
+			name:      "Thresholds Per Field",
+			overrides: `{"thresholds": {"max_breaches": 9, "load_threshold": 0.8}}`,
+			want:      policyDocument{SamplingRates: fetched.SamplingRates, MaskingRules: fetched.MaskingRules, Thresholds: &ThresholdOverrides{LatencyThreshold: "250ms", LoadThreshold: 0.8, MaxBreaches: 9}},
+			overridden: []OverriddenValue{
+				{Field: "thresholds.load_threshold", Override: 0.8},
+				{Field: "thresholds.max_breaches", Fetched: 5, Override: 9},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", &staticClient{}, nopLogger{})
+			p.OverridesPath = filepath.Join(t.TempDir(), "overrides.json")
+			writeOverrides(t, p.OverridesPath, tt.overrides)
+
+			got := p.mergeOverrides(fetched)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeOverrides() = %+v, want %+v", got, tt.want)
+			}
+			if overridden := p.Overrides(); !reflect.DeepEqual(overridden, tt.overridden) {
+				t.Errorf("Overrides() = %+v, want %+v", overridden, tt.overridden)
+			}
+			if len(fetched.SamplingRates) != 2 || len(fetched.MaskingRules) != 1 || fetched.Thresholds.MaxBreaches != 5 {
+				t.Fatalf("mergeOverrides() modified the fetched document: %+v", fetched)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	client := &staticClient{doc: `{"sampling_rates": {"checkout": 0.5}}`}
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", client, nopLogger{})
+	if err := p.ApplyOverrides(); err == nil {
+		t.Error("ApplyOverrides() without OverridesPath succeeded")
+	}
+	p.OverridesPath = filepath.Join(t.TempDir(), "overrides.json")
+	var applied []ThresholdOverrides
+	p.OnThresholdsUpdated = func(t ThresholdOverrides) error {
+		applied = append(applied, t)
+		return nil
+	}
+	if err := p.FetchAndUpdate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rate := func() float64 {
+		p.State.mu.RLock()
+		defer p.State.mu.RUnlock()
+		return p.State.SamplingRates["checkout"]
+	}
+
+	steps := []struct {
+		name      string
+		overrides string
+		rate      float64
+		applied   []ThresholdOverrides // Every threshold change applied so far
+	}{
+		{name: "Override", overrides: `{"sampling_rates": {"checkout": 1}, "thresholds": {"max_breaches": 7}}`, rate: 1, applied: []ThresholdOverrides{{MaxBreaches: 7}}},
+		// An invalid file keeps the previous overrides in effect.
+		{name: "Invalid File", overrides: `{"sampling_rates": `, rate: 1, applied: []ThresholdOverrides{{MaxBreaches: 7}}},
+		// Omitted thresholds leave the running ones unchanged, so the removed override keeps its value.
+		{name: "Removed", rate: 0.5, applied: []ThresholdOverrides{{MaxBreaches: 7}}},
+		{name: "New Override", overrides: `{"thresholds": {"max_breaches": 3}}`, rate: 0.5, applied: []ThresholdOverrides{{MaxBreaches: 7}, {MaxBreaches: 3}}},
+	}
+	for _, step := range steps {
+		writeOverrides(t, p.OverridesPath, step.overrides)
+		if err := p.ApplyOverrides(); err != nil {
+			t.Fatalf("%s: ApplyOverrides() error = %v", step.name, err)
+		}
+		if got := rate(); got != step.rate {
+			t.Errorf("%s: checkout rate = %v, want %v", step.name, got, step.rate)
+		}
+		if !reflect.DeepEqual(applied, step.applied) {
+			t.Errorf("%s: applied thresholds = %+v, want %+v", step.name, applied, step.applied)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package governance
+
+import (
+	"services/telemetry"
+)
+
+// TelemetrySilences converts the policy document's maintenance windows into silences owned by
+// telemetry.SilenceSourcePolicy, for the STS silence registry.
+func TelemetrySilences(windows []SilenceWindow) []telemetry.Silence {
+	silences := make([]telemetry.Silence, 0, len(windows))
+	for _, w := range windows {
+		matchers := make([]telemetry.Matcher, 0, len(w.Matchers))
+		for name, value := range w.Matchers {
+			matchers = append(matchers, telemetry.Matcher{Name: name, Value: value})
+		}
+		silences = append(silences, telemetry.Silence{
+			Source:    telemetry.SilenceSourcePolicy,
+			Matchers:  matchers,
+			StartsAt:  w.StartsAt,
+			EndsAt:    w.EndsAt,
+			CreatedBy: "governance",
+			Comment:   w.Comment,
+		})
+	}
+	return silences
+}
+
+// applySilences replaces the policy silences of the STS silence registry, if one is set, with the
+// document's windows.
+func (p *TracePolicyGovernanceModule) applySilences(windows []SilenceWindow) {
+	if p.Silences == nil {
+		return
+	}
+	if err := p.Silences.ReplaceSource(telemetry.SilenceSourcePolicy, TelemetrySilences(windows)); err != nil {
+		p.Log.Warnf("Ignoring governance silences; previous silences remain in effect: %v", err)
+	}
+}
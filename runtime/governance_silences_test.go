@@ -0,0 +1,66 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"services/telemetry"
+)
+
+type staticClient struct{ doc string }
+
+func (c *staticClient) Get(ctx context.Context, url string) ([]byte, error) {
+	return []byte(c.doc), nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+
+func TestPolicySilences(t *testing.T) {
+	registry := telemetry.NewSilenceRegistry()
+	apiID, _ := registry.Add(telemetry.Silence{
+		Matchers: []telemetry.Matcher{{Name: telemetry.CauseLabel, Value: telemetry.CauseLoad}},
+		StartsAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:   time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	client := &staticClient{}
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", client, nopLogger{})
+	p.Silences = registry
+
+	update := func(doc string) {
+		t.Helper()
+		client.doc = doc
+		if err := p.FetchAndUpdate(context.Background()); err != nil {
+			t.Fatalf("FetchAndUpdate() error = %v", err)
+		}
+	}
+	during := time.Date(2026, 3, 1, 2, 0, 0, 0, time.UTC)
+	latency := []string{telemetry.CauseLatency}
+	integrity := []string{telemetry.CauseIntegrity}
+
+	// Add: the document's window silences matching violations while it is open.
+	update(`{"silences": [{"matchers": {"cause": "latency"}, "starts_at": "2026-03-01T01:00:00Z", "ends_at": "2026-03-01T03:00:00Z", "comment": "db migration"}]}`)
+	if !registry.Silenced(latency, nil, during) || registry.Silenced(integrity, nil, during) {
+		t.Error("policy window does not silence exactly the latency cause")
+	}
+
+	// Expiry: the window stops silencing once it ends.
+	if registry.Silenced(latency, nil, during.Add(time.Hour)) {
+		t.Error("policy window still silences after it ended")
+	}
+
+	// Replace: a new document swaps the policy windows, leaving other sources alone.
+	update(`{"silences": [{"matchers": {"cause": "integrity"}, "starts_at": "2026-03-01T01:00:00Z", "ends_at": "2026-03-01T03:00:00Z"}]}`)
+	if registry.Silenced(latency, nil, during) || !registry.Silenced(integrity, nil, during) {
+		t.Error("replaced policy window still in effect, or its replacement is not")
+	}
+	update(`{}`)
+	silences := registry.List()
+	if len(silences) != 1 || silences[0].ID != apiID {
+		t.Errorf("silences after the policy dropped its windows = %+v, want only the API silence", silences)
+	}
+}
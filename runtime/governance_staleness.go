@@ -0,0 +1,65 @@
+package governance
+
+import (
+	"time"
+
+	"internal/events"
+)
+
+// Freshness describes how current the governance state is.
+type Freshness struct {
+	Generation  uint64        `json:"generation"`
+	LastUpdated time.Time     `json:"last_updated"`
+	Age         time.Duration `json:"age"` // Time since LastUpdated; zero if never updated
+	Stale       bool          `json:"stale"`
+}
+
+// Freshness reports the state's generation and age at now. It is stale if it was never loaded or
+// is older than maxAge; a zero maxAge only flags a state that was never loaded.
+func (gs *GovernanceState) Freshness(maxAge time.Duration, now time.Time) Freshness {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+	f := Freshness{Generation: gs.Generation, LastUpdated: gs.LastUpdated, Stale: gs.LastUpdated.IsZero()}
+	if !f.Stale {
+		f.Age = now.Sub(gs.LastUpdated)
+		f.Stale = maxAge > 0 && f.Age > maxAge
+	}
+	return f
+}
+
+// CheckStaleness evaluates the staleness alarm against MaxPolicyAge. The alarm is raised, with a
+// warning, a call to OnStale and a GovernanceStale event, when the state becomes stale and cleared
+// once it refreshes; it is never raised while MaxPolicyAge is zero. StartPolicyPolling calls it
+// after every fetch.
+func (p *TracePolicyGovernanceModule) CheckStaleness(now time.Time) Freshness {
+	f := p.State.Freshness(p.MaxPolicyAge, now)
+	if p.MaxPolicyAge <= 0 {
+		return f
+	}
+	if !f.Stale {
+		if p.stale.Swap(false) {
+			p.staleGauge.Set(0)
+			p.Log.Infof("Governance policies are fresh again (generation %d)", f.Generation)
+		}
+		return f
+	}
+	if p.stale.Swap(true) {
+		return f
+	}
+	p.staleGauge.Set(1)
+	if f.LastUpdated.IsZero() {
+		p.Log.Warnf("Governance policies are stale: never loaded from %s", p.ConfigURL)
+	} else {
+		p.Log.Warnf("Governance policies are stale: generation %d last updated %v ago (max %v)",
+			f.Generation, f.Age.Round(time.Second), p.MaxPolicyAge)
+	}
+	if p.OnStale != nil {
+		p.OnStale(f)
+	}
+	if p.Bus != nil {
+		events.Publish(p.Bus, events.GovernanceStale, events.GovernanceStaleEvent{
+			Generation: f.Generation, LastUpdated: f.LastUpdated, Age: f.Age, MaxAge: p.MaxPolicyAge, At: now,
+		})
+	}
+	return f
+}
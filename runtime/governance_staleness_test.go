@@ -0,0 +1,78 @@
+package governance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"internal/events"
+)
+
+func TestGovernanceState_Freshness(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		lastUpdated time.Time
+		maxAge      time.Duration
+		now         time.Time
+		want        Freshness
+	}{
+		{name: "Never Loaded", maxAge: time.Hour, now: updated, want: Freshness{Generation: 3, Stale: true}},
+		{name: "Never Loaded Without Max Age", now: updated, want: Freshness{Generation: 3, Stale: true}},
+		{name: "Fresh", lastUpdated: updated, maxAge: time.Hour, now: updated.Add(time.Hour), want: Freshness{Generation: 3, LastUpdated: updated, Age: time.Hour}},
+		{name: "Stale", lastUpdated: updated, maxAge: time.Hour, now: updated.Add(time.Hour + time.Second), want: Freshness{Generation: 3, LastUpdated: updated, Age: time.Hour + time.Second, Stale: true}},
+		{name: "No Max Age", lastUpdated: updated, now: updated.Add(48 * time.Hour), want: Freshness{Generation: 3, LastUpdated: updated, Age: 48 * time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := &GovernanceState{Generation: 3, LastUpdated: tt.lastUpdated}
+			if got := gs.Freshness(tt.maxAge, tt.now); got != tt.want {
+				t.Errorf("Freshness() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStaleness(t *testing.T) {
+	bus := events.NewBus(0)
+	var published []events.GovernanceStaleEvent
+	events.Subscribe(bus, events.GovernanceStale, func(ev events.GovernanceStaleEvent) { published = append(published, ev) })
+
+	p := NewTracePolicyGovernanceModule("http://policy.local/policies.json", &staticClient{doc: `{}`}, nopLogger{})
+	p.MaxPolicyAge = time.Hour
+	p.Bus = bus
+	var raised []Freshness
+	p.OnStale = func(f Freshness) { raised = append(raised, f) }
+
+	// Never loaded: the alarm is raised once, however often it is checked.
+	now := time.Now()
+	p.CheckStaleness(now)
+	p.CheckStaleness(now.Add(time.Minute))
+	if len(raised) != 1 || !raised[0].LastUpdated.IsZero() {
+		t.Fatalf("OnStale calls = %+v, want one for the never loaded state", raised)
+	}
+
+	// A refresh clears the alarm; it is raised again once the policies age past MaxPolicyAge.
+	if err := p.FetchAndUpdate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if f := p.CheckStaleness(time.Now()); f.Stale || f.Generation != 1 {
+		t.Errorf("CheckStaleness after a refresh = %+v, want fresh generation 1", f)
+	}
+	later := time.Now().Add(2 * time.Hour)
+	if f := p.CheckStaleness(later); !f.Stale {
+		t.Errorf("CheckStaleness two hours later = %+v, want stale", f)
+	}
+	if len(raised) != 2 || raised[1].Generation != 1 {
+		t.Errorf("OnStale calls = %+v, want a second one for generation 1", raised)
+	}
+
+	// Without a maximum age the alarm is never raised.
+	p.MaxPolicyAge = 0
+	p.CheckStaleness(later.Add(time.Hour))
+
+	bus.Close()
+	if len(published) != 2 || published[1].Generation != 1 || published[1].MaxAge != time.Hour || !published[1].At.Equal(later) || published[1].Age < time.Hour {
+		t.Errorf("GovernanceStale events = %+v, want one per raised alarm", published)
+	}
+}
@@ -0,0 +1,52 @@
+package governance
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pkg/telemetrypb"
+)
+
+// ToProto converts the current governance state to its wire representation.
+func (gs *GovernanceState) ToProto() *telemetrypb.GovernanceState {
+	gs.mu.RLock()
+	defer gs.mu.RUnlock()
+
+	pb := &telemetrypb.GovernanceState{
+		SamplingRates: make(map[string]float64, len(gs.SamplingRates)),
+		MaskingRules:  append([]string(nil), gs.MaskingRules...),
+		LastUpdated:   timestamppb.New(gs.LastUpdated),
+	}
+	for k, v := range gs.SamplingRates {
+		pb.SamplingRates[k] = v
+	}
+	for _, w := range gs.Silences {
+		pb.Silences = append(pb.Silences, &telemetrypb.SilenceWindow{
+			Matchers: w.Matchers,
+			StartsAt: timestamppb.New(w.StartsAt),
+			EndsAt:   timestamppb.New(w.EndsAt),
+			Comment:  w.Comment,
+		})
+	}
+	return pb
+}
+
+// GovernanceStateFromProto builds a governance state from its wire representation.
+func GovernanceStateFromProto(pb *telemetrypb.GovernanceState) *GovernanceState {
+	gs := &GovernanceState{
+		SamplingRates: make(map[string]float64, len(pb.GetSamplingRates())),
+		MaskingRules:  append([]string(nil), pb.GetMaskingRules()...),
+		LastUpdated:   pb.GetLastUpdated().AsTime(),
+	}
+	for k, v := range pb.GetSamplingRates() {
+		gs.SamplingRates[k] = v
+	}
+	for _, w := range pb.GetSilences() {
+		gs.Silences = append(gs.Silences, SilenceWindow{
+			Matchers: w.GetMatchers(),
+			StartsAt: w.GetStartsAt().AsTime(),
+			EndsAt:   w.GetEndsAt().AsTime(),
+			Comment:  w.GetComment(),
+		})
+	}
+	return gs
+}
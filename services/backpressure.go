@@ -0,0 +1,34 @@
+package telemetry
+
+// defaultBackpressureStart is the metric/threshold ratio at which throttling begins.
+const defaultBackpressureStart = 0.75
+
+// Backpressure recommends a throttle level from 0.0 (no throttling) to 1.0 (shed all optional load).
+// It ramps linearly from BackpressureStart up to the GATM threshold, so schedulers reach full
+// throttle before a breach is recorded. The worst of latency and load pressure is used.
+func (s *sovereignTelemetryService) Backpressure() float64 {
+	s.mu.RLock()
+	td, th := s.data, s.thresholds
+	s.mu.RUnlock()
+
+	if td.Timestamp.IsZero() {
+		// No collection yet; nothing to base a recommendation on.
+		return 0
+	}
+
+	ratio := float64(td.PipelineLatencyS9) / float64(th.LatencyThreshold)
+	if load := td.ResourceLoad_Pct / th.LoadThreshold; load > ratio {
+		ratio = load
+	}
+
+	start := s.cfg.BackpressureStart
+	throttle := (ratio - start) / (1 - start)
+	switch {
+	case throttle < 0:
+		return 0
+	case throttle > 1:
+		return 1
+	default:
+		return throttle
+	}
+}
@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackpressure(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   float64
+		latency time.Duration
+		load    float64
+		want    float64
+	}{
+		{name: "Below Start", latency: 500 * time.Millisecond, load: 0.2, want: 0},
+		{name: "At Start", latency: 750 * time.Millisecond, want: 0},
+		{name: "Latency Ramp", latency: 875 * time.Millisecond, load: 0.2, want: 0.5},
+		{name: "Load Ramp", latency: 100 * time.Millisecond, load: 0.7, want: 0.5},
+		{name: "Worst Of Both", latency: 950 * time.Millisecond, load: 0.7, want: 0.8},
+		{name: "At Threshold", latency: time.Second, want: 1},
+		{name: "Clamped Above Threshold", latency: 3 * time.Second, load: 0.99, want: 1},
+		{name: "Custom Start", start: 0.5, latency: 750 * time.Millisecond, want: 0.5},
+		{name: "Out Of Range Start Uses Default", start: 1.5, latency: 875 * time.Millisecond, want: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &sequenceSource{snapshots: []TelemetryData{{PipelineLatencyS9: tt.latency, ResourceLoad_Pct: tt.load, IntegrityHashChainStatus: "SYNCED"}}}
+			sts := NewSovereignTelemetryService(STSConfiguration{LatencyThreshold: time.Second, LoadThreshold: 0.8, BackpressureStart: tt.start}, src)
+			if err := sts.CollectNow(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := sts.Backpressure(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Backpressure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	sts := NewSovereignTelemetryService(STSConfiguration{}, &sequenceSource{snapshots: []TelemetryData{{PipelineLatencyS9: 5 * time.Second}}})
+	if got := sts.Backpressure(); got != 0 {
+		t.Errorf("Backpressure() before any collection = %v, want 0", got)
+	}
+}
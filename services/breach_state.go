@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// BreachState is the portion of STS state shared between replicas through a BreachStateStore.
+type BreachState struct {
+	Count           int              `json:"count"`
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+	// Cycle is the collection cycle the count was last advanced for: the snapshot timestamp
+	// truncated to the collection interval.
+	Cycle time.Time `json:"cycle,omitempty"`
+	// Observations is the most collections any replica has assessed within Cycle. A replica that
+	// assesses more, e.g. after CollectNow, advances the count once for each additional one.
+	Observations int `json:"observations,omitempty"`
+}
+
+// BreachStateStore persists the cumulative GATM breach state outside the process, so restarts
+// and replicas observe the same count. Replicas advance the count once per collection cycle,
+// whichever gets there first, rather than once each; collections a replica adds within a cycle
+// are counted too.
+type BreachStateStore interface {
+	// UpdateBreachState atomically replaces the stored state with update(stored) and returns the
+	// state stored. The zero state stands in for a missing one. update may be called more than once
+	// if another replica changes the state concurrently.
+	UpdateBreachState(ctx context.Context, update func(BreachState) BreachState) (BreachState, error)
+}
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStateStore is a BreachStateStore shared by replicas in one process.
+type memoryStateStore struct {
+	mu    sync.Mutex
+	state BreachState
+}
+
+func (m *memoryStateStore) UpdateBreachState(ctx context.Context, update func(BreachState) BreachState) (BreachState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = update(m.state)
+	return m.state, nil
+}
+
+func TestStateStore_ReplicasAdvanceOncePerCycle(t *testing.T) {
+	store := &memoryStateStore{}
+	newReplica := func() STS {
+		return NewSovereignTelemetryService(STSConfiguration{
+			DefaultInterval:  time.Second,
+			LatencyThreshold: time.Second,
+			MaxBreaches:      3,
+			StateStore:       store,
+		}, &scriptedSource{latencies: []time.Duration{2 * time.Second}})
+	}
+	a, b := newReplica(), newReplica()
+
+	for cycle := 1; cycle <= 3; cycle++ {
+		for _, replica := range []STS{a, b} {
+			if err := replica.CollectNow(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := replica.GetHealthStatus().GATMBreachCount; got != cycle {
+				t.Fatalf("cycle %d: GATMBreachCount = %d, want %d with both replicas violating", cycle, got, cycle)
+			}
+		}
+		if escalated := b.CheckGATMViolation(); escalated != (cycle == 3) {
+			t.Errorf("cycle %d: CheckGATMViolation() = %v", cycle, escalated)
+		}
+	}
+
+	// Out-of-band changes reach the other replica, which keeps the cycle it already assessed.
+	if _, err := a.Acknowledge("oncall", "investigating", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.GetHealthStatus(); !got.IsAcknowledged || got.GATMBreachCount != 4 {
+		t.Errorf("replica b = %+v, want the acknowledgement shared and the count advanced once", got)
+	}
+	a.ResetBreachCount()
+	if got := store.state; got.Count != 0 || got.Acknowledgement != nil || !got.Cycle.Equal(time.Unix(3, 0)) {
+		t.Errorf("shared state after reset = %+v, want a cleared count for cycle 3", got)
+	}
+}
+
+func TestStateStore_SeveralCollectionsPerCycle(t *testing.T) {
+	store := &memoryStateStore{}
+	newReplica := func() STS {
+		// Collections are a second apart, so every one below falls in the same 10s cycle.
+		return NewSovereignTelemetryService(STSConfiguration{
+			DefaultInterval:  10 * time.Second,
+			LatencyThreshold: time.Second,
+			MaxBreaches:      3,
+			StateStore:       store,
+		}, &sequenceSource{snapshots: []TelemetryData{{PipelineLatencyS9: 2 * time.Second, IntegrityHashChainStatus: "SYNCED"}}})
+	}
+	replicas := map[string]STS{"a": newReplica(), "b": newReplica()}
+
+	steps := []struct {
+		replica string
+		want    int
+	}{
+		{replica: "a", want: 1},
+		{replica: "a", want: 2}, // A second breach in the cycle is counted, not dropped
+		{replica: "b", want: 2}, // b's first and second collections were counted by a already
+		{replica: "b", want: 2},
+		{replica: "b", want: 3}, // b's third goes beyond what a assessed
+		{replica: "a", want: 3},
+	}
+	for i, step := range steps {
+		sts := replicas[step.replica]
+		if err := sts.CollectNow(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := sts.GetHealthStatus().GATMBreachCount; got != step.want {
+			t.Errorf("step %d (%s): GATMBreachCount = %d, want %d", i, step.replica, got, step.want)
+		}
+	}
+	if !replicas["a"].CheckGATMViolation() {
+		t.Error("CheckGATMViolation() = false after three breaches in one cycle")
+	}
+}
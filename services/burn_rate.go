@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Escalation modes select how CheckGATMViolation decides to escalate.
+const (
+	// EscalationModeConsecutive escalates once GATMBreachCount reaches MaxBreaches (default).
+	EscalationModeConsecutive = "consecutive"
+	// EscalationModeBurnRate escalates when any multi-window burn-rate alert fires.
+	EscalationModeBurnRate = "burn_rate"
+	// EscalationModeSlidingWindow escalates when the share of violating samples among the last N
+	// reaches a ratio threshold.
+	EscalationModeSlidingWindow = "sliding_window"
+)
+
+// defaultBurnRateMaxSamples bounds how much sink history a single evaluation may read.
+const defaultBurnRateMaxSamples = 10000
+
+// BurnRateWindow pairs a long and a short window; both must exceed Factor for the alert to fire.
+// The long window catches sustained burns, the short window ensures the burn is still ongoing.
+type BurnRateWindow struct {
+	Long   time.Duration
+	Short  time.Duration
+	Factor float64 // Burn-rate multiple of the error budget (e.g., 14.4)
+}
+
+// BurnRateConfig defines the SLO and alerting windows for burn-rate detection.
+type BurnRateConfig struct {
+	// Objective is the target fraction of non-violating samples (e.g., 0.99).
+	Objective float64
+	Windows   []BurnRateWindow
+	// MaxSamples bounds the history read from the sink per evaluation. Zero applies a default.
+	MaxSamples int
+}
+
+// DefaultBurnRateWindows returns the classic fast/slow burn pairs scaled to telemetry sampling.
+func DefaultBurnRateWindows() []BurnRateWindow {
+	return []BurnRateWindow{
+		{Long: time.Hour, Short: 5 * time.Minute, Factor: 14.4},
+		{Long: 6 * time.Hour, Short: 30 * time.Minute, Factor: 6},
+	}
+}
+
+// Validate ensures the burn-rate configuration is usable.
+func (c BurnRateConfig) Validate() error {
+	if c.Objective <= 0 || c.Objective >= 1 {
+		return errors.New("burn rate: objective must be between (0.0, 1.0)")
+	}
+	if len(c.Windows) == 0 {
+		return errors.New("burn rate: at least one window pair is required")
+	}
+	for i, w := range c.Windows {
+		if w.Short <= 0 || w.Long <= w.Short {
+			return fmt.Errorf("burn rate: window %d must satisfy 0 < short < long", i)
+		}
+		if w.Factor <= 0 {
+			return fmt.Errorf("burn rate: window %d factor must be positive", i)
+		}
+	}
+	return nil
+}
+
+// BurnRateStatus reports the measured burn rates for each window pair.
+type BurnRateStatus struct {
+	Firing bool
+	Rates  []WindowBurnRate
+}
+
+// WindowBurnRate is the measured burn rate of a single window pair.
+type WindowBurnRate struct {
+	Window    BurnRateWindow
+	LongRate  float64
+	ShortRate float64
+	Firing    bool
+}
+
+// BurnRateDetector evaluates SLO-style multi-window, multi-burn-rate alerts over sink history.
+type BurnRateDetector struct {
+	cfg  BurnRateConfig
+	sink TelemetrySink
+}
+
+// NewBurnRateDetector validates cfg and returns a detector reading from sink.
+func NewBurnRateDetector(cfg BurnRateConfig, sink TelemetrySink) (*BurnRateDetector, error) {
+	if sink == nil {
+		return nil, errors.New("burn rate: a telemetry sink is required")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.MaxSamples == 0 {
+		cfg.MaxSamples = defaultBurnRateMaxSamples
+	}
+	return &BurnRateDetector{cfg: cfg, sink: sink}, nil
+}
+
+// Evaluate computes burn rates as of now. A sample counts against the budget when it violated
+// GATM rules and was not silenced.
+func (d *BurnRateDetector) Evaluate(ctx context.Context, now time.Time) (BurnRateStatus, error) {
+	history, err := d.sink.QueryLastN(ctx, d.cfg.MaxSamples)
+	if err != nil {
+		return BurnRateStatus{}, fmt.Errorf("burn rate: failed to query sink: %w", err)
+	}
+
+	budget := 1 - d.cfg.Objective
+	var status BurnRateStatus
+	for _, w := range d.cfg.Windows {
+		rate := WindowBurnRate{
+			Window:    w,
+			LongRate:  errorRatio(history, now.Add(-w.Long), now) / budget,
+			ShortRate: errorRatio(history, now.Add(-w.Short), now) / budget,
+		}
+		rate.Firing = rate.LongRate >= w.Factor && rate.ShortRate >= w.Factor
+		status.Firing = status.Firing || rate.Firing
+		status.Rates = append(status.Rates, rate)
+	}
+	return status, nil
+}
+
+// errorRatio returns the fraction of violating samples with timestamps in (from, to].
+func errorRatio(history []TelemetryData, from, to time.Time) float64 {
+	var total, bad int
+	for _, td := range history {
+		if !td.Timestamp.After(from) || td.Timestamp.After(to) {
+			continue
+		}
+		total++
+		if td.IsGATMViolating && !td.IsSilenced {
+			bad++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total)
+}
@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// sliceSink is a minimal in-memory TelemetrySink for tests.
+type sliceSink struct {
+	records []TelemetryData
+}
+
+func (s *sliceSink) Record(ctx context.Context, data TelemetryData) error {
+	s.records = append(s.records, data)
+	return nil
+}
+
+func (s *sliceSink) QueryLastN(ctx context.Context, n int) ([]TelemetryData, error) {
+	if n > len(s.records) {
+		n = len(s.records)
+	}
+	return s.records[len(s.records)-n:], nil
+}
+
+func (s *sliceSink) Close(ctx context.Context) error { return nil }
+
+func TestBurnRateDetector_Evaluate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := BurnRateWindow{Long: time.Hour, Short: 5 * time.Minute, Factor: 10}
+
+	tests := []struct {
+		name      string
+		violating func(age time.Duration) bool
+		want      bool
+	}{
+		{
+			name:      "Healthy",
+			violating: func(time.Duration) bool { return false },
+			want:      false,
+		},
+		{
+			name:      "Sustained Burn",
+			violating: func(time.Duration) bool { return true },
+			want:      true,
+		},
+		{
+			name:      "Recovered (Long Window Only)",
+			violating: func(age time.Duration) bool { return age > 10*time.Minute },
+			want:      false,
+		},
+		{
+			name:      "Fresh Spike (Short Window Only)",
+			violating: func(age time.Duration) bool { return age < 2*time.Minute },
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &sliceSink{}
+			for age := time.Hour - time.Minute; age >= 0; age -= time.Minute {
+				sink.Record(context.Background(), TelemetryData{
+					Timestamp:       now.Add(-age),
+					IsGATMViolating: tt.violating(age),
+				})
+			}
+
+			d, err := NewBurnRateDetector(BurnRateConfig{Objective: 0.99, Windows: []BurnRateWindow{window}}, sink)
+			if err != nil {
+				t.Fatalf("NewBurnRateDetector() error = %v", err)
+			}
+			status, err := d.Evaluate(context.Background(), now)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if status.Firing != tt.want {
+				t.Errorf("Evaluate() firing = %v, want %v (rates: %+v)", status.Firing, tt.want, status.Rates)
+			}
+		})
+	}
+}
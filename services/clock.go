@@ -0,0 +1,169 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock statuses reported in TelemetryData.ClockStatus. An empty status means the clock was not
+// probed.
+const (
+	ClockSynced = "SYNCED"
+	// ClockUnknown means the clock probe failed, so the offset is unknown.
+	ClockUnknown = "UNKNOWN"
+	// ClockSteppedBack means the snapshot's timestamp or latency went backwards, e.g. after the
+	// clock was stepped; the values were clamped to keep the pipeline monotonic.
+	ClockSteppedBack = "STEPPED_BACK"
+)
+
+// ClockProbe measures the offset of the local clock from a time reference. A positive offset means
+// the local clock is ahead.
+type ClockProbe interface {
+	ClockOffset(ctx context.Context) (time.Duration, error)
+}
+
+// ClockProcessor is a collect-stage processor recording the probe's offset in each snapshot. A
+// failing probe does not fail the cycle; it leaves the snapshot's ClockStatus UNKNOWN, which the
+// clock-skew check treats as a breach.
+func ClockProcessor(probe ClockProbe) StageProcessor {
+	return StageProcessor{Stage: StageCollect, Name: "clock", Processor: ProcessorFunc(func(ctx context.Context, td *TelemetryData) error {
+		offset, err := probe.ClockOffset(ctx)
+		if err != nil {
+			td.ClockOffset, td.ClockStatus = 0, ClockUnknown
+			return nil
+		}
+		td.ClockOffset, td.ClockStatus = offset, ClockSynced
+		return nil
+	})}
+}
+
+// checkMonotonic clamps a snapshot whose timestamp precedes the previous snapshot's, or whose
+// latency is negative, so sinks see ordered records and latency stays meaningful. It marks the
+// snapshot STEPPED_BACK and reports whether it did.
+func checkMonotonic(td *TelemetryData, previous time.Time) bool {
+	stepped := false
+	if !previous.IsZero() && td.Timestamp.Before(previous) {
+		td.Timestamp = previous
+		stepped = true
+	}
+	if td.PipelineLatencyS9 < 0 {
+		td.PipelineLatencyS9 = 0
+		stepped = true
+	}
+	if stepped {
+		td.ClockStatus = ClockSteppedBack
+	}
+	return stepped
+}
+
+// clockSkewed reports whether the snapshot's clock offset exceeds maxSkew, or is unknown.
+func clockSkewed(td TelemetryData, maxSkew time.Duration) bool {
+	if td.ClockStatus == ClockUnknown {
+		return true
+	}
+	return td.ClockOffset > maxSkew || td.ClockOffset < -maxSkew
+}
+
+// NTPProbe queries an NTP server with a single SNTP request (RFC 4330).
+type NTPProbe struct {
+	Server  string        // host or host:port; port 123 by default
+	Timeout time.Duration // Default 2s
+}
+
+// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch.
+const ntpEpochOffset = 2208988800
+
+func (p NTPProbe) ClockOffset(ctx context.Context) (time.Duration, error) {
+	addr := p.Server
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "123")
+	}
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return 0, fmt.Errorf("ntp: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := make([]byte, 48)
+	req[0] = 0x23 // LI 0, version 4, mode 3 (client)
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, fmt.Errorf("ntp: %w", err)
+	}
+	resp := make([]byte, 48)
+	if _, err := conn.Read(resp); err != nil {
+		return 0, fmt.Errorf("ntp: %w", err)
+	}
+	received := time.Now()
+	if mode := resp[0] & 0x7; mode != 4 {
+		return 0, fmt.Errorf("ntp: unexpected response mode %d", mode)
+	}
+	if stratum := resp[1]; stratum == 0 {
+		return 0, errors.New("ntp: server sent kiss-o'-death")
+	}
+	serverReceived := ntpTime(resp[32:40])
+	serverSent := ntpTime(resp[40:48])
+	// Offset of the server from the local clock, averaged over both legs of the exchange.
+	serverAhead := (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2
+	return -serverAhead, nil
+}
+
+// ntpTime decodes a 64-bit NTP timestamp.
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b[:4])) - ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:]))
+	return time.Unix(secs, frac*int64(time.Second)>>32)
+}
+
+// ChronyProbe reads the offset chronyd is correcting from its tracking report.
+type ChronyProbe struct {
+	Command string // Path of chronyc; default "chronyc" from PATH
+}
+
+func (p ChronyProbe) ClockOffset(ctx context.Context) (time.Duration, error) {
+	command := p.Command
+	if command == "" {
+		command = "chronyc"
+	}
+	out, err := exec.CommandContext(ctx, command, "-c", "tracking").Output()
+	if err != nil {
+		return 0, fmt.Errorf("chrony: %w", err)
+	}
+	return parseChronyTracking(out)
+}
+
+// parseChronyTracking extracts the clock offset from `chronyc -c tracking` output. Its fifth field
+// is the correction chronyd is applying: positive when the local clock is slow.
+func parseChronyTracking(out []byte) (time.Duration, error) {
+	fields := strings.Split(string(bytes.TrimSpace(out)), ",")
+	if len(fields) < 14 {
+		return 0, fmt.Errorf("chrony: unexpected tracking report %q", out)
+	}
+	if leap := fields[13]; leap == "Not synchronised" {
+		return 0, errors.New("chrony: clock not synchronised")
+	}
+	correction, err := strconv.ParseFloat(fields[4], 64)
+	if err != nil {
+		return 0, fmt.Errorf("chrony: invalid system time offset: %w", err)
+	}
+	return -DurationFromSeconds(correction), nil
+}
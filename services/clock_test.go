@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+type clockProbeFunc func(ctx context.Context) (time.Duration, error)
+
+func (f clockProbeFunc) ClockOffset(ctx context.Context) (time.Duration, error) { return f(ctx) }
+
+func TestCollectNow_ClockChecks(t *testing.T) {
+	offsets := []time.Duration{200 * time.Millisecond, -2 * time.Second, 0, 0}
+	errs := []error{nil, nil, nil, errors.New("chronyc: command not found")}
+	stamps := []time.Duration{10 * time.Second, 20 * time.Second, 5 * time.Second, 30 * time.Second}
+	cycle := 0
+	rewind := StageProcessor{Stage: StageCollect, Processor: ProcessorFunc(func(ctx context.Context, td *TelemetryData) error {
+		td.Timestamp = time.Unix(0, 0).Add(stamps[cycle])
+		return nil
+	})}
+	probe := clockProbeFunc(func(ctx context.Context) (time.Duration, error) { return offsets[cycle], errs[cycle] })
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		MaxClockSkew: time.Second,
+		Processors:   []StageProcessor{rewind, ClockProcessor(probe)},
+	}, &scriptedSource{latencies: []time.Duration{100 * time.Millisecond}})
+
+	tests := []struct {
+		status string
+		causes []string
+		at     time.Duration
+	}{
+		{status: ClockSynced, at: 10 * time.Second},
+		{status: ClockSynced, causes: []string{CauseClockSkew}, at: 20 * time.Second},
+		// The clock went back: the timestamp holds at the previous snapshot's.
+		{status: ClockSteppedBack, causes: []string{CauseClockJump}, at: 20 * time.Second},
+		{status: ClockUnknown, causes: []string{CauseClockSkew}, at: 30 * time.Second},
+	}
+	for i, tt := range tests {
+		cycle = i
+		if err := sts.CollectNow(context.Background()); err != nil {
+			t.Fatalf("cycle %d: CollectNow() error = %v", i, err)
+		}
+		got := sts.GetHealthStatus()
+		if got.ClockStatus != tt.status || !slices.Equal(got.ViolationCauses, tt.causes) || !got.Timestamp.Equal(time.Unix(0, 0).Add(tt.at)) {
+			t.Errorf("cycle %d: status %s, causes %v, timestamp %v; want %s, %v, %v", i, got.ClockStatus, got.ViolationCauses, got.Timestamp, tt.status, tt.causes, tt.at)
+		}
+	}
+}
+
+func TestParseChronyTracking(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "Slow", out: "A9FEA97B,169.254.169.123,4,1726000000.1,0.000250000,-0.000001,0.000002,-8.1,0.0,0.03,0.000318,0.000134,64.4,Normal\n", want: -250 * time.Microsecond},
+		{name: "Fast", out: "A9FEA97B,169.254.169.123,4,1726000000.1,-0.5,0,0,0,0,0,0,0,64,Normal", want: 500 * time.Millisecond},
+		{name: "Unsynchronised", out: "00000000,,0,0.0,0.0,0,0,0,0,0,0,0,0,Not synchronised", wantErr: true},
+		{name: "Truncated", out: "506", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChronyTracking([]byte(tt.out))
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseChronyTracking() = %v, %v; want %v, error %t", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNTPProbe(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	defer conn.Close()
+	go func() {
+		req := make([]byte, 48)
+		_, addr, err := conn.ReadFrom(req)
+		if err != nil {
+			return
+		}
+		// A server whose clock runs 3s ahead of ours.
+		now := time.Now().Add(3 * time.Second)
+		resp := make([]byte, 48)
+		resp[0], resp[1] = 0x24, 2 // version 4, mode 4 (server), stratum 2
+		secs := uint32(now.Unix() + ntpEpochOffset)
+		frac := uint32(uint64(now.Nanosecond()) << 32 / uint64(time.Second))
+		for _, at := range []int{32, 40} {
+			binary.BigEndian.PutUint32(resp[at:], secs)
+			binary.BigEndian.PutUint32(resp[at+4:], frac)
+		}
+		conn.WriteTo(resp, addr)
+	}()
+
+	offset, err := NTPProbe{Server: conn.LocalAddr().String(), Timeout: time.Second}.ClockOffset(context.Background())
+	if err != nil {
+		t.Fatalf("ClockOffset() error = %v", err)
+	}
+	if offset > -2900*time.Millisecond || offset < -3100*time.Millisecond {
+		t.Errorf("ClockOffset() = %v, want about -3s", offset)
+	}
+}
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"context"
+
+	"pkg/crashdump"
+)
+
+// CrashSections returns the crash bundle sections describing sts: "telemetry", the latest
+// snapshot, and "breach_state", the breach count and acknowledgement it escalates on. With a sink
+// and a positive recent, "sink_records" holds the last recent records written to it.
+func CrashSections(sts STS, sink TelemetrySink, recent int) []crashdump.Section {
+	sections := []crashdump.Section{
+		{Name: "telemetry", Collect: func(ctx context.Context) (interface{}, error) {
+			return sts.GetHealthStatus(), nil
+		}},
+		{Name: "breach_state", Collect: func(ctx context.Context) (interface{}, error) {
+			if s, ok := sts.(*sovereignTelemetryService); ok {
+				return s.breachState(), nil
+			}
+			td := sts.GetHealthStatus()
+			return BreachState{Count: td.GATMBreachCount, UpdatedAt: td.Timestamp}, nil
+		}},
+	}
+	if sink != nil && recent > 0 {
+		sections = append(sections, crashdump.Section{Name: "sink_records", Collect: func(ctx context.Context) (interface{}, error) {
+			return sink.QueryLastN(ctx, recent)
+		}})
+	}
+	return sections
+}
+
+// breachState returns the breach state as last assessed by this replica.
+func (s *sovereignTelemetryService) breachState() BreachState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return BreachState{Count: s.data.GATMBreachCount, Acknowledgement: s.ack, UpdatedAt: s.data.Timestamp}
+}
+
+// IncidentSections returns the incident bundle sections read from sink: "incident", the breach
+// timeline reconstructed from the last history records, and "history", those records.
+func IncidentSections(sink TelemetrySink, history int) []crashdump.Section {
+	return []crashdump.Section{
+		{Name: "incident", Collect: func(ctx context.Context) (interface{}, error) {
+			return ReconstructIncident(ctx, sink, history)
+		}},
+		{Name: "history", Collect: func(ctx context.Context) (interface{}, error) {
+			return sink.QueryLastN(ctx, history)
+		}},
+	}
+}
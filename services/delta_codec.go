@@ -0,0 +1,179 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const defaultKeyframeInterval = 60
+
+// ErrMissingKeyframe is returned when a delta frame is read before any keyframe, e.g. from a
+// stream that was truncated at the front.
+var ErrMissingKeyframe = errors.New("delta frame without preceding keyframe")
+
+// deltaFrame is one line of a delta-encoded stream. A keyframe carries every field of the
+// snapshot; a delta carries only fields whose encoding changed since the previous snapshot, plus
+// the names of fields that were dropped (omitempty fields that became empty).
+type deltaFrame struct {
+	Key   map[string]json.RawMessage `json:"k,omitempty"`
+	Delta map[string]json.RawMessage `json:"d,omitempty"`
+	Unset []string                   `json:"u,omitempty"`
+}
+
+// DeltaEncoder writes snapshots as JSON lines, storing only the fields that changed from the
+// previous snapshot. A full keyframe is written every KeyframeInterval snapshots so a reader can
+// resynchronize and a damaged frame only affects the records up to the next keyframe.
+// It is not safe for concurrent use.
+type DeltaEncoder struct {
+	w        io.Writer
+	interval int
+	prev     map[string]json.RawMessage
+	since    int // Snapshots written since the last keyframe
+}
+
+// NewDeltaEncoder creates an encoder writing to w, with a keyframe every keyframeInterval
+// snapshots (default 60).
+func NewDeltaEncoder(w io.Writer, keyframeInterval int) *DeltaEncoder {
+	if keyframeInterval <= 0 {
+		keyframeInterval = defaultKeyframeInterval
+	}
+	return &DeltaEncoder{w: w, interval: keyframeInterval}
+}
+
+// Encode writes td as a keyframe or a delta against the previous snapshot.
+func (e *DeltaEncoder) Encode(td TelemetryData) error {
+	fields, err := snapshotFields(td)
+	if err != nil {
+		return err
+	}
+
+	var frame deltaFrame
+	if e.prev == nil || e.since >= e.interval {
+		frame.Key = fields
+		e.since = 0
+	} else {
+		frame.Delta = make(map[string]json.RawMessage)
+		for name, value := range fields {
+			if prev, ok := e.prev[name]; !ok || !bytes.Equal(prev, value) {
+				frame.Delta[name] = value
+			}
+		}
+		for name := range e.prev {
+			if _, ok := fields[name]; !ok {
+				frame.Unset = append(frame.Unset, name)
+			}
+		}
+		sort.Strings(frame.Unset)
+	}
+
+	line, err := json.Marshal(frame)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry frame: %w", err)
+	}
+	if _, err := e.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	e.prev = fields
+	e.since++
+	return nil
+}
+
+// Reset makes the next snapshot a keyframe, e.g. after the underlying writer was rotated.
+func (e *DeltaEncoder) Reset() {
+	e.prev = nil
+}
+
+// snapshotFields splits the JSON encoding of td into its top-level fields.
+func snapshotFields(td TelemetryData) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(td)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode telemetry record: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode telemetry record: %w", err)
+	}
+	return fields, nil
+}
+
+// DeltaDecoder reads a stream written by DeltaEncoder. It is not safe for concurrent use.
+type DeltaDecoder struct {
+	scanner *bufio.Scanner
+	cur     map[string]json.RawMessage
+	line    int
+}
+
+// NewDeltaDecoder creates a decoder reading from r.
+func NewDeltaDecoder(r io.Reader) *DeltaDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &DeltaDecoder{scanner: scanner}
+}
+
+// Decode returns the next snapshot, or io.EOF at the end of the stream. Blank lines are skipped.
+func (d *DeltaDecoder) Decode() (TelemetryData, error) {
+	for d.scanner.Scan() {
+		d.line++
+		if len(d.scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame deltaFrame
+		if err := json.Unmarshal(d.scanner.Bytes(), &frame); err != nil {
+			return TelemetryData{}, fmt.Errorf("invalid telemetry frame on line %d: %w", d.line, err)
+		}
+
+		switch {
+		case frame.Key != nil:
+			d.cur = frame.Key
+		case d.cur == nil:
+			return TelemetryData{}, fmt.Errorf("line %d: %w", d.line, ErrMissingKeyframe)
+		default:
+			next := make(map[string]json.RawMessage, len(d.cur)+len(frame.Delta))
+			for name, value := range d.cur {
+				next[name] = value
+			}
+			for name, value := range frame.Delta {
+				next[name] = value
+			}
+			for _, name := range frame.Unset {
+				delete(next, name)
+			}
+			d.cur = next
+		}
+
+		encoded, err := json.Marshal(d.cur)
+		if err != nil {
+			return TelemetryData{}, fmt.Errorf("invalid telemetry frame on line %d: %w", d.line, err)
+		}
+		var td TelemetryData
+		if err := json.Unmarshal(encoded, &td); err != nil {
+			return TelemetryData{}, fmt.Errorf("invalid telemetry frame on line %d: %w", d.line, err)
+		}
+		return td, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return TelemetryData{}, fmt.Errorf("failed to read telemetry stream: %w", err)
+	}
+	return TelemetryData{}, io.EOF
+}
+
+// ReadDeltaJSONL decodes an entire delta-encoded stream, e.g. for ReplaySource.
+func ReadDeltaJSONL(r io.Reader) ([]TelemetryData, error) {
+	dec := NewDeltaDecoder(r)
+	var records []TelemetryData
+	for {
+		td, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, td)
+	}
+}
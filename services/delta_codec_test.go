@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeltaCodecRoundTrip(t *testing.T) {
+	start := time.Unix(1700000000, 0).UTC()
+	var records []TelemetryData
+	for i := 0; i < 7; i++ {
+		td := TelemetryData{
+			Timestamp:                start.Add(time.Duration(i) * 250 * time.Millisecond),
+			PipelineLatencyS9:        200 * time.Millisecond,
+			ResourceLoad_Pct:         0.4,
+			IntegrityHashChainStatus: "SYNCED",
+			Severity:                 SeverityOK,
+			Labels:                   map[string]string{"node": "a"},
+		}
+		if i == 3 {
+			td.PipelineLatencyS9 = 2 * time.Second
+			td.IsGATMViolating = true
+			td.ViolationCauses = []string{"latency"}
+			td.Severity = SeverityWarn
+		}
+		records = append(records, td)
+	}
+
+	var buf bytes.Buffer
+	enc := NewDeltaEncoder(&buf, 5)
+	for _, td := range records {
+		if err := enc.Encode(td); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i, line := range lines {
+		var frame deltaFrame
+		if err := json.Unmarshal([]byte(line), &frame); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if wantKey := i%5 == 0; (frame.Key != nil) != wantKey {
+			t.Errorf("line %d keyframe = %t, want %t", i, frame.Key != nil, wantKey)
+		}
+	}
+	// An unchanged snapshot stores only its timestamp.
+	if !strings.HasPrefix(lines[1], `{"d":{"timestamp":`) || strings.Contains(lines[1], "labels") {
+		t.Errorf("delta frame = %s, want timestamp only", lines[1])
+	}
+	if !strings.Contains(lines[4], `"u":["violation_causes"]`) {
+		t.Errorf("frame after violation = %s, want violation_causes unset", lines[4])
+	}
+
+	got, err := ReadDeltaJSONL(&buf)
+	if err != nil {
+		t.Fatalf("ReadDeltaJSONL failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("decoded records differ:\n got %+v\nwant %+v", got, records)
+	}
+}
+
+func TestDeltaDecoderRequiresKeyframe(t *testing.T) {
+	_, err := ReadDeltaJSONL(strings.NewReader(`{"d":{"resource_load_pct":0.5}}` + "\n"))
+	if !errors.Is(err, ErrMissingKeyframe) {
+		t.Errorf("error = %v, want ErrMissingKeyframe", err)
+	}
+}
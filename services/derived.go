@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+
+	"pkg/errreport"
+	"pkg/recovery"
+	"pkg/ringbuf"
+)
+
+const defaultDerivedWindow = 60
+
+// DerivedMetric is a business metric computed from recent telemetry, e.g. commits per minute. Its
+// value is added to every snapshot under TelemetryData.Derived, where GATM rules read it as
+// telemetry.derived.<name>, the exporter publishes it as sts_derived_metric{metric="<name>"}, and
+// sinks store and return it with the snapshot.
+type DerivedMetric struct {
+	Name string // e.g. "commits_per_minute"
+	// Window is the number of previous snapshots passed to Compute (default 60). Fewer are passed
+	// until that many were collected.
+	Window int
+	// Compute returns the metric for current, which has been collected and enriched but not yet
+	// evaluated, given the previous snapshots ordered from oldest to newest. Previous snapshots
+	// carry the derived values computed for them. On an error, or a NaN or infinite result, the
+	// snapshot carries no value for the metric and the failure is counted in
+	// sts_derived_metric_errors_total.
+	Compute func(history []TelemetryData, current TelemetryData) (float64, error)
+}
+
+// derivedMetrics computes the configured derived metrics over a ring of recent snapshots.
+type derivedMetrics struct {
+	metrics []DerivedMetric
+	history *ringbuf.Buffer[TelemetryData] // Sized for the largest window
+}
+
+// newDerivedMetrics returns nil if ms holds no usable metric. Metrics without a name or Compute
+// are ignored.
+func newDerivedMetrics(ms []DerivedMetric) *derivedMetrics {
+	d := &derivedMetrics{}
+	window := 0
+	for _, m := range ms {
+		if m.Name == "" || m.Compute == nil {
+			continue
+		}
+		if m.Window <= 0 {
+			m.Window = defaultDerivedWindow
+		}
+		window = max(window, m.Window)
+		d.metrics = append(d.metrics, m)
+	}
+	if len(d.metrics) == 0 {
+		return nil
+	}
+	d.history = ringbuf.New[TelemetryData](window, ringbuf.Overwrite)
+	return d
+}
+
+// compute sets td.Derived. A metric that fails or panics is left out; panics are also sent to
+// reporter.
+func (d *derivedMetrics) compute(ctx context.Context, td *TelemetryData, m stsMetrics, reporter errreport.ErrorReporter) {
+	if d == nil {
+		return
+	}
+	history := d.history.Last(d.history.Cap())
+	derived := maps.Clone(td.Derived)
+	if derived == nil {
+		derived = make(map[string]float64, len(d.metrics))
+	}
+	for _, metric := range d.metrics {
+		window := history[max(0, len(history)-metric.Window):]
+		var value float64
+		err := recovery.Call("derived metric "+metric.Name, func() (err error) {
+			value, err = metric.Compute(window, *td)
+			return err
+		})
+		if errors.Is(err, recovery.ErrPanic) {
+			errreport.Report(ctx, reporter, errreport.KindPanic, "derived metric "+metric.Name, err)
+		}
+		if err == nil && (math.IsNaN(value) || math.IsInf(value, 0)) {
+			err = fmt.Errorf("derived metric %s is %v", metric.Name, value)
+		}
+		if err != nil {
+			m.derivedErrors.Inc(metric.Name)
+			delete(derived, metric.Name)
+			continue
+		}
+		derived[metric.Name] = value
+	}
+	td.Derived = derived
+}
+
+// record adds an assessed snapshot to the history.
+func (d *derivedMetrics) record(td TelemetryData) {
+	if d != nil {
+		d.history.Push(cloneSnapshot(td))
+	}
+}
@@ -0,0 +1,42 @@
+package telemetry
+
+import "errors"
+
+// Sentinel errors returned by the STS. Wrapped errors preserve the underlying cause, so callers
+// can branch with errors.Is while still logging the detail.
+var (
+	// ErrCollectionFailed wraps any failure of TelemetrySource.Collect.
+	ErrCollectionFailed = errors.New("telemetry collection failed")
+	// ErrBreachStateUnavailable wraps failures loading or saving shared breach state.
+	ErrBreachStateUnavailable = errors.New("breach state store unavailable")
+	// ErrSinkWriteFailed wraps failures recording a snapshot to the configured sink.
+	ErrSinkWriteFailed = errors.New("telemetry sink write failed")
+	// ErrNoActiveBreach is returned by Acknowledge when there is no breach to acknowledge.
+	ErrNoActiveBreach = errors.New("no active GATM breach to acknowledge")
+	// ErrInvalidTTL is returned by Acknowledge for a non-positive TTL.
+	ErrInvalidTTL = errors.New("acknowledgement ttl must be positive")
+	// ErrNoIncident is returned by ReconstructIncident when history holds no violating snapshot.
+	ErrNoIncident = errors.New("no incident in telemetry history")
+	// ErrIncidentUnavailable wraps sink failures while reconstructing an incident.
+	ErrIncidentUnavailable = errors.New("incident history unavailable")
+	// ErrInvalidTransition is returned by Lifecycle.Fire for a transition the lifecycle does not permit.
+	ErrInvalidTransition = errors.New("invalid lifecycle transition")
+	// ErrTransitionRefused wraps the error of a guard that vetoed a lifecycle transition.
+	ErrTransitionRefused = errors.New("lifecycle transition refused")
+	// ErrInvalidThresholds is returned by UpdateThresholds for thresholds outside their valid ranges.
+	ErrInvalidThresholds = errors.New("invalid GATM thresholds")
+	// ErrThresholdsRejected wraps the reason a runtime threshold change was refused by admission.
+	ErrThresholdsRejected = errors.New("GATM threshold change rejected")
+	// ErrProcessorFailed wraps the error of a pipeline processor, naming its stage.
+	ErrProcessorFailed = errors.New("pipeline processor failed")
+	// ErrOutOfOrder is returned by OrderSink for a record too old to be written in timestamp order.
+	ErrOutOfOrder = errors.New("record out of timestamp order")
+	// ErrRangeUnsupported is returned by QueryRange for a sink that cannot page through its history.
+	ErrRangeUnsupported = errors.New("sink does not support range queries")
+	// ErrInvalidCursor is returned for a range query cursor that was not issued by a previous page.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidHistogram is returned for a histogram whose bounds and counts do not match.
+	ErrInvalidHistogram = errors.New("invalid histogram")
+	// ErrPreflightFailed is returned by Preflight when a check failed that may not degrade.
+	ErrPreflightFailed = errors.New("preflight checks failed")
+)
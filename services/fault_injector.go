@@ -0,0 +1,175 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"pkg/duration"
+)
+
+// FaultKind selects the kind of fault injected into collected telemetry.
+type FaultKind string
+
+const (
+	// FaultLatencySpike overrides PipelineLatencyS9 with Fault.Value (seconds).
+	FaultLatencySpike FaultKind = "latency_spike"
+	// FaultLoadSpike overrides ResourceLoad_Pct with Fault.Value (0.0 - 1.0).
+	FaultLoadSpike FaultKind = "load_spike"
+	// FaultIntegrityDivergence reports the CRoT hash chain as DIVERGED.
+	FaultIntegrityDivergence FaultKind = "integrity_divergence"
+	// FaultCollectionError fails the collection with Fault.Message.
+	FaultCollectionError FaultKind = "collection_error"
+	// FaultCollectionDelay stalls the collection for Fault.Value seconds before delegating.
+	FaultCollectionDelay FaultKind = "collection_delay"
+)
+
+// defaultSpikeLatency matches the artificial latency used by the simulated source.
+const defaultSpikeLatency = 2500 * time.Millisecond
+
+// ErrInjectedFault wraps every error produced by a FaultCollectionError.
+var ErrInjectedFault = errors.New("injected telemetry fault")
+
+// Fault is a single scripted fault, active during [After, After+For) measured from injector start.
+// After and For are written as duration strings, e.g. {"after": "30s", "for": "2m"}, or as integer
+// nanoseconds.
+type Fault struct {
+	Kind    FaultKind         `json:"kind" yaml:"kind"`
+	After   duration.Duration `json:"after" yaml:"after"`
+	For     duration.Duration `json:"for" yaml:"for"`
+	Value   float64           `json:"value,omitempty" yaml:"value,omitempty"`
+	Message string            `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Validate checks that the fault is well-formed.
+func (f Fault) Validate() error {
+	switch f.Kind {
+	case FaultLatencySpike, FaultIntegrityDivergence, FaultCollectionError, FaultCollectionDelay:
+	case FaultLoadSpike:
+		if f.Value < 0 || f.Value > 1 {
+			return fmt.Errorf("fault %s: value must be between [0.0, 1.0]", f.Kind)
+		}
+	default:
+		return fmt.Errorf("unknown fault kind '%s'", f.Kind)
+	}
+	if f.After < 0 || f.For <= 0 {
+		return fmt.Errorf("fault %s: after must not be negative and for must be positive", f.Kind)
+	}
+	return nil
+}
+
+func (f Fault) activeAt(elapsed time.Duration) bool {
+	return elapsed >= f.After.Std() && elapsed < f.After.Std()+f.For.Std()
+}
+
+// FaultInjector wraps a TelemetrySource and deterministically injects scripted faults,
+// replacing ad-hoc random failure generation in tests and chaos drills.
+type FaultInjector struct {
+	source  TelemetrySource
+	faults  []Fault
+	startAt time.Time
+	now     func() time.Time
+	mu      sync.RWMutex
+}
+
+// NewFaultInjector wraps src with the given script. Fault offsets are measured from construction.
+func NewFaultInjector(src TelemetrySource, script []Fault) (*FaultInjector, error) {
+	if src == nil {
+		return nil, errors.New("fault injector requires a telemetry source")
+	}
+	for _, f := range script {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &FaultInjector{
+		source:  src,
+		faults:  append([]Fault(nil), script...),
+		startAt: time.Now(),
+		now:     time.Now,
+	}, nil
+}
+
+// Inject schedules an additional fault at runtime. Its After offset is relative to now.
+func (fi *FaultInjector) Inject(f Fault) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	f.After += duration.Duration(fi.now().Sub(fi.startAt))
+	fi.faults = append(fi.faults, f)
+	return nil
+}
+
+// Clear removes every scheduled fault.
+func (fi *FaultInjector) Clear() {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.faults = nil
+}
+
+// Faults returns the current script with offsets relative to injector start.
+func (fi *FaultInjector) Faults() []Fault {
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+	return append([]Fault(nil), fi.faults...)
+}
+
+// Collect delegates to the wrapped source and applies every active fault.
+func (fi *FaultInjector) Collect(ctx context.Context) (TelemetryData, error) {
+	active := fi.activeFaults()
+
+	for _, f := range active {
+		switch f.Kind {
+		case FaultCollectionError:
+			return TelemetryData{}, fmt.Errorf("%w: %s", ErrInjectedFault, f.Message)
+		case FaultCollectionDelay:
+			timer := time.NewTimer(time.Duration(f.Value * float64(time.Second)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return TelemetryData{}, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	td, err := fi.source.Collect(ctx)
+	if err != nil {
+		return td, err
+	}
+
+	for _, f := range active {
+		switch f.Kind {
+		case FaultLatencySpike:
+			td.PipelineLatencyS9 = defaultSpikeLatency
+			if f.Value > 0 {
+				td.PipelineLatencyS9 = DurationFromSeconds(f.Value)
+			}
+		case FaultLoadSpike:
+			td.ResourceLoad_Pct = f.Value
+		case FaultIntegrityDivergence:
+			td.IntegrityHashChainStatus = "DIVERGED"
+		}
+	}
+	return td, nil
+}
+
+func (fi *FaultInjector) activeFaults() []Fault {
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+	elapsed := fi.now().Sub(fi.startAt)
+	var active []Fault
+	for _, f := range fi.faults {
+		if f.activeAt(elapsed) {
+			active = append(active, f)
+		}
+	}
+	return active
+}
+
+// Ensure FaultInjector implements the TelemetrySource interface.
+var _ TelemetrySource = (*FaultInjector)(nil)
@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"pkg/duration"
+)
+
+// staticSource always returns the same healthy snapshot.
+type staticSource struct{}
+
+func (staticSource) Collect(ctx context.Context) (TelemetryData, error) {
+	return TelemetryData{PipelineLatencyS9: 200 * time.Millisecond, ResourceLoad_Pct: 0.3, IntegrityHashChainStatus: "SYNCED"}, nil
+}
+
+func TestFaultInjector_Collect(t *testing.T) {
+	script := []Fault{
+		{Kind: FaultLatencySpike, After: duration.Duration(10 * time.Second), For: duration.Duration(5 * time.Second), Value: 3},
+		{Kind: FaultIntegrityDivergence, After: duration.Duration(20 * time.Second), For: duration.Duration(5 * time.Second)},
+		{Kind: FaultCollectionError, After: duration.Duration(30 * time.Second), For: duration.Duration(5 * time.Second), Message: "probe down"},
+	}
+	fi, err := NewFaultInjector(staticSource{}, script)
+	if err != nil {
+		t.Fatalf("NewFaultInjector() error = %v", err)
+	}
+	start := fi.startAt
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		wantLatency   time.Duration
+		wantIntegrity string
+		wantErr       bool
+	}{
+		{name: "Before Faults", elapsed: 0, wantLatency: 200 * time.Millisecond, wantIntegrity: "SYNCED"},
+		{name: "Latency Spike", elapsed: 12 * time.Second, wantLatency: 3 * time.Second, wantIntegrity: "SYNCED"},
+		{name: "Integrity Divergence", elapsed: 21 * time.Second, wantLatency: 200 * time.Millisecond, wantIntegrity: "DIVERGED"},
+		{name: "Collection Error", elapsed: 30 * time.Second, wantErr: true},
+		{name: "After Faults", elapsed: time.Minute, wantLatency: 200 * time.Millisecond, wantIntegrity: "SYNCED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi.now = func() time.Time { return start.Add(tt.elapsed) }
+			td, err := fi.Collect(context.Background())
+			if tt.wantErr {
+				if !errors.Is(err, ErrInjectedFault) {
+					t.Fatalf("Collect() error = %v, want ErrInjectedFault", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			if td.PipelineLatencyS9 != tt.wantLatency || td.IntegrityHashChainStatus != tt.wantIntegrity {
+				t.Errorf("Collect() = latency %v, integrity %s; want %v, %s",
+					td.PipelineLatencyS9, td.IntegrityHashChainStatus, tt.wantLatency, tt.wantIntegrity)
+			}
+		})
+	}
+}
+
+func TestFault_UnmarshalJSON(t *testing.T) {
+	input := `[
+		{"kind": "load_spike", "after": "30s", "for": "2m", "value": 0.99},
+		{"kind": "integrity_divergence", "after": 0, "for": 5000000000}
+	]`
+	var got []Fault
+	if err := json.Unmarshal([]byte(input), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := []Fault{
+		{Kind: FaultLoadSpike, After: duration.Duration(30 * time.Second), For: duration.Duration(2 * time.Minute), Value: 0.99},
+		{Kind: FaultIntegrityDivergence, For: duration.Duration(5 * time.Second)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+
+	var f Fault
+	if err := json.Unmarshal([]byte(`{"kind": "load_spike", "after": "soon", "for": "1m"}`), &f); err == nil {
+		t.Error("Unmarshal() with an invalid after succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+
+	"pkg/errreport"
+	"pkg/recovery"
+)
+
+// GATMRule is an additional GATM rule evaluated over TelemetryData, e.g., a compiled CEL expression.
+// Variables are exposed under the "telemetry" key using TelemetryData's JSON field names; derived
+// metrics are a map under telemetry.derived, e.g. telemetry.derived.commits_per_minute.
+type GATMRule interface {
+	RuleName() string
+	Evaluate(ctx context.Context, vars map[string]interface{}) (bool, error)
+}
+
+// ruleVariables builds the rule activation from a telemetry snapshot.
+func ruleVariables(td TelemetryData) map[string]interface{} {
+	return map[string]interface{}{
+		"telemetry": map[string]interface{}{
+			"timestamp":           td.Timestamp,
+			"pipeline_latency_s9": td.PipelineLatencyS9.Seconds(),
+			"resource_load_pct":   td.ResourceLoad_Pct,
+			"hash_chain_status":   td.IntegrityHashChainStatus,
+			"gatm_breach_count":   int64(td.GATMBreachCount),
+			"clock_offset_s":      td.ClockOffset.Seconds(),
+			"clock_status":        td.ClockStatus,
+			"latency_p99_s":       latencyP99(td),
+			"derived":             derivedVariables(td),
+		},
+	}
+}
+
+// derivedVariables exposes the derived metrics. The map is never nil, so rules can test for a
+// metric with has() or "in" before it is first computed.
+func derivedVariables(td TelemetryData) map[string]interface{} {
+	vars := make(map[string]interface{}, len(td.Derived))
+	for name, value := range td.Derived {
+		vars[name] = value
+	}
+	return vars
+}
+
+// violatedRules returns a cause for every rule that is violated.
+// A rule that fails to evaluate (including exceeding its cost limit or panicking) counts as a breach, so a
+// misbehaving rule can never silently mask a real anomaly. Panics are also sent to reporter.
+func violatedRules(ctx context.Context, rules []GATMRule, td TelemetryData, reporter errreport.ErrorReporter) []string {
+	vars := ruleVariables(td)
+	var causes []string
+	for _, rule := range rules {
+		var violated bool
+		err := recovery.Call("GATM rule "+rule.RuleName(), func() (err error) {
+			violated, err = rule.Evaluate(ctx, vars)
+			return err
+		})
+		if errors.Is(err, recovery.ErrPanic) {
+			errreport.Report(ctx, reporter, errreport.KindPanic, "GATM rule "+rule.RuleName(), err)
+		}
+		if err != nil || violated {
+			causes = append(causes, CauseRulePrefix+rule.RuleName())
+		}
+	}
+	return causes
+}
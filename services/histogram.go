@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of NewLatencyHistogram's buckets.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Histogram is a bucketed distribution of observations, e.g. the S9 latencies seen during one
+// collection interval. Averages hide the tail that GATM cares about; a histogram keeps it through
+// aggregation, since histograms merge by adding bucket counts.
+//
+// Counts holds one count per bucket, not cumulative: Counts[i] observations were at most Bounds[i]
+// (and above Bounds[i-1]), and the last entry counts observations above every bound.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"` // Bucket upper bounds, ascending
+	Counts []uint64  `json:"counts"` // len(Bounds)+1 bucket counts
+	Sum    float64   `json:"sum"`    // Sum of all observations
+}
+
+// NewHistogram creates an empty histogram with the given ascending bucket upper bounds.
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{Bounds: slices.Clone(bounds), Counts: make([]uint64, len(bounds)+1)}
+}
+
+// NewLatencyHistogram creates an empty histogram with DefaultLatencyBuckets.
+func NewLatencyHistogram() *Histogram {
+	return NewHistogram(DefaultLatencyBuckets)
+}
+
+// Validate checks that the bounds are ascending and there is one count per bucket.
+func (h *Histogram) Validate() error {
+	if len(h.Counts) != len(h.Bounds)+1 {
+		return fmt.Errorf("%w: %d counts for %d bounds", ErrInvalidHistogram, len(h.Counts), len(h.Bounds))
+	}
+	for i := 1; i < len(h.Bounds); i++ {
+		if !(h.Bounds[i] > h.Bounds[i-1]) {
+			return fmt.Errorf("%w: bounds not ascending at %v", ErrInvalidHistogram, h.Bounds[i])
+		}
+	}
+	return nil
+}
+
+// Observe adds one observation.
+func (h *Histogram) Observe(v float64) {
+	h.Counts[sort.SearchFloat64s(h.Bounds, v)]++
+	h.Sum += v
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	var n uint64
+	for _, c := range h.Counts {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the mean observation, or 0 for an empty histogram.
+func (h *Histogram) Mean() float64 {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	return h.Sum / float64(n)
+}
+
+// Merge adds other's observations. Histograms with other bounds are re-bucketed onto h's: each of
+// other's buckets is counted in the first of h's buckets whose bound covers it, so merged
+// quantiles can only overestimate the tail, never hide it.
+func (h *Histogram) Merge(other *Histogram) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+	if err := other.Validate(); err != nil {
+		return err
+	}
+	if slices.Equal(h.Bounds, other.Bounds) {
+		for i, c := range other.Counts {
+			h.Counts[i] += c
+		}
+	} else {
+		for i, c := range other.Counts {
+			upper := math.Inf(1)
+			if i < len(other.Bounds) {
+				upper = other.Bounds[i]
+			}
+			h.Counts[sort.SearchFloat64s(h.Bounds, upper)] += c
+		}
+	}
+	h.Sum += other.Sum
+	return nil
+}
+
+// Clone returns a deep copy of h; it is nil for a nil h.
+func (h *Histogram) Clone() *Histogram {
+	if h == nil {
+		return nil
+	}
+	return &Histogram{Bounds: slices.Clone(h.Bounds), Counts: slices.Clone(h.Counts), Sum: h.Sum}
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) by linear interpolation within its bucket, as
+// Prometheus' histogram_quantile does: the first bucket starts at 0, and a quantile falling above
+// every bound is reported as the highest bound. It returns 0 for an empty histogram.
+func (h *Histogram) Quantile(q float64) float64 {
+	n := h.Count()
+	if n == 0 || len(h.Bounds) == 0 {
+		return 0
+	}
+	q = min(max(q, 0), 1)
+	rank := q * float64(n)
+	var cumulative float64
+	for i, c := range h.Counts {
+		if i == len(h.Bounds) {
+			break
+		}
+		if cumulative+float64(c) >= rank && c > 0 {
+			lower := 0.0
+			if i > 0 {
+				lower = h.Bounds[i-1]
+			}
+			return lower + (h.Bounds[i]-lower)*(rank-cumulative)/float64(c)
+		}
+		cumulative += float64(c)
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+// latencyP99 estimates the snapshot's 99th percentile S9 latency in seconds, falling back to the
+// point latency when the source reports no histogram.
+func latencyP99(td TelemetryData) float64 {
+	if td.LatencyHistogram == nil || td.LatencyHistogram.Count() == 0 {
+		return td.PipelineLatencyS9.Seconds()
+	}
+	return td.LatencyHistogram.Quantile(0.99)
+}
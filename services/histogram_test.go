@@ -0,0 +1,61 @@
+package telemetry
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 2, 4})
+	for _, v := range []float64{0.5, 1.5, 1.5, 3, 10} {
+		h.Observe(v)
+	}
+	if want := []uint64{1, 2, 1, 1}; !slices.Equal(h.Counts, want) || h.Count() != 5 || h.Sum != 16.5 {
+		t.Fatalf("after Observe counts = %v, sum = %v, want %v and 16.5", h.Counts, h.Sum, want)
+	}
+
+	tests := []struct {
+		name string
+		q    float64
+		want float64
+	}{
+		{name: "Median", q: 0.5, want: 1.75},
+		{name: "First Bucket", q: 0.1, want: 0.5},
+		{name: "Overflow", q: 0.99, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Quantile(tt.q); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Quantile(%v) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Merge", func(t *testing.T) {
+		merged := h.Clone()
+		if err := merged.Merge(h); err != nil {
+			t.Fatal(err)
+		}
+		if want := []uint64{2, 4, 2, 2}; !slices.Equal(merged.Counts, want) || merged.Sum != 33 {
+			t.Errorf("merged counts = %v, sum = %v, want %v and 33", merged.Counts, merged.Sum, want)
+		}
+		if h.Count() != 5 {
+			t.Error("Merge into a clone changed the original")
+		}
+
+		// Buckets of other bounds land in the first bucket covering them.
+		coarse := NewHistogram([]float64{2, 8})
+		if err := coarse.Merge(h); err != nil {
+			t.Fatal(err)
+		}
+		if want := []uint64{3, 1, 1}; !slices.Equal(coarse.Counts, want) {
+			t.Errorf("re-bucketed counts = %v, want %v", coarse.Counts, want)
+		}
+
+		if err := merged.Merge(&Histogram{Bounds: []float64{1}, Counts: []uint64{1}}); !errors.Is(err, ErrInvalidHistogram) {
+			t.Errorf("Merge() of a malformed histogram error = %v, want ErrInvalidHistogram", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// Timeline events recorded in an Incident.
+const (
+	IncidentEventViolationStarted = "violation_started"
+	IncidentEventSeverityChanged  = "severity_changed"
+	IncidentEventCausesChanged    = "causes_changed"
+	IncidentEventSilenced         = "silenced"
+	IncidentEventUnsilenced       = "unsilenced"
+	IncidentEventAcknowledged     = "acknowledged"
+	IncidentEventAckExpired       = "acknowledgement_expired"
+	IncidentEventEscalated        = "escalated"
+	IncidentEventCleared          = "cleared"
+)
+
+// IncidentTransition is one state change during an incident.
+type IncidentTransition struct {
+	At          time.Time `json:"at"`
+	Event       string    `json:"event"`
+	Severity    Severity  `json:"severity"`
+	Causes      []string  `json:"causes,omitempty"`
+	BreachCount int       `json:"breach_count"`
+}
+
+// Incident is the reconstructed timeline of the most recent GATM violation, for postmortems.
+type Incident struct {
+	StartedAt    time.Time            `json:"started_at"`
+	EndedAt      time.Time            `json:"ended_at,omitempty"` // Zero while Ongoing
+	Ongoing      bool                 `json:"ongoing"`
+	FirstCauses  []string             `json:"first_causes"` // Rules that fired first
+	PeakSeverity Severity             `json:"peak_severity"`
+	PeakBreaches int                  `json:"peak_breaches"`
+	Samples      int                  `json:"samples"`     // Snapshots covered by the incident
+	Escalations  []time.Time          `json:"escalations"` // When escalation handlers were dispatched
+	Transitions  []IncidentTransition `json:"transitions"`
+}
+
+// WriteJSON exports the incident as indented JSON.
+func (inc *Incident) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(inc)
+}
+
+// ReconstructIncident rebuilds the most recent incident from the last n records in sink history.
+// An incident is the last contiguous run of violating snapshots; it is Ongoing if the newest
+// snapshot is still violating. Escalations are inferred with the service's dispatch rule: a change
+// of severity or causes while neither silenced nor acknowledged. If the run begins at the oldest
+// record fetched, StartedAt is only a lower bound; increase n to see further back.
+func ReconstructIncident(ctx context.Context, sink TelemetrySink, n int) (*Incident, error) {
+	history, err := sink.QueryLastN(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIncidentUnavailable, err)
+	}
+
+	end := len(history) - 1
+	for end >= 0 && !history[end].IsGATMViolating {
+		end--
+	}
+	if end < 0 {
+		return nil, ErrNoIncident
+	}
+	start := end
+	for start > 0 && history[start-1].IsGATMViolating {
+		start--
+	}
+
+	first := history[start]
+	inc := &Incident{
+		StartedAt:    first.Timestamp,
+		Ongoing:      end == len(history)-1,
+		FirstCauses:  first.ViolationCauses,
+		PeakSeverity: first.Severity,
+		Samples:      end - start + 1,
+	}
+	replaced := TelemetryData{Severity: SeverityOK}
+	if start > 0 {
+		replaced = history[start-1]
+	}
+
+	var prev *TelemetryData
+	for i := start; i <= end; i++ {
+		td := history[i]
+		add := func(event string) {
+			inc.Transitions = append(inc.Transitions, IncidentTransition{
+				At: td.Timestamp, Event: event, Severity: td.Severity, Causes: td.ViolationCauses, BreachCount: td.GATMBreachCount,
+			})
+		}
+		if prev == nil {
+			add(IncidentEventViolationStarted)
+		} else {
+			if td.Severity != prev.Severity {
+				add(IncidentEventSeverityChanged)
+			}
+			if !slices.Equal(td.ViolationCauses, prev.ViolationCauses) {
+				add(IncidentEventCausesChanged)
+			}
+			switch {
+			case td.IsSilenced && !prev.IsSilenced:
+				add(IncidentEventSilenced)
+			case !td.IsSilenced && prev.IsSilenced:
+				add(IncidentEventUnsilenced)
+			}
+			switch {
+			case td.IsAcknowledged && !prev.IsAcknowledged:
+				add(IncidentEventAcknowledged)
+			case !td.IsAcknowledged && prev.IsAcknowledged:
+				add(IncidentEventAckExpired)
+			}
+		}
+		if dispatchDue(replaced, td) {
+			inc.Escalations = append(inc.Escalations, td.Timestamp)
+			add(IncidentEventEscalated)
+		}
+		if td.Severity.Rank() > inc.PeakSeverity.Rank() {
+			inc.PeakSeverity = td.Severity
+		}
+		inc.PeakBreaches = max(inc.PeakBreaches, td.GATMBreachCount)
+		replaced = td
+		prev = &history[i]
+	}
+
+	if !inc.Ongoing {
+		cleared := history[end+1]
+		inc.EndedAt = cleared.Timestamp
+		inc.Transitions = append(inc.Transitions, IncidentTransition{
+			At: cleared.Timestamp, Event: IncidentEventCleared, Severity: cleared.Severity, BreachCount: cleared.GATMBreachCount,
+		})
+	}
+	return inc, nil
+}
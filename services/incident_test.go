@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReconstructIncident(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return t0.Add(time.Duration(i) * time.Minute) }
+	sink := &sliceSink{records: []TelemetryData{
+		{Timestamp: at(0), Severity: SeverityOK},
+		{Timestamp: at(1), Severity: SeverityDegraded, IsGATMViolating: true, ViolationCauses: []string{"latency"}, GATMBreachCount: 1},
+		{Timestamp: at(2), Severity: SeverityCritical, IsGATMViolating: true, ViolationCauses: []string{"latency", "load"}, GATMBreachCount: 2},
+		{Timestamp: at(3), Severity: SeverityCritical, IsGATMViolating: true, ViolationCauses: []string{"latency", "load"}, GATMBreachCount: 3, IsAcknowledged: true},
+		{Timestamp: at(4), Severity: SeverityOK},
+	}}
+
+	inc, err := ReconstructIncident(context.Background(), sink, 100)
+	if err != nil {
+		t.Fatalf("ReconstructIncident: %v", err)
+	}
+	if inc.Ongoing || !inc.StartedAt.Equal(at(1)) || !inc.EndedAt.Equal(at(4)) {
+		t.Errorf("incident window = %v..%v (ongoing %v), want %v..%v", inc.StartedAt, inc.EndedAt, inc.Ongoing, at(1), at(4))
+	}
+	if inc.PeakSeverity != SeverityCritical || inc.PeakBreaches != 3 || inc.Samples != 3 {
+		t.Errorf("peak = %s/%d over %d samples", inc.PeakSeverity, inc.PeakBreaches, inc.Samples)
+	}
+	if len(inc.FirstCauses) != 1 || inc.FirstCauses[0] != "latency" {
+		t.Errorf("first causes = %v", inc.FirstCauses)
+	}
+	if len(inc.Escalations) != 2 || !inc.Escalations[0].Equal(at(1)) || !inc.Escalations[1].Equal(at(2)) {
+		t.Errorf("escalations = %v, want [%v %v]", inc.Escalations, at(1), at(2))
+	}
+
+	var events []string
+	for _, tr := range inc.Transitions {
+		events = append(events, tr.Event)
+	}
+	want := []string{
+		IncidentEventViolationStarted, IncidentEventEscalated,
+		IncidentEventSeverityChanged, IncidentEventCausesChanged, IncidentEventEscalated,
+		IncidentEventAcknowledged,
+		IncidentEventCleared,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("transitions = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", events, want)
+		}
+	}
+
+	if _, err := ReconstructIncident(context.Background(), &sliceSink{records: sink.records[:1]}, 100); !errors.Is(err, ErrNoIncident) {
+		t.Errorf("healthy history: err = %v, want ErrNoIncident", err)
+	}
+}
@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"pkg/correlation"
+)
+
+// LifecycleState is the escalation level of the service as a whole, derived from the GATM
+// assessment of each cycle.
+type LifecycleState string
+
+const (
+	StateHealthy    LifecycleState = "HEALTHY"
+	StateWarn       LifecycleState = "WARN"        // Approaching or breaching thresholds, below the escalation trigger
+	StateRRPPending LifecycleState = "RRP_PENDING" // Escalation triggered; recovery (RRP) is due
+	StateSIHActive  LifecycleState = "SIH_ACTIVE"  // Escalation triggered at CRITICAL severity; the node is held (SIH)
+	StateRecovering LifecycleState = "RECOVERING"  // Escalation cleared; waiting for the breach count to drain
+)
+
+// lifecycleTransitions lists the states reachable from each state. De-escalation from RRP_PENDING
+// or SIH_ACTIVE always passes through RECOVERING, and SIH is never downgraded to RRP.
+var lifecycleTransitions = map[LifecycleState][]LifecycleState{
+	StateHealthy:    {StateWarn, StateRRPPending, StateSIHActive},
+	StateWarn:       {StateHealthy, StateRRPPending, StateSIHActive},
+	StateRRPPending: {StateSIHActive, StateRecovering},
+	StateSIHActive:  {StateRecovering},
+	StateRecovering: {StateHealthy, StateWarn, StateRRPPending, StateSIHActive},
+}
+
+// CanTransition reports whether the lifecycle permits moving from one state to another.
+func CanTransition(from, to LifecycleState) bool {
+	for _, s := range lifecycleTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Escalated reports whether the state has triggered escalation (RRP_PENDING or SIH_ACTIVE).
+func (s LifecycleState) Escalated() bool {
+	return s == StateRRPPending || s == StateSIHActive
+}
+
+// Transition records one lifecycle state change.
+type Transition struct {
+	From          LifecycleState `json:"from"`
+	To            LifecycleState `json:"to"`
+	Reason        string         `json:"reason"`
+	At            time.Time      `json:"at"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+}
+
+// LifecycleStatus is the current lifecycle state and how it was reached.
+type LifecycleStatus struct {
+	State          LifecycleState `json:"state"`
+	Since          time.Time      `json:"since"`
+	LastTransition *Transition    `json:"last_transition,omitempty"`
+}
+
+// TransitionGuard may veto a transition by returning an error, e.g. to hold SIH_ACTIVE until an
+// operator releases it.
+type TransitionGuard func(ctx context.Context, t Transition) error
+
+// TransitionHook observes a completed transition. Hooks run synchronously, in registration order,
+// after the state has changed.
+type TransitionHook func(ctx context.Context, t Transition)
+
+// Lifecycle is the escalation state machine. The zero value is not usable; create one with NewLifecycle.
+type Lifecycle struct {
+	mu     sync.Mutex
+	status LifecycleStatus
+	guards []TransitionGuard
+	hooks  []TransitionHook
+}
+
+// NewLifecycle creates a state machine in StateHealthy.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{status: LifecycleStatus{State: StateHealthy, Since: time.Now()}}
+}
+
+// Guard adds a guard consulted before every transition.
+func (l *Lifecycle) Guard(g TransitionGuard) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.guards = append(l.guards, g)
+}
+
+// OnTransition adds a hook called after every transition.
+func (l *Lifecycle) OnTransition(h TransitionHook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// State returns the current state.
+func (l *Lifecycle) State() LifecycleState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status.State
+}
+
+// Status returns the current state and the transition that entered it.
+func (l *Lifecycle) Status() LifecycleStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	status := l.status
+	if status.LastTransition != nil {
+		t := *status.LastTransition
+		status.LastTransition = &t
+	}
+	return status
+}
+
+// Fire moves the machine to state to. Staying in the current state is a no-op. A transition the
+// lifecycle does not permit fails with ErrInvalidTransition, and one vetoed by a guard with
+// ErrTransitionRefused; in both cases the state is unchanged.
+func (l *Lifecycle) Fire(ctx context.Context, to LifecycleState, reason string) error {
+	l.mu.Lock()
+	from := l.status.State
+	if from == to {
+		l.mu.Unlock()
+		return nil
+	}
+	if !CanTransition(from, to) {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+	}
+	t := Transition{From: from, To: to, Reason: reason, At: time.Now(), CorrelationID: correlation.FromContext(ctx)}
+	for _, guard := range l.guards {
+		if err := guard(ctx, t); err != nil {
+			l.mu.Unlock()
+			return fmt.Errorf("%w: %s -> %s: %w", ErrTransitionRefused, from, to, err)
+		}
+	}
+	l.status = LifecycleStatus{State: to, Since: t.At, LastTransition: &t}
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx, t)
+	}
+	return nil
+}
+
+// nextLifecycleState derives the state a snapshot calls for. escalating is the escalation trigger
+// of the configured mode, already suppressed by silences and acknowledgements.
+func nextLifecycleState(current LifecycleState, td TelemetryData, escalating bool) (LifecycleState, string) {
+	switch {
+	case escalating && (td.Severity == SeverityCritical || current == StateSIHActive):
+		return StateSIHActive, "escalation triggered at " + string(td.Severity)
+	case escalating:
+		return StateRRPPending, "escalation triggered at " + string(td.Severity)
+	case current.Escalated() && (td.IsSilenced || td.IsAcknowledged):
+		// Escalation is paused, not cleared: the incident is still being handled.
+		return current, ""
+	case current.Escalated():
+		return StateRecovering, "escalation cleared"
+	case current == StateRecovering && td.GATMBreachCount > 0:
+		return StateRecovering, ""
+	case td.IsGATMViolating || td.Severity.Rank() > 0:
+		return StateWarn, "severity " + string(td.Severity)
+	default:
+		return StateHealthy, "within thresholds"
+	}
+}
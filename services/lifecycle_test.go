@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLifecycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []time.Duration // One collection each; threshold 1s, CRITICAL from 1.5s
+		reset     bool            // ResetBreachCount after the collections; replaces the last state
+		want      []LifecycleState
+	}{
+		{
+			name:      "Warn Then Healthy",
+			latencies: []time.Duration{950 * time.Millisecond, 100 * time.Millisecond},
+			want:      []LifecycleState{StateWarn, StateHealthy},
+		},
+		{
+			name:      "Escalates And Recovers",
+			latencies: []time.Duration{1200 * time.Millisecond, 1200 * time.Millisecond, 100 * time.Millisecond, 100 * time.Millisecond},
+			want:      []LifecycleState{StateWarn, StateRRPPending, StateRecovering, StateHealthy},
+		},
+		{
+			name:      "Critical Escalation Holds SIH",
+			latencies: []time.Duration{2 * time.Second, 2 * time.Second, 1200 * time.Millisecond},
+			want:      []LifecycleState{StateWarn, StateSIHActive, StateSIHActive},
+		},
+		{
+			name:      "Reset Recovers",
+			latencies: []time.Duration{1200 * time.Millisecond, 1200 * time.Millisecond},
+			reset:     true,
+			want:      []LifecycleState{StateWarn, StateRecovering}, // RRP_PENDING, then reset
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := NewLifecycle()
+			var hooked []LifecycleState
+			lc.OnTransition(func(ctx context.Context, tr Transition) { hooked = append(hooked, tr.To) })
+			sts := NewSovereignTelemetryService(STSConfiguration{
+				LatencyThreshold: time.Second,
+				MaxBreaches:      2,
+				Lifecycle:        lc,
+			}, &scriptedSource{latencies: tt.latencies})
+
+			var got []LifecycleState
+			for range tt.latencies {
+				if err := sts.CollectNow(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, sts.Lifecycle().State)
+			}
+			if tt.reset {
+				sts.ResetBreachCount()
+				got = append(got[:len(got)-1], sts.Lifecycle().State)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("states = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("states = %v, want %v", got, tt.want)
+				}
+			}
+			if len(hooked) == 0 || hooked[len(hooked)-1] != got[len(got)-1] {
+				t.Errorf("hooks saw %v, want last %s", hooked, got[len(got)-1])
+			}
+		})
+	}
+}
+
+func TestLifecycle_Guards(t *testing.T) {
+	lc := NewLifecycle()
+	if err := lc.Fire(context.Background(), StateRecovering, "test"); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("HEALTHY -> RECOVERING error = %v, want ErrInvalidTransition", err)
+	}
+	lc.Guard(func(ctx context.Context, tr Transition) error {
+		if tr.From == StateSIHActive {
+			return errors.New("held for operator release")
+		}
+		return nil
+	})
+	if err := lc.Fire(context.Background(), StateSIHActive, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Fire(context.Background(), StateRecovering, "test"); !errors.Is(err, ErrTransitionRefused) {
+		t.Fatalf("guarded transition error = %v, want ErrTransitionRefused", err)
+	}
+	if lc.State() != StateSIHActive {
+		t.Errorf("State() = %s after refused transition, want SIH_ACTIVE", lc.State())
+	}
+}
+
+func TestLifecycle_GuardHoldsEscalation(t *testing.T) {
+	lc := NewLifecycle()
+	lc.Guard(func(ctx context.Context, tr Transition) error {
+		if tr.From == StateSIHActive {
+			return errors.New("held for operator release")
+		}
+		return nil
+	})
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		LatencyThreshold: time.Second,
+		MaxBreaches:      2,
+		Lifecycle:        lc,
+	}, &scriptedSource{latencies: []time.Duration{2 * time.Second, 2 * time.Second, 100 * time.Millisecond}})
+
+	for i := 0; i < 3; i++ {
+		sts.CollectNow(context.Background()) // The last cycle fails to leave SIH_ACTIVE
+	}
+	if !sts.CheckGATMViolation() {
+		t.Errorf("CheckGATMViolation() = false while the guard holds %s", lc.State())
+	}
+
+	before := sts.GetHealthStatus().GATMBreachCount
+	if err := sts.ResetBreachCount(); !errors.Is(err, ErrTransitionRefused) {
+		t.Fatalf("ResetBreachCount() error = %v, want ErrTransitionRefused", err)
+	}
+	if got := sts.GetHealthStatus().GATMBreachCount; got != before || before == 0 {
+		t.Errorf("breach count after refused reset = %d, want %d", got, before)
+	}
+	if lc.State() != StateSIHActive || !sts.CheckGATMViolation() {
+		t.Errorf("state after refused reset = %s, want SIH_ACTIVE and still escalated", lc.State())
+	}
+}
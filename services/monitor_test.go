@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMonitor_SubscriberLimitAndReaping(t *testing.T) {
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		MaxMonitorSubscribers: 1,
+		MonitorIdleTimeout:    50 * time.Millisecond,
+	}, &scriptedSource{latencies: []time.Duration{100 * time.Millisecond}})
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Never read beyond the first snapshot and never cancel: the stream must be reaped.
+	abandoned := sts.Monitor(context.Background(), time.Millisecond)
+	<-abandoned
+	if sts.MonitorSubscribers() != 1 {
+		t.Fatalf("MonitorSubscribers = %d, want 1", sts.MonitorSubscribers())
+	}
+	if _, open := <-sts.Monitor(context.Background(), time.Millisecond); open {
+		t.Fatal("subscriber beyond the limit was accepted")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for sts.MonitorSubscribers() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("abandoned subscriber was not reaped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := sts.Monitor(ctx, time.Hour)
+	if _, open := <-stream; !open {
+		t.Fatal("subscriber rejected after the abandoned one was reaped")
+	}
+	cancel()
+	for range stream {
+	}
+}
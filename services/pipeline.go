@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"pkg/recovery"
+)
+
+// Stage is a step of the collection pipeline. Every cycle runs the stages in order:
+// collect → enrich → evaluate → persist → notify.
+type Stage string
+
+const (
+	// StageCollect runs after the source returned a snapshot, e.g. to normalize raw metrics.
+	StageCollect Stage = "collect"
+	// StageEnrich adds context to the snapshot, e.g. tagging it with the deployment version.
+	StageEnrich Stage = "enrich"
+	// StageEvaluate runs after the GATM thresholds and rules were checked, before the breach count is
+	// updated. Processors may add ViolationCauses; the snapshot violates GATM if any cause remains.
+	StageEvaluate Stage = "evaluate"
+	// StagePersist runs after the snapshot was saved to the state store and sink.
+	StagePersist Stage = "persist"
+	// StageNotify runs after escalation handlers were dispatched.
+	StageNotify Stage = "notify"
+)
+
+// Stages lists the pipeline stages in execution order.
+var Stages = []Stage{StageCollect, StageEnrich, StageEvaluate, StagePersist, StageNotify}
+
+// Processor is a custom step of the collection pipeline. Processors at the collect, enrich and
+// evaluate stages may modify the snapshot, and an error from them fails the cycle before any state
+// changes. At the persist and notify stages the snapshot is already recorded: changes to it are
+// not kept, and an error is returned from the cycle after its assessment took effect.
+type Processor interface {
+	Process(ctx context.Context, td *TelemetryData) error
+}
+
+// ProcessorFunc adapts a function to Processor.
+type ProcessorFunc func(ctx context.Context, td *TelemetryData) error
+
+func (f ProcessorFunc) Process(ctx context.Context, td *TelemetryData) error { return f(ctx, td) }
+
+// StageProcessor binds a Processor to the stage it runs at.
+type StageProcessor struct {
+	Stage     Stage
+	Name      string // Identifies the processor in errors and panic reports
+	Processor Processor
+}
+
+// LabelProcessor is an enrich-stage processor adding static labels, e.g. {"deployment": "v2.4.1"},
+// without overwriting labels the source already set.
+func LabelProcessor(labels map[string]string) StageProcessor {
+	return StageProcessor{Stage: StageEnrich, Name: "labels", Processor: ProcessorFunc(func(_ context.Context, td *TelemetryData) error {
+		if len(labels) == 0 {
+			return nil
+		}
+		merged := make(map[string]string, len(td.Labels)+len(labels))
+		for k, v := range labels {
+			merged[k] = v
+		}
+		for k, v := range td.Labels {
+			merged[k] = v
+		}
+		td.Labels = merged
+		return nil
+	})}
+}
+
+// runStage runs the processors registered for stage in order, stopping at the first error. A
+// panicking processor fails the stage like an error.
+func (s *sovereignTelemetryService) runStage(ctx context.Context, stage Stage, td *TelemetryData) error {
+	for _, p := range s.cfg.Processors {
+		if p.Stage != stage {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("%T", p.Processor)
+		}
+		if err := recovery.Call("pipeline processor "+name, func() error { return p.Processor.Process(ctx, td) }); err != nil {
+			return fmt.Errorf("%w: %s stage processor %s: %w", ErrProcessorFailed, stage, name, err)
+		}
+	}
+	return nil
+}
+
+// cloneSnapshot copies the snapshot's labels, causes and other shared values, so persist and
+// notify processors cannot modify the recorded state through them.
+func cloneSnapshot(td TelemetryData) TelemetryData {
+	td.Labels = maps.Clone(td.Labels)
+	td.ViolationCauses = slices.Clone(td.ViolationCauses)
+	td.LatencyHistogram = td.LatencyHistogram.Clone()
+	td.Derived = maps.Clone(td.Derived)
+	return td
+}
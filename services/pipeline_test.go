@@ -0,0 +1,122 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollectNow_Pipeline(t *testing.T) {
+	var order []Stage
+	trace := func(stage Stage, err error) StageProcessor {
+		return StageProcessor{Stage: stage, Processor: ProcessorFunc(func(ctx context.Context, td *TelemetryData) error {
+			order = append(order, stage)
+			return err
+		})}
+	}
+	sink := &sliceSink{}
+	canary := StageProcessor{Stage: StageEvaluate, Name: "canary", Processor: ProcessorFunc(func(ctx context.Context, td *TelemetryData) error {
+		if td.Labels["deployment"] == "v2-canary" {
+			td.ViolationCauses = append(td.ViolationCauses, "canary")
+		}
+		return nil
+	})}
+	errNotify := errors.New("pager unreachable")
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		Sink: sink,
+		Processors: []StageProcessor{
+			trace(StageNotify, errNotify), trace(StagePersist, nil), trace(StageEvaluate, nil),
+			trace(StageEnrich, nil), trace(StageCollect, nil),
+			LabelProcessor(map[string]string{"deployment": "v2-canary"}), canary,
+		},
+	}, &scriptedSource{latencies: []time.Duration{100 * time.Millisecond}})
+
+	err := sts.CollectNow(context.Background())
+	if !errors.Is(err, ErrProcessorFailed) || !errors.Is(err, errNotify) {
+		t.Fatalf("CollectNow() error = %v, want ErrProcessorFailed wrapping the notify processor's error", err)
+	}
+	if want := Stages; !reflect.DeepEqual(order, want) {
+		t.Errorf("stages ran in order %v, want %v", order, want)
+	}
+	got := sts.GetHealthStatus()
+	if !got.IsGATMViolating || !reflect.DeepEqual(got.ViolationCauses, []string{"canary"}) || got.GATMBreachCount != 1 {
+		t.Errorf("snapshot = %+v, want a breach caused by the evaluate processor", got)
+	}
+	if len(sink.records) != 1 || sink.records[0].Labels["deployment"] != "v2-canary" {
+		t.Errorf("sink recorded %+v, want the enriched snapshot", sink.records)
+	}
+}
+
+func TestCollectNow_ProcessorFailureKeepsState(t *testing.T) {
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		Processors: []StageProcessor{{Stage: StageEnrich, Name: "panicky", Processor: ProcessorFunc(func(ctx context.Context, td *TelemetryData) error {
+			panic("lookup table not loaded")
+		})}},
+	}, &scriptedSource{latencies: []time.Duration{time.Hour}})
+
+	if err := sts.CollectNow(context.Background()); !errors.Is(err, ErrProcessorFailed) {
+		t.Fatalf("CollectNow() error = %v, want ErrProcessorFailed", err)
+	}
+	if got := sts.GetHealthStatus(); got.IntegrityHashChainStatus != "INITIALIZING" || got.GATMBreachCount != 0 {
+		t.Errorf("snapshot = %+v, want the state left untouched by a failed enrich stage", got)
+	}
+}
+
+type derivedRule struct{}
+
+func (derivedRule) RuleName() string { return "commit_rate" }
+
+func (derivedRule) Evaluate(ctx context.Context, vars map[string]interface{}) (bool, error) {
+	derived := vars["telemetry"].(map[string]interface{})["derived"].(map[string]interface{})
+	rate, ok := derived["commits_per_minute"].(float64)
+	return ok && rate < 30, nil
+}
+
+func TestCollectNow_DerivedMetrics(t *testing.T) {
+	// A commit resets the S9 latency, so a latency drop between snapshots counts one commit.
+	commitsPerMinute := DerivedMetric{Name: "commits_per_minute", Window: 3, Compute: func(history []TelemetryData, current TelemetryData) (float64, error) {
+		if len(history) == 0 {
+			return 0, errors.New("no history yet")
+		}
+		commits, prev := 0, history[0]
+		for _, td := range append(history[1:], current) {
+			if td.PipelineLatencyS9 < prev.PipelineLatencyS9 {
+				commits++
+			}
+			prev = td
+		}
+		return float64(commits) / current.Timestamp.Sub(history[0].Timestamp).Minutes(), nil
+	}}
+	broken := DerivedMetric{Name: "broken", Compute: func([]TelemetryData, TelemetryData) (float64, error) { panic("division table missing") }}
+	sink := &sliceSink{}
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		Sink:           sink,
+		DerivedMetrics: []DerivedMetric{commitsPerMinute, broken},
+		Rules:          []GATMRule{derivedRule{}},
+	}, &scriptedSource{latencies: []time.Duration{300 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}})
+
+	for i := 0; i < 5; i++ {
+		if err := sts.CollectNow(context.Background()); err != nil {
+			t.Fatalf("CollectNow() #%d error = %v", i, err)
+		}
+	}
+	var got []float64
+	for _, td := range sink.records {
+		if _, ok := td.Derived["broken"]; ok {
+			t.Errorf("snapshot %v carries the value of a panicking metric", td.Timestamp)
+		}
+		if v, ok := td.Derived["commits_per_minute"]; ok {
+			got = append(got, v)
+		}
+	}
+	// The first snapshot has no history; later windows of one to three seconds hold one commit each.
+	if want := []float64{60, 30, 20, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("commits_per_minute = %v, want %v", got, want)
+	}
+	last := sts.GetHealthStatus()
+	if !reflect.DeepEqual(last.ViolationCauses, []string{CauseRulePrefix + "commit_rate"}) {
+		t.Errorf("causes = %v, want the rule reading the derived metric to fire", last.ViolationCauses)
+	}
+}
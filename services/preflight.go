@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"pkg/recovery"
+)
+
+const defaultPreflightTimeout = 10 * time.Second
+
+// Preflight check statuses.
+const (
+	PreflightOK       = "ok"
+	PreflightDegraded = "degraded" // Failed, but the component may start degraded
+	PreflightFailed   = "failed"
+	PreflightSkipped  = "skipped" // Not run after an earlier failure with FailFast
+)
+
+// PreflightCheck verifies one dependency before Run begins.
+type PreflightCheck struct {
+	Component string // e.g. "source", "sink", "gatm_rules"
+	Name      string // What is checked, e.g. "collect" or an endpoint
+	Check     func(ctx context.Context) error
+	// Degrade lets the service start without the component when the check fails, e.g. a sink
+	// that buffers until its server is reachable. Otherwise a failure makes the report not ready.
+	Degrade bool
+}
+
+// PreflightOptions configures Preflight.
+type PreflightOptions struct {
+	// FailFast stops at the first failed check that does not degrade; the rest are skipped.
+	FailFast bool
+	// Timeout bounds each check (default 10s).
+	Timeout time.Duration
+}
+
+// PreflightResult is the outcome of one check.
+type PreflightResult struct {
+	Component string        `json:"component"`
+	Name      string        `json:"name"`
+	Status    string        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration_ns"`
+}
+
+// PreflightReport is the readiness of the service's dependencies before it starts.
+type PreflightReport struct {
+	Ready    bool              `json:"ready"`              // No check failed, though some may be degraded
+	Degraded []string          `json:"degraded,omitempty"` // Components starting degraded
+	Results  []PreflightResult `json:"results"`
+}
+
+// Preflight runs checks in order and reports the readiness of each. It returns an error wrapping
+// ErrPreflightFailed, together with the full report, if a check failed that does not degrade.
+func Preflight(ctx context.Context, checks []PreflightCheck, opts PreflightOptions) (PreflightReport, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultPreflightTimeout
+	}
+	report := PreflightReport{Ready: true, Results: make([]PreflightResult, 0, len(checks))}
+	var failed []string
+	for _, c := range checks {
+		result := PreflightResult{Component: c.Component, Name: c.Name, Status: PreflightSkipped}
+		if opts.FailFast && !report.Ready {
+			report.Results = append(report.Results, result)
+			continue
+		}
+		start := time.Now()
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := recovery.Call("preflight check "+c.Component, func() error { return c.Check(checkCtx) })
+		cancel()
+		result.Duration = time.Since(start)
+		switch {
+		case err == nil:
+			result.Status = PreflightOK
+		case c.Degrade:
+			result.Status, result.Error = PreflightDegraded, err.Error()
+			if !slices.Contains(report.Degraded, c.Component) {
+				report.Degraded = append(report.Degraded, c.Component)
+			}
+		default:
+			result.Status, result.Error = PreflightFailed, err.Error()
+			report.Ready = false
+			failed = append(failed, c.Component+"/"+c.Name)
+		}
+		report.Results = append(report.Results, result)
+	}
+	if !report.Ready {
+		return report, fmt.Errorf("%w: %s", ErrPreflightFailed, strings.Join(failed, ", "))
+	}
+	return report, nil
+}
+
+// SourceCheck verifies that source can collect a snapshot. The snapshot is discarded, so a
+// stateful source such as a replay skips one record.
+func SourceCheck(source TelemetrySource) PreflightCheck {
+	return PreflightCheck{Component: "source", Name: "collect", Check: func(ctx context.Context) error {
+		td, err := source.Collect(ctx)
+		if err != nil {
+			return err
+		}
+		if h := td.LatencyHistogram; h != nil {
+			return h.Validate()
+		}
+		return nil
+	}}
+}
+
+// Pinger is implemented by sinks that can verify their backend accepts writes without recording
+// a snapshot, e.g. by pinging a database server.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingSink pings sink, or the sink wrapped by its middleware, if it is a Pinger. Other sinks are
+// checked by reading their newest record, which at least shows the backend is reachable.
+func PingSink(ctx context.Context, sink TelemetrySink) error {
+	for s := sink; s != nil; {
+		if p, ok := s.(Pinger); ok {
+			return p.Ping(ctx)
+		}
+		u, ok := s.(interface{ Unwrap() TelemetrySink })
+		if !ok {
+			break
+		}
+		s = u.Unwrap()
+	}
+	_, err := sink.QueryLastN(ctx, 1)
+	return err
+}
+
+// SinkCheck verifies sink with PingSink.
+func SinkCheck(sink TelemetrySink) PreflightCheck {
+	return PreflightCheck{Component: "sink", Name: "ping", Check: func(ctx context.Context) error {
+		if err := PingSink(ctx, sink); err != nil {
+			return fmt.Errorf("%w: %w", ErrSinkWriteFailed, err)
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,71 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPreflight(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("unreachable") }
+	tests := []struct {
+		name     string
+		checks   []PreflightCheck
+		opts     PreflightOptions
+		want     []string // Status of every check
+		ready    bool
+		degraded []string
+	}{
+		{
+			name:   "Ready",
+			checks: []PreflightCheck{{Component: "config", Check: ok}, SourceCheck(&scriptedSource{latencies: []time.Duration{time.Second}})},
+			want:   []string{PreflightOK, PreflightOK},
+			ready:  true,
+		},
+		{
+			name:     "Degraded",
+			checks:   []PreflightCheck{{Component: "sink", Check: fail, Degrade: true}, {Component: "source", Check: ok}},
+			want:     []string{PreflightDegraded, PreflightOK},
+			ready:    true,
+			degraded: []string{"sink"},
+		},
+		{
+			name:   "Failed",
+			checks: []PreflightCheck{{Component: "config", Check: fail}, {Component: "source", Check: ok}},
+			want:   []string{PreflightFailed, PreflightOK},
+		},
+		{
+			name:   "Fail Fast",
+			checks: []PreflightCheck{{Component: "config", Check: fail}, {Component: "source", Check: ok}},
+			opts:   PreflightOptions{FailFast: true},
+			want:   []string{PreflightFailed, PreflightSkipped},
+		},
+		{
+			name:   "Panic",
+			checks: []PreflightCheck{{Component: "source", Check: func(context.Context) error { panic("boom") }}},
+			want:   []string{PreflightFailed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Preflight(context.Background(), tt.checks, tt.opts)
+			var got []string
+			for _, r := range report.Results {
+				got = append(got, r.Status)
+			}
+			if !slices.Equal(got, tt.want) || report.Ready != tt.ready || !slices.Equal(report.Degraded, tt.degraded) {
+				t.Errorf("Preflight() statuses = %v, ready = %v, degraded = %v; want %v, %v, %v", got, report.Ready, report.Degraded, tt.want, tt.ready, tt.degraded)
+			}
+			if errors.Is(err, ErrPreflightFailed) == tt.ready {
+				t.Errorf("Preflight() error = %v with ready = %v", err, report.Ready)
+			}
+		})
+	}
+
+	if err := PingSink(context.Background(), ChainSink(&sliceSink{}, CorrelateSink())); err != nil {
+		t.Errorf("PingSink() on a sink without Ping error = %v, want the read fallback to succeed", err)
+	}
+}
@@ -0,0 +1,161 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrReplayExhausted is returned by ReplaySource.Collect once every record has been played back.
+var ErrReplayExhausted = errors.New("telemetry replay exhausted")
+
+// ReplayOptions control playback timing of recorded telemetry.
+type ReplayOptions struct {
+	// Speed scales the original inter-record timing: 1 replays in real time, 10 is ten times faster.
+	// Zero disables pacing and returns records as fast as they are collected.
+	Speed float64
+	// Loop restarts playback from the first record instead of returning ErrReplayExhausted. Each
+	// pass is shifted forward in time to follow the previous one, so timestamps keep increasing.
+	Loop bool
+	// RebaseTimestamps shifts record timestamps so the replay appears to start now,
+	// preserving the original spacing between records regardless of Speed.
+	RebaseTimestamps bool
+}
+
+// ReplaySource is a TelemetrySource that plays back recorded TelemetryData,
+// enabling reproducible incident analysis and integration tests.
+type ReplaySource struct {
+	records []TelemetryData
+	opts    ReplayOptions
+
+	next    int
+	startAt time.Time     // Wall-clock time playback (re)started
+	origin  time.Time     // Wall-clock time the first pass started, for RebaseTimestamps
+	shift   time.Duration // Added to timestamps of the current pass when looping
+	mu      sync.Mutex
+}
+
+// NewReplaySource creates a replay over records, which must be ordered from oldest to newest.
+func NewReplaySource(records []TelemetryData, opts ReplayOptions) (*ReplaySource, error) {
+	if len(records) == 0 {
+		return nil, errors.New("telemetry replay requires at least one record")
+	}
+	if opts.Speed < 0 {
+		return nil, errors.New("telemetry replay speed must not be negative")
+	}
+	return &ReplaySource{records: records, opts: opts}, nil
+}
+
+// ReadJSONL decodes a JSONL export, one TelemetryData object per line. Blank lines are skipped.
+func ReadJSONL(r io.Reader) ([]TelemetryData, error) {
+	var records []TelemetryData
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var td TelemetryData
+		if err := json.Unmarshal(scanner.Bytes(), &td); err != nil {
+			return nil, fmt.Errorf("invalid telemetry record on line %d: %w", line, err)
+		}
+		records = append(records, td)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read telemetry export: %w", err)
+	}
+	return records, nil
+}
+
+// SnapshotSink captures the last n records of a sink for replay.
+func SnapshotSink(ctx context.Context, sink TelemetrySink, n int) ([]TelemetryData, error) {
+	records, err := sink.QueryLastN(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot telemetry sink: %w", err)
+	}
+	return records, nil
+}
+
+// Collect returns the next recorded snapshot, waiting until its scaled offset from the first
+// record has elapsed. Fields STS fills in itself (the GATM assessment, acknowledgement, clock
+// probe and derived metrics) are cleared so the replay is evaluated afresh rather than inheriting
+// the recorded outcome.
+func (r *ReplaySource) Collect(ctx context.Context) (TelemetryData, error) {
+	r.mu.Lock()
+	if r.next == len(r.records) {
+		if !r.opts.Loop {
+			r.mu.Unlock()
+			return TelemetryData{}, ErrReplayExhausted
+		}
+		r.next = 0
+		r.startAt = time.Time{}
+		r.shift += r.passLength()
+	}
+	if r.startAt.IsZero() {
+		r.startAt = time.Now()
+	}
+	if r.origin.IsZero() {
+		r.origin = r.startAt
+	}
+	td := r.records[r.next]
+	offset := td.Timestamp.Sub(r.records[0].Timestamp)
+	startAt, origin, shift := r.startAt, r.origin, r.shift
+	r.next++
+	r.mu.Unlock()
+
+	if r.opts.Speed > 0 {
+		due := startAt.Add(time.Duration(float64(offset) / r.opts.Speed))
+		if wait := time.Until(due); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return TelemetryData{}, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	if r.opts.RebaseTimestamps {
+		td.Timestamp = origin.Add(offset)
+	}
+	td.Timestamp = td.Timestamp.Add(shift)
+	td.GATMBreachCount = 0
+	td.IsGATMViolating = false
+	td.ViolationCauses = nil
+	td.IsSilenced = false
+	td.Severity = ""
+	td.IsAcknowledged = false
+	td.ClockOffset = 0
+	td.ClockStatus = ""
+	td.Derived = nil
+	return td, nil
+}
+
+// passLength is how far each loop shifts timestamps: the recorded span plus the mean interval
+// between records, or one second for a single record, so a pass starts after the previous one ends.
+func (r *ReplaySource) passLength() time.Duration {
+	n := len(r.records)
+	span := r.records[n-1].Timestamp.Sub(r.records[0].Timestamp)
+	if n == 1 || span <= 0 {
+		return span + time.Second
+	}
+	return span + span/time.Duration(n-1)
+}
+
+// Remaining reports how many records are left before the replay is exhausted.
+func (r *ReplaySource) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records) - r.next
+}
+
+// Ensure ReplaySource implements the TelemetrySource interface.
+var _ TelemetrySource = (*ReplaySource)(nil)
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplaySource_ClearsServiceFields(t *testing.T) {
+	records, err := ReadJSONL(strings.NewReader(`{"timestamp":"2024-01-01T00:00:00Z","pipeline_latency_s9":0.2,"hash_chain_status":"SYNCED","gatm_breach_count":4,"is_gatm_violating":true,"violation_causes":["clock_jump"],"is_silenced":true,"is_acknowledged":true,"severity":"CRITICAL","clock_offset_s":3,"clock_status":"STEPPED_BACK","derived":{"cost":1.5},"labels":{"zone":"a"}}
+
+{"timestamp":"2024-01-01T00:00:10Z","pipeline_latency_s9":0.3,"hash_chain_status":"SYNCED"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := NewReplaySource(records, ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	td, err := src.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if td.GATMBreachCount != 0 || td.IsGATMViolating || td.ViolationCauses != nil || td.IsSilenced || td.Severity != "" {
+		t.Errorf("GATM assessment not cleared: %+v", td)
+	}
+	if td.IsAcknowledged || td.ClockOffset != 0 || td.ClockStatus != "" || td.Derived != nil {
+		t.Errorf("acknowledgement, clock or derived fields not cleared: %+v", td)
+	}
+	// Measurements are replayed as recorded.
+	if td.PipelineLatencyS9 != 200*time.Millisecond || td.Labels["zone"] != "a" {
+		t.Errorf("measurements changed: %+v", td)
+	}
+	if records[0].ClockStatus != ClockSteppedBack {
+		t.Error("Collect modified the recorded snapshot")
+	}
+
+	if _, err := src.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.Collect(context.Background()); !errors.Is(err, ErrReplayExhausted) {
+		t.Errorf("Collect() after the last record error = %v, want ErrReplayExhausted", err)
+	}
+}
+
+func TestReplaySource_ReplayedClockJumpIsNotABreach(t *testing.T) {
+	recorded := TelemetryData{
+		Timestamp:                time.Unix(100, 0),
+		PipelineLatencyS9:        100 * time.Millisecond,
+		IntegrityHashChainStatus: "SYNCED",
+		ClockStatus:              ClockSteppedBack,
+		IsAcknowledged:           true,
+	}
+	src, err := NewReplaySource([]TelemetryData{recorded}, ReplayOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sts := NewSovereignTelemetryService(STSConfiguration{LatencyThreshold: time.Second, MaxBreaches: 1}, src)
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if td := sts.GetHealthStatus(); td.IsGATMViolating || td.IsAcknowledged {
+		t.Errorf("replayed snapshot = %+v, want no violation or acknowledgement carried over", td)
+	}
+}
+
+func TestReplaySource_LoopKeepsTimestampsIncreasing(t *testing.T) {
+	records := []TelemetryData{
+		{Timestamp: time.Unix(100, 0), PipelineLatencyS9: 100 * time.Millisecond, IntegrityHashChainStatus: "SYNCED"},
+		{Timestamp: time.Unix(110, 0), PipelineLatencyS9: 100 * time.Millisecond, IntegrityHashChainStatus: "SYNCED"},
+		{Timestamp: time.Unix(120, 0), PipelineLatencyS9: 100 * time.Millisecond, IntegrityHashChainStatus: "SYNCED"},
+	}
+	for _, rebase := range []bool{false, true} {
+		src, err := NewReplaySource(records, ReplayOptions{Loop: true, RebaseTimestamps: rebase})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sts := NewSovereignTelemetryService(STSConfiguration{LatencyThreshold: time.Second, MaxBreaches: 1}, src)
+		var previous time.Time
+		for i := 0; i < 3*len(records); i++ {
+			if err := sts.CollectNow(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			td := sts.GetHealthStatus()
+			if td.IsGATMViolating || td.ClockStatus == ClockSteppedBack {
+				t.Fatalf("rebase=%v: cycle %d = %+v, want no clock_jump across loops", rebase, i, td)
+			}
+			if !td.Timestamp.After(previous) {
+				t.Fatalf("rebase=%v: cycle %d timestamp %v does not follow %v", rebase, i, td.Timestamp, previous)
+			}
+			previous = td.Timestamp
+		}
+		if !rebase && !previous.Equal(time.Unix(180, 0)) {
+			t.Errorf("last timestamp of the third pass = %v, want two 30s passes after the recording", previous.Unix())
+		}
+	}
+}
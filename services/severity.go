@@ -0,0 +1,173 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Severity grades how far telemetry deviates from GATM thresholds.
+type Severity string
+
+const (
+	SeverityOK       Severity = "OK"
+	SeverityWarn     Severity = "WARN"     // Approaching a threshold
+	SeverityDegraded Severity = "DEGRADED" // Threshold exceeded
+	SeverityCritical Severity = "CRITICAL" // Threshold exceeded by the critical ratio, or CRoT divergence
+)
+
+// Default tier boundaries expressed as metric/threshold ratios.
+const (
+	defaultWarnRatio     = 0.9
+	defaultCriticalRatio = 1.5
+)
+
+// Rank orders severities from OK (0) to CRITICAL (3).
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarn:
+		return 1
+	case SeverityDegraded:
+		return 2
+	case SeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// SeverityTiers defines the metric/threshold ratios separating severity tiers.
+type SeverityTiers struct {
+	WarnRatio     float64 // e.g., 0.9: WARN at 90% of a threshold
+	CriticalRatio float64 // e.g., 1.5: CRITICAL at 150% of a threshold
+}
+
+// classify derives the severity of a snapshot given its violation causes.
+func (t SeverityTiers) classify(td TelemetryData, th Thresholds, causes []string) Severity {
+	if td.IntegrityHashChainStatus != "SYNCED" {
+		return SeverityCritical
+	}
+
+	ratio := float64(td.PipelineLatencyS9) / float64(th.LatencyThreshold)
+	if load := td.ResourceLoad_Pct / th.LoadThreshold; load > ratio {
+		ratio = load
+	}
+
+	switch {
+	case ratio >= t.CriticalRatio:
+		return SeverityCritical
+	case len(causes) > 0:
+		// Threshold or CEL rule breached.
+		return SeverityDegraded
+	case ratio >= t.WarnRatio:
+		return SeverityWarn
+	default:
+		return SeverityOK
+	}
+}
+
+// EscalationHandler reacts to a snapshot entering a severity tier (e.g., RRP/SIH triggers, paging).
+type EscalationHandler interface {
+	Escalate(ctx context.Context, td TelemetryData) error
+}
+
+// EscalationHandlerFunc adapts a function to EscalationHandler.
+type EscalationHandlerFunc func(ctx context.Context, td TelemetryData) error
+
+// Escalate implements EscalationHandler.
+func (f EscalationHandlerFunc) Escalate(ctx context.Context, td TelemetryData) error {
+	return f(ctx, td)
+}
+
+// EscalationRouter selects escalation handlers per severity tier and per violation cause.
+type EscalationRouter struct {
+	handlers map[Severity][]EscalationHandler
+	routes   []causeRoute
+	mu       sync.RWMutex
+}
+
+// causeRoute sends escalations with a violation cause matching pattern to its handlers.
+type causeRoute struct {
+	pattern  string
+	handlers []EscalationHandler
+}
+
+// matches reports whether any of causes matches the route's pattern.
+func (r causeRoute) matches(causes []string) bool {
+	for _, cause := range causes {
+		if MatchCause(r.pattern, cause) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchCause reports whether cause matches pattern: either exactly, or, for a pattern ending in
+// "*", by prefix (e.g. "rule:*" matches every CEL rule breach).
+func MatchCause(pattern, cause string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(cause, prefix)
+	}
+	return pattern == cause
+}
+
+// NewEscalationRouter creates an empty router.
+func NewEscalationRouter() *EscalationRouter {
+	return &EscalationRouter{handlers: make(map[Severity][]EscalationHandler)}
+}
+
+// Handle registers a handler for a severity tier.
+func (r *EscalationRouter) Handle(severity Severity, h EscalationHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[severity] = append(r.handlers[severity], h)
+}
+
+// HandleCause registers handlers for violations with a cause matching pattern (see MatchCause),
+// regardless of severity, so e.g. integrity failures reach SIH while load reaches an autoscaler.
+// A route's handlers run at most once per dispatch, however many of the snapshot's causes match.
+func (r *EscalationRouter) HandleCause(pattern string, handlers ...EscalationHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, causeRoute{pattern: pattern, handlers: handlers})
+}
+
+// dispatchDue reports whether current, replacing previous, is escalated: its severity or its set of
+// violation causes changed while it is neither silenced nor acknowledged.
+func dispatchDue(previous, current TelemetryData) bool {
+	if current.IsSilenced || current.IsAcknowledged {
+		return false
+	}
+	return current.Severity != previous.Severity || !slices.Equal(current.ViolationCauses, previous.ViolationCauses)
+}
+
+// Dispatch invokes every handler registered for the snapshot's severity, then the handlers of
+// every cause route matching its violation causes in registration order. A failing handler does
+// not stop the others; their errors are joined.
+func (r *EscalationRouter) Dispatch(ctx context.Context, td TelemetryData) error {
+	r.mu.RLock()
+	handlers := r.handlers[td.Severity]
+	routes := r.routes
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := h.Escalate(ctx, td); err != nil {
+			errs = append(errs, fmt.Errorf("escalation handler for %s failed: %w", td.Severity, err))
+		}
+	}
+	for _, route := range routes {
+		if !route.matches(td.ViolationCauses) {
+			continue
+		}
+		for _, h := range route.handlers {
+			if err := h.Escalate(ctx, td); err != nil {
+				errs = append(errs, fmt.Errorf("escalation handler for cause %s failed: %w", route.pattern, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
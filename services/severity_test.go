@@ -0,0 +1,90 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// sequenceSource returns its snapshots in turn, one second apart, repeating the last.
+type sequenceSource struct {
+	snapshots []TelemetryData
+	i         int
+}
+
+func (s *sequenceSource) Collect(ctx context.Context) (TelemetryData, error) {
+	td := s.snapshots[min(s.i, len(s.snapshots)-1)]
+	s.i++
+	td.Timestamp = time.Unix(int64(s.i), 0)
+	return td, nil
+}
+
+func TestEscalationRouter_DispatchInvokesEveryHandler(t *testing.T) {
+	errPager := errors.New("pager unreachable")
+	errSIH := errors.New("sih rejected")
+	var called []string
+	handler := func(name string, err error) EscalationHandler {
+		return EscalationHandlerFunc(func(ctx context.Context, td TelemetryData) error {
+			called = append(called, name)
+			return err
+		})
+	}
+
+	r := NewEscalationRouter()
+	r.Handle(SeverityCritical, handler("pager", errPager))
+	r.Handle(SeverityCritical, handler("ticket", nil))
+	r.Handle(SeverityWarn, handler("warn", nil))
+	r.HandleCause(CauseIntegrity, handler("sih", errSIH))
+	r.HandleCause("rule:*", handler("rules", nil))
+	r.HandleCause(CauseLoad, handler("autoscaler", nil))
+
+	err := r.Dispatch(context.Background(), TelemetryData{
+		Severity:        SeverityCritical,
+		ViolationCauses: []string{CauseIntegrity, "rule:disk", "rule:cpu"},
+	})
+	if !errors.Is(err, errPager) || !errors.Is(err, errSIH) {
+		t.Errorf("Dispatch() error = %v, want both handler failures", err)
+	}
+	want := []string{"pager", "ticket", "sih", "rules"}
+	if len(called) != len(want) {
+		t.Fatalf("handlers called = %v, want %v", called, want)
+	}
+	for i := range want {
+		if called[i] != want[i] {
+			t.Fatalf("handlers called = %v, want %v", called, want)
+		}
+	}
+
+	called = nil
+	if err := r.Dispatch(context.Background(), TelemetryData{Severity: SeverityOK}); err != nil || len(called) != 0 {
+		t.Errorf("Dispatch() of an OK snapshot = %v, called %v; want no handlers", err, called)
+	}
+}
+
+func TestEscalation_DispatchesOnCauseChange(t *testing.T) {
+	// Both snapshots are CRITICAL on integrity; the second also breaches latency.
+	integrity := TelemetryData{PipelineLatencyS9: 100 * time.Millisecond, IntegrityHashChainStatus: "BROKEN"}
+	latency := integrity
+	latency.PipelineLatencyS9 = 10 * time.Second
+
+	var dispatched [][]string
+	r := NewEscalationRouter()
+	r.Handle(SeverityCritical, EscalationHandlerFunc(func(ctx context.Context, td TelemetryData) error {
+		dispatched = append(dispatched, td.ViolationCauses)
+		return nil
+	}))
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		LatencyThreshold: time.Second,
+		Escalation:       r,
+	}, &sequenceSource{snapshots: []TelemetryData{integrity, latency, latency}})
+
+	for i := 0; i < 3; i++ {
+		if err := sts.CollectNow(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(dispatched) != 2 || len(dispatched[0]) != 1 || len(dispatched[1]) != 2 {
+		t.Errorf("dispatched causes = %v, want one dispatch on entering CRITICAL and one when latency joined", dispatched)
+	}
+}
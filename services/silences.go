@@ -0,0 +1,187 @@
+package telemetry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Violation causes reported in TelemetryData.ViolationCauses and exposed to silence matchers
+// under the "cause" label. CEL rule breaches are reported as "rule:<name>".
+const (
+	CauseLatency    = "latency"
+	CauseLoad       = "load"
+	CauseIntegrity  = "integrity"
+	CauseClockSkew  = "clock_skew"
+	CauseClockJump  = "clock_jump"
+	CauseRulePrefix = "rule:"
+
+	// CauseLabel is the label name carrying the violation cause during silence matching.
+	CauseLabel = "cause"
+)
+
+// Silence sources identify who owns a silence, so config reloads and policy updates only
+// replace their own entries.
+const (
+	SilenceSourceAPI    = "api"
+	SilenceSourceConfig = "config"
+	SilenceSourcePolicy = "policy"
+)
+
+// Matcher selects violations by exact label value.
+type Matcher struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// Silence suppresses breach counting and escalation for matching violations within [StartsAt, EndsAt).
+// A silence without matchers applies to every violation.
+type Silence struct {
+	ID        string    `json:"id"`
+	Source    string    `json:"source"`
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+}
+
+// Active reports whether the silence window covers now.
+func (s Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// matches reports whether every matcher is satisfied by labels.
+func (s Silence) matches(labels map[string]string) bool {
+	for _, m := range s.Matchers {
+		if labels[m.Name] != m.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// SilenceRegistry is the thread-safe store of maintenance windows consulted by STS.
+type SilenceRegistry struct {
+	silences map[string]Silence
+	mu       sync.RWMutex
+}
+
+// NewSilenceRegistry creates an empty registry.
+func NewSilenceRegistry() *SilenceRegistry {
+	return &SilenceRegistry{silences: make(map[string]Silence)}
+}
+
+// Add validates and stores a silence, assigning an ID if none is set. It returns the silence ID.
+func (r *SilenceRegistry) Add(s Silence) (string, error) {
+	if !s.EndsAt.After(s.StartsAt) {
+		return "", errors.New("silence: end time must be after start time")
+	}
+	if s.Source == "" {
+		s.Source = SilenceSourceAPI
+	}
+	if s.ID == "" {
+		s.ID = newSilenceID()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.silences[s.ID] = s
+	return s.ID, nil
+}
+
+// Expire removes a silence by ID. It returns false if the ID is unknown.
+func (r *SilenceRegistry) Expire(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.silences[id]
+	delete(r.silences, id)
+	return ok
+}
+
+// ReplaceSource atomically swaps every silence owned by source for the given set.
+// Config reloads and governance policy updates use this so their silences track the document.
+func (r *SilenceRegistry) ReplaceSource(source string, silences []Silence) error {
+	for _, s := range silences {
+		if !s.EndsAt.After(s.StartsAt) {
+			return errors.New("silence: end time must be after start time")
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, s := range r.silences {
+		if s.Source == source {
+			delete(r.silences, id)
+		}
+	}
+	for _, s := range silences {
+		s.Source = source
+		if s.ID == "" {
+			s.ID = newSilenceID()
+		}
+		r.silences[s.ID] = s
+	}
+	return nil
+}
+
+// List returns all silences, including expired ones not yet pruned, ordered by start time.
+func (r *SilenceRegistry) List() []Silence {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Silence, 0, len(r.silences))
+	for _, s := range r.silences {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartsAt.Before(out[j].StartsAt) })
+	return out
+}
+
+// Prune drops silences that ended before now.
+func (r *SilenceRegistry) Prune(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, s := range r.silences {
+		if !now.Before(s.EndsAt) {
+			delete(r.silences, id)
+		}
+	}
+}
+
+// Silenced reports whether every cause is covered by an active silence.
+// Each cause is matched against the static labels plus cause=<cause>.
+func (r *SilenceRegistry) Silenced(causes []string, labels map[string]string, now time.Time) bool {
+	if len(causes) == 0 {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matchLabels := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	for _, cause := range causes {
+		matchLabels[CauseLabel] = cause
+		covered := false
+		for _, s := range r.silences {
+			if s.Active(now) && s.matches(matchLabels) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func newSilenceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
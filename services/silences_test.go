@@ -0,0 +1,41 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSilenceRegistry_PrunedByEvaluation(t *testing.T) {
+	silences := NewSilenceRegistry()
+	for _, s := range []Silence{
+		{ID: "ended", StartsAt: time.Unix(0, 0), EndsAt: time.Unix(1, 0)},
+		// Ended by the wall clock, but still covering the replayed snapshot.
+		{ID: "replayed", StartsAt: time.Unix(0, 0), EndsAt: time.Unix(100, 0)},
+		{ID: "upcoming", StartsAt: time.Now().Add(time.Hour), EndsAt: time.Now().Add(2 * time.Hour)},
+	} {
+		if _, err := silences.Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The source's first snapshot is stamped time.Unix(1, 0).
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		LatencyThreshold: time.Second,
+		MaxBreaches:      3,
+		Silences:         silences,
+	}, &sequenceSource{snapshots: []TelemetryData{{PipelineLatencyS9: 2 * time.Second, IntegrityHashChainStatus: "SYNCED"}}})
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, s := range silences.List() {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 2 || ids[0] != "replayed" || ids[1] != "upcoming" {
+		t.Errorf("silences after evaluation = %v, want [replayed upcoming]", ids)
+	}
+	if status := sts.GetHealthStatus(); !status.IsSilenced || status.GATMBreachCount != 0 {
+		t.Errorf("GetHealthStatus() = silenced %v with %d breaches, want the breach silenced", status.IsSilenced, status.GATMBreachCount)
+	}
+}
@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SimulationProfile bounds the generated metrics and failure rates.
+// A fully zero-valued profile is replaced by DefaultSimulationProfile.
+type SimulationProfile struct {
+	LatencyMin float64 `json:"latency_min" yaml:"latency_min"` // seconds
+	LatencyMax float64 `json:"latency_max" yaml:"latency_max"` // seconds
+	LoadMin    float64 `json:"load_min" yaml:"load_min"`       // 0.0 - 1.0
+	LoadMax    float64 `json:"load_max" yaml:"load_max"`       // 0.0 - 1.0
+
+	// FailureProbability is the chance (0.0 - 1.0) that a collection produces a failure state.
+	FailureProbability float64 `json:"failure_probability" yaml:"failure_probability"`
+	// DivergenceProbability is the share of failures reported as CRoT divergence; the rest are latency spikes.
+	DivergenceProbability float64 `json:"divergence_probability" yaml:"divergence_probability"`
+	// SpikeLatency is the latency reported during a latency-spike failure (seconds).
+	SpikeLatency float64 `json:"spike_latency" yaml:"spike_latency"`
+}
+
+// DefaultSimulationProfile reproduces the original simulation: latency 0-1.5s, load 0-100%,
+// and a 10% failure chance split evenly between divergence and 2.5s latency spikes.
+func DefaultSimulationProfile() SimulationProfile {
+	return SimulationProfile{
+		LatencyMax:            1.5,
+		LoadMax:               1.0,
+		FailureProbability:    0.1,
+		DivergenceProbability: 0.5,
+		SpikeLatency:          2.5,
+	}
+}
+
+// Validate ensures the profile's bounds and probabilities are coherent.
+func (p SimulationProfile) Validate() error {
+	if p.LatencyMin < 0 || p.LatencyMax < p.LatencyMin {
+		return errors.New("simulation: latency bounds must satisfy 0 <= min <= max")
+	}
+	if p.LoadMin < 0 || p.LoadMax < p.LoadMin || p.LoadMax > 1 {
+		return errors.New("simulation: load bounds must satisfy 0 <= min <= max <= 1")
+	}
+	if p.FailureProbability < 0 || p.FailureProbability > 1 {
+		return errors.New("simulation: failure probability must be between [0.0, 1.0]")
+	}
+	if p.DivergenceProbability < 0 || p.DivergenceProbability > 1 {
+		return errors.New("simulation: divergence probability must be between [0.0, 1.0]")
+	}
+	return nil
+}
+
+// SimulationPhase applies a profile for a fixed number of collections.
+type SimulationPhase struct {
+	Name        string            `json:"name" yaml:"name"`
+	Collections int               `json:"collections" yaml:"collections"`
+	Profile     SimulationProfile `json:"profile" yaml:"profile"`
+}
+
+// SimulationConfig configures the simulated telemetry source.
+type SimulationConfig struct {
+	// Seed makes the generated sequence reproducible. Zero seeds from the current time.
+	Seed int64 `json:"seed" yaml:"seed"`
+	// Profile is used when no scenario is configured.
+	Profile SimulationProfile `json:"profile" yaml:"profile"`
+	// Scenario phases are played in order, advancing by collection count.
+	Scenario []SimulationPhase `json:"scenario,omitempty" yaml:"scenario,omitempty"`
+	// LoopScenario restarts the scenario after its last phase; otherwise the last phase persists.
+	LoopScenario bool `json:"loop_scenario" yaml:"loop_scenario"`
+}
+
+// DegradationScenario returns a healthy → degraded → recovered script with n collections per phase.
+func DegradationScenario(n int) []SimulationPhase {
+	return []SimulationPhase{
+		{Name: "healthy", Collections: n, Profile: SimulationProfile{LatencyMax: 0.5, LoadMin: 0.2, LoadMax: 0.5}},
+		{Name: "degraded", Collections: n, Profile: SimulationProfile{
+			LatencyMin: 0.8, LatencyMax: 1.5, LoadMin: 0.7, LoadMax: 0.95,
+			FailureProbability: 0.4, DivergenceProbability: 0.25, SpikeLatency: 2.5,
+		}},
+		{Name: "recovered", Collections: n, Profile: SimulationProfile{LatencyMax: 0.5, LoadMin: 0.2, LoadMax: 0.5}},
+	}
+}
+
+// simulatedTelemetrySource is a data provider for initialization, examples, and tests.
+type simulatedTelemetrySource struct {
+	cfg         SimulationConfig
+	rng         *rand.Rand // not goroutine-safe; guarded by mu
+	collections int
+	mu          sync.Mutex
+}
+
+// NewSimulatedTelemetrySource validates cfg and returns a reproducible simulated source.
+func NewSimulatedTelemetrySource(cfg SimulationConfig) (TelemetrySource, error) {
+	if cfg.Profile != (SimulationProfile{}) {
+		if err := cfg.Profile.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for _, phase := range cfg.Scenario {
+		if phase.Collections <= 0 {
+			return nil, fmt.Errorf("simulation: phase '%s' must run for a positive number of collections", phase.Name)
+		}
+		if err := phase.Profile.Validate(); err != nil {
+			return nil, fmt.Errorf("phase '%s': %w", phase.Name, err)
+		}
+	}
+	return newSimulatedTelemetrySource(cfg), nil
+}
+
+func newSimulatedTelemetrySource(cfg SimulationConfig) *simulatedTelemetrySource {
+	if cfg.Profile == (SimulationProfile{}) {
+		cfg.Profile = DefaultSimulationProfile()
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	return &simulatedTelemetrySource{cfg: cfg, rng: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+// Collect simulates fetching metrics from system endpoints.
+func (s *simulatedTelemetrySource) Collect(ctx context.Context) (TelemetryData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.currentProfile()
+	s.collections++
+
+	newData := TelemetryData{
+		Timestamp:                time.Now(),
+		PipelineLatencyS9:        DurationFromSeconds(p.LatencyMin + s.rng.Float64()*(p.LatencyMax-p.LatencyMin)),
+		ResourceLoad_Pct:         p.LoadMin + s.rng.Float64()*(p.LoadMax-p.LoadMin),
+		IntegrityHashChainStatus: "SYNCED",
+	}
+	if s.rng.Float64() < p.FailureProbability {
+		if s.rng.Float64() < p.DivergenceProbability {
+			newData.IntegrityHashChainStatus = "DIVERGED"
+		} else {
+			newData.PipelineLatencyS9 = DurationFromSeconds(p.SpikeLatency) // Artificially high latency
+		}
+	}
+
+	return newData, nil
+}
+
+// currentProfile selects the scenario phase for the current collection. Callers must hold s.mu.
+func (s *simulatedTelemetrySource) currentProfile() SimulationProfile {
+	total := 0
+	for _, phase := range s.cfg.Scenario {
+		total += phase.Collections
+	}
+	if total == 0 {
+		return s.cfg.Profile
+	}
+
+	n := s.collections
+	if s.cfg.LoopScenario {
+		n %= total
+	}
+	for _, phase := range s.cfg.Scenario {
+		if n < phase.Collections {
+			return phase.Profile
+		}
+		n -= phase.Collections
+	}
+	return s.cfg.Scenario[len(s.cfg.Scenario)-1].Profile
+}
@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// collectSeconds collects n snapshots from src and returns their latencies in seconds.
+func collectSeconds(t *testing.T, src TelemetrySource, n int) []float64 {
+	t.Helper()
+	out := make([]float64, n)
+	for i := range out {
+		td, err := src.Collect(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = td.PipelineLatencyS9.Seconds()
+	}
+	return out
+}
+
+func TestSimulatedSource_Seed(t *testing.T) {
+	snapshots := func(seed int64) []TelemetryData {
+		src, err := NewSimulatedTelemetrySource(SimulationConfig{Seed: seed, Profile: SimulationProfile{LatencyMax: 2, LoadMax: 1, FailureProbability: 0.3, DivergenceProbability: 0.5, SpikeLatency: 5}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make([]TelemetryData, 50)
+		for i := range out {
+			td, err := src.Collect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Timestamps are wall-clock time, not part of the seeded sequence.
+			out[i] = TelemetryData{PipelineLatencyS9: td.PipelineLatencyS9, ResourceLoad_Pct: td.ResourceLoad_Pct, IntegrityHashChainStatus: td.IntegrityHashChainStatus}
+		}
+		return out
+	}
+	first, again, other := snapshots(42), snapshots(42), snapshots(43)
+	if !reflect.DeepEqual(first, again) {
+		t.Error("the same seed produced different sequences")
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Error("different seeds produced the same sequence")
+	}
+}
+
+func TestSimulatedSource_Scenario(t *testing.T) {
+	fast := SimulationProfile{LatencyMax: 0.1}
+	slow := SimulationProfile{LatencyMin: 1, LatencyMax: 1.1}
+	scenario := []SimulationPhase{{Name: "fast", Collections: 2, Profile: fast}, {Name: "slow", Collections: 3, Profile: slow}}
+
+	tests := []struct {
+		name string
+		loop bool
+		want []bool // Whether each collection is in the slow phase
+	}{
+		{name: "Last Phase Persists", want: []bool{false, false, true, true, true, true, true, true}},
+		{name: "Loop", loop: true, want: []bool{false, false, true, true, true, false, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := NewSimulatedTelemetrySource(SimulationConfig{Seed: 1, Scenario: scenario, LoopScenario: tt.loop})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, latency := range collectSeconds(t, src, len(tt.want)) {
+				if slow := latency >= 1; slow != tt.want[i] {
+					t.Errorf("collection %d latency %vs, want slow phase %v", i, latency, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewSimulatedTelemetrySource_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  SimulationConfig
+	}{
+		{name: "Inverted Latency", cfg: SimulationConfig{Profile: SimulationProfile{LatencyMin: 2, LatencyMax: 1}}},
+		{name: "Load Above One", cfg: SimulationConfig{Profile: SimulationProfile{LoadMax: 1.5}}},
+		{name: "Empty Phase", cfg: SimulationConfig{Scenario: []SimulationPhase{{Name: "idle"}}}},
+		{name: "Invalid Phase Profile", cfg: SimulationConfig{Scenario: []SimulationPhase{{Name: "bad", Collections: 1, Profile: SimulationProfile{FailureProbability: 2}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSimulatedTelemetrySource(tt.cfg); err == nil {
+				t.Error("NewSimulatedTelemetrySource() succeeded, want an error")
+			}
+		})
+	}
+}
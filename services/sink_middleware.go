@@ -0,0 +1,192 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"pkg/correlation"
+	"pkg/metrics"
+)
+
+// redactedValue replaces any label value matched by a masking rule.
+const redactedValue = "[REDACTED]"
+
+// SinkMiddleware wraps a TelemetrySink to add cross-cutting behavior around Record.
+// Queries and Close pass through to the wrapped sink unchanged.
+type SinkMiddleware func(next TelemetrySink) TelemetrySink
+
+// ChainSink composes middleware around sink. The first middleware is outermost and sees each
+// record first.
+func ChainSink(sink TelemetrySink, middleware ...SinkMiddleware) TelemetrySink {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		sink = middleware[i](sink)
+	}
+	return sink
+}
+
+// recordInterceptor overrides Record while delegating every other method to the embedded sink.
+type recordInterceptor struct {
+	TelemetrySink
+	record func(ctx context.Context, td TelemetryData) error
+}
+
+func (r *recordInterceptor) Record(ctx context.Context, td TelemetryData) error {
+	return r.record(ctx, td)
+}
+
+// Unwrap returns the wrapped sink, so QueryRange reaches it.
+func (r *recordInterceptor) Unwrap() TelemetrySink { return r.TelemetrySink }
+
+// FilterSink drops records for which keep returns false.
+func FilterSink(keep func(td TelemetryData) bool) SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			if !keep(td) {
+				return nil
+			}
+			return next.Record(ctx, td)
+		}}
+	}
+}
+
+// TransformSink rewrites each record before it is stored, e.g., for unit conversion.
+func TransformSink(transform func(td TelemetryData) TelemetryData) SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			return next.Record(ctx, transform(td))
+		}}
+	}
+}
+
+// EnrichSink adds static labels to every record. Labels already present on a record win.
+func EnrichSink(labels map[string]string) SinkMiddleware {
+	return TransformSink(func(td TelemetryData) TelemetryData {
+		merged := make(map[string]string, len(labels)+len(td.Labels))
+		for k, v := range labels {
+			merged[k] = v
+		}
+		for k, v := range td.Labels {
+			merged[k] = v
+		}
+		td.Labels = merged
+		return td
+	})
+}
+
+// DownsampleSink stores at most one healthy record per interval. Violating records are always
+// stored so downsampling never hides a breach.
+func DownsampleSink(interval time.Duration) SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		var (
+			last time.Time
+			mu   sync.Mutex
+		)
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			mu.Lock()
+			keep := td.IsGATMViolating || last.IsZero() || td.Timestamp.Sub(last) >= interval
+			if keep {
+				last = td.Timestamp
+			}
+			mu.Unlock()
+			if !keep {
+				return nil
+			}
+			return next.Record(ctx, td)
+		}}
+	}
+}
+
+// RedactSink masks label values matched by the current masking rules (regular expressions).
+// rules is consulted on every record so governance policy updates apply without rebuilding the chain;
+// compiled expressions are cached per rule set.
+func RedactSink(rules func() []string) SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		var (
+			cachedKey string
+			compiled  []*regexp.Regexp
+			mu        sync.Mutex
+		)
+		compile := func() ([]*regexp.Regexp, error) {
+			current := rules()
+			key := fmt.Sprintf("%q", current)
+			mu.Lock()
+			defer mu.Unlock()
+			if key == cachedKey && compiled != nil {
+				return compiled, nil
+			}
+			res := make([]*regexp.Regexp, 0, len(current))
+			for _, rule := range current {
+				re, err := regexp.Compile(rule)
+				if err != nil {
+					return nil, fmt.Errorf("invalid masking rule '%s': %w", rule, err)
+				}
+				res = append(res, re)
+			}
+			cachedKey, compiled = key, res
+			return res, nil
+		}
+
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			res, err := compile()
+			if err != nil {
+				// Fail closed: never persist data that could not be redacted.
+				return err
+			}
+			if len(res) > 0 && len(td.Labels) > 0 {
+				masked := make(map[string]string, len(td.Labels))
+				for k, v := range td.Labels {
+					for _, re := range res {
+						v = re.ReplaceAllString(v, redactedValue)
+					}
+					masked[k] = v
+				}
+				td.Labels = masked
+			}
+			return next.Record(ctx, td)
+		}}
+	}
+}
+
+// CorrelationLabel is the label CorrelateSink stores the collection cycle's correlation ID under.
+const CorrelationLabel = "correlation_id"
+
+// CorrelateSink labels each record with the correlation ID of the cycle or request that produced
+// it, so stored telemetry can be joined with escalation logs and audit entries.
+func CorrelateSink() SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			if id := correlation.FromContext(ctx); id != "" {
+				labels := make(map[string]string, len(td.Labels)+1)
+				for k, v := range td.Labels {
+					labels[k] = v
+				}
+				labels[CorrelationLabel] = id
+				td.Labels = labels
+			}
+			return next.Record(ctx, td)
+		}}
+	}
+}
+
+// InstrumentSink counts writes by result and measures write latency, labeled with the sink's name.
+func InstrumentSink(p metrics.Provider, name string) SinkMiddleware {
+	p = metrics.OrNop(p)
+	writes := p.Counter("sts_sink_writes_total", "Telemetry sink writes by sink and result.", "sink", "result")
+	latency := p.Histogram("sts_sink_write_duration_seconds", "Telemetry sink write latency.", nil, "sink")
+	return func(next TelemetrySink) TelemetrySink {
+		return &recordInterceptor{TelemetrySink: next, record: func(ctx context.Context, td TelemetryData) error {
+			start := time.Now()
+			err := next.Record(ctx, td)
+			latency.Observe(time.Since(start).Seconds(), name)
+			if err != nil {
+				writes.Inc(name, "error")
+				return err
+			}
+			writes.Inc(name, "ok")
+			return nil
+		}}
+	}
+}
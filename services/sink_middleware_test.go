@@ -0,0 +1,105 @@
+package telemetry
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// rangeSink is a sliceSink that pages through its records.
+type rangeSink struct {
+	sliceSink
+}
+
+func (s *rangeSink) QueryRange(ctx context.Context, q RangeQuery) (Page, error) {
+	return PageRecords(s.records, q)
+}
+
+// appendLabel returns middleware that appends step to the "steps" label.
+func appendLabel(step string) SinkMiddleware {
+	return TransformSink(func(td TelemetryData) TelemetryData {
+		td.Labels = map[string]string{"steps": td.Labels["steps"] + step}
+		return td
+	})
+}
+
+func TestChainSink_Order(t *testing.T) {
+	sink := &sliceSink{}
+	chain := ChainSink(sink, appendLabel("a"), appendLabel("b"), appendLabel("c"))
+	if err := chain.Record(context.Background(), TelemetryData{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := sink.records[0].Labels["steps"]; got != "abc" {
+		t.Errorf("steps = %q, want the first middleware to see the record first (abc)", got)
+	}
+}
+
+func TestDownsampleSink(t *testing.T) {
+	sink := &sliceSink{}
+	chain := ChainSink(sink, DownsampleSink(10*time.Second))
+	for i, violating := range []bool{false, false, true, false, false, true} {
+		td := TelemetryData{Timestamp: time.Unix(int64(i*3), 0), IsGATMViolating: violating, GATMBreachCount: i}
+		if err := chain.Record(context.Background(), td); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Healthy records within 10s of the last stored one are dropped; violating ones never are.
+	var got []int
+	for _, td := range sink.records {
+		got = append(got, td.GATMBreachCount)
+	}
+	if want := []int{0, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("stored records %v, want %v", got, want)
+	}
+}
+
+func TestEnrichSink_ExistingLabelsWin(t *testing.T) {
+	sink := &sliceSink{}
+	chain := ChainSink(sink, EnrichSink(map[string]string{"node": "n1", "zone": "z1"}))
+	if err := chain.Record(context.Background(), TelemetryData{Labels: map[string]string{"node": "n2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if labels := sink.records[0].Labels; labels["node"] != "n2" || labels["zone"] != "z1" {
+		t.Errorf("labels = %v, want node=n2 zone=z1", labels)
+	}
+}
+
+func TestRedactSink(t *testing.T) {
+	rules := []string{`\d+\.\d+\.\d+\.\d+`}
+	sink := &sliceSink{}
+	chain := ChainSink(sink, RedactSink(func() []string { return rules }))
+
+	if err := chain.Record(context.Background(), TelemetryData{Labels: map[string]string{"peer": "10.0.0.7:443"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := sink.records[0].Labels["peer"]; got != redactedValue+":443" {
+		t.Errorf("peer = %q, want the address masked", got)
+	}
+
+	// An invalid rule fails closed: the record is not written.
+	rules = []string{"("}
+	if err := chain.Record(context.Background(), TelemetryData{Labels: map[string]string{"peer": "10.0.0.8"}}); err == nil {
+		t.Error("Record with an invalid masking rule succeeded")
+	}
+	if len(sink.records) != 1 {
+		t.Errorf("sink has %d records after the refused write, want 1", len(sink.records))
+	}
+}
+
+func TestSinkMiddleware_QueryRangeUnwraps(t *testing.T) {
+	sink := &rangeSink{}
+	chain := ChainSink(sink, EnrichSink(map[string]string{"node": "n1"}), DownsampleSink(time.Second), CorrelateSink())
+	for i := 0; i < 3; i++ {
+		if err := chain.Record(context.Background(), TelemetryData{Timestamp: time.Unix(int64(i*2), 0)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	page, err := QueryRange(context.Background(), chain, RangeQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Records) != 3 || page.Records[0].Labels["node"] != "n1" {
+		t.Errorf("QueryRange = %+v, want the 3 enriched records", page.Records)
+	}
+}
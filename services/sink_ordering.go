@@ -0,0 +1,139 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ordering modes of OrderSink.
+const (
+	// OrderReject rejects any record older than the newest record already written.
+	OrderReject = "reject"
+	// OrderReorder holds records for OrderingConfig.Window and writes them in timestamp order.
+	OrderReorder = "reorder"
+)
+
+// OrderingConfig configures timestamp ordering enforcement for OrderSink.
+type OrderingConfig struct {
+	Mode string // OrderReject (default) or OrderReorder
+	// Window is how far behind the newest record a record may arrive and still be reordered into
+	// place. Records are held until a record Window newer arrives, or for Window of wall time, and
+	// records arriving later than that are rejected. Required by OrderReorder.
+	Window time.Duration
+}
+
+// OrderSink keeps the records reaching the next sink in timestamp order, as range queries and
+// aggregates over persistent sinks assume, when records may arrive out of order (e.g. from replay
+// or merged sources). Out-of-order records that cannot be placed are rejected with ErrOutOfOrder.
+// In reorder mode, QueryLastN includes held records and Close writes them before closing.
+func OrderSink(cfg OrderingConfig) SinkMiddleware {
+	return func(next TelemetrySink) TelemetrySink {
+		return &orderedSink{TelemetrySink: next, cfg: cfg, now: time.Now}
+	}
+}
+
+type orderedSink struct {
+	TelemetrySink
+	cfg OrderingConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	written time.Time    // Timestamp of the newest record written to the next sink
+	newest  time.Time    // Timestamp of the newest record accepted
+	held    []heldRecord // Records held for reordering, by timestamp
+}
+
+type heldRecord struct {
+	td    TelemetryData
+	since time.Time // Arrival, in wall time
+}
+
+func (s *orderedSink) Record(ctx context.Context, td TelemetryData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if td.Timestamp.Before(s.written) {
+		return fmt.Errorf("%w: record at %v precedes the last written record at %v", ErrOutOfOrder,
+			td.Timestamp.Format(time.RFC3339Nano), s.written.Format(time.RFC3339Nano))
+	}
+	if s.cfg.Mode != OrderReorder {
+		if err := s.TelemetrySink.Record(ctx, td); err != nil {
+			return err
+		}
+		s.written = td.Timestamp
+		return nil
+	}
+
+	i := sort.Search(len(s.held), func(i int) bool { return s.held[i].td.Timestamp.After(td.Timestamp) })
+	s.held = append(s.held, heldRecord{})
+	copy(s.held[i+1:], s.held[i:])
+	s.held[i] = heldRecord{td: td, since: s.now()}
+	if td.Timestamp.After(s.newest) {
+		s.newest = td.Timestamp
+	}
+	return s.release(ctx, false)
+}
+
+// release writes the held records that can no longer be preceded by a record within the window,
+// or all of them. Records are only dropped from the hold once written, so a failed write is
+// retried with the next record.
+func (s *orderedSink) release(ctx context.Context, all bool) error {
+	watermark := s.newest.Add(-s.cfg.Window)
+	// A record held for a full window is released with every record before it.
+	last := -1
+	now := s.now()
+	for i, h := range s.held {
+		if all || !h.td.Timestamp.After(watermark) || now.Sub(h.since) >= s.cfg.Window {
+			last = i
+		}
+	}
+	for last >= 0 {
+		h := s.held[0]
+		if err := s.TelemetrySink.Record(ctx, h.td); err != nil {
+			return err
+		}
+		s.written = h.td.Timestamp
+		s.held = s.held[1:]
+		last--
+	}
+	return nil
+}
+
+// QueryLastN merges the held records, all newer than the written ones, into the next sink's. The
+// lock is held throughout, so no record is released, and counted twice, mid-query.
+func (s *orderedSink) QueryLastN(ctx context.Context, n int) ([]TelemetryData, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	held := make([]TelemetryData, 0, len(s.held))
+	for _, h := range s.held {
+		held = append(held, h.td)
+	}
+	if len(held) >= n {
+		return held[len(held)-n:], nil
+	}
+	records, err := s.TelemetrySink.QueryLastN(ctx, n-len(held))
+	if err != nil {
+		return nil, err
+	}
+	return append(records, held...), nil
+}
+
+// Unwrap returns the wrapped sink, so QueryRange reaches it. Range queries do not include held
+// records.
+func (s *orderedSink) Unwrap() TelemetrySink { return s.TelemetrySink }
+
+// Close writes the held records, then closes the next sink.
+func (s *orderedSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.release(ctx, true)
+	s.mu.Unlock()
+	if cerr := s.TelemetrySink.Close(ctx); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOrderSink(t *testing.T) {
+	at := func(s int) TelemetryData { return TelemetryData{Timestamp: time.Unix(int64(s), 0)} }
+	seconds := func(records []TelemetryData) []int64 {
+		var out []int64
+		for _, td := range records {
+			out = append(out, td.Timestamp.Unix())
+		}
+		return out
+	}
+	tests := []struct {
+		name     string
+		cfg      OrderingConfig
+		in       []int
+		want     []int64 // Written to the next sink, after Close
+		rejected int
+	}{
+		{name: "Reject", cfg: OrderingConfig{}, in: []int{1, 3, 2, 3, 4}, want: []int64{1, 3, 3, 4}, rejected: 1},
+		{name: "Reorder", cfg: OrderingConfig{Mode: OrderReorder, Window: 5 * time.Second}, in: []int{1, 4, 2, 9, 3, 12, 5, 8}, want: []int64{1, 2, 4, 5, 8, 9, 12}, rejected: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &sliceSink{}
+			sink := OrderSink(tt.cfg)(next)
+			rejected := 0
+			for _, s := range tt.in {
+				if err := sink.Record(context.Background(), at(s)); errors.Is(err, ErrOutOfOrder) {
+					rejected++
+				} else if err != nil {
+					t.Fatalf("Record(%d) error = %v", s, err)
+				}
+			}
+			if got, _ := sink.QueryLastN(context.Background(), 3); len(got) != 3 || got[2].Timestamp.Unix() != tt.want[len(tt.want)-1] {
+				t.Errorf("QueryLastN(3) = %v, want the newest records including held ones", seconds(got))
+			}
+			if err := sink.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := seconds(next.records); rejected != tt.rejected || !slices.Equal(got, tt.want) {
+				t.Errorf("wrote %v with %d rejected, want %v with %d rejected", got, rejected, tt.want, tt.rejected)
+			}
+		})
+	}
+}
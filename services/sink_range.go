@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPageLimit is the page size of a RangeQuery without a Limit.
+const DefaultPageLimit = 500
+
+// RangeQuery selects one page of history in timestamp order.
+type RangeQuery struct {
+	From   time.Time // Inclusive; zero is unbounded
+	To     time.Time // Exclusive; zero is unbounded
+	Cursor string    // NextCursor of the previous page; empty starts at From
+	Limit  int       // Records per page (default DefaultPageLimit)
+}
+
+// Page is one page of a range query.
+type Page struct {
+	Records []TelemetryData `json:"records"`
+	// NextCursor resumes the query after this page; it is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// RangeQuerier is implemented by sinks that can page through their history in timestamp order,
+// so long histories can be exported without loading them whole. Use QueryRange to reach it through
+// sink middleware.
+type RangeQuerier interface {
+	QueryRange(ctx context.Context, q RangeQuery) (Page, error)
+}
+
+// QueryRange runs q against sink, or the sink wrapped by its middleware, if it is a RangeQuerier.
+func QueryRange(ctx context.Context, sink TelemetrySink, q RangeQuery) (Page, error) {
+	for sink != nil {
+		if rq, ok := sink.(RangeQuerier); ok {
+			return rq.QueryRange(ctx, q)
+		}
+		u, ok := sink.(interface{ Unwrap() TelemetrySink })
+		if !ok {
+			break
+		}
+		sink = u.Unwrap()
+	}
+	return Page{}, fmt.Errorf("%w: %T", ErrRangeUnsupported, sink)
+}
+
+// Cursor is a position in timestamp-ordered history: after the first Skip records at After. It is
+// independent of the sink, so a cursor stays valid across restarts and federated sinks.
+type Cursor struct {
+	After time.Time
+	Skip  int
+}
+
+// String encodes the cursor as an opaque token.
+func (c Cursor) String() string {
+	raw := strconv.FormatInt(c.After.UnixNano(), 10) + "." + strconv.Itoa(c.Skip)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a token returned by Cursor.String.
+func ParseCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	nanos, skip, ok := strings.Cut(string(raw), ".")
+	after, err1 := strconv.ParseInt(nanos, 10, 64)
+	n, err2 := strconv.Atoi(skip)
+	if !ok || err1 != nil || err2 != nil || n < 0 {
+		return Cursor{}, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	return Cursor{After: time.Unix(0, after).UTC(), Skip: n}, nil
+}
+
+// Bounds returns where a range query resumes: records from lower on, skipping the first skip
+// records at exactly lower.
+func (q RangeQuery) Bounds() (lower time.Time, skip int, err error) {
+	lower = q.From
+	if q.Cursor == "" {
+		return lower, 0, nil
+	}
+	c, err := ParseCursor(q.Cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if c.After.Before(lower) {
+		return lower, 0, nil
+	}
+	return c.After, c.Skip, nil
+}
+
+// PageLimit returns the query's page size.
+func (q RangeQuery) PageLimit() int {
+	if q.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	return q.Limit
+}
+
+// NextCursor returns the cursor following page, a page of records resuming from lower after skip
+// records; see Bounds.
+func NextCursor(lower time.Time, skip int, page []TelemetryData) Cursor {
+	last := page[len(page)-1].Timestamp
+	c := Cursor{After: last}
+	if last.Equal(lower) {
+		c.Skip = skip
+	}
+	for i := len(page) - 1; i >= 0 && page[i].Timestamp.Equal(last); i-- {
+		c.Skip++
+	}
+	return c
+}
+
+// PageRecords pages through records held in memory, ordered oldest first, for sinks implementing
+// RangeQuerier over their whole contents.
+func PageRecords(records []TelemetryData, q RangeQuery) (Page, error) {
+	lower, skip, err := q.Bounds()
+	if err != nil {
+		return Page{}, err
+	}
+	start := sort.Search(len(records), func(i int) bool { return !records[i].Timestamp.Before(lower) })
+	for n := skip; n > 0 && start < len(records) && records[start].Timestamp.Equal(lower); n-- {
+		start++
+	}
+	end := len(records)
+	if !q.To.IsZero() {
+		end = start + sort.Search(len(records)-start, func(i int) bool { return !records[start+i].Timestamp.Before(q.To) })
+	}
+	limit := q.PageLimit()
+	if end-start <= limit {
+		return Page{Records: records[start:end]}, nil
+	}
+	page := records[start : start+limit]
+	return Page{Records: page, NextCursor: NextCursor(lower, skip, page).String()}, nil
+}
@@ -0,0 +1,61 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPageRecords(t *testing.T) {
+	// Several records share a timestamp, so a page boundary can fall between them.
+	var records []TelemetryData
+	for i, s := range []int64{1, 2, 2, 2, 3, 4, 5, 5, 6} {
+		records = append(records, TelemetryData{Timestamp: time.Unix(s, 0), GATMBreachCount: i})
+	}
+	tests := []struct {
+		name string
+		q    RangeQuery
+		want []int // GATMBreachCount of every record, over all pages
+	}{
+		{name: "All", q: RangeQuery{Limit: 2}, want: []int{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{name: "Bounded", q: RangeQuery{From: time.Unix(2, 0), To: time.Unix(5, 0), Limit: 2}, want: []int{1, 2, 3, 4, 5}},
+		{name: "Single Page", q: RangeQuery{From: time.Unix(5, 0)}, want: []int{6, 7, 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			q := tt.q
+			for pages := 0; ; pages++ {
+				if pages > len(records) {
+					t.Fatal("paging does not terminate")
+				}
+				page, err := PageRecords(records, q)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(page.Records) > q.PageLimit() {
+					t.Fatalf("page of %d records exceeds limit %d", len(page.Records), q.PageLimit())
+				}
+				for _, td := range page.Records {
+					got = append(got, td.GATMBreachCount)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				q.Cursor = page.NextCursor
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("paged records %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := PageRecords(records, RangeQuery{Cursor: "not a cursor"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("PageRecords() with a bad cursor error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := QueryRange(context.Background(), ChainSink(&sliceSink{}, CorrelateSink()), RangeQuery{}); !errors.Is(err, ErrRangeUnsupported) {
+		t.Errorf("QueryRange() on a sink without range support error = %v, want ErrRangeUnsupported", err)
+	}
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// hungSink blocks every write until its context is done, like a remote sink that stopped answering.
+type hungSink struct {
+	sliceSink
+}
+
+func (s *hungSink) Record(ctx context.Context, data TelemetryData) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestCollectNow_SinkWriteTimeout(t *testing.T) {
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		Sink:             &hungSink{},
+		SinkWriteTimeout: 20 * time.Millisecond,
+	}, &scriptedSource{latencies: []time.Duration{100 * time.Millisecond}})
+
+	start := time.Now()
+	err := sts.CollectNow(context.Background())
+	if !errors.Is(err, ErrSinkWriteFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CollectNow() error = %v, want ErrSinkWriteFailed wrapping context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CollectNow() took %v with a 20ms sink write timeout", elapsed)
+	}
+	if sts.GetHealthStatus().IntegrityHashChainStatus == "INITIALIZING" {
+		t.Error("the cycle's assessment was not kept after the sink write failed")
+	}
+}
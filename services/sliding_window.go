@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"errors"
+	"math"
+)
+
+// SlidingWindowConfig configures EscalationModeSlidingWindow.
+type SlidingWindowConfig struct {
+	Samples int     // Window length N in collection cycles
+	Ratio   float64 // Escalate when violations/N reaches this ratio (0.0 - 1.0]
+}
+
+// Validate checks the window parameters.
+func (c SlidingWindowConfig) Validate() error {
+	if c.Samples <= 0 {
+		return errors.New("sliding window: samples must be positive")
+	}
+	if c.Ratio <= 0 || c.Ratio > 1 {
+		return errors.New("sliding window: ratio must be between (0.0, 1.0]")
+	}
+	return nil
+}
+
+// threshold is the number of violating samples in a full window that triggers escalation.
+func (c SlidingWindowConfig) threshold() int {
+	return int(math.Ceil(c.Ratio * float64(c.Samples)))
+}
+
+// violationWindow records whether each of the last N non-silenced samples violated GATM.
+// In sliding-window mode it replaces the decayed counter: GATMBreachCount is the number of
+// violations in the window, so sinks, acknowledgements, and escalation see the same value.
+type violationWindow struct {
+	samples    []bool
+	next       int
+	filled     int
+	violations int
+}
+
+func newViolationWindow(n int) *violationWindow {
+	return &violationWindow{samples: make([]bool, n)}
+}
+
+// observe adds a sample, evicting the oldest once the window is full, and returns the violation count.
+func (w *violationWindow) observe(violating bool) int {
+	if w.filled == len(w.samples) {
+		if w.samples[w.next] {
+			w.violations--
+		}
+	} else {
+		w.filled++
+	}
+	w.samples[w.next] = violating
+	if violating {
+		w.violations++
+	}
+	w.next = (w.next + 1) % len(w.samples)
+	return w.violations
+}
+
+func (w *violationWindow) reset() {
+	clear(w.samples)
+	w.next, w.filled, w.violations = 0, 0, 0
+}
@@ -0,0 +1,56 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// scriptedSource returns one snapshot per collection, cycling through latencies.
+type scriptedSource struct {
+	latencies []time.Duration
+	i         int
+}
+
+func (s *scriptedSource) Collect(ctx context.Context) (TelemetryData, error) {
+	td := TelemetryData{
+		Timestamp:                time.Unix(int64(s.i), 0),
+		PipelineLatencyS9:        s.latencies[s.i%len(s.latencies)],
+		IntegrityHashChainStatus: "SYNCED",
+	}
+	s.i++
+	return td, nil
+}
+
+func TestSlidingWindowEscalation(t *testing.T) {
+	// Alternating violations: the decayed counter never reaches MaxBreaches, but half the window violates.
+	src := &scriptedSource{latencies: []time.Duration{2 * time.Second, 100 * time.Millisecond}}
+	sts := NewSovereignTelemetryService(STSConfiguration{
+		LatencyThreshold: time.Second,
+		MaxBreaches:      3,
+		EscalationMode:   EscalationModeSlidingWindow,
+		SlidingWindow:    SlidingWindowConfig{Samples: 4, Ratio: 0.5},
+	}, src)
+
+	var escalated []bool
+	for i := 0; i < 6; i++ {
+		if err := sts.CollectNow(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		escalated = append(escalated, sts.CheckGATMViolation())
+	}
+	want := []bool{false, false, true, true, true, true}
+	for i := range want {
+		if escalated[i] != want[i] {
+			t.Fatalf("escalation by cycle = %v, want %v", escalated, want)
+		}
+	}
+	if got := sts.GetHealthStatus().GATMBreachCount; got != 2 {
+		t.Errorf("GATMBreachCount = %d, want 2 violations in window", got)
+	}
+
+	sts.ResetBreachCount()
+	if sts.CheckGATMViolation() {
+		t.Error("still escalated after reset")
+	}
+}
@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"time"
+
+	"pkg/metrics"
+)
+
+// stsMetrics holds the STS instruments, created from STSConfiguration.Metrics.
+type stsMetrics struct {
+	collections   metrics.Counter         // result: ok|error
+	duration      metrics.Histogram       // Collection and processing latency, seconds
+	latency       metrics.BucketHistogram // S9 latency distributions reported by the source, seconds
+	breachCount   metrics.Gauge
+	severity      metrics.Gauge   // Severity rank (0 OK .. 3 CRITICAL)
+	violations    metrics.Counter // cause
+	clockOffset   metrics.Gauge   // Latest probed clock offset, seconds
+	clockSteps    metrics.Counter // Snapshots clamped after the clock went backwards
+	subscribers   metrics.Gauge   // Active Monitor streams
+	monitorEnds   metrics.Counter // reason: rejected|cancelled|abandoned
+	derived       metrics.Gauge   // metric
+	derivedErrors metrics.Counter // metric
+}
+
+func newSTSMetrics(p metrics.Provider) stsMetrics {
+	p = metrics.OrNop(p)
+	return stsMetrics{
+		collections:   p.Counter("sts_collections_total", "Telemetry collection cycles by result.", "result"),
+		duration:      p.Histogram("sts_collection_duration_seconds", "Time to collect and process one telemetry snapshot.", nil),
+		latency:       p.BucketHistogram("sts_pipeline_latency_seconds", "S9 pipeline latency distribution reported by the telemetry source.", DefaultLatencyBuckets),
+		breachCount:   p.Gauge("sts_gatm_breach_count", "Current cumulative GATM breach count."),
+		severity:      p.Gauge("sts_severity_rank", "Current severity tier (0 OK, 1 WARN, 2 DEGRADED, 3 CRITICAL)."),
+		violations:    p.Counter("sts_gatm_violations_total", "Snapshots violating GATM rules, by cause.", "cause"),
+		clockOffset:   p.Gauge("sts_clock_offset_seconds", "Latest local clock offset from the time reference."),
+		clockSteps:    p.Counter("sts_clock_steps_total", "Snapshots whose timestamp or latency went backwards."),
+		subscribers:   p.Gauge("sts_monitor_subscribers", "Active Monitor subscribers."),
+		monitorEnds:   p.Counter("sts_monitor_streams_ended_total", "Monitor streams ended or refused, by reason.", "reason"),
+		derived:       p.Gauge("sts_derived_metric", "Latest value of each derived business metric.", "metric"),
+		derivedErrors: p.Counter("sts_derived_metric_errors_total", "Derived metric computations that failed, by metric.", "metric"),
+	}
+}
+
+func (m stsMetrics) observeCycle(start time.Time, err error) {
+	m.duration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.collections.Inc("error")
+		return
+	}
+	m.collections.Inc("ok")
+}
+
+func (m stsMetrics) observeSnapshot(td TelemetryData) {
+	m.breachCount.Set(float64(td.GATMBreachCount))
+	m.severity.Set(float64(td.Severity.Rank()))
+	if td.ClockStatus == ClockSynced {
+		m.clockOffset.Set(td.ClockOffset.Seconds())
+	}
+	for _, cause := range td.ViolationCauses {
+		m.violations.Inc(cause)
+	}
+	if h := td.LatencyHistogram; h != nil {
+		m.latency.ObserveBuckets(h.Bounds, h.Counts, h.Sum)
+	}
+	for name, value := range td.Derived {
+		m.derived.Set(value, name)
+	}
+}
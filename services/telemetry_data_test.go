@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelemetryDataJSONLatency(t *testing.T) {
+	td := TelemetryData{Timestamp: time.Unix(0, 0).UTC(), PipelineLatencyS9: 1500 * time.Millisecond, Severity: SeverityWarn}
+	data, err := json.Marshal(td)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"pipeline_latency_s9":1.5`) {
+		t.Errorf("latency not encoded as seconds: %s", data)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "Seconds", input: `{"pipeline_latency_s9":0.25}`, want: 250 * time.Millisecond},
+		{name: "Duration String", input: `{"pipeline_latency_s9":"2s"}`, want: 2 * time.Second},
+		{name: "Missing", input: `{}`, want: 0},
+		{name: "Invalid", input: `{"pipeline_latency_s9":"soon"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TelemetryData
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.PipelineLatencyS9 != tt.want {
+				t.Errorf("PipelineLatencyS9 = %v, want %v", got.PipelineLatencyS9, tt.want)
+			}
+		})
+	}
+
+	var round TelemetryData
+	if err := json.Unmarshal(data, &round); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+	if round.PipelineLatencyS9 != td.PipelineLatencyS9 || round.Severity != td.Severity || !round.Timestamp.Equal(td.Timestamp) {
+		t.Errorf("round trip = %+v, want %+v", round, td)
+	}
+}
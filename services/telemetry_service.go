@@ -5,47 +5,241 @@ package telemetry
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"pkg/correlation"
+	"pkg/errreport"
+	"pkg/featureflag"
+	"pkg/jitter"
+	"pkg/metrics"
+	"pkg/recovery"
 )
 
 // TelemetryData holds the essential metrics monitored by STS.
 type TelemetryData struct {
 	Timestamp                time.Time `json:"timestamp"`
-	PipelineLatency_S9       float64   `json:"pipeline_latency_s9"`       // Time since last successful S9 Commit (seconds)
+	PipelineLatencyS9        time.Duration `json:"pipeline_latency_s9" unit:"s"` // Time since last successful S9 Commit; seconds on the wire (see MarshalJSON)
 	ResourceLoad_Pct         float64   `json:"resource_load_pct"`         // Current CPU/Memory utilization average (0.0 to 1.0)
 	IntegrityHashChainStatus string    `json:"hash_chain_status"`       // CRoT integrity anchor status (e.g., "SYNCED", "DIVERGED")
 	GATMBreachCount          int       `json:"gatm_breach_count"`       // Consecutive breaches against GATM rules (cumulative)
 	IsGATMViolating          bool      `json:"is_gatm_violating"`       // Instantaneous GATM rule breach status
+	ViolationCauses          []string  `json:"violation_causes,omitempty"` // Causes behind IsGATMViolating (e.g., "latency", "rule:<name>")
+	IsSilenced               bool      `json:"is_silenced"`             // Violation fully covered by an active maintenance silence
+	IsAcknowledged           bool      `json:"is_acknowledged"`         // Operator acknowledged the violation; escalation paused
+	Severity                 Severity  `json:"severity"`                // Graded deviation from GATM thresholds
+	Labels                   map[string]string `json:"labels,omitempty"` // Enrichment labels (e.g., node, zone) added by sink middleware
+	ClockOffset              time.Duration `json:"clock_offset_s,omitempty" unit:"s"` // Local clock offset from the time reference (positive: ahead); seconds on the wire
+	ClockStatus              string    `json:"clock_status,omitempty"`    // SYNCED, UNKNOWN or STEPPED_BACK; empty if the clock was not probed
+	LatencyHistogram         *Histogram `json:"latency_histogram,omitempty"` // S9 latencies observed during the collection interval, seconds; nil if the source reports none
+	Derived                  map[string]float64 `json:"derived,omitempty"` // Business metrics by name, computed by STSConfiguration.DerivedMetrics
+}
+
+// DurationFromSeconds converts float seconds, as used on the wire and in storage, to a Duration.
+func DurationFromSeconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// telemetryDataJSON has TelemetryData's fields without its methods, so the codec below can reuse
+// the default encoding for everything except the latency.
+type telemetryDataJSON TelemetryData
+
+// MarshalJSON keeps pipeline_latency_s9 in float seconds, the encoding used before the field
+// became a Duration, so stored records and external consumers stay compatible. clock_offset_s
+// follows the same encoding.
+func (td TelemetryData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		telemetryDataJSON
+		PipelineLatencyS9 float64 `json:"pipeline_latency_s9"`
+		ClockOffset       float64 `json:"clock_offset_s,omitempty"`
+	}{telemetryDataJSON(td), td.PipelineLatencyS9.Seconds(), td.ClockOffset.Seconds()})
+}
+
+// UnmarshalJSON accepts pipeline_latency_s9 and clock_offset_s as float seconds or as a duration
+// string (e.g. "1.5s").
+func (td *TelemetryData) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		*telemetryDataJSON
+		PipelineLatencyS9 json.RawMessage `json:"pipeline_latency_s9"`
+		ClockOffset       json.RawMessage `json:"clock_offset_s"`
+	}
+	wire.telemetryDataJSON = (*telemetryDataJSON)(td)
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	latency, err := parseLatency(wire.PipelineLatencyS9)
+	if err != nil {
+		return fmt.Errorf("invalid pipeline_latency_s9: %w", err)
+	}
+	td.PipelineLatencyS9 = latency
+	offset, err := parseLatency(wire.ClockOffset)
+	if err != nil {
+		return fmt.Errorf("invalid clock_offset_s: %w", err)
+	}
+	td.ClockOffset = offset
+	return nil
+}
+
+func parseLatency(raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return DurationFromSeconds(seconds), nil
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, fmt.Errorf("expected seconds or a duration string, got %s", raw)
+	}
+	return time.ParseDuration(text)
+}
+
+// Acknowledgement records an operator's acknowledgement of an ongoing GATM violation.
+type Acknowledgement struct {
+	By     string    `json:"by"`
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+	Until  time.Time `json:"until"`
 }
 
 // Define Constant Default Values
 const (
 	defaultInterval    = 5 * time.Second
-	defaultLatency     = 1.0  // 1.0 second threshold
+	defaultLatency     = 1 * time.Second
 	defaultLoad        = 0.8  // 80% load threshold
 	defaultMaxBreaches = 5
 	// The factor used to damp/decay the cumulative GATM breach count when conditions stabilize.
 	defaultDecayFactor = 0.7
+	// Bound on out-of-band breach state writes (reset, acknowledgement) to the shared store.
+	stateStoreTimeout = 2 * time.Second
+	// Monitor subscriber limits.
+	defaultMaxMonitorSubscribers = 64
+	defaultMonitorIdleTimeout    = 5 * time.Minute
+	// Consecutive failed cycles before a collection failure is sent to the ErrorReporter.
+	defaultErrorReportAfter = 3
 )
 
 // STSConfiguration holds adjustable runtime parameters for the Telemetry Service.
 type STSConfiguration struct {
 	DefaultInterval   time.Duration
-	LatencyThreshold  float64 // seconds
+	// StartupSplay delays Run's first collection by a random duration up to this bound, so a fleet
+	// started together does not collect in lockstep. Zero disables it.
+	StartupSplay time.Duration
+	// Jitter perturbs each interval by up to this fraction of DefaultInterval (0.0 - 1.0), e.g. 0.1
+	// for ±10%, keeping replicas from drifting back into sync. Zero disables it.
+	Jitter float64
+	LatencyThreshold  time.Duration
 	LoadThreshold     float64 // percentage (0.0 - 1.0)
 	MaxBreaches       int     // count
 	BreachDecayFactor float64 // Damping factor (0.0 - 1.0)
+
+	// Rules are additional GATM rules evaluated after the built-in thresholds, while the
+	// featureflag.CELRules flag is on.
+	Rules []GATMRule
+	// Flags gates behaviors being rolled out across the fleet. Nil leaves every flag at its default.
+	Flags *featureflag.Set
+
+	// Processors are custom pipeline steps, run in order within their stage (see Stage), e.g.
+	// LabelProcessor to tag snapshots with the deployment version.
+	Processors []StageProcessor
+
+	// DerivedMetrics compute business metrics from recent telemetry after the enrich stage, so GATM
+	// rules and evaluate-stage processors see their values (see DerivedMetric).
+	DerivedMetrics []DerivedMetric
+
+	// MaxClockSkew, if positive, breaches GATM (cause "clock_skew") when the snapshot's clock offset
+	// exceeds it either way, or could not be probed. Offsets are recorded by ClockProcessor.
+	MaxClockSkew time.Duration
+
+	// Silences suppresses breach counting and escalation during maintenance windows. Optional.
+	Silences *SilenceRegistry
+	// Labels are static instance labels (e.g., node, zone) matched by silences alongside the violation cause.
+	Labels map[string]string
+
+	// Sink, if set, receives every processed snapshot.
+	Sink TelemetrySink
+	// SinkWriteTimeout bounds each write to Sink, so a hung remote sink cannot block a cycle. Zero
+	// derives it from the collection interval (half of DefaultInterval); negative disables it.
+	SinkWriteTimeout time.Duration
+	// EscalationMode selects the escalation trigger (EscalationModeConsecutive by default).
+	EscalationMode string
+	// BurnRate is required by EscalationModeBurnRate; without it the consecutive counter is used.
+	BurnRate *BurnRateDetector
+	// SlidingWindow is required by EscalationModeSlidingWindow; if invalid, the consecutive counter is used.
+	// The window is kept per replica: with a StateStore, replicas share its count but not its samples.
+	SlidingWindow SlidingWindowConfig
+
+	// Tiers grade snapshots into severities. Zero ratios apply defaults (WARN 0.9, CRITICAL 1.5).
+	Tiers SeverityTiers
+	// Escalation, if set, is dispatched whenever the severity tier changes (unless silenced or acknowledged).
+	Escalation *EscalationRouter
+
+	// BackpressureStart is the metric/threshold ratio (0.0 - 1.0) at which throttling begins (default 0.75).
+	BackpressureStart float64
+
+	// StateStore, if set, shares the breach count and acknowledgement with other replicas. The
+	// count advances once per collection cycle (DefaultInterval) across the replicas sharing it.
+	StateStore BreachStateStore
+
+	// Lifecycle, if set, is the state machine driven by each cycle, so callers can register guards
+	// and hooks before Run. A new one is created otherwise.
+	Lifecycle *Lifecycle
+
+	// ThresholdAdmission, if set, must admit every runtime threshold change made through UpdateThresholds.
+	ThresholdAdmission ThresholdAdmitter
+
+	// Metrics receives STS instrumentation. Optional; defaults to metrics.Nop.
+	Metrics metrics.Provider
+
+	// MaxMonitorSubscribers caps concurrent Monitor streams (default 64); negative means unlimited.
+	MaxMonitorSubscribers int
+	// MonitorIdleTimeout ends a Monitor stream whose reader has not received an update for this long
+	// (default 5m, and never less than two stream intervals), reaping subscribers that stopped reading
+	// without cancelling their context.
+	MonitorIdleTimeout time.Duration
+
+	// ErrorReporter, if set, receives panics of the telemetry source and GATM rules as they happen,
+	// and cycle failures once ErrorReportAfter consecutive cycles have failed (default 3).
+	ErrorReporter    errreport.ErrorReporter
+	ErrorReportAfter int
 }
 
 // STS provides the mandated monitoring interface.
 type STS interface {
 	Run(ctx context.Context) error
-	Monitor(ctx context.Context, interval time.Duration) <-chan TelemetryData
+	Monitor(ctx context.Context, interval time.Duration, opts ...MonitorOption) <-chan TelemetryData
 	GetHealthStatus() TelemetryData
 	CheckGATMViolation() bool
+	// CollectNow performs an immediate, out-of-band collection outside the Run() ticker. It works while paused.
+	CollectNow(ctx context.Context) error
+	// Pause freezes background collection by Run(); state and escalation stay at their last values.
+	Pause()
+	// Resume restarts background collection with an immediate cycle.
+	Resume()
+	// Paused reports whether background collection is paused.
+	Paused() bool
+	// MonitorSubscribers returns the number of active Monitor streams.
+	MonitorSubscribers() int
+	// ResetBreachCount clears the cumulative GATM breach count, moving an escalated lifecycle to
+	// RECOVERING. It fails, leaving the count unchanged, if a lifecycle guard refuses the transition.
+	ResetBreachCount() error
+	// Acknowledge pauses escalation of the current violation for ttl, or until the breach count clears.
+	Acknowledge(by, reason string, ttl time.Duration) (Acknowledgement, error)
+	// Backpressure returns a recommended throttle level (0.0 - 1.0) for downstream workload schedulers.
+	Backpressure() float64
+	// Lifecycle returns the current escalation state (HEALTHY, WARN, RRP_PENDING, SIH_ACTIVE, RECOVERING).
+	Lifecycle() LifecycleStatus
+	// Thresholds returns the GATM thresholds in effect.
+	Thresholds() Thresholds
+	// UpdateThresholds changes GATM thresholds at runtime, subject to ThresholdAdmission, and returns
+	// the thresholds in effect afterwards.
+	UpdateThresholds(ctx context.Context, source string, update Thresholds) (Thresholds, error)
 }
 
 // TelemetrySource defines the interface for collecting raw system metric data.
@@ -54,37 +248,28 @@ type TelemetrySource interface {
 	Collect(ctx context.Context) (TelemetryData, error)
 }
 
-// simulatedTelemetrySource is a temporary data provider for initialization and testing.
-type simulatedTelemetrySource struct{}
-
-// Collect simulates fetching metrics from system endpoints.
-func (*simulatedTelemetrySource) Collect(ctx context.Context) (TelemetryData, error) {
-	// Use seeded time for better simulation realism in production (if time.Now is used to set seed).
-	// NOTE: rand is inherently not safe for crypto/concurrent use, but acceptable for simulation.
-	newData := TelemetryData{
-		Timestamp:                time.Now(),
-		PipelineLatency_S9:       rand.Float64() * 1.5, // Simulating 0.0 to 1.5 seconds
-		ResourceLoad_Pct:         rand.Float64(),       // Simulating 0.0 to 1.0 load
-		IntegrityHashChainStatus: "SYNCED",
-	}
-	// 10% chance of generating a failure state for simulation
-	if rand.Float64() < 0.1 { 
-		if rand.Intn(2) == 0 {
-			newData.IntegrityHashChainStatus = "DIVERGED"
-		} else {
-			newData.PipelineLatency_S9 = 2.5 // Artificially high latency
-		}
-	}
-
-	return newData, nil
-}
-
 // sovereignTelemetryService is the concrete, thread-safe implementation of STS.
 type sovereignTelemetryService struct {
 	cfg    STSConfiguration
 	data   TelemetryData
+	thresholds Thresholds // Runtime-adjustable subset of cfg, guarded by mu
 	mu     sync.RWMutex
 	source TelemetrySource
+
+	burnRateFiring bool             // Latest burn-rate evaluation result, guarded by mu
+	window         *violationWindow // Sliding-window samples, guarded by mu; nil in other modes
+	cycle          time.Time        // Shared-state cycle of the latest collection, guarded by mu
+	cycleSeen      int              // Local collections assessed in cycle, guarded by mu
+	derived        *derivedMetrics  // Nil without DerivedMetrics
+
+	paused  atomic.Bool
+	resumed chan struct{} // Wakes Run for an immediate cycle after Resume
+
+	subscribers atomic.Int64 // Active Monitor streams
+	failures    atomic.Int64 // Consecutive failed cycles
+	ack            *Acknowledgement // Active operator acknowledgement, guarded by mu
+
+	metrics stsMetrics
 }
 
 // NewSovereignTelemetryService initializes the telemetry service.
@@ -106,96 +291,459 @@ func NewSovereignTelemetryService(cfg STSConfiguration, src TelemetrySource) STS
 	if cfg.BreachDecayFactor == 0 {
 		cfg.BreachDecayFactor = defaultDecayFactor
 	}
+	if cfg.MaxMonitorSubscribers == 0 {
+		cfg.MaxMonitorSubscribers = defaultMaxMonitorSubscribers
+	}
+	if cfg.MonitorIdleTimeout == 0 {
+		cfg.MonitorIdleTimeout = defaultMonitorIdleTimeout
+	}
+	if cfg.SinkWriteTimeout == 0 {
+		cfg.SinkWriteTimeout = cfg.DefaultInterval / 2
+	}
+	if cfg.ErrorReportAfter <= 0 {
+		cfg.ErrorReportAfter = defaultErrorReportAfter
+	}
+	if cfg.BackpressureStart <= 0 || cfg.BackpressureStart >= 1 {
+		cfg.BackpressureStart = defaultBackpressureStart
+	}
+	if cfg.Tiers.WarnRatio == 0 {
+		cfg.Tiers.WarnRatio = defaultWarnRatio
+	}
+	if cfg.Tiers.CriticalRatio == 0 {
+		cfg.Tiers.CriticalRatio = defaultCriticalRatio
+	}
+	if cfg.Lifecycle == nil {
+		cfg.Lifecycle = NewLifecycle()
+	}
+	if cfg.EscalationMode == "" || (cfg.EscalationMode == EscalationModeBurnRate && cfg.BurnRate == nil) ||
+		(cfg.EscalationMode == EscalationModeSlidingWindow && cfg.SlidingWindow.Validate() != nil) {
+		cfg.EscalationMode = EscalationModeConsecutive
+	}
+	var window *violationWindow
+	if cfg.EscalationMode == EscalationModeSlidingWindow {
+		window = newViolationWindow(cfg.SlidingWindow.Samples)
+	}
 
 	if src == nil {
 		// If no specific source is injected, default to simulation.
-		src = &simulatedTelemetrySource{}
+		src = newSimulatedTelemetrySource(SimulationConfig{})
 	}
 
 	return &sovereignTelemetryService{
 		cfg:  cfg,
 		source: src,
+		window: window,
+		derived: newDerivedMetrics(cfg.DerivedMetrics),
+		thresholds: thresholdsFromConfig(cfg),
+		resumed: make(chan struct{}, 1),
+		metrics: newSTSMetrics(cfg.Metrics),
 		// Ensure GATMBreachCount and IsGATMViolating are initialized to 0/false
-		data: TelemetryData{IntegrityHashChainStatus: "INITIALIZING"},
+		data: TelemetryData{IntegrityHashChainStatus: "INITIALIZING", Severity: SeverityOK},
+	}
+}
+
+// recordSnapshot writes a snapshot to the sink within SinkWriteTimeout. The cycle's correlation ID
+// and cancellation still reach the sink through ctx.
+func (s *sovereignTelemetryService) recordSnapshot(ctx context.Context, td TelemetryData) error {
+	if s.cfg.SinkWriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.SinkWriteTimeout)
+		defer cancel()
+	}
+	err := s.cfg.Sink.Record(ctx, td)
+	if err != nil && s.cfg.SinkWriteTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("write timed out after %v: %w", s.cfg.SinkWriteTimeout, err)
 	}
+	return err
 }
 
-// checkGATMRules performs the instantaneous Generalized Anomaly Threshold Model (GATM) check.
-func (s *sovereignTelemetryService) checkGATMRules(td TelemetryData) bool {
-	if td.PipelineLatency_S9 > s.cfg.LatencyThreshold {
-		return true
+// reportCycle sends cycle failures to the ErrorReporter: a panicking source at once, other
+// failures on every ErrorReportAfter-th consecutive failed cycle, so a lone transient error is
+// not reported.
+func (s *sovereignTelemetryService) reportCycle(ctx context.Context, err error) {
+	if err == nil {
+		s.failures.Store(0)
+		return
+	}
+	n := s.failures.Add(1)
+	if s.cfg.ErrorReporter == nil {
+		return
 	}
-	if td.ResourceLoad_Pct > s.cfg.LoadThreshold {
-		return true
+	ev := errreport.New(ctx, errreport.KindCollection, fmt.Sprintf("telemetry source %T", s.source), err)
+	if ev.Kind != errreport.KindPanic && n%int64(s.cfg.ErrorReportAfter) != 0 {
+		return
+	}
+	ev.Tags = map[string]string{"consecutive_failures": strconv.FormatInt(n, 10)}
+	s.cfg.ErrorReporter.Report(ctx, ev)
+}
+
+// checkGATMRules performs the instantaneous Generalized Anomaly Threshold Model (GATM) check,
+// returning the causes of any breach.
+func (s *sovereignTelemetryService) checkGATMRules(ctx context.Context, td TelemetryData, th Thresholds) []string {
+	var causes []string
+	if td.PipelineLatencyS9 > th.LatencyThreshold {
+		causes = append(causes, CauseLatency)
+	}
+	if td.ResourceLoad_Pct > th.LoadThreshold {
+		causes = append(causes, CauseLoad)
 	}
 	// CRoT integrity anchor violation is high priority
 	if td.IntegrityHashChainStatus != "SYNCED" {
-		return true
+		causes = append(causes, CauseIntegrity)
+	}
+	if td.ClockStatus == ClockSteppedBack {
+		causes = append(causes, CauseClockJump)
 	}
-	return false
+	if s.cfg.MaxClockSkew > 0 && td.ClockStatus != "" && clockSkewed(td, s.cfg.MaxClockSkew) {
+		causes = append(causes, CauseClockSkew)
+	}
+	if len(s.cfg.Rules) > 0 && s.cfg.Flags.Enabled(featureflag.CELRules) {
+		causes = append(causes, violatedRules(ctx, s.cfg.Rules, td, s.cfg.ErrorReporter)...)
+	}
+	return causes
+}
+
+// collectAndProcess runs one cycle of the collection pipeline: collect → enrich → evaluate →
+// persist → notify. Each cycle runs under a correlation ID (adopted from CollectNow's caller when
+// present) that flows into processors, sink writes and escalation handlers.
+func (s *sovereignTelemetryService) collectAndProcess(ctx context.Context) (err error) {
+	ctx = correlation.Ensure(ctx, nil)
+	start := time.Now()
+	defer func() {
+		s.metrics.observeCycle(start, err)
+		s.reportCycle(ctx, err)
+	}()
+
+	td, err := s.collect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.runStage(ctx, StageEnrich, &td); err != nil {
+		return err
+	}
+	s.derived.compute(ctx, &td, s.metrics, s.cfg.ErrorReporter)
+	snapshot, previous, err := s.evaluate(ctx, td)
+	if err != nil {
+		return err
+	}
+	s.derived.record(snapshot)
+	s.metrics.observeSnapshot(snapshot)
+	// Advance the lifecycle once the cycle is done, even if a sink or handler failed, since the
+	// assessment itself stands.
+	defer func() {
+		if lerr := s.advanceLifecycle(ctx); err == nil {
+			err = lerr
+		}
+	}()
+
+	if err := s.persist(ctx, snapshot); err != nil {
+		return err
+	}
+	return s.notify(ctx, snapshot, previous)
 }
 
-// collectAndProcess fetches metrics, assesses GATM violation status, and updates state atomically.
-func (s *sovereignTelemetryService) collectAndProcess(ctx context.Context) error {
-	fetchedData, err := s.source.Collect(ctx)
+// collect fetches a snapshot from the source and runs the collect stage on it.
+func (s *sovereignTelemetryService) collect(ctx context.Context) (TelemetryData, error) {
+	var fetchedData TelemetryData
+	err := recovery.Call(fmt.Sprintf("telemetry source %T", s.source), func() (err error) {
+		fetchedData, err = s.source.Collect(ctx)
+		return err
+	})
 	if err != nil {
 		// Inability to collect telemetry data itself should perhaps trigger a mild GATM breach.
-		return fmt.Errorf("telemetry collection failed: %w", err)
+		return TelemetryData{}, fmt.Errorf("%w: %w", ErrCollectionFailed, err)
+	}
+	if h := fetchedData.LatencyHistogram; h != nil {
+		if err := h.Validate(); err != nil {
+			return TelemetryData{}, fmt.Errorf("%w: %w", ErrCollectionFailed, err)
+		}
 	}
+	if err := s.runStage(ctx, StageCollect, &fetchedData); err != nil {
+		return TelemetryData{}, err
+	}
+	return fetchedData, nil
+}
 
-	isViolated := s.checkGATMRules(fetchedData)
+// evaluate assesses GATM violation status and updates state atomically, returning the new
+// snapshot and the one it replaced. With a StateStore, the breach count and acknowledgement
+// are advanced in the shared state, once per collection cycle across replicas.
+func (s *sovereignTelemetryService) evaluate(ctx context.Context, fetchedData TelemetryData) (TelemetryData, TelemetryData, error) {
+	th := s.Thresholds()
+	s.mu.RLock()
+	previous := s.data.Timestamp
+	s.mu.RUnlock()
+	if checkMonotonic(&fetchedData, previous) {
+		s.metrics.clockSteps.Inc()
+	}
+	fetchedData.ViolationCauses = s.checkGATMRules(ctx, fetchedData, th)
+	fetchedData.IsGATMViolating = len(fetchedData.ViolationCauses) > 0
+	if err := s.runStage(ctx, StageEvaluate, &fetchedData); err != nil {
+		return TelemetryData{}, TelemetryData{}, err
+	}
+	causes := fetchedData.ViolationCauses
+	isViolated := len(causes) > 0
+	isSilenced := isViolated && s.cfg.Silences != nil &&
+		s.cfg.Silences.Silenced(causes, s.cfg.Labels, fetchedData.Timestamp)
+	if s.cfg.Silences != nil {
+		// Drop silences that ended before both this snapshot and the wall clock, so a replay of
+		// older snapshots keeps the silences covering them.
+		pruneAt := time.Now()
+		if fetchedData.Timestamp.Before(pruneAt) {
+			pruneAt = fetchedData.Timestamp
+		}
+		s.cfg.Silences.Prune(pruneAt)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Preserve cumulative count before updating base metrics
-	currentBreachCount := s.data.GATMBreachCount
+	// Sliding-window samples are kept per replica, so they are observed once per local cycle.
+	// Silenced samples are left out of the window, like they are held out of the decayed count.
+	windowCount := -1
+	if s.window != nil && !isSilenced {
+		windowCount = s.window.observe(isViolated)
+	}
+	advance := func(state BreachState) BreachState {
+		switch {
+		case isSilenced:
+			// Planned maintenance: hold the count steady so silenced breaches neither escalate nor decay.
+		case windowCount >= 0:
+			state.Count = windowCount
+		case isViolated:
+			state.Count++
+		case state.Count > 0:
+			// Apply damping factor to the previous count if the system stabilized
+			newCount := int(float64(state.Count) * th.BreachDecayFactor)
+			// Ensure count fully resets if decay pushed it below 1, avoiding stale low counts.
+			if newCount < 1 {
+				newCount = 0
+			}
+			state.Count = newCount
+		}
+		// An acknowledgement lapses once it expires or the breach has fully cleared.
+		if ack := state.Acknowledgement; ack != nil && (state.Count == 0 || !fetchedData.Timestamp.Before(ack.Until)) {
+			state.Acknowledgement = nil
+		}
+		state.UpdatedAt = fetchedData.Timestamp
+		return state
+	}
+
+	var state BreachState
+	if s.cfg.StateStore == nil {
+		state = advance(BreachState{Count: s.data.GATMBreachCount, Acknowledgement: s.ack})
+	} else {
+		// The first replica to assess a cycle advances the shared count; the others adopt it, so
+		// replicas escalate on the same cumulative view, as fast as a single instance would. Jitter,
+		// CollectNow and Resume can put several local collections in one cycle: each one beyond the
+		// most any replica has assessed in the cycle advances the count again, so none is dropped.
+		cycle := fetchedData.Timestamp.Truncate(s.cfg.DefaultInterval)
+		if cycle.Equal(s.cycle) {
+			s.cycleSeen++
+		} else {
+			s.cycle, s.cycleSeen = cycle, 1
+		}
+		seen := s.cycleSeen
+		var err error
+		state, err = s.cfg.StateStore.UpdateBreachState(ctx, func(shared BreachState) BreachState {
+			switch {
+			case shared.Cycle.Before(cycle):
+			case shared.Cycle.Equal(cycle) && shared.Observations < seen:
+			default:
+				return shared
+			}
+			shared = advance(shared)
+			shared.Cycle, shared.Observations = cycle, seen
+			return shared
+		})
+		if err != nil {
+			return TelemetryData{}, TelemetryData{}, fmt.Errorf("%w: update: %w", ErrBreachStateUnavailable, err)
+		}
+	}
+
+	replaced := s.data
 
 	// Overwrite base metrics with fresh data
 	s.data = fetchedData
 	
 	// Set instantaneous status
 	s.data.IsGATMViolating = isViolated
+	s.data.ViolationCauses = causes
+	s.data.IsSilenced = isSilenced
+	s.data.Severity = s.cfg.Tiers.classify(fetchedData, th, causes)
+	s.data.GATMBreachCount = state.Count
+	s.ack = state.Acknowledgement
+	s.data.IsAcknowledged = s.ack != nil
 
-	// Update cumulative breach count logic
-	if isViolated {
-		s.data.GATMBreachCount = currentBreachCount + 1
-	} else if currentBreachCount > 0 {
-		// Apply damping factor to the previous count if the system stabilized
-		newCount := int(float64(currentBreachCount) * s.cfg.BreachDecayFactor)
-		// Ensure count fully resets if decay pushed it below 1, avoiding stale low counts.
-		if newCount < 1 {
-			s.data.GATMBreachCount = 0
-		} else {
-			s.data.GATMBreachCount = newCount
+	return s.data, replaced, nil
+}
+
+// persist records the snapshot to the sink and runs the persist stage.
+func (s *sovereignTelemetryService) persist(ctx context.Context, snapshot TelemetryData) error {
+	if s.cfg.Sink != nil {
+		if err := s.recordSnapshot(ctx, snapshot); err != nil {
+			return fmt.Errorf("%w: %w", ErrSinkWriteFailed, err)
 		}
-	} else {
-		s.data.GATMBreachCount = 0
 	}
 
-	return nil
+	snapshot = cloneSnapshot(snapshot)
+	return s.runStage(ctx, StagePersist, &snapshot)
 }
 
-// Run starts the continuous background monitoring loop, updating internal state.
+// notify dispatches escalation on a change of severity or causes, evaluates the burn rate and runs
+// the notify stage.
+func (s *sovereignTelemetryService) notify(ctx context.Context, snapshot TelemetryData, previous TelemetryData) error {
+	// A failed escalation handler does not hold up the burn rate or the notify stage.
+	var dispatchErr error
+	if s.cfg.Escalation != nil && dispatchDue(previous, snapshot) {
+		dispatchErr = s.cfg.Escalation.Dispatch(ctx, snapshot)
+	}
+
+	if s.cfg.EscalationMode == EscalationModeBurnRate {
+		status, err := s.cfg.BurnRate.Evaluate(ctx, snapshot.Timestamp)
+		if err != nil {
+			return errors.Join(dispatchErr, err)
+		}
+		s.mu.Lock()
+		s.burnRateFiring = status.Firing
+		s.mu.Unlock()
+	}
+
+	snapshot = cloneSnapshot(snapshot)
+	return errors.Join(dispatchErr, s.runStage(ctx, StageNotify, &snapshot))
+}
+
+// advanceLifecycle moves the lifecycle to the state called for by the latest snapshot.
+func (s *sovereignTelemetryService) advanceLifecycle(ctx context.Context) error {
+	s.mu.RLock()
+	snapshot, escalating := s.data, s.escalationDueLocked()
+	s.mu.RUnlock()
+	next, reason := nextLifecycleState(s.cfg.Lifecycle.State(), snapshot, escalating)
+	return s.cfg.Lifecycle.Fire(ctx, next, reason)
+}
+
+// Lifecycle returns the current escalation state.
+func (s *sovereignTelemetryService) Lifecycle() LifecycleStatus {
+	return s.cfg.Lifecycle.Status()
+}
+
+// Run starts the continuous background monitoring loop, updating internal state. The first cycle
+// waits for a random StartupSplay and each interval is perturbed by Jitter.
 func (s *sovereignTelemetryService) Run(ctx context.Context) error {
-	ticker := time.NewTicker(s.cfg.DefaultInterval)
-	defer ticker.Stop()
+	if err := jitter.Sleep(ctx, jitter.Splay(s.cfg.StartupSplay)); err != nil {
+		return err
+	}
+	timer := time.NewTimer(jitter.Apply(s.cfg.DefaultInterval, s.cfg.Jitter))
+	defer timer.Stop()
 
 	// Initial collection before starting the loop
-	if err := s.collectAndProcess(ctx); err != nil {
-		// Handle initialization failure if required, or continue with default state
-		// Since this service is crucial, we allow running even if the first collect fails, 
-		// letting the error surface through the monitoring channel if exposed.
+	if !s.paused.Load() {
+		if err := s.collectAndProcess(ctx); err != nil {
+			// Handle initialization failure if required, or continue with default state
+			// Since this service is crucial, we allow running even if the first collect fails, 
+			// letting the error surface through the monitoring channel if exposed.
+		}
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-ticker.C:
+		case <-timer.C:
+		case <-s.resumed:
+		}
+		if !s.paused.Load() {
 			s.collectAndProcess(ctx)
 		}
+		timer.Reset(jitter.Apply(s.cfg.DefaultInterval, s.cfg.Jitter))
+	}
+}
+
+// Pause freezes background collection, e.g., during planned maintenance.
+func (s *sovereignTelemetryService) Pause() {
+	s.paused.Store(true)
+}
+
+// Resume restarts background collection. Run performs a cycle immediately so state is not stale.
+func (s *sovereignTelemetryService) Resume() {
+	if !s.paused.Swap(false) {
+		return
+	}
+	select {
+	case s.resumed <- struct{}{}:
+	default:
+	}
+}
+
+// Paused reports whether background collection is paused.
+func (s *sovereignTelemetryService) Paused() bool {
+	return s.paused.Load()
+}
+
+// CollectNow performs an immediate collection and GATM assessment.
+func (s *sovereignTelemetryService) CollectNow(ctx context.Context) error {
+	return s.collectAndProcess(ctx)
+}
+
+// ResetBreachCount clears the cumulative GATM breach count; the next collection re-evaluates from
+// zero. An escalated lifecycle moves to RECOVERING first, so a guard holding the escalation also
+// keeps the count.
+func (s *sovereignTelemetryService) ResetBreachCount() error {
+	if s.cfg.Lifecycle.State().Escalated() {
+		if err := s.cfg.Lifecycle.Fire(context.Background(), StateRecovering, "breach count reset"); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	s.data.GATMBreachCount = 0
+	s.burnRateFiring = false
+	if s.window != nil {
+		s.window.reset()
 	}
+	s.ack = nil
+	s.data.IsAcknowledged = false
+	s.mu.Unlock()
+
+	s.shareBreachState(func(state *BreachState) {
+		state.Count = 0
+		state.Acknowledgement = nil
+	})
+	return nil
+}
+
+// shareBreachState applies an out-of-band state change (reset, acknowledgement) to the shared
+// store. Failures are tolerated: the change then holds locally until the next collection adopts
+// the shared state.
+func (s *sovereignTelemetryService) shareBreachState(change func(*BreachState)) {
+	if s.cfg.StateStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), stateStoreTimeout)
+	defer cancel()
+	s.cfg.StateStore.UpdateBreachState(ctx, func(state BreachState) BreachState {
+		change(&state)
+		state.UpdatedAt = time.Now()
+		return state
+	})
+}
+
+// Acknowledge pauses escalation of the current violation. It fails if there is nothing to acknowledge.
+func (s *sovereignTelemetryService) Acknowledge(by, reason string, ttl time.Duration) (Acknowledgement, error) {
+	if ttl <= 0 {
+		return Acknowledgement{}, ErrInvalidTTL
+	}
+	s.mu.Lock()
+	if s.data.GATMBreachCount == 0 {
+		s.mu.Unlock()
+		return Acknowledgement{}, ErrNoActiveBreach
+	}
+	now := time.Now()
+	s.ack = &Acknowledgement{By: by, Reason: reason, At: now, Until: now.Add(ttl)}
+	s.data.IsAcknowledged = true
+	ack := *s.ack
+	s.mu.Unlock()
+
+	s.shareBreachState(func(state *BreachState) { state.Acknowledgement = &ack })
+	return ack, nil
 }
 
 // GetHealthStatus returns the latest cached TelemetryData snapshot.
@@ -205,32 +753,117 @@ func (s *sovereignTelemetryService) GetHealthStatus() TelemetryData {
 	return s.data
 }
 
-// CheckGATMViolation reports if the persistent breach count exceeds the mandated RRP/SIH escalation threshold.
+// CheckGATMViolation reports whether the lifecycle is escalated (RRP_PENDING or SIH_ACTIVE), so
+// a guard holding an escalation keeps it reported. Escalation is suppressed while the current
+// violation is silenced or acknowledged.
 func (s *sovereignTelemetryService) CheckGATMViolation() bool {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	return s.data.GATMBreachCount >= s.cfg.MaxBreaches
+	suppressed := s.data.IsSilenced || s.data.IsAcknowledged
+	s.mu.RUnlock()
+	return !suppressed && s.cfg.Lifecycle.State().Escalated()
+}
+
+// escalationDueLocked evaluates the escalation trigger of the configured mode, which advances the
+// lifecycle at the end of each cycle. Callers must hold s.mu.
+func (s *sovereignTelemetryService) escalationDueLocked() bool {
+	if s.data.IsSilenced || s.data.IsAcknowledged {
+		return false
+	}
+	switch s.cfg.EscalationMode {
+	case EscalationModeBurnRate:
+		return s.burnRateFiring
+	case EscalationModeSlidingWindow:
+		return s.data.GATMBreachCount >= s.cfg.SlidingWindow.threshold()
+	}
+	return s.data.GATMBreachCount >= s.thresholds.MaxBreaches
+}
+
+// MonitorOption customizes a Monitor stream.
+type MonitorOption func(*monitorOptions)
+
+type monitorOptions struct {
+	backfill int
+}
+
+// WithBackfill sends the last n snapshots from the configured sink, oldest first, before live
+// updates begin, so observers connecting mid-incident see its context. Without a sink, or if the
+// query fails, the stream starts with live updates only.
+func WithBackfill(n int) MonitorOption {
+	return func(o *monitorOptions) { o.backfill = n }
+}
+
+// MonitorSubscribers returns the number of active Monitor streams.
+func (s *sovereignTelemetryService) MonitorSubscribers() int {
+	return int(s.subscribers.Load())
 }
 
 // Monitor starts a temporary, dedicated monitoring stream for external observers.
 // Note: This polls the internal state updated by Run(), avoiding unnecessary repeated collection.
-func (s *sovereignTelemetryService) Monitor(ctx context.Context, interval time.Duration) <-chan TelemetryData {
+// When MaxMonitorSubscribers streams are already active, the returned channel is closed immediately.
+// A stream ends when ctx is done or its reader stays idle for MonitorIdleTimeout.
+func (s *sovereignTelemetryService) Monitor(ctx context.Context, interval time.Duration, opts ...MonitorOption) <-chan TelemetryData {
+	var o monitorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	output := make(chan TelemetryData, 1) // Buffered channel for immediate non-blocking send
+
+	if n := s.subscribers.Add(1); s.cfg.MaxMonitorSubscribers > 0 && n > int64(s.cfg.MaxMonitorSubscribers) {
+		s.subscribers.Add(-1)
+		s.metrics.monitorEnds.Inc("rejected")
+		close(output)
+		return output
+	}
+	s.metrics.subscribers.Set(float64(s.subscribers.Load()))
+	idleTimeout := max(s.cfg.MonitorIdleTimeout, 2*interval)
 	ticker := time.NewTicker(interval)
 
 	go func() {
+		reason := "cancelled"
+		defer func() {
+			s.metrics.subscribers.Set(float64(s.subscribers.Add(-1)))
+			s.metrics.monitorEnds.Inc(reason)
+		}()
 		defer close(output)
 		defer ticker.Stop()
 
-		// Send immediate snapshot on start
-		s.mu.RLock()
-		if s.data.Timestamp.IsZero() { 
-			// Ensure initialization has occurred
-			// Wait for first collection or use default (implementation choice)
-		} else {
-			output <- s.data
+		// deliver blocks until the reader takes td, ctx is done, or the reader is idle too long.
+		deliver := func(td TelemetryData) bool {
+			idle := time.NewTimer(idleTimeout)
+			defer idle.Stop()
+			select {
+			case output <- td:
+				return true
+			case <-ctx.Done():
+				return false
+			case <-idle.C:
+				reason = "abandoned"
+				return false
+			}
 		}
+
+		// Backfill history; sends block so no context is dropped.
+		var lastSent time.Time
+		if o.backfill > 0 && s.cfg.Sink != nil {
+			history, err := s.cfg.Sink.QueryLastN(ctx, o.backfill)
+			if err == nil {
+				for _, td := range history {
+					if !deliver(td) {
+						return
+					}
+					lastSent = td.Timestamp
+				}
+			}
+		}
+
+		// Send immediate snapshot on start, unless backfill already ended with it
+		s.mu.RLock()
+		current := s.data
 		s.mu.RUnlock()
+		if !current.Timestamp.IsZero() && !current.Timestamp.Equal(lastSent) && !deliver(current) {
+			return
+		}
+		lastDelivered := time.Now()
 
 		for {
 			select {
@@ -241,11 +874,17 @@ func (s *sovereignTelemetryService) Monitor(ctx context.Context, interval time.D
 				// Push the latest snapshot, assuming Run() is actively updating it.
 				select {
 				case output <- s.data:
-				// Successfully sent
+					// Successfully sent
+					lastDelivered = time.Now()
 				default:
 					// Non-blocking send failure indicates reader is slow; drop measurement.
 				}
 				s.mu.RUnlock()
+				if time.Since(lastDelivered) >= idleTimeout {
+					// The reader stopped consuming without cancelling: reap the stream.
+					reason = "abandoned"
+					return
+				}
 			}
 		}
 	}()
@@ -0,0 +1,74 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSource counts collections; it is safe for use by Run.
+type countingSource struct {
+	n atomic.Int64
+}
+
+func (s *countingSource) Collect(ctx context.Context) (TelemetryData, error) {
+	n := s.n.Add(1)
+	return TelemetryData{Timestamp: time.Unix(n, 0), PipelineLatencyS9: 100 * time.Millisecond, IntegrityHashChainStatus: "SYNCED"}, nil
+}
+
+// waitStatus waits until the status reports the snapshot collected at ts, failing after a second.
+func waitStatus(t *testing.T, sts STS, ts time.Time) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !sts.GetHealthStatus().Timestamp.Equal(ts) {
+		if time.Now().After(deadline) {
+			t.Fatalf("status timestamp = %v, want %v", sts.GetHealthStatus().Timestamp, ts)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	src := &countingSource{}
+	// The interval is long enough that every collection below comes from Run's start or Resume.
+	sts := NewSovereignTelemetryService(STSConfiguration{DefaultInterval: time.Hour}, src)
+	sts.Pause()
+	if !sts.Paused() {
+		t.Fatal("Paused() = false after Pause")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sts.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Paused: Run skips its initial collection, but a single-shot collection still runs.
+	time.Sleep(20 * time.Millisecond)
+	if n := src.n.Load(); n != 0 {
+		t.Fatalf("collections while paused = %d, want 0", n)
+	}
+	if err := sts.CollectNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := src.n.Load(); n != 1 {
+		t.Fatalf("collections after CollectNow = %d, want 1", n)
+	}
+
+	// Resume triggers an immediate cycle rather than waiting for the next interval.
+	sts.Resume()
+	if sts.Paused() {
+		t.Error("Paused() = true after Resume")
+	}
+	waitStatus(t, sts, time.Unix(2, 0))
+
+	// Resuming a running service does not add a cycle.
+	sts.Resume()
+	time.Sleep(20 * time.Millisecond)
+	if n := src.n.Load(); n != 2 {
+		t.Errorf("collections after a second Resume = %d, want 2", n)
+	}
+}
@@ -0,0 +1,11 @@
+package telemetry
+
+import "context"
+
+// TelemetrySink persists processed telemetry snapshots for trend analysis and historical queries.
+type TelemetrySink interface {
+	Record(ctx context.Context, data TelemetryData) error
+	// QueryLastN fetches the last N records, ordered from oldest to newest.
+	QueryLastN(ctx context.Context, n int) ([]TelemetryData, error)
+	Close(ctx context.Context) error
+}
@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Thresholds are the GATM thresholds that may be changed while the service runs.
+type Thresholds struct {
+	LatencyThreshold  time.Duration `json:"latency_threshold"`
+	LoadThreshold     float64       `json:"load_threshold"`      // 0.0 - 1.0
+	MaxBreaches       int           `json:"max_breaches"`        // count
+	BreachDecayFactor float64       `json:"breach_decay_factor"` // 0.0 - 1.0
+}
+
+// Validate checks that every threshold is within its meaningful range.
+func (t Thresholds) Validate() error {
+	var problems []string
+	if t.LatencyThreshold <= 0 {
+		problems = append(problems, "latency threshold must be positive")
+	}
+	if t.LoadThreshold <= 0 || t.LoadThreshold > 1 {
+		problems = append(problems, "load threshold must be in (0, 1]")
+	}
+	if t.MaxBreaches <= 0 {
+		problems = append(problems, "max breaches must be positive")
+	}
+	if t.BreachDecayFactor <= 0 || t.BreachDecayFactor >= 1 {
+		problems = append(problems, "breach decay factor must be in (0, 1)")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidThresholds, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// merge returns t with every non-zero field of update applied.
+func (t Thresholds) merge(update Thresholds) Thresholds {
+	if update.LatencyThreshold != 0 {
+		t.LatencyThreshold = update.LatencyThreshold
+	}
+	if update.LoadThreshold != 0 {
+		t.LoadThreshold = update.LoadThreshold
+	}
+	if update.MaxBreaches != 0 {
+		t.MaxBreaches = update.MaxBreaches
+	}
+	if update.BreachDecayFactor != 0 {
+		t.BreachDecayFactor = update.BreachDecayFactor
+	}
+	return t
+}
+
+// ThresholdChange is a proposed runtime threshold update.
+type ThresholdChange struct {
+	Source   string // Who pushed the change, e.g. an admin principal or "governance"
+	Current  Thresholds
+	Proposed Thresholds // Current with the update applied
+}
+
+// ThresholdAdmitter decides whether a runtime threshold change may be applied, e.g. by evaluating
+// it against an admission policy. A non-nil error rejects the change.
+type ThresholdAdmitter interface {
+	AdmitThresholds(ctx context.Context, change ThresholdChange) error
+}
+
+// thresholdsFromConfig extracts the runtime-adjustable thresholds from a defaulted configuration.
+func thresholdsFromConfig(cfg STSConfiguration) Thresholds {
+	return Thresholds{
+		LatencyThreshold:  cfg.LatencyThreshold,
+		LoadThreshold:     cfg.LoadThreshold,
+		MaxBreaches:       cfg.MaxBreaches,
+		BreachDecayFactor: cfg.BreachDecayFactor,
+	}
+}
+
+// Thresholds returns the thresholds in effect.
+func (s *sovereignTelemetryService) Thresholds() Thresholds {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.thresholds
+}
+
+// UpdateThresholds applies the non-zero fields of update, taking effect from the next collection.
+// The result must pass Validate and, if configured, the ThresholdAdmission policy; otherwise the
+// current thresholds are kept and ErrInvalidThresholds or ErrThresholdsRejected is returned.
+func (s *sovereignTelemetryService) UpdateThresholds(ctx context.Context, source string, update Thresholds) (Thresholds, error) {
+	s.mu.RLock()
+	current := s.thresholds
+	s.mu.RUnlock()
+
+	proposed := current.merge(update)
+	if err := proposed.Validate(); err != nil {
+		return current, err
+	}
+	if s.cfg.ThresholdAdmission != nil {
+		change := ThresholdChange{Source: source, Current: current, Proposed: proposed}
+		if err := s.cfg.ThresholdAdmission.AdmitThresholds(ctx, change); err != nil {
+			return current, fmt.Errorf("%w: %w", ErrThresholdsRejected, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.thresholds != current {
+		// Another update landed while this one was being admitted; it was admitted against stale values.
+		return s.thresholds, fmt.Errorf("%w: thresholds changed concurrently", ErrThresholdsRejected)
+	}
+	s.thresholds = proposed
+	return proposed, nil
+}
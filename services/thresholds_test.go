@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type admitFunc func(ctx context.Context, change ThresholdChange) error
+
+func (f admitFunc) AdmitThresholds(ctx context.Context, change ThresholdChange) error {
+	return f(ctx, change)
+}
+
+func TestUpdateThresholds(t *testing.T) {
+	// Mirrors a "latency threshold must be >= 200ms" admission policy.
+	policy := admitFunc(func(ctx context.Context, change ThresholdChange) error {
+		if change.Proposed.LatencyThreshold < 200*time.Millisecond {
+			return errors.New("latency threshold below 200ms")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		update      Thresholds
+		wantErr     error
+		wantLatency time.Duration
+	}{
+		{name: "Admitted", update: Thresholds{LatencyThreshold: 250 * time.Millisecond}, wantLatency: 250 * time.Millisecond},
+		{name: "Rejected By Policy", update: Thresholds{LatencyThreshold: 50 * time.Millisecond}, wantErr: ErrThresholdsRejected, wantLatency: time.Second},
+		{name: "Invalid", update: Thresholds{LoadThreshold: 1.5}, wantErr: ErrInvalidThresholds, wantLatency: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sts := NewSovereignTelemetryService(STSConfiguration{LatencyThreshold: time.Second, ThresholdAdmission: policy}, &scriptedSource{latencies: []time.Duration{0}})
+			_, err := sts.UpdateThresholds(context.Background(), "test", tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateThresholds() error = %v, want %v", err, tt.wantErr)
+			}
+			got := sts.Thresholds()
+			if got.LatencyThreshold != tt.wantLatency || got.MaxBreaches != defaultMaxBreaches {
+				t.Errorf("Thresholds() = %+v, want latency %v and other fields unchanged", got, tt.wantLatency)
+			}
+		})
+	}
+}
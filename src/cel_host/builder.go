@@ -0,0 +1,186 @@
+package cel_host
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// ImplementationProvider accepts host function implementations; both Registry and Namespaces are
+// providers.
+type ImplementationProvider interface {
+	Provide(implRef string, impl Implementation) error
+}
+
+var (
+	refValType   = reflect.TypeOf((*ref.Val)(nil)).Elem()
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	bytesType    = reflect.TypeOf([]byte(nil))
+)
+
+// celTypeOf maps a Go parameter or result type to its CEL type:
+//
+//	bool → bool, int/int32/int64 → int, uint/uint32/uint64 → uint, float32/float64 → double,
+//	string → string, []byte → bytes, time.Time → timestamp, time.Duration → duration,
+//	[]E → list(E), map[K]V → map(K, V), ref.Val and interfaces → dyn
+func celTypeOf(t reflect.Type) (*cel.Type, error) {
+	switch t {
+	case timeType:
+		return cel.TimestampType, nil
+	case durationType:
+		return cel.DurationType, nil
+	case bytesType:
+		return cel.BytesType, nil
+	}
+	if t == refValType || t.Kind() == reflect.Interface {
+		return cel.DynType, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return cel.BoolType, nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return cel.IntType, nil
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return cel.UintType, nil
+	case reflect.Float32, reflect.Float64:
+		return cel.DoubleType, nil
+	case reflect.String:
+		return cel.StringType, nil
+	case reflect.Slice:
+		elem, err := celTypeOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return cel.ListType(elem), nil
+	case reflect.Map:
+		key, err := celTypeOf(t.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := celTypeOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return cel.MapType(key, value), nil
+	}
+	return nil, fmt.Errorf("Go type %s has no CEL equivalent", t)
+}
+
+// typeOf returns the reflect.Type of T, including interface types.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// argument converts a CEL value to the Go parameter type T.
+func argument[T any](implRef string, pos int, v ref.Val) (T, ref.Val) {
+	var zero T
+	if types.IsError(v) || types.IsUnknown(v) {
+		return zero, v
+	}
+	if native, ok := v.(T); ok {
+		return native, nil
+	}
+	native, err := v.ConvertToNative(typeOf[T]())
+	if err != nil {
+		return zero, types.NewErr("%s: argument %d: %v", implRef, pos, err)
+	}
+	out, ok := native.(T)
+	if !ok {
+		return zero, types.NewErr("%s: argument %d: cannot use %T as %s", implRef, pos, native, typeOf[T]())
+	}
+	return out, nil
+}
+
+// result converts a Go result to a CEL value, wrapping a returned error.
+func result[R any](implRef string, out R, err error) ref.Val {
+	if err != nil {
+		return types.NewErr("%s: %v", implRef, err)
+	}
+	if v, ok := any(out).(ref.Val); ok {
+		return v
+	}
+	return types.DefaultTypeAdapter.NativeToValue(out)
+}
+
+// signature derives the CEL parameter and result types of a function.
+func signature(implRef string, resultType reflect.Type, params ...reflect.Type) ([]*cel.Type, *cel.Type, error) {
+	args := make([]*cel.Type, len(params))
+	for i, p := range params {
+		t, err := celTypeOf(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("implementation %q: parameter %d: %w", implRef, i, err)
+		}
+		args[i] = t
+	}
+	out, err := celTypeOf(resultType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("implementation %q: result: %w", implRef, err)
+	}
+	return args, out, nil
+}
+
+// RegisterUnary provides fn under implRef, deriving its CEL signature from T and R. Arguments are
+// converted from CEL values, and a returned error becomes a CEL error naming implRef.
+func RegisterUnary[T, R any](p ImplementationProvider, implRef string, fn func(T) (R, error)) error {
+	args, out, err := signature(implRef, typeOf[R](), typeOf[T]())
+	if err != nil {
+		return err
+	}
+	return p.Provide(implRef, Implementation{Args: args, Result: out, Fn: func(vals ...ref.Val) ref.Val {
+		a, bad := argument[T](implRef, 0, vals[0])
+		if bad != nil {
+			return bad
+		}
+		r, err := fn(a)
+		return result(implRef, r, err)
+	}})
+}
+
+// RegisterBinary is RegisterUnary for two-argument functions.
+func RegisterBinary[A, B, R any](p ImplementationProvider, implRef string, fn func(A, B) (R, error)) error {
+	args, out, err := signature(implRef, typeOf[R](), typeOf[A](), typeOf[B]())
+	if err != nil {
+		return err
+	}
+	return p.Provide(implRef, Implementation{Args: args, Result: out, Fn: func(vals ...ref.Val) ref.Val {
+		a, bad := argument[A](implRef, 0, vals[0])
+		if bad != nil {
+			return bad
+		}
+		b, bad := argument[B](implRef, 1, vals[1])
+		if bad != nil {
+			return bad
+		}
+		r, err := fn(a, b)
+		return result(implRef, r, err)
+	}})
+}
+
+// RegisterTernary is RegisterUnary for three-argument functions.
+func RegisterTernary[A, B, C, R any](p ImplementationProvider, implRef string, fn func(A, B, C) (R, error)) error {
+	args, out, err := signature(implRef, typeOf[R](), typeOf[A](), typeOf[B](), typeOf[C]())
+	if err != nil {
+		return err
+	}
+	return p.Provide(implRef, Implementation{Args: args, Result: out, Fn: func(vals ...ref.Val) ref.Val {
+		a, bad := argument[A](implRef, 0, vals[0])
+		if bad != nil {
+			return bad
+		}
+		b, bad := argument[B](implRef, 1, vals[1])
+		if bad != nil {
+			return bad
+		}
+		c, bad := argument[C](implRef, 2, vals[2])
+		if bad != nil {
+			return bad
+		}
+		r, err := fn(a, b, c)
+		return result(implRef, r, err)
+	}})
+}
@@ -0,0 +1,88 @@
+package cel_host
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenericAdapters(t *testing.T) {
+	r := NewRegistry()
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(RegisterUnary(r, "net/IsInternal", func(host string) (bool, error) {
+		if host == "" {
+			return false, errors.New("empty host")
+		}
+		return strings.HasSuffix(host, ".internal"), nil
+	}))
+	must(RegisterBinary(r, "list/Contains", func(list []string, s string) (bool, error) {
+		return slices.Contains(list, s), nil
+	}))
+	must(RegisterTernary(r, "time/Within", func(at time.Time, window time.Duration, now time.Time) (bool, error) {
+		return now.Sub(at) <= window, nil
+	}))
+	must(RegisterUnary(r, "map/Total", func(m map[string]int64) (int64, error) {
+		var total int64
+		for _, v := range m {
+			total += v
+		}
+		return total, nil
+	}))
+	_, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: []FunctionConfig{
+		{Name: "is_internal", ImplementationRef: "net/IsInternal"},
+		{Name: "contains", ImplementationRef: "list/Contains"},
+		{Name: "within", ImplementationRef: "time/Within"},
+		{Name: "total", ImplementationRef: "map/Total"},
+	}})
+	must(err)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`is_internal("db.internal") && !is_internal("example.com")`, true},
+		{`contains(["a", "b"], "b") && !contains([], "b")`, true},
+		{`within(timestamp("2026-01-01T00:00:00Z"), duration("1h"), timestamp("2026-01-01T00:30:00Z"))`, true},
+		{`within(timestamp("2026-01-01T00:00:00Z"), duration("1h"), timestamp("2026-01-01T02:00:00Z"))`, false},
+		{`total({"a": 2, "b": 3}) == 5`, true},
+	}
+	for _, tt := range tests {
+		rule, err := CompileRuleWithOptions("adapter", tt.expr, RuleOptions{Registry: r})
+		if err != nil {
+			t.Fatalf("CompileRuleWithOptions(%s) error = %v", tt.expr, err)
+		}
+		got, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED"))
+		if err != nil || got != tt.want {
+			t.Errorf("%s = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+
+	// A returned error becomes a CEL error naming the implementation.
+	rule, err := CompileRuleWithOptions("empty", `is_internal("")`, RuleOptions{Registry: r})
+	must(err)
+	if _, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err == nil || !strings.Contains(err.Error(), "net/IsInternal: empty host") {
+		t.Errorf("Evaluate() of a failing function error = %v", err)
+	}
+	// Arguments are type-checked against the derived signature.
+	if _, err := CompileRuleWithOptions("mistyped", `is_internal(42)`, RuleOptions{Registry: r}); err == nil {
+		t.Error("an int argument to a string parameter compiled")
+	}
+}
+
+func TestGenericAdapters_RejectUnsupportedTypes(t *testing.T) {
+	r := NewRegistry()
+	if err := RegisterUnary(r, "bad/Chan", func(c chan int) (bool, error) { return false, nil }); err == nil || !strings.Contains(err.Error(), "parameter 0") {
+		t.Errorf("RegisterUnary() with a channel parameter error = %v", err)
+	}
+	if err := RegisterBinary(r, "bad/Struct", func(a, b int) (struct{}, error) { return struct{}{}, nil }); err == nil || !strings.Contains(err.Error(), "result") {
+		t.Errorf("RegisterBinary() with a struct result error = %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package cel_host
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ErrNondeterministic is returned when deterministic mode rejects an expression calling a function
+// not declared deterministic, such as time.now or rand.
+var ErrNondeterministic = errors.New("expression calls nondeterministic functions")
+
+// CheckDeterministic returns ErrNondeterministic if the type-checked ast calls any host function
+// not declared Deterministic. The standard CEL library is deterministic.
+func (r *Registry) CheckDeterministic(ast *cel.Ast) error {
+	r.mu.RLock()
+	fns := r.functions
+	r.mu.RUnlock()
+	return checkDeterministic(ast, fns)
+}
+
+// checkDeterministic checks ast against the function set fns.
+func checkDeterministic(ast *cel.Ast, fns map[string]boundFunction) error {
+	checked, err := cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return fmt.Errorf("determinism check requires a checked expression: %w", err)
+	}
+
+	byOverload := make(map[string]string, len(fns))
+	for name, fn := range fns {
+		if !fn.deterministic {
+			byOverload[overloadID(name, fn.impl)] = name
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, reference := range checked.GetReferenceMap() {
+		for _, id := range reference.GetOverloadId() {
+			if name, ok := byOverload[id]; ok {
+				found[name] = true
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: %s", ErrNondeterministic, strings.Join(names, ", "))
+}
+
+// InputSnapshot encodes an activation as canonical JSON, with object keys sorted, so the exact
+// input of a deterministic evaluation can be recorded and replayed byte for byte.
+func InputSnapshot(vars map[string]interface{}) (json.RawMessage, error) {
+	encoded, err := json.Marshal(vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot evaluation input: %w", err)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,101 @@
+package cel_host
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// newDeterminismRegistry binds square as deterministic and clock, standing in for time.now, as not.
+func newDeterminismRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := newTestRegistry(t, FunctionConfig{Name: "square", ImplementationRef: "math/Square", Deterministic: true})
+	var ticks int64
+	r.Provide("time/Now", Implementation{Args: []*cel.Type{cel.IntType}, Result: cel.IntType, Fn: func(args ...ref.Val) ref.Val {
+		ticks++
+		return types.Int(ticks)
+	}})
+	if _, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: []FunctionConfig{{Name: "clock", ImplementationRef: "time/Now"}}}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestCheckDeterministic(t *testing.T) {
+	r := newDeterminismRegistry(t)
+	env, err := cel.NewEnv(append(r.EnvOptions(), cel.Variable(TelemetryVariable, cel.MapType(cel.StringType, cel.DynType)))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want string // Nondeterministic functions reported; empty if deterministic
+	}{
+		{`square(2) == 4 && size("abc") == 3`, ""},
+		{`clock(0) > 0`, "clock"},
+		{`square(clock(0)) > 0 || [1, 2].exists(x, clock(x) > 1)`, "clock"},
+	}
+	for _, tt := range tests {
+		ast, issues := env.Compile(tt.expr)
+		if issues.Err() != nil {
+			t.Fatal(issues.Err())
+		}
+		err := r.CheckDeterministic(ast)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("CheckDeterministic(%s) error = %v", tt.expr, err)
+		case tt.want != "" && (!errors.Is(err, ErrNondeterministic) || !strings.HasSuffix(err.Error(), ": "+tt.want)):
+			t.Errorf("CheckDeterministic(%s) error = %v, want ErrNondeterministic naming %s", tt.expr, err, tt.want)
+		}
+	}
+
+	// An unchecked expression cannot be analysed.
+	parsed, _ := env.Parse(`clock(0) > 0`)
+	if err := r.CheckDeterministic(parsed); err == nil {
+		t.Error("CheckDeterministic() accepted an unchecked expression")
+	}
+}
+
+func TestDeterministicRule(t *testing.T) {
+	r := newDeterminismRegistry(t)
+	if _, err := CompileRuleWithOptions("flaky", `clock(0) % 2 == 0`, RuleOptions{Registry: r, Deterministic: true}); !errors.Is(err, ErrNondeterministic) {
+		t.Errorf("CompileRuleWithOptions() error = %v, want ErrNondeterministic", err)
+	}
+	if _, err := CompileRuleWithOptions("flaky", `clock(0) % 2 == 0`, RuleOptions{Registry: r}); err != nil {
+		t.Errorf("CompileRuleWithOptions() outside deterministic mode error = %v", err)
+	}
+
+	rule, err := CompileRuleWithOptions("slow", `square(2) > 3 && telemetry.pipeline_latency_s9 > 1.0`, RuleOptions{Registry: r, Deterministic: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := rule.EvaluateRecorded(context.Background(), telemetryVars(1.5, "SYNCED"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"telemetry":{"hash_chain_status":"SYNCED","pipeline_latency_s9":1.5}}`
+	if !ev.Result || !ev.Deterministic || ev.Rule != "slow" || string(ev.Input) != want {
+		t.Errorf("EvaluateRecorded() = %+v, want a true deterministic result with input %s", ev, want)
+	}
+}
+
+func TestInputSnapshot_IsCanonical(t *testing.T) {
+	a := map[string]interface{}{"b": 1, "a": map[string]interface{}{"y": true, "x": "s"}}
+	b := map[string]interface{}{"a": map[string]interface{}{"x": "s", "y": true}, "b": 1}
+	sa, err := InputSnapshot(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, _ := InputSnapshot(b)
+	if string(sa) != string(sb) || string(sa) != `{"a":{"x":"s","y":true},"b":1}` {
+		t.Errorf("InputSnapshot() = %s and %s, want identical sorted JSON", sa, sb)
+	}
+	if _, err := InputSnapshot(map[string]interface{}{"ch": make(chan int)}); err == nil {
+		t.Error("InputSnapshot() encoded a channel")
+	}
+}
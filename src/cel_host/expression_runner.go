@@ -0,0 +1,136 @@
+package cel_host
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/interpreter"
+)
+
+// snippetLength bounds the source excerpt shown for each trace step.
+const snippetLength = 32
+
+// ExpressionTest evaluates a policy expression against a JSON fixture, such as a SystemContext or
+// TelemetryData document, so policy authors can try expressions before committing manifests.
+// Unlike rules, the expression may produce any type.
+type ExpressionTest struct {
+	Expression string
+	Fixture    json.RawMessage // A JSON object
+	// Variable names the fixture in the expression (default TelemetryVariable), e.g. "context"
+	// for SystemContext fixtures. Fields are keyed by their JSON names.
+	Variable  string
+	CostLimit uint64    // Zero applies DefaultRuleCostLimit
+	Registry  *Registry // Host functions available to the expression; may be nil
+}
+
+// TraceStep is the value of one subexpression.
+type TraceStep struct {
+	Offset  int32  `json:"offset"`  // Position of the subexpression in the source
+	Snippet string `json:"snippet"` // Source text starting at Offset
+	Value   string `json:"value"`
+}
+
+// ExpressionResult is the outcome of an ExpressionTest.
+type ExpressionResult struct {
+	Value string      `json:"value,omitempty"`
+	Type  string      `json:"type"`
+	Cost  uint64      `json:"cost"`
+	Trace []TraceStep `json:"trace"` // Ordered by source position
+	Error string      `json:"error,omitempty"`
+}
+
+// Run compiles and evaluates the expression. Compile and fixture errors are returned as errors;
+// an evaluation error is returned too, with the partial result (cost and trace so far). A run
+// stopped by the cost limit has no trace. Host functions are called in both evaluations, so the
+// Registry accounts for each call twice.
+func (t ExpressionTest) Run(ctx context.Context) (ExpressionResult, error) {
+	variable := t.Variable
+	if variable == "" {
+		variable = TelemetryVariable
+	}
+	costLimit := t.CostLimit
+	if costLimit == 0 {
+		costLimit = DefaultRuleCostLimit
+	}
+	var fixture map[string]interface{}
+	if err := json.Unmarshal(t.Fixture, &fixture); err != nil {
+		return ExpressionResult{}, fmt.Errorf("fixture must be a JSON object: %w", err)
+	}
+
+	envOptions := []cel.EnvOption{cel.Variable(variable, cel.MapType(cel.StringType, cel.DynType))}
+	programOptions := []cel.ProgramOption{cel.CostLimit(costLimit)}
+	if t.Registry != nil {
+		envOptions = append(envOptions, t.Registry.EnvOptions()...)
+		programOptions = append(programOptions, cel.CostTracking(t.Registry))
+	}
+	env, err := cel.NewEnv(envOptions...)
+	if err != nil {
+		return ExpressionResult{}, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(t.Expression)
+	if issues != nil && issues.Err() != nil {
+		return ExpressionResult{}, fmt.Errorf("expression failed to compile: %w", issues.Err())
+	}
+	// CEL neither reports nor enforces the cost of a program that also tracks state, so the
+	// expression is evaluated twice: once under the cost limit for its value and cost, and, if
+	// that stayed within the limit, once more to record the trace.
+	program, err := env.Program(ast, programOptions...)
+	if err != nil {
+		return ExpressionResult{}, fmt.Errorf("failed to build program: %w", err)
+	}
+	tracer, err := env.Program(ast, cel.EvalOptions(cel.OptTrackState))
+	if err != nil {
+		return ExpressionResult{}, fmt.Errorf("failed to build program: %w", err)
+	}
+
+	vars := map[string]interface{}{variable: fixture}
+	res := ExpressionResult{Type: ast.OutputType().String()}
+	out, details, evalErr := program.ContextEval(ctx, vars)
+	if details != nil {
+		if cost := details.ActualCost(); cost != nil {
+			res.Cost = *cost
+		}
+	}
+	var cancelled interpreter.EvalCancelledError
+	if !errors.As(evalErr, &cancelled) {
+		if _, details, _ := tracer.ContextEval(ctx, vars); details != nil {
+			res.Trace = trace(ast, t.Expression, details)
+		}
+	}
+	if evalErr != nil {
+		res.Error = evalErr.Error()
+		return res, fmt.Errorf("expression evaluation failed: %w", evalErr)
+	}
+	res.Value = fmt.Sprintf("%v", out.Value())
+	return res, nil
+}
+
+// trace lists the evaluated subexpressions by source position.
+func trace(ast *cel.Ast, source string, details *cel.EvalDetails) []TraceStep {
+	checked, err := cel.AstToCheckedExpr(ast)
+	if err != nil {
+		return nil
+	}
+	positions := checked.GetSourceInfo().GetPositions() // Code point offsets
+	runes := []rune(source)
+	state := details.State()
+	steps := make([]TraceStep, 0, len(state.IDs()))
+	for _, id := range state.IDs() {
+		v, ok := state.Value(id)
+		offset, known := positions[id]
+		if !ok || !known {
+			continue
+		}
+		snippet := runes[min(int(offset), len(runes)):]
+		if len(snippet) > snippetLength {
+			snippet = append(snippet[:snippetLength:snippetLength], '…')
+		}
+		steps = append(steps, TraceStep{Offset: offset, Snippet: string(snippet), Value: fmt.Sprintf("%v", v.Value())})
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Offset < steps[j].Offset })
+	return steps
+}
@@ -0,0 +1,79 @@
+package cel_host
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExpressionTest_Run(t *testing.T) {
+	res, err := ExpressionTest{
+		Expression: `telemetry.resource_load_pct * 100.0`,
+		Fixture:    json.RawMessage(`{"resource_load_pct": 0.5}`),
+	}.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if res.Value != "50" || res.Type != "double" || res.Cost == 0 {
+		t.Errorf("Run() = %+v, want the double 50 at a non-zero cost", res)
+	}
+	if len(res.Trace) != 3 || res.Trace[0].Value != "0.5" || res.Trace[1].Value != "50" {
+		t.Errorf("trace = %+v, want the field, the product and the literal", res.Trace)
+	}
+	for i := 1; i < len(res.Trace); i++ {
+		if res.Trace[i].Offset < res.Trace[i-1].Offset {
+			t.Fatalf("trace is not ordered by offset: %+v", res.Trace)
+		}
+	}
+}
+
+func TestExpressionTest_RunWithVariableAndFunctions(t *testing.T) {
+	r := newTestRegistry(t, FunctionConfig{Name: "square", ImplementationRef: "math/Square"})
+	res, err := ExpressionTest{
+		// JSON numbers are doubles, hence int().
+		Expression: `context.hardware.cpu_architecture == "amd64" && square(int(context.hardware.cores)) > 10`,
+		Fixture:    json.RawMessage(`{"hardware": {"cpu_architecture": "amd64", "cores": 4}}`),
+		Variable:   "context",
+		Registry:   r,
+	}.Run(context.Background())
+	if err != nil || res.Value != "true" || res.Type != "bool" {
+		t.Errorf("Run() = %+v, %v; want true", res, err)
+	}
+}
+
+func TestExpressionTest_RunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		test ExpressionTest
+		want string
+	}{
+		{"fixture", ExpressionTest{Expression: `true`, Fixture: json.RawMessage(`[1]`)}, "fixture must be a JSON object"},
+		{"compile", ExpressionTest{Expression: `telemetry.`, Fixture: json.RawMessage(`{}`)}, "failed to compile"},
+		{"variable", ExpressionTest{Expression: `telemetry.x`, Fixture: json.RawMessage(`{}`), Variable: "context"}, "failed to compile"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.test.Run(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Run() error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	// An evaluation error comes with the partial result.
+	res, err := ExpressionTest{
+		Expression: `telemetry.a > 1 && telemetry.missing > 1`,
+		Fixture:    json.RawMessage(`{"a": 2}`),
+	}.Run(context.Background())
+	if err == nil || res.Error == "" || res.Value != "" || len(res.Trace) == 0 {
+		t.Errorf("Run() = %+v, %v; want an evaluation error with a partial trace", res, err)
+	}
+
+	// The cost limit is enforced, and a run it stops has no trace.
+	res, err = ExpressionTest{
+		Expression: `telemetry.a * 2.0 + telemetry.a * 3.0 > 1.0`,
+		Fixture:    json.RawMessage(`{"a": 2}`),
+		CostLimit:  1,
+	}.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "cost limit") || res.Cost == 0 || res.Trace != nil {
+		t.Errorf("Run() = %+v, %v; want a cost limit error without a trace", res, err)
+	}
+}
@@ -3,9 +3,9 @@ package cel_host
 
 import (
 	"context"
+
 	"github.com/google/cel-go/cel"
-	"github.com/google/cel-go/common/types"
-	"github.com/google/cel-go/interpreter"
+	"github.com/google/cel-go/common/types/ref"
 )
 
 // HostFunctionRegistry defines the standardized interface for resolving custom CEL functions
@@ -18,14 +18,38 @@ type HostFunctionRegistry interface {
 	// ExecuteCustomFunction handles the dispatch and execution of a specific named function.
 	// This implementation ensures that function implementations are sandboxed or executed
 	// safely, respecting defined cost limits.
-	ExecuteCustomFunction(ctx context.Context, name string, args []interpreter.PrerecordedData) (types.Val, error)
+	ExecuteCustomFunction(ctx context.Context, name string, args []ref.Val) (ref.Val, error)
+}
+
+// NamespacedHostFunctionRegistry isolates function sets per policy namespace (tenant): an
+// expression compiled for one namespace can only call the functions configured for it.
+type NamespacedHostFunctionRegistry interface {
+	// ForNamespace returns the registry holding the namespace's functions.
+	ForNamespace(namespace string) (HostFunctionRegistry, error)
 }
 
 // RuntimeConfiguration is a structure reflecting the `available_functions` block from the config.
 type RuntimeConfiguration struct {
-	AvailableFunctions []struct {
-		Name string
-		ImplementationRef string
-		CostFactor int
-	}
-}
\ No newline at end of file
+	AvailableFunctions []FunctionConfig `json:"available_functions"`
+	// Namespaces declare per-tenant function sets, isolated from each other and from
+	// AvailableFunctions.
+	Namespaces map[string]NamespaceConfig `json:"namespaces,omitempty"`
+}
+
+// NamespaceConfig declares the functions and cost budget of one policy namespace.
+type NamespaceConfig struct {
+	AvailableFunctions []FunctionConfig `json:"available_functions"`
+	// CostBudget caps the per-evaluation cost of the namespace's expressions, whatever limit they
+	// request. Zero applies DefaultRuleCostLimit.
+	CostBudget uint64 `json:"cost_budget,omitempty"`
+}
+
+// FunctionConfig declares one custom function available to expressions.
+type FunctionConfig struct {
+	Name              string `json:"name"`
+	ImplementationRef string `json:"implementation_ref"` // Key of the implementation provided to the registry
+	CostFactor        int    `json:"cost_factor"`        // Cost charged per call; zero counts as 1
+	// Deterministic declares that the function returns the same result for the same arguments.
+	// Functions such as time.now or rand must leave it false; deterministic mode rejects them.
+	Deterministic bool `json:"deterministic"`
+}
@@ -0,0 +1,140 @@
+package cel_host
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"pkg/metrics"
+)
+
+// ErrUnknownNamespace is returned for a namespace that has not been configured.
+var ErrUnknownNamespace = errors.New("unknown function namespace")
+
+// tenant is one configured namespace.
+type tenant struct {
+	registry *Registry
+	budget   uint64
+}
+
+// Namespaces gives every policy namespace (tenant) its own Registry and cost budget, so one
+// tenant's expensive custom function cannot be called from another tenant's expressions.
+// Implementations are provided once and shared; which of them a namespace may call is decided by
+// its configuration.
+type Namespaces struct {
+	mu       sync.RWMutex
+	impls    map[string]Implementation
+	tenants  map[string]tenant
+	provider metrics.Provider
+}
+
+// NewNamespaces creates an empty set of namespaces without instrumentation.
+func NewNamespaces() *Namespaces {
+	return &Namespaces{impls: make(map[string]Implementation), tenants: make(map[string]tenant)}
+}
+
+// SetMetrics instruments the function calls of namespaces configured afterwards, labelled
+// "<namespace>/<function>". A nil provider disables instrumentation.
+func (n *Namespaces) SetMetrics(provider metrics.Provider) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.provider = provider
+}
+
+// Provide makes an implementation available to namespaces configured afterwards.
+func (n *Namespaces) Provide(ref string, impl Implementation) error {
+	if ref == "" || impl.Fn == nil || impl.Result == nil {
+		return fmt.Errorf("implementation %q needs a function and a result type", ref)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.impls[ref] = impl
+	return nil
+}
+
+// Configure creates or replaces the namespace's registry with the configured functions.
+func (n *Namespaces) Configure(namespace string, cfg NamespaceConfig) error {
+	if namespace == "" {
+		return errors.New("function namespace name must not be empty")
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	r := NewRegistry()
+	r.namespace = namespace
+	r.SetMetrics(n.provider)
+	for ref, impl := range n.impls {
+		r.impls[ref] = impl
+	}
+	if _, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: cfg.AvailableFunctions}); err != nil {
+		return fmt.Errorf("namespace '%s': %w", namespace, err)
+	}
+	budget := cfg.CostBudget
+	if budget == 0 {
+		budget = DefaultRuleCostLimit
+	}
+	n.tenants[namespace] = tenant{registry: r, budget: budget}
+	return nil
+}
+
+// ConfigureAll configures every namespace declared in the runtime configuration.
+func (n *Namespaces) ConfigureAll(runtimeConfig RuntimeConfiguration) error {
+	for namespace, cfg := range runtimeConfig.Namespaces {
+		if err := n.Configure(namespace, cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Registry returns the namespace's registry.
+func (n *Namespaces) Registry(namespace string) (*Registry, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	t, ok := n.tenants[namespace]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownNamespace, namespace)
+	}
+	return t.registry, nil
+}
+
+// ForNamespace implements NamespacedHostFunctionRegistry.
+func (n *Namespaces) ForNamespace(namespace string) (HostFunctionRegistry, error) {
+	return n.Registry(namespace)
+}
+
+// CompileRule compiles an expression for a namespace: only its functions are callable, and the
+// cost limit is capped at its budget.
+func (n *Namespaces) CompileRule(namespace, name, expr string, opts RuleOptions) (*RuleProgram, error) {
+	n.mu.RLock()
+	t, ok := n.tenants[namespace]
+	n.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownNamespace, namespace)
+	}
+	opts.Registry = t.registry
+	if opts.CostLimit == 0 || opts.CostLimit > t.budget {
+		opts.CostLimit = t.budget
+	}
+	program, err := CompileRuleWithOptions(name, expr, opts)
+	if err != nil {
+		return nil, fmt.Errorf("namespace '%s': %w", namespace, err)
+	}
+	return program, nil
+}
+
+// Report returns the function statistics of every namespace, keyed by namespace.
+func (n *Namespaces) Report() map[string][]FunctionStats {
+	n.mu.RLock()
+	registries := make(map[string]*Registry, len(n.tenants))
+	for name, t := range n.tenants {
+		registries[name] = t.registry
+	}
+	n.mu.RUnlock()
+	report := make(map[string][]FunctionStats, len(registries))
+	for name, r := range registries {
+		report[name] = r.Report()
+	}
+	return report
+}
+
+var _ NamespacedHostFunctionRegistry = (*Namespaces)(nil)
@@ -0,0 +1,81 @@
+package cel_host
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func newTestNamespaces(t *testing.T) *Namespaces {
+	t.Helper()
+	n := NewNamespaces()
+	n.Provide("math/Square", Implementation{Args: []*cel.Type{cel.IntType}, Result: cel.IntType, Fn: func(args ...ref.Val) ref.Val {
+		v := args[0].(types.Int)
+		return v * v
+	}})
+	err := n.ConfigureAll(RuntimeConfiguration{Namespaces: map[string]NamespaceConfig{
+		"team-a": {AvailableFunctions: []FunctionConfig{{Name: "square", ImplementationRef: "math/Square", CostFactor: 10}}, CostBudget: 25},
+		"team-b": {},
+	}})
+	if err != nil {
+		t.Fatalf("ConfigureAll() error = %v", err)
+	}
+	return n
+}
+
+func TestNamespaces_IsolateFunctions(t *testing.T) {
+	n := newTestNamespaces(t)
+	if _, err := n.CompileRule("team-b", "borrowed", `square(2) == 4`, RuleOptions{}); err == nil || !strings.Contains(err.Error(), "namespace 'team-b'") {
+		t.Errorf("team-b compiled a call to team-a's function: %v", err)
+	}
+	rule, err := n.CompileRule("team-a", "own", `square(2) == 4`, RuleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); !ok || err != nil {
+		t.Errorf("Evaluate() = %v, %v", ok, err)
+	}
+
+	b, err := n.ForNamespace("team-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ExecuteCustomFunction(context.Background(), "square", []ref.Val{types.Int(2)}); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("team-b dispatched team-a's function: %v", err)
+	}
+	if _, err := n.CompileRule("team-c", "r", `true`, RuleOptions{}); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("CompileRule() in an unknown namespace error = %v", err)
+	}
+	if err := n.Configure("", NamespaceConfig{}); err == nil {
+		t.Error("Configure() accepted an empty namespace")
+	}
+
+	report := n.Report()
+	if len(report["team-a"]) != 1 || report["team-a"][0].Calls != 1 || len(report["team-b"]) != 0 {
+		t.Errorf("Report() = %+v, want team-a's one call only", report)
+	}
+}
+
+func TestNamespaces_CapCostAtBudget(t *testing.T) {
+	n := newTestNamespaces(t)
+	// Three calls cost 30, over team-a's budget of 25, whatever limit the rule asks for.
+	rule, err := n.CompileRule("team-a", "greedy", `square(1) + square(2) + square(3) > 0`, RuleOptions{CostLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("Evaluate() over the namespace budget error = %v", err)
+	}
+	rule, err = n.CompileRule("team-a", "modest", `square(1) + square(2) > 0`, RuleOptions{CostLimit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); !ok || err != nil {
+		t.Errorf("Evaluate() within the budget = %v, %v", ok, err)
+	}
+}
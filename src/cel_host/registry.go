@@ -0,0 +1,287 @@
+package cel_host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+
+	"pkg/metrics"
+	"pkg/recovery"
+)
+
+// Sentinel errors for host function registration and dispatch; match them with errors.Is.
+var (
+	ErrUnknownImplementation = errors.New("unknown host function implementation")
+	ErrUnknownFunction       = errors.New("unknown host function")
+)
+
+// Implementation is the Go code behind a custom function, provided under an implementation
+// reference such as "network_utils/IPMatcher.IsInternal".
+type Implementation struct {
+	Args   []*cel.Type
+	Result *cel.Type
+	Fn     func(args ...ref.Val) ref.Val
+}
+
+// FunctionStats is the accumulated execution record of one custom function.
+type FunctionStats struct {
+	Name        string        `json:"name"`
+	Calls       uint64        `json:"calls"`
+	Errors      uint64        `json:"errors"`
+	Cost        uint64        `json:"cost"` // Cumulative cost: calls times the cost factor
+	TotalTime   time.Duration `json:"total_time"`
+	MaxDuration time.Duration `json:"max_duration"`
+}
+
+// MeanDuration is the average latency of a call, or zero before the first.
+func (s FunctionStats) MeanDuration() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.TotalTime / time.Duration(s.Calls)
+}
+
+// boundFunction is a configured function resolved to its implementation.
+type boundFunction struct {
+	impl          Implementation
+	cost          uint64
+	deterministic bool
+}
+
+// Registry is the standard HostFunctionRegistry. Implementations are provided by reference, and
+// RegisterFunctions binds the configured functions to them. Every call is counted, timed, and
+// charged its cost factor, both in Report and through the metrics facade, so expensive functions
+// in policy expressions can be identified. Use it as the program's cost tracker, via
+// cel.CostTracking(registry), to charge the cost factors against the program cost limit too.
+type Registry struct {
+	namespace string // Set for tenant registries; prefixes the function metric label
+
+	mu        sync.RWMutex
+	impls     map[string]Implementation
+	functions map[string]boundFunction
+	stats     map[string]*FunctionStats
+
+	calls    metrics.Counter   // function, result: ok|error
+	cost     metrics.Counter   // function
+	duration metrics.Histogram // function
+}
+
+// NewRegistry creates an empty registry without instrumentation.
+func NewRegistry() *Registry {
+	r := &Registry{
+		impls:     make(map[string]Implementation),
+		functions: make(map[string]boundFunction),
+		stats:     make(map[string]*FunctionStats),
+	}
+	r.SetMetrics(nil)
+	return r
+}
+
+// SetMetrics instruments function calls. A nil provider disables instrumentation.
+func (r *Registry) SetMetrics(provider metrics.Provider) {
+	provider = metrics.OrNop(provider)
+	r.calls = provider.Counter("cel_host_function_calls_total", "CEL host function calls by function and result.", "function", "result")
+	r.cost = provider.Counter("cel_host_function_cost_total", "Cumulative cost charged by CEL host functions.", "function")
+	r.duration = provider.Histogram("cel_host_function_duration_seconds", "CEL host function call latency.", nil, "function")
+}
+
+// Provide makes an implementation available under ref for RegisterFunctions.
+func (r *Registry) Provide(ref string, impl Implementation) error {
+	if ref == "" || impl.Fn == nil || impl.Result == nil {
+		return fmt.Errorf("implementation %q needs a function and a result type", ref)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.impls[ref] = impl
+	return nil
+}
+
+// RegisterFunctions implements HostFunctionRegistry, appending a CEL declaration for every
+// configured function. Every function must reference a provided implementation; on error no
+// function is registered.
+func (r *Registry) RegisterFunctions(envOptions []cel.EnvOption, runtimeConfig RuntimeConfiguration) ([]cel.EnvOption, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fns, err := r.bind(runtimeConfig, r.functions)
+	if err != nil {
+		return nil, err
+	}
+	r.install(fns)
+	for _, fc := range runtimeConfig.AvailableFunctions {
+		envOptions = append(envOptions, r.declare(fc.Name, fns[fc.Name].impl))
+	}
+	return envOptions, nil
+}
+
+// bind resolves the configured functions to their implementations, returning them added to a
+// copy of base. Callers hold r.mu.
+func (r *Registry) bind(runtimeConfig RuntimeConfiguration, base map[string]boundFunction) (map[string]boundFunction, error) {
+	fns := make(map[string]boundFunction, len(base)+len(runtimeConfig.AvailableFunctions))
+	for name, fn := range base {
+		fns[name] = fn
+	}
+	for _, fc := range runtimeConfig.AvailableFunctions {
+		impl, ok := r.impls[fc.ImplementationRef]
+		if !ok {
+			return nil, fmt.Errorf("function '%s': %w %q", fc.Name, ErrUnknownImplementation, fc.ImplementationRef)
+		}
+		if _, dup := fns[fc.Name]; dup {
+			return nil, fmt.Errorf("function '%s' is declared twice", fc.Name)
+		}
+		cost := uint64(1)
+		if fc.CostFactor > 0 {
+			cost = uint64(fc.CostFactor)
+		}
+		fns[fc.Name] = boundFunction{impl: impl, cost: cost, deterministic: fc.Deterministic}
+	}
+	return fns, nil
+}
+
+// install makes fns the bound function set. Callers hold r.mu.
+func (r *Registry) install(fns map[string]boundFunction) {
+	r.functions = fns
+	for name := range fns {
+		if r.stats[name] == nil {
+			r.stats[name] = &FunctionStats{Name: name}
+		}
+	}
+}
+
+// EnvOptions returns the declarations of every function bound so far, for environments created
+// after RegisterFunctions.
+func (r *Registry) EnvOptions() []cel.EnvOption {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.declarations(r.functions)
+}
+
+// declarations returns the CEL declarations of fns, ordered by name.
+func (r *Registry) declarations(fns map[string]boundFunction) []cel.EnvOption {
+	names := make([]string, 0, len(fns))
+	for name := range fns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	opts := make([]cel.EnvOption, 0, len(names))
+	for _, name := range names {
+		opts = append(opts, r.declare(name, fns[name].impl))
+	}
+	return opts
+}
+
+// overloadID names the single overload declared for a function.
+func overloadID(name string, impl Implementation) string {
+	return fmt.Sprintf("%s_%d", name, len(impl.Args))
+}
+
+// declare returns the CEL declaration of a function, bound to dispatch through the registry.
+func (r *Registry) declare(name string, impl Implementation) cel.EnvOption {
+	return cel.Function(name,
+		cel.Overload(overloadID(name, impl), impl.Args, impl.Result,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				// CEL bindings carry no context; cancellation is enforced by the program.
+				out, err := r.ExecuteCustomFunction(context.Background(), name, args)
+				if err != nil {
+					return types.NewErr("%v", err)
+				}
+				return out
+			})))
+}
+
+// ExecuteCustomFunction implements HostFunctionRegistry. A panic in the implementation is
+// returned as an error, as is a CEL error value.
+func (r *Registry) ExecuteCustomFunction(ctx context.Context, name string, args []ref.Val) (ref.Val, error) {
+	r.mu.RLock()
+	fn, ok := r.functions[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownFunction, name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var out ref.Val
+	start := time.Now()
+	err := recovery.Call("CEL host function "+name, func() error {
+		out = fn.impl.Fn(args...)
+		if types.IsError(out) {
+			return fmt.Errorf("%v", out.Value())
+		}
+		return nil
+	})
+	r.record(name, fn.cost, time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("function '%s' failed: %w", name, err)
+	}
+	return out, nil
+}
+
+// record accounts one call.
+func (r *Registry) record(name string, cost uint64, elapsed time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	label := name
+	if r.namespace != "" {
+		label = r.namespace + "/" + name
+	}
+	r.calls.Inc(label, result)
+	r.cost.Add(float64(cost), label)
+	r.duration.Observe(elapsed.Seconds(), label)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats[name]
+	s.Calls++
+	if err != nil {
+		s.Errors++
+	}
+	s.Cost += cost
+	s.TotalTime += elapsed
+	if elapsed > s.MaxDuration {
+		s.MaxDuration = elapsed
+	}
+}
+
+// CallCost implements interpreter.ActualCostEstimator, charging each host function call its cost
+// factor. Other functions are left to CEL's default costs.
+func (r *Registry) CallCost(function, overloadID string, args []ref.Val, result ref.Val) *uint64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if fn, ok := r.functions[function]; ok {
+		cost := fn.cost
+		return &cost
+	}
+	return nil
+}
+
+var _ interpreter.ActualCostEstimator = (*Registry)(nil)
+
+// Report returns the accumulated statistics of every registered function, most expensive first.
+func (r *Registry) Report() []FunctionStats {
+	r.mu.RLock()
+	report := make([]FunctionStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		report = append(report, *s)
+	}
+	r.mu.RUnlock()
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Cost != report[j].Cost {
+			return report[i].Cost > report[j].Cost
+		}
+		return report[i].Name < report[j].Name
+	})
+	return report
+}
+
+var _ HostFunctionRegistry = (*Registry)(nil)
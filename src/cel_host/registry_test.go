@@ -0,0 +1,118 @@
+package cel_host
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+// newTestRegistry provides "math/Square", "util/Boom" (panics) and "util/Fail" (returns a CEL
+// error), and binds them as square, boom and fail with the given configuration.
+func newTestRegistry(t *testing.T, functions ...FunctionConfig) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	impls := map[string]Implementation{
+		"math/Square": {Args: []*cel.Type{cel.IntType}, Result: cel.IntType, Fn: func(args ...ref.Val) ref.Val {
+			n := args[0].(types.Int)
+			return n * n
+		}},
+		"util/Boom": {Args: []*cel.Type{cel.IntType}, Result: cel.BoolType, Fn: func(args ...ref.Val) ref.Val {
+			panic("boom")
+		}},
+		"util/Fail": {Args: []*cel.Type{cel.IntType}, Result: cel.BoolType, Fn: func(args ...ref.Val) ref.Val {
+			return types.NewErr("lookup failed")
+		}},
+	}
+	for name, impl := range impls {
+		if err := r.Provide(name, impl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: functions}); err != nil {
+		t.Fatalf("RegisterFunctions() error = %v", err)
+	}
+	return r
+}
+
+func statsOf(r *Registry, name string) FunctionStats {
+	for _, s := range r.Report() {
+		if s.Name == name {
+			return s
+		}
+	}
+	return FunctionStats{}
+}
+
+func TestRegistry_CostAccounting(t *testing.T) {
+	r := newTestRegistry(t,
+		FunctionConfig{Name: "square", ImplementationRef: "math/Square", CostFactor: 5},
+		FunctionConfig{Name: "boom", ImplementationRef: "util/Boom"},
+		FunctionConfig{Name: "fail", ImplementationRef: "util/Fail"},
+	)
+	rule, err := CompileRuleWithOptions("squares", `square(3) == 9 && square(2) == 4`, RuleOptions{Registry: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err != nil || !ok {
+		t.Fatalf("Evaluate() = %v, %v", ok, err)
+	}
+	if s := statsOf(r, "square"); s.Calls != 2 || s.Errors != 0 || s.Cost != 10 || s.TotalTime < s.MaxDuration {
+		t.Errorf("square stats = %+v, want 2 calls costing 10", s)
+	}
+
+	// Panics and CEL errors are returned as errors and counted; a zero cost factor charges 1.
+	if _, err := r.ExecuteCustomFunction(context.Background(), "boom", []ref.Val{types.Int(1)}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("ExecuteCustomFunction(boom) error = %v", err)
+	}
+	if _, err := r.ExecuteCustomFunction(context.Background(), "fail", []ref.Val{types.Int(1)}); err == nil || !strings.Contains(err.Error(), "lookup failed") {
+		t.Errorf("ExecuteCustomFunction(fail) error = %v", err)
+	}
+	if s := statsOf(r, "boom"); s.Calls != 1 || s.Errors != 1 || s.Cost != 1 {
+		t.Errorf("boom stats = %+v, want one failed call costing 1", s)
+	}
+	if _, err := r.ExecuteCustomFunction(context.Background(), "cube", nil); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("ExecuteCustomFunction(cube) error = %v, want ErrUnknownFunction", err)
+	}
+
+	report := r.Report()
+	if len(report) != 3 || report[0].Name != "square" || report[1].Name != "boom" || report[2].Name != "fail" {
+		t.Errorf("Report() = %+v, want square first, then by name", report)
+	}
+}
+
+func TestRegistry_CostFactorCountsAgainstLimit(t *testing.T) {
+	r := newTestRegistry(t, FunctionConfig{Name: "square", ImplementationRef: "math/Square", CostFactor: 100})
+	rule, err := CompileRuleWithOptions("expensive", `square(1) + square(2) > 0`, RuleOptions{Registry: r, CostLimit: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("Evaluate() of two calls costing 100 each with limit 150 error = %v", err)
+	}
+}
+
+func TestRegistry_RejectsUnknownImplementation(t *testing.T) {
+	r := newTestRegistry(t, FunctionConfig{Name: "square", ImplementationRef: "math/Square"})
+	_, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: []FunctionConfig{
+		{Name: "cube", ImplementationRef: "math/Square"},
+		{Name: "sqrt", ImplementationRef: "math/Sqrt"},
+	}})
+	if !errors.Is(err, ErrUnknownImplementation) {
+		t.Fatalf("RegisterFunctions() error = %v, want ErrUnknownImplementation", err)
+	}
+	// Nothing of the failed registration is bound.
+	if _, err := r.ExecuteCustomFunction(context.Background(), "cube", []ref.Val{types.Int(2)}); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("cube was bound by a failed registration: %v", err)
+	}
+
+	if _, err := r.RegisterFunctions(nil, RuntimeConfiguration{AvailableFunctions: []FunctionConfig{
+		{Name: "square", ImplementationRef: "math/Square"},
+	}}); err == nil || !strings.Contains(err.Error(), "declared twice") {
+		t.Errorf("RegisterFunctions() of a bound name error = %v", err)
+	}
+}
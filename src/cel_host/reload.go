@@ -0,0 +1,169 @@
+package cel_host
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultReloadInterval = 10 * time.Second
+
+// Logger defines the interface required for internal component logging.
+type Logger interface {
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// ruleSource is what a cached program was compiled from.
+type ruleSource struct {
+	expression string
+	opts       RuleOptions
+}
+
+// ProgramCache holds rule programs compiled against the host functions declared in a runtime
+// configuration file (cel_runtime_config.json), and rebuilds them when the file's
+// available_functions change. A reload is all or nothing: if any cached expression no longer
+// compiles against the new function list, e.g. because a function it calls was removed, the
+// reload is rejected and the current functions and programs stay in effect.
+type ProgramCache struct {
+	registry *Registry
+	path     string
+	log      Logger
+
+	mu       sync.RWMutex
+	sources  map[string]ruleSource
+	programs map[string]*RuleProgram
+	sum      [sha256.Size]byte // Digest of the configuration in effect
+}
+
+// NewProgramCache loads the runtime configuration at path into registry, which must not have
+// functions registered by other means, and returns an empty cache.
+func NewProgramCache(registry *Registry, path string, logger Logger) (*ProgramCache, error) {
+	c := &ProgramCache{
+		registry: registry,
+		path:     path,
+		log:      logger,
+		sources:  make(map[string]ruleSource),
+		programs: make(map[string]*RuleProgram),
+	}
+	if _, err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Add compiles expr under name against the current host functions and caches it, replacing any
+// program of the same name. opts.Registry is always the cache's registry.
+func (c *ProgramCache) Add(name, expr string, opts RuleOptions) (*RuleProgram, error) {
+	opts.Registry = c.registry
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	program, err := CompileRuleWithOptions(name, expr, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.sources[name] = ruleSource{expression: expr, opts: opts}
+	c.programs[name] = program
+	return program, nil
+}
+
+// Remove drops a cached program, so later reloads no longer need to keep it compiling.
+func (c *ProgramCache) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sources, name)
+	delete(c.programs, name)
+}
+
+// Program returns the current program for name. Callers should look it up for each evaluation
+// rather than retaining it, so they pick up rebuilt programs.
+func (c *ProgramCache) Program(name string) (*RuleProgram, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.programs[name]
+	return p, ok
+}
+
+// Reload re-reads the runtime configuration and, if it changed, rebuilds every cached program
+// against its function list before installing both at once.
+func (c *ProgramCache) Reload() (changed bool, err error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read CEL runtime configuration %s: %w", c.path, err)
+	}
+	sum := sha256.Sum256(data)
+	var cfg RuntimeConfiguration
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return false, fmt.Errorf("invalid CEL runtime configuration %s: %w", c.path, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sum == c.sum {
+		return false, nil
+	}
+
+	c.registry.mu.RLock()
+	fns, err := c.registry.bind(cfg, nil)
+	c.registry.mu.RUnlock()
+	if err != nil {
+		return false, fmt.Errorf("CEL runtime configuration %s rejected: %w", c.path, err)
+	}
+	names := make([]string, 0, len(c.sources))
+	for name := range c.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	programs := make(map[string]*RuleProgram, len(names))
+	for _, name := range names {
+		src := c.sources[name]
+		program, err := compileRule(name, src.expression, src.opts, fns)
+		if err != nil {
+			return false, fmt.Errorf("CEL runtime configuration %s rejected, it would break loaded expressions: %w", c.path, err)
+		}
+		programs[name] = program
+	}
+
+	c.registry.mu.Lock()
+	c.registry.install(fns)
+	c.registry.mu.Unlock()
+	c.programs, c.sum = programs, sum
+	if c.log != nil {
+		c.log.Infof("CEL runtime configuration %s loaded: %d functions, %d programs rebuilt", c.path, len(fns), len(programs))
+	}
+	return true, nil
+}
+
+// Watch checks the runtime configuration for changes every interval (default 10s) until ctx is
+// cancelled. A rejected reload is logged once and retried on every check.
+func (c *ProgramCache) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var reported string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := c.Reload()
+		switch {
+		case err == nil:
+			reported = ""
+		case err.Error() != reported:
+			reported = err.Error()
+			if c.log != nil {
+				c.log.Errorf("%v; keeping the current functions", err)
+			}
+		}
+	}
+}
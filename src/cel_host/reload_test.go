@@ -0,0 +1,92 @@
+package cel_host
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRuntimeConfig(t *testing.T, path string, functions ...FunctionConfig) {
+	t.Helper()
+	data, err := json.Marshal(RuntimeConfiguration{AvailableFunctions: functions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProgramCache_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cel_runtime_config.json")
+	square := FunctionConfig{Name: "square", ImplementationRef: "math/Square"}
+	writeRuntimeConfig(t, path, square)
+	r := newTestRegistry(t)
+	cache, err := NewProgramCache(r, path, nil)
+	if err != nil {
+		t.Fatalf("NewProgramCache() error = %v", err)
+	}
+	before, err := cache.Add("squares", `square(3) == 9`, RuleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := cache.Reload(); changed || err != nil {
+		t.Errorf("Reload() of an unchanged file = %v, %v", changed, err)
+	}
+
+	// Removing a function a cached program calls is rejected as a whole.
+	writeRuntimeConfig(t, path, FunctionConfig{Name: "fail", ImplementationRef: "util/Fail"})
+	if changed, err := cache.Reload(); changed || err == nil || !strings.Contains(err.Error(), "would break loaded expressions") {
+		t.Errorf("Reload() dropping square = %v, %v; want it rejected", changed, err)
+	}
+	if p, _ := cache.Program("squares"); p != before {
+		t.Error("rejected reload replaced the program")
+	}
+	if _, err := r.ExecuteCustomFunction(context.Background(), "fail", nil); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("rejected reload bound its functions: %v", err)
+	}
+
+	// A configuration referencing a missing implementation is rejected too.
+	writeRuntimeConfig(t, path, square, FunctionConfig{Name: "sqrt", ImplementationRef: "math/Sqrt"})
+	if _, err := cache.Reload(); !errors.Is(err, ErrUnknownImplementation) {
+		t.Errorf("Reload() with a missing implementation error = %v", err)
+	}
+
+	// A compatible change rebuilds every program against the new cost factors.
+	writeRuntimeConfig(t, path, FunctionConfig{Name: "square", ImplementationRef: "math/Square", CostFactor: 7})
+	if changed, err := cache.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v", changed, err)
+	}
+	after, _ := cache.Program("squares")
+	if after == before {
+		t.Fatal("program was not rebuilt")
+	}
+	if ok, err := after.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); !ok || err != nil {
+		t.Errorf("rebuilt program = %v, %v", ok, err)
+	}
+	if s := statsOf(r, "square"); s.Cost != 7 {
+		t.Errorf("square cost = %d, want the reloaded factor 7", s.Cost)
+	}
+
+	// Once removed, a program no longer holds back reloads.
+	cache.Remove("squares")
+	writeRuntimeConfig(t, path, FunctionConfig{Name: "fail", ImplementationRef: "util/Fail"})
+	if changed, err := cache.Reload(); !changed || err != nil {
+		t.Errorf("Reload() after Remove = %v, %v", changed, err)
+	}
+}
+
+func TestNewProgramCache_RejectsInvalidConfiguration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cel_runtime_config.json")
+	if _, err := NewProgramCache(NewRegistry(), path, nil); err == nil {
+		t.Error("NewProgramCache() accepted a missing file")
+	}
+	os.WriteFile(path, []byte(`{"available_functions": [`), 0o600)
+	if _, err := NewProgramCache(NewRegistry(), path, nil); err == nil || !strings.Contains(err.Error(), "invalid CEL runtime configuration") {
+		t.Errorf("NewProgramCache() of malformed JSON error = %v", err)
+	}
+}
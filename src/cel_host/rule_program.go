@@ -0,0 +1,155 @@
+package cel_host
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+
+	"pkg/recovery"
+)
+
+// TelemetryVariable is the name under which TelemetryData fields are exposed to rule expressions,
+// keyed by their JSON names (e.g., telemetry.pipeline_latency_s9).
+const TelemetryVariable = "telemetry"
+
+// DefaultRuleCostLimit bounds the evaluation cost of a rule when no explicit limit is configured.
+const DefaultRuleCostLimit uint64 = 1000
+
+// RuleProgram is a compiled, cost-limited boolean CEL expression over telemetry data.
+type RuleProgram struct {
+	name          string
+	expression    string
+	program       cel.Program
+	deterministic bool
+}
+
+// RuleOptions configures CompileRuleWithOptions.
+type RuleOptions struct {
+	// CostLimit bounds the evaluation cost; zero defaults to DefaultRuleCostLimit.
+	CostLimit uint64
+	// Registry makes its bound host functions available to the expression and charges their cost
+	// factors against CostLimit.
+	Registry *Registry
+	// Deterministic rejects, at compile time, expressions calling host functions not declared
+	// deterministic, so every evaluation can be reproduced from its recorded input.
+	Deterministic bool
+}
+
+// Evaluation records one rule evaluation together with its complete input.
+type Evaluation struct {
+	Rule       string          `json:"rule"`
+	Expression string          `json:"expression"`
+	Input      json.RawMessage `json:"input"` // Canonical JSON of the activation; see InputSnapshot
+	Result     bool            `json:"result"`
+	Error      string          `json:"error,omitempty"`
+	// Deterministic reports whether the rule was compiled in deterministic mode, i.e. whether
+	// replaying Input is guaranteed to reproduce Result.
+	Deterministic bool `json:"deterministic"`
+}
+
+// CompileRule parses and type-checks expr, which must evaluate to a bool.
+// A zero costLimit defaults to DefaultRuleCostLimit.
+func CompileRule(name, expr string, costLimit uint64) (*RuleProgram, error) {
+	return CompileRuleWithOptions(name, expr, RuleOptions{CostLimit: costLimit})
+}
+
+// CompileRuleWithOptions is CompileRule with host functions and deterministic mode.
+func CompileRuleWithOptions(name, expr string, opts RuleOptions) (*RuleProgram, error) {
+	var fns map[string]boundFunction
+	if opts.Registry != nil {
+		opts.Registry.mu.RLock()
+		fns = opts.Registry.functions
+		opts.Registry.mu.RUnlock()
+	}
+	return compileRule(name, expr, opts, fns)
+}
+
+// compileRule compiles against the host function set fns of opts.Registry, which need not be
+// installed yet, so reloads can be validated before they take effect.
+func compileRule(name, expr string, opts RuleOptions, fns map[string]boundFunction) (*RuleProgram, error) {
+	costLimit := opts.CostLimit
+	if costLimit == 0 {
+		costLimit = DefaultRuleCostLimit
+	}
+
+	envOptions := []cel.EnvOption{
+		cel.Variable(TelemetryVariable, cel.MapType(cel.StringType, cel.DynType)),
+	}
+	if opts.Registry != nil {
+		envOptions = append(envOptions, opts.Registry.declarations(fns)...)
+	}
+	env, err := cel.NewEnv(envOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("rule '%s' failed to compile: %w", name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule '%s' must evaluate to bool, got %s", name, ast.OutputType())
+	}
+
+	if opts.Deterministic && opts.Registry != nil {
+		if err := checkDeterministic(ast, fns); err != nil {
+			return nil, fmt.Errorf("rule '%s' is not deterministic: %w", name, err)
+		}
+	}
+
+	programOptions := []cel.ProgramOption{cel.CostLimit(costLimit)}
+	if opts.Registry != nil {
+		programOptions = append(programOptions, cel.CostTracking(opts.Registry))
+	}
+	program, err := env.Program(ast, programOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("rule '%s' failed to build program: %w", name, err)
+	}
+	return &RuleProgram{name: name, expression: expr, program: program, deterministic: opts.Deterministic}, nil
+}
+
+// RuleName returns the configured rule name.
+func (r *RuleProgram) RuleName() string {
+	return r.name
+}
+
+// Expression returns the rule's source expression.
+func (r *RuleProgram) Expression() string {
+	return r.expression
+}
+
+// Evaluate runs the rule against the given activation. Exceeding the cost limit is reported as an
+// error, as is a panic in any host function the expression calls.
+func (r *RuleProgram) Evaluate(ctx context.Context, vars map[string]interface{}) (bool, error) {
+	var out ref.Val
+	err := recovery.Call("CEL rule "+r.name, func() (err error) {
+		out, _, err = r.program.ContextEval(ctx, vars)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("rule '%s' evaluation failed: %w", r.name, err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("rule '%s' returned non-bool value %v", r.name, out.Value())
+	}
+	return result, nil
+}
+
+// EvaluateRecorded is Evaluate that also returns a record of the evaluation and its full input,
+// for audit trails.
+func (r *RuleProgram) EvaluateRecorded(ctx context.Context, vars map[string]interface{}) (Evaluation, error) {
+	input, err := InputSnapshot(vars)
+	if err != nil {
+		return Evaluation{}, fmt.Errorf("rule '%s': %w", r.name, err)
+	}
+	ev := Evaluation{Rule: r.name, Expression: r.expression, Input: input, Deterministic: r.deterministic}
+	ev.Result, err = r.Evaluate(ctx, vars)
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	return ev, err
+}
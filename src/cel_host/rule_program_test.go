@@ -0,0 +1,78 @@
+package cel_host
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func telemetryVars(latency float64, status string) map[string]interface{} {
+	return map[string]interface{}{TelemetryVariable: map[string]interface{}{
+		"pipeline_latency_s9": latency,
+		"hash_chain_status":   status,
+	}}
+}
+
+func TestCompileRule(t *testing.T) {
+	rule, err := CompileRule("slow_or_diverged", `telemetry.pipeline_latency_s9 > 2.0 || telemetry.hash_chain_status != "SYNCED"`, 0)
+	if err != nil {
+		t.Fatalf("CompileRule() error = %v", err)
+	}
+	if rule.RuleName() != "slow_or_diverged" {
+		t.Errorf("RuleName() = %q", rule.RuleName())
+	}
+	tests := []struct {
+		latency float64
+		status  string
+		want    bool
+	}{
+		{0.5, "SYNCED", false},
+		{2.5, "SYNCED", true},
+		{0.5, "DIVERGED", true},
+	}
+	for _, tt := range tests {
+		got, err := rule.Evaluate(context.Background(), telemetryVars(tt.latency, tt.status))
+		if err != nil || got != tt.want {
+			t.Errorf("Evaluate(%v, %s) = %v, %v; want %v", tt.latency, tt.status, got, err, tt.want)
+		}
+	}
+
+	// A missing field is an evaluation error, which GATM rules count as a breach.
+	if _, err := rule.Evaluate(context.Background(), map[string]interface{}{TelemetryVariable: map[string]interface{}{}}); err == nil {
+		t.Error("Evaluate() without the referenced fields succeeded")
+	}
+}
+
+func TestCompileRule_Rejects(t *testing.T) {
+	tests := []struct {
+		name, expr, want string
+	}{
+		{"syntax", `telemetry.pipeline_latency_s9 >`, "failed to compile"},
+		{"non_bool", `telemetry.pipeline_latency_s9 * 2.0`, "must evaluate to bool"},
+		{"unknown_variable", `system.load > 1.0`, "failed to compile"},
+	}
+	for _, tt := range tests {
+		if _, err := CompileRule(tt.name, tt.expr, 0); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: CompileRule() error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRuleProgram_CostLimit(t *testing.T) {
+	expr := `[1, 2, 3, 4, 5, 6, 7, 8].all(x, [1, 2, 3, 4, 5, 6, 7, 8].all(y, x * y > 0))`
+	rule, err := CompileRule("quadratic", expr, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rule.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err == nil || !strings.Contains(err.Error(), "cost limit") {
+		t.Errorf("Evaluate() over the cost limit error = %v", err)
+	}
+
+	unbounded, err := CompileRule("quadratic", expr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := unbounded.Evaluate(context.Background(), telemetryVars(0, "SYNCED")); err != nil || !ok {
+		t.Errorf("Evaluate() within the default limit = %v, %v", ok, err)
+	}
+}